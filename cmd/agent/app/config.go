@@ -6,7 +6,9 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/DataDog/datadog-agent/cmd/agent/app/settings"
 	"github.com/DataDog/datadog-agent/cmd/agent/common"
@@ -14,10 +16,16 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	commonsettings "github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	"github.com/DataDog/datadog-agent/pkg/logs"
+	"github.com/DataDog/datadog-agent/pkg/metadata/host"
 
 	"github.com/fatih/color"
 )
 
+// hostTagsRefreshTimeout bounds the refresh of the host tags after a configuration reload
+const hostTagsRefreshTimeout = 30 * time.Second
+
 func init() {
 	AgentCmd.AddCommand(cmdconfig.Config(getSettingsClient))
 }
@@ -75,3 +83,43 @@ func initRuntimeSettings() error {
 	}
 	return commonsettings.RegisterRuntimeSetting(commonsettings.ProfilingRuntimeSetting("internal_profiling"))
 }
+
+// initConfigReload registers the subsystems supporting a hot reload of their
+// configuration and reads the configuration file used as the reload baseline.
+func initConfigReload(fwd *forwarder.DefaultForwarder) error {
+	hooks := []commonsettings.ReloadHook{
+		commonsettings.NewReloadHook("logger", []string{"log_level"}, func(_ []string) error {
+			return config.ChangeLogLevel(config.Datadog.GetString("log_level"))
+		}),
+		commonsettings.NewReloadHook("host tags", []string{"tags", "extra_tags", "env", "tag_value_split_separator"}, func(_ []string) error {
+			// refresh the cached host tags, they are sent with the next host metadata payload. Their
+			// collection may query cloud provider APIs, so it's done without blocking the reload.
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), hostTagsRefreshTimeout)
+				defer cancel()
+				host.GetHostTags(ctx, false)
+			}()
+			return nil
+		}),
+		commonsettings.NewReloadHook("dogstatsd mapper", []string{"dogstatsd_mapper_profiles", "dogstatsd_mapper_cache_size"}, func(_ []string) error {
+			if common.DSD == nil {
+				return nil
+			}
+			return common.DSD.ReloadMapper()
+		}),
+		commonsettings.NewReloadHook("logs processing rules", []string{"logs_config.processing_rules"}, func(_ []string) error {
+			return logs.ReloadProcessingRules()
+		}),
+		commonsettings.NewReloadHook("proxy", []string{"proxy", "no_proxy_nonexact_match"}, func(_ []string) error {
+			config.RefreshProxies()
+			fwd.ScheduleConnectionReset()
+			return nil
+		}),
+	}
+	for _, hook := range hooks {
+		if err := commonsettings.RegisterReloadHook(hook); err != nil {
+			return err
+		}
+	}
+	return commonsettings.InitConfigReload(config.Datadog.ConfigFileUsed())
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs"
 	"github.com/DataDog/datadog-agent/pkg/metadata"
 	"github.com/DataDog/datadog-agent/pkg/metadata/host"
+	"github.com/DataDog/datadog-agent/pkg/netflow"
 	orchcfg "github.com/DataDog/datadog-agent/pkg/orchestrator/config"
 	"github.com/DataDog/datadog-agent/pkg/pidfile"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
@@ -68,6 +69,7 @@ import (
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/disk"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/filehandles"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/memory"
+	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/pressure"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/uptime"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/winproc"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/systemd"
@@ -355,7 +357,8 @@ func StartAgent() error {
 	options := forwarder.NewOptions(keysPerDomain)
 	options.EnabledFeatures = forwarder.SetFeature(options.EnabledFeatures, forwarder.CoreFeatures)
 
-	common.Forwarder = forwarder.NewDefaultForwarder(options)
+	defaultForwarder := forwarder.NewDefaultForwarder(options)
+	common.Forwarder = defaultForwarder
 	log.Debugf("Starting forwarder")
 	common.Forwarder.Start() //nolint:errcheck
 	log.Debugf("Forwarder started")
@@ -399,6 +402,13 @@ func StartAgent() error {
 		}
 	}
 
+	// Start NetFlow server
+	if netflow.IsEnabled() {
+		if err = netflow.StartServer(eventPlatformForwarder); err != nil {
+			log.Errorf("Failed to start NetFlow server: %s", err)
+		}
+	}
+
 	// start logs-agent
 	if config.Datadog.GetBool("logs_enabled") || config.Datadog.GetBool("log_enabled") {
 		if config.Datadog.GetBool("log_enabled") {
@@ -411,6 +421,13 @@ func StartAgent() error {
 		log.Info("logs-agent disabled")
 	}
 
+	// setup the hot reload of the configuration file
+	if err := initConfigReload(defaultForwarder); err != nil {
+		log.Warnf("Can't initialize the configuration reload: %v", err)
+	} else if config.Datadog.GetBool("config_watch.enabled") {
+		go settings.WatchConfigFile(common.MainCtx, config.Datadog.GetDuration("config_watch.interval"))
+	}
+
 	if err = common.SetupSystemProbeConfig(sysProbeConfFilePath); err != nil {
 		log.Infof("System probe config not found, disabling pulling system probe info in the status page: %v", err)
 	}
@@ -471,6 +488,7 @@ func StopAgent() {
 		common.MetadataScheduler.Stop()
 	}
 	traps.StopServer()
+	netflow.StopServer()
 	api.StopServer()
 	clcrunnerapi.StopCLCRunnerServer()
 	jmx.StopJmxfetch()
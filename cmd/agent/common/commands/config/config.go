@@ -25,6 +25,7 @@ func Config(getClient settings.ClientBuilder) *cobra.Command {
 	cmd.AddCommand(listRuntime(getClient))
 	cmd.AddCommand(set(getClient))
 	cmd.AddCommand(get(getClient))
+	cmd.AddCommand(reload(getClient))
 
 	return cmd
 }
@@ -59,6 +60,16 @@ func get(getClient settings.ClientBuilder) *cobra.Command {
 	}
 }
 
+// reload returns a cobra command to reload the configuration file of a running agent.
+func reload(getClient settings.ClientBuilder) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Reload the configuration file, applying the settings that don't require a restart",
+		Long:  ``,
+		RunE:  func(_ *cobra.Command, _ []string) error { return reloadConfiguration(getClient) },
+	}
+}
+
 func showRuntimeConfiguration(getClient settings.ClientBuilder) error {
 	c, err := getClient()
 	if err != nil {
@@ -139,3 +150,40 @@ func getConfigValue(getClient settings.ClientBuilder, args []string) error {
 
 	return nil
 }
+
+func reloadConfiguration(getClient settings.ClientBuilder) error {
+	c, err := getClient()
+	if err != nil {
+		return err
+	}
+
+	result, err := c.Reload()
+	if err != nil {
+		return err
+	}
+
+	if len(result.Applied) == 0 && len(result.RestartRequired) == 0 {
+		fmt.Println("No configuration change detected")
+		return nil
+	}
+
+	if len(result.Applied) > 0 {
+		fmt.Println("=== Settings applied without restart ===")
+		for _, key := range result.Applied {
+			fmt.Println(key)
+		}
+	}
+
+	if len(result.RestartRequired) > 0 {
+		fmt.Println("=== Settings requiring a restart to be applied ===")
+		for _, key := range result.RestartRequired {
+			fmt.Println(key)
+		}
+	}
+
+	for hook, e := range result.Errors {
+		fmt.Printf("Error while reloading %s: %s\n", hook, e)
+	}
+
+	return nil
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
 	orchcfg "github.com/DataDog/datadog-agent/pkg/orchestrator/config"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
@@ -226,7 +227,12 @@ func start(cmd *cobra.Command, args []string) error {
 	}
 	s := serializer.NewSerializer(f, orchestratorForwarder)
 
-	aggregatorInstance := aggregator.InitAggregator(s, nil, hostname)
+	// setup the event platform forwarder, used to send the Kubernetes events routed to the logs pipeline.
+	// Its pipeline is only created once the kubernetes_apiserver check sends an event, i.e. when
+	// send_events_as_logs is configured.
+	eventPlatformForwarder := epforwarder.NewLazyEventPlatformForwarder(epforwarder.EventTypeKubernetesEvents)
+
+	aggregatorInstance := aggregator.InitAggregator(s, eventPlatformForwarder, hostname)
 	aggregatorInstance.AddAgentStartupTelemetry(fmt.Sprintf("%s - Datadog Cluster Agent", version.AgentVersion))
 
 	le, err := leaderelection.GetLeaderEngine()
@@ -337,6 +343,7 @@ func start(cmd *cobra.Command, args []string) error {
 		server := admissioncmd.NewServer()
 		server.Register(config.Datadog.GetString("admission_controller.inject_config.endpoint"), mutate.InjectConfig, apiCl.DynamicCl)
 		server.Register(config.Datadog.GetString("admission_controller.inject_tags.endpoint"), mutate.InjectTags, apiCl.DynamicCl)
+		server.Register(config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint"), mutate.InjectAutoInstrumentation, apiCl.DynamicCl)
 
 		// Start the k8s admission webhook server
 		wg.Add(1)
@@ -394,6 +401,7 @@ func start(cmd *cobra.Command, args []string) error {
 	if orchestratorForwarder != nil {
 		orchestratorForwarder.Stop()
 	}
+	eventPlatformForwarder.Stop()
 
 	log.Info("See ya!")
 	log.Flush()
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/discovery"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	processutil "github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// discoveryHandler returns the inventory of the services running on the host
+func discoveryHandler(w http.ResponseWriter, _ *http.Request) {
+	if !discovery.IsEnabled() {
+		http.Error(w, "process discovery is disabled", http.StatusNotFound)
+		return
+	}
+
+	probe := procutil.NewProcessProbe()
+	defer probe.Close()
+	procs, err := probe.ProcessesByPID(time.Now())
+	if err != nil {
+		log.Errorf("unable to list processes for discovery: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("unable to get hostname for discovery: %s", err)
+	}
+
+	inventory := discovery.NewDiscoverer(processutil.HostProc()).Inventory(hostname, procs)
+	body, err := json.Marshal(inventory)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/discovery"
+)
+
+// startDiscoveryReporter starts sending the inventory of the services of the host, and returns a function stopping it
+func startDiscoveryReporter(cfg *config.AgentConfig) func() {
+	forwarder := epforwarder.NewEventPlatformForwarderWithPipelines(epforwarder.EventTypeProcessDiscovery)
+	forwarder.Start()
+	reporter := discovery.NewReporter(discovery.ReportInterval(), forwarder, cfg.HostName)
+	reporter.Start()
+
+	return func() {
+		reporter.Stop()
+		forwarder.Stop()
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/events"
+)
+
+// startEventCollector starts collecting the lifecycle events of the processes, and returns a function stopping it
+func startEventCollector(cfg *config.AgentConfig) (func(), error) {
+	eventsCfg, err := events.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	forwarder := epforwarder.NewEventPlatformForwarderWithPipelines(epforwarder.EventTypeProcessEvents)
+	forwarder.Start()
+	collector := events.NewCollector(eventsCfg, forwarder, cfg.Scrubber, cfg.HostName)
+	if err := collector.Start(); err != nil {
+		forwarder.Stop()
+		return nil, err
+	}
+
+	return func() {
+		collector.Stop()
+		forwarder.Stop()
+	}, nil
+}
@@ -22,6 +22,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/pidfile"
 	"github.com/DataDog/datadog-agent/pkg/process/checks"
 	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/discovery"
+	"github.com/DataDog/datadog-agent/pkg/process/events"
 	"github.com/DataDog/datadog-agent/pkg/process/heartbeat"
 	"github.com/DataDog/datadog-agent/pkg/process/statsd"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
@@ -297,6 +299,19 @@ func runAgent(exit chan struct{}) {
 		_ = log.Error(err)
 	}
 
+	if events.IsEnabled() {
+		stopEventCollector, err := startEventCollector(cfg)
+		if err != nil {
+			log.Errorf("Error starting the process event collector: %s", err)
+		} else {
+			defer stopEventCollector()
+		}
+	}
+
+	if discovery.IsEnabled() {
+		defer startDiscoveryReporter(cfg)()
+	}
+
 	cl, err := NewCollector(cfg)
 	if err != nil {
 		log.Criticalf("Error creating collector: %s", err)
@@ -10,6 +10,8 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -70,6 +72,22 @@ var (
 		Short: "Run runtime self test",
 		RunE:  runRuntimeSelfTest,
 	}
+
+	policyCmd = &cobra.Command{
+		Use:   "policy",
+		Short: "Policy related commands",
+	}
+
+	testPolicyCmd = &cobra.Command{
+		Use:   "test",
+		Short: "Replay recorded events against the policies and report the matching rules",
+		RunE:  testPolicy,
+	}
+
+	testPolicyArgs = struct {
+		dir    string
+		events string
+	}{}
 )
 
 func init() {
@@ -80,6 +98,12 @@ func init() {
 	checkPoliciesCmd.Flags().StringVar(&checkPoliciesArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
 
 	runtimeCmd.AddCommand(selfTestCmd)
+
+	policyCmd.AddCommand(testPolicyCmd)
+	testPolicyCmd.Flags().StringVar(&testPolicyArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
+	testPolicyCmd.Flags().StringVar(&testPolicyArgs.events, "events", "", "Path to a file of recorded events, one serialized event per line")
+	_ = testPolicyCmd.MarkFlagRequired("events")
+	runtimeCmd.AddCommand(policyCmd)
 }
 
 func dumpProcessCache(cmd *cobra.Command, args []string) error {
@@ -112,6 +136,7 @@ func checkPolicies(cmd *cobra.Command, args []string) error {
 	enabled := map[eval.EventType]bool{"*": true}
 
 	opts := rules.NewOptsWithParams(model.SECLConstants, sprobe.SupportedDiscarders, enabled, sprobe.AllCustomRuleIDs(), model.SECLLegacyAttributes, &securityLogger.PatternLogger{})
+	opts.VariableScopes = model.SECLVariableScopes
 	model := &model.Model{}
 	ruleSet := rules.NewRuleSet(model, model.NewEvent, opts)
 
@@ -137,6 +162,45 @@ func checkPolicies(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func testPolicy(cmd *cobra.Command, args []string) error {
+	// enabled all the rules
+	enabled := map[eval.EventType]bool{"*": true}
+
+	opts := rules.NewOptsWithParams(model.SECLConstants, sprobe.SupportedDiscarders, enabled, sprobe.AllCustomRuleIDs(), model.SECLLegacyAttributes, &securityLogger.PatternLogger{})
+	opts.VariableScopes = model.SECLVariableScopes
+	model := &model.Model{}
+	ruleSet := rules.NewRuleSet(model, model.NewEvent, opts)
+
+	if err := rules.LoadPolicies(testPolicyArgs.dir, ruleSet); err.ErrorOrNil() != nil {
+		return err
+	}
+
+	file, err := os.Open(testPolicyArgs.events)
+	if err != nil {
+		return errors.Wrap(err, "unable to open the events file")
+	}
+	defer file.Close()
+
+	tester := rules.NewPolicyTester(ruleSet)
+	decoder := sprobe.NewEventDecoder(file)
+
+	for {
+		event, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return errors.Wrapf(err, "unable to decode event %d", len(tester.GetReport().Events))
+		}
+
+		tester.Test(event)
+	}
+
+	content, _ := json.MarshalIndent(tester.GetReport(), "", "\t")
+	fmt.Printf("%s\n", string(content))
+
+	return nil
+}
+
 func runRuntimeSelfTest(cmd *cobra.Command, args []string) error {
 	client, err := secagent.NewRuntimeSecurityClient()
 	if err != nil {
@@ -12,12 +12,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/compliance"
 	"github.com/DataDog/datadog-agent/pkg/compliance/agent"
 	"github.com/DataDog/datadog-agent/pkg/compliance/checks"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/report"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
@@ -30,9 +34,11 @@ import (
 
 var (
 	checkArgs = struct {
-		framework string
-		file      string
-		verbose   bool
+		framework  string
+		file       string
+		verbose    bool
+		report     string
+		reportFile string
 	}{}
 )
 
@@ -40,6 +46,8 @@ func setupCheckCmd(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&checkArgs.framework, "framework", "", "", "Framework to run the checks from")
 	cmd.Flags().StringVarP(&checkArgs.file, "file", "f", "", "Compliance suite file to read rules from")
 	cmd.Flags().BoolVarP(&checkArgs.verbose, "verbose", "v", false, "Include verbose details")
+	cmd.Flags().StringVarP(&checkArgs.report, "report", "", "", "Write a report of the results in the given format (json, junit or sarif)")
+	cmd.Flags().StringVarP(&checkArgs.reportFile, "report-file", "", "", "File to write the report to, defaults to the standard output")
 }
 
 // CheckCmd returns a cobra command to run security agent checks
@@ -57,6 +65,10 @@ func CheckCmd(confPathArray []string) *cobra.Command {
 }
 
 func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
+	if checkArgs.report != "" && !report.IsValidFormat(report.Format(checkArgs.report)) {
+		return fmt.Errorf("unsupported report format `%s`, expecting json, junit or sarif", checkArgs.report)
+	}
+
 	err := configureLogger()
 	if err != nil {
 		return err
@@ -114,7 +126,13 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 
 	options = append(options, checks.WithHostname(hostname))
 
-	reporter := &runCheckReporter{}
+	var reporter event.Reporter = &runCheckReporter{}
+
+	var collector *report.Collector
+	if checkArgs.report != "" {
+		collector = report.NewCollector()
+		reporter = collector
+	}
 
 	if ruleID != "" {
 		log.Infof("Looking for rule with ID=%s", ruleID)
@@ -126,10 +144,13 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		options = append(options, checks.WithMatchSuite(checks.IsFramework(checkArgs.framework)))
 	}
 
+	var files []string
 	if checkArgs.file != "" {
+		files = []string{checkArgs.file}
 		err = agent.RunChecksFromFile(reporter, checkArgs.file, options...)
 	} else {
 		configDir := config.Datadog.GetString("compliance_config.dir")
+		files, _ = filepath.Glob(filepath.Join(configDir, "*.yaml"))
 		err = agent.RunChecks(reporter, configDir, options...)
 	}
 
@@ -137,9 +158,46 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		log.Errorf("Failed to run checks: %v", err)
 		return err
 	}
+
+	if collector != nil {
+		return writeReport(collector, files, ruleID, hostname)
+	}
 	return nil
 }
 
+// writeReport writes the report of the checks, listing all the rules matching the command filters
+func writeReport(collector *report.Collector, files []string, ruleID string, hostname string) error {
+	for _, file := range files {
+		suite, err := compliance.ParseSuite(file)
+		if err != nil {
+			continue
+		}
+
+		if checkArgs.framework != "" && suite.Meta.Framework != checkArgs.framework {
+			continue
+		}
+
+		for i := range suite.Rules {
+			if ruleID != "" && suite.Rules[i].ID != ruleID {
+				continue
+			}
+			collector.AddRule(&suite.Meta, &suite.Rules[i])
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if checkArgs.reportFile != "" {
+		f, err := os.OpenFile(checkArgs.reportFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return collector.Build(hostname).Write(w, report.Format(checkArgs.report))
+}
+
 func configureLogger() error {
 	var (
 		logFormat = "%LEVEL | %Msg%n"
@@ -150,7 +208,14 @@ func configureLogger() error {
 		logFormat = fmt.Sprintf("%%Date(%s) | %%LEVEL | (%%ShortFilePath:%%Line in %%FuncShort) | %%Msg%%n", logDateFormat)
 		logLevel = "trace"
 	}
-	logger, err := seelog.LoggerFromWriterWithMinLevelAndFormat(os.Stdout, seelog.DebugLvl, logFormat)
+
+	// keep the standard output for the report
+	var output io.Writer = os.Stdout
+	if checkArgs.report != "" && checkArgs.reportFile == "" {
+		output = os.Stderr
+	}
+
+	logger, err := seelog.LoggerFromWriterWithMinLevelAndFormat(output, seelog.DebugLvl, logFormat)
 	if err != nil {
 		return err
 	}
@@ -17,8 +17,10 @@ import (
 	"github.com/DataDog/datadog-agent/cmd/system-probe/utils"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	networkconfig "github.com/DataDog/datadog-agent/pkg/network/config"
+	dnsdebugging "github.com/DataDog/datadog-agent/pkg/network/dns/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/encoding"
 	"github.com/DataDog/datadog-agent/pkg/network/http/debugging"
+	protocolsdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -116,7 +118,41 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 			return
 		}
 
-		utils.WriteAsJSON(w, debugging.HTTP(cs.HTTP, cs.DNS))
+		utils.WriteAsJSON(w, debugging.HTTP(cs.HTTP, cs.GRPC, cs.DNS))
+	})
+
+	httpMux.HandleFunc("/debug/protocol_classification", func(w http.ResponseWriter, req *http.Request) {
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, protocolsdebugging.Protocols(cs.Conns, cs.Protocols, cs.DNS))
+	})
+
+	httpMux.HandleFunc("/debug/dns_stats", func(w http.ResponseWriter, req *http.Request) {
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, dnsdebugging.DNS(cs.Conns, cs.DNSResolvers))
+	})
+
+	// serves the most recent failed DNS lookups, oldest first
+	httpMux.HandleFunc("/debug/dns_failed_lookups", func(w http.ResponseWriter, req *http.Request) {
+		lookups := nt.tracer.DNSFailedLookups()
+		if lookups == nil {
+			http.Error(w, "the recording of failed DNS lookups is disabled", http.StatusNotFound)
+			return
+		}
+		utils.WriteAsJSON(w, lookups)
 	})
 
 	// Convenience logging if nothing has made any requests to the system-probe in some time, let's log something.
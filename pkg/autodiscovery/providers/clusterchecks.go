@@ -12,13 +12,19 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
-const defaultGraceDuration = 60 * time.Second
+const (
+	defaultGraceDuration = 60 * time.Second
+	// checkStatsReportInterval is the interval at which the check stats are
+	// sent to the cluster-agent along with the node status
+	checkStatsReportInterval = 60 * time.Second
+)
 
 // ClusterChecksConfigProvider implements the ConfigProvider interface
 // for the cluster check feature.
@@ -26,6 +32,7 @@ type ClusterChecksConfigProvider struct {
 	dcaClient      clusteragent.DCAClientInterface
 	graceDuration  time.Duration
 	heartbeat      time.Time
+	statsReport    time.Time
 	lastChange     int64
 	identifier     string
 	flushedConfigs bool
@@ -92,6 +99,10 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
 	}
+	if time.Since(c.statsReport) >= checkStatsReportInterval {
+		// Let the cluster-agent weigh the checks running on this node
+		status.CheckStats = getCheckStats()
+	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
 	if err != nil {
@@ -105,6 +116,9 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 	}
 
 	c.heartbeat = time.Now()
+	if status.CheckStats != nil {
+		c.statsReport = c.heartbeat
+	}
 	if reply.IsUpToDate {
 		log.Tracef("Up to date with change %d", c.lastChange)
 	} else {
@@ -139,6 +153,21 @@ func (c *ClusterChecksConfigProvider) Collect(ctx context.Context) ([]integratio
 	return reply.Configs, nil
 }
 
+// getCheckStats returns the execution stats of the checks running on the node
+func getCheckStats() types.CLCRunnersStats {
+	stats := types.CLCRunnersStats{}
+	for _, checks := range expvars.GetCheckStats() {
+		for id, s := range checks {
+			stats[string(id)] = types.CLCRunnerStats{
+				AverageExecutionTime: int(s.AverageExecutionTime),
+				MetricSamples:        int(s.MetricSamples),
+				LastExecFailed:       s.LastError != "",
+			}
+		}
+	}
+	return stats
+}
+
 func init() {
 	RegisterProvider("clusterchecks", NewClusterChecksConfigProvider)
 }
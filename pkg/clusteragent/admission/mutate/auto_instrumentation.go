@@ -0,0 +1,334 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package mutate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+type language string
+
+const (
+	java   language = "java"
+	python language = "python"
+	js     language = "js"
+	dotnet language = "dotnet"
+
+	// Pod annotations selecting the library to inject for a language, e.g.
+	// admission.datadoghq.com/java-lib.version: "v0.87.0"
+	// admission.datadoghq.com/python-lib.custom-image: "my-registry/dd-lib-python-init:v0.50.0"
+	libVersionAnnotationKeyFormat     = "admission.datadoghq.com/%s-lib.version"
+	libCustomImageAnnotationKeyFormat = "admission.datadoghq.com/%s-lib.custom-image"
+
+	libImageNameFormat = "%s/dd-lib-%s-init:%s"
+
+	volumeName = "datadog-auto-instrumentation"
+	mountPath  = "/datadog-lib"
+
+	javaToolOptionsKey  = "JAVA_TOOL_OPTIONS"
+	javaToolOptionsVal  = "-javaagent:/datadog-lib/dd-java-agent.jar"
+	pythonPathKey       = "PYTHONPATH"
+	pythonPathVal       = "/datadog-lib/"
+	nodeOptionsKey      = "NODE_OPTIONS"
+	nodeOptionsVal      = "--require=/datadog-lib/node_modules/dd-trace/init"
+	dotnetProfilerGUID  = "{846F5F1C-F9AE-4B07-969E-05C26BC060D8}"
+	dotnetProfilerPath  = "/datadog-lib/Datadog.Trace.ClrProfiler.Native.so"
+	dotnetTracerHomeKey = "DD_DOTNET_TRACER_HOME"
+)
+
+var supportedLanguages = []language{java, python, js, dotnet}
+
+// libInfo describes the library image to inject for a language
+type libInfo struct {
+	lang  language
+	image string
+}
+
+// libConfig is the per-namespace configuration of a library
+type libConfig struct {
+	Version string `mapstructure:"version" json:"version"`
+	Image   string `mapstructure:"image" json:"image"`
+}
+
+// InjectAutoInstrumentation adds an init container copying the tracing library
+// of the application language into a shared volume, and the env vars loading it
+func InjectAutoInstrumentation(rawPod []byte, ns string, dc dynamic.Interface) ([]byte, error) {
+	return mutate(rawPod, ns, injectAutoInstrumentation, dc)
+}
+
+// injectAutoInstrumentation injects the tracing libraries requested by the pod
+// annotations or configured for the pod namespace
+func injectAutoInstrumentation(pod *corev1.Pod, ns string, _ dynamic.Interface) error {
+	var injected bool
+	defer func() {
+		metrics.MutationAttempts.Inc(metrics.LibInjectionMutationType, strconv.FormatBool(injected))
+	}()
+
+	if pod == nil {
+		metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "nil pod")
+		return errors.New("cannot inject lib into nil pod")
+	}
+
+	if ns == "" {
+		ns = pod.GetNamespace()
+	}
+
+	libs, err := extractLibInfo(pod, ns)
+	if err != nil {
+		metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "invalid config")
+		return err
+	}
+	if len(libs) == 0 {
+		return nil
+	}
+
+	if err := injectAutoInstruConfig(pod, libs); err != nil {
+		metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "cannot inject env")
+		return err
+	}
+	injected = true
+
+	return nil
+}
+
+// extractLibInfo returns the libraries to inject into the pod. The pod
+// annotations take precedence over the libraries configured for its namespace.
+func extractLibInfo(pod *corev1.Pod, ns string) ([]libInfo, error) {
+	if val := pod.GetLabels()[common.EnabledLabelKey]; val == "false" {
+		return nil, nil
+	}
+
+	registry := config.Datadog.GetString("admission_controller.auto_instrumentation.container_registry")
+	annotations := pod.GetAnnotations()
+	libs := []libInfo{}
+	requested := make(map[language]bool)
+
+	for _, lang := range supportedLanguages {
+		if image, found := annotations[fmt.Sprintf(libCustomImageAnnotationKeyFormat, lang)]; found {
+			libs = append(libs, libInfo{lang: lang, image: image})
+			requested[lang] = true
+			continue
+		}
+		if version, found := annotations[fmt.Sprintf(libVersionAnnotationKeyFormat, lang)]; found {
+			libs = append(libs, libInfo{lang: lang, image: libImageName(registry, lang, version)})
+			requested[lang] = true
+		}
+	}
+
+	if !shouldInjectConf(pod) {
+		// only the libraries explicitly requested by the pod annotations are injected
+		return libs, nil
+	}
+
+	nsLibs, err := getNamespaceLibConfigs(ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, lang := range supportedLanguages {
+		conf, found := nsLibs[string(lang)]
+		if !found || requested[lang] {
+			continue
+		}
+		switch {
+		case conf.Image != "":
+			libs = append(libs, libInfo{lang: lang, image: conf.Image})
+		case conf.Version != "":
+			libs = append(libs, libInfo{lang: lang, image: libImageName(registry, lang, conf.Version)})
+		default:
+			log.Warnf("Ignoring %s library configuration for namespace %s: neither image nor version is set", lang, ns)
+		}
+	}
+
+	return libs, nil
+}
+
+// getNamespaceLibConfigs returns the libraries configured for a namespace, by language
+func getNamespaceLibConfigs(ns string) (map[string]libConfig, error) {
+	key := "admission_controller.auto_instrumentation.namespaces"
+	var namespaces map[string]map[string]libConfig
+	var err error
+
+	raw := config.Datadog.Get(key)
+	if raw == nil {
+		return nil, nil
+	}
+	if s, ok := raw.(string); ok && s != "" {
+		err = json.Unmarshal([]byte(s), &namespaces)
+	} else {
+		err = config.Datadog.UnmarshalKey(key, &namespaces)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s configuration: %v", key, err)
+	}
+
+	return namespaces[ns], nil
+}
+
+func libImageName(registry string, lang language, version string) string {
+	return fmt.Sprintf(libImageNameFormat, registry, lang, version)
+}
+
+// injectAutoInstruConfig adds the shared volume, one init container per library
+// and the language specific env vars to the pod
+func injectAutoInstruConfig(pod *corev1.Pod, libs []libInfo) error {
+	sort.Slice(libs, func(i, j int) bool { return libs[i].lang < libs[j].lang })
+
+	for _, lib := range libs {
+		var err error
+		switch lib.lang {
+		case java:
+			err = injectLibEnv(pod, javaToolOptionsKey, func(current string) string { return appendOption(current, javaToolOptionsVal) })
+		case python:
+			err = injectLibEnv(pod, pythonPathKey, func(current string) string {
+				if current == "" {
+					return pythonPathVal
+				}
+				for _, path := range strings.Split(current, ":") {
+					if path == pythonPathVal {
+						return current
+					}
+				}
+				return pythonPathVal + ":" + current
+			})
+		case js:
+			err = injectLibEnv(pod, nodeOptionsKey, func(current string) string { return appendOption(current, nodeOptionsVal) })
+		case dotnet:
+			for _, env := range []corev1.EnvVar{
+				{Name: "CORECLR_ENABLE_PROFILING", Value: "1"},
+				{Name: "CORECLR_PROFILER", Value: dotnetProfilerGUID},
+				{Name: "CORECLR_PROFILER_PATH", Value: dotnetProfilerPath},
+				{Name: dotnetTracerHomeKey, Value: mountPath},
+			} {
+				injectEnv(pod, env)
+			}
+		default:
+			return fmt.Errorf("language %q is not supported", lib.lang)
+		}
+		if err != nil {
+			return err
+		}
+
+		injectLibInitContainer(pod, lib)
+	}
+
+	injectLibVolume(pod)
+	return nil
+}
+
+// injectLibEnv sets an env var of all the pod containers to the value returned
+// by build, which gets the current value of the env var
+func injectLibEnv(pod *corev1.Pod, name string, build func(current string) string) error {
+	for i, ctr := range pod.Spec.Containers {
+		index := -1
+		for j, env := range ctr.Env {
+			if env.Name == name {
+				index = j
+				break
+			}
+		}
+
+		if index < 0 {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{Name: name, Value: build("")})
+			continue
+		}
+
+		if ctr.Env[index].ValueFrom != nil {
+			return fmt.Errorf("%s is defined from a source in container %s of pod %s, cannot append to it", name, ctr.Name, podString(pod))
+		}
+		pod.Spec.Containers[i].Env[index].Value = build(ctr.Env[index].Value)
+	}
+	return nil
+}
+
+// appendOption appends a space-separated option to the value of an env var, unless it was
+// already appended by a previous invocation of the webhook
+func appendOption(current, option string) string {
+	for _, opt := range strings.Fields(current) {
+		if opt == option {
+			return current
+		}
+	}
+	if current == "" {
+		return option
+	}
+	return current + " " + option
+}
+
+// injectLibInitContainer adds the init container copying the library into the shared volume
+func injectLibInitContainer(pod *corev1.Pod, lib libInfo) {
+	name := fmt.Sprintf("datadog-lib-%s-init", lib.lang)
+	for _, ctr := range pod.Spec.InitContainers {
+		if ctr.Name == name {
+			log.Debugf("Init container %s already exists in pod %s", name, podString(pod))
+			return
+		}
+	}
+
+	log.Debugf("Injecting init container %s with image %s into pod %s", name, lib.image, podString(pod))
+	pod.Spec.InitContainers = append([]corev1.Container{
+		{
+			Name:    name,
+			Image:   lib.image,
+			Command: []string{"sh", "copy-lib.sh", mountPath},
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      volumeName,
+					MountPath: mountPath,
+				},
+			},
+		},
+	}, pod.Spec.InitContainers...)
+}
+
+// injectLibVolume adds the shared volume to the pod and mounts it in all its containers
+func injectLibVolume(pod *corev1.Pod) {
+	volumeExists := false
+	for _, vol := range pod.Spec.Volumes {
+		if vol.Name == volumeName {
+			volumeExists = true
+			break
+		}
+	}
+	if !volumeExists {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		})
+	}
+
+	for i, ctr := range pod.Spec.Containers {
+		mounted := false
+		for _, mount := range ctr.VolumeMounts {
+			if mount.Name == volumeName {
+				mounted = true
+				break
+			}
+		}
+		if !mounted {
+			pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      volumeName,
+				MountPath: mountPath,
+			})
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package mutate
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func fakePodWithAnnotations(annotations map[string]string) *corev1.Pod {
+	pod := fakePod("pod")
+	pod.Annotations = annotations
+	return pod
+}
+
+func Test_extractLibInfo(t *testing.T) {
+	mockConfig := config.Mock()
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		ns          string
+		setupConfig func()
+		want        []libInfo
+	}{
+		{
+			name:        "no annotation",
+			pod:         fakePodWithAnnotations(nil),
+			setupConfig: func() {},
+			want:        []libInfo{},
+		},
+		{
+			name: "java version",
+			pod: fakePodWithAnnotations(map[string]string{
+				"admission.datadoghq.com/java-lib.version": "v1",
+			}),
+			setupConfig: func() {},
+			want:        []libInfo{{lang: java, image: "gcr.io/datadoghq/dd-lib-java-init:v1"}},
+		},
+		{
+			name: "custom image takes precedence",
+			pod: fakePodWithAnnotations(map[string]string{
+				"admission.datadoghq.com/python-lib.version":      "v1",
+				"admission.datadoghq.com/python-lib.custom-image": "foo/bar:v2",
+			}),
+			setupConfig: func() {},
+			want:        []libInfo{{lang: python, image: "foo/bar:v2"}},
+		},
+		{
+			name: "custom registry",
+			pod: fakePodWithAnnotations(map[string]string{
+				"admission.datadoghq.com/js-lib.version": "v1",
+			}),
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.auto_instrumentation.container_registry", "my-registry")
+			},
+			want: []libInfo{{lang: js, image: "my-registry/dd-lib-js-init:v1"}},
+		},
+		{
+			name: "namespace libraries, pod not labelled",
+			pod:  fakePodWithAnnotations(nil),
+			ns:   "payments",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.mutate_unlabelled", false)
+				mockConfig.Set("admission_controller.auto_instrumentation.namespaces", `{"payments":{"java":{"version":"v1"}}}`)
+			},
+			want: []libInfo{},
+		},
+		{
+			name: "namespace libraries, mutate unlabelled",
+			pod: fakePodWithAnnotations(map[string]string{
+				"admission.datadoghq.com/java-lib.version": "v2",
+			}),
+			ns: "payments",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.mutate_unlabelled", true)
+				mockConfig.Set("admission_controller.auto_instrumentation.container_registry", "gcr.io/datadoghq")
+				mockConfig.Set("admission_controller.auto_instrumentation.namespaces", `{"payments":{"java":{"version":"v1"},"dotnet":{"image":"foo/dotnet:v3"}}}`)
+			},
+			want: []libInfo{
+				{lang: java, image: "gcr.io/datadoghq/dd-lib-java-init:v2"},
+				{lang: dotnet, image: "foo/dotnet:v3"},
+			},
+		},
+		{
+			name: "namespace libraries, other namespace",
+			pod:  fakePodWithAnnotations(nil),
+			ns:   "default",
+			setupConfig: func() {
+				mockConfig.Set("admission_controller.mutate_unlabelled", true)
+			},
+			want: []libInfo{},
+		},
+		{
+			name: "disabled by label",
+			pod: func() *corev1.Pod {
+				pod := fakePodWithAnnotations(map[string]string{
+					"admission.datadoghq.com/java-lib.version": "v1",
+				})
+				pod.Labels = map[string]string{"admission.datadoghq.com/enabled": "false"}
+				return pod
+			}(),
+			setupConfig: func() {},
+			want:        nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupConfig()
+			got, err := extractLibInfo(tt.pod, tt.ns)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_injectAutoInstruConfig(t *testing.T) {
+	pod := fakePodWithContainer("pod",
+		corev1.Container{Name: "app", Env: []corev1.EnvVar{fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-Xmx1g")}},
+		corev1.Container{Name: "sidecar"},
+	)
+
+	err := injectAutoInstruConfig(pod, []libInfo{
+		{lang: python, image: "python-image"},
+		{lang: java, image: "java-image"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, pod.Spec.InitContainers, 2)
+	assert.Equal(t, "datadog-lib-python-init", pod.Spec.InitContainers[0].Name)
+	assert.Equal(t, "python-image", pod.Spec.InitContainers[0].Image)
+	assert.Equal(t, "datadog-lib-java-init", pod.Spec.InitContainers[1].Name)
+	assert.Equal(t, "java-image", pod.Spec.InitContainers[1].Image)
+
+	require.Len(t, pod.Spec.Volumes, 1)
+	assert.Equal(t, volumeName, pod.Spec.Volumes[0].Name)
+	assert.NotNil(t, pod.Spec.Volumes[0].EmptyDir)
+
+	assert.Equal(t, []corev1.EnvVar{
+		fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-Xmx1g -javaagent:/datadog-lib/dd-java-agent.jar"),
+		fakeEnvWithValue("PYTHONPATH", "/datadog-lib/"),
+	}, pod.Spec.Containers[0].Env)
+	assert.Equal(t, []corev1.EnvVar{
+		fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-javaagent:/datadog-lib/dd-java-agent.jar"),
+		fakeEnvWithValue("PYTHONPATH", "/datadog-lib/"),
+	}, pod.Spec.Containers[1].Env)
+
+	for _, ctr := range pod.Spec.Containers {
+		assert.Equal(t, []corev1.VolumeMount{{Name: volumeName, MountPath: mountPath}}, ctr.VolumeMounts)
+	}
+}
+
+func Test_injectAutoInstruConfigReinvocation(t *testing.T) {
+	pod := fakePodWithContainer("pod", corev1.Container{
+		Name: "app",
+		Env: []corev1.EnvVar{
+			fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-Xmx1g"),
+			fakeEnvWithValue("PYTHONPATH", "/app"),
+		},
+	})
+	libs := []libInfo{
+		{lang: java, image: "java-image"},
+		{lang: python, image: "python-image"},
+		{lang: js, image: "js-image"},
+	}
+
+	// the webhook can be invoked again on the pod it already mutated
+	require.NoError(t, injectAutoInstruConfig(pod, libs))
+	require.NoError(t, injectAutoInstruConfig(pod, libs))
+
+	assert.Equal(t, []corev1.EnvVar{
+		fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-Xmx1g -javaagent:/datadog-lib/dd-java-agent.jar"),
+		fakeEnvWithValue("PYTHONPATH", "/datadog-lib/:/app"),
+		fakeEnvWithValue("NODE_OPTIONS", "--require=/datadog-lib/node_modules/dd-trace/init"),
+	}, pod.Spec.Containers[0].Env)
+	assert.Len(t, pod.Spec.InitContainers, 3)
+	assert.Len(t, pod.Spec.Volumes, 1)
+}
+
+func Test_injectAutoInstruConfigValueFrom(t *testing.T) {
+	pod := fakePodWithContainer("pod", corev1.Container{
+		Name: "app",
+		Env: []corev1.EnvVar{
+			{
+				Name: "NODE_OPTIONS",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+		},
+	})
+
+	err := injectAutoInstruConfig(pod, []libInfo{{lang: js, image: "js-image"}})
+	assert.Error(t, err)
+}
@@ -31,10 +31,14 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 		Dangling: makeConfigArray(d.store.danglingConfigs),
 	}
 	for _, node := range d.store.nodes {
+		node.RLock()
 		n := types.StateNodeResponse{
-			Name:    node.name,
-			Configs: makeConfigArray(node.digestToConfig),
+			Name:         node.name,
+			Configs:      makeConfigArray(node.digestToConfig),
+			Busyness:     node.busyness,
+			CheckWeights: makeCheckWeights(node.clcRunnerStats),
 		}
+		node.RUnlock()
 		response.Nodes = append(response.Nodes, n)
 	}
 
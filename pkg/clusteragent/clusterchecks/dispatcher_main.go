@@ -32,6 +32,8 @@ type dispatcher struct {
 	extraTags             []string
 	clcRunnersClient      clusteragent.CLCRunnerClientInterface
 	advancedDispatching   bool
+	rebalanceCooldown     int64
+	rebalanceThreshold    float64
 }
 
 func newDispatcher() *dispatcher {
@@ -52,6 +54,9 @@ func newDispatcher() *dispatcher {
 		d.extraTags = append(d.extraTags, fmt.Sprintf("kube_cluster_name:%s", clusterTagValue))
 	}
 
+	d.rebalanceCooldown = config.Datadog.GetInt64("cluster_checks.rebalance_cooldown")
+	d.rebalanceThreshold = config.Datadog.GetFloat64("cluster_checks.rebalance_threshold")
+
 	d.advancedDispatching = config.Datadog.GetBool("cluster_checks.advanced_dispatching_enabled")
 	if !d.advancedDispatching {
 		return d
@@ -139,6 +144,12 @@ func (d *dispatcher) add(config integration.Config) {
 	}
 
 	d.addConfig(config, target)
+
+	if d.advancedDispatching && target != "" {
+		// Account for the check until the next stats update of the node
+		// to avoid dispatching all the new checks to the same node
+		d.addEstimatedWeight(config.Digest(), target)
+	}
 }
 
 // remove deletes a given configuration
@@ -146,6 +157,10 @@ func (d *dispatcher) remove(config integration.Config) {
 	digest := config.Digest()
 	log.Debugf("Removing configuration %s:%s", config.Name, digest)
 	d.removeConfig(digest)
+
+	d.store.Lock()
+	delete(d.store.digestToWeight, digest)
+	d.store.Unlock()
 }
 
 // reset empties the store and resets all states
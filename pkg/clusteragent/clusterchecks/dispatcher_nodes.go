@@ -20,6 +20,11 @@ import (
 
 const defaultBusynessValue int = -1
 
+// reportedStatsTTL is the duration in seconds during which the check stats
+// reported by a node agent are preferred to the stats collected from its
+// CLC runner API. Node agents report their stats every minute.
+const reportedStatsTTL int64 = 180
+
 // getClusterCheckConfigs returns configurations dispatched to a given node
 func (d *dispatcher) getClusterCheckConfigs(nodeName string) ([]integration.Config, int64, error) {
 	d.store.RLock()
@@ -45,6 +50,12 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 		warmingUp = true
 	}
 	node := d.store.getOrCreateNodeStore(nodeName, clientIP)
+	if status.CheckStats != nil {
+		d.updateNodeStats(node, status.CheckStats)
+		node.Lock()
+		node.statsReportedAt = timestampNow()
+		node.Unlock()
+	}
 	d.store.Unlock()
 
 	node.Lock()
@@ -145,11 +156,6 @@ func (d *dispatcher) expireNodes() {
 // updateRunnersStats collects stats from the registred
 // Cluster Level Check runners and updates the stats cache
 func (d *dispatcher) updateRunnersStats() {
-	if d.clcRunnersClient == nil {
-		log.Debug("Cluster Level Check runner client was not correctly initialised")
-		return
-	}
-
 	start := time.Now()
 	defer func() {
 		updateStatsDuration.Set(time.Since(start).Seconds(), le.JoinLeaderValue)
@@ -160,31 +166,83 @@ func (d *dispatcher) updateRunnersStats() {
 	for name, node := range d.store.nodes {
 		node.RLock()
 		ip := node.clientIP
+		reportedAt := node.statsReportedAt
 		node.RUnlock()
 
+		if timestampNow()-reportedAt < reportedStatsTTL {
+			log.Tracef("Using the check stats reported by node %s", name)
+			continue
+		}
+
+		if d.clcRunnersClient == nil {
+			log.Debugf("Cluster Level Check runner client was not correctly initialised, cannot collect stats on node %s", name)
+			continue
+		}
+
 		stats, err := d.clcRunnersClient.GetRunnerStats(ip)
 		if err != nil {
-			log.Debugf("Cannot get CLC Runner stats with IP %s on node %s: %v", node.clientIP, name, err)
+			log.Debugf("Cannot get CLC Runner stats with IP %s on node %s: %v", ip, name, err)
 			statsCollectionFails.Inc(name, le.JoinLeaderValue)
 			continue
 		}
-		node.Lock()
-		for id, checkStats := range stats {
-			// Stats contain info about all the running checks on a node
-			// Node checks must be filtered from Cluster Checks
-			// so they can be included in calculating node Agent busyness and excluded from rebalancing decisions.
-			if _, found := d.store.idToDigest[check.ID(id)]; found {
-				// Cluster check detected (exists in the Cluster Agent checks store)
-				log.Tracef("Check %s running on node %s is a cluster check", id, node.name)
-				checkStats.IsClusterCheck = true
-				stats[id] = checkStats
-			}
+		d.updateNodeStats(node, stats)
+	}
+}
+
+// updateNodeStats replaces the check stats of a node and updates its busyness
+// and the known weights of the cluster checks it runs.
+// The store lock must be held by the caller, the node lock must not.
+func (d *dispatcher) updateNodeStats(node *nodeStore, stats types.CLCRunnersStats) {
+	digestWeights := make(map[string]int)
+	for id, checkStats := range stats {
+		// Stats contain info about all the running checks on a node
+		// Node checks must be filtered from Cluster Checks
+		// so they can be included in calculating node Agent busyness and excluded from rebalancing decisions.
+		if digest, found := d.store.idToDigest[check.ID(id)]; found {
+			// Cluster check detected (exists in the Cluster Agent checks store)
+			log.Tracef("Check %s running on node %s is a cluster check", id, node.name)
+			checkStats.IsClusterCheck = true
+			stats[id] = checkStats
+			digestWeights[digest] += busynessFunc(checkStats)
 		}
-		node.clcRunnerStats = stats
-		log.Tracef("Updated CLC Runner stats on node: %s, node IP: %s, stats: %v", name, node.clientIP, stats)
-		node.busyness = calculateBusyness(stats)
-		log.Debugf("Updated busyness on node: %s, node IP: %s, busyness value: %d", name, node.clientIP, node.busyness)
-		busyness.Set(float64(node.busyness), node.name, le.JoinLeaderValue)
-		node.Unlock()
+	}
+	for digest, weight := range digestWeights {
+		d.store.digestToWeight[digest] = weight
+	}
+
+	node.Lock()
+	defer node.Unlock()
+	node.clcRunnerStats = stats
+	log.Tracef("Updated CLC Runner stats on node: %s, node IP: %s, stats: %v", node.name, node.clientIP, stats)
+	node.busyness = calculateBusyness(stats)
+	log.Debugf("Updated busyness on node: %s, node IP: %s, busyness value: %d", node.name, node.clientIP, node.busyness)
+	busyness.Set(float64(node.busyness), node.name, le.JoinLeaderValue)
+}
+
+// addEstimatedWeight adds the weight of a newly dispatched check to the
+// busyness of its node. The last known weight of the check is used if it
+// already ran, the average weight of the cluster checks otherwise.
+func (d *dispatcher) addEstimatedWeight(digest, nodeName string) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	node, found := d.store.getNodeStore(nodeName)
+	if !found {
+		return
+	}
+
+	weight, found := d.store.digestToWeight[digest]
+	if !found && len(d.store.digestToWeight) > 0 {
+		total := 0
+		for _, w := range d.store.digestToWeight {
+			total += w
+		}
+		weight = total / len(d.store.digestToWeight)
+	}
+
+	node.Lock()
+	defer node.Unlock()
+	if node.busyness > defaultBusynessValue {
+		node.busyness += weight
 	}
 }
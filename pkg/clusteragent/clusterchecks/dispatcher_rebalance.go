@@ -52,7 +52,7 @@ func (d *dispatcher) calculateAvg() (int, error) {
 	defer d.store.RUnlock()
 
 	for _, node := range d.store.nodes {
-		busyness = node.GetBusyness(busynessFunc)
+		busyness += node.GetBusyness(busynessFunc)
 		length++
 	}
 
@@ -104,9 +104,18 @@ func (d *dispatcher) updateDiff(avg int) map[string]int {
 // A check Xi running on a node N is chosen to move to another node if it satisfies the following
 // Weight(Xi) >  Weight(Xj) (for each j != i, 0 <= j < len(weights))
 // where Weight(X) is the busyness value caused by running the check X.
+// Checks moved by a previous rebalancing less than rebalanceCooldown seconds
+// ago are not considered.
 func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 	d.store.RLock()
 	node, found := d.store.getNodeStore(nodeName)
+	coolingDown := make(map[string]struct{})
+	cutoff := timestampNow() - d.rebalanceCooldown
+	for checkID, movedAt := range d.store.checkMovedAt {
+		if movedAt > cutoff {
+			coolingDown[checkID] = struct{}{}
+		}
+	}
 	d.store.RUnlock()
 
 	if !found {
@@ -114,7 +123,10 @@ func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
 		return "", -1, fmt.Errorf("node %s not found in store", nodeName)
 	}
 
-	return node.GetMostWeightedClusterCheck(busynessFunc)
+	return node.GetMostWeightedClusterCheck(busynessFunc, func(checkID string) bool {
+		_, found := coolingDown[checkID]
+		return found
+	})
 }
 
 // pickNode select the most appropriate node to receive a specific check.
@@ -173,6 +185,23 @@ func (d *dispatcher) moveCheck(src, dest, checkID string) error {
 	return nil
 }
 
+// markChecksMoved records when checks were moved, to keep them on their
+// new node during the rebalancing cooldown
+func (d *dispatcher) markChecksMoved(moves []types.RebalanceResponse) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	now := timestampNow()
+	for id, movedAt := range d.store.checkMovedAt {
+		if movedAt <= now-d.rebalanceCooldown {
+			delete(d.store.checkMovedAt, id)
+		}
+	}
+	for _, move := range moves {
+		d.store.checkMovedAt[move.CheckID] = now
+	}
+}
+
 // rebalance tries to optimize the checks repartition on cluster level check
 // runners with less possible check moves based on the runner stats.
 func (d *dispatcher) rebalance() []types.RebalanceResponse {
@@ -195,7 +224,15 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 	diffMap, weights := d.getDiffAndWeights(totalAvg)
 	sort.Sort(weights)
 
+	// hysteresis: checks are moved from a node only if its busyness exceeds
+	// the average by more than the rebalancing threshold, and then until
+	// it gets back to the average
+	threshold := int(float64(totalAvg) * d.rebalanceThreshold)
+
 	for _, nodeWeight := range weights {
+		if diffMap[nodeWeight.nodeName] <= threshold {
+			continue
+		}
 		for diffMap[nodeWeight.nodeName] > 0 {
 			// try to move checks from a node only of the node busyness is above the average
 			sourceNodeName := nodeWeight.nodeName
@@ -241,5 +278,9 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 		}
 	}
 
+	// checks can move several times during a rebalancing,
+	// the cooldown only applies to the next ones
+	d.markChecksMoved(checksMoved)
+
 	return checksMoved
 }
@@ -9,6 +9,7 @@ package clusterchecks
 
 import (
 	"fmt"
+	"sort"
 	"testing"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
@@ -16,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRebalance(t *testing.T) {
@@ -1448,3 +1450,108 @@ func TestMoveCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestRebalanceCooldown(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		movedAt    map[string]int64
+		expectedA  []string
+		expectedB  []string
+		movedCheck string
+	}{
+		{
+			name:       "no check moved recently",
+			movedAt:    map[string]int64{},
+			expectedA:  []string{"checkA1"},
+			expectedB:  []string{"checkA0"},
+			movedCheck: "checkA0",
+		},
+		{
+			name:       "heaviest check moved recently",
+			movedAt:    map[string]int64{"checkA0": timestampNow()},
+			expectedA:  []string{"checkA0"},
+			expectedB:  []string{"checkA1"},
+			movedCheck: "checkA1",
+		},
+		{
+			name:       "heaviest check moved before the cooldown",
+			movedAt:    map[string]int64{"checkA0": timestampNow() - 3600},
+			expectedA:  []string{"checkA1"},
+			expectedB:  []string{"checkA0"},
+			movedCheck: "checkA0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dispatcher := newDispatcher()
+			dispatcher.store.active = true
+			dispatcher.store.checkMovedAt = tc.movedAt
+
+			dispatcher.store.nodes["A"] = newNodeStore("A", "")
+			dispatcher.store.nodes["A"].clcRunnerStats = types.CLCRunnersStats{
+				"checkA0": types.CLCRunnerStats{AverageExecutionTime: 500, IsClusterCheck: true},
+				"checkA1": types.CLCRunnerStats{AverageExecutionTime: 250, IsClusterCheck: true},
+			}
+			dispatcher.store.nodes["B"] = newNodeStore("B", "")
+
+			moves := dispatcher.rebalance()
+			require.Len(t, moves, 1)
+			assert.Equal(t, tc.movedCheck, moves[0].CheckID)
+
+			assert.Equal(t, tc.expectedA, orderedCheckIDs(dispatcher.store.nodes["A"].clcRunnerStats))
+			assert.Equal(t, tc.expectedB, orderedCheckIDs(dispatcher.store.nodes["B"].clcRunnerStats))
+			assert.Contains(t, dispatcher.store.checkMovedAt, tc.movedCheck)
+
+			requireNotLocked(t, dispatcher.store)
+		})
+	}
+}
+
+func TestRebalanceThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		threshold float64
+		moved     int
+	}{
+		{
+			name:      "imbalance below the threshold",
+			threshold: 0.1,
+			moved:     0,
+		},
+		{
+			name:      "no threshold",
+			threshold: 0,
+			moved:     1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dispatcher := newDispatcher()
+			dispatcher.store.active = true
+			dispatcher.rebalanceThreshold = tc.threshold
+
+			// busyness of A: 1000, busyness of B: 12*92 = 1104
+			dispatcher.store.nodes["A"] = newNodeStore("A", "")
+			dispatcher.store.nodes["A"].clcRunnerStats = types.CLCRunnersStats{
+				"checkA0": types.CLCRunnerStats{AverageExecutionTime: 1250, IsClusterCheck: true},
+			}
+			dispatcher.store.nodes["B"] = newNodeStore("B", "")
+			dispatcher.store.nodes["B"].clcRunnerStats = types.CLCRunnersStats{}
+			for i := 0; i < 12; i++ {
+				dispatcher.store.nodes["B"].clcRunnerStats[fmt.Sprintf("checkB%d", i)] = types.CLCRunnerStats{AverageExecutionTime: 115, IsClusterCheck: true}
+			}
+
+			moves := dispatcher.rebalance()
+			assert.Len(t, moves, tc.moved)
+
+			requireNotLocked(t, dispatcher.store)
+		})
+	}
+}
+
+func orderedCheckIDs(stats types.CLCRunnersStats) []string {
+	ids := []string{}
+	for id := range stats {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/clustername"
 	"github.com/DataDog/datadog-agent/pkg/version"
@@ -637,3 +638,86 @@ func TestUpdateRunnersStats(t *testing.T) {
 
 	requireNotLocked(t, dispatcher.store)
 }
+
+func TestProcessNodeStatusCheckStats(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.store.active = true
+	dispatcher.clcRunnersClient = &dummyClcRunnerClient
+
+	config := integration.Config{
+		Name:         "jmx",
+		ClusterCheck: true,
+		Instances:    []integration.Data{integration.Data("host: foo")},
+	}
+	id := string(check.BuildID(config.Name, config.Instances[0], config.InitConfig))
+	dispatcher.addConfig(config, "node1")
+
+	status := types.NodeStatus{
+		CheckStats: types.CLCRunnersStats{
+			id: {
+				AverageExecutionTime: 100,
+				MetricSamples:        50,
+			},
+			"cpu": {
+				AverageExecutionTime: 10,
+				MetricSamples:        10,
+			},
+		},
+	}
+	_, err := dispatcher.processNodeStatus("node1", "10.0.0.1", status)
+	assert.NoError(t, err)
+
+	node1, found := dispatcher.store.getNodeStore("node1")
+	require.True(t, found)
+	assert.True(t, node1.clcRunnerStats[id].IsClusterCheck)
+	assert.False(t, node1.clcRunnerStats["cpu"].IsClusterCheck)
+	assert.Equal(t, 100, node1.busyness)
+	assert.Equal(t, 90, dispatcher.store.digestToWeight[config.Digest()])
+
+	// Reported stats are preferred to the ones of the CLC runner API
+	dispatcher.updateRunnersStats()
+	assert.Len(t, node1.clcRunnerStats, 2)
+	assert.Equal(t, 100, node1.busyness)
+
+	// Until they are outdated
+	node1.statsReportedAt -= reportedStatsTTL
+	dispatcher.updateRunnersStats()
+	assert.EqualValues(t, types.CLCRunnersStats{
+		"http_check:My Nginx Service:b0041608e66d20ba": {
+			AverageExecutionTime: 241,
+			MetricSamples:        3,
+		},
+	}, node1.clcRunnerStats)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestAddEstimatedWeight(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.store.active = true
+	dispatcher.advancedDispatching = true
+
+	dispatcher.store.getOrCreateNodeStore("node1", "").busyness = 100
+	dispatcher.store.getOrCreateNodeStore("node2", "").busyness = 50
+
+	known := generateIntegration("known")
+	dispatcher.store.digestToWeight[known.Digest()] = 80
+	dispatcher.store.digestToWeight["other"] = 40
+
+	// The last known weight of the check is added to the least busy node
+	dispatcher.add(known)
+	assert.Equal(t, "node2", dispatcher.store.digestToNode[known.Digest()])
+	assert.Equal(t, 130, dispatcher.store.nodes["node2"].busyness)
+
+	// The average weight is used for unknown checks
+	unknown := generateIntegration("unknown")
+	dispatcher.add(unknown)
+	assert.Equal(t, "node1", dispatcher.store.digestToNode[unknown.Digest()])
+	assert.Equal(t, 160, dispatcher.store.nodes["node1"].busyness)
+
+	// The weight is forgotten once the check is unscheduled
+	dispatcher.remove(known)
+	assert.NotContains(t, dispatcher.store.digestToWeight, known.Digest())
+
+	requireNotLocked(t, dispatcher.store)
+}
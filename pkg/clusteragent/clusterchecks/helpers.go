@@ -54,6 +54,27 @@ func busynessFunc(s types.CLCRunnerStats) int {
 	return int(checkExecutionTimeWeight*float64(s.AverageExecutionTime) + checkMetricSamplesWeight*float64(s.MetricSamples))
 }
 
+// makeCheckWeights returns the weights of the checks of a node, heaviest first
+func makeCheckWeights(checkStats types.CLCRunnersStats) []types.CheckWeight {
+	weights := make([]types.CheckWeight, 0, len(checkStats))
+	for id, stats := range checkStats {
+		weights = append(weights, types.CheckWeight{
+			CheckID:              id,
+			AverageExecutionTime: stats.AverageExecutionTime,
+			MetricSamples:        stats.MetricSamples,
+			IsClusterCheck:       stats.IsClusterCheck,
+			Weight:               busynessFunc(stats),
+		})
+	}
+	sort.Slice(weights, func(i, j int) bool {
+		if weights[i].Weight != weights[j].Weight {
+			return weights[i].Weight > weights[j].Weight
+		}
+		return weights[i].CheckID < weights[j].CheckID
+	})
+	return weights
+}
+
 // orderedKeys sorts the keys of a map and return them in a slice
 func orderedKeys(m map[string]int) []string {
 	keys := []string{}
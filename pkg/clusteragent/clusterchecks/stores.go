@@ -30,6 +30,8 @@ type clusterStore struct {
 	danglingConfigs  map[string]integration.Config            // Configs we could not dispatch to any node
 	endpointsConfigs map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
 	idToDigest       map[check.ID]string                      // link check IDs to check configs
+	digestToWeight   map[string]int                           // Last known weight of check configs
+	checkMovedAt     map[string]int64                         // Last time checks were moved by the rebalancing, by check ID
 }
 
 func newClusterStore() *clusterStore {
@@ -47,6 +49,8 @@ func (s *clusterStore) reset() {
 	s.danglingConfigs = make(map[string]integration.Config)
 	s.endpointsConfigs = make(map[string]map[string]integration.Config)
 	s.idToDigest = make(map[check.ID]string)
+	s.digestToWeight = make(map[string]int)
+	s.checkMovedAt = make(map[string]int64)
 }
 
 // getNodeStore retrieves the store struct for a given node name, if it exists
@@ -88,6 +92,7 @@ type nodeStore struct {
 	digestToConfig   map[string]integration.Config
 	clientIP         string
 	clcRunnerStats   types.CLCRunnersStats
+	statsReportedAt  int64 // last time the node agent reported its check stats
 	busyness         int
 }
 
@@ -163,9 +168,10 @@ func (s *nodeStore) GetBusyness(busynessFunc func(stats types.CLCRunnerStats) in
 	return busyness
 }
 
-// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node
+// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node,
+// ignoring the checks for which excluded returns true
 // The nodeStore handles thread safety for this public method
-func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int) (string, int, error) {
+func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int, excluded func(checkID string) bool) (string, int, error) {
 	s.RLock()
 	defer s.RUnlock()
 	if len(s.clcRunnerStats) == 0 {
@@ -177,7 +183,7 @@ func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CL
 	checkWeight := 0
 	for id, stats := range s.clcRunnerStats {
 		busyness := busynessFunc(stats)
-		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck {
+		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck && !excluded(id) {
 			// Only consider Cluster Checks
 			checkWeight = busyness
 			checkID = id
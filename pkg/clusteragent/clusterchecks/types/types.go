@@ -12,6 +12,9 @@ import (
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
 	LastChange int64 `json:"last_change"`
+	// CheckStats holds the execution stats of the checks running on the
+	// node, it is only sent periodically and is nil otherwise
+	CheckStats CLCRunnersStats `json:"check_stats,omitempty"`
 }
 
 // StatusResponse holds the DCA response for a status report
@@ -47,8 +50,19 @@ type StateResponse struct {
 
 // StateNodeResponse is a chunk of StateResponse
 type StateNodeResponse struct {
-	Name    string               `json:"name"`
-	Configs []integration.Config `json:"configs"`
+	Name         string               `json:"name"`
+	Configs      []integration.Config `json:"configs"`
+	Busyness     int                  `json:"busyness"`
+	CheckWeights []CheckWeight        `json:"check_weights,omitempty"`
+}
+
+// CheckWeight is the weight of a check in the busyness of the node running it
+type CheckWeight struct {
+	CheckID              string `json:"check_id"`
+	AverageExecutionTime int    `json:"average_execution_time"`
+	MetricSamples        int    `json:"metric_samples"`
+	IsClusterCheck       bool   `json:"is_cluster_check"`
+	Weight               int    `json:"weight"`
 }
 
 // Stats holds statistics for the agent status command
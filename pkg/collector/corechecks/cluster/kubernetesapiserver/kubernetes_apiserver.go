@@ -24,6 +24,7 @@ import (
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
@@ -34,6 +35,7 @@ import (
 // Covers the Control Plane service check and the in memory pod metadata.
 const (
 	KubeControlPaneCheck          = "kube_apiserver_controlplane.up"
+	kubeEventsCountMetric         = "kube_apiserver.events.count"
 	kubernetesAPIServerCheckName  = "kubernetes_apiserver"
 	eventTokenKey                 = "event"
 	maxEventCardinality           = 300
@@ -54,6 +56,14 @@ type KubeASConfig struct {
 	LeaderSkip               bool     `yaml:"skip_leader_election"`
 	ResyncPeriodEvents       int      `yaml:"kubernetes_event_resync_period_s"`
 	UseComponentStatus       bool     `yaml:"use_component_status"`
+	// IncludeEvents restricts the collected events to the ones matching one of the filters
+	IncludeEvents []KubeEventFilter `yaml:"include_events"`
+	// ExcludeEvents drops the events matching one of the filters
+	ExcludeEvents []KubeEventFilter `yaml:"exclude_events"`
+	// EventsAsLogs sends the events matching one of the filters to the logs pipeline instead of the event stream
+	EventsAsLogs []KubeEventFilter `yaml:"send_events_as_logs"`
+	// EventMetricReasons lists the event reasons counted in the kube_apiserver.events.count metric
+	EventMetricReasons []string `yaml:"event_metric_reasons"`
 }
 
 // EventC holds the information pertaining to which event we collected last and when we last re-synced.
@@ -68,6 +78,7 @@ type KubeASCheck struct {
 	instance        *KubeASConfig
 	eventCollection EventC
 	ignoredEvents   string
+	metricReasons   map[string]struct{}
 	ac              *apiserver.APIClient
 	oshiftAPILevel  apiserver.OpenShiftAPILevel
 	providerIDCache *cache.Cache
@@ -118,6 +129,11 @@ func (k *KubeASCheck) Configure(config, initConfig integration.Data, source stri
 	}
 	k.ignoredEvents = convertFilter(k.instance.FilteredEventTypes)
 
+	k.metricReasons = make(map[string]struct{}, len(k.instance.EventMetricReasons))
+	for _, reason := range k.instance.EventMetricReasons {
+		k.metricReasons[strings.ToLower(reason)] = struct{}{}
+	}
+
 	return nil
 }
 
@@ -295,12 +311,28 @@ func (k *KubeASCheck) parseComponentStatus(sender aggregator.Sender, componentsS
 
 // processEvents:
 // - iterates over the Kubernetes Events
+// - counts the events whose reason is turned into a metric
+// - drops the filtered out events and sends the ones routed to the logs pipeline as structured logs
 // - extracts some attributes and builds a structure ready to be submitted as a Datadog event (bundle)
 // - formats the bundle and submit the Datadog event
 func (k *KubeASCheck) processEvents(sender aggregator.Sender, events []*v1.Event) error {
 	eventsByObject := make(map[string]*kubernetesEventBundle)
+	hostname, _ := util.GetHostname(context.TODO())
+	clusterName := clustername.GetClusterName(context.TODO(), hostname)
 
 	for _, event := range events {
+		if tags := eventMetricTags(k.metricReasons, event); tags != nil {
+			sender.Count(kubeEventsCountMetric, 1, "", tags)
+		}
+
+		switch k.instance.routeEvent(event) {
+		case routeDropped:
+			continue
+		case routeLog:
+			k.sendEventLog(sender, event, clusterName)
+			continue
+		}
+
 		id := bundleID(event)
 		bundle, found := eventsByObject[id]
 		if found == false {
@@ -312,8 +344,6 @@ func (k *KubeASCheck) processEvents(sender aggregator.Sender, events []*v1.Event
 			k.Warnf("Error while bundling events, %s.", err.Error()) //nolint:errcheck
 		}
 	}
-	hostname, _ := util.GetHostname(context.TODO())
-	clusterName := clustername.GetClusterName(context.TODO(), hostname)
 	for _, bundle := range eventsByObject {
 		datadogEv, err := bundle.formatEvents(clusterName, k.providerIDCache)
 		if err != nil {
@@ -325,6 +355,16 @@ func (k *KubeASCheck) processEvents(sender aggregator.Sender, events []*v1.Event
 	return nil
 }
 
+// sendEventLog submits the event as a structured log to the logs pipeline
+func (k *KubeASCheck) sendEventLog(sender aggregator.Sender, event *v1.Event, clusterName string) {
+	eventLog, err := formatEventLog(event, clusterName)
+	if err != nil {
+		k.Warnf("Error while formatting event log, %s. Not submitting", err.Error()) //nolint:errcheck
+		return
+	}
+	sender.EventPlatformEvent(string(eventLog), epforwarder.EventTypeKubernetesEvents)
+}
+
 func (k *KubeASCheck) componentStatusCheck(sender aggregator.Sender) error {
 	componentsStatus, err := k.ac.ComponentStatuses()
 	if err != nil {
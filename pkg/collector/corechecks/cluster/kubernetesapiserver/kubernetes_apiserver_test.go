@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/clustername"
 	"k8s.io/apimachinery/pkg/types"
@@ -333,3 +334,35 @@ func TestProcessEventsType(t *testing.T) {
 	mocked.AssertNumberOfCalls(t, "Event", 2)
 	mocked.AssertExpectations(t)
 }
+
+func TestProcessEventsRouting(t *testing.T) {
+	ev1 := createEvent(2, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "default-scheduler", "machine-blue", "Scheduled", "Successfully assigned dca-789976f5d7-2ljx6 to ip-10-0-0-54", "Normal", 709662600)
+	ev2 := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	ev3 := createEvent(1, "kube-system", "metrics-server", "HorizontalPodAutoscaler", "e6417a7f-f566-11e7-9749-0e4863e1cbf5", "horizontal-pod-autoscaler", "", "FailedGetScale", "no matches for kind", "Warning", 709662600)
+
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{
+		ExcludeEvents:      []KubeEventFilter{{Kinds: []string{"HorizontalPodAutoscaler"}}},
+		EventsAsLogs:       []KubeEventFilter{{Types: []string{"Normal"}}},
+		EventMetricReasons: []string{"BackOff", "FailedGetScale"},
+	})
+	kubeASCheck.metricReasons = map[string]struct{}{"backoff": {}, "failedgetscale": {}}
+
+	mocked := mocksender.NewMockSender(kubeASCheck.ID())
+	mocked.On("Event", mock.AnythingOfType("metrics.Event"))
+	mocked.On("Count", mock.AnythingOfType("string"), mock.AnythingOfType("float64"), mock.AnythingOfType("string"), mock.AnythingOfType("[]string"))
+	mocked.On("EventPlatformEvent", mock.AnythingOfType("string"), mock.AnythingOfType("string"))
+
+	kubeASCheck.processEvents(mocked, []*v1.Event{ev1, ev2, ev3})
+
+	// the excluded event is still counted
+	mocked.AssertMetric(t, "Count", kubeEventsCountMetric, 1, "", []string{"reason:BackOff", "kubernetes_kind:Pod", "kube_namespace:default"})
+	mocked.AssertMetric(t, "Count", kubeEventsCountMetric, 1, "", []string{"reason:FailedGetScale", "kubernetes_kind:HorizontalPodAutoscaler", "kube_namespace:kube-system"})
+	mocked.AssertNumberOfCalls(t, "Count", 2)
+
+	// the normal event is sent as a log, the warning one as an event
+	mocked.AssertNumberOfCalls(t, "EventPlatformEvent", 1)
+	assert.Equal(t, epforwarder.EventTypeKubernetesEvents, mocked.Calls[0].Arguments.Get(1))
+	assert.Contains(t, mocked.Calls[0].Arguments.Get(0), `"reason":"Scheduled"`)
+	mocked.AssertNumberOfCalls(t, "Event", 1)
+	assert.Contains(t, (mocked.Calls[3].Arguments.Get(0)).(metrics.Event).Text, "4 **BackOff**")
+}
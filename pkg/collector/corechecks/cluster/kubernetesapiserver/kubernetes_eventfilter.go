@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// KubeEventFilter selects Kubernetes events. An event matches the filter if
+// every non-empty field of the filter matches it, so an empty filter matches
+// all the events.
+type KubeEventFilter struct {
+	Kinds      []string `yaml:"kinds"`
+	Namespaces []string `yaml:"namespaces"`
+	Reasons    []string `yaml:"reasons"`
+	Types      []string `yaml:"types"`
+}
+
+func (f KubeEventFilter) match(event *v1.Event) bool {
+	return matchFold(f.Kinds, event.InvolvedObject.Kind) &&
+		matchExact(f.Namespaces, event.InvolvedObject.Namespace) &&
+		matchFold(f.Reasons, event.Reason) &&
+		matchFold(f.Types, event.Type)
+}
+
+// matchAnyFilter returns whether the event matches at least one of the filters
+func matchAnyFilter(filters []KubeEventFilter, event *v1.Event) bool {
+	for _, f := range filters {
+		if f.match(event) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchFold(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchExact(values []string, s string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// eventRoute is the destination of a collected Kubernetes event
+type eventRoute int
+
+const (
+	routeDropped eventRoute = iota
+	routeEvent
+	routeLog
+)
+
+// routeEvent returns where the event should be sent. Events not matching the
+// include filters, or matching the exclude filters, are dropped. The other
+// ones are sent as Datadog events, unless they match the log filters.
+func (c *KubeASConfig) routeEvent(event *v1.Event) eventRoute {
+	if len(c.IncludeEvents) > 0 && !matchAnyFilter(c.IncludeEvents, event) {
+		return routeDropped
+	}
+	if matchAnyFilter(c.ExcludeEvents, event) {
+		return routeDropped
+	}
+	if matchAnyFilter(c.EventsAsLogs, event) {
+		return routeLog
+	}
+	return routeEvent
+}
+
+// eventMetricTags returns the tags of the event count metric, nil if the event
+// reason isn't turned into a metric
+func eventMetricTags(reasons map[string]struct{}, event *v1.Event) []string {
+	if _, found := reasons[strings.ToLower(event.Reason)]; !found {
+		return nil
+	}
+
+	tags := []string{
+		fmt.Sprintf("reason:%s", event.Reason),
+		fmt.Sprintf("kubernetes_kind:%s", event.InvolvedObject.Kind),
+	}
+	if event.InvolvedObject.Namespace != "" {
+		tags = append(tags, fmt.Sprintf("kube_namespace:%s", event.InvolvedObject.Namespace))
+	}
+	return tags
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestRouteEvent(t *testing.T) {
+	podEvent := createEvent(1, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	hpaEvent := createEvent(1, "kube-system", "metrics-server", "HorizontalPodAutoscaler", "e6417a7f-f566-11e7-9749-0e4863e1cbf5", "horizontal-pod-autoscaler", "", "FailedGetScale", "no matches for kind", "Warning", 709662600)
+	nodeEvent := createEvent(1, "", "machine-blue", "Node", "e63e74fa-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "NodeReady", "Node is ready", "Normal", 709662600)
+
+	tests := []struct {
+		name     string
+		config   string
+		expected []eventRoute
+	}{
+		{
+			name:     "no filter",
+			config:   "",
+			expected: []eventRoute{routeEvent, routeEvent, routeEvent},
+		},
+		{
+			name: "include kinds",
+			config: `
+include_events:
+  - kinds: [pod, Node]
+`,
+			expected: []eventRoute{routeEvent, routeDropped, routeEvent},
+		},
+		{
+			name: "include namespace and type",
+			config: `
+include_events:
+  - namespaces: [default]
+  - types: [Normal]
+`,
+			expected: []eventRoute{routeEvent, routeDropped, routeEvent},
+		},
+		{
+			name: "exclude reason of a kind",
+			config: `
+exclude_events:
+  - kinds: [HorizontalPodAutoscaler]
+    reasons: [FailedGetScale]
+  - kinds: [Pod]
+    reasons: [Pulled]
+`,
+			expected: []eventRoute{routeEvent, routeDropped, routeEvent},
+		},
+		{
+			name: "send normal events as logs",
+			config: `
+send_events_as_logs:
+  - types: [Normal]
+`,
+			expected: []eventRoute{routeEvent, routeEvent, routeLog},
+		},
+		{
+			name: "exclusion takes precedence over logs",
+			config: `
+exclude_events:
+  - namespaces: [kube-system]
+send_events_as_logs:
+  - {}
+`,
+			expected: []eventRoute{routeLog, routeDropped, routeLog},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &KubeASConfig{}
+			assert.NoError(t, yaml.Unmarshal([]byte(tt.config), conf))
+			assert.Equal(t, tt.expected[0], conf.routeEvent(podEvent))
+			assert.Equal(t, tt.expected[1], conf.routeEvent(hpaEvent))
+			assert.Equal(t, tt.expected[2], conf.routeEvent(nodeEvent))
+		})
+	}
+}
+
+func TestEventMetricTags(t *testing.T) {
+	reasons := map[string]struct{}{"backoff": {}, "nodeready": {}}
+
+	podEvent := createEvent(1, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	assert.Equal(t, []string{"reason:BackOff", "kubernetes_kind:Pod", "kube_namespace:default"}, eventMetricTags(reasons, podEvent))
+
+	nodeEvent := createEvent(1, "", "machine-blue", "Node", "e63e74fa-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "NodeReady", "Node is ready", "Normal", 709662600)
+	assert.Equal(t, []string{"reason:NodeReady", "kubernetes_kind:Node"}, eventMetricTags(reasons, nodeEvent))
+
+	scheduledEvent := createEvent(1, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "default-scheduler", "", "Scheduled", "Successfully assigned", "Normal", 709662600)
+	assert.Nil(t, eventMetricTags(reasons, scheduledEvent))
+	assert.Nil(t, eventMetricTags(nil, podEvent))
+}
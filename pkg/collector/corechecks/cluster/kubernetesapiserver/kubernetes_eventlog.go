@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	eventLogSource        = "kubernetes"
+	eventLogStatusInfo    = "info"
+	eventLogStatusWarning = "warning"
+)
+
+// kubernetesEventLog is the structured log sent for a Kubernetes event
+// routed to the logs pipeline
+type kubernetesEventLog struct {
+	Message   string                   `json:"message"`
+	Status    string                   `json:"status"`
+	Timestamp int64                    `json:"timestamp"`
+	Hostname  string                   `json:"hostname,omitempty"`
+	Source    string                   `json:"ddsource"`
+	Service   string                   `json:"service,omitempty"`
+	Tags      string                   `json:"ddtags"`
+	Event     kubernetesEventLogDetail `json:"kubernetes_event"`
+}
+
+type kubernetesEventLogDetail struct {
+	Reason         string                   `json:"reason"`
+	Type           string                   `json:"type"`
+	Count          int32                    `json:"count"`
+	Component      string                   `json:"source_component,omitempty"`
+	FirstTimestamp int64                    `json:"first_timestamp,omitempty"`
+	LastTimestamp  int64                    `json:"last_timestamp,omitempty"`
+	InvolvedObject kubernetesEventLogObject `json:"involved_object"`
+}
+
+type kubernetesEventLogObject struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	UID       string `json:"uid,omitempty"`
+	FieldPath string `json:"field_path,omitempty"`
+}
+
+// formatEventLog returns the JSON structured log of a Kubernetes event
+func formatEventLog(event *v1.Event, clusterName string) ([]byte, error) {
+	if event == nil || event.InvolvedObject.Kind == "" || event.Reason == "" {
+		return nil, errors.New("could not retrieve some attributes of the event")
+	}
+
+	tags := []string{
+		fmt.Sprintf("source_component:%s", event.Source.Component),
+		fmt.Sprintf("kubernetes_kind:%s", event.InvolvedObject.Kind),
+		fmt.Sprintf("name:%s", event.InvolvedObject.Name),
+		fmt.Sprintf("reason:%s", event.Reason),
+	}
+	if kindTag := getKindTag(event.InvolvedObject.Kind, event.InvolvedObject.Name); kindTag != "" {
+		tags = append(tags, kindTag)
+	}
+	if event.InvolvedObject.Namespace != "" {
+		tags = append(tags, fmt.Sprintf("kube_namespace:%s", event.InvolvedObject.Namespace))
+	}
+	if clusterName != "" {
+		tags = append(tags, fmt.Sprintf("kube_cluster_name:%s", clusterName))
+	}
+
+	// Same hostname logic as the bundled events
+	var hostname string
+	if event.InvolvedObject.Kind == "Pod" || event.InvolvedObject.Kind == "Node" {
+		hostname = event.Source.Host
+		if hostname != "" && clusterName != "" {
+			hostname = hostname + "-" + clusterName
+		}
+	}
+
+	status := eventLogStatusInfo
+	if event.Type == v1.EventTypeWarning {
+		status = eventLogStatusWarning
+	}
+
+	return json.Marshal(kubernetesEventLog{
+		Message:   event.Message,
+		Status:    status,
+		Timestamp: eventLogTimestamp(event),
+		Hostname:  hostname,
+		Source:    eventLogSource,
+		Service:   event.Source.Component,
+		Tags:      strings.Join(tags, ","),
+		Event: kubernetesEventLogDetail{
+			Reason:         event.Reason,
+			Type:           event.Type,
+			Count:          event.Count,
+			Component:      event.Source.Component,
+			FirstTimestamp: unixMillis(event.FirstTimestamp.Time),
+			LastTimestamp:  unixMillis(event.LastTimestamp.Time),
+			InvolvedObject: kubernetesEventLogObject{
+				Kind:      event.InvolvedObject.Kind,
+				Name:      event.InvolvedObject.Name,
+				Namespace: event.InvolvedObject.Namespace,
+				UID:       string(event.InvolvedObject.UID),
+				FieldPath: event.InvolvedObject.FieldPath,
+			},
+		},
+	})
+}
+
+// eventLogTimestamp returns the last time the event occurred, in milliseconds
+func eventLogTimestamp(event *v1.Event) int64 {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return unixMillis(event.LastTimestamp.Time)
+	case !event.EventTime.IsZero():
+		return unixMillis(event.EventTime.Time)
+	case !event.FirstTimestamp.IsZero():
+		return unixMillis(event.FirstTimestamp.Time)
+	default:
+		return unixMillis(time.Now())
+	}
+}
+
+func unixMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatEventLog(t *testing.T) {
+	ev := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+
+	eventLog, err := formatEventLog(ev, "laika")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"message": "Back-off restarting failed container",
+		"status": "warning",
+		"timestamp": 709662600000,
+		"hostname": "machine-blue-laika",
+		"ddsource": "kubernetes",
+		"service": "kubelet",
+		"ddtags": "source_component:kubelet,kubernetes_kind:Pod,name:dca-789976f5d7-2ljx6,reason:BackOff,pod_name:dca-789976f5d7-2ljx6,kube_namespace:default,kube_cluster_name:laika",
+		"kubernetes_event": {
+			"reason": "BackOff",
+			"type": "Warning",
+			"count": 4,
+			"source_component": "kubelet",
+			"first_timestamp": 709662600000,
+			"last_timestamp": 709662600000,
+			"involved_object": {
+				"kind": "Pod",
+				"name": "dca-789976f5d7-2ljx6",
+				"namespace": "default",
+				"uid": "e6417a7f-f566-11e7-9749-0e4863e1cbf4"
+			}
+		}
+	}`, string(eventLog))
+
+	// the hostname is only set for pod and node events
+	ev = createEvent(1, "default", "dca", "Deployment", "e6417a7f-f566-11e7-9749-0e4863e1cbf5", "deployment-controller", "machine-blue", "ScalingReplicaSet", "Scaled up replica set dca-789976f5d7 to 1", "Normal", 709662600)
+	eventLog, err = formatEventLog(ev, "")
+	require.NoError(t, err)
+	assert.NotContains(t, string(eventLog), "hostname")
+	assert.Contains(t, string(eventLog), `"status":"info"`)
+
+	_, err = formatEventLog(createEvent(1, "default", "dca", "", "", "", "", "", "", "", 0), "")
+	assert.Error(t, err)
+}
@@ -114,6 +114,7 @@ type OrchestratorCheck struct {
 	persistentVolumeListerSync      cache.InformerSynced
 	persistentVolumeClaimLister     corelisters.PersistentVolumeClaimLister
 	persistentVolumeClaimListerSync cache.InformerSynced
+	manifestListers                 []manifestLister
 }
 
 func newOrchestratorCheck(base core.CheckBase, instance *OrchestratorInstance) *OrchestratorCheck {
@@ -255,6 +256,54 @@ func (o *OrchestratorCheck) Configure(config, initConfig integration.Data, sourc
 			o.persistentVolumeClaimLister = persistentVolumeClaimInformer.Lister()
 			o.persistentVolumeClaimListerSync = persistentVolumeClaimInformer.Informer().HasSynced
 			informersToSync[apiserver.InformerName(orchestrator.K8sPersistentVolumeClaim.String())] = persistentVolumeClaimInformer.Informer()
+		case "ingresses":
+			ingressInformer := apiCl.InformerFactory.Networking().V1().Ingresses()
+			o.addManifestLister(orchestrator.K8sIngress, func(selector labels.Selector) (interface{}, error) {
+				return ingressInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sIngress.String())] = ingressInformer.Informer()
+		case "namespaces":
+			namespaceInformer := apiCl.InformerFactory.Core().V1().Namespaces()
+			o.addManifestLister(orchestrator.K8sNamespace, func(selector labels.Selector) (interface{}, error) {
+				return namespaceInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sNamespace.String())] = namespaceInformer.Informer()
+		case "roles":
+			roleInformer := apiCl.InformerFactory.Rbac().V1().Roles()
+			o.addManifestLister(orchestrator.K8sRole, func(selector labels.Selector) (interface{}, error) {
+				return roleInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sRole.String())] = roleInformer.Informer()
+		case "clusterroles":
+			clusterRoleInformer := apiCl.InformerFactory.Rbac().V1().ClusterRoles()
+			o.addManifestLister(orchestrator.K8sClusterRole, func(selector labels.Selector) (interface{}, error) {
+				return clusterRoleInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sClusterRole.String())] = clusterRoleInformer.Informer()
+		case "rolebindings":
+			roleBindingInformer := apiCl.InformerFactory.Rbac().V1().RoleBindings()
+			o.addManifestLister(orchestrator.K8sRoleBinding, func(selector labels.Selector) (interface{}, error) {
+				return roleBindingInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sRoleBinding.String())] = roleBindingInformer.Informer()
+		case "clusterrolebindings":
+			clusterRoleBindingInformer := apiCl.InformerFactory.Rbac().V1().ClusterRoleBindings()
+			o.addManifestLister(orchestrator.K8sClusterRoleBinding, func(selector labels.Selector) (interface{}, error) {
+				return clusterRoleBindingInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sClusterRoleBinding.String())] = clusterRoleBindingInformer.Informer()
+		case "serviceaccounts":
+			serviceAccountInformer := apiCl.InformerFactory.Core().V1().ServiceAccounts()
+			o.addManifestLister(orchestrator.K8sServiceAccount, func(selector labels.Selector) (interface{}, error) {
+				return serviceAccountInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sServiceAccount.String())] = serviceAccountInformer.Informer()
+		case "horizontalpodautoscalers":
+			hpaInformer := apiCl.InformerFactory.Autoscaling().V2beta1().HorizontalPodAutoscalers()
+			o.addManifestLister(orchestrator.K8sHorizontalPodAutoscaler, func(selector labels.Selector) (interface{}, error) {
+				return hpaInformer.Lister().List(selector)
+			})
+			informersToSync[apiserver.InformerName(orchestrator.K8sHorizontalPodAutoscaler.String())] = hpaInformer.Informer()
 
 		default:
 			_ = o.Warnf("Unsupported collector: %s", v)
@@ -310,6 +359,9 @@ func (o *OrchestratorCheck) Run() error {
 	o.processStatefulSets(sender)
 	o.processPersistentVolume(sender)
 	o.processPersistentVolumeClaim(sender)
+	for _, lister := range o.manifestListers {
+		o.processManifests(sender, lister)
+	}
 
 	return nil
 }
@@ -635,6 +687,49 @@ func (o *OrchestratorCheck) processPersistentVolumeClaim(sender aggregator.Sende
 	sender.OrchestratorMetadata(messages, o.clusterID, int(orchestrator.K8sPersistentVolumeClaim))
 }
 
+// manifestLister lists the resources of a type that is only sent as manifests
+type manifestLister struct {
+	nodeType orchestrator.NodeType
+	list     func(selector labels.Selector) (interface{}, error)
+}
+
+func (o *OrchestratorCheck) addManifestLister(nodeType orchestrator.NodeType, list func(selector labels.Selector) (interface{}, error)) {
+	o.manifestListers = append(o.manifestListers, manifestLister{nodeType: nodeType, list: list})
+}
+
+func (o *OrchestratorCheck) processManifests(sender aggregator.Sender, lister manifestLister) {
+	list, err := lister.list(labels.Everything())
+	if err != nil {
+		_ = o.Warnf("Unable to list %s resources: %s", lister.nodeType, err)
+		return
+	}
+	objects, err := toObjects(list)
+	if err != nil {
+		_ = o.Warnf("Unable to process %s list: %s", lister.nodeType, err)
+		return
+	}
+	groupID := atomic.AddInt32(&o.groupID, 1)
+
+	messages, err := processManifestList(lister.nodeType, objects, groupID, o.orchestratorConfig, o.clusterID)
+	if err != nil {
+		_ = o.Warnf("Unable to process %s list: %s", lister.nodeType, err)
+	}
+
+	sendManifestMetadata(sender, lister.nodeType, len(objects), messages, o.clusterID)
+}
+
+func sendManifestMetadata(sender aggregator.Sender, nodeType orchestrator.NodeType, listLen int, messages []model.MessageBody, clusterID string) {
+	stats := orchestrator.CheckStats{
+		CacheHits: listLen - len(messages),
+		CacheMiss: len(messages),
+		NodeType:  nodeType,
+	}
+
+	orchestrator.KubernetesResourceCache.Set(orchestrator.BuildStatsKey(nodeType), stats, orchestrator.NoExpiration)
+
+	sender.OrchestratorMetadata(messages, clusterID, int(nodeType))
+}
+
 // Cancel cancels the orchestrator check
 func (o *OrchestratorCheck) Cancel() {
 	log.Infof("Shutting down informers used by the check '%s'", o.ID())
@@ -10,6 +10,7 @@ package orchestrator
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 )
@@ -751,3 +753,124 @@ func chunkPersistentVolumeClaims(pvcs []*model.PersistentVolumeClaim, chunkCount
 
 	return chunks
 }
+
+// The following resources have no dedicated message type in the orchestrator
+// payload, they are sent as manifests.
+
+// processManifestList redacts the given resources and chunks their manifests into messages.
+func processManifestList(nodeType orchestrator.NodeType, objects []metav1.Object, groupID int32, cfg *config.OrchestratorConfig, clusterID string) ([]model.MessageBody, error) {
+	start := time.Now()
+	manifests := make([]*model.Manifest, 0, len(objects))
+
+	for _, obj := range objects {
+		if orchestrator.SkipKubernetesResource(obj.GetUID(), obj.GetResourceVersion(), nodeType) {
+			continue
+		}
+
+		manifest, err := extractManifest(nodeType, redactManifest(obj, cfg))
+		if err != nil {
+			log.Warnf("Could not marshal %s to JSON: %s", nodeType, err)
+			continue
+		}
+
+		manifests = append(manifests, manifest)
+	}
+
+	groupSize := orchestrator.GroupSize(len(manifests), cfg.MaxPerMessage)
+
+	chunks := chunkManifests(manifests, groupSize, cfg.MaxPerMessage)
+	messages := make([]model.MessageBody, 0, groupSize)
+
+	for i := 0; i < groupSize; i++ {
+		messages = append(messages, &model.CollectorManifest{
+			ClusterName: cfg.KubeClusterName,
+			ClusterId:   clusterID,
+			GroupId:     groupID,
+			GroupSize:   int32(groupSize),
+			Manifests:   chunks[i],
+		})
+	}
+
+	log.Debugf("Collected & enriched %d out of %d %s resources in %s", len(manifests), len(objects), nodeType, time.Since(start))
+	return messages, nil
+}
+
+// toObjects converts a slice of Kubernetes resources, e.g. []*corev1.Namespace, to a slice of objects.
+func toObjects(list interface{}) ([]metav1.Object, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("expected a list of resources, got %T", list)
+	}
+
+	objects := make([]metav1.Object, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		obj, ok := value.Index(i).Interface().(metav1.Object)
+		if !ok {
+			return nil, fmt.Errorf("expected a list of resources, got %T", list)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// redactManifest returns a copy of the resource without the last applied configuration annotation,
+// and with the env vars and commands of its containers scrubbed when scrubbing is enabled.
+// The resource itself is left untouched, as it belongs to the informer cache.
+func redactManifest(obj metav1.Object, cfg *config.OrchestratorConfig) metav1.Object {
+	if runtimeObj, ok := obj.(runtime.Object); ok {
+		if copied, ok := runtimeObj.DeepCopyObject().(metav1.Object); ok {
+			obj = copied
+		}
+	}
+
+	redact.RemoveLastAppliedConfigurationAnnotation(obj.GetAnnotations())
+
+	if cfg.IsScrubbingEnabled {
+		if spec := podSpec(obj); spec != nil {
+			for c := 0; c < len(spec.InitContainers); c++ {
+				redact.ScrubContainer(&spec.InitContainers[c], cfg.Scrubber)
+			}
+			for c := 0; c < len(spec.Containers); c++ {
+				redact.ScrubContainer(&spec.Containers[c], cfg.Scrubber)
+			}
+		}
+	}
+
+	return obj
+}
+
+// podSpec returns the pod spec embedded in a resource, or nil if it has none.
+func podSpec(obj metav1.Object) *corev1.PodSpec {
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		return &o.Spec
+	case *corev1.PodTemplate:
+		return &o.Template.Spec
+	case *v1.Deployment:
+		return &o.Spec.Template.Spec
+	case *v1.ReplicaSet:
+		return &o.Spec.Template.Spec
+	case *v1.DaemonSet:
+		return &o.Spec.Template.Spec
+	case *v1.StatefulSet:
+		return &o.Spec.Template.Spec
+	case *batchv1.Job:
+		return &o.Spec.Template.Spec
+	case *batchv1beta1.CronJob:
+		return &o.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return nil
+}
+
+// chunkManifests chunks the given list of manifests, honoring the given chunk count and size.
+// The last chunk may be smaller than the others.
+func chunkManifests(manifests []*model.Manifest, chunkCount, chunkSize int) [][]*model.Manifest {
+	chunks := make([][]*model.Manifest, 0, chunkCount)
+
+	for counter := 1; counter <= chunkCount; counter++ {
+		chunkStart, chunkEnd := orchestrator.ChunkRange(len(manifests), chunkCount, chunkSize, counter)
+		chunks = append(chunks, manifests[chunkStart:chunkEnd])
+	}
+
+	return chunks
+}
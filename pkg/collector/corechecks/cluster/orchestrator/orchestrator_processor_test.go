@@ -11,7 +11,18 @@ import (
 	"testing"
 
 	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/config"
+
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestChunkDeployments(t *testing.T) {
@@ -132,6 +143,143 @@ func TestChunkReplicasets(t *testing.T) {
 	assert.ElementsMatch(t, expected, actual)
 }
 
+func TestChunkManifests(t *testing.T) {
+	manifests := []*model.Manifest{
+		{Uid: "1"},
+		{Uid: "2"},
+		{Uid: "3"},
+		{Uid: "4"},
+		{Uid: "5"},
+	}
+	expected := [][]*model.Manifest{
+		{{Uid: "1"}, {Uid: "2"}},
+		{{Uid: "3"}, {Uid: "4"}},
+		{{Uid: "5"}},
+	}
+	actual := chunkManifests(manifests, 3, 2)
+	assert.ElementsMatch(t, expected, actual)
+}
+
+func TestProcessManifestList(t *testing.T) {
+	meta := func(uid string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: "resource-" + uid, UID: types.UID(uid), ResourceVersion: "1"}
+	}
+	tests := []struct {
+		nodeType orchestrator.NodeType
+		list     interface{}
+	}{
+		{orchestrator.K8sIngress, []*netv1.Ingress{{ObjectMeta: meta("ingress-1")}, {ObjectMeta: meta("ingress-2")}}},
+		{orchestrator.K8sNamespace, []*corev1.Namespace{{ObjectMeta: meta("namespace-1")}, {ObjectMeta: meta("namespace-2")}}},
+		{orchestrator.K8sRole, []*rbacv1.Role{{ObjectMeta: meta("role-1")}, {ObjectMeta: meta("role-2")}}},
+		{orchestrator.K8sClusterRole, []*rbacv1.ClusterRole{{ObjectMeta: meta("clusterrole-1")}, {ObjectMeta: meta("clusterrole-2")}}},
+		{orchestrator.K8sRoleBinding, []*rbacv1.RoleBinding{{ObjectMeta: meta("rolebinding-1")}, {ObjectMeta: meta("rolebinding-2")}}},
+		{orchestrator.K8sClusterRoleBinding, []*rbacv1.ClusterRoleBinding{{ObjectMeta: meta("clusterrolebinding-1")}, {ObjectMeta: meta("clusterrolebinding-2")}}},
+		{orchestrator.K8sServiceAccount, []*corev1.ServiceAccount{{ObjectMeta: meta("serviceaccount-1")}, {ObjectMeta: meta("serviceaccount-2")}}},
+		{orchestrator.K8sHorizontalPodAutoscaler, []*autoscalingv2beta1.HorizontalPodAutoscaler{{ObjectMeta: meta("hpa-1")}, {ObjectMeta: meta("hpa-2")}}},
+	}
+
+	cfg := config.NewDefaultOrchestratorConfig()
+	cfg.MaxPerMessage = 1
+	cfg.KubeClusterName = "test-cluster"
+
+	for _, tt := range tests {
+		t.Run(tt.nodeType.String(), func(t *testing.T) {
+			objects, err := toObjects(tt.list)
+			require.NoError(t, err)
+
+			messages, err := processManifestList(tt.nodeType, objects, 3, cfg, "cluster-id")
+			require.NoError(t, err)
+			require.Len(t, messages, 2)
+
+			for i, message := range messages {
+				collectorManifest, ok := message.(*model.CollectorManifest)
+				require.True(t, ok)
+				assert.Equal(t, "test-cluster", collectorManifest.ClusterName)
+				assert.Equal(t, "cluster-id", collectorManifest.ClusterId)
+				assert.EqualValues(t, 3, collectorManifest.GroupId)
+				assert.EqualValues(t, 2, collectorManifest.GroupSize)
+				require.Len(t, collectorManifest.Manifests, 1)
+
+				manifest := collectorManifest.Manifests[0]
+				assert.Equal(t, tt.nodeType.String(), manifest.Type)
+				assert.Equal(t, string(objects[i].GetUID()), manifest.Uid)
+				assert.Equal(t, "json", manifest.ContentType)
+				assert.Contains(t, string(manifest.Content), objects[i].GetName())
+			}
+
+			// unchanged resources are skipped on the next run
+			messages, err = processManifestList(tt.nodeType, objects, 4, cfg, "cluster-id")
+			require.NoError(t, err)
+			assert.Empty(t, messages)
+		})
+	}
+}
+
+func TestToObjects(t *testing.T) {
+	objects, err := toObjects([]*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "default"}}})
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "default", objects[0].GetName())
+
+	_, err = toObjects([]string{"default"})
+	assert.Error(t, err)
+
+	_, err = toObjects(&corev1.Namespace{})
+	assert.Error(t, err)
+}
+
+func TestRedactManifest(t *testing.T) {
+	container := corev1.Container{
+		Name:    "app",
+		Command: []string{"app", "--password", "hunter2"},
+		Env: []corev1.EnvVar{
+			{Name: "DB_PASSWORD", Value: "hunter2"},
+			{Name: "DB_HOST", Value: "db"},
+		},
+	}
+	deploy := &v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "app",
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": `{"env":[{"name":"DB_PASSWORD","value":"hunter2"}]}`,
+			},
+		},
+		Spec: v1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{*container.DeepCopy()},
+					Containers:     []corev1.Container{*container.DeepCopy()},
+				},
+			},
+		},
+	}
+
+	cfg := config.NewDefaultOrchestratorConfig()
+	cfg.IsScrubbingEnabled = true
+
+	redacted, ok := redactManifest(deploy, cfg).(*v1.Deployment)
+	require.True(t, ok)
+	assert.Equal(t, "-", redacted.Annotations["kubectl.kubernetes.io/last-applied-configuration"])
+	for _, c := range append(redacted.Spec.Template.Spec.InitContainers, redacted.Spec.Template.Spec.Containers...) {
+		assert.Equal(t, []corev1.EnvVar{{Name: "DB_PASSWORD", Value: "********"}, {Name: "DB_HOST", Value: "db"}}, c.Env)
+		assert.Equal(t, []string{"app", "--password", "********"}, c.Command)
+	}
+
+	// the resource from the informer cache is left untouched
+	assert.Contains(t, deploy.Annotations["kubectl.kubernetes.io/last-applied-configuration"], "hunter2")
+	assert.Equal(t, container, deploy.Spec.Template.Spec.Containers[0])
+
+	manifest, err := extractManifest(orchestrator.K8sDeployment, redactManifest(deploy, cfg))
+	require.NoError(t, err)
+	assert.NotContains(t, string(manifest.Content), "hunter2")
+
+	// containers are only scrubbed when scrubbing is enabled
+	cfg.IsScrubbingEnabled = false
+	redacted = redactManifest(deploy, cfg).(*v1.Deployment)
+	assert.Equal(t, "-", redacted.Annotations["kubectl.kubernetes.io/last-applied-configuration"])
+	assert.Equal(t, container, redacted.Spec.Template.Spec.Containers[0])
+}
+
 func TestConvertNodeStatusToTags(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -8,10 +8,12 @@
 package orchestrator
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
 	orchutil "github.com/DataDog/datadog-agent/pkg/util/orchestrator"
 
 	v1 "k8s.io/api/apps/v1"
@@ -762,3 +764,28 @@ func findNodeRoles(nodeLabels map[string]string) []string {
 	}
 	return roles.List()
 }
+
+// manifestVersion is the version of the manifest format
+const manifestVersion = "v1"
+
+// extractManifest returns the manifest of a resource which has no dedicated
+// message type in the orchestrator payload. The whole resource is sent as JSON.
+func extractManifest(nodeType orchestrator.NodeType, obj metav1.Object) (*model.Manifest, error) {
+	// k8s objects only have json "omitempty" annotations
+	// and marshalling is more performant than YAML.
+	// encoding/json is used as jsoniter panics on some of the map fields of the
+	// resources sent as manifests, such as ingresses.
+	content, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Manifest{
+		Orchestrator: nodeType.Orchestrator(),
+		Type:         nodeType.String(),
+		Uid:          string(obj.GetUID()),
+		Content:      content,
+		ContentType:  "json",
+		Version:      manifestVersion,
+	}, nil
+}
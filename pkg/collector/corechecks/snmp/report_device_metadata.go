@@ -4,6 +4,7 @@ import (
 	json "encoding/json"
 	"fmt"
 	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	snmpinterfaces "github.com/DataDog/datadog-agent/pkg/snmp/interfaces"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"strconv"
@@ -25,6 +26,7 @@ func (ms *metricSender) reportNetworkDeviceMetadata(config snmpConfig, store *re
 	if err != nil {
 		log.Debugf("Unable to build interfaces metadata: %s", err)
 	}
+	storeInterfaceNames(config.ipAddress, interfaces)
 
 	metadataPayloads := batchPayloads(config.subnet, collectTime, metadata.PayloadMetadataBatchSize, device, interfaces)
 
@@ -101,6 +103,19 @@ func buildNetworkInterfacesMetadata(deviceID string, store *resultValueStore) ([
 	return interfaces, err
 }
 
+// storeInterfaceNames makes the names of the interfaces of a device available to the
+// other components of the agent, such as the NetFlow collector
+func storeInterfaceNames(ipAddress string, interfaces []metadata.InterfaceMetadata) {
+	if len(interfaces) == 0 {
+		return
+	}
+	names := make(map[int32]string, len(interfaces))
+	for _, networkInterface := range interfaces {
+		names[networkInterface.Index] = networkInterface.Name
+	}
+	snmpinterfaces.DefaultStore.SetDeviceInterfaces(ipAddress, names)
+}
+
 func batchPayloads(subnet string, collectTime time.Time, batchSize int, device metadata.DeviceMetadata, interfaces []metadata.InterfaceMetadata) []metadata.NetworkDevicesMetadata {
 	var payloads []metadata.NetworkDevicesMetadata
 	var resourceCount int
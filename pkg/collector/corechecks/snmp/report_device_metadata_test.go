@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/metadata"
+	snmpinterfaces "github.com/DataDog/datadog-agent/pkg/snmp/interfaces"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/cihub/seelog"
 	"github.com/stretchr/testify/assert"
@@ -171,6 +172,10 @@ func Test_metricSender_reportNetworkDeviceMetadata_withInterfaces(t *testing.T)
 	assert.NoError(t, err)
 
 	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+
+	name, ok := snmpinterfaces.DefaultStore.GetInterfaceName("1.2.3.4", 2)
+	assert.True(t, ok)
+	assert.Equal(t, "22", name)
 }
 
 func Test_batchPayloads(t *testing.T) {
@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package pressure
+
+import (
+	"errors"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/providers/cgroup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const checkName = "pressure"
+
+// pressureResources are the resources PSI is reported for, both in /proc/pressure and in cgroups
+var pressureResources = []string{"cpu", "memory", "io"}
+
+// For testing
+var (
+	getContainersPressure = cgroup.GetContainersPressure
+	hostPressureDir       = func() string {
+		return filepath.Join(config.Datadog.GetString("container_proc_root"), "pressure")
+	}
+)
+
+type pressureInstanceConfig struct {
+	CollectContainers bool `yaml:"collect_containers"`
+}
+
+// Check reports the pressure stall information (PSI) of the host and of the containers
+type Check struct {
+	core.CheckBase
+	instance pressureInstanceConfig
+}
+
+// Configure parses the check configuration and init the check
+func (c *Check) Configure(rawInstance integration.Data, rawInitConfig integration.Data, source string) error {
+	if err := c.CommonConfigure(rawInstance, source); err != nil {
+		return err
+	}
+
+	c.instance.CollectContainers = true
+	return yaml.Unmarshal(rawInstance, &c.instance)
+}
+
+// Run executes the check
+func (c *Check) Run() error {
+	sender, err := aggregator.GetSender(c.ID())
+	if err != nil {
+		return err
+	}
+
+	var reported bool
+	for _, resource := range pressureResources {
+		stats, err := cgroup.ParsePressureFile(filepath.Join(hostPressureDir(), resource))
+		if err != nil {
+			log.Warnf("pressure.Check: could not read %s pressure: %s", resource, err)
+			continue
+		}
+		if stats != nil {
+			submitPressure(sender, "system.pressure."+resource, stats, nil)
+			reported = true
+		}
+	}
+
+	if c.instance.CollectContainers {
+		c.submitContainersPressure(sender)
+	}
+
+	sender.Commit()
+
+	if !reported {
+		return errors.New("pressure stall information is not available, it requires Linux 4.20+ built with CONFIG_PSI")
+	}
+	return nil
+}
+
+func (c *Check) submitContainersPressure(sender aggregator.Sender) {
+	pressures, err := getContainersPressure()
+	if err == cgroup.ErrPressureNotAvailable {
+		log.Debugf("pressure.Check: %s, not reporting container pressure", err)
+		return
+	} else if err != nil {
+		log.Warnf("pressure.Check: could not get container pressure: %s", err)
+		return
+	}
+
+	for containerID, pressure := range pressures {
+		tags, err := tagger.Tag(containers.BuildTaggerEntityName(containerID), collectors.HighCardinality)
+		if err != nil {
+			log.Debugf("pressure.Check: could not collect tags for container %s: %s", containerID, err)
+		}
+
+		for resource, stats := range map[string]*metrics.PressureStats{
+			"cpu":    pressure.CPU,
+			"memory": pressure.Memory,
+			"io":     pressure.IO,
+		} {
+			if stats != nil {
+				submitPressure(sender, "container.pressure."+resource, stats, tags)
+			}
+		}
+	}
+}
+
+// submitPressure reports the averages of the some and full lines as gauges, in percentage
+// of time stalled, and the total stall time as a rate, in microseconds per second
+func submitPressure(sender aggregator.Sender, prefix string, stats *metrics.PressureStats, tags []string) {
+	for _, l := range []struct {
+		name string
+		line *metrics.PressureLine
+	}{
+		{"some", stats.Some},
+		{"full", stats.Full},
+	} {
+		if l.line == nil {
+			continue
+		}
+		name := prefix + "." + l.name
+		sender.Gauge(name+".avg10", l.line.Avg10, "", tags)
+		sender.Gauge(name+".avg60", l.line.Avg60, "", tags)
+		sender.Gauge(name+".avg300", l.line.Avg300, "", tags)
+		sender.Rate(name+".total", float64(l.line.Total), "", tags)
+	}
+}
+
+func pressureFactory() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(checkName),
+	}
+}
+
+func init() {
+	core.RegisterCheck(checkName, pressureFactory)
+}
@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package pressure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/local"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/providers/cgroup"
+)
+
+const testContainerID = "3e1a2b1f6f0e05b1c2a5d8a0f6b62f3e7e9a7c8d9f0a1b2c3d4e5f60718293a4"
+
+func TestPressureCheck(t *testing.T) {
+	hostPressureDir = func() string { return "./testdata/pressure" }
+	getContainersPressure = func() (map[string]*metrics.ContainerPressureStats, error) {
+		return map[string]*metrics.ContainerPressureStats{
+			testContainerID: {
+				CPU: &metrics.PressureStats{
+					Some: &metrics.PressureLine{Avg10: 2.5, Avg60: 1.25, Avg300: 0.5, Total: 42},
+				},
+			},
+		}, nil
+	}
+
+	fakeTagger := local.NewFakeTagger()
+	fakeTagger.SetTags(containers.BuildTaggerEntityName(testContainerID), "fake", []string{"image_name:redis"}, nil, []string{"container_id:" + testContainerID}, nil)
+	oldTagger := tagger.GetDefaultTagger()
+	tagger.SetDefaultTagger(fakeTagger)
+	defer tagger.SetDefaultTagger(oldTagger)
+
+	pressureCheck := pressureFactory().(*Check)
+	assert.NoError(t, pressureCheck.Configure(nil, nil, "test"))
+	assert.True(t, pressureCheck.instance.CollectContainers)
+
+	mock := mocksender.NewMockSender(pressureCheck.ID())
+	mock.SetupAcceptAll()
+
+	assert.NoError(t, pressureCheck.Run())
+
+	mock.AssertMetric(t, "Gauge", "system.pressure.cpu.some.avg10", 1.5, "", nil)
+	mock.AssertMetric(t, "Gauge", "system.pressure.cpu.some.avg300", 0.2, "", nil)
+	mock.AssertMetric(t, "Rate", "system.pressure.cpu.some.total", 3527491, "", nil)
+	mock.AssertNotCalled(t, "Gauge", "system.pressure.cpu.full.avg10", 0.0, "", []string(nil))
+	mock.AssertMetric(t, "Gauge", "system.pressure.memory.full.avg60", 0.04, "", nil)
+	mock.AssertMetric(t, "Rate", "system.pressure.io.full.total", 16450012, "", nil)
+
+	containerTags := []string{"image_name:redis", "container_id:" + testContainerID}
+	mock.AssertMetric(t, "Gauge", "container.pressure.cpu.some.avg10", 2.5, "", containerTags)
+	mock.AssertMetric(t, "Rate", "container.pressure.cpu.some.total", 42, "", containerTags)
+
+	// 3 resources with some lines, 2 with full lines, 1 container resource with a some line
+	mock.AssertNumberOfCalls(t, "Gauge", 3*6)
+	mock.AssertNumberOfCalls(t, "Rate", 6)
+	mock.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestPressureCheckContainersDisabled(t *testing.T) {
+	hostPressureDir = func() string { return "./testdata/pressure" }
+	getContainersPressure = func() (map[string]*metrics.ContainerPressureStats, error) {
+		return nil, cgroup.ErrPressureNotAvailable
+	}
+
+	pressureCheck := pressureFactory().(*Check)
+	assert.NoError(t, pressureCheck.Configure([]byte("collect_containers: false"), nil, "test"))
+	assert.False(t, pressureCheck.instance.CollectContainers)
+
+	mock := mocksender.NewMockSender(pressureCheck.ID())
+	mock.SetupAcceptAll()
+
+	assert.NoError(t, pressureCheck.Run())
+	mock.AssertNumberOfCalls(t, "Rate", 5)
+}
+
+func TestPressureCheckNotAvailable(t *testing.T) {
+	hostPressureDir = func() string { return "./testdata/missing" }
+	getContainersPressure = func() (map[string]*metrics.ContainerPressureStats, error) {
+		return nil, cgroup.ErrPressureNotAvailable
+	}
+
+	pressureCheck := pressureFactory().(*Check)
+	assert.NoError(t, pressureCheck.Configure(nil, nil, "test"))
+
+	mock := mocksender.NewMockSender(pressureCheck.ID())
+	mock.SetupAcceptAll()
+
+	assert.Error(t, pressureCheck.Run())
+	mock.AssertNumberOfCalls(t, "Gauge", 0)
+}
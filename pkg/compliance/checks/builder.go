@@ -561,7 +561,13 @@ func (b *builder) nodeLabelKeys() []string {
 }
 
 func (b *builder) newCheck(meta *compliance.SuiteMeta, ruleScope compliance.RuleScope, rule *compliance.Rule, handler resourceReporter) (compliance.Check, error) {
-	checkable, err := newResourceCheckList(b, rule.ID, rule.Resources)
+	var checkable checkable
+	var err error
+	if rule.IsRego() {
+		checkable, err = newRegoCheck(b, rule)
+	} else {
+		checkable, err = newResourceCheckList(b, rule.ID, rule.Resources)
+	}
 
 	if err != nil {
 		return nil, err
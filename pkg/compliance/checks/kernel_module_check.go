@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procModulesPath = "/proc/modules"
+
+// modprobeConfigDirs lists the modprobe configuration directories, see modprobe.d(5)
+var modprobeConfigDirs = []string{
+	"/etc/modprobe.d",
+	"/run/modprobe.d",
+	"/usr/local/lib/modprobe.d",
+	"/usr/lib/modprobe.d",
+	"/lib/modprobe.d",
+}
+
+var kernelModuleReportedFields = []string{
+	compliance.KernelModuleFieldName,
+	compliance.KernelModuleFieldLoaded,
+	compliance.KernelModuleFieldBlacklisted,
+	compliance.KernelModuleFieldDisabled,
+}
+
+func resolveKernelModule(_ context.Context, e env.Env, id string, res compliance.Resource) (resolved, error) {
+	if res.KernelModule == nil {
+		return nil, fmt.Errorf("%s: expecting kernel_module resource in kernel_module check", id)
+	}
+
+	module := res.KernelModule
+	if err := module.Validate(); err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	// module names are reported with underscores by the kernel, dashes and underscores are interchangeable
+	name := strings.ReplaceAll(module.Name, "-", "_")
+
+	log.Debugf("%s: running kernel_module check: %s", id, name)
+
+	loaded, err := isKernelModuleLoaded(e.NormalizeToHostRoot(procModulesPath), name)
+	if err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	config := &modprobeConfig{module: name}
+	for _, dir := range modprobeConfigDirs {
+		files, err := filepath.Glob(filepath.Join(e.NormalizeToHostRoot(dir), "*.conf"))
+		if err != nil {
+			return nil, wrapErrorWithID(id, err)
+		}
+
+		for _, file := range files {
+			if err := config.parseFile(file); err != nil {
+				log.Debugf("%s: failed to read modprobe configuration %s: %v", id, file, err)
+			}
+		}
+	}
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.KernelModuleFieldName:        module.Name,
+			compliance.KernelModuleFieldLoaded:      loaded,
+			compliance.KernelModuleFieldBlacklisted: config.blacklisted,
+			compliance.KernelModuleFieldDisabled:    config.disabled,
+		},
+		nil,
+	)
+
+	return newResolvedInstance(instance, module.Name, "kernel_module"), nil
+}
+
+func isKernelModuleLoaded(path string, name string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	prefix := []byte(name + " ")
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if bytes.HasPrefix(scanner.Bytes(), prefix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+type modprobeConfig struct {
+	module      string
+	blacklisted bool
+	disabled    bool
+}
+
+func (c *modprobeConfig) parseFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.parse(f)
+}
+
+// parse looks for the blacklist and install commands of the module. A module is considered as disabled
+// when its install command is replaced by /bin/true or /bin/false, preventing it from being loaded.
+func (c *modprobeConfig) parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if strings.ReplaceAll(fields[1], "-", "_") != c.module {
+			continue
+		}
+
+		switch fields[0] {
+		case "blacklist":
+			c.blacklisted = true
+		case "install":
+			if len(fields) > 2 {
+				switch filepath.Base(fields[2]) {
+				case "true", "false":
+					c.disabled = true
+				}
+			}
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestKernelModuleCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource compliance.Resource
+
+		expectReport *compliance.Report
+	}{
+		{
+			name: "module disabled and not loaded",
+			resource: compliance.Resource{
+				KernelModule: &compliance.KernelModule{
+					Name: "cramfs",
+				},
+				Condition: `!kernel_module.loaded && kernel_module.disabled`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"kernel_module.name":        "cramfs",
+					"kernel_module.loaded":      false,
+					"kernel_module.blacklisted": false,
+					"kernel_module.disabled":    true,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "cramfs",
+					Type: "kernel_module",
+				},
+			},
+		},
+		{
+			name: "module blacklisted but loaded",
+			resource: compliance.Resource{
+				KernelModule: &compliance.KernelModule{
+					Name: "usb-storage",
+				},
+				Condition: `!kernel_module.loaded`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"kernel_module.name":        "usb-storage",
+					"kernel_module.loaded":      true,
+					"kernel_module.blacklisted": true,
+					"kernel_module.disabled":    false,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "usb-storage",
+					Type: "kernel_module",
+				},
+			},
+		},
+		{
+			name: "module commented out",
+			resource: compliance.Resource{
+				KernelModule: &compliance.KernelModule{
+					Name: "udf",
+				},
+				Condition: `kernel_module.disabled`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"kernel_module.name":        "udf",
+					"kernel_module.loaded":      false,
+					"kernel_module.blacklisted": false,
+					"kernel_module.disabled":    false,
+				},
+				Resource: compliance.ReportResource{
+					ID:   "udf",
+					Type: "kernel_module",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(normalizeToTestdata("./testdata/kernel_module"))
+
+			kernelModuleCheck, err := newResourceCheck(env, "rule-id", test.resource)
+			assert.NoError(err)
+
+			reports := kernelModuleCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
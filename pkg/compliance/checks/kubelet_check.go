@@ -0,0 +1,379 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/jsonquery"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	defaultKubeletProcess = "kubelet"
+
+	kubeletFeatureGatesFlag = "--feature-gates"
+	kubeletFeatureGatesKey  = "featureGates"
+
+	kubeletSourceFlag    = "flag"
+	kubeletSourceFile    = "file"
+	kubeletSourceDefault = "default"
+
+	// kubeconfigInlineData is reported instead of a path for certificates embedded in the kubeconfig
+	kubeconfigInlineData = "<inline>"
+)
+
+// ErrKubeletNotFound is returned when neither the kubelet process nor its configuration file can be found
+var ErrKubeletNotFound = errors.New("kubelet not found")
+
+var kubeletReportedFields = []string{
+	compliance.KubeletFieldConfigFile,
+	compliance.KubeletFieldKubeconfig,
+	compliance.KubeletFieldKubeconfigServer,
+	compliance.KubeletFieldKubeconfigCertificateAuthority,
+	compliance.KubeletFieldKubeconfigInsecureSkipTLSVerify,
+	compliance.KubeletFieldKubeconfigClientCertificate,
+	compliance.KubeletFieldKubeconfigClientKey,
+	compliance.KubeletFieldSettings,
+}
+
+// kubeletSetting maps a kubelet configuration file key to its command-line flag. The kubelet
+// applies different defaults whether it is started with a configuration file or not.
+type kubeletSetting struct {
+	key         string
+	flag        string
+	boolean     bool
+	fileDefault string
+	flagDefault string
+}
+
+var kubeletSettings = []kubeletSetting{
+	{key: "authentication.anonymous.enabled", flag: "--anonymous-auth", boolean: true, fileDefault: "false", flagDefault: "true"},
+	{key: "authentication.webhook.enabled", flag: "--authentication-token-webhook", boolean: true, fileDefault: "true", flagDefault: "false"},
+	{key: "authentication.x509.clientCAFile", flag: "--client-ca-file"},
+	{key: "authorization.mode", flag: "--authorization-mode", fileDefault: "Webhook", flagDefault: "AlwaysAllow"},
+	{key: "readOnlyPort", flag: "--read-only-port", fileDefault: "0", flagDefault: "10255"},
+	{key: "streamingConnectionIdleTimeout", flag: "--streaming-connection-idle-timeout", fileDefault: "4h0m0s", flagDefault: "4h0m0s"},
+	{key: "protectKernelDefaults", flag: "--protect-kernel-defaults", boolean: true, fileDefault: "false", flagDefault: "false"},
+	{key: "makeIPTablesUtilChains", flag: "--make-iptables-util-chains", boolean: true, fileDefault: "true", flagDefault: "true"},
+	{key: "eventRecordQPS", flag: "--event-qps", fileDefault: "5", flagDefault: "5"},
+	{key: "rotateCertificates", flag: "--rotate-certificates", boolean: true, fileDefault: "false", flagDefault: "false"},
+	{key: "serverTLSBootstrap", flag: "--server-tls-bootstrap", boolean: true, fileDefault: "false", flagDefault: "false"},
+	{key: "tlsCertFile", flag: "--tls-cert-file"},
+	{key: "tlsPrivateKeyFile", flag: "--tls-private-key-file"},
+	{key: "tlsCipherSuites", flag: "--tls-cipher-suites"},
+	{key: "staticPodPath", flag: "--pod-manifest-path"},
+	{key: "podPidsLimit", flag: "--pod-max-pids", fileDefault: "-1", flagDefault: "-1"},
+}
+
+type kubeletValue struct {
+	value  string
+	source string
+}
+
+// kubeletConfig holds the effective configuration of the kubelet
+type kubeletConfig struct {
+	values map[string]kubeletValue
+	// checked records the effective value of the settings evaluated by the rule
+	checked map[string]string
+}
+
+// kubeconfig holds the parts of a kubeconfig file used by the kubelet to reach the API server
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// kubeconfigSettings are the API server connection settings of the current context of a kubeconfig
+type kubeconfigSettings struct {
+	server                string
+	certificateAuthority  string
+	insecureSkipTLSVerify bool
+	clientCertificate     string
+	clientKey             string
+}
+
+func resolveKubelet(_ context.Context, e env.Env, id string, res compliance.Resource) (resolved, error) {
+	if res.Kubelet == nil {
+		return nil, fmt.Errorf("%s: expecting kubelet resource in kubelet check", id)
+	}
+
+	kubelet := res.Kubelet
+
+	processName := kubelet.Process
+	if processName == "" {
+		processName = defaultKubeletProcess
+	}
+
+	log.Debugf("%s: running kubelet check: %s", id, processName)
+
+	processes, err := getProcesses(cacheValidity)
+	if err != nil {
+		return nil, log.Errorf("%s: Unable to fetch processes: %v", id, err)
+	}
+
+	var flags map[string]string
+	if matchedProcesses := processes.findProcessesByName(processName); len(matchedProcesses) > 0 {
+		flags = parseProcessCmdLine(matchedProcesses[0].Cmdline)
+	}
+
+	configFile, hasConfigFile := flags["--config"]
+	if !hasConfigFile {
+		configFile = kubelet.ConfigFile
+	}
+
+	if flags == nil && configFile == "" {
+		return nil, ErrKubeletNotFound
+	}
+
+	var fileContent map[string]interface{}
+	if configFile != "" {
+		if fileContent, err = readKubeletConfigFile(e.NormalizeToHostRoot(configFile)); err != nil {
+			return nil, wrapErrorWithID(id, err)
+		}
+	}
+
+	config := newKubeletConfig(flags, fileContent)
+
+	kubeconfigFile := flags["--kubeconfig"]
+	var kubeconfig kubeconfigSettings
+	if kubeconfigFile != "" {
+		if kubeconfig, err = readKubeconfig(e, kubeconfigFile); err != nil {
+			// the kubeconfig settings are only reported, the kubelet configuration can still be checked
+			log.Warnf("%s: %v", id, err)
+		}
+	}
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.KubeletFieldConfigFile:                      configFile,
+			compliance.KubeletFieldKubeconfig:                      kubeconfigFile,
+			compliance.KubeletFieldKubeconfigServer:                kubeconfig.server,
+			compliance.KubeletFieldKubeconfigCertificateAuthority:  kubeconfig.certificateAuthority,
+			compliance.KubeletFieldKubeconfigInsecureSkipTLSVerify: kubeconfig.insecureSkipTLSVerify,
+			compliance.KubeletFieldKubeconfigClientCertificate:     kubeconfig.clientCertificate,
+			compliance.KubeletFieldKubeconfigClientKey:             kubeconfig.clientKey,
+			// filled while the condition is evaluated, before the report is built
+			compliance.KubeletFieldSettings: config.checked,
+		},
+		eval.FunctionMap{
+			compliance.KubeletFuncConfig: config.evalConfig,
+			compliance.KubeletFuncSource: config.evalSource,
+		},
+	)
+
+	return newResolvedInstance(instance, processName, "kubelet"), nil
+}
+
+func readKubeletConfigFile(path string) (map[string]interface{}, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var content interface{}
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("failed to parse kubelet configuration %s: %w", path, err)
+	}
+
+	// an empty file is a valid configuration
+	if content == nil {
+		return nil, nil
+	}
+
+	m, ok := jsonquery.NormalizeYAMLForGoJQ(content).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to parse kubelet configuration %s: expecting a map", path)
+	}
+	return m, nil
+}
+
+// readKubeconfig returns the API server connection settings of the current context of a kubeconfig.
+// Relative certificate paths are resolved against the directory of the kubeconfig, like kubectl does.
+func readKubeconfig(e env.Env, path string) (kubeconfigSettings, error) {
+	var settings kubeconfigSettings
+
+	data, err := ioutil.ReadFile(e.NormalizeToHostRoot(path))
+	if err != nil {
+		return settings, err
+	}
+
+	var config kubeconfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return settings, fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+	}
+
+	contextName := config.CurrentContext
+	if contextName == "" && len(config.Contexts) == 1 {
+		contextName = config.Contexts[0].Name
+	}
+
+	var clusterName, userName string
+	for _, context := range config.Contexts {
+		if context.Name == contextName {
+			clusterName, userName = context.Context.Cluster, context.Context.User
+			break
+		}
+	}
+	if clusterName == "" && userName == "" {
+		return settings, fmt.Errorf("failed to parse kubeconfig %s: context %q not found", path, contextName)
+	}
+
+	resolvePath := func(file, data string) string {
+		if file == "" {
+			if data != "" {
+				return kubeconfigInlineData
+			}
+			return ""
+		}
+		if !filepath.IsAbs(file) {
+			return filepath.Join(filepath.Dir(path), file)
+		}
+		return file
+	}
+
+	for _, cluster := range config.Clusters {
+		if cluster.Name == clusterName {
+			settings.server = cluster.Cluster.Server
+			settings.certificateAuthority = resolvePath(cluster.Cluster.CertificateAuthority, cluster.Cluster.CertificateAuthorityData)
+			settings.insecureSkipTLSVerify = cluster.Cluster.InsecureSkipTLSVerify
+			break
+		}
+	}
+
+	for _, user := range config.Users {
+		if user.Name == userName {
+			settings.clientCertificate = resolvePath(user.User.ClientCertificate, user.User.ClientCertificateData)
+			settings.clientKey = resolvePath(user.User.ClientKey, user.User.ClientKeyData)
+			break
+		}
+	}
+
+	return settings, nil
+}
+
+// newKubeletConfig merges the flags, the configuration file and the defaults, flags taking precedence
+// over the configuration file like the kubelet does
+func newKubeletConfig(flags map[string]string, fileContent map[string]interface{}) *kubeletConfig {
+	c := &kubeletConfig{
+		values:  make(map[string]kubeletValue),
+		checked: make(map[string]string),
+	}
+
+	flattenKubeletConfig("", fileContent, c.values)
+
+	for _, setting := range kubeletSettings {
+		if value, found := flags[setting.flag]; found {
+			// boolean flags may be passed without value
+			if setting.boolean && value == "" {
+				value = "true"
+			}
+			c.values[setting.key] = kubeletValue{value: value, source: kubeletSourceFlag}
+			continue
+		}
+
+		if _, found := c.values[setting.key]; found {
+			continue
+		}
+
+		value := setting.flagDefault
+		if fileContent != nil {
+			value = setting.fileDefault
+		}
+		c.values[setting.key] = kubeletValue{value: value, source: kubeletSourceDefault}
+	}
+
+	if featureGates, found := flags[kubeletFeatureGatesFlag]; found {
+		for _, gate := range strings.Split(featureGates, ",") {
+			parts := strings.SplitN(strings.TrimSpace(gate), "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			c.values[kubeletFeatureGatesKey+"."+parts[0]] = kubeletValue{value: parts[1], source: kubeletSourceFlag}
+		}
+	}
+
+	return c
+}
+
+func flattenKubeletConfig(prefix string, content map[string]interface{}, values map[string]kubeletValue) {
+	for key, value := range content {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flattenKubeletConfig(key, value, values)
+		case []interface{}:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				items = append(items, fmt.Sprintf("%v", item))
+			}
+			values[key] = kubeletValue{value: strings.Join(items, ","), source: kubeletSourceFile}
+		case nil:
+		default:
+			values[key] = kubeletValue{value: fmt.Sprintf("%v", value), source: kubeletSourceFile}
+		}
+	}
+}
+
+func (c *kubeletConfig) get(args ...interface{}) (kubeletValue, error) {
+	if len(args) != 1 {
+		return kubeletValue{}, fmt.Errorf(`invalid number of arguments, expecting 1 got %d`, len(args))
+	}
+	key, ok := args[0].(string)
+	if !ok {
+		return kubeletValue{}, errors.New(`expecting string value for key argument`)
+	}
+	value := c.values[key]
+	c.checked[key] = value.value
+	return value, nil
+}
+
+func (c *kubeletConfig) evalConfig(_ eval.Instance, args ...interface{}) (interface{}, error) {
+	value, err := c.get(args...)
+	return value.value, err
+}
+
+func (c *kubeletConfig) evalSource(_ eval.Instance, args ...interface{}) (interface{}, error) {
+	value, err := c.get(args...)
+	return value.source, err
+}
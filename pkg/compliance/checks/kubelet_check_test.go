@@ -0,0 +1,239 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+type kubeletFixture struct {
+	name     string
+	resource compliance.Resource
+
+	processes    processes
+	expectReport *compliance.Report
+	expectError  error
+}
+
+func (f *kubeletFixture) run(t *testing.T) {
+	t.Helper()
+	assert := assert.New(t)
+
+	cache.Cache.Delete(processCacheKey)
+	processFetcher = func() (processes, error) {
+		for pid, p := range f.processes {
+			p.Pid = pid
+		}
+		return f.processes, nil
+	}
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(normalizeToTestdata("./testdata/kubelet")).Maybe()
+
+	kubeletCheck, err := newResourceCheck(env, "rule-id", f.resource)
+	assert.NoError(err)
+
+	reports := kubeletCheck.check(env)
+	assert.Equal(f.expectReport, reports[0])
+	assert.Equal(f.expectError, reports[0].Error)
+}
+
+func TestKubeletCheck(t *testing.T) {
+	kubeletResource := func(condition string) compliance.Resource {
+		return compliance.Resource{
+			Kubelet:   &compliance.Kubelet{},
+			Condition: condition,
+		}
+	}
+
+	withConfigFile := processes{
+		42: {
+			Name:    "kubelet",
+			Cmdline: []string{"/usr/bin/kubelet", "--config=/var/lib/kubelet/config.yaml", "--kubeconfig=/etc/kubernetes/kubelet.conf", "--read-only-port=0"},
+		},
+	}
+
+	withoutConfigFile := processes{
+		42: {
+			Name:    "kubelet",
+			Cmdline: []string{"/usr/bin/kubelet", "--kubeconfig", "/etc/kubernetes/kubelet.conf", "--protect-kernel-defaults", "--feature-gates=RotateKubeletServerCertificate=true,DynamicKubeletConfig=false"},
+		},
+	}
+
+	kubeconfigData := func(configFile string, settings map[string]string) event.Data {
+		return event.Data{
+			"kubelet.configFile":                       configFile,
+			"kubelet.kubeconfig":                       "/etc/kubernetes/kubelet.conf",
+			"kubelet.kubeconfig.server":                "https://10.0.0.1:6443",
+			"kubelet.kubeconfig.certificateAuthority":  "<inline>",
+			"kubelet.kubeconfig.insecureSkipTLSVerify": false,
+			"kubelet.kubeconfig.clientCertificate":     "/var/lib/kubelet/pki/kubelet-client-current.pem",
+			"kubelet.kubeconfig.clientKey":             "/var/lib/kubelet/pki/kubelet-client-current.pem",
+			"kubelet.settings":                         settings,
+		}
+	}
+
+	reportWithConfigFile := func(passed bool, settings map[string]string) *compliance.Report {
+		return &compliance.Report{
+			Passed: passed,
+			Data:   kubeconfigData("/var/lib/kubelet/config.yaml", settings),
+			Resource: compliance.ReportResource{
+				ID:   "kubelet",
+				Type: "kubelet",
+			},
+		}
+	}
+
+	reportWithoutConfigFile := func(passed bool, settings map[string]string) *compliance.Report {
+		return &compliance.Report{
+			Passed: passed,
+			Data:   kubeconfigData("", settings),
+			Resource: compliance.ReportResource{
+				ID:   "kubelet",
+				Type: "kubelet",
+			},
+		}
+	}
+
+	tests := []kubeletFixture{
+		{
+			name:         "value from the configuration file",
+			resource:     kubeletResource(`kubelet.config("authentication.anonymous.enabled") == "false" && kubelet.source("authentication.anonymous.enabled") == "file"`),
+			processes:    withConfigFile,
+			expectReport: reportWithConfigFile(true, map[string]string{"authentication.anonymous.enabled": "false"}),
+		},
+		{
+			name:         "flag overriding the configuration file",
+			resource:     kubeletResource(`kubelet.config("readOnlyPort") == "0" && kubelet.source("readOnlyPort") == "flag"`),
+			processes:    withConfigFile,
+			expectReport: reportWithConfigFile(true, map[string]string{"readOnlyPort": "0"}),
+		},
+		{
+			name:         "list from the configuration file",
+			resource:     kubeletResource(`kubelet.config("tlsCipherSuites") == "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"`),
+			processes:    withConfigFile,
+			expectReport: reportWithConfigFile(true, map[string]string{"tlsCipherSuites": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}),
+		},
+		{
+			name:         "feature gate from the configuration file",
+			resource:     kubeletResource(`kubelet.config("featureGates.RotateKubeletServerCertificate") == "true"`),
+			processes:    withConfigFile,
+			expectReport: reportWithConfigFile(true, map[string]string{"featureGates.RotateKubeletServerCertificate": "true"}),
+		},
+		{
+			name:         "configuration file default",
+			resource:     kubeletResource(`kubelet.config("protectKernelDefaults") == "true"`),
+			processes:    withConfigFile,
+			expectReport: reportWithConfigFile(false, map[string]string{"protectKernelDefaults": "false"}),
+		},
+		{
+			name:         "flag default",
+			resource:     kubeletResource(`kubelet.config("authentication.anonymous.enabled") == "false"`),
+			processes:    withoutConfigFile,
+			expectReport: reportWithoutConfigFile(false, map[string]string{"authentication.anonymous.enabled": "true"}),
+		},
+		{
+			name:         "boolean flag without value",
+			resource:     kubeletResource(`kubelet.config("protectKernelDefaults") == "true" && kubelet.source("protectKernelDefaults") == "flag"`),
+			processes:    withoutConfigFile,
+			expectReport: reportWithoutConfigFile(true, map[string]string{"protectKernelDefaults": "true"}),
+		},
+		{
+			name:         "feature gate from flags",
+			resource:     kubeletResource(`kubelet.config("featureGates.RotateKubeletServerCertificate") == "true"`),
+			processes:    withoutConfigFile,
+			expectReport: reportWithoutConfigFile(true, map[string]string{"featureGates.RotateKubeletServerCertificate": "true"}),
+		},
+		{
+			name: "configuration file from the resource",
+			resource: compliance.Resource{
+				Kubelet: &compliance.Kubelet{
+					ConfigFile: "/var/lib/kubelet/config.yaml",
+				},
+				Condition: `kubelet.config("authentication.x509.clientCAFile") == "/etc/kubernetes/pki/ca.crt"`,
+			},
+			processes: processes{},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"kubelet.configFile":                       "/var/lib/kubelet/config.yaml",
+					"kubelet.kubeconfig":                       "",
+					"kubelet.kubeconfig.server":                "",
+					"kubelet.kubeconfig.certificateAuthority":  "",
+					"kubelet.kubeconfig.insecureSkipTLSVerify": false,
+					"kubelet.kubeconfig.clientCertificate":     "",
+					"kubelet.kubeconfig.clientKey":             "",
+					"kubelet.settings":                         map[string]string{"authentication.x509.clientCAFile": "/etc/kubernetes/pki/ca.crt"},
+				},
+				Resource: compliance.ReportResource{
+					ID:   "kubelet",
+					Type: "kubelet",
+				},
+			},
+		},
+		{
+			name:      "kubelet not found",
+			resource:  kubeletResource(`kubelet.config("readOnlyPort") == "0"`),
+			processes: processes{},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Error:  ErrKubeletNotFound,
+			},
+			expectError: ErrKubeletNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t)
+		})
+	}
+}
+
+func TestNewKubeletConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	config := newKubeletConfig(
+		map[string]string{"--anonymous-auth": "false"},
+		map[string]interface{}{
+			"authentication": map[string]interface{}{
+				"anonymous": map[string]interface{}{"enabled": true},
+			},
+			"eventRecordQPS": 0,
+		},
+	)
+
+	assert.Equal(kubeletValue{value: "false", source: kubeletSourceFlag}, config.values["authentication.anonymous.enabled"])
+	assert.Equal(kubeletValue{value: "0", source: kubeletSourceFile}, config.values["eventRecordQPS"])
+	assert.Equal(kubeletValue{value: "Webhook", source: kubeletSourceDefault}, config.values["authorization.mode"])
+	assert.Equal(kubeletValue{}, config.values["unknown"])
+}
+
+func TestReadKubeconfig(t *testing.T) {
+	assert := assert.New(t)
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(normalizeToTestdata("./testdata/kubelet"))
+
+	settings, err := readKubeconfig(env, "/etc/kubernetes/bootstrap-kubelet.conf")
+	assert.NoError(err)
+	assert.Equal(kubeconfigSettings{
+		server:                "https://10.0.0.1:6443",
+		certificateAuthority:  "/etc/kubernetes/pki/ca.crt",
+		insecureSkipTLSVerify: true,
+	}, settings)
+
+	_, err = readKubeconfig(env, "/etc/kubernetes/missing.conf")
+	assert.Error(err)
+}
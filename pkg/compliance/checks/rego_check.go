@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const defaultRegoFindingsQuery = "data.datadog.findings"
+
+// ErrRegoResourceNotSupported is returned when a resource kind cannot be used as Rego input
+var ErrRegoResourceNotSupported = errors.New("resource kind not supported as rego input")
+
+type regoInput struct {
+	tag      string
+	resource compliance.Resource
+	resolve  resolveFunc
+}
+
+// regoCheck evaluates a Rego module against the instances of the resources of a rule. Instances are
+// passed as input, grouped by resource tag, and the findings returned by the module are turned into reports.
+type regoCheck struct {
+	ruleID string
+	inputs []regoInput
+	query  rego.PreparedEvalQuery
+}
+
+func newRegoCheck(env env.Env, rule *compliance.Rule) (checkable, error) {
+	var inputs []regoInput
+	for _, resource := range rule.Resources {
+		kind := resource.Kind()
+		if kind == compliance.KindCustom || kind == compliance.KindInvalid {
+			return nil, fmt.Errorf("%s: %w: %s", rule.ID, ErrRegoResourceNotSupported, kind)
+		}
+
+		if err := checkResourceClient(env, rule.ID, kind); err != nil {
+			return nil, err
+		}
+
+		resolve, _, err := resourceKindToResolverAndFields(kind)
+		if err != nil {
+			return nil, log.Errorf("%s: failed to find resource resolver for resource kind: %s", rule.ID, kind)
+		}
+
+		tag := resource.Tag
+		if tag == "" {
+			tag = string(kind)
+		}
+
+		inputs = append(inputs, regoInput{
+			tag:      tag,
+			resource: resource,
+			resolve:  resolve,
+		})
+	}
+
+	findings := rule.Findings
+	if findings == "" {
+		findings = defaultRegoFindingsQuery
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	query, err := rego.New(
+		rego.Query(findings),
+		rego.Module(rule.ID+".rego", rule.Module),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to compile rego module: %w", rule.ID, err)
+	}
+
+	return &regoCheck{
+		ruleID: rule.ID,
+		inputs: inputs,
+		query:  query,
+	}, nil
+}
+
+func (c *regoCheck) check(env env.Env) []*compliance.Report {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	input := make(map[string]interface{})
+	for _, regoInput := range c.inputs {
+		list, _ := input[regoInput.tag].([]interface{})
+		if list == nil {
+			// a resource that cannot be resolved is passed as an empty list, the module decides
+			// whether a missing resource is a finding
+			list = []interface{}{}
+		}
+
+		instances, err := c.resolveInput(ctx, env, regoInput)
+		if err != nil {
+			log.Debugf("%s: passing empty input for resource %s: %v", c.ruleID, regoInput.tag, err)
+		}
+
+		input[regoInput.tag] = append(list, instances...)
+	}
+
+	results, err := c.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return []*compliance.Report{compliance.BuildReportForError(err)}
+	}
+
+	var reports []*compliance.Report
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			findings, ok := expression.Value.([]interface{})
+			if !ok {
+				return []*compliance.Report{compliance.BuildReportForError(fmt.Errorf("%s: expecting a list of findings, got %T", c.ruleID, expression.Value))}
+			}
+
+			for _, finding := range findings {
+				reports = append(reports, regoFindingToReport(finding))
+			}
+		}
+	}
+
+	log.Debugf("%s: rego module returned %d findings", c.ruleID, len(reports))
+
+	if len(reports) == 0 {
+		return []*compliance.Report{{Passed: true}}
+	}
+
+	return reports
+}
+
+func (c *regoCheck) resolveInput(ctx context.Context, env env.Env, input regoInput) ([]interface{}, error) {
+	resolved, err := input.resolve(ctx, env, c.ruleID, input.resource)
+	if err != nil {
+		return nil, err
+	}
+	return resolvedToRegoInput(resolved)
+}
+
+// resolvedToRegoInput returns the variables of the resolved instances, dotted variable names being
+// turned into nested objects
+func resolvedToRegoInput(resolved resolved) ([]interface{}, error) {
+	switch res := resolved.(type) {
+	case *_resolvedInstance:
+		return []interface{}{varsToRegoInput(res.Vars())}, nil
+	case *resolvedIterator:
+		var instances []interface{}
+		for !res.Done() {
+			instance, err := res.Next()
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, varsToRegoInput(instance.Vars()))
+		}
+		return instances, nil
+	default:
+		return nil, ErrRegoResourceNotSupported
+	}
+}
+
+func varsToRegoInput(vars eval.VarMap) map[string]interface{} {
+	input := make(map[string]interface{})
+	for name, value := range vars {
+		parts := strings.Split(name, ".")
+
+		node := input
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+	return input
+}
+
+// regoFindingToReport converts a finding, an object with a status (passed, failed or error), an optional
+// resource_id, resource_type and data, into a report
+func regoFindingToReport(finding interface{}) *compliance.Report {
+	fields, ok := finding.(map[string]interface{})
+	if !ok {
+		return compliance.BuildReportForError(fmt.Errorf("expecting finding object, got %T", finding))
+	}
+
+	report := &compliance.Report{}
+
+	status, _ := fields["status"].(string)
+	switch status {
+	case event.Passed:
+		report.Passed = true
+	case event.Failed:
+	case event.Error:
+		message, _ := fields["message"].(string)
+		if message == "" {
+			message = "rego finding reported an error"
+		}
+		report.Error = errors.New(message)
+	default:
+		return compliance.BuildReportForError(fmt.Errorf("invalid finding status `%v`", fields["status"]))
+	}
+
+	report.Resource.ID, _ = fields["resource_id"].(string)
+	report.Resource.Type, _ = fields["resource_type"].(string)
+
+	if data, ok := fields["data"].(map[string]interface{}); ok {
+		report.Data = event.Data(data)
+	}
+
+	return report
+}
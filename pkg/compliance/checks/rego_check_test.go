@@ -0,0 +1,243 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+// the processes of the members of the docker group must not run without user namespaces
+const testRegoModule = `
+package datadog
+
+docker_users := {user | user := input.docker_group[_].group.users[_]}
+
+userns_remap(p) {
+	startswith(p.process.cmdLine[_], "--userns-remap")
+}
+
+findings[f] {
+	p := input.process[_]
+	docker_users[p.process.name]
+	not userns_remap(p)
+	f := {
+		"status": "failed",
+		"resource_id": p.process.name,
+		"resource_type": "process",
+		"data": {"process.exe": p.process.exe},
+	}
+}
+
+findings[f] {
+	p := input.process[_]
+	docker_users[p.process.name]
+	userns_remap(p)
+	f := {
+		"status": "passed",
+		"resource_id": p.process.name,
+		"resource_type": "process",
+	}
+}
+`
+
+func TestRegoCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	cache.Cache.Delete(processCacheKey)
+	processFetcher = func() (processes, error) {
+		return processes{
+			42: {Name: "alice", Exe: "/usr/bin/alice", Cmdline: []string{"alice", "--userns-remap=default"}},
+			43: {Name: "bob", Exe: "/usr/bin/bob", Cmdline: []string{"bob"}},
+			44: {Name: "mallory", Exe: "/usr/bin/mallory", Cmdline: []string{"mallory"}},
+		}, nil
+	}
+
+	rule := &compliance.Rule{
+		ID: "rule-id",
+		Resources: []compliance.Resource{
+			{
+				Group: &compliance.Group{Name: "docker"},
+				Tag:   "docker_group",
+			},
+			{
+				Process: &compliance.Process{Name: "alice"},
+			},
+			{
+				Process: &compliance.Process{Name: "bob"},
+			},
+			{
+				Process: &compliance.Process{Name: "mallory"},
+			},
+		},
+		Module: testRegoModule,
+	}
+
+	env := &mocks.Env{}
+	env.On("EtcGroupPath").Return("./testdata/group/etc-group")
+
+	regoCheck, err := newRegoCheck(env, rule)
+	assert.NoError(err)
+
+	reports := regoCheck.check(env)
+	assert.Len(reports, 2)
+	assert.ElementsMatch([]*compliance.Report{
+		{
+			Passed:   true,
+			Resource: compliance.ReportResource{ID: "alice", Type: "process"},
+		},
+		{
+			Passed:   false,
+			Data:     event.Data{"process.exe": "/usr/bin/bob"},
+			Resource: compliance.ReportResource{ID: "bob", Type: "process"},
+		},
+	}, reports)
+}
+
+func TestRegoCheckUnresolvedInput(t *testing.T) {
+	assert := assert.New(t)
+
+	cache.Cache.Delete(processCacheKey)
+	processFetcher = func() (processes, error) {
+		return processes{
+			42: {Name: "alice", Exe: "/usr/bin/alice", Cmdline: []string{"alice"}},
+		}, nil
+	}
+
+	rule := &compliance.Rule{
+		ID: "rule-id",
+		Resources: []compliance.Resource{
+			{
+				Group: &compliance.Group{Name: "docker"},
+				Tag:   "docker_group",
+			},
+			{
+				Process: &compliance.Process{Name: "alice"},
+			},
+		},
+		Module: `
+package datadog
+
+findings[f] {
+	count(input.docker_group) == 0
+	f := {"status": "failed", "resource_id": "docker", "resource_type": "group"}
+}
+`,
+	}
+
+	env := &mocks.Env{}
+	env.On("EtcGroupPath").Return("./testdata/group/missing")
+
+	regoCheck, err := newRegoCheck(env, rule)
+	assert.NoError(err)
+
+	reports := regoCheck.check(env)
+	assert.Equal([]*compliance.Report{
+		{
+			Passed:   false,
+			Resource: compliance.ReportResource{ID: "docker", Type: "group"},
+		},
+	}, reports)
+}
+
+func TestRegoCheckNoFindings(t *testing.T) {
+	assert := assert.New(t)
+
+	cache.Cache.Delete(processCacheKey)
+	processFetcher = func() (processes, error) {
+		return processes{}, nil
+	}
+
+	rule := &compliance.Rule{
+		ID: "rule-id",
+		Resources: []compliance.Resource{
+			{
+				Group: &compliance.Group{Name: "docker"},
+				Tag:   "docker_group",
+			},
+			{
+				Process: &compliance.Process{Name: "alice"},
+			},
+		},
+		Module: testRegoModule,
+	}
+
+	env := &mocks.Env{}
+	env.On("EtcGroupPath").Return("./testdata/group/etc-group")
+
+	regoCheck, err := newRegoCheck(env, rule)
+	assert.NoError(err)
+
+	reports := regoCheck.check(env)
+	assert.Equal([]*compliance.Report{{Passed: true}}, reports)
+}
+
+func TestRegoCheckInvalidModule(t *testing.T) {
+	rule := &compliance.Rule{
+		ID: "rule-id",
+		Resources: []compliance.Resource{
+			{Process: &compliance.Process{Name: "dockerd"}},
+		},
+		Module: "package datadog\nfindings[f] {",
+	}
+
+	_, err := newRegoCheck(&mocks.Env{}, rule)
+	assert.Error(t, err)
+}
+
+func TestRegoCheckCustomResource(t *testing.T) {
+	rule := &compliance.Rule{
+		ID: "rule-id",
+		Resources: []compliance.Resource{
+			{Custom: &compliance.Custom{Name: "check"}},
+		},
+		Module: testRegoModule,
+	}
+
+	_, err := newRegoCheck(&mocks.Env{}, rule)
+	assert.True(t, errors.Is(err, ErrRegoResourceNotSupported))
+}
+
+func TestVarsToRegoInput(t *testing.T) {
+	input := varsToRegoInput(eval.VarMap{
+		"kube.resource.name": "admin",
+		"kube.resource.kind": "ClusterRoleBinding",
+		"file.path":          "/etc/passwd",
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"kube": map[string]interface{}{
+			"resource": map[string]interface{}{
+				"name": "admin",
+				"kind": "ClusterRoleBinding",
+			},
+		},
+		"file": map[string]interface{}{
+			"path": "/etc/passwd",
+		},
+	}, input)
+}
+
+func TestRegoFindingToReport(t *testing.T) {
+	assert := assert.New(t)
+
+	report := regoFindingToReport(map[string]interface{}{"status": "error", "message": "unable to list bindings"})
+	assert.Equal(errors.New("unable to list bindings"), report.Error)
+
+	report = regoFindingToReport(map[string]interface{}{"status": "unknown"})
+	assert.Error(report.Error)
+
+	report = regoFindingToReport("failed")
+	assert.Error(report.Error)
+}
@@ -162,18 +162,10 @@ func newResourceCheck(env env.Env, ruleID string, resource compliance.Resource)
 	switch kind {
 	case compliance.KindCustom:
 		return newCustomCheck(ruleID, resource)
-	case compliance.KindAudit:
-		if env.AuditClient() == nil {
-			return nil, log.Errorf("%s: audit client not initialized", ruleID)
-		}
-	case compliance.KindDocker:
-		if env.DockerClient() == nil {
-			return nil, log.Errorf("%s: docker client not initialized", ruleID)
-		}
-	case compliance.KindKubernetes:
-		if env.KubeClient() == nil {
-			return nil, log.Errorf("%s: kube client not initialized", ruleID)
-		}
+	}
+
+	if err := checkResourceClient(env, ruleID, kind); err != nil {
+		return nil, err
 	}
 
 	resolve, reportedFields, err := resourceKindToResolverAndFields(kind)
@@ -198,6 +190,25 @@ func newResourceCheck(env env.Env, ruleID string, resource compliance.Resource)
 	}, nil
 }
 
+// checkResourceClient returns an error if the client required by a resource kind is not initialized
+func checkResourceClient(env env.Env, ruleID string, kind compliance.ResourceKind) error {
+	switch kind {
+	case compliance.KindAudit:
+		if env.AuditClient() == nil {
+			return log.Errorf("%s: audit client not initialized", ruleID)
+		}
+	case compliance.KindDocker:
+		if env.DockerClient() == nil {
+			return log.Errorf("%s: docker client not initialized", ruleID)
+		}
+	case compliance.KindKubernetes:
+		if env.KubeClient() == nil {
+			return log.Errorf("%s: kube client not initialized", ruleID)
+		}
+	}
+	return nil
+}
+
 func resourceKindToResolverAndFields(kind compliance.ResourceKind) (resolveFunc, []string, error) {
 	switch kind {
 	case compliance.KindFile:
@@ -214,6 +225,14 @@ func resourceKindToResolverAndFields(kind compliance.ResourceKind) (resolveFunc,
 		return resolveDocker, dockerReportedFields, nil
 	case compliance.KindKubernetes:
 		return resolveKubeapiserver, kubeResourceReportedFields, nil
+	case compliance.KindSysctl:
+		return resolveSysctl, sysctlReportedFields, nil
+	case compliance.KindSystemdUnit:
+		return resolveSystemdUnit, systemdUnitReportedFields, nil
+	case compliance.KindKernelModule:
+		return resolveKernelModule, kernelModuleReportedFields, nil
+	case compliance.KindKubelet:
+		return resolveKubelet, kubeletReportedFields, nil
 	default:
 		return nil, nil, ErrResourceKindNotSupported
 	}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procSysPath = "/proc/sys"
+
+var sysctlReportedFields = []string{
+	compliance.SysctlFieldKey,
+	compliance.SysctlFieldValue,
+}
+
+// ErrSysctlNotFound is returned when a kernel parameter cannot be found
+var ErrSysctlNotFound = errors.New("sysctl not found")
+
+func resolveSysctl(_ context.Context, e env.Env, id string, res compliance.Resource) (resolved, error) {
+	if res.Sysctl == nil {
+		return nil, fmt.Errorf("%s: expecting sysctl resource in sysctl check", id)
+	}
+
+	sysctl := res.Sysctl
+	if err := sysctl.Validate(); err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	path := e.NormalizeToHostRoot(sysctlKeyToPath(sysctl.Key))
+
+	log.Debugf("%s: running sysctl check: %s (%s)", id, sysctl.Key, path)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, wrapErrorWithID(id, fmt.Errorf("%w: %s", ErrSysctlNotFound, sysctl.Key))
+		}
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.SysctlFieldKey:   sysctl.Key,
+			compliance.SysctlFieldValue: normalizeSysctlValue(string(data)),
+		},
+		nil,
+	)
+
+	return newResolvedInstance(instance, sysctl.Key, "sysctl"), nil
+}
+
+// sysctlKeyToPath returns the /proc/sys path of a kernel parameter, dots and slashes being
+// swapped like the sysctl command does
+func sysctlKeyToPath(key string) string {
+	key = strings.Map(func(r rune) rune {
+		switch r {
+		case '.':
+			return '/'
+		case '/':
+			return '.'
+		}
+		return r
+	}, key)
+
+	return filepath.Join(procSysPath, key)
+}
+
+// normalizeSysctlValue collapses the whitespaces of multi-valued parameters, so that the value matches
+// the output of the sysctl command
+func normalizeSysctlValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func normalizeToTestdata(root string) func(string) string {
+	return func(path string) string {
+		return filepath.Join(root, path)
+	}
+}
+
+func TestSysctlCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource compliance.Resource
+
+		expectReport *compliance.Report
+		expectError  error
+	}{
+		{
+			name: "ip forwarding disabled",
+			resource: compliance.Resource{
+				Sysctl: &compliance.Sysctl{
+					Key: "net.ipv4.ip_forward",
+				},
+				Condition: `sysctl.value == "0"`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"sysctl.key":   "net.ipv4.ip_forward",
+					"sysctl.value": "0",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "net.ipv4.ip_forward",
+					Type: "sysctl",
+				},
+			},
+		},
+		{
+			name: "multi-valued parameter",
+			resource: compliance.Resource{
+				Sysctl: &compliance.Sysctl{
+					Key: "net.ipv4.tcp_rmem",
+				},
+				Condition: `sysctl.value == "4096 87380 6291456"`,
+			},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"sysctl.key":   "net.ipv4.tcp_rmem",
+					"sysctl.value": "4096 87380 6291456",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "net.ipv4.tcp_rmem",
+					Type: "sysctl",
+				},
+			},
+		},
+		{
+			name: "key with a dotted interface name",
+			resource: compliance.Resource{
+				Sysctl: &compliance.Sysctl{
+					Key: "net.ipv4.conf.eth0/100.rp_filter",
+				},
+				Condition: `sysctl.value == "0"`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"sysctl.key":   "net.ipv4.conf.eth0/100.rp_filter",
+					"sysctl.value": "1",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "net.ipv4.conf.eth0/100.rp_filter",
+					Type: "sysctl",
+				},
+			},
+		},
+		{
+			name: "unknown key",
+			resource: compliance.Resource{
+				Sysctl: &compliance.Sysctl{
+					Key: "net.ipv4.unknown",
+				},
+				Condition: `sysctl.value == "0"`,
+			},
+			expectReport: &compliance.Report{
+				Passed: false,
+				Error:  fmt.Errorf("rule-id: %w", fmt.Errorf("%w: %s", ErrSysctlNotFound, "net.ipv4.unknown")),
+			},
+			expectError: ErrSysctlNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(normalizeToTestdata("./testdata/sysctl"))
+
+			sysctlCheck, err := newResourceCheck(env, "rule-id", test.resource)
+			assert.NoError(err)
+
+			reports := sysctlCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+			if test.expectError != nil {
+				assert.True(errors.Is(reports[0].Error, test.expectError))
+			} else {
+				assert.NoError(reports[0].Error)
+			}
+		})
+	}
+}
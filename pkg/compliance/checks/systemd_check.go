@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"github.com/coreos/go-systemd/dbus"
+	godbus "github.com/godbus/dbus"
+)
+
+const (
+	// systemdPrivateSocket is the socket systemd listens to for direct connections, without a D-Bus daemon
+	systemdPrivateSocket = "/run/systemd/private"
+	// systemBusSocket is the socket of the D-Bus system bus
+	systemBusSocket = "/var/run/dbus/system_bus_socket"
+)
+
+var systemdUnitReportedFields = []string{
+	compliance.SystemdUnitFieldName,
+	compliance.SystemdUnitFieldLoadState,
+	compliance.SystemdUnitFieldActiveState,
+	compliance.SystemdUnitFieldUnitFileState,
+}
+
+// systemdUnitProperties returns the properties of a systemd unit, it is overridden by tests
+var systemdUnitProperties = getSystemdUnitProperties
+
+func resolveSystemdUnit(ctx context.Context, e env.Env, id string, res compliance.Resource) (resolved, error) {
+	if res.SystemdUnit == nil {
+		return nil, fmt.Errorf("%s: expecting systemd_unit resource in systemd_unit check", id)
+	}
+
+	unit := res.SystemdUnit
+	if err := unit.Validate(); err != nil {
+		return nil, wrapErrorWithID(id, err)
+	}
+
+	log.Debugf("%s: running systemd_unit check: %s", id, unit.Name)
+
+	properties, err := systemdUnitProperties(ctx, e, unit.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query systemd unit %s: %v", id, unit.Name, err)
+	}
+
+	loadState := properties["LoadState"]
+	activeState := properties["ActiveState"]
+	unitFileState := properties["UnitFileState"]
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.SystemdUnitFieldName:          unit.Name,
+			compliance.SystemdUnitFieldLoadState:     loadState,
+			compliance.SystemdUnitFieldActiveState:   activeState,
+			compliance.SystemdUnitFieldSubState:      properties["SubState"],
+			compliance.SystemdUnitFieldUnitFileState: unitFileState,
+			compliance.SystemdUnitFieldEnabled:       unitFileState == "enabled" || unitFileState == "enabled-runtime",
+			compliance.SystemdUnitFieldActive:        activeState == "active",
+			compliance.SystemdUnitFieldMasked:        loadState == "masked" || strings.HasPrefix(unitFileState, "masked"),
+		},
+		nil,
+	)
+
+	return newResolvedInstance(instance, unit.Name, "systemd_unit"), nil
+}
+
+// getSystemdUnitProperties reads the state of a unit from systemd over D-Bus, through the sockets of the host
+func getSystemdUnitProperties(ctx context.Context, e env.Env, name string) (map[string]string, error) {
+	conn, err := newSystemdConnection(e)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	type result struct {
+		properties map[string]interface{}
+		err        error
+	}
+	done := make(chan result, 1)
+	go func() {
+		properties, err := conn.GetUnitProperties(name)
+		done <- result{properties, err}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		properties := make(map[string]string)
+		for _, key := range []string{"LoadState", "ActiveState", "SubState", "UnitFileState"} {
+			if value, ok := res.properties[key].(string); ok {
+				properties[key] = value
+			}
+		}
+		return properties, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newSystemdConnection connects to systemd directly through its private socket, or through the system bus
+func newSystemdConnection(e env.Env) (*dbus.Conn, error) {
+	privateSocket := e.NormalizeToHostRoot(systemdPrivateSocket)
+	conn, err := dbus.NewConnection(func() (*godbus.Conn, error) {
+		// no Hello when talking directly to systemd
+		return dbusAuthConnection(privateSocket, false)
+	})
+	if err == nil {
+		return conn, nil
+	}
+	log.Debugf("Failed to connect to systemd private socket %s, falling back to the system bus: %v", privateSocket, err)
+
+	return dbus.NewConnection(func() (*godbus.Conn, error) {
+		return dbusAuthConnection(e.NormalizeToHostRoot(systemBusSocket), true)
+	})
+}
+
+// dbusAuthConnection opens an authenticated D-Bus connection to a socket, it only uses the EXTERNAL method with
+// the uid to avoid a username lookup
+func dbusAuthConnection(socket string, hello bool) (*godbus.Conn, error) {
+	conn, err := godbus.Dial(fmt.Sprintf("unix:path=%s", socket))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Auth([]godbus.Auth{godbus.AuthExternal(strconv.Itoa(os.Getuid()))}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if hello {
+		if err := conn.Hello(); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build !windows
+
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSystemdUnitCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource compliance.Resource
+
+		properties map[string]string
+
+		expectReport *compliance.Report
+	}{
+		{
+			name: "unit enabled and active",
+			resource: compliance.Resource{
+				SystemdUnit: &compliance.SystemdUnit{
+					Name: "auditd.service",
+				},
+				Condition: `systemd_unit.enabled && systemd_unit.active`,
+			},
+			properties: map[string]string{"LoadState": "loaded", "ActiveState": "active", "SubState": "running", "UnitFileState": "enabled"},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"systemd_unit.name":          "auditd.service",
+					"systemd_unit.loadState":     "loaded",
+					"systemd_unit.activeState":   "active",
+					"systemd_unit.unitFileState": "enabled",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "auditd.service",
+					Type: "systemd_unit",
+				},
+			},
+		},
+		{
+			name: "unit masked",
+			resource: compliance.Resource{
+				SystemdUnit: &compliance.SystemdUnit{
+					Name: "rpcbind.service",
+				},
+				Condition: `systemd_unit.masked`,
+			},
+			properties: map[string]string{"LoadState": "masked", "ActiveState": "inactive", "SubState": "dead", "UnitFileState": "masked"},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"systemd_unit.name":          "rpcbind.service",
+					"systemd_unit.loadState":     "masked",
+					"systemd_unit.activeState":   "inactive",
+					"systemd_unit.unitFileState": "masked",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "rpcbind.service",
+					Type: "systemd_unit",
+				},
+			},
+		},
+		{
+			name: "unit not found",
+			resource: compliance.Resource{
+				SystemdUnit: &compliance.SystemdUnit{
+					Name: "telnet.socket",
+				},
+				Condition: `!systemd_unit.enabled`,
+			},
+			properties: map[string]string{"LoadState": "not-found", "ActiveState": "inactive", "SubState": "dead", "UnitFileState": ""},
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"systemd_unit.name":          "telnet.socket",
+					"systemd_unit.loadState":     "not-found",
+					"systemd_unit.activeState":   "inactive",
+					"systemd_unit.unitFileState": "",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "telnet.socket",
+					Type: "systemd_unit",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			systemdUnitProperties = func(ctx context.Context, _ env.Env, name string) (map[string]string, error) {
+				assert.Equal(test.resource.SystemdUnit.Name, name)
+				return test.properties, nil
+			}
+			defer func() { systemdUnitProperties = getSystemdUnitProperties }()
+
+			env := &mocks.Env{}
+
+			systemdCheck, err := newResourceCheck(env, "rule-id", test.resource)
+			assert.NoError(err)
+
+			reports := systemdCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
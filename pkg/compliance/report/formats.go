@@ -0,0 +1,309 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+const (
+	toolName = "datadog-security-agent"
+
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+func (r *Report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func ruleName(rule *RuleResult) string {
+	if rule.Description == "" {
+		return rule.RuleID
+	}
+	return compliance.CheckName(rule.RuleID, rule.Description)
+}
+
+func resourceName(resource *ResourceResult) string {
+	if resource.ResourceType == "" {
+		return resource.ResourceID
+	}
+	return resource.ResourceType + " " + resource.ResourceID
+}
+
+// resourceDetails returns a human readable description of the result of a resource
+func resourceDetails(resource *ResourceResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n", resourceName(resource), resource.Result)
+
+	if data, ok := resource.Data.(event.Data); ok {
+		keys := make([]string, 0, len(data))
+		for key := range data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(&b, "  %s: %v\n", key, data[key])
+		}
+	}
+
+	return b.String()
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name          `xml:"testsuites"`
+	Name    string            `xml:"name,attr"`
+	Tests   int               `xml:"tests,attr"`
+	Failed  int               `xml:"failures,attr"`
+	Errors  int               `xml:"errors,attr"`
+	Skipped int               `xml:"skipped,attr"`
+	Suites  []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Hostname  string           `xml:"hostname,attr,omitempty"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failed    int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message  string `xml:"message,attr,omitempty"`
+	Contents string `xml:",chardata"`
+}
+
+// writeJUnit writes the report as JUnit XML, with one test suite per framework and one test case per rule
+func (r *Report) writeJUnit(w io.Writer) error {
+	suites := &junitTestSuites{
+		Name: toolName,
+	}
+	byFramework := make(map[string]*junitTestSuite)
+
+	for _, rule := range r.Rules {
+		suite, exists := byFramework[rule.Framework]
+		if !exists {
+			suite = &junitTestSuite{
+				Name:      rule.Framework,
+				Hostname:  r.Hostname,
+				Timestamp: r.Date.Format("2006-01-02T15:04:05"),
+			}
+			byFramework[rule.Framework] = suite
+			suites.Suites = append(suites.Suites, suite)
+		}
+
+		var details strings.Builder
+		for i := range rule.Resources {
+			details.WriteString(resourceDetails(&rule.Resources[i]))
+		}
+
+		testCase := &junitTestCase{
+			Name:      ruleName(rule),
+			ClassName: rule.Framework,
+		}
+
+		message := &junitMessage{
+			Message:  rule.Remediation,
+			Contents: details.String(),
+		}
+
+		switch rule.Result {
+		case event.Failed:
+			testCase.Failure = message
+			suite.Failed++
+		case event.Error:
+			testCase.Error = message
+			suite.Errors++
+		case Skipped:
+			testCase.Skipped = &junitMessage{Message: "rule does not apply to this host"}
+			suite.Skipped++
+		default:
+			testCase.SystemOut = details.String()
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failed += suite.Failed
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name,omitempty"`
+	ShortDescription *sarifMessage     `json:"shortDescription,omitempty"`
+	Help             *sarifMessage     `json:"help,omitempty"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// sarifKindAndLevel returns the kind and level of a SARIF result given the result of a resource
+func sarifKindAndLevel(result string) (string, string) {
+	switch result {
+	case event.Failed:
+		return "fail", "error"
+	case event.Error:
+		return "review", "warning"
+	default:
+		return "pass", "none"
+	}
+}
+
+// writeSARIF writes the report as a SARIF log, with one result per evaluated resource
+func (r *Report) writeSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:  toolName,
+				Rules: []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	for _, rule := range r.Rules {
+		descriptor := sarifRule{
+			ID:   rule.RuleID,
+			Name: ruleName(rule),
+			Properties: map[string]string{
+				"framework": rule.Framework,
+				"version":   rule.Version,
+			},
+		}
+		if rule.Description != "" {
+			descriptor.ShortDescription = &sarifMessage{Text: rule.Description}
+		}
+		if rule.Remediation != "" {
+			descriptor.Help = &sarifMessage{Text: rule.Remediation}
+		}
+
+		ruleIndex := len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, descriptor)
+
+		for i := range rule.Resources {
+			resource := &rule.Resources[i]
+			kind, level := sarifKindAndLevel(resource.Result)
+
+			result := sarifResult{
+				RuleID:    rule.RuleID,
+				RuleIndex: ruleIndex,
+				Kind:      kind,
+				Level:     level,
+				Message:   sarifMessage{Text: strings.TrimSpace(resourceDetails(resource))},
+			}
+
+			if resource.ResourceID != "" {
+				result.Locations = []sarifLocation{{
+					LogicalLocations: []sarifLogicalLocation{{
+						Name: resource.ResourceID,
+						Kind: resource.ResourceType,
+					}},
+				}}
+			}
+
+			if resource.Data != nil {
+				result.Properties = map[string]interface{}{
+					"data": resource.Data,
+				}
+			}
+
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
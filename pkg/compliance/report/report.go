@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package report implements the local export of compliance check results
+package report
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+// Format defines the output format of a report
+type Format string
+
+const (
+	// JSONFormat is used to write a report as JSON
+	JSONFormat = Format("json")
+	// JUnitFormat is used to write a report as JUnit XML
+	JUnitFormat = Format("junit")
+	// SARIFFormat is used to write a report as SARIF
+	SARIFFormat = Format("sarif")
+)
+
+// Skipped is used to report a rule that was not evaluated, as it does not apply to the host
+const Skipped = "skipped"
+
+// ResourceResult holds the result of a rule for one resource
+type ResourceResult struct {
+	ResourceID   string      `json:"resource_id,omitempty"`
+	ResourceType string      `json:"resource_type,omitempty"`
+	Result       string      `json:"result"`
+	Data         interface{} `json:"data,omitempty"`
+}
+
+// RuleResult holds the results of a rule
+type RuleResult struct {
+	RuleID      string           `json:"rule_id"`
+	Description string           `json:"description,omitempty"`
+	Framework   string           `json:"framework,omitempty"`
+	Version     string           `json:"version,omitempty"`
+	Remediation string           `json:"remediation,omitempty"`
+	Result      string           `json:"result"`
+	Resources   []ResourceResult `json:"resources,omitempty"`
+}
+
+// Report holds the results of all the rules of a compliance check run
+type Report struct {
+	Hostname string        `json:"hostname,omitempty"`
+	Date     time.Time     `json:"date"`
+	Rules    []*RuleResult `json:"rules"`
+}
+
+// Collector is an event.Reporter collecting the events of the checks run locally to build a report
+type Collector struct {
+	sync.Mutex
+
+	rules  []*RuleResult
+	byRule map[string]*RuleResult
+}
+
+// NewCollector returns a new Collector
+func NewCollector() *Collector {
+	return &Collector{
+		byRule: make(map[string]*RuleResult),
+	}
+}
+
+// AddRule declares a rule to be listed in the report, even if no event is reported for it, rules
+// may be added before or after their events are reported
+func (c *Collector) AddRule(meta *compliance.SuiteMeta, rule *compliance.Rule) {
+	c.Lock()
+	defer c.Unlock()
+
+	result, exists := c.byRule[rule.ID]
+	if !exists {
+		result = &RuleResult{
+			RuleID: rule.ID,
+		}
+		c.rules = append(c.rules, result)
+		c.byRule[rule.ID] = result
+	}
+
+	result.Description = rule.Description
+	result.Framework = meta.Framework
+	result.Version = meta.Version
+	result.Remediation = rule.Remediation
+}
+
+// Report collects the event of a rule
+func (c *Collector) Report(e *event.Event) {
+	c.Lock()
+	defer c.Unlock()
+
+	result, exists := c.byRule[e.AgentRuleID]
+	if !exists {
+		result = &RuleResult{
+			RuleID:    e.AgentRuleID,
+			Framework: e.AgentFrameworkID,
+		}
+		c.rules = append(c.rules, result)
+		c.byRule[e.AgentRuleID] = result
+	}
+
+	result.Resources = append(result.Resources, ResourceResult{
+		ResourceID:   e.ResourceID,
+		ResourceType: e.ResourceType,
+		Result:       e.Result,
+		Data:         e.Data,
+	})
+}
+
+// ReportRaw is not supported by reports
+func (c *Collector) ReportRaw(content []byte, service string, tags ...string) {
+}
+
+// Build returns the report of the collected results
+func (c *Collector) Build(hostname string) *Report {
+	c.Lock()
+	defer c.Unlock()
+
+	report := &Report{
+		Hostname: hostname,
+		Date:     time.Now().UTC(),
+		Rules:    c.rules,
+	}
+
+	for _, rule := range report.Rules {
+		rule.Result = ruleResult(rule.Resources)
+	}
+
+	return report
+}
+
+// ruleResult returns the result of a rule given the results of its resources: a rule
+// fails as soon as one of its resources fails
+func ruleResult(resources []ResourceResult) string {
+	if len(resources) == 0 {
+		return Skipped
+	}
+
+	result := event.Passed
+	for _, resource := range resources {
+		switch resource.Result {
+		case event.Error:
+			result = event.Error
+		case event.Failed:
+			if result != event.Error {
+				result = event.Failed
+			}
+		}
+	}
+	return result
+}
+
+// IsValidFormat returns whether the format is supported
+func IsValidFormat(format Format) bool {
+	switch format {
+	case JSONFormat, JUnitFormat, SARIFFormat:
+		return true
+	default:
+		return false
+	}
+}
+
+// Write writes the report in the given format
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case JSONFormat:
+		return r.writeJSON(w)
+	case JUnitFormat:
+		return r.writeJUnit(w)
+	case SARIFFormat:
+		return r.writeSARIF(w)
+	default:
+		return fmt.Errorf("unsupported report format `%s`", format)
+	}
+}
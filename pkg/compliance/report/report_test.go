@@ -0,0 +1,170 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+func newTestCollector() *Collector {
+	meta := &compliance.SuiteMeta{
+		Framework: "cis-docker",
+		Version:   "1.2.0",
+	}
+
+	c := NewCollector()
+	c.AddRule(meta, &compliance.Rule{
+		ID:          "cis-docker-1",
+		Description: "Ensure daemon.json permissions are set to 644",
+		Remediation: "Run chmod 644 /etc/docker/daemon.json",
+	})
+	c.AddRule(meta, &compliance.Rule{
+		ID:          "cis-docker-2",
+		Description: "Ensure containers are restricted from acquiring new privileges",
+	})
+	c.AddRule(meta, &compliance.Rule{
+		ID:          "cis-docker-3",
+		Description: "Ensure swarm mode is not enabled",
+	})
+
+	c.Report(&event.Event{
+		AgentRuleID:      "cis-docker-1",
+		AgentFrameworkID: "cis-docker",
+		ResourceID:       "/etc/docker/daemon.json",
+		ResourceType:     "file",
+		Result:           event.Failed,
+		Data:             event.Data{"file.permissions": 0666},
+	})
+	c.Report(&event.Event{
+		AgentRuleID:      "cis-docker-2",
+		AgentFrameworkID: "cis-docker",
+		ResourceID:       "abc",
+		ResourceType:     "docker_container",
+		Result:           event.Passed,
+	})
+	c.Report(&event.Event{
+		AgentRuleID:      "cis-docker-2",
+		AgentFrameworkID: "cis-docker",
+		ResourceID:       "def",
+		ResourceType:     "docker_container",
+		Result:           event.Passed,
+	})
+
+	return c
+}
+
+func TestCollector(t *testing.T) {
+	report := newTestCollector().Build("myhost")
+
+	assert.Equal(t, "myhost", report.Hostname)
+	if assert.Len(t, report.Rules, 3) {
+		assert.Equal(t, event.Failed, report.Rules[0].Result)
+		assert.Equal(t, "Run chmod 644 /etc/docker/daemon.json", report.Rules[0].Remediation)
+		assert.Equal(t, event.Passed, report.Rules[1].Result)
+		assert.Len(t, report.Rules[1].Resources, 2)
+		assert.Equal(t, Skipped, report.Rules[2].Result)
+	}
+}
+
+func TestCollectorRuleAddedAfterEvents(t *testing.T) {
+	c := NewCollector()
+	c.Report(&event.Event{
+		AgentRuleID:      "cis-docker-1",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Passed,
+	})
+	c.AddRule(&compliance.SuiteMeta{Framework: "cis-docker"}, &compliance.Rule{
+		ID:          "cis-docker-1",
+		Remediation: "Run chmod 644 /etc/docker/daemon.json",
+	})
+
+	report := c.Build("myhost")
+	if assert.Len(t, report.Rules, 1) {
+		assert.Equal(t, event.Passed, report.Rules[0].Result)
+		assert.Equal(t, "Run chmod 644 /etc/docker/daemon.json", report.Rules[0].Remediation)
+	}
+}
+
+func TestRuleResult(t *testing.T) {
+	assert.Equal(t, Skipped, ruleResult(nil))
+	assert.Equal(t, event.Passed, ruleResult([]ResourceResult{{Result: event.Passed}}))
+	assert.Equal(t, event.Failed, ruleResult([]ResourceResult{{Result: event.Passed}, {Result: event.Failed}}))
+	assert.Equal(t, event.Error, ruleResult([]ResourceResult{{Result: event.Error}, {Result: event.Failed}}))
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTestCollector().Build("myhost").Write(&buf, JSONFormat)
+	assert.NoError(t, err)
+
+	var report Report
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	if assert.Len(t, report.Rules, 3) {
+		assert.Equal(t, "cis-docker-1", report.Rules[0].RuleID)
+		assert.Equal(t, "Run chmod 644 /etc/docker/daemon.json", report.Rules[0].Remediation)
+		assert.Equal(t, map[string]interface{}{"file.permissions": float64(0666)}, report.Rules[0].Resources[0].Data)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTestCollector().Build("myhost").Write(&buf, JUnitFormat)
+	assert.NoError(t, err)
+
+	var suites junitTestSuites
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failed)
+	assert.Equal(t, 1, suites.Skipped)
+	if assert.Len(t, suites.Suites, 1) && assert.Len(t, suites.Suites[0].TestCases, 3) {
+		testCase := suites.Suites[0].TestCases[0]
+		assert.Equal(t, "cis-docker-1: Ensure daemon.json permissions are set to 644", testCase.Name)
+		if assert.NotNil(t, testCase.Failure) {
+			assert.Equal(t, "Run chmod 644 /etc/docker/daemon.json", testCase.Failure.Message)
+			assert.Contains(t, testCase.Failure.Contents, "file.permissions: 438")
+		}
+		assert.Nil(t, suites.Suites[0].TestCases[1].Failure)
+		assert.NotNil(t, suites.Suites[0].TestCases[2].Skipped)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	err := newTestCollector().Build("myhost").Write(&buf, SARIFFormat)
+	assert.NoError(t, err)
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(t, sarifVersion, log.Version)
+	if assert.Len(t, log.Runs, 1) {
+		run := log.Runs[0]
+		assert.Len(t, run.Tool.Driver.Rules, 3)
+		assert.Equal(t, "Run chmod 644 /etc/docker/daemon.json", run.Tool.Driver.Rules[0].Help.Text)
+
+		if assert.Len(t, run.Results, 3) {
+			assert.Equal(t, "fail", run.Results[0].Kind)
+			assert.Equal(t, "error", run.Results[0].Level)
+			assert.Equal(t, "/etc/docker/daemon.json", run.Results[0].Locations[0].LogicalLocations[0].Name)
+			assert.Equal(t, "pass", run.Results[1].Kind)
+			assert.Equal(t, 1, run.Results[1].RuleIndex)
+		}
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, newTestCollector().Build("myhost").Write(&buf, Format("html")))
+}
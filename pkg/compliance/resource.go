@@ -32,6 +32,14 @@ const (
 	KindKubernetes = ResourceKind("kubernetes")
 	// KindCustom is used for a Custom check
 	KindCustom = ResourceKind("custom")
+	// KindSysctl is used for a Sysctl resource
+	KindSysctl = ResourceKind("sysctl")
+	// KindSystemdUnit is used for a SystemdUnit resource
+	KindSystemdUnit = ResourceKind("systemd_unit")
+	// KindKernelModule is used for a KernelModule resource
+	KindKernelModule = ResourceKind("kernel_module")
+	// KindKubelet is used for a Kubelet resource
+	KindKubelet = ResourceKind("kubelet")
 )
 
 // Resource describes supported resource types observed by a Rule
@@ -44,8 +52,14 @@ type Resource struct {
 	Docker        *DockerResource     `yaml:"docker,omitempty"`
 	KubeApiserver *KubernetesResource `yaml:"kubeApiserver,omitempty"`
 	Custom        *Custom             `yaml:"custom,omitempty"`
+	Sysctl        *Sysctl             `yaml:"sysctl,omitempty"`
+	SystemdUnit   *SystemdUnit        `yaml:"systemd_unit,omitempty"`
+	KernelModule  *KernelModule       `yaml:"kernel_module,omitempty"`
+	Kubelet       *Kubelet            `yaml:"kubelet,omitempty"`
 	Condition     string              `yaml:"condition"`
 	Fallback      *Fallback           `yaml:"fallback,omitempty"`
+	// Tag is the name of the Rego input holding the instances of the resource, defaults to its kind
+	Tag string `yaml:"tag,omitempty"`
 }
 
 // Kind returns ResourceKind of the resource
@@ -67,6 +81,14 @@ func (r *Resource) Kind() ResourceKind {
 		return KindKubernetes
 	case r.Custom != nil:
 		return KindCustom
+	case r.Sysctl != nil:
+		return KindSysctl
+	case r.SystemdUnit != nil:
+		return KindSystemdUnit
+	case r.KernelModule != nil:
+		return KindKernelModule
+	case r.Kubelet != nil:
+		return KindKubelet
 	default:
 		return KindInvalid
 	}
@@ -117,6 +139,7 @@ const (
 	KubeResourceFieldVersion   = "kube.resource.version"
 	KubeResourceFieldNamespace = "kube.resource.namespace"
 	KubeResourceFieldKind      = "kube.resource.kind"
+	KubeResourceFieldObject    = "kube.resource.object"
 
 	KubeResourceFuncJQ = "kube.resource.jq"
 )
@@ -257,3 +280,94 @@ type Custom struct {
 	Name      string            `yaml:"name"`
 	Variables map[string]string `yaml:"variables,omitempty"`
 }
+
+// Fields available for Sysctl
+const (
+	SysctlFieldKey   = "sysctl.key"
+	SysctlFieldValue = "sysctl.value"
+)
+
+// Sysctl describes a kernel parameter resource
+type Sysctl struct {
+	Key string `yaml:"key"`
+}
+
+// Validate validates sysctl resource
+func (s *Sysctl) Validate() error {
+	if len(s.Key) == 0 {
+		return errors.New("sysctl resource is missing key")
+	}
+	return nil
+}
+
+// Fields available for SystemdUnit
+const (
+	SystemdUnitFieldName          = "systemd_unit.name"
+	SystemdUnitFieldLoadState     = "systemd_unit.loadState"
+	SystemdUnitFieldActiveState   = "systemd_unit.activeState"
+	SystemdUnitFieldSubState      = "systemd_unit.subState"
+	SystemdUnitFieldUnitFileState = "systemd_unit.unitFileState"
+	SystemdUnitFieldEnabled       = "systemd_unit.enabled"
+	SystemdUnitFieldActive        = "systemd_unit.active"
+	SystemdUnitFieldMasked        = "systemd_unit.masked"
+)
+
+// SystemdUnit describes a systemd unit resource
+type SystemdUnit struct {
+	Name string `yaml:"name"`
+}
+
+// Validate validates systemd unit resource
+func (u *SystemdUnit) Validate() error {
+	if len(u.Name) == 0 {
+		return errors.New("systemd_unit resource is missing name")
+	}
+	return nil
+}
+
+// Fields available for KernelModule
+const (
+	KernelModuleFieldName        = "kernel_module.name"
+	KernelModuleFieldLoaded      = "kernel_module.loaded"
+	KernelModuleFieldBlacklisted = "kernel_module.blacklisted"
+	KernelModuleFieldDisabled    = "kernel_module.disabled"
+)
+
+// KernelModule describes a kernel module resource
+type KernelModule struct {
+	Name string `yaml:"name"`
+}
+
+// Validate validates kernel module resource
+func (m *KernelModule) Validate() error {
+	if len(m.Name) == 0 {
+		return errors.New("kernel_module resource is missing name")
+	}
+	return nil
+}
+
+// Fields & functions available for Kubelet
+const (
+	KubeletFieldConfigFile = "kubelet.configFile"
+	KubeletFieldKubeconfig = "kubelet.kubeconfig"
+
+	KubeletFieldKubeconfigServer                = "kubelet.kubeconfig.server"
+	KubeletFieldKubeconfigCertificateAuthority  = "kubelet.kubeconfig.certificateAuthority"
+	KubeletFieldKubeconfigInsecureSkipTLSVerify = "kubelet.kubeconfig.insecureSkipTLSVerify"
+	KubeletFieldKubeconfigClientCertificate     = "kubelet.kubeconfig.clientCertificate"
+	KubeletFieldKubeconfigClientKey             = "kubelet.kubeconfig.clientKey"
+
+	KubeletFieldSettings = "kubelet.settings"
+
+	KubeletFuncConfig = "kubelet.config"
+	KubeletFuncSource = "kubelet.source"
+)
+
+// Kubelet describes the effective configuration of the kubelet, merging its command-line flags,
+// its configuration file and their defaults
+type Kubelet struct {
+	// Name of the kubelet process, defaults to kubelet
+	Process string `yaml:"process,omitempty"`
+	// Configuration file used when the kubelet is not started with the --config flag
+	ConfigFile string `yaml:"configFile,omitempty"`
+}
@@ -62,6 +62,31 @@ docker:
 condition: docker.template("{{ $.Config.Healthcheck }}") != ""
 `
 
+const testResourceSysctl = `
+sysctl:
+  key: net.ipv4.ip_forward
+condition: sysctl.value == "0"
+`
+
+const testResourceSystemdUnit = `
+systemd_unit:
+  name: auditd.service
+condition: systemd_unit.enabled && systemd_unit.active
+`
+
+const testResourceKernelModule = `
+kernel_module:
+  name: cramfs
+condition: >-
+  !kernel_module.loaded && kernel_module.disabled
+`
+
+const testResourceKubelet = `
+kubelet:
+  configFile: /var/lib/kubelet/config.yaml
+condition: kubelet.config("authentication.anonymous.enabled") == "false"
+`
+
 func TestResources(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -150,6 +175,46 @@ func TestResources(t *testing.T) {
 				Condition: `docker.template("{{ $.Config.Healthcheck }}") != ""`,
 			},
 		},
+		{
+			name:  "sysctl",
+			input: testResourceSysctl,
+			expected: Resource{
+				Sysctl: &Sysctl{
+					Key: "net.ipv4.ip_forward",
+				},
+				Condition: `sysctl.value == "0"`,
+			},
+		},
+		{
+			name:  "systemd unit",
+			input: testResourceSystemdUnit,
+			expected: Resource{
+				SystemdUnit: &SystemdUnit{
+					Name: "auditd.service",
+				},
+				Condition: `systemd_unit.enabled && systemd_unit.active`,
+			},
+		},
+		{
+			name:  "kernel module",
+			input: testResourceKernelModule,
+			expected: Resource{
+				KernelModule: &KernelModule{
+					Name: "cramfs",
+				},
+				Condition: `!kernel_module.loaded && kernel_module.disabled`,
+			},
+		},
+		{
+			name:  "kubelet",
+			input: testResourceKubelet,
+			expected: Resource{
+				Kubelet: &Kubelet{
+					ConfigFile: "/var/lib/kubelet/config.yaml",
+				},
+				Condition: `kubelet.config("authentication.anonymous.enabled") == "false"`,
+			},
+		},
 	}
 
 	for _, test := range tests {
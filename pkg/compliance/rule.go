@@ -16,6 +16,16 @@ type Rule struct {
 	HostSelector string        `yaml:"hostSelector,omitempty"`
 	ResourceType string        `yaml:"resourceType,omitempty"`
 	Resources    []Resource    `yaml:"resources,omitempty"`
+	Remediation  string        `yaml:"remediation,omitempty"`
+	// Module is the Rego module evaluating the resources of the rule instead of their conditions
+	Module string `yaml:"module,omitempty"`
+	// Findings is the Rego query returning the findings of the module, defaults to data.datadog.findings
+	Findings string `yaml:"findings,omitempty"`
+}
+
+// IsRego returns whether the rule is evaluated by a Rego module
+func (r *Rule) IsRego() bool {
+	return r.Module != ""
 }
 
 // RuleScope defines scope for applicability of a rule
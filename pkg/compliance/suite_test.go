@@ -44,6 +44,7 @@ func TestParseSuite(t *testing.T) {
 								Condition: `file.permissions == 0644`,
 							},
 						},
+						Remediation: "Run chmod 644 /etc/docker/daemon.json",
 					},
 				},
 			},
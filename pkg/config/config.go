@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	yaml "gopkg.in/yaml.v2"
@@ -86,7 +87,6 @@ const (
 // Datadog is the global configuration object
 var (
 	Datadog       Config
-	proxies       *Proxy
 	overrideFuncs = make([]func(Config), 0)
 )
 
@@ -301,6 +301,10 @@ func InitConfig(config Config) {
 	// Agent GUI access port
 	config.BindEnvAndSetDefault("GUI_port", defaultGuiPort)
 
+	// Watch the configuration file and hot reload the keys that support it
+	config.BindEnvAndSetDefault("config_watch.enabled", false)
+	config.BindEnvAndSetDefault("config_watch.interval", 10*time.Second)
+
 	if IsContainerized() {
 		// In serverless-containerized environments (e.g Fargate)
 		// it's impossible to mount host volumes.
@@ -548,6 +552,14 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("snmp_traps_config.bind_host", "localhost")
 	config.BindEnvAndSetDefault("snmp_traps_config.stop_timeout", 5) // in seconds
 
+	// NetFlow
+	config.BindEnvAndSetDefault("network_devices.netflow.enabled", false)
+	config.BindEnvAndSetDefault("network_devices.netflow.bind_host", "localhost")
+	config.BindEnvAndSetDefault("network_devices.netflow.ports", []int{2055, 4739, 6343})
+	config.BindEnvAndSetDefault("network_devices.netflow.aggregator_flush_interval", 300) // in seconds
+	config.BindEnvAndSetDefault("network_devices.netflow.aggregator_max_flows", 100000)
+	config.BindEnvAndSetDefault("network_devices.netflow.stop_timeout", 5) // in seconds
+
 	// Kube ApiServer
 	config.BindEnvAndSetDefault("kubernetes_kubeconfig_path", "")
 	config.BindEnvAndSetDefault("kubernetes_apiserver_ca_path", "")
@@ -716,6 +728,9 @@ func InitConfig(config Config) {
 	bindEnvAndSetLogsConfigKeys(config, "database_monitoring.activity.")
 	bindEnvAndSetLogsConfigKeys(config, "database_monitoring.metrics.")
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.metadata.")
+	bindEnvAndSetLogsConfigKeys(config, "network_devices.netflow.forwarder.")
+	bindEnvAndSetLogsConfigKeys(config, "process_config.event_collection.forwarder.")
+	bindEnvAndSetLogsConfigKeys(config, "process_config.process_discovery.forwarder.")
 
 	config.BindEnvAndSetDefault("logs_config.dd_port", 10516)
 	config.BindEnvAndSetDefault("logs_config.dev_mode_use_proto", true)
@@ -772,6 +787,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cluster_checks.extra_tags", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.advanced_dispatching_enabled", false)
 	config.BindEnvAndSetDefault("cluster_checks.clc_runners_port", 5005)
+	config.BindEnvAndSetDefault("cluster_checks.rebalance_cooldown", 1800) // value in seconds
+	config.BindEnvAndSetDefault("cluster_checks.rebalance_threshold", 0.1) // fraction of the average node busyness
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
 	config.BindEnvAndSetDefault("clc_runner_id", "")
@@ -793,6 +810,10 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("admission_controller.inject_config.endpoint", "/injectconfig")
 	config.BindEnvAndSetDefault("admission_controller.inject_tags.enabled", true)
 	config.BindEnvAndSetDefault("admission_controller.inject_tags.endpoint", "/injecttags")
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.endpoint", "/injectlib")
+	config.BindEnvAndSetDefault("admission_controller.auto_instrumentation.container_registry", "gcr.io/datadoghq")
+	config.BindEnv("admission_controller.auto_instrumentation.namespaces")            // libraries to inject by namespace and language
 	config.BindEnvAndSetDefault("admission_controller.pod_owners_cache_validity", 10) // in minutes
 	config.BindEnvAndSetDefault("admission_controller.namespace_selector_fallback", false)
 
@@ -864,6 +885,12 @@ func InitConfig(config Config) {
 	config.SetKnown("process_config.log_file")
 	config.SetKnown("process_config.internal_profiling.enabled")
 	config.SetKnown("process_config.remote_tagger")
+	config.BindEnvAndSetDefault("process_config.process_discovery.enabled", false)
+	config.BindEnvAndSetDefault("process_config.process_discovery.interval", 600) // in seconds
+	config.BindEnvAndSetDefault("process_config.event_collection.enabled", false)
+	config.BindEnvAndSetDefault("process_config.event_collection.poll_interval", 5)   // in seconds
+	config.BindEnvAndSetDefault("process_config.event_collection.flush_interval", 10) // in seconds
+	config.BindEnvAndSetDefault("process_config.event_collection.max_batch_size", 500)
 
 	// Network
 	config.BindEnv("network.id")
@@ -916,6 +943,25 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("runtime_security_config.log_patterns", []string{})
 	bindEnvAndSetLogsConfigKeys(config, "runtime_security_config.endpoints.")
 	config.BindEnvAndSetDefault("runtime_security_config.self_test.enabled", true)
+	config.BindEnvAndSetDefault("runtime_security_config.record_events.file", "")
+	config.BindEnvAndSetDefault("runtime_security_config.active_response.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.active_response.dry_run", false)
+	config.BindEnvAndSetDefault("runtime_security_config.active_response.rate_limit", 5)
+	config.BindEnvAndSetDefault("runtime_security_config.active_response.protected_pids", []string{})
+	config.BindEnvAndSetDefault("runtime_security_config.active_response.protected_binaries", []string{
+		"/sbin/init",
+		"/lib/systemd/systemd",
+		"/usr/lib/systemd/systemd",
+		"/opt/datadog-agent/bin/agent/agent",
+		"/opt/datadog-agent/embedded/bin/system-probe",
+		"/opt/datadog-agent/embedded/bin/security-agent",
+	})
+	config.BindEnvAndSetDefault("runtime_security_config.activity_profiles.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.activity_profiles.dir", filepath.Join(defaultRunPath, "runtime-security", "profiles"))
+	config.BindEnvAndSetDefault("runtime_security_config.activity_profiles.learning_period", 86400)
+	config.BindEnvAndSetDefault("runtime_security_config.activity_profiles.path_depth", 3)
+	config.BindEnvAndSetDefault("runtime_security_config.activity_profiles.max_entries", 1000)
+	config.BindEnvAndSetDefault("runtime_security_config.activity_profiles.max_profiles", 500)
 
 	// Serverless Agent
 	config.BindEnvAndSetDefault("serverless.logs_enabled", true)
@@ -931,13 +977,41 @@ func InitConfig(config Config) {
 
 var ddURLRegexp = regexp.MustCompile(`^app(\.(us|eu)\d)?\.datad(oghq|0g)\.(com|eu)$`)
 
+// proxies holds the proxy settings, they can be refreshed at runtime while being read
+var (
+	proxies     *Proxy
+	proxiesLock sync.RWMutex
+)
+
 // GetProxies returns the proxy settings from the configuration
 func GetProxies() *Proxy {
+	proxiesLock.RLock()
+	defer proxiesLock.RUnlock()
 	return proxies
 }
 
+func setProxies(p *Proxy) {
+	proxiesLock.Lock()
+	defer proxiesLock.Unlock()
+	proxies = p
+}
+
+// RefreshProxies recomputes the proxy settings from the Datadog configuration
+// and the environment, e.g. after the proxy settings were reloaded at runtime.
+func RefreshProxies() {
+	setProxies(proxyFromEnv(Datadog))
+}
+
 // loadProxyFromEnv overrides the proxy settings with environment variables
 func loadProxyFromEnv(config Config) {
+	if p := proxyFromEnv(config); p != nil {
+		setProxies(p)
+	}
+}
+
+// proxyFromEnv overrides the proxy settings of config with environment variables
+// and returns the resulting proxy settings, or nil if no proxy is configured.
+func proxyFromEnv(config Config) *Proxy {
 	// Viper doesn't handle mixing nested variables from files and set
 	// manually.  If we manually set one of the sub value for "proxy" all
 	// other values from the conf file will be shadowed when using
@@ -1010,13 +1084,17 @@ func loadProxyFromEnv(config Config) {
 			// []interface{}, which will then conflict with type []string and fail to merge.
 			config.Set("proxy.no_proxy", []interface{}{})
 		}
-		proxies = p
 	}
 
 	if !config.GetBool("use_proxy_for_cloud_metadata") {
 		p.NoProxy = append(p.NoProxy, "169.254.169.254") // Azure, EC2, GCE
 		p.NoProxy = append(p.NoProxy, "100.100.100.200") // Alibaba
 	}
+
+	if !isSet {
+		return nil
+	}
+	return p
 }
 
 // Load reads configs files and initializes the config module
@@ -1102,6 +1180,29 @@ func load(config Config, origin string, loadSecret bool) (*Warnings, error) {
 	return &warnings, nil
 }
 
+// LoadNewConfig reads the configuration file at path into a new Config, leaving
+// the global Datadog configuration untouched. The same post-processing as Load
+// is applied so that the result can be compared with the running configuration.
+func LoadNewConfig(path string) (Config, error) {
+	config := NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	InitConfig(config)
+	config.SetConfigFile(path)
+
+	if err := config.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	if err := ResolveSecrets(config, "datadog.yaml"); err != nil {
+		return nil, err
+	}
+
+	applyOverrideFuncs(config)
+	proxyFromEnv(config)
+	SanitizeAPIKeyConfig(config, "api_key")
+	setTracemallocEnabled(config)
+	setNumWorkers(config)
+	return config, nil
+}
+
 // ResolveSecrets merges all the secret values from origin into config. Secret values
 // are identified by a value of the form "ENC[key]" where key is the secret key.
 // See: https://github.com/DataDog/datadog-agent/blob/main/docs/agent/secrets.md
@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/common/types"
@@ -565,6 +566,39 @@ func TestLoadProxyFromStdEnvNoValue(t *testing.T) {
 	require.Nil(t, proxies)
 }
 
+func TestRefreshProxiesConcurrentReads(t *testing.T) {
+	config := setupConf()
+	p := &Proxy{HTTP: "test", HTTPS: "test2", NoProxy: []string{"a", "b", "c"}}
+	config.Set("proxy", p)
+	config.Set("use_proxy_for_cloud_metadata", true)
+
+	resetEnv := unsetEnvForTest("NO_PROXY") // CircleCI sets NO_PROXY, so unset it for this test
+	defer resetEnv()
+
+	datadog := Datadog
+	Datadog = config
+	defer func() {
+		Datadog = datadog
+		setProxies(nil)
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RefreshProxies()
+		}()
+		go func() {
+			defer wg.Done()
+			GetProxies()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, p, GetProxies())
+}
+
 func TestLoadProxyConfOnly(t *testing.T) {
 	config := setupConf()
 
@@ -6,6 +6,7 @@ type Client interface {
 	Set(key string, value string) (bool, error)
 	List() (map[string]RuntimeSettingResponse, error)
 	FullConfig() (string, error)
+	Reload() (*ReloadResult, error)
 }
 
 // ClientBuilder represents a function returning a runtime settings API client
@@ -105,3 +105,23 @@ func (rc *runtimeSettingsHTTPClient) Set(key string, value string) (bool, error)
 	}
 	return hidden, nil
 }
+
+func (rc *runtimeSettingsHTTPClient) Reload() (*settings.ReloadResult, error) {
+	r, err := util.DoPost(rc.c, fmt.Sprintf("%s/%s", rc.baseURL, "reload"), "application/json", bytes.NewBuffer([]byte{}))
+	if err != nil {
+		var errMap = make(map[string]string)
+		_ = json.Unmarshal(r, &errMap)
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			return nil, fmt.Errorf(e)
+		}
+		return nil, err
+	}
+
+	var result settings.ReloadResult
+	err = json.Unmarshal(r, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
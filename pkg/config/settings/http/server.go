@@ -18,11 +18,13 @@ var Server = struct {
 	GetValue         http.HandlerFunc
 	SetValue         http.HandlerFunc
 	ListConfigurable http.HandlerFunc
+	Reload           http.HandlerFunc
 }{
 	GetFull:          getFullConfig,
 	GetValue:         getConfigValue,
 	SetValue:         setConfigValue,
 	ListConfigurable: listConfigurableSettings,
+	Reload:           reloadConfig,
 }
 
 func getFullConfig(namespace string) http.HandlerFunc {
@@ -122,3 +124,22 @@ func setConfigValue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func reloadConfig(w http.ResponseWriter, _ *http.Request) {
+	log.Infof("Got a request to reload the configuration")
+
+	result, err := settings.ReloadConfig()
+	if err != nil {
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), http.StatusInternalServerError)
+		return
+	}
+	body, err := json.Marshal(result)
+	if err != nil {
+		log.Errorf("Unable to marshal configuration reload response: %s", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	reloadHooks []ReloadHook
+
+	reloadMutex sync.Mutex
+	// reloadConfigFile is the path of the configuration file to reload
+	reloadConfigFile string
+	// reloadBaseline is the configuration file content the running agent
+	// applied, the keys requiring a restart keep their startup values
+	reloadBaseline config.Config
+)
+
+// ReloadHook applies hot-reloadable configuration keys to a running subsystem.
+type ReloadHook interface {
+	// Name returns the name of the subsystem reloaded by the hook
+	Name() string
+	// Keys returns the configuration keys handled by the hook, a key also
+	// matches all its sub-keys (e.g. "proxy" matches "proxy.http")
+	Keys() []string
+	// Reload is called with the changed keys once their new values have been
+	// set in the configuration
+	Reload(changed []string) error
+}
+
+// ReloadResult is the outcome of a configuration reload
+type ReloadResult struct {
+	// Applied lists the changed keys that were hot reloaded
+	Applied []string `json:"applied"`
+	// RestartRequired lists the changed keys that only apply after a restart
+	RestartRequired []string `json:"restart_required"`
+	// Errors contains the errors returned by the reload hooks, by hook name
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+type reloadHook struct {
+	name   string
+	keys   []string
+	reload func(changed []string) error
+}
+
+// NewReloadHook returns a ReloadHook calling reload when one of keys changed
+func NewReloadHook(name string, keys []string, reload func(changed []string) error) ReloadHook {
+	return &reloadHook{name: name, keys: keys, reload: reload}
+}
+
+func (h *reloadHook) Name() string {
+	return h.name
+}
+
+func (h *reloadHook) Keys() []string {
+	return h.keys
+}
+
+func (h *reloadHook) Reload(changed []string) error {
+	return h.reload(changed)
+}
+
+// RegisterReloadHook keeps track of the subsystems supporting hot reload
+func RegisterReloadHook(hook ReloadHook) error {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	for _, h := range reloadHooks {
+		if h.Name() == hook.Name() {
+			return errors.New("duplicated reload hook detected")
+		}
+	}
+	reloadHooks = append(reloadHooks, hook)
+	return nil
+}
+
+// HotReloadableKeys returns the configuration keys handled by the registered reload hooks
+func HotReloadableKeys() []string {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	var keys []string
+	for _, h := range reloadHooks {
+		keys = append(keys, h.Keys()...)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// InitConfigReload reads the configuration file at path as the baseline
+// future reloads will be compared to.
+func InitConfigReload(path string) error {
+	baseline, err := config.LoadNewConfig(path)
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %v", path, err)
+	}
+
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	reloadConfigFile = path
+	reloadBaseline = baseline
+	return nil
+}
+
+// ReloadConfig reads the configuration file again and applies the changed keys
+// supported by the registered reload hooks. The other changed keys are only
+// reported, and will keep being reported until the agent is restarted.
+func ReloadConfig() (*ReloadResult, error) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+
+	if reloadBaseline == nil {
+		return nil, errors.New("configuration reload is not initialized")
+	}
+
+	newConfig, err := config.LoadNewConfig(reloadConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s: %v", reloadConfigFile, err)
+	}
+
+	result := &ReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+	changedByHook := make(map[ReloadHook][]string)
+
+	for _, key := range diffConfigs(reloadBaseline, newConfig) {
+		hook := findReloadHook(key)
+		if hook == nil {
+			result.RestartRequired = append(result.RestartRequired, key)
+			continue
+		}
+		value := newConfig.Get(key)
+		config.Datadog.Set(key, value)
+		reloadBaseline.Set(key, value)
+		changedByHook[hook] = append(changedByHook[hook], key)
+		result.Applied = append(result.Applied, key)
+	}
+
+	// call the hooks in registration order
+	for _, hook := range reloadHooks {
+		changed, found := changedByHook[hook]
+		if !found {
+			continue
+		}
+		log.Infof("Reloading %s after configuration change of: %s", hook.Name(), strings.Join(changed, ", "))
+		if err := hook.Reload(changed); err != nil {
+			log.Errorf("Unable to reload %s: %v", hook.Name(), err)
+			if result.Errors == nil {
+				result.Errors = make(map[string]string)
+			}
+			result.Errors[hook.Name()] = err.Error()
+		}
+	}
+
+	if len(result.RestartRequired) > 0 {
+		log.Warnf("The following configuration changes require a restart of the agent: %s", strings.Join(result.RestartRequired, ", "))
+	}
+
+	return result, nil
+}
+
+// findReloadHook returns the hook handling key, nil if the key isn't hot reloadable
+func findReloadHook(key string) ReloadHook {
+	for _, h := range reloadHooks {
+		for _, k := range h.Keys() {
+			if key == k || strings.HasPrefix(key, k+".") {
+				return h
+			}
+		}
+	}
+	return nil
+}
+
+// diffConfigs returns the sorted list of keys whose value differ between old and new
+func diffConfigs(old, new config.Config) []string {
+	keys := make(map[string]struct{})
+	for _, k := range old.AllKeys() {
+		keys[k] = struct{}{}
+	}
+	for _, k := range new.AllKeys() {
+		keys[k] = struct{}{}
+	}
+
+	// only compare leaf keys, a key with a default (e.g. "proxy") can also be
+	// the parent of keys loaded from the file (e.g. "proxy.https")
+	for k := range keys {
+		for i := strings.LastIndex(k, "."); i > 0; i = strings.LastIndex(k[:i], ".") {
+			delete(keys, k[:i])
+		}
+	}
+
+	var changed []string
+	for k := range keys {
+		if !reflect.DeepEqual(old.Get(k), new.Get(k)) {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cleanReloadHooks() {
+	reloadHooks = nil
+	reloadConfigFile = ""
+	reloadBaseline = nil
+}
+
+func writeConfigFile(t *testing.T, path string, content string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+}
+
+func TestRegisterReloadHook(t *testing.T) {
+	cleanReloadHooks()
+
+	hook := NewReloadHook("test", []string{"tags", "proxy"}, func(_ []string) error { return nil })
+	assert.NoError(t, RegisterReloadHook(hook))
+	assert.Equal(t, []string{"proxy", "tags"}, HotReloadableKeys())
+
+	err := RegisterReloadHook(hook)
+	assert.NotNil(t, err)
+	assert.Equal(t, "duplicated reload hook detected", err.Error())
+}
+
+func TestReloadConfigNotInitialized(t *testing.T) {
+	cleanReloadHooks()
+
+	_, err := ReloadConfig()
+	assert.Error(t, err)
+}
+
+func TestReloadConfig(t *testing.T) {
+	cleanReloadHooks()
+	config.Mock()
+
+	dir, err := ioutil.TempDir("", "reload")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datadog.yaml")
+
+	writeConfigFile(t, path, `
+hostname: foo
+tags:
+  - team:a
+proxy:
+  https: http://proxy:3128
+`)
+
+	var changedTags, changedProxy []string
+	require.NoError(t, RegisterReloadHook(NewReloadHook("tags", []string{"tags"}, func(changed []string) error {
+		changedTags = changed
+		return nil
+	})))
+	require.NoError(t, RegisterReloadHook(NewReloadHook("proxy", []string{"proxy"}, func(changed []string) error {
+		changedProxy = changed
+		return nil
+	})))
+	require.NoError(t, InitConfigReload(path))
+
+	// no change
+	result, err := ReloadConfig()
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Empty(t, result.RestartRequired)
+
+	writeConfigFile(t, path, `
+hostname: bar
+tags:
+  - team:b
+  - env:prod
+proxy:
+  https: http://proxy:3129
+`)
+
+	result, err = ReloadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"proxy.https", "tags"}, result.Applied)
+	assert.Equal(t, []string{"hostname"}, result.RestartRequired)
+	assert.Empty(t, result.Errors)
+
+	assert.Equal(t, []string{"tags"}, changedTags)
+	assert.Equal(t, []string{"proxy.https"}, changedProxy)
+	assert.Equal(t, []string{"team:b", "env:prod"}, config.Datadog.GetStringSlice("tags"))
+	assert.Equal(t, "http://proxy:3129", config.Datadog.GetString("proxy.https"))
+	assert.Equal(t, "", config.Datadog.GetString("hostname"))
+
+	// applied keys are not reported again, keys requiring a restart are
+	changedTags = nil
+	result, err = ReloadConfig()
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, []string{"hostname"}, result.RestartRequired)
+	assert.Nil(t, changedTags)
+}
+
+func TestReloadConfigHookError(t *testing.T) {
+	cleanReloadHooks()
+	config.Mock()
+
+	dir, err := ioutil.TempDir("", "reload")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datadog.yaml")
+
+	writeConfigFile(t, path, "log_level: info\n")
+	require.NoError(t, RegisterReloadHook(NewReloadHook("logger", []string{"log_level"}, func(_ []string) error {
+		return assert.AnError
+	})))
+	require.NoError(t, InitConfigReload(path))
+
+	writeConfigFile(t, path, "log_level: debug\n")
+	result, err := ReloadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log_level"}, result.Applied)
+	assert.Equal(t, map[string]string{"logger": assert.AnError.Error()}, result.Errors)
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// WatchConfigFile polls the configuration file given to InitConfigReload every
+// interval and reloads the configuration when the file changed, until ctx is done.
+func WatchConfigFile(ctx context.Context, interval time.Duration) {
+	reloadMutex.Lock()
+	path := reloadConfigFile
+	reloadMutex.Unlock()
+
+	lastModTime, lastSize := statConfigFile(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Infof("Watching %s for configuration changes every %s", path, interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			modTime, size := statConfigFile(path)
+			if modTime.Equal(lastModTime) && size == lastSize {
+				continue
+			}
+			lastModTime, lastSize = modTime, size
+
+			log.Infof("Configuration file %s changed, reloading it", path)
+			result, err := ReloadConfig()
+			if err != nil {
+				log.Errorf("Unable to reload the configuration: %v", err)
+				continue
+			}
+			log.Infof("Configuration reloaded, %d key(s) applied, %d key(s) requiring a restart", len(result.Applied), len(result.RestartRequired))
+		}
+	}
+}
+
+func statConfigFile(path string) (time.Time, int64) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		log.Debugf("Unable to stat configuration file %s: %v", path, err)
+		return time.Time{}, 0
+	}
+	return fi.ModTime(), fi.Size()
+}
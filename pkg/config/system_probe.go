@@ -119,12 +119,18 @@ func InitSystemProbeConfig(cfg Config) {
 	// network_config namespace only
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_http_monitoring"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_HTTP_MONITORING")
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_https_monitoring"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_HTTPS_MONITORING")
+	cfg.BindEnvAndSetDefault(join(netNS, "enable_http2_monitoring"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_HTTP2_MONITORING")
+	cfg.BindEnvAndSetDefault(join(netNS, "enable_protocol_classification"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_PROTOCOL_CLASSIFICATION")
+	cfg.BindEnvAndSetDefault(join(netNS, "enable_http_path_normalization"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_HTTP_PATH_NORMALIZATION")
+	cfg.SetKnown(join(netNS, "http_replace_rules"))
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_gateway_lookup"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_GATEWAY_LOOKUP")
 
 	// list of DNS query types to be recorded
 	cfg.BindEnvAndSetDefault(join(netNS, "dns_recorded_query_types"), []string{})
 	// (temporary) enable submitting DNS stats by query type.
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_by_querytype"), false)
+	// maximum number of failed DNS lookups per second sent to the stream of failed lookups, 0 disabling it
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_failed_lookups_rate_limit"), 0, "DD_SYSTEM_PROBE_NETWORK_DNS_FAILED_LOOKUPS_RATE_LIMIT")
 
 	// windows config
 	cfg.BindEnvAndSetDefault(join(spNS, "windows.enable_monotonic_count"), false)
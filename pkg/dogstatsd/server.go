@@ -142,6 +142,7 @@ type Server struct {
 	Debug                     *dsdServerDebug
 	TCapture                  *replay.TrafficCapture
 	mapper                    *mapper.MetricMapper
+	mapperMutex               sync.RWMutex
 	eolTerminationUDP         bool
 	eolTerminationUDS         bool
 	eolTerminationNamedPipe   bool
@@ -369,20 +370,34 @@ func NewServer(aggregator *aggregator.BufferedAggregator, extraTags []string) (*
 	// map some metric name
 	// ----------------------
 
+	if err := s.ReloadMapper(); err != nil {
+		log.Warnf("Could not set up the metric mapper: %v", err)
+	}
+	return s, nil
+}
+
+// ReloadMapper (re)creates the metric mapper from the mapping profiles of the
+// configuration. The current mapper is kept if the profiles are invalid.
+func (s *Server) ReloadMapper() error {
 	cacheSize := config.Datadog.GetInt("dogstatsd_mapper_cache_size")
 
 	mappings, err := config.GetDogstatsdMappingProfiles()
 	if err != nil {
-		log.Warnf("Could not parse mapping profiles: %v", err)
-	} else if len(mappings) != 0 {
-		mapperInstance, err := mapper.NewMetricMapper(mappings, cacheSize)
+		return fmt.Errorf("could not parse mapping profiles: %v", err)
+	}
+
+	var mapperInstance *mapper.MetricMapper
+	if len(mappings) != 0 {
+		mapperInstance, err = mapper.NewMetricMapper(mappings, cacheSize)
 		if err != nil {
-			log.Warnf("Could not create metric mapper: %v", err)
-		} else {
-			s.mapper = mapperInstance
+			return fmt.Errorf("could not create metric mapper: %v", err)
 		}
 	}
-	return s, nil
+
+	s.mapperMutex.Lock()
+	s.mapper = mapperInstance
+	s.mapperMutex.Unlock()
+	return nil
 }
 
 func (s *Server) handleMessages() {
@@ -624,8 +639,11 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		return metricSamples, err
 	}
 
-	if s.mapper != nil {
-		mapResult := s.mapper.Map(sample.name)
+	s.mapperMutex.RLock()
+	metricMapper := s.mapper
+	s.mapperMutex.RUnlock()
+	if metricMapper != nil {
+		mapResult := metricMapper.Map(sample.name)
 		if mapResult != nil {
 			log.Tracef("Dogstatsd mapper: metric mapped from %q to %q with tags %v", sample.name, mapResult.Name, mapResult.Tags)
 			sample.name = mapResult.Name
@@ -815,6 +815,46 @@ dogstatsd_mapper_profiles:
 	}
 }
 
+func TestReloadMapper(t *testing.T) {
+	config.Datadog.SetConfigType("yaml")
+	err := config.Datadog.ReadConfig(strings.NewReader(""))
+	require.NoError(t, err)
+
+	port, err := getAvailableUDPPort()
+	require.NoError(t, err)
+	config.Datadog.SetDefault("dogstatsd_port", port)
+
+	s, err := NewServer(mockAggregator(), nil)
+	require.NoError(t, err, "cannot start DSD")
+	defer s.Stop()
+	assert.Nil(t, s.mapper)
+
+	err = config.Datadog.ReadConfig(strings.NewReader(`
+dogstatsd_mapper_profiles:
+  - name: test
+    prefix: 'test.'
+    mappings:
+      - match: "test.job.duration.*"
+        name: "test.job.duration"
+        tags:
+          job_type: "$1"
+`))
+	require.NoError(t, err)
+	require.NoError(t, s.ReloadMapper())
+
+	parser := newParser(newFloat64ListPool())
+	samples, err := s.parseMetricMessage(nil, parser, []byte("test.job.duration.my_job_type:666|g"), "", false)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "test.job.duration", samples[0].Name)
+	assert.Equal(t, []string{"job_type:my_job_type"}, samples[0].Tags)
+
+	err = config.Datadog.ReadConfig(strings.NewReader(""))
+	require.NoError(t, err)
+	require.NoError(t, s.ReloadMapper())
+	assert.Nil(t, s.mapper)
+}
+
 func TestNewServerExtraTags(t *testing.T) {
 	require := require.New(t)
 	port, err := getAvailableUDPPort()
@@ -25,6 +25,18 @@ const (
 
 	// EventTypeNetworkDevicesMetadata is the event type for network devices metadata
 	EventTypeNetworkDevicesMetadata = "network-devices-metadata"
+
+	// EventTypeNetworkDevicesNetFlow is the event type for network devices NetFlow data
+	EventTypeNetworkDevicesNetFlow = "network-devices-netflow"
+
+	// EventTypeKubernetesEvents is the event type for Kubernetes events sent as logs
+	EventTypeKubernetesEvents = "kubernetes-events"
+
+	// EventTypeProcessEvents is the event type for process lifecycle events
+	EventTypeProcessEvents = "process-events"
+
+	// EventTypeProcessDiscovery is the event type for the inventory of the services discovered from the processes
+	EventTypeProcessDiscovery = "process-discovery"
 )
 
 var passthroughPipelineDescs = []passthroughPipelineDesc{
@@ -66,6 +78,45 @@ var passthroughPipelineDescs = []passthroughPipelineDesc{
 		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
 		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
 	},
+	{
+		eventType:                     EventTypeNetworkDevicesNetFlow,
+		endpointsConfigPrefix:         "network_devices.netflow.forwarder.",
+		hostnameEndpointPrefix:        "ndmflow-intake.",
+		defaultBatchMaxConcurrentSend: 10,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
+}
+
+// dedicatedPipelineDescs are the pipelines that the default forwarder doesn't start, they are only started by
+// the forwarders created for their event type with NewEventPlatformForwarderWithPipelines, or lazily when their
+// first event is sent.
+var dedicatedPipelineDescs = []passthroughPipelineDesc{
+	{
+		// Kubernetes events are sent to the logs intake, using the logs endpoints configuration
+		eventType:                     EventTypeKubernetesEvents,
+		endpointsConfigPrefix:         "logs_config.",
+		hostnameEndpointPrefix:        "agent-http-intake.logs.",
+		defaultBatchMaxConcurrentSend: pkgconfig.DefaultBatchMaxConcurrentSend,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
+	{
+		eventType:                     EventTypeProcessEvents,
+		endpointsConfigPrefix:         "process_config.event_collection.forwarder.",
+		hostnameEndpointPrefix:        "process-events-intake.",
+		defaultBatchMaxConcurrentSend: pkgconfig.DefaultBatchMaxConcurrentSend,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
+	{
+		eventType:                     EventTypeProcessDiscovery,
+		endpointsConfigPrefix:         "process_config.process_discovery.forwarder.",
+		hostnameEndpointPrefix:        "process-discovery-intake.",
+		defaultBatchMaxConcurrentSend: pkgconfig.DefaultBatchMaxConcurrentSend,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
 }
 
 // An EventPlatformForwarder forwards Messages to a destination based on their event type
@@ -80,11 +131,16 @@ type defaultEventPlatformForwarder struct {
 	purgeMx         sync.Mutex
 	pipelines       map[string]*passthroughPipeline
 	destinationsCtx *client.DestinationsContext
+	// lazy runs the pipelines which are only created when their first event is sent, it may be nil
+	lazy *lazyEventPlatformForwarder
 }
 
 func (s *defaultEventPlatformForwarder) SendEventPlatformEvent(e *message.Message, eventType string) error {
 	p, ok := s.pipelines[eventType]
 	if !ok {
+		if s.lazy != nil {
+			return s.lazy.SendEventPlatformEvent(e, eventType)
+		}
 		return fmt.Errorf("unknown eventType=%s", eventType)
 	}
 	select {
@@ -117,6 +173,11 @@ func (s *defaultEventPlatformForwarder) Purge() map[string][]*message.Message {
 	for eventType, p := range s.pipelines {
 		result[eventType] = purgeChan(p.in)
 	}
+	if s.lazy != nil {
+		for eventType, msgs := range s.lazy.Purge() {
+			result[eventType] = msgs
+		}
+	}
 	return result
 }
 
@@ -133,6 +194,9 @@ func (s *defaultEventPlatformForwarder) Stop() {
 	for _, p := range s.pipelines {
 		stopper.Add(p)
 	}
+	if s.lazy != nil {
+		stopper.Add(s.lazy)
+	}
 	stopper.Stop()
 	// TODO: wait on stop and cancel context only after timeout like logs agent
 	s.destinationsCtx.Stop()
@@ -216,10 +280,14 @@ func joinHosts(endpoints []config.Endpoint) string {
 }
 
 func newDefaultEventPlatformForwarder() *defaultEventPlatformForwarder {
+	return newEventPlatformForwarder(passthroughPipelineDescs)
+}
+
+func newEventPlatformForwarder(descs []passthroughPipelineDesc) *defaultEventPlatformForwarder {
 	destinationsCtx := client.NewDestinationsContext()
 	destinationsCtx.Start()
 	pipelines := make(map[string]*passthroughPipeline)
-	for _, desc := range passthroughPipelineDescs {
+	for _, desc := range descs {
 		p, err := newHTTPPassthroughPipeline(desc, destinationsCtx)
 		if err != nil {
 			log.Errorf("Failed to initialize event platform forwarder pipeline. eventType=%s, error=%s", desc.eventType, err.Error())
@@ -233,15 +301,113 @@ func newDefaultEventPlatformForwarder() *defaultEventPlatformForwarder {
 	}
 }
 
-// NewEventPlatformForwarder creates a new EventPlatformForwarder
+// NewEventPlatformForwarder creates a new EventPlatformForwarder. Besides the default pipelines, it runs the
+// Kubernetes events pipeline, created when the first event is sent, as the kubernetes_apiserver check can send
+// its events as logs from the node agents and the cluster check runners.
 func NewEventPlatformForwarder() EventPlatformForwarder {
-	return newDefaultEventPlatformForwarder()
+	f := newDefaultEventPlatformForwarder()
+	f.lazy = newLazyEventPlatformForwarder(EventTypeKubernetesEvents)
+	return f
 }
 
-// NewNoopEventPlatformForwarder returns the standard event platform forwarder with sending disabled, meaning events
-// will build up in each pipeline channel without being forwarded to the intake
+// NewEventPlatformForwarderWithPipelines creates a new EventPlatformForwarder only running the pipelines of the
+// given event types, instead of all the pipelines of the default forwarder
+func NewEventPlatformForwarderWithPipelines(eventTypes ...string) EventPlatformForwarder {
+	return newEventPlatformForwarder(pipelineDescs(eventTypes))
+}
+
+// pipelineDescs returns the descriptions of the pipelines of the given event types
+func pipelineDescs(eventTypes []string) []passthroughPipelineDesc {
+	var descs []passthroughPipelineDesc
+	for _, eventType := range eventTypes {
+		found := false
+		for _, desc := range append(passthroughPipelineDescs, dedicatedPipelineDescs...) {
+			if desc.eventType == eventType {
+				descs = append(descs, desc)
+				found = true
+				break
+			}
+		}
+		if !found {
+			log.Errorf("Unknown event platform forwarder pipeline. eventType=%s", eventType)
+		}
+	}
+	return descs
+}
+
+// NewLazyEventPlatformForwarder creates a new EventPlatformForwarder only running the pipelines of the given event
+// types, which are created and started when the first event is sent. It suits the agents that can't tell at startup
+// whether they will send events, e.g. because it depends on the configuration of a check.
+func NewLazyEventPlatformForwarder(eventTypes ...string) EventPlatformForwarder {
+	return newLazyEventPlatformForwarder(eventTypes...)
+}
+
+func newLazyEventPlatformForwarder(eventTypes ...string) *lazyEventPlatformForwarder {
+	return &lazyEventPlatformForwarder{descs: pipelineDescs(eventTypes)}
+}
+
+type lazyEventPlatformForwarder struct {
+	mu        sync.Mutex
+	descs     []passthroughPipelineDesc
+	forwarder *defaultEventPlatformForwarder
+	stopped   bool
+}
+
+func (l *lazyEventPlatformForwarder) SendEventPlatformEvent(e *message.Message, eventType string) error {
+	known := false
+	for _, desc := range l.descs {
+		if desc.eventType == eventType {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("unknown eventType=%s", eventType)
+	}
+
+	l.mu.Lock()
+	if l.forwarder == nil && !l.stopped {
+		log.Debugf("starting event platform forwarder for eventType=%s", eventType)
+		l.forwarder = newEventPlatformForwarder(l.descs)
+		l.forwarder.Start()
+	}
+	forwarder := l.forwarder
+	l.mu.Unlock()
+
+	if forwarder == nil {
+		return fmt.Errorf("event platform forwarder is stopped, dropping event for eventType=%s", eventType)
+	}
+	return forwarder.SendEventPlatformEvent(e, eventType)
+}
+
+func (l *lazyEventPlatformForwarder) Purge() map[string][]*message.Message {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.forwarder == nil {
+		return make(map[string][]*message.Message)
+	}
+	return l.forwarder.Purge()
+}
+
+// Start is a no-op, the pipelines are started when the first event is sent
+func (l *lazyEventPlatformForwarder) Start() {}
+
+func (l *lazyEventPlatformForwarder) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = true
+	if l.forwarder != nil {
+		l.forwarder.Stop()
+	}
+}
+
+// NewNoopEventPlatformForwarder returns an event platform forwarder running the pipelines of all the event types,
+// including the dedicated ones, with sending disabled, meaning events will build up in each pipeline channel without
+// being forwarded to the intake
 func NewNoopEventPlatformForwarder() EventPlatformForwarder {
-	f := newDefaultEventPlatformForwarder()
+	descs := make([]passthroughPipelineDesc, 0, len(passthroughPipelineDescs)+len(dedicatedPipelineDescs))
+	descs = append(descs, passthroughPipelineDescs...)
+	f := newEventPlatformForwarder(append(descs, dedicatedPipelineDescs...))
 	// remove the senders
 	for _, p := range f.pipelines {
 		p.sender = nil
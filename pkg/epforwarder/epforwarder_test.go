@@ -0,0 +1,83 @@
+package epforwarder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func pipelineEventTypes(f *defaultEventPlatformForwarder) []string {
+	var eventTypes []string
+	for eventType := range f.pipelines {
+		eventTypes = append(eventTypes, eventType)
+	}
+	return eventTypes
+}
+
+func TestDefaultForwarderSkipsDedicatedPipelines(t *testing.T) {
+	f := newDefaultEventPlatformForwarder()
+
+	eventTypes := pipelineEventTypes(f)
+	assert.Contains(t, eventTypes, EventTypeNetworkDevicesMetadata)
+	for _, desc := range dedicatedPipelineDescs {
+		assert.NotContains(t, eventTypes, desc.eventType)
+	}
+}
+
+func TestNewEventPlatformForwarderWithPipelines(t *testing.T) {
+	f, ok := NewEventPlatformForwarderWithPipelines(EventTypeKubernetesEvents, "unknown").(*defaultEventPlatformForwarder)
+	require.True(t, ok)
+
+	assert.ElementsMatch(t, []string{EventTypeKubernetesEvents}, pipelineEventTypes(f))
+	assert.Error(t, f.SendEventPlatformEvent(message.NewMessage([]byte("{}"), nil, "", 0), EventTypeNetworkDevicesMetadata))
+}
+
+func TestLazyEventPlatformForwarder(t *testing.T) {
+	mockConfig := coreConfig.Mock()
+	mockConfig.Set("logs_config.logs_dd_url", "localhost:1")
+	defer mockConfig.Set("logs_config.logs_dd_url", "")
+
+	f, ok := NewLazyEventPlatformForwarder(EventTypeKubernetesEvents).(*lazyEventPlatformForwarder)
+	require.True(t, ok)
+	f.Start()
+
+	// nothing is created until an event is sent
+	assert.Empty(t, f.Purge())
+	assert.Error(t, f.SendEventPlatformEvent(message.NewMessage([]byte("{}"), nil, "", 0), EventTypeNetworkDevicesMetadata))
+	assert.Nil(t, f.forwarder)
+
+	assert.NoError(t, f.SendEventPlatformEvent(message.NewMessage([]byte("{}"), nil, "", 0), EventTypeKubernetesEvents))
+	require.NotNil(t, f.forwarder)
+	assert.ElementsMatch(t, []string{EventTypeKubernetesEvents}, pipelineEventTypes(f.forwarder))
+}
+
+func TestLazyEventPlatformForwarderStopped(t *testing.T) {
+	f, ok := NewLazyEventPlatformForwarder(EventTypeKubernetesEvents).(*lazyEventPlatformForwarder)
+	require.True(t, ok)
+	f.Stop()
+
+	assert.Error(t, f.SendEventPlatformEvent(message.NewMessage([]byte("{}"), nil, "", 0), EventTypeKubernetesEvents))
+	assert.Nil(t, f.forwarder)
+}
+
+func TestEventPlatformForwarderCreatesKubernetesEventsPipeline(t *testing.T) {
+	mockConfig := coreConfig.Mock()
+	mockConfig.Set("logs_config.logs_dd_url", "localhost:1")
+	defer mockConfig.Set("logs_config.logs_dd_url", "")
+
+	f, ok := NewEventPlatformForwarder().(*defaultEventPlatformForwarder)
+	require.True(t, ok)
+	require.NotNil(t, f.lazy)
+	assert.NotContains(t, pipelineEventTypes(f), EventTypeKubernetesEvents)
+	assert.Nil(t, f.lazy.forwarder)
+
+	// the node agents and the cluster check runners can send the events of the kubernetes_apiserver check as logs
+	assert.NoError(t, f.SendEventPlatformEvent(message.NewMessage([]byte("{}"), nil, "", 0), EventTypeKubernetesEvents))
+	require.NotNil(t, f.lazy.forwarder)
+	assert.ElementsMatch(t, []string{EventTypeKubernetesEvents}, pipelineEventTypes(f.lazy.forwarder))
+	assert.Error(t, f.SendEventPlatformEvent(message.NewMessage([]byte("{}"), nil, "", 0), EventTypeProcessEvents))
+}
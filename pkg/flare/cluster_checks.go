@@ -88,9 +88,9 @@ func GetClusterChecks(w io.Writer) error {
 	fmt.Fprintln(w, fmt.Sprintf("=== %d agents reporting ===", len(cr.Nodes)))
 	sort.Slice(cr.Nodes, func(i, j int) bool { return cr.Nodes[i].Name < cr.Nodes[j].Name })
 	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(table, "\nName\tRunning checks")
+	fmt.Fprintln(table, "\nName\tRunning checks\tBusyness")
 	for _, n := range cr.Nodes {
-		fmt.Fprintf(table, "%s\t%d\n", n.Name, len(n.Configs))
+		fmt.Fprintf(table, "%s\t%d\t%s\n", n.Name, len(n.Configs), formatBusyness(n.Busyness))
 	}
 	table.Flush()
 
@@ -103,11 +103,34 @@ func GetClusterChecks(w io.Writer) error {
 		for _, c := range node.Configs {
 			PrintConfig(w, c)
 		}
+		printCheckWeights(w, node.CheckWeights)
 	}
 
 	return nil
 }
 
+// formatBusyness returns the busyness of a node, n/a if no stats were collected yet
+func formatBusyness(busyness int) string {
+	if busyness < 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%d", busyness)
+}
+
+// printCheckWeights prints the weights of the checks running on a node
+func printCheckWeights(w io.Writer, weights []types.CheckWeight) {
+	if len(weights) == 0 {
+		return
+	}
+	fmt.Fprintln(w, fmt.Sprintf("\n=== %s ===", color.BlueString("Check weights")))
+	table := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(table, "Check ID\tAvg execution time (ms)\tMetric samples\tCluster check\tWeight")
+	for _, c := range weights {
+		fmt.Fprintf(table, "%s\t%d\t%d\t%t\t%d\n", c.CheckID, c.AverageExecutionTime, c.MetricSamples, c.IsClusterCheck, c.Weight)
+	}
+	table.Flush()
+}
+
 // GetEndpointsChecks dumps the endpointschecks dispatching state to the writer
 func GetEndpointsChecks(w io.Writer) error {
 	if !endpointschecksEnabled() {
@@ -169,6 +169,18 @@ func (f *domainForwarder) scheduleConnectionResets() {
 	}
 }
 
+// scheduleConnectionReset signals the workers to recreate their connections to DD
+// before sending their next transaction
+func (f *domainForwarder) scheduleConnectionReset() {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	log.Debugf("Scheduling reset of connections used for domain: %q", f.domain)
+	for _, worker := range f.workers {
+		worker.ScheduleConnectionReset()
+	}
+}
+
 func (f *domainForwarder) init() {
 	highPrioBuffSize := config.Datadog.GetInt("forwarder_high_prio_buffer_size")
 	lowPrioBuffSize := config.Datadog.GetInt("forwarder_low_prio_buffer_size")
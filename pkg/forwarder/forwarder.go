@@ -308,6 +308,17 @@ func (f *DefaultForwarder) Start() error {
 	return nil
 }
 
+// ScheduleConnectionReset signals all the workers that their connections should be
+// recreated before sending their next transaction, e.g. to apply new proxy settings.
+func (f *DefaultForwarder) ScheduleConnectionReset() {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	for _, df := range f.domainForwarders {
+		df.scheduleConnectionReset()
+	}
+}
+
 // Stop all the component of a forwarder and free resources
 func (f *DefaultForwarder) Stop() {
 	log.Infof("stopping the Forwarder")
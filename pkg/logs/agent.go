@@ -162,6 +162,11 @@ func (a *Agent) Flush(ctx context.Context) {
 	a.pipelineProvider.Flush(ctx)
 }
 
+// SetProcessingRules replaces the global processing rules applied by the pipelines.
+func (a *Agent) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	a.pipelineProvider.SetProcessingRules(processingRules)
+}
+
 // Stop stops all the elements of the data pipeline
 // in the right order to prevent data loss
 func (a *Agent) Stop() {
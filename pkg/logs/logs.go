@@ -196,6 +196,19 @@ func Flush(ctx context.Context) {
 	log.Debug("Flush in the logs-agent done.")
 }
 
+// ReloadProcessingRules reads the global processing rules from the configuration
+// again and applies them to the running instance of the Logs Agent.
+func ReloadProcessingRules() error {
+	processingRules, err := config.GlobalProcessingRules()
+	if err != nil {
+		return fmt.Errorf("invalid processing rules: %v", err)
+	}
+	if IsAgentRunning() && agent != nil {
+		agent.SetProcessingRules(processingRules)
+	}
+	return nil
+}
+
 // IsAgentRunning returns true if the logs-agent is running.
 func IsAgentRunning() bool {
 	return status.Get().IsRunning
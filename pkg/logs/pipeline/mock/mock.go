@@ -8,6 +8,7 @@ package mock
 import (
 	"context"
 
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
 )
@@ -37,3 +38,6 @@ func (p *mockProvider) Flush(ctx context.Context) {}
 func (p *mockProvider) NextPipelineChan() chan *message.Message {
 	return p.msgChan
 }
+
+// SetProcessingRules does nothing
+func (p *mockProvider) SetProcessingRules(processingRules []*config.ProcessingRule) {}
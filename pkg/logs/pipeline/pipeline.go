@@ -87,6 +87,11 @@ func (p *Pipeline) Stop() {
 	p.sender.Stop()
 }
 
+// SetProcessingRules replaces the global processing rules of the pipeline processor.
+func (p *Pipeline) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.processor.SetProcessingRules(processingRules)
+}
+
 // Flush flushes synchronously the processor and sender managed by this pipeline.
 func (p *Pipeline) Flush(ctx context.Context) {
 	p.processor.Flush(ctx) // flush messages in the processor into the sender
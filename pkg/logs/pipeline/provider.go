@@ -7,6 +7,7 @@ package pipeline
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 
 	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
@@ -25,6 +26,8 @@ type Provider interface {
 	NextPipelineChan() chan *message.Message
 	// Flush flushes all pipeline contained in this Provider
 	Flush(ctx context.Context)
+	// SetProcessingRules replaces the global processing rules of all pipelines
+	SetProcessingRules(processingRules []*config.ProcessingRule)
 }
 
 // provider implements providing logic
@@ -33,9 +36,13 @@ type provider struct {
 	auditor                   auditor.Auditor
 	diagnosticMessageReceiver diagnostic.MessageReceiver
 	outputChan                chan *message.Message
-	processingRules           []*config.ProcessingRule
 	endpoints                 *config.Endpoints
 
+	// rulesMu protects processingRules, and the pipelines while they are started and stopped,
+	// as the rules can be replaced at any time by a config reload
+	rulesMu         sync.Mutex
+	processingRules []*config.ProcessingRule
+
 	pipelines            []*Pipeline
 	currentPipelineIndex int32
 	destinationsContext  *client.DestinationsContext
@@ -71,6 +78,8 @@ func (p *provider) Start() {
 	// This requires the auditor to be started before.
 	p.outputChan = p.auditor.Channel()
 
+	p.rulesMu.Lock()
+	defer p.rulesMu.Unlock()
 	for i := 0; i < p.numberOfPipelines; i++ {
 		pipeline := NewPipeline(p.outputChan, p.processingRules, p.endpoints, p.destinationsContext, p.diagnosticMessageReceiver, p.serverless)
 		pipeline.Start()
@@ -81,6 +90,8 @@ func (p *provider) Start() {
 // Stop stops all pipelines in parallel,
 // this call blocks until all pipelines are stopped
 func (p *provider) Stop() {
+	p.rulesMu.Lock()
+	defer p.rulesMu.Unlock()
 	stopper := restart.NewParallelStopper()
 	for _, pipeline := range p.pipelines {
 		stopper.Add(pipeline)
@@ -113,3 +124,14 @@ func (p *provider) Flush(ctx context.Context) {
 		}
 	}
 }
+
+// SetProcessingRules replaces the global processing rules of the running pipelines
+// and of the ones started later on.
+func (p *provider) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.rulesMu.Lock()
+	defer p.rulesMu.Unlock()
+	p.processingRules = processingRules
+	for _, pipeline := range p.pipelines {
+		pipeline.SetProcessingRules(processingRules)
+	}
+}
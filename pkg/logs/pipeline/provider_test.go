@@ -25,7 +25,7 @@ type ProviderTestSuite struct {
 }
 
 func (suite *ProviderTestSuite) SetupTest() {
-	suite.a = auditor.New("", auditor.DefaultRegistryFilename, time.Hour, health.RegisterLiveness("fake"))
+	suite.a = auditor.New(suite.T().TempDir(), auditor.DefaultRegistryFilename, time.Hour, health.RegisterLiveness("fake"))
 	suite.p = &provider{
 		numberOfPipelines: 3,
 		auditor:           suite.a,
@@ -56,6 +56,25 @@ func (suite *ProviderTestSuite) TestProvider() {
 	suite.Nil(suite.p.NextPipelineChan())
 }
 
+func (suite *ProviderTestSuite) TestSetProcessingRulesWhileStarting() {
+	rules := []*config.ProcessingRule{{Type: config.ExcludeAtMatch, Name: "exclude", Pattern: "foo"}}
+
+	suite.a.Start()
+	done := make(chan struct{})
+	go func() {
+		suite.p.SetProcessingRules(rules)
+		close(done)
+	}()
+	suite.p.Start()
+	<-done
+
+	suite.Equal(rules, suite.p.processingRules)
+	suite.Equal(3, len(suite.p.pipelines))
+
+	suite.p.Stop()
+	suite.a.Stop()
+}
+
 func TestProviderTestSuite(t *testing.T) {
 	suite.Run(t, new(ProviderTestSuite))
 }
@@ -27,6 +27,7 @@ type Processor struct {
 	done                      chan struct{}
 	diagnosticMessageReceiver diagnostic.MessageReceiver
 	mu                        sync.Mutex
+	rulesMu                   sync.RWMutex // protects processingRules
 }
 
 // New returns an initialized Processor.
@@ -53,6 +54,13 @@ func (p *Processor) Stop() {
 	<-p.done
 }
 
+// SetProcessingRules replaces the global processing rules applied to the messages.
+func (p *Processor) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.rulesMu.Lock()
+	defer p.rulesMu.Unlock()
+	p.processingRules = processingRules
+}
+
 // Flush processes synchronously the messages that this processor has to process.
 func (p *Processor) Flush(ctx context.Context) {
 	p.mu.Lock()
@@ -107,7 +115,9 @@ func (p *Processor) processMessage(msg *message.Message) {
 // and a copy of the message with some fields redacted, depending on config
 func (p *Processor) applyRedactingRules(msg *message.Message) (bool, []byte) {
 	content := msg.Content
+	p.rulesMu.RLock()
 	rules := append(p.processingRules, msg.Origin.LogSource.Config.ProcessingRules...)
+	p.rulesMu.RUnlock()
 	for _, rule := range rules {
 		switch rule.Type {
 		case config.ExcludeAtMatch:
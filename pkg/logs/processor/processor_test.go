@@ -61,6 +61,22 @@ func TestInclusion(t *testing.T) {
 	assert.Nil(t, redactedMessage)
 }
 
+func TestSetProcessingRules(t *testing.T) {
+	p := &Processor{}
+
+	source := config.LogSource{Config: &config.LogsConfig{}}
+	shouldProcess, _ := p.applyRedactingRules(newMessage([]byte("hello"), &source, ""))
+	assert.Equal(t, true, shouldProcess)
+
+	p.SetProcessingRules([]*config.ProcessingRule{newProcessingRule("exclude_at_match", "", "hello")})
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("hello"), &source, ""))
+	assert.Equal(t, false, shouldProcess)
+
+	p.SetProcessingRules(nil)
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("hello"), &source, ""))
+	assert.Equal(t, true, shouldProcess)
+}
+
 func TestExclusionWithInclusion(t *testing.T) {
 	eRule := newProcessingRule("exclude_at_match", "", "^bob")
 	iRule := newProcessingRule("include_at_match", "", ".*@datadoghq.com$")
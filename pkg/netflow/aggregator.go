@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	snmpinterfaces "github.com/DataDog/datadog-agent/pkg/snmp/interfaces"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// flowKey identifies the flows aggregated together
+type flowKey struct {
+	flowType        FlowType
+	exporter        string
+	srcAddr         string
+	dstAddr         string
+	srcPort         uint16
+	dstPort         uint16
+	ipProtocol      uint8
+	inputInterface  uint32
+	outputInterface uint32
+}
+
+func newFlowKey(flow *Flow) flowKey {
+	return flowKey{
+		flowType:        flow.FlowType,
+		exporter:        flow.ExporterAddr.String(),
+		srcAddr:         flow.SrcAddr.String(),
+		dstAddr:         flow.DstAddr.String(),
+		srcPort:         flow.SrcPort,
+		dstPort:         flow.DstPort,
+		ipProtocol:      flow.IPProtocol,
+		inputInterface:  flow.InputInterface,
+		outputInterface: flow.OutputInterface,
+	}
+}
+
+// flowAggregator aggregates the flows received over a flush interval by 5-tuple,
+// exporter and interfaces, and sends them to the event platform forwarder
+type flowAggregator struct {
+	flowIn        chan *Flow
+	flows         map[flowKey]*Flow
+	maxFlows      int
+	flushInterval time.Duration
+	sender        epforwarder.EventPlatformForwarder
+	hostname      string
+
+	stopChan chan struct{}
+	stopped  sync.WaitGroup
+}
+
+func newFlowAggregator(sender epforwarder.EventPlatformForwarder, config *Config, hostname string) *flowAggregator {
+	return &flowAggregator{
+		flowIn:        make(chan *Flow, flowsChanSize),
+		flows:         make(map[flowKey]*Flow),
+		maxFlows:      config.AggregatorMaxFlows,
+		flushInterval: config.FlushInterval(),
+		sender:        sender,
+		hostname:      hostname,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+func (agg *flowAggregator) start() {
+	agg.stopped.Add(1)
+	go agg.run()
+}
+
+// stop stops the aggregator once the received flows are flushed
+func (agg *flowAggregator) stop() {
+	close(agg.stopChan)
+	agg.stopped.Wait()
+}
+
+func (agg *flowAggregator) run() {
+	defer agg.stopped.Done()
+
+	ticker := time.NewTicker(agg.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case flow := <-agg.flowIn:
+			agg.add(flow)
+		case now := <-ticker.C:
+			agg.flush(now)
+		case <-agg.stopChan:
+			// the flows already received are aggregated before the last flush
+			for len(agg.flowIn) > 0 {
+				agg.add(<-agg.flowIn)
+			}
+			agg.flush(time.Now())
+			return
+		}
+	}
+}
+
+func (agg *flowAggregator) add(flow *Flow) {
+	key := newFlowKey(flow)
+	aggFlow, ok := agg.flows[key]
+	if !ok {
+		if len(agg.flows) >= agg.maxFlows {
+			netflowFlowsDropped.Add(1)
+			return
+		}
+		agg.flows[key] = flow
+		return
+	}
+
+	aggFlow.Bytes += flow.Bytes
+	aggFlow.Packets += flow.Packets
+	aggFlow.TCPFlags |= flow.TCPFlags
+	if flow.StartTimestamp < aggFlow.StartTimestamp {
+		aggFlow.StartTimestamp = flow.StartTimestamp
+	}
+	if flow.EndTimestamp > aggFlow.EndTimestamp {
+		aggFlow.EndTimestamp = flow.EndTimestamp
+	}
+}
+
+// flush sends the aggregated flows and resets the aggregation, it returns the number of flows sent
+func (agg *flowAggregator) flush(now time.Time) int {
+	flows := agg.flows
+	agg.flows = make(map[flowKey]*Flow)
+
+	sent := 0
+	for _, flow := range flows {
+		payloadBytes, err := json.Marshal(agg.buildPayload(flow, now))
+		if err != nil {
+			log.Errorf("Error marshalling flow: %s", err)
+			continue
+		}
+		if err := agg.send(&message.Message{Content: payloadBytes}); err != nil {
+			log.Warnf("Error sending flows, dropping %d flows: %s", len(flows)-sent, err)
+			netflowFlowsDropped.Add(int64(len(flows) - sent))
+			break
+		}
+		sent++
+	}
+	netflowFlowsFlushed.Add(int64(sent))
+	log.Debugf("Flushed %d flows", sent)
+	return sent
+}
+
+// send sends a flow to the event platform forwarder, waiting for its pipeline to make room
+// for it as the flows of a flush usually outnumber the capacity of the pipeline
+func (agg *flowAggregator) send(m *message.Message) error {
+	var err error
+	for i := 0; i < sendMaxRetries; i++ {
+		if err = agg.sender.SendEventPlatformEvent(m, epforwarder.EventTypeNetworkDevicesNetFlow); err == nil {
+			return nil
+		}
+		time.Sleep(sendRetryInterval)
+	}
+	return err
+}
+
+func (agg *flowAggregator) buildPayload(flow *Flow, now time.Time) FlowPayload {
+	exporter := flow.ExporterAddr.String()
+	return FlowPayload{
+		FlushTimestamp: now.UnixNano() / int64(time.Millisecond),
+		FlowType:       flow.FlowType,
+		Host:           agg.hostname,
+		Exporter:       Exporter{IP: exporter},
+		Start:          flow.StartTimestamp,
+		End:            flow.EndTimestamp,
+		Bytes:          flow.Bytes,
+		Packets:        flow.Packets,
+		IPProtocol:     flow.IPProtocol,
+		TCPFlags:       flow.TCPFlags,
+		Source:         Endpoint{IP: ipString(flow.SrcAddr), Port: flow.SrcPort},
+		Destination:    Endpoint{IP: ipString(flow.DstAddr), Port: flow.DstPort},
+		Ingress:        ObservationPoint{Interface: newInterface(exporter, flow.InputInterface)},
+		Egress:         ObservationPoint{Interface: newInterface(exporter, flow.OutputInterface)},
+	}
+}
+
+// newInterface returns the interface of an exporter, named after the interfaces reported by the SNMP check
+func newInterface(exporter string, index uint32) Interface {
+	name, _ := snmpinterfaces.DefaultStore.GetInterfaceName(exporter, int32(index))
+	return Interface{Index: index, Name: name}
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	snmpinterfaces "github.com/DataDog/datadog-agent/pkg/snmp/interfaces"
+)
+
+func newTestFlow(srcPort uint16, bytes uint64, start uint64, tcpFlags uint8) *Flow {
+	return &Flow{
+		FlowType:        TypeNetFlow9,
+		ExporterAddr:    net.ParseIP("10.0.0.1").To4(),
+		StartTimestamp:  start,
+		EndTimestamp:    start + 10,
+		Bytes:           bytes,
+		Packets:         1,
+		SrcAddr:         net.ParseIP("192.168.1.10").To4(),
+		DstAddr:         net.ParseIP("192.168.2.20").To4(),
+		SrcPort:         srcPort,
+		DstPort:         443,
+		IPProtocol:      6,
+		TCPFlags:        tcpFlags,
+		InputInterface:  1,
+		OutputInterface: 2,
+	}
+}
+
+func purgeFlowPayloads(t *testing.T, sender epforwarder.EventPlatformForwarder) []FlowPayload {
+	var payloads []FlowPayload
+	for _, m := range sender.Purge()[epforwarder.EventTypeNetworkDevicesNetFlow] {
+		var payload FlowPayload
+		require.NoError(t, json.Unmarshal(m.Content, &payload))
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+func TestAggregator(t *testing.T) {
+	snmpinterfaces.DefaultStore.SetDeviceInterfaces("10.0.0.1", map[int32]string{1: "eth0"})
+	defer snmpinterfaces.DefaultStore.SetDeviceInterfaces("10.0.0.1", nil)
+
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	agg := newFlowAggregator(sender, &Config{AggregatorMaxFlows: 10, AggregatorFlushInterval: 300}, "my-host")
+
+	agg.add(newTestFlow(40000, 100, 1000, 0x02))
+	agg.add(newTestFlow(40000, 200, 990, 0x10))
+	agg.add(newTestFlow(40001, 300, 1000, 0))
+
+	assert.Equal(t, 2, agg.flush(time.Unix(2000, 0)))
+	assert.Empty(t, agg.flows)
+
+	payloads := purgeFlowPayloads(t, sender)
+	require.Len(t, payloads, 2)
+	if payloads[0].Source.Port != 40000 {
+		payloads[0], payloads[1] = payloads[1], payloads[0]
+	}
+
+	assert.Equal(t, FlowPayload{
+		FlushTimestamp: 2000000,
+		FlowType:       TypeNetFlow9,
+		Host:           "my-host",
+		Exporter:       Exporter{IP: "10.0.0.1"},
+		Start:          990,
+		End:            1010,
+		Bytes:          300,
+		Packets:        2,
+		IPProtocol:     6,
+		TCPFlags:       0x12,
+		Source:         Endpoint{IP: "192.168.1.10", Port: 40000},
+		Destination:    Endpoint{IP: "192.168.2.20", Port: 443},
+		Ingress:        ObservationPoint{Interface: Interface{Index: 1, Name: "eth0"}},
+		Egress:         ObservationPoint{Interface: Interface{Index: 2}},
+	}, payloads[0])
+	assert.Equal(t, uint64(300), payloads[1].Bytes)
+}
+
+func TestAggregatorMaxFlows(t *testing.T) {
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	agg := newFlowAggregator(sender, &Config{AggregatorMaxFlows: 1, AggregatorFlushInterval: 300}, "my-host")
+
+	dropped := netflowFlowsDropped.Value()
+	agg.add(newTestFlow(40000, 100, 1000, 0))
+	agg.add(newTestFlow(40001, 100, 1000, 0))
+	// flows already aggregated are still updated
+	agg.add(newTestFlow(40000, 100, 1000, 0))
+
+	assert.Len(t, agg.flows, 1)
+	assert.Equal(t, dropped+1, netflowFlowsDropped.Value())
+	assert.Equal(t, uint64(200), agg.flows[newFlowKey(newTestFlow(40000, 0, 0, 0))].Bytes)
+}
+
+func TestAggregatorFlushOnStop(t *testing.T) {
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	agg := newFlowAggregator(sender, &Config{AggregatorMaxFlows: 10, AggregatorFlushInterval: 300}, "my-host")
+	agg.start()
+
+	agg.flowIn <- newTestFlow(40000, 100, 1000, 0)
+	agg.flowIn <- newTestFlow(40000, 100, 1000, 0)
+	agg.stop()
+
+	payloads := purgeFlowPayloads(t, sender)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, uint64(200), payloads[0].Bytes)
+}
+
+func TestAggregatorFlushPipelineFull(t *testing.T) {
+	// the pipeline of the noop forwarder is never emptied
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	agg := newFlowAggregator(sender, &Config{AggregatorMaxFlows: 1000, AggregatorFlushInterval: 300}, "my-host")
+	for port := uint16(0); port < 150; port++ {
+		agg.add(newTestFlow(port, 100, 1000, 0))
+	}
+
+	dropped := netflowFlowsDropped.Value()
+	sent := agg.flush(time.Now())
+	assert.Equal(t, len(purgeFlowPayloads(t, sender)), sent)
+	assert.Equal(t, dropped+int64(150-sent), netflowFlowsDropped.Value())
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// IsEnabled returns whether NetFlow collection is enabled in the Agent configuration.
+func IsEnabled() bool {
+	return config.Datadog.GetBool("network_devices.netflow.enabled")
+}
+
+// Config contains configuration for the NetFlow collector.
+// YAML field tags provided for test marshalling purposes.
+type Config struct {
+	BindHost string   `mapstructure:"bind_host" yaml:"bind_host"`
+	Ports    []uint16 `mapstructure:"ports" yaml:"ports"`
+	// AggregatorFlushInterval is the interval, in seconds, at which the aggregated flows are sent
+	AggregatorFlushInterval int `mapstructure:"aggregator_flush_interval" yaml:"aggregator_flush_interval"`
+	// AggregatorMaxFlows bounds the number of distinct flows aggregated over a flush interval
+	AggregatorMaxFlows int `mapstructure:"aggregator_max_flows" yaml:"aggregator_max_flows"`
+	StopTimeout        int `mapstructure:"stop_timeout" yaml:"stop_timeout"`
+}
+
+// ReadConfig builds and returns configuration from Agent configuration.
+func ReadConfig() (*Config, error) {
+	var c Config
+	err := config.Datadog.UnmarshalKey("network_devices.netflow", &c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set defaults.
+	if c.BindHost == "" {
+		c.BindHost = defaultBindHost
+	}
+	if len(c.Ports) == 0 {
+		c.Ports = defaultPorts
+	}
+	if c.AggregatorFlushInterval <= 0 {
+		c.AggregatorFlushInterval = defaultAggregatorFlushInterval
+	}
+	if c.AggregatorMaxFlows <= 0 {
+		c.AggregatorMaxFlows = defaultAggregatorMaxFlows
+	}
+	if c.StopTimeout == 0 {
+		c.StopTimeout = defaultStopTimeout
+	}
+
+	return &c, nil
+}
+
+// Addr returns the host:port address to listen on for a given port.
+func (c *Config) Addr(port uint16) string {
+	return fmt.Sprintf("%s:%d", c.BindHost, port)
+}
+
+// FlushInterval returns the interval at which the aggregated flows are sent.
+func (c *Config) FlushInterval() time.Duration {
+	return time.Duration(c.AggregatorFlushInterval) * time.Second
+}
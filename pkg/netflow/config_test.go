@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// configure sets Datadog Agent configuration from a config object.
+func configure(t *testing.T, netflowConfig Config) {
+	datadogYaml := map[string]interface{}{
+		"network_devices": map[string]interface{}{
+			"netflow": netflowConfig,
+		},
+	}
+
+	config.Datadog.SetConfigType("yaml")
+	out, err := yaml.Marshal(datadogYaml)
+	require.NoError(t, err)
+
+	err = config.Datadog.ReadConfig(strings.NewReader(string(out)))
+	require.NoError(t, err)
+}
+
+func TestConfig(t *testing.T) {
+	configure(t, Config{
+		BindHost:                "127.0.0.1",
+		Ports:                   []uint16{2055, 6343},
+		AggregatorFlushInterval: 10,
+	})
+	config, err := ReadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{2055, 6343}, config.Ports)
+	assert.Equal(t, "127.0.0.1:6343", config.Addr(6343))
+	assert.Equal(t, "10s", config.FlushInterval().String())
+	assert.Equal(t, defaultAggregatorMaxFlows, config.AggregatorMaxFlows)
+	assert.Equal(t, defaultStopTimeout, config.StopTimeout)
+}
+
+func TestDefaultConfig(t *testing.T) {
+	config.Datadog.SetConfigType("yaml")
+	err := config.Datadog.ReadConfig(strings.NewReader(`
+network_devices:
+  netflow:
+    enabled: true
+`))
+	require.NoError(t, err)
+	assert.True(t, IsEnabled())
+
+	netflowConfig, err := ReadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []uint16{2055, 4739, 6343}, netflowConfig.Ports)
+	assert.Equal(t, "localhost", netflowConfig.BindHost)
+	assert.Equal(t, defaultAggregatorFlushInterval, netflowConfig.AggregatorFlushInterval)
+}
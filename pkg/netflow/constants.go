@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import "time"
+
+// defaultPorts are the ports usually used by NetFlow, IPFIX and sFlow exporters
+var defaultPorts = []uint16{2055, 4739, 6343}
+
+const (
+	defaultBindHost                = "localhost"
+	defaultAggregatorFlushInterval = 300 // in seconds
+	defaultAggregatorMaxFlows      = 100000
+	defaultStopTimeout             = 5
+
+	flowsChanSize = 10000
+
+	// a flow is dropped, along with the rest of the flush, if the event platform
+	// forwarder can't accept it after sendMaxRetries*sendRetryInterval
+	sendMaxRetries    = 100
+	sendRetryInterval = 10 * time.Millisecond
+
+	// maxTemplatesPerExporter bounds the number of NetFlow v9 and IPFIX templates cached for an exporter
+	maxTemplatesPerExporter = 1000
+	// templateTTL is the time after which a template not sent again by its exporter is expired.
+	// Exporters usually refresh their templates every 30 minutes.
+	templateTTL = 90 * time.Minute
+
+	// maxDatagramSize is the maximum size of the UDP datagrams we read
+	maxDatagramSize = 65535
+)
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	netflow5Version = 5
+	netflow9Version = 9
+	ipfixVersion    = 10
+	sflow5Version   = 5
+)
+
+var errTruncatedPacket = errors.New("truncated packet")
+
+// Decoder decodes the NetFlow v5, NetFlow v9, IPFIX and sFlow v5 datagrams sent by exporters,
+// the protocol being detected from the version found in the header of each datagram.
+// The NetFlow v9 and IPFIX templates are cached by exporter, until they are not refreshed by the exporter
+// within templateTTL.
+type Decoder struct {
+	templates *templateCache
+}
+
+// NewDecoder returns a new Decoder
+func NewDecoder() *Decoder {
+	return &Decoder{
+		templates: newTemplateCache(maxTemplatesPerExporter, templateTTL),
+	}
+}
+
+// Decode decodes a datagram received from an exporter at the given time. The flows of data records
+// whose template is unknown yet are dropped, exporters sending their templates periodically.
+func (d *Decoder) Decode(payload []byte, exporter net.IP, now time.Time) ([]*Flow, error) {
+	if len(payload) < 4 {
+		return nil, errTruncatedPacket
+	}
+
+	// sFlow datagrams start with a 32 bits version, NetFlow and IPFIX ones with a 16 bits version
+	if binary.BigEndian.Uint32(payload) == sflow5Version {
+		return decodeSFlow5(payload, now)
+	}
+
+	switch version := binary.BigEndian.Uint16(payload); version {
+	case netflow5Version:
+		return decodeNetFlow5(payload, exporter)
+	case netflow9Version:
+		return d.decodeNetFlow9(payload, exporter, now)
+	case ipfixVersion:
+		return d.decodeIPFIX(payload, exporter, now)
+	default:
+		return nil, fmt.Errorf("unsupported flow version %d", version)
+	}
+}
@@ -0,0 +1,344 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testExporter   = net.ParseIP("10.0.0.1").To4()
+	testExportTime = uint32(1620000000)
+)
+
+// packetBuilder writes the big endian fields of a test packet
+type packetBuilder struct {
+	bytes.Buffer
+}
+
+func (b *packetBuilder) put(values ...interface{}) *packetBuilder {
+	for _, v := range values {
+		if ip, ok := v.(net.IP); ok {
+			v = []byte(ip)
+		}
+		binary.Write(&b.Buffer, binary.BigEndian, v) //nolint:errcheck
+	}
+	return b
+}
+
+// set writes a NetFlow v9 flowset or an IPFIX set with its header
+func (b *packetBuilder) set(id uint16, content []byte) *packetBuilder {
+	return b.put(id, uint16(4+len(content)), content)
+}
+
+func buildNetFlow5Packet() []byte {
+	b := &packetBuilder{}
+	// header: version, count, sysUptime, unixSecs, unixNsecs, sequence, engine type and ID, sampling
+	b.put(uint16(5), uint16(2), uint32(60000), testExportTime, uint32(0), uint32(1), uint16(0), uint16(0x4000|10))
+	for i := 0; i < 2; i++ {
+		b.put(net.ParseIP("192.168.1.10").To4(), net.ParseIP("192.168.2.20").To4(), net.IPv4zero.To4())
+		// input and output interfaces, packets, bytes, first and last
+		b.put(uint16(1), uint16(2), uint32(3), uint32(1500), uint32(10000), uint32(50000))
+		// ports, pad, TCP flags, protocol, tos, AS and masks
+		b.put(uint16(40000+i), uint16(443), uint8(0), uint8(0x12), uint8(6), uint8(0), uint32(0), uint32(0))
+	}
+	return b.Bytes()
+}
+
+func TestDecodeNetFlow5(t *testing.T) {
+	flows, err := NewDecoder().Decode(buildNetFlow5Packet(), testExporter, time.Now())
+	require.NoError(t, err)
+	require.Len(t, flows, 2)
+
+	bootTime := uint64(testExportTime)*1000 - 60000
+	assert.Equal(t, &Flow{
+		FlowType:        TypeNetFlow5,
+		ExporterAddr:    testExporter,
+		StartTimestamp:  (bootTime + 10000) / 1000,
+		EndTimestamp:    (bootTime + 50000) / 1000,
+		Bytes:           15000,
+		Packets:         30,
+		SrcAddr:         net.ParseIP("192.168.1.10").To4(),
+		DstAddr:         net.ParseIP("192.168.2.20").To4(),
+		SrcPort:         40000,
+		DstPort:         443,
+		IPProtocol:      6,
+		TCPFlags:        0x12,
+		InputInterface:  1,
+		OutputInterface: 2,
+	}, flows[0])
+	assert.Equal(t, uint16(40001), flows[1].SrcPort)
+}
+
+func TestDecodeNetFlow5Truncated(t *testing.T) {
+	packet := buildNetFlow5Packet()
+	_, err := NewDecoder().Decode(packet[:len(packet)-1], testExporter, time.Now())
+	assert.Error(t, err)
+}
+
+func buildNetFlow9Packet(withTemplate bool) []byte {
+	b := &packetBuilder{}
+	// header: version, count, sysUptime, unixSecs, sequence, source ID
+	b.put(uint16(9), uint16(2), uint32(60000), testExportTime, uint32(1), uint32(42))
+
+	if withTemplate {
+		template := &packetBuilder{}
+		template.put(uint16(256), uint16(9),
+			uint16(fieldIPv4SrcAddr), uint16(4), uint16(fieldIPv4DstAddr), uint16(4),
+			uint16(fieldL4SrcPort), uint16(2), uint16(fieldL4DstPort), uint16(2),
+			uint16(fieldProtocol), uint16(1), uint16(fieldInBytes), uint16(4),
+			uint16(fieldInPkts), uint16(4), uint16(fieldFirstSwitched), uint16(4),
+			uint16(fieldLastSwitched), uint16(4))
+		b.set(netflow9TemplateFlowSetID, template.Bytes())
+
+		options := &packetBuilder{}
+		options.put(uint16(257), uint16(4), uint16(4), uint16(1), uint16(4), uint16(fieldSamplingInterval), uint16(4))
+		b.set(netflow9OptionsTemplateFlowSetID, options.Bytes())
+	}
+
+	data := &packetBuilder{}
+	data.put(net.ParseIP("192.168.1.10").To4(), net.ParseIP("192.168.2.20").To4(),
+		uint16(53000), uint16(53), uint8(17), uint32(120), uint32(1), uint32(20000), uint32(20000))
+	// padding
+	data.put(uint8(0), uint8(0), uint8(0))
+	b.set(256, data.Bytes())
+
+	optionsData := &packetBuilder{}
+	optionsData.put(uint32(1), uint32(100))
+	b.set(257, optionsData.Bytes())
+	return b.Bytes()
+}
+
+func TestDecodeNetFlow9(t *testing.T) {
+	decoder := NewDecoder()
+	flows, err := decoder.Decode(buildNetFlow9Packet(true), testExporter, time.Now())
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+
+	bootTime := uint64(testExportTime)*1000 - 60000
+	assert.Equal(t, &Flow{
+		FlowType:       TypeNetFlow9,
+		ExporterAddr:   testExporter,
+		StartTimestamp: (bootTime + 20000) / 1000,
+		EndTimestamp:   (bootTime + 20000) / 1000,
+		Bytes:          120,
+		Packets:        1,
+		SrcAddr:        net.ParseIP("192.168.1.10").To4(),
+		DstAddr:        net.ParseIP("192.168.2.20").To4(),
+		SrcPort:        53000,
+		DstPort:        53,
+		IPProtocol:     17,
+	}, flows[0])
+
+	// the templates are cached by exporter
+	flows, err = decoder.Decode(buildNetFlow9Packet(false), testExporter, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, flows, 1)
+
+	missing := netflowMissingTemplates.Value()
+	flows, err = decoder.Decode(buildNetFlow9Packet(false), net.ParseIP("10.0.0.2").To4(), time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, flows)
+	assert.Equal(t, missing+2, netflowMissingTemplates.Value())
+}
+
+func buildIPFIXPacket(templates ...[]byte) []byte {
+	b := &packetBuilder{}
+	for _, t := range templates {
+		b.set(ipfixTemplateSetID, t)
+	}
+
+	data := &packetBuilder{}
+	for i := 0; i < 2; i++ {
+		data.put(net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2"), uint16(50000+i), uint16(80), uint8(6),
+			uint64(4000), uint64(5), uint64(1620000010000), uint64(1620000020000))
+		// variable length field, encoded on 1 and 3 bytes
+		if i == 0 {
+			data.put(uint8(3), []byte("abc"))
+		} else {
+			data.put(uint8(255), uint16(3), []byte("abc"))
+		}
+		// enterprise field
+		data.put(uint32(7))
+	}
+	b.set(256, data.Bytes())
+
+	header := &packetBuilder{}
+	header.put(uint16(10), uint16(ipfixHeaderSize+b.Len()), testExportTime, uint32(1), uint32(7))
+	return append(header.Bytes(), b.Bytes()...)
+}
+
+func buildIPFIXTemplate() []byte {
+	template := &packetBuilder{}
+	template.put(uint16(256), uint16(11),
+		uint16(fieldIPv6SrcAddr), uint16(16), uint16(fieldIPv6DstAddr), uint16(16),
+		uint16(fieldL4SrcPort), uint16(2), uint16(fieldL4DstPort), uint16(2),
+		uint16(fieldProtocol), uint16(1), uint16(fieldInBytes), uint16(8),
+		uint16(fieldInPkts), uint16(8), uint16(fieldFlowStartMilliseconds), uint16(8),
+		uint16(fieldFlowEndMilliseconds), uint16(8),
+		uint16(82), uint16(variableLength),
+		uint16(ipfixEnterpriseBit|1), uint16(4), uint32(29305))
+	return template.Bytes()
+}
+
+func TestDecodeIPFIX(t *testing.T) {
+	decoder := NewDecoder()
+	flows, err := decoder.Decode(buildIPFIXPacket(buildIPFIXTemplate()), testExporter, time.Now())
+	require.NoError(t, err)
+	require.Len(t, flows, 2)
+
+	assert.Equal(t, &Flow{
+		FlowType:       TypeIPFIX,
+		ExporterAddr:   testExporter,
+		StartTimestamp: 1620000010,
+		EndTimestamp:   1620000020,
+		Bytes:          4000,
+		Packets:        5,
+		SrcAddr:        net.ParseIP("2001:db8::1"),
+		DstAddr:        net.ParseIP("2001:db8::2"),
+		SrcPort:        50000,
+		DstPort:        80,
+		IPProtocol:     6,
+	}, flows[0])
+	assert.Equal(t, uint16(50001), flows[1].SrcPort)
+	assert.Equal(t, uint64(4000), flows[1].Bytes)
+
+	// template withdrawal
+	flows, err = decoder.Decode(buildIPFIXPacket([]byte{1, 0, 0, 0}), testExporter, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, flows)
+}
+
+func buildSFlowPacket() []byte {
+	// ethernet frame holding a TCP segment in a VLAN
+	frame := &packetBuilder{}
+	frame.put(make([]byte, 12), uint16(etherTypeDot1Q), uint16(100), uint16(etherTypeIPv4))
+	frame.put(uint8(0x45), uint8(0), uint16(1500), make([]byte, 5), uint8(6), uint16(0),
+		net.ParseIP("172.16.0.1").To4(), net.ParseIP("172.16.0.2").To4())
+	frame.put(uint16(22), uint16(60000), make([]byte, 9), uint8(0x18))
+
+	header := &packetBuilder{}
+	header.put(uint32(sflowHeaderProtocolEthernet), uint32(1518), uint32(4), uint32(frame.Len()), frame.Bytes())
+
+	flowSample := &packetBuilder{}
+	// sequence, source ID, rate, pool, drops, input and output interfaces, record count
+	flowSample.put(uint32(1), uint32(3), uint32(512), uint32(0), uint32(0), uint32(3), uint32(0x40000005), uint32(1))
+	flowSample.put(uint32(sflowRawPacketHeader), uint32(header.Len()), header.Bytes())
+
+	sampledIPv6 := &packetBuilder{}
+	sampledIPv6.put(uint32(80), uint32(17), net.ParseIP("2001:db8::1"), net.ParseIP("2001:db8::2"),
+		uint32(5353), uint32(53), uint32(0), uint32(0))
+	expandedSample := &packetBuilder{}
+	// sequence, source ID type and index, rate, pool, drops, input and output interfaces, record count
+	expandedSample.put(uint32(2), uint32(0), uint32(7), uint32(100), uint32(0), uint32(0),
+		uint32(0), uint32(7), uint32(0), uint32(8), uint32(2))
+	// unknown record, skipped
+	expandedSample.put(uint32(1001), uint32(4), uint32(0))
+	expandedSample.put(uint32(sflowSampledIPv6), uint32(sampledIPv6.Len()), sampledIPv6.Bytes())
+
+	b := &packetBuilder{}
+	b.put(uint32(5), uint32(sflowAddressIPv4), net.ParseIP("10.0.0.3").To4(), uint32(0), uint32(1), uint32(1000), uint32(3))
+	b.put(uint32(sflowFlowSample), uint32(flowSample.Len()), flowSample.Bytes())
+	b.put(uint32(sflowExpandedFlowSample), uint32(expandedSample.Len()), expandedSample.Bytes())
+	// counter sample, skipped
+	b.put(uint32(2), uint32(4), uint32(0))
+	return b.Bytes()
+}
+
+func TestDecodeSFlow5(t *testing.T) {
+	now := time.Unix(1620000000, 0)
+	flows, err := NewDecoder().Decode(buildSFlowPacket(), testExporter, now)
+	require.NoError(t, err)
+	require.Len(t, flows, 2)
+
+	assert.Equal(t, &Flow{
+		FlowType:        TypeSFlow5,
+		ExporterAddr:    net.ParseIP("10.0.0.3").To4(),
+		StartTimestamp:  1620000000,
+		EndTimestamp:    1620000000,
+		Bytes:           1518 * 512,
+		Packets:         512,
+		SrcAddr:         net.ParseIP("172.16.0.1").To4(),
+		DstAddr:         net.ParseIP("172.16.0.2").To4(),
+		SrcPort:         22,
+		DstPort:         60000,
+		IPProtocol:      6,
+		TCPFlags:        0x18,
+		InputInterface:  3,
+		OutputInterface: 5,
+	}, flows[0])
+
+	assert.Equal(t, &Flow{
+		FlowType:        TypeSFlow5,
+		ExporterAddr:    net.ParseIP("10.0.0.3").To4(),
+		StartTimestamp:  1620000000,
+		EndTimestamp:    1620000000,
+		Bytes:           8000,
+		Packets:         100,
+		SrcAddr:         net.ParseIP("2001:db8::1"),
+		DstAddr:         net.ParseIP("2001:db8::2"),
+		SrcPort:         5353,
+		DstPort:         53,
+		IPProtocol:      17,
+		InputInterface:  7,
+		OutputInterface: 8,
+	}, flows[1])
+}
+
+func TestDecodeSFlow5Truncated(t *testing.T) {
+	packet := buildSFlowPacket()
+	_, err := NewDecoder().Decode(packet[:100], testExporter, time.Now())
+	assert.Error(t, err)
+}
+
+func TestDecodeUnsupportedVersion(t *testing.T) {
+	_, err := NewDecoder().Decode([]byte{0, 7, 0, 0}, testExporter, time.Now())
+	assert.EqualError(t, err, "unsupported flow version 7")
+}
+
+func TestTemplateCache(t *testing.T) {
+	cache := newTemplateCache(2, time.Hour)
+	now := time.Now()
+	key := func(exporter string, id uint16) templateKey {
+		return templateKey{exporter: exporter, version: ipfixVersion, id: id}
+	}
+
+	cache.set(key("10.0.0.1", 256), &template{}, now)
+	cache.set(key("10.0.0.1", 257), &template{}, now.Add(time.Minute))
+	// the templates of an exporter don't count against the limit of another
+	cache.set(key("10.0.0.2", 256), &template{}, now.Add(time.Minute))
+
+	// the least recently updated template of the exporter is evicted
+	evicted := netflowTemplatesEvicted.Value()
+	cache.set(key("10.0.0.1", 258), &template{}, now.Add(2*time.Minute))
+	assert.Equal(t, evicted+1, netflowTemplatesEvicted.Value())
+	assert.Nil(t, cache.get(key("10.0.0.1", 256), now.Add(2*time.Minute)))
+	assert.NotNil(t, cache.get(key("10.0.0.1", 257), now.Add(2*time.Minute)))
+	assert.NotNil(t, cache.get(key("10.0.0.1", 258), now.Add(2*time.Minute)))
+	assert.NotNil(t, cache.get(key("10.0.0.2", 256), now.Add(2*time.Minute)))
+
+	// a refreshed template doesn't evict another one
+	cache.set(key("10.0.0.1", 257), &template{}, now.Add(30*time.Minute))
+	assert.Equal(t, evicted+1, netflowTemplatesEvicted.Value())
+
+	// the templates not refreshed within the ttl are expired
+	later := now.Add(63 * time.Minute)
+	assert.Nil(t, cache.get(key("10.0.0.1", 258), later))
+	assert.NotNil(t, cache.get(key("10.0.0.1", 257), later))
+
+	expired := netflowTemplatesExpired.Value()
+	cache.set(key("10.0.0.3", 256), &template{}, later)
+	assert.Equal(t, expired+2, netflowTemplatesExpired.Value())
+	assert.Len(t, cache.templates["10.0.0.1"], 1)
+	assert.NotContains(t, cache.templates, "10.0.0.2")
+}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import "net"
+
+// FlowType is the protocol a flow was received with
+type FlowType string
+
+const (
+	// TypeNetFlow5 represents NetFlow v5 flows
+	TypeNetFlow5 FlowType = "netflow5"
+	// TypeNetFlow9 represents NetFlow v9 flows
+	TypeNetFlow9 FlowType = "netflow9"
+	// TypeIPFIX represents IPFIX flows
+	TypeIPFIX FlowType = "ipfix"
+	// TypeSFlow5 represents sFlow v5 flows
+	TypeSFlow5 FlowType = "sflow5"
+)
+
+// Flow is a flow record decoded from any of the supported protocols
+type Flow struct {
+	FlowType     FlowType
+	ExporterAddr net.IP
+
+	// Start and end of the flow, in seconds since epoch
+	StartTimestamp uint64
+	EndTimestamp   uint64
+
+	// Bytes and packets are scaled according to the sampling rate, if any
+	Bytes   uint64
+	Packets uint64
+
+	SrcAddr    net.IP
+	DstAddr    net.IP
+	SrcPort    uint16
+	DstPort    uint16
+	IPProtocol uint8
+	Tos        uint8
+	TCPFlags   uint8
+
+	InputInterface  uint32
+	OutputInterface uint32
+}
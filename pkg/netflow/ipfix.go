@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	ipfixHeaderSize = 16
+
+	ipfixTemplateSetID        = 2
+	ipfixOptionsTemplateSetID = 3
+
+	// enterprise bit of the field specifiers, followed by an enterprise number when set
+	ipfixEnterpriseBit = 0x8000
+)
+
+// decodeIPFIX decodes an IPFIX message: a header followed by template, options template and data sets
+func (d *Decoder) decodeIPFIX(payload []byte, exporter net.IP, now time.Time) ([]*Flow, error) {
+	if len(payload) < ipfixHeaderSize {
+		return nil, errTruncatedPacket
+	}
+
+	length := int(binary.BigEndian.Uint16(payload[2:]))
+	if length < ipfixHeaderSize || length > len(payload) {
+		return nil, fmt.Errorf("invalid message length %d: %w", length, errTruncatedPacket)
+	}
+	payload = payload[:length]
+
+	exportTime := uint64(binary.BigEndian.Uint32(payload[4:]))
+	domainID := binary.BigEndian.Uint32(payload[12:])
+	ctx := recordContext{
+		flowType:    TypeIPFIX,
+		exporter:    exporter,
+		exportTime:  exportTime,
+		receiveTime: now,
+	}
+	key := templateKey{exporter: exporter.String(), version: ipfixVersion, domain: domainID}
+
+	var flows []*Flow
+	for offset := ipfixHeaderSize; offset+4 <= len(payload); {
+		id := binary.BigEndian.Uint16(payload[offset:])
+		setLength := int(binary.BigEndian.Uint16(payload[offset+2:]))
+		if setLength < 4 || offset+setLength > len(payload) {
+			return flows, fmt.Errorf("invalid set length %d: %w", setLength, errTruncatedPacket)
+		}
+		body := payload[offset+4 : offset+setLength]
+		offset += setLength
+
+		switch {
+		case id == ipfixTemplateSetID:
+			d.decodeIPFIXTemplates(key, body, false, now)
+		case id == ipfixOptionsTemplateSetID:
+			d.decodeIPFIXTemplates(key, body, true, now)
+		case id >= minDataSetID:
+			key.id = id
+			flows = append(flows, d.decodeDataSet(key, body, ctx)...)
+		}
+	}
+	return flows, nil
+}
+
+func (d *Decoder) decodeIPFIXTemplates(key templateKey, body []byte, options bool, now time.Time) {
+	headerSize := 4
+	if options {
+		// the options templates also hold their number of scope fields
+		headerSize = 6
+	}
+
+	for len(body) >= headerSize {
+		key.id = binary.BigEndian.Uint16(body)
+		fieldCount := int(binary.BigEndian.Uint16(body[2:]))
+		body = body[headerSize:]
+		if key.id < minDataSetID {
+			// padding
+			return
+		}
+		if fieldCount == 0 {
+			// template withdrawal
+			d.templates.delete(key)
+			continue
+		}
+
+		t := &template{fields: make([]templateField, 0, fieldCount), options: options}
+		for i := 0; i < fieldCount; i++ {
+			if len(body) < 4 {
+				return
+			}
+			field := templateField{
+				id:     binary.BigEndian.Uint16(body),
+				length: binary.BigEndian.Uint16(body[2:]),
+			}
+			body = body[4:]
+
+			if field.id&ipfixEnterpriseBit != 0 {
+				if len(body) < 4 {
+					return
+				}
+				field.id &^= ipfixEnterpriseBit
+				field.enterprise = binary.BigEndian.Uint32(body)
+				body = body[4:]
+			}
+			t.fields = append(t.fields, field)
+		}
+		d.templates.set(key, t, now)
+	}
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+const (
+	netflow5HeaderSize = 24
+	netflow5RecordSize = 48
+)
+
+// decodeNetFlow5 decodes a NetFlow v5 datagram: a header followed by fixed size records
+func decodeNetFlow5(payload []byte, exporter net.IP) ([]*Flow, error) {
+	if len(payload) < netflow5HeaderSize {
+		return nil, errTruncatedPacket
+	}
+
+	count := int(binary.BigEndian.Uint16(payload[2:]))
+	sysUptime := uint64(binary.BigEndian.Uint32(payload[4:]))
+	unixSecs := uint64(binary.BigEndian.Uint32(payload[8:]))
+	unixNsecs := uint64(binary.BigEndian.Uint32(payload[12:]))
+	// the 2 first bits hold the sampling mode, the others the sampling interval
+	samplingInterval := uint64(binary.BigEndian.Uint16(payload[22:]) & 0x3fff)
+	if samplingInterval == 0 {
+		samplingInterval = 1
+	}
+
+	if len(payload) < netflow5HeaderSize+count*netflow5RecordSize {
+		return nil, fmt.Errorf("%d records announced in a packet of %d bytes: %w", count, len(payload), errTruncatedPacket)
+	}
+
+	// the first and last fields of the records are relative to the boot of the exporter, in milliseconds
+	bootTime := unixSecs*1000 + unixNsecs/1000000 - sysUptime
+
+	flows := make([]*Flow, 0, count)
+	for i := 0; i < count; i++ {
+		record := payload[netflow5HeaderSize+i*netflow5RecordSize:]
+		flows = append(flows, &Flow{
+			FlowType:        TypeNetFlow5,
+			ExporterAddr:    exporter,
+			SrcAddr:         net.IP(append([]byte(nil), record[0:4]...)),
+			DstAddr:         net.IP(append([]byte(nil), record[4:8]...)),
+			InputInterface:  uint32(binary.BigEndian.Uint16(record[12:])),
+			OutputInterface: uint32(binary.BigEndian.Uint16(record[14:])),
+			Packets:         uint64(binary.BigEndian.Uint32(record[16:])) * samplingInterval,
+			Bytes:           uint64(binary.BigEndian.Uint32(record[20:])) * samplingInterval,
+			StartTimestamp:  (bootTime + uint64(binary.BigEndian.Uint32(record[24:]))) / 1000,
+			EndTimestamp:    (bootTime + uint64(binary.BigEndian.Uint32(record[28:]))) / 1000,
+			SrcPort:         binary.BigEndian.Uint16(record[32:]),
+			DstPort:         binary.BigEndian.Uint16(record[34:]),
+			TCPFlags:        record[37],
+			IPProtocol:      record[38],
+			Tos:             record[39],
+		})
+	}
+	return flows, nil
+}
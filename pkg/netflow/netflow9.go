@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	netflow9HeaderSize = 20
+
+	netflow9TemplateFlowSetID        = 0
+	netflow9OptionsTemplateFlowSetID = 1
+	// data flowsets use the ID of their template, starting at 256
+	minDataSetID = 256
+)
+
+// decodeNetFlow9 decodes a NetFlow v9 datagram: a header followed by template, options
+// template and data flowsets
+func (d *Decoder) decodeNetFlow9(payload []byte, exporter net.IP, now time.Time) ([]*Flow, error) {
+	if len(payload) < netflow9HeaderSize {
+		return nil, errTruncatedPacket
+	}
+
+	sysUptime := uint64(binary.BigEndian.Uint32(payload[4:]))
+	unixSecs := uint64(binary.BigEndian.Uint32(payload[8:]))
+	sourceID := binary.BigEndian.Uint32(payload[16:])
+	ctx := recordContext{
+		flowType:    TypeNetFlow9,
+		exporter:    exporter,
+		exportTime:  unixSecs,
+		bootTime:    unixSecs*1000 - sysUptime,
+		receiveTime: now,
+	}
+	key := templateKey{exporter: exporter.String(), version: netflow9Version, domain: sourceID}
+
+	var flows []*Flow
+	for offset := netflow9HeaderSize; offset+4 <= len(payload); {
+		id := binary.BigEndian.Uint16(payload[offset:])
+		length := int(binary.BigEndian.Uint16(payload[offset+2:]))
+		if length < 4 || offset+length > len(payload) {
+			return flows, fmt.Errorf("invalid flowset length %d: %w", length, errTruncatedPacket)
+		}
+		body := payload[offset+4 : offset+length]
+		offset += length
+
+		switch {
+		case id == netflow9TemplateFlowSetID:
+			d.decodeNetFlow9Templates(key, body, now)
+		case id == netflow9OptionsTemplateFlowSetID:
+			d.decodeNetFlow9OptionsTemplates(key, body, now)
+		case id >= minDataSetID:
+			key.id = id
+			flows = append(flows, d.decodeDataSet(key, body, ctx)...)
+		}
+	}
+	return flows, nil
+}
+
+func (d *Decoder) decodeNetFlow9Templates(key templateKey, body []byte, now time.Time) {
+	for len(body) >= 4 {
+		key.id = binary.BigEndian.Uint16(body)
+		fieldCount := int(binary.BigEndian.Uint16(body[2:]))
+		body = body[4:]
+		if len(body) < fieldCount*4 {
+			return
+		}
+
+		t := &template{fields: make([]templateField, 0, fieldCount)}
+		for i := 0; i < fieldCount; i++ {
+			t.fields = append(t.fields, templateField{
+				id:     binary.BigEndian.Uint16(body[i*4:]),
+				length: binary.BigEndian.Uint16(body[i*4+2:]),
+			})
+		}
+		body = body[fieldCount*4:]
+		d.templates.set(key, t, now)
+	}
+}
+
+func (d *Decoder) decodeNetFlow9OptionsTemplates(key templateKey, body []byte, now time.Time) {
+	for len(body) >= 6 {
+		key.id = binary.BigEndian.Uint16(body)
+		// lengths in bytes of the scope and option field definitions
+		scopeLength := int(binary.BigEndian.Uint16(body[2:]))
+		optionLength := int(binary.BigEndian.Uint16(body[4:]))
+		body = body[6:]
+		fieldCount := (scopeLength + optionLength) / 4
+		if key.id < minDataSetID || len(body) < fieldCount*4 {
+			// padding
+			return
+		}
+
+		t := &template{fields: make([]templateField, 0, fieldCount), options: true}
+		for i := 0; i < fieldCount; i++ {
+			t.fields = append(t.fields, templateField{
+				id:     binary.BigEndian.Uint16(body[i*4:]),
+				length: binary.BigEndian.Uint16(body[i*4+2:]),
+			})
+		}
+		body = body[fieldCount*4:]
+		d.templates.set(key, t, now)
+	}
+}
+
+// decodeDataSet decodes the data records of a NetFlow v9 or IPFIX data set
+func (d *Decoder) decodeDataSet(key templateKey, body []byte, ctx recordContext) []*Flow {
+	t := d.templates.get(key, ctx.receiveTime)
+	if t == nil {
+		netflowMissingTemplates.Add(1)
+		return nil
+	}
+	if t.options {
+		return nil
+	}
+
+	var flows []*Flow
+	for len(body) > 0 {
+		flow, size := decodeDataRecord(t, body, ctx)
+		if size == 0 {
+			// padding
+			break
+		}
+		flows = append(flows, flow)
+		body = body[size:]
+	}
+	return flows
+}
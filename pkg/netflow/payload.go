@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+// FlowPayload is the payload of an aggregated flow, as sent to the intake
+type FlowPayload struct {
+	FlushTimestamp int64            `json:"flush_timestamp"`
+	FlowType       FlowType         `json:"type"`
+	Host           string           `json:"host"`
+	Exporter       Exporter         `json:"exporter"`
+	Start          uint64           `json:"start"` // in seconds
+	End            uint64           `json:"end"`   // in seconds
+	Bytes          uint64           `json:"bytes"`
+	Packets        uint64           `json:"packets"`
+	IPProtocol     uint8            `json:"ip_protocol"`
+	TCPFlags       uint8            `json:"tcp_flags"`
+	Source         Endpoint         `json:"source"`
+	Destination    Endpoint         `json:"destination"`
+	Ingress        ObservationPoint `json:"ingress"`
+	Egress         ObservationPoint `json:"egress"`
+}
+
+// Exporter holds the address of the device exporting the flow
+type Exporter struct {
+	IP string `json:"ip"`
+}
+
+// Endpoint holds the address and port of one end of the flow
+type Endpoint struct {
+	IP   string `json:"ip"`
+	Port uint16 `json:"port"`
+}
+
+// ObservationPoint holds the interface of the exporter on which the flow was observed
+type ObservationPoint struct {
+	Interface Interface `json:"interface"`
+}
+
+// Interface holds the index of an interface, and its name when known from the SNMP check
+type Interface struct {
+	Index uint32 `json:"index"`
+	Name  string `json:"name,omitempty"`
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Server manages the NetFlow, IPFIX and sFlow listeners and the aggregation of the received flows.
+type Server struct {
+	config     *Config
+	listeners  []*net.UDPConn
+	decoder    *Decoder
+	aggregator *flowAggregator
+	listening  sync.WaitGroup
+}
+
+var (
+	serverInstance *Server
+	startError     error
+)
+
+// StartServer starts the global netflow server.
+func StartServer(sender epforwarder.EventPlatformForwarder) error {
+	server, err := NewNetflowServer(sender)
+	serverInstance = server
+	startError = err
+	return err
+}
+
+// StopServer stops the global netflow server, if it is running.
+func StopServer() {
+	if serverInstance != nil {
+		serverInstance.Stop()
+		serverInstance = nil
+		startError = nil
+	}
+}
+
+// IsRunning returns whether the netflow server is currently running.
+func IsRunning() bool {
+	return serverInstance != nil
+}
+
+// NewNetflowServer configures and returns a running netflow server.
+func NewNetflowServer(sender epforwarder.EventPlatformForwarder) (*Server, error) {
+	config, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Error getting the hostname: %v", err)
+	}
+
+	server := &Server{
+		config:     config,
+		decoder:    NewDecoder(),
+		aggregator: newFlowAggregator(sender, config, hostname),
+	}
+
+	for _, port := range config.Ports {
+		addr, err := net.ResolveUDPAddr("udp", config.Addr(port))
+		if err != nil {
+			server.closeListeners()
+			return nil, err
+		}
+		conn, err := net.ListenUDP("udp", addr)
+		if err != nil {
+			server.closeListeners()
+			return nil, err
+		}
+		server.listeners = append(server.listeners, conn)
+	}
+
+	server.aggregator.start()
+	for _, conn := range server.listeners {
+		server.listening.Add(1)
+		go server.listen(conn)
+	}
+
+	return server, nil
+}
+
+func (s *Server) listen(conn *net.UDPConn) {
+	defer s.listening.Done()
+	log.Infof("Start listening for flows on %s", conn.LocalAddr())
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			// the connection is closed when the server is stopped
+			return
+		}
+		netflowPackets.Add(1)
+
+		flows, err := s.decoder.Decode(buf[:n], addr.IP, time.Now())
+		if err != nil {
+			log.Debugf("Error decoding packet from %s on listener %s: %s", addr, conn.LocalAddr(), err)
+			netflowDecodeErrors.Add(1)
+		}
+		for _, flow := range flows {
+			netflowFlows.Add(1)
+			s.aggregator.flowIn <- flow
+		}
+	}
+}
+
+func (s *Server) closeListeners() {
+	for _, conn := range s.listeners {
+		log.Infof("Stop listening on %s", conn.LocalAddr())
+		conn.Close()
+	}
+}
+
+// Stop stops the Server, flushing the flows aggregated so far.
+func (s *Server) Stop() {
+	stopped := make(chan interface{})
+
+	go func() {
+		s.closeListeners()
+		s.listening.Wait()
+		s.aggregator.stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Duration(s.config.StopTimeout) * time.Second):
+		log.Errorf("Stopping server. Timeout after %d seconds", s.config.StopTimeout)
+	}
+}
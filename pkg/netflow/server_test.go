@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+)
+
+// getPort requests a random UDP port number and makes sure it is available
+func getPort(t *testing.T) uint16 {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	return uint16(conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+func TestServer(t *testing.T) {
+	port := getPort(t)
+	configure(t, Config{
+		BindHost: "127.0.0.1",
+		Ports:    []uint16{port},
+	})
+
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	require.NoError(t, StartServer(sender))
+	require.True(t, IsRunning())
+
+	conn, err := net.Dial("udp", serverInstance.config.Addr(port))
+	require.NoError(t, err)
+	defer conn.Close()
+	flows := netflowFlows.Value()
+	_, err = conn.Write(buildNetFlow5Packet())
+	require.NoError(t, err)
+
+	// wait for the flows to be decoded
+	require.Eventually(t, func() bool {
+		return netflowFlows.Value() == flows+2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the flows are flushed when the server is stopped
+	StopServer()
+	assert.False(t, IsRunning())
+
+	payloads := purgeFlowPayloads(t, sender)
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "127.0.0.1", payloads[0].Exporter.IP)
+	assert.Equal(t, TypeNetFlow5, payloads[0].FlowType)
+
+	status := GetStatus()
+	assert.NotContains(t, status, "error")
+}
+
+func TestServerStartError(t *testing.T) {
+	// the second port is already in use
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	configure(t, Config{
+		BindHost: "127.0.0.1",
+		Ports:    []uint16{getPort(t), uint16(conn.LocalAddr().(*net.UDPAddr).Port)},
+	})
+
+	err = StartServer(epforwarder.NewNoopEventPlatformForwarder())
+	assert.Error(t, err)
+	assert.False(t, IsRunning())
+	assert.Contains(t, GetStatus(), "error")
+}
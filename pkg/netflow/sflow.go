@@ -0,0 +1,283 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	sflowAddressIPv4 = 1
+	sflowAddressIPv6 = 2
+
+	sflowFlowSample         = 1
+	sflowExpandedFlowSample = 3
+
+	sflowRawPacketHeader = 1
+	sflowSampledIPv4     = 3
+	sflowSampledIPv6     = 4
+
+	sflowHeaderProtocolEthernet = 1
+
+	etherTypeIPv4  = 0x0800
+	etherTypeIPv6  = 0x86dd
+	etherTypeDot1Q = 0x8100
+
+	ipProtocolTCP = 6
+	ipProtocolUDP = 17
+)
+
+// sflowReader reads the big endian fields of an sFlow datagram, keeping the first error
+type sflowReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sflowReader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 4 {
+		r.err = errTruncatedPacket
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *sflowReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data) < n {
+		r.err = errTruncatedPacket
+		return nil
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *sflowReader) address() net.IP {
+	switch addrType := r.uint32(); addrType {
+	case sflowAddressIPv4:
+		return readIP(r.bytes(net.IPv4len))
+	case sflowAddressIPv6:
+		return readIP(r.bytes(net.IPv6len))
+	default:
+		if r.err == nil {
+			r.err = fmt.Errorf("unsupported address type %d", addrType)
+		}
+		return nil
+	}
+}
+
+// decodeSFlow5 decodes an sFlow v5 datagram. Each flow sample describes a single sampled packet,
+// its counters are scaled by the sampling rate. sFlow samples have no timestamp, the time at
+// which the datagram is received is used instead.
+func decodeSFlow5(payload []byte, now time.Time) ([]*Flow, error) {
+	r := &sflowReader{data: payload}
+	r.uint32() // version
+	agent := r.address()
+	r.uint32() // sub agent ID
+	r.uint32() // sequence number
+	r.uint32() // uptime
+	sampleCount := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	ts := uint64(now.Unix())
+	var flows []*Flow
+	for i := uint32(0); i < sampleCount; i++ {
+		format := r.uint32()
+		sample := &sflowReader{data: r.bytes(int(r.uint32()))}
+		if r.err != nil {
+			return flows, r.err
+		}
+
+		// the enterprise is in the top 20 bits of the format, only standard samples are decoded
+		switch format {
+		case sflowFlowSample, sflowExpandedFlowSample:
+			flow, err := decodeSFlowSample(sample, format == sflowExpandedFlowSample)
+			if err != nil {
+				return flows, err
+			}
+			if flow == nil {
+				continue
+			}
+			flow.ExporterAddr = agent
+			flow.StartTimestamp = ts
+			flow.EndTimestamp = ts
+			flows = append(flows, flow)
+		}
+	}
+	return flows, nil
+}
+
+// decodeSFlowSample decodes a flow sample, or nil if it has no record describing the sampled packet
+func decodeSFlowSample(r *sflowReader, expanded bool) (*Flow, error) {
+	r.uint32() // sequence number
+	if expanded {
+		r.uint32() // source ID type
+		r.uint32() // source ID index
+	} else {
+		r.uint32() // source ID
+	}
+	rate := uint64(r.uint32())
+	r.uint32() // sample pool
+	r.uint32() // drops
+
+	flow := &Flow{FlowType: TypeSFlow5}
+	if expanded {
+		r.uint32() // input interface format
+		flow.InputInterface = r.uint32()
+		r.uint32() // output interface format
+		flow.OutputInterface = r.uint32()
+	} else {
+		// the interface format is held in the top 2 bits
+		flow.InputInterface = r.uint32() & 0x3fffffff
+		flow.OutputInterface = r.uint32() & 0x3fffffff
+	}
+	recordCount := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+	if rate == 0 {
+		rate = 1
+	}
+
+	decoded := false
+	for i := uint32(0); i < recordCount; i++ {
+		format := r.uint32()
+		record := &sflowReader{data: r.bytes(int(r.uint32()))}
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		var err error
+		switch format {
+		case sflowRawPacketHeader:
+			err = decodeSFlowRawPacketHeader(record, flow)
+		case sflowSampledIPv4:
+			err = decodeSFlowSampledIP(record, flow, net.IPv4len)
+		case sflowSampledIPv6:
+			err = decodeSFlowSampledIP(record, flow, net.IPv6len)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		decoded = true
+	}
+	if !decoded {
+		return nil, nil
+	}
+
+	flow.Bytes *= rate
+	flow.Packets = rate
+	return flow, nil
+}
+
+func decodeSFlowRawPacketHeader(r *sflowReader, flow *Flow) error {
+	protocol := r.uint32()
+	frameLength := r.uint32()
+	r.uint32() // stripped
+	header := r.bytes(int(r.uint32()))
+	if r.err != nil {
+		return r.err
+	}
+
+	flow.Bytes = uint64(frameLength)
+	if protocol == sflowHeaderProtocolEthernet {
+		decodeEthernetHeader(header, flow)
+	}
+	return nil
+}
+
+func decodeSFlowSampledIP(r *sflowReader, flow *Flow, addrLen int) error {
+	length := r.uint32()
+	protocol := r.uint32()
+	flow.SrcAddr = readIP(r.bytes(addrLen))
+	flow.DstAddr = readIP(r.bytes(addrLen))
+	srcPort := r.uint32()
+	dstPort := r.uint32()
+	tcpFlags := r.uint32()
+	tos := r.uint32() // priority for IPv6
+	if r.err != nil {
+		return r.err
+	}
+
+	flow.Bytes = uint64(length)
+	flow.IPProtocol = uint8(protocol)
+	flow.SrcPort = uint16(srcPort)
+	flow.DstPort = uint16(dstPort)
+	flow.TCPFlags = uint8(tcpFlags)
+	flow.Tos = uint8(tos)
+	return nil
+}
+
+// decodeEthernetHeader fills the flow with the addresses and ports of the sampled packet.
+// The header is usually truncated, the fields it doesn't hold are left empty.
+func decodeEthernetHeader(data []byte, flow *Flow) {
+	if len(data) < 14 {
+		return
+	}
+	etherType := binary.BigEndian.Uint16(data[12:])
+	data = data[14:]
+	for etherType == etherTypeDot1Q {
+		if len(data) < 4 {
+			return
+		}
+		etherType = binary.BigEndian.Uint16(data[2:])
+		data = data[4:]
+	}
+
+	var transport []byte
+	switch etherType {
+	case etherTypeIPv4:
+		if len(data) < 20 {
+			return
+		}
+		headerLength := int(data[0]&0x0f) * 4
+		flow.Tos = data[1]
+		flow.IPProtocol = data[9]
+		flow.SrcAddr = readIP(data[12:16])
+		flow.DstAddr = readIP(data[16:20])
+		if headerLength >= 20 && len(data) >= headerLength {
+			transport = data[headerLength:]
+		}
+	case etherTypeIPv6:
+		if len(data) < 40 {
+			return
+		}
+		flow.Tos = uint8(binary.BigEndian.Uint16(data) >> 4)
+		flow.IPProtocol = data[6]
+		flow.SrcAddr = readIP(data[8:24])
+		flow.DstAddr = readIP(data[24:40])
+		transport = data[40:]
+	default:
+		return
+	}
+
+	switch flow.IPProtocol {
+	case ipProtocolTCP:
+		if len(transport) >= 14 {
+			flow.TCPFlags = transport[13]
+		}
+		fallthrough
+	case ipProtocolUDP:
+		if len(transport) >= 4 {
+			flow.SrcPort = binary.BigEndian.Uint16(transport)
+			flow.DstPort = binary.BigEndian.Uint16(transport[2:])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/json"
+	"expvar"
+)
+
+var (
+	netflowExpvars          = expvar.NewMap("netflow")
+	netflowPackets          = expvar.Int{}
+	netflowDecodeErrors     = expvar.Int{}
+	netflowFlows            = expvar.Int{}
+	netflowFlowsDropped     = expvar.Int{}
+	netflowMissingTemplates = expvar.Int{}
+	netflowFlowsFlushed     = expvar.Int{}
+	netflowTemplatesEvicted = expvar.Int{}
+	netflowTemplatesExpired = expvar.Int{}
+)
+
+func init() {
+	netflowExpvars.Set("Packets", &netflowPackets)
+	netflowExpvars.Set("DecodeErrors", &netflowDecodeErrors)
+	netflowExpvars.Set("Flows", &netflowFlows)
+	netflowExpvars.Set("FlowsDropped", &netflowFlowsDropped)
+	netflowExpvars.Set("MissingTemplates", &netflowMissingTemplates)
+	netflowExpvars.Set("FlowsFlushed", &netflowFlowsFlushed)
+	netflowExpvars.Set("TemplatesEvicted", &netflowTemplatesEvicted)
+	netflowExpvars.Set("TemplatesExpired", &netflowTemplatesExpired)
+}
+
+// GetStatus returns key-value data for use in status reporting of the netflow server.
+func GetStatus() map[string]interface{} {
+	status := make(map[string]interface{})
+
+	metricsJSON := []byte(expvar.Get("netflow").String())
+	metrics := make(map[string]interface{})
+	json.Unmarshal(metricsJSON, &metrics) //nolint:errcheck
+	status["metrics"] = metrics
+
+	if startError != nil {
+		status["error"] = startError.Error()
+	}
+
+	return status
+}
@@ -0,0 +1,295 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package netflow
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// variableLength is the length of the IPFIX fields whose length is encoded in each record
+const variableLength = 65535
+
+// Information elements shared by NetFlow v9 and IPFIX
+const (
+	fieldInBytes                = 1
+	fieldInPkts                 = 2
+	fieldProtocol               = 4
+	fieldTos                    = 5
+	fieldTCPFlags               = 6
+	fieldL4SrcPort              = 7
+	fieldIPv4SrcAddr            = 8
+	fieldInputSNMP              = 10
+	fieldL4DstPort              = 11
+	fieldIPv4DstAddr            = 12
+	fieldOutputSNMP             = 14
+	fieldLastSwitched           = 21
+	fieldFirstSwitched          = 22
+	fieldOutBytes               = 23
+	fieldOutPkts                = 24
+	fieldIPv6SrcAddr            = 27
+	fieldIPv6DstAddr            = 28
+	fieldSamplingInterval       = 34
+	fieldFlowStartSeconds       = 150
+	fieldFlowEndSeconds         = 151
+	fieldFlowStartMilliseconds  = 152
+	fieldFlowEndMilliseconds    = 153
+	fieldSystemInitTimeMilliSec = 160
+)
+
+type templateField struct {
+	id         uint16
+	enterprise uint32
+	length     uint16
+}
+
+type template struct {
+	fields []templateField
+	// the data records of options templates describe the exporter, not flows
+	options bool
+}
+
+type templateKey struct {
+	exporter string
+	version  uint16
+	// source ID for NetFlow v9, observation domain ID for IPFIX
+	domain uint32
+	id     uint16
+}
+
+type cachedTemplate struct {
+	*template
+	// receive time of the last definition of the template, the exporters sending their
+	// templates periodically
+	updated time.Time
+}
+
+// templateCache holds the templates sent by exporters. It is safe for concurrent use,
+// as an exporter may send its datagrams to several listeners.
+// The templates are held by exporter, each exporter being limited to maxPerExporter templates
+// so that a misbehaving exporter can't grow the cache unbounded nor evict the templates of
+// others. The templates not refreshed within ttl are expired.
+type templateCache struct {
+	mu             sync.RWMutex
+	templates      map[string]map[templateKey]*cachedTemplate
+	maxPerExporter int
+	ttl            time.Duration
+	lastPurge      time.Time
+}
+
+func newTemplateCache(maxPerExporter int, ttl time.Duration) *templateCache {
+	return &templateCache{
+		templates:      make(map[string]map[templateKey]*cachedTemplate),
+		maxPerExporter: maxPerExporter,
+		ttl:            ttl,
+	}
+}
+
+// get returns the template of the key, or nil if it is unknown or expired at the given time
+func (c *templateCache) get(key templateKey, now time.Time) *template {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cached := c.templates[key.exporter][key]
+	if cached == nil || now.Sub(cached.updated) > c.ttl {
+		return nil
+	}
+	return cached.template
+}
+
+// set adds or refreshes a template received at the given time. When the exporter already has
+// maxPerExporter templates, its least recently updated one is evicted.
+func (c *templateCache) set(key templateKey, t *template, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastPurge) >= c.ttl {
+		c.purge(now)
+	}
+
+	exporterTemplates := c.templates[key.exporter]
+	if exporterTemplates == nil {
+		exporterTemplates = make(map[templateKey]*cachedTemplate)
+		c.templates[key.exporter] = exporterTemplates
+	}
+	if _, ok := exporterTemplates[key]; !ok && len(exporterTemplates) >= c.maxPerExporter {
+		var oldest templateKey
+		var oldestUpdate time.Time
+		for k, cached := range exporterTemplates {
+			if oldestUpdate.IsZero() || cached.updated.Before(oldestUpdate) {
+				oldest, oldestUpdate = k, cached.updated
+			}
+		}
+		delete(exporterTemplates, oldest)
+		netflowTemplatesEvicted.Add(1)
+	}
+	exporterTemplates[key] = &cachedTemplate{template: t, updated: now}
+}
+
+func (c *templateCache) delete(key templateKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	exporterTemplates := c.templates[key.exporter]
+	delete(exporterTemplates, key)
+	if len(exporterTemplates) == 0 {
+		delete(c.templates, key.exporter)
+	}
+}
+
+// purge removes the expired templates, along with the exporters left without templates.
+// It must be called with the lock held.
+func (c *templateCache) purge(now time.Time) {
+	for exporter, exporterTemplates := range c.templates {
+		for key, cached := range exporterTemplates {
+			if now.Sub(cached.updated) > c.ttl {
+				delete(exporterTemplates, key)
+				netflowTemplatesExpired.Add(1)
+			}
+		}
+		if len(exporterTemplates) == 0 {
+			delete(c.templates, exporter)
+		}
+	}
+	c.lastPurge = now
+}
+
+// recordContext holds the information of a datagram header needed to decode its data records
+type recordContext struct {
+	flowType FlowType
+	exporter net.IP
+	// export time of the datagram, in seconds since epoch
+	exportTime uint64
+	// boot time of the exporter, in milliseconds since epoch, used by the fields relative
+	// to the uptime of the exporter. 0 if unknown.
+	bootTime uint64
+	// receive time of the datagram
+	receiveTime time.Time
+}
+
+// decodeDataRecord decodes a data record, returning the flow and the size of the record,
+// or 0 if the record is truncated, which happens with the padding at the end of sets
+func decodeDataRecord(t *template, data []byte, ctx recordContext) (*Flow, int) {
+	flow := &Flow{
+		FlowType:     ctx.flowType,
+		ExporterAddr: ctx.exporter,
+	}
+
+	var firstSwitched, lastSwitched, sampling, outBytes, outPkts uint64
+	bootTime := ctx.bootTime
+	offset := 0
+	for _, field := range t.fields {
+		length := int(field.length)
+		if field.length == variableLength {
+			if offset >= len(data) {
+				return nil, 0
+			}
+			length = int(data[offset])
+			offset++
+			if length == 255 {
+				if offset+2 > len(data) {
+					return nil, 0
+				}
+				length = int(binary.BigEndian.Uint16(data[offset:]))
+				offset += 2
+			}
+		}
+		if offset+length > len(data) {
+			return nil, 0
+		}
+		value := data[offset : offset+length]
+		offset += length
+
+		if field.enterprise != 0 {
+			continue
+		}
+
+		switch field.id {
+		case fieldInBytes:
+			flow.Bytes = readUint(value)
+		case fieldInPkts:
+			flow.Packets = readUint(value)
+		case fieldOutBytes:
+			outBytes = readUint(value)
+		case fieldOutPkts:
+			outPkts = readUint(value)
+		case fieldProtocol:
+			flow.IPProtocol = uint8(readUint(value))
+		case fieldTos:
+			flow.Tos = uint8(readUint(value))
+		case fieldTCPFlags:
+			flow.TCPFlags = uint8(readUint(value))
+		case fieldL4SrcPort:
+			flow.SrcPort = uint16(readUint(value))
+		case fieldL4DstPort:
+			flow.DstPort = uint16(readUint(value))
+		case fieldInputSNMP:
+			flow.InputInterface = uint32(readUint(value))
+		case fieldOutputSNMP:
+			flow.OutputInterface = uint32(readUint(value))
+		case fieldIPv4SrcAddr, fieldIPv6SrcAddr:
+			flow.SrcAddr = readIP(value)
+		case fieldIPv4DstAddr, fieldIPv6DstAddr:
+			flow.DstAddr = readIP(value)
+		case fieldFirstSwitched:
+			firstSwitched = readUint(value)
+		case fieldLastSwitched:
+			lastSwitched = readUint(value)
+		case fieldFlowStartSeconds:
+			flow.StartTimestamp = readUint(value)
+		case fieldFlowEndSeconds:
+			flow.EndTimestamp = readUint(value)
+		case fieldFlowStartMilliseconds:
+			flow.StartTimestamp = readUint(value) / 1000
+		case fieldFlowEndMilliseconds:
+			flow.EndTimestamp = readUint(value) / 1000
+		case fieldSystemInitTimeMilliSec:
+			bootTime = readUint(value)
+		case fieldSamplingInterval:
+			sampling = readUint(value)
+		}
+	}
+
+	// egress flows may only be reported with the out counters
+	if flow.Bytes == 0 && flow.Packets == 0 {
+		flow.Bytes, flow.Packets = outBytes, outPkts
+	}
+	if sampling > 1 {
+		flow.Bytes *= sampling
+		flow.Packets *= sampling
+	}
+
+	if flow.StartTimestamp == 0 && bootTime != 0 && firstSwitched != 0 {
+		flow.StartTimestamp = (bootTime + firstSwitched) / 1000
+	}
+	if flow.EndTimestamp == 0 && bootTime != 0 && lastSwitched != 0 {
+		flow.EndTimestamp = (bootTime + lastSwitched) / 1000
+	}
+	if flow.StartTimestamp == 0 {
+		flow.StartTimestamp = ctx.exportTime
+	}
+	if flow.EndTimestamp == 0 {
+		flow.EndTimestamp = ctx.exportTime
+	}
+
+	return flow, offset
+}
+
+// readUint reads an unsigned integer encoded with a reduced size, as allowed by NetFlow v9 and IPFIX
+func readUint(value []byte) uint64 {
+	var v uint64
+	for _, b := range value {
+		v = v<<8 | uint64(b)
+	}
+	return v
+}
+
+func readIP(value []byte) net.IP {
+	if len(value) != net.IPv4len && len(value) != net.IPv6len {
+		return nil
+	}
+	return net.IP(append([]byte(nil), value...))
+}
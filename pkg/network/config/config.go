@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -56,6 +59,11 @@ type Config struct {
 	// These stats objects get flushed on every client request (default 30s check interval)
 	MaxDNSStats int
 
+	// DNSFailedLookupsRateLimit is the maximum number of failed DNS lookups per second recorded for
+	// troubleshooting, the most recent ones being kept. The recording is disabled when it is 0.
+	// It is relevant *only* when DNSInspection and CollectDNSStats is enabled.
+	DNSFailedLookupsRateLimit int
+
 	// EnableHTTPMonitoring specifies whether the tracer should monitor HTTP traffic
 	EnableHTTPMonitoring bool
 
@@ -63,6 +71,21 @@ type Config struct {
 	// Supported libraries: OpenSSL
 	EnableHTTPSMonitoring bool
 
+	// EnableHTTP2Monitoring specifies whether the packets of plain HTTP/2 connections, including gRPC, should be
+	// passed to userspace to be decoded. It is relevant *only* when EnableHTTPMonitoring is enabled.
+	EnableHTTP2Monitoring bool
+
+	// EnableProtocolClassification specifies whether the TCP payloads should be captured to classify the application
+	// protocol of the connections, and to collect the operation stats of the PostgreSQL, Redis and Kafka connections
+	EnableProtocolClassification bool
+
+	// EnableHTTPPathNormalization specifies whether the numeric, UUID and hexadecimal segments of HTTP paths
+	// should be replaced with placeholders, so that requests to the same endpoint are aggregated together
+	EnableHTTPPathNormalization bool
+
+	// HTTPReplaceRules are rewrite rules applied to HTTP paths, in order, before their stats are aggregated
+	HTTPReplaceRules []*ReplaceRule
+
 	// UDPConnTimeout determines the length of traffic inactivity between two
 	// (IP, port)-pairs before declaring a UDP connection as inactive. This is
 	// set to /proc/sys/net/netfilter/nf_conntrack_udp_timeout on Linux by
@@ -151,6 +174,18 @@ type Config struct {
 	RecordedQueryTypes []string
 }
 
+// ReplaceRule specifies a rewrite rule applied to HTTP paths
+type ReplaceRule struct {
+	// Pattern specifies the regexp pattern to be used when replacing. It must compile.
+	Pattern string `mapstructure:"pattern"`
+
+	// Re holds the compiled Pattern and is only used internally.
+	Re *regexp.Regexp `mapstructure:"-"`
+
+	// Repl specifies the replacement string to be used when Pattern matches.
+	Repl string `mapstructure:"repl"`
+}
+
 func join(pieces ...string) string {
 	return strings.Join(pieces, ".")
 }
@@ -190,10 +225,18 @@ func New() *Config {
 		MaxDNSStatsBuffered: 75000,
 		DNSTimeout:          time.Duration(cfg.GetInt(join(spNS, "dns_timeout_in_s"))) * time.Second,
 
+		DNSFailedLookupsRateLimit: cfg.GetInt(join(netNS, "dns_failed_lookups_rate_limit")),
+
 		EnableHTTPMonitoring:  cfg.GetBool(join(netNS, "enable_http_monitoring")),
 		EnableHTTPSMonitoring: cfg.GetBool(join(netNS, "enable_https_monitoring")),
+		EnableHTTP2Monitoring: cfg.GetBool(join(netNS, "enable_http2_monitoring")),
 		MaxHTTPStatsBuffered:  100000,
 
+		EnableProtocolClassification: cfg.GetBool(join(netNS, "enable_protocol_classification")),
+
+		EnableHTTPPathNormalization: cfg.GetBool(join(netNS, "enable_http_path_normalization")),
+		HTTPReplaceRules:            httpReplaceRules(cfg),
+
 		EnableConntrack:              cfg.GetBool(join(spNS, "enable_conntrack")),
 		ConntrackMaxStateSize:        cfg.GetInt(join(spNS, "conntrack_max_state_size")),
 		ConntrackRateLimit:           cfg.GetInt(join(spNS, "conntrack_rate_limit")),
@@ -233,3 +276,40 @@ func New() *Config {
 
 	return c
 }
+
+// httpReplaceRules returns the HTTP path rewrite rules found in the configuration. Rules whose
+// pattern doesn't compile are ignored.
+func httpReplaceRules(cfg ddconfig.Config) []*ReplaceRule {
+	key := join(netNS, "http_replace_rules")
+	if !cfg.IsSet(key) {
+		return nil
+	}
+
+	var rules []*ReplaceRule
+	if err := cfg.UnmarshalKey(key, &rules); err != nil {
+		log.Errorf("Bad format for %q it should be of the form '[{\"pattern\":\"pattern\",\"repl\":\"replace_str\"}]', error: %v", key, err)
+		return nil
+	}
+
+	valid := rules[:0]
+	for _, r := range rules {
+		if err := compileReplaceRule(r); err != nil {
+			log.Errorf("Ignoring invalid rule of %q: %s", key, err)
+			continue
+		}
+		valid = append(valid, r)
+	}
+	return valid
+}
+
+func compileReplaceRule(r *ReplaceRule) error {
+	if r.Pattern == "" {
+		return errors.New(`all rules must have a "pattern"`)
+	}
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("pattern %q: %s", r.Pattern, err)
+	}
+	r.Re = re
+	return nil
+}
@@ -230,3 +230,34 @@ func TestSettingMaxDNSStats(t *testing.T) {
 		assert.Equal(t, 10000, cfg.MaxDNSStats)
 	})
 }
+
+func TestHTTPPathNormalization(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.False(t, cfg.EnableHTTPPathNormalization)
+		assert.Empty(t, cfg.HTTPReplaceRules)
+	})
+
+	t.Run("via YAML", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		_, err := sysconfig.New("./testdata/TestDDAgentConfigYamlAndSystemProbeConfig-HTTPReplaceRules.yaml")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.True(t, cfg.EnableHTTPPathNormalization)
+		// the invalid rule is ignored
+		require.Len(t, cfg.HTTPReplaceRules, 2)
+		assert.Equal(t, "^/users/[^/]+", cfg.HTTPReplaceRules[0].Pattern)
+		assert.Equal(t, "/users/{user}", cfg.HTTPReplaceRules[0].Repl)
+		assert.Equal(t, "/users/{user}/orders", cfg.HTTPReplaceRules[0].Re.ReplaceAllString("/users/john/orders", cfg.HTTPReplaceRules[0].Repl))
+		assert.Equal(t, "/static/.*", cfg.HTTPReplaceRules[1].Pattern)
+	})
+}
@@ -0,0 +1,87 @@
+package debugging
+
+import (
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+	"github.com/google/gopacket/layers"
+)
+
+// Summary represents a (debug-friendly) view of the DNS stats the connections payload has no field for:
+// the TTLs of the answers, and the stats of the lookups answered by each resolver
+type Summary struct {
+	TTLs      []TTLStats
+	Resolvers []ResolverStats
+}
+
+// TTLStats holds the lowest and highest TTLs, in seconds, of the answers of the successful responses
+// to the lookups of a domain, for a given query type, sent by a client to a DNS server
+type TTLStats struct {
+	Client    Address
+	Server    Address
+	Domain    string
+	QueryType string
+	// Count is the number of responses whose answers had a TTL
+	Count  uint32
+	MinTTL uint32
+	MaxTTL uint32
+}
+
+// ResolverStats holds the stats of the lookups answered by a resolver
+type ResolverStats struct {
+	Resolver          string
+	Timeouts          uint32
+	SuccessLatencySum uint64
+	FailureLatencySum uint64
+	CountByRcode      map[uint32]uint32
+}
+
+// Address represents represents a IP:Port
+type Address struct {
+	IP   string
+	Port uint16
+}
+
+// DNS returns a debug-friendly representation of the TTLs of the DNS stats of the connections,
+// sorted by domain, and of the stats of each resolver, sorted by resolver
+func DNS(conns []network.ConnectionStats, resolvers map[string]dns.Stats) Summary {
+	var summary Summary
+	for _, c := range conns {
+		for domain, byType := range c.DNSStatsByDomainByQueryType {
+			for queryType, stats := range byType {
+				if stats.TTLCount == 0 {
+					continue
+				}
+
+				summary.TTLs = append(summary.TTLs, TTLStats{
+					Client:    Address{IP: c.Source.String(), Port: c.SPort},
+					Server:    Address{IP: c.Dest.String(), Port: c.DPort},
+					Domain:    domain.Get().(string),
+					QueryType: layers.DNSType(queryType).String(),
+					Count:     stats.TTLCount,
+					MinTTL:    stats.MinTTL,
+					MaxTTL:    stats.MaxTTL,
+				})
+			}
+		}
+	}
+	sort.SliceStable(summary.TTLs, func(i, j int) bool {
+		return summary.TTLs[i].Domain < summary.TTLs[j].Domain
+	})
+
+	for resolver, stats := range resolvers {
+		summary.Resolvers = append(summary.Resolvers, ResolverStats{
+			Resolver:          resolver,
+			Timeouts:          stats.Timeouts,
+			SuccessLatencySum: stats.SuccessLatencySum,
+			FailureLatencySum: stats.FailureLatencySum,
+			CountByRcode:      stats.CountByRcode,
+		})
+	}
+	sort.Slice(summary.Resolvers, func(i, j int) bool {
+		return summary.Resolvers[i].Resolver < summary.Resolvers[j].Resolver
+	})
+
+	return summary
+}
@@ -0,0 +1,72 @@
+//+build windows linux_bpf
+
+package dns
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// failedLookupsBufferSize is the number of most recent failed lookups kept
+const failedLookupsBufferSize = 100
+
+// failedLookupBuffer keeps the most recent failed lookups, meant for troubleshooting. The lookups
+// are rate-limited, and the oldest one is overwritten when the buffer is full, so that recording
+// a lookup never blocks the processing of DNS packets.
+type failedLookupBuffer struct {
+	// Telemetry is at the beginning of the struct to keep all fields 64-bit aligned.
+	// see https://staticcheck.io/docs/checks#SA1027
+	recorded    int64
+	rateLimited int64
+
+	limiter *rate.Limiter
+
+	mux     sync.Mutex
+	lookups []FailedLookup
+	// index of the oldest lookup once the buffer is full
+	next int
+}
+
+// newFailedLookupBuffer returns a buffer recording at most ratePerSec failed lookups per second
+func newFailedLookupBuffer(ratePerSec int) *failedLookupBuffer {
+	return &failedLookupBuffer{
+		limiter: rate.NewLimiter(rate.Limit(ratePerSec), ratePerSec),
+		lookups: make([]FailedLookup, 0, failedLookupsBufferSize),
+	}
+}
+
+func (b *failedLookupBuffer) record(lookup FailedLookup) {
+	if !b.limiter.Allow() {
+		atomic.AddInt64(&b.rateLimited, 1)
+		return
+	}
+	atomic.AddInt64(&b.recorded, 1)
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if len(b.lookups) < failedLookupsBufferSize {
+		b.lookups = append(b.lookups, lookup)
+		return
+	}
+	b.lookups[b.next] = lookup
+	b.next = (b.next + 1) % failedLookupsBufferSize
+}
+
+// Snapshot returns a copy of the most recent failed lookups, oldest first
+func (b *failedLookupBuffer) Snapshot() []FailedLookup {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	snapshot := make([]FailedLookup, 0, len(b.lookups))
+	snapshot = append(snapshot, b.lookups[b.next:]...)
+	return append(snapshot, b.lookups[:b.next]...)
+}
+
+// Stats returns a map of counters, meant to be reported as telemetry
+func (b *failedLookupBuffer) Stats() map[string]int64 {
+	return map[string]int64{
+		"failed_lookups":              atomic.LoadInt64(&b.recorded),
+		"failed_lookups_rate_limited": atomic.LoadInt64(&b.rateLimited),
+	}
+}
@@ -36,6 +36,14 @@ func (nullReverseDNS) GetStats() map[string]int64 {
 	}
 }
 
+func (nullReverseDNS) GetResolverStats() map[string]Stats {
+	return nil
+}
+
+func (nullReverseDNS) FailedLookups() []FailedLookup {
+	return nil
+}
+
 func (nullReverseDNS) Close() {}
 
 var _ ReverseDNS = nullReverseDNS{}
@@ -206,6 +206,7 @@ func (p *dnsParser) parseAnswerInto(
 	alias := p.extractCNAME(question.Name, dns.Answers)
 	p.extractIPsInto(alias, dns.Answers, t)
 	t.dns = string(bytes.ToLower(question.Name))
+	pktInfo.ttl, pktInfo.hasTTL = minAnswerTTL(dns.Answers)
 
 	pktInfo.pktType = successfulResponse
 	return nil
@@ -238,6 +239,22 @@ func (*dnsParser) extractIPsInto(alias []byte, records []layers.DNSResourceRecor
 	}
 }
 
+// minAnswerTTL returns the lowest TTL of the answers, which is how long the whole answer can be cached
+func minAnswerTTL(records []layers.DNSResourceRecord) (uint32, bool) {
+	var ttl uint32
+	found := false
+	for _, record := range records {
+		if record.Class != layers.DNSClassIN {
+			continue
+		}
+		if !found || record.TTL < ttl {
+			ttl = record.TTL
+			found = true
+		}
+	}
+	return ttl, found
+}
+
 func (p *dnsParser) isWantedQueryType(checktype layers.DNSType) bool {
 	_, ok := p.recordedQueryTypes[checktype]
 	return ok
@@ -36,6 +36,7 @@ type socketFilterSnooper struct {
 	parser          *dnsParser
 	cache           *reverseDNSCache
 	statKeeper      *dnsStatKeeper
+	failedLookups   *failedLookupBuffer
 	exit            chan struct{}
 	wg              sync.WaitGroup
 	collectLocalDNS bool
@@ -67,8 +68,13 @@ type packetSource interface {
 func newSocketFilterSnooper(cfg *config.Config, source packetSource) (*socketFilterSnooper, error) {
 	cache := newReverseDNSCache(dnsCacheSize, dnsCacheExpirationPeriod)
 	var statKeeper *dnsStatKeeper
+	var failedLookups *failedLookupBuffer
 	if cfg.CollectDNSStats {
-		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, cfg.MaxDNSStats)
+		if cfg.DNSFailedLookupsRateLimit > 0 {
+			failedLookups = newFailedLookupBuffer(cfg.DNSFailedLookupsRateLimit)
+			log.Infof("Recording of DNS failed lookups has been enabled. Maximum number of failed lookups recorded per second: %d", cfg.DNSFailedLookupsRateLimit)
+		}
+		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, cfg.MaxDNSStats, failedLookups)
 		log.Infof("DNS Stats Collection has been enabled. Maximum number of stats objects: %d", cfg.MaxDNSStats)
 		if cfg.CollectDNSDomains {
 			log.Infof("DNS domain collection has been enabled")
@@ -81,6 +87,7 @@ func newSocketFilterSnooper(cfg *config.Config, source packetSource) (*socketFil
 		parser:          newDNSParser(source.PacketType(), cfg),
 		cache:           cache,
 		statKeeper:      statKeeper,
+		failedLookups:   failedLookups,
 		translation:     new(translation),
 		exit:            make(chan struct{}),
 		collectLocalDNS: cfg.CollectLocalDNS,
@@ -115,6 +122,22 @@ func (s *socketFilterSnooper) GetDNSStats() StatsByKeyByNameByType {
 	return s.statKeeper.GetAndResetAllStats()
 }
 
+// GetResolverStats returns the stats of the lookups answered by each resolver, keyed by resolver IP
+func (s *socketFilterSnooper) GetResolverStats() map[string]Stats {
+	if s.statKeeper == nil {
+		return nil
+	}
+	return s.statKeeper.GetResolverStats()
+}
+
+// FailedLookups returns the most recent failed lookups, or nil if their recording is disabled
+func (s *socketFilterSnooper) FailedLookups() []FailedLookup {
+	if s.failedLookups == nil {
+		return nil
+	}
+	return s.failedLookups.Snapshot()
+}
+
 // GetStats returns stats for use with telemetry
 func (s *socketFilterSnooper) GetStats() map[string]int64 {
 	stats := s.cache.Stats()
@@ -134,6 +157,11 @@ func (s *socketFilterSnooper) GetStats() map[string]int64 {
 		stats["num_stats"] = int64(numStats)
 		stats["dropped_stats"] = int64(droppedStats)
 	}
+	if s.failedLookups != nil {
+		for key, value := range s.failedLookups.Stats() {
+			stats[key] = value
+		}
+	}
 	return stats
 }
 
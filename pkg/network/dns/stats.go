@@ -7,7 +7,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/google/gopacket/layers"
 	"go4.org/intern"
 )
 
@@ -27,6 +29,8 @@ const (
 // for 10000 entries.
 const (
 	maxStateMapSize = 10000
+	// maxResolverStats limits the number of resolvers we keep stats for
+	maxResolverStats = 1000
 )
 
 type dnsPacketInfo struct {
@@ -36,6 +40,8 @@ type dnsPacketInfo struct {
 	rCode         uint8         // responseCode
 	question      *intern.Value // only relevant for query packets
 	queryType     QueryType
+	ttl           uint32 // lowest TTL of the answers, only relevant if hasTTL is set
+	hasTTL        bool
 }
 
 type stateKey struct {
@@ -63,12 +69,18 @@ type dnsStatKeeper struct {
 	droppedStats     int
 	lastNumStats     int32
 	lastDroppedStats int32
+	// stats of the lookups by resolver, which are kept since startup
+	resolverStats map[util.Address]Stats
+	// failedLookups is nil if the recording of failed lookups is disabled
+	failedLookups *failedLookupBuffer
 }
 
-func newDNSStatkeeper(timeout time.Duration, maxStats int) *dnsStatKeeper {
+func newDNSStatkeeper(timeout time.Duration, maxStats int, failedLookups *failedLookupBuffer) *dnsStatKeeper {
 	statsKeeper := &dnsStatKeeper{
 		stats:            make(StatsByKeyByNameByType),
 		state:            make(map[stateKey]stateValue),
+		resolverStats:    make(map[util.Address]Stats),
+		failedLookups:    failedLookups,
 		expirationPeriod: timeout,
 		exit:             make(chan struct{}),
 		maxSize:          maxStateMapSize,
@@ -121,6 +133,13 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 	d.deleteCount++
 
 	latency := microSecs(ts) - start.ts
+	// Note: time.Duration in the agent version of go (1.12.9) does not have the Microseconds method.
+	timeout := latency > uint64(d.expirationPeriod.Microseconds())
+
+	d.updateResolverStats(info, latency, timeout)
+	if timeout || info.pktType == failedResponse {
+		d.recordFailedLookup(info.key, start, info.rCode, latency, timeout, ts)
+	}
 
 	allStats, ok := d.stats[info.key]
 	if !ok {
@@ -144,22 +163,82 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 		d.numStats++
 	}
 
-	// Note: time.Duration in the agent version of go (1.12.9) does not have the Microseconds method.
-	if latency > uint64(d.expirationPeriod.Microseconds()) {
-		byqtype.Timeouts++
-	} else {
-		byqtype.CountByRcode[uint32(info.rCode)]++
-		if info.pktType == successfulResponse {
-			byqtype.SuccessLatencySum += latency
-		} else if info.pktType == failedResponse {
-			byqtype.FailureLatencySum += latency
-		}
-	}
+	addToStats(&byqtype, info, latency, timeout)
 	stats[start.qtype] = byqtype
 	allStats[start.question] = stats
 	d.stats[info.key] = allStats
 }
 
+// addToStats records a response, or a timeout, in stats
+func addToStats(stats *Stats, info dnsPacketInfo, latency uint64, timeout bool) {
+	if timeout {
+		stats.Timeouts++
+		return
+	}
+	stats.CountByRcode[uint32(info.rCode)]++
+	if info.pktType == successfulResponse {
+		stats.SuccessLatencySum += latency
+		if info.hasTTL {
+			stats.AddTTL(info.ttl)
+		}
+	} else if info.pktType == failedResponse {
+		stats.FailureLatencySum += latency
+	}
+}
+
+// updateResolverStats records a response, or a timeout, in the stats of the resolver of the query
+func (d *dnsStatKeeper) updateResolverStats(info dnsPacketInfo, latency uint64, timeout bool) {
+	stats, ok := d.resolverStats[info.key.ServerIP]
+	if !ok {
+		if len(d.resolverStats) >= maxResolverStats {
+			return
+		}
+		stats.CountByRcode = make(map[uint32]uint32)
+	}
+	addToStats(&stats, info, latency, timeout)
+	d.resolverStats[info.key.ServerIP] = stats
+}
+
+// recordFailedLookup records a query which got an error response or timed out
+func (d *dnsStatKeeper) recordFailedLookup(key Key, start stateValue, rCode uint8, latency uint64, timeout bool, ts time.Time) {
+	if d.failedLookups == nil {
+		return
+	}
+	lookup := FailedLookup{
+		Timestamp:     ts,
+		ClientIP:      key.ClientIP.String(),
+		ServerIP:      key.ServerIP.String(),
+		QueryType:     layers.DNSType(start.qtype).String(),
+		Timeout:       timeout,
+		LatencyMicros: latency,
+	}
+	if start.question != nil {
+		lookup.Question, _ = start.question.Get().(string)
+	}
+	if !timeout {
+		lookup.Rcode = rCode
+		lookup.RcodeName = layers.DNSResponseCode(rCode).String()
+	}
+	d.failedLookups.record(lookup)
+}
+
+// GetResolverStats returns a copy of the stats of each resolver, keyed by IP
+func (d *dnsStatKeeper) GetResolverStats() map[string]Stats {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	ret := make(map[string]Stats, len(d.resolverStats))
+	for server, stats := range d.resolverStats {
+		rcodeCopy := make(map[uint32]uint32, len(stats.CountByRcode))
+		for rcode, count := range stats.CountByRcode {
+			rcodeCopy[rcode] = count
+		}
+		stats.CountByRcode = rcodeCopy
+		ret[server.String()] = stats
+	}
+	return ret
+}
+
 func (d *dnsStatKeeper) GetNumStats() (int32, int32) {
 	numStats := atomic.LoadInt32(&d.lastNumStats)
 	droppedStats := atomic.LoadInt32(&d.lastDroppedStats)
@@ -215,6 +294,9 @@ func (d *dnsStatKeeper) removeExpiredStates(earliestTs time.Time) {
 		if v.ts < threshold {
 			delete(d.state, k)
 			d.deleteCount++
+			latency := uint64(d.expirationPeriod.Microseconds())
+			d.updateResolverStats(dnsPacketInfo{key: k.key}, latency, true)
+			d.recordFailedLookup(k.key, v, 0, latency, true, time.Now())
 			// When we expire a state, we need to increment timeout count for that key:domain
 			allStats, ok := d.stats[k.key]
 			if !ok {
@@ -38,7 +38,7 @@ func testLatency(
 	expectedTimeouts uint32,
 ) {
 	var d = intern.GetByString("abc.com")
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, nil)
 	key := getSampleDNSKey()
 	qPkt := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
 	then := time.Now()
@@ -75,7 +75,7 @@ func TestTimeout(t *testing.T) {
 }
 
 func TestExpiredStateRemoval(t *testing.T) {
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, nil)
 	key := getSampleDNSKey()
 	var d = intern.GetByString("abc.com")
 	qPkt1 := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
@@ -102,6 +102,125 @@ func TestExpiredStateRemoval(t *testing.T) {
 	assert.Equal(t, uint32(1), stats[key][d][TypeA].Timeouts)
 }
 
+func TestTTLs(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, nil)
+	key := getSampleDNSKey()
+	var d = intern.GetByString("abc.com")
+	now := time.Now()
+	for i, ttl := range []uint32{300, 30, 60} {
+		id := uint16(i)
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: id, pktType: query, key: key, question: d, queryType: TypeA}, now)
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: id, key: key, pktType: successfulResponse, queryType: TypeA, ttl: ttl, hasTTL: true}, now)
+	}
+	// a response without answers
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 3, pktType: query, key: key, question: d, queryType: TypeA}, now)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 3, key: key, pktType: successfulResponse, queryType: TypeA}, now)
+
+	stats := sk.GetAndResetAllStats()
+	require.Contains(t, stats, key)
+	require.Contains(t, stats[key], d)
+	s := stats[key][d][TypeA]
+	assert.Equal(t, uint32(4), s.CountByRcode[0])
+	assert.Equal(t, uint32(3), s.TTLCount)
+	assert.Equal(t, uint32(30), s.MinTTL)
+	assert.Equal(t, uint32(300), s.MaxTTL)
+}
+
+func TestResolverStats(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, nil)
+	key := getSampleDNSKey()
+	otherKey := key
+	otherKey.ServerIP = util.AddressFromString("8.8.4.4")
+	var d = intern.GetByString("abc.com")
+	then := time.Now()
+	now := then.Add(10 * time.Microsecond)
+
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, key: key, pktType: successfulResponse, queryType: TypeA, ttl: 60, hasTTL: true}, now)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, pktType: query, key: key, question: d, queryType: TypeAAAA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, key: key, pktType: failedResponse, rCode: 3}, now)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 3, pktType: query, key: otherKey, question: d, queryType: TypeA}, then)
+	sk.removeExpiredStates(then.Add(time.Second))
+
+	// resolver stats are not reset with the stats by key
+	sk.GetAndResetAllStats()
+	stats := sk.GetResolverStats()
+	require.Len(t, stats, 2)
+
+	s := stats["8.8.8.8"]
+	assert.Equal(t, map[uint32]uint32{0: 1, 3: 1}, s.CountByRcode)
+	assert.Equal(t, uint64(10), s.SuccessLatencySum)
+	assert.Equal(t, uint64(10), s.FailureLatencySum)
+	assert.Equal(t, uint32(0), s.Timeouts)
+	assert.Equal(t, uint32(60), s.MinTTL)
+
+	s = stats["8.8.4.4"]
+	assert.Empty(t, s.CountByRcode)
+	assert.Equal(t, uint32(1), s.Timeouts)
+}
+
+func TestFailedLookups(t *testing.T) {
+	failedLookups := newFailedLookupBuffer(2)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, failedLookups)
+	key := getSampleDNSKey()
+	var d = intern.GetByString("abc.com")
+	then := time.Now()
+	now := then.Add(10 * time.Microsecond)
+
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, key: key, pktType: successfulResponse, queryType: TypeA}, now)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, key: key, pktType: failedResponse, rCode: 3}, now)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 3, pktType: query, key: key, question: d, queryType: TypeAAAA}, then)
+	sk.removeExpiredStates(then.Add(time.Second))
+	// the rate limit only allows two failed lookups
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 4, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 4, key: key, pktType: failedResponse, rCode: 2}, now)
+
+	lookups := failedLookups.Snapshot()
+	require.Len(t, lookups, 2)
+	nxdomain := lookups[0]
+	assert.Equal(t, FailedLookup{
+		Timestamp:     now,
+		ClientIP:      "1.1.1.1",
+		ServerIP:      "8.8.8.8",
+		Question:      "abc.com",
+		QueryType:     "A",
+		Rcode:         3,
+		RcodeName:     "Non-Existent Domain",
+		LatencyMicros: 10,
+	}, nxdomain)
+
+	timeout := lookups[1]
+	assert.True(t, timeout.Timeout)
+	assert.Equal(t, "AAAA", timeout.QueryType)
+	assert.Zero(t, timeout.Rcode)
+	assert.Equal(t, uint64(DNSTimeoutSecs*time.Second/time.Microsecond), timeout.LatencyMicros)
+
+	assert.Equal(t, map[string]int64{
+		"failed_lookups":              2,
+		"failed_lookups_rate_limited": 1,
+	}, failedLookups.Stats())
+}
+
+func TestFailedLookupsBufferFull(t *testing.T) {
+	failedLookups := newFailedLookupBuffer(failedLookupsBufferSize * 2)
+	for i := 0; i < failedLookupsBufferSize+1; i++ {
+		failedLookups.record(FailedLookup{LatencyMicros: uint64(i)})
+	}
+	assert.Equal(t, int64(failedLookupsBufferSize+1), failedLookups.Stats()["failed_lookups"])
+
+	// the oldest lookup is overwritten
+	lookups := failedLookups.Snapshot()
+	require.Len(t, lookups, failedLookupsBufferSize)
+	assert.Equal(t, uint64(1), lookups[0].LatencyMicros)
+	assert.Equal(t, uint64(failedLookupsBufferSize), lookups[failedLookupsBufferSize-1].LatencyMicros)
+
+	// the snapshot is a copy
+	lookups[0].LatencyMicros = 0
+	assert.Equal(t, uint64(1), failedLookups.Snapshot()[0].LatencyMicros)
+}
+
 func BenchmarkStats(b *testing.B) {
 	key := getSampleDNSKey()
 
@@ -119,7 +238,7 @@ func BenchmarkStats(b *testing.B) {
 			b.ResetTimer()
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				sk := newDNSStatkeeper(1000*time.Second, 10000)
+				sk := newDNSStatkeeper(1000*time.Second, 10000, nil)
 				for j := 0; j < numPackets; j++ {
 					sk.ProcessPacketInfo(packets[j], ts)
 				}
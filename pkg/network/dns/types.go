@@ -1,6 +1,8 @@
 package dns
 
 import (
+	"time"
+
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/google/gopacket/layers"
 	"go4.org/intern"
@@ -42,6 +44,11 @@ type ReverseDNS interface {
 	Resolve([]util.Address) map[util.Address][]string
 	GetDNSStats() StatsByKeyByNameByType
 	GetStats() map[string]int64
+	// GetResolverStats returns the stats of the lookups answered by each resolver since startup, keyed by resolver IP
+	GetResolverStats() map[string]Stats
+	// FailedLookups returns the most recent lookups which failed or timed out, as recorded at a limited rate,
+	// or nil if their recording is disabled
+	FailedLookups() []FailedLookup
 	Close()
 }
 
@@ -60,4 +67,50 @@ type Stats struct {
 	SuccessLatencySum uint64
 	FailureLatencySum uint64
 	CountByRcode      map[uint32]uint32
+	// TTLCount is the number of successful responses whose answers had a TTL, MinTTL
+	// and MaxTTL being the lowest and highest of these TTLs, in seconds
+	TTLCount uint32
+	MinTTL   uint32
+	MaxTTL   uint32
+}
+
+// AddTTL records the TTL of the answers of a successful response
+func (s *Stats) AddTTL(ttl uint32) {
+	if s.TTLCount == 0 || ttl < s.MinTTL {
+		s.MinTTL = ttl
+	}
+	if s.TTLCount == 0 || ttl > s.MaxTTL {
+		s.MaxTTL = ttl
+	}
+	s.TTLCount++
+}
+
+// MergeTTLs merges the TTLs recorded by other into s
+func (s *Stats) MergeTTLs(other Stats) {
+	if other.TTLCount == 0 {
+		return
+	}
+	if s.TTLCount == 0 || other.MinTTL < s.MinTTL {
+		s.MinTTL = other.MinTTL
+	}
+	if s.TTLCount == 0 || other.MaxTTL > s.MaxTTL {
+		s.MaxTTL = other.MaxTTL
+	}
+	s.TTLCount += other.TTLCount
+}
+
+// FailedLookup describes a DNS query which got an error response code, such as NXDOMAIN or SERVFAIL,
+// or no response at all
+type FailedLookup struct {
+	Timestamp time.Time `json:"timestamp"`
+	ClientIP  string    `json:"client_ip"`
+	ServerIP  string    `json:"server_ip"`
+	// Question is empty unless the collection of DNS domains is enabled
+	Question  string `json:"question,omitempty"`
+	QueryType string `json:"query_type"`
+	Rcode     uint8  `json:"rcode"`
+	RcodeName string `json:"rcode_name,omitempty"`
+	Timeout   bool   `json:"timeout"`
+	// LatencyMicros is the time the response took, or the DNS timeout for the queries which timed out
+	LatencyMicros uint64 `json:"latency_us"`
 }
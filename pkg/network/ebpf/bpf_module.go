@@ -53,6 +53,21 @@ func ReadDNSModule(bpfDir string, debug bool) (bytecode.AssetReader, error) {
 	return ebpfReader, nil
 }
 
+// ReadProtocolsModule from the asset file
+func ReadProtocolsModule(bpfDir string, debug bool) (bytecode.AssetReader, error) {
+	file := "protocols.o"
+	if debug {
+		file = "protocols-debug.o"
+	}
+
+	ebpfReader, err := bytecode.GetReader(bpfDir, file)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find asset: %s", err)
+	}
+
+	return ebpfReader, nil
+}
+
 // ReadOffsetBPFModule from the asset file
 func ReadOffsetBPFModule(bpfDir string, debug bool) (bytecode.AssetReader, error) {
 	file := "offset-guess.o"
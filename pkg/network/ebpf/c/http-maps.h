@@ -45,6 +45,17 @@ struct bpf_map_def SEC("maps/http_batch_state") http_batch_state = {
     .namespace = "",
 };
 
+/* This map keeps track of the TCP connections carrying plain HTTP/2 traffic, whose packets are passed to userspace.
+   The value is the last time a packet of the connection was seen, used by userspace to expire the idle connections */
+struct bpf_map_def SEC("maps/http2_conns") http2_conns = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(conn_tuple_t),
+    .value_size = sizeof(__u64),
+    .max_entries = 1, // This will get overridden at runtime using max_tracked_connections
+    .pinning = 0,
+    .namespace = "",
+};
+
 struct bpf_map_def SEC("maps/ssl_sock_by_ctx") ssl_sock_by_ctx = {
     .type = BPF_MAP_TYPE_HASH,
     .key_size = sizeof(void *),
@@ -0,0 +1,63 @@
+#ifndef __HTTP2_H
+#define __HTTP2_H
+
+#include "tracer.h"
+#include "http-maps.h"
+#include "sock.h"
+
+#ifndef TCPHDR_RST
+#define TCPHDR_RST 0x04
+#endif
+
+// The HTTP/2 connection preface sent by clients starts with "PRI * HTTP/2.0"
+#define HTTP2_PREFACE_SIZE 14
+
+static __always_inline __u64 http2_monitoring_enabled() {
+    __u64 val = 0;
+    LOAD_CONSTANT("http2_monitoring_enabled", val);
+    return val;
+}
+
+static __always_inline int http2_is_preface(struct __sk_buff* skb, u32 offset) {
+    if (skb->len - offset < HTTP2_PREFACE_SIZE) {
+        return 0;
+    }
+
+    char preface[HTTP2_PREFACE_SIZE] = "PRI * HTTP/2.0";
+#pragma unroll
+    for (int i = 0; i < HTTP2_PREFACE_SIZE; i++) {
+        if (load_byte(skb, offset + i) != preface[i]) {
+            return 0;
+        }
+    }
+    return 1;
+}
+
+// http2_filter returns the number of bytes of the packet passed to userspace by the socket filter: the whole
+// packet for the connections that started with the HTTP/2 connection preface, nothing otherwise.
+// Since HTTP/2 frames are not aligned on packets, the payloads are decoded in userspace.
+static __always_inline int http2_filter(struct __sk_buff* skb, skb_info_t *skb_info) {
+    if (!http2_monitoring_enabled()) {
+        return 0;
+    }
+
+    if (http2_is_preface(skb, skb_info->data_off)) {
+        __u64 now = bpf_ktime_get_ns();
+        bpf_map_update_elem(&http2_conns, &skb_info->tup, &now, BPF_ANY);
+        return -1;
+    }
+
+    __u64 *last_seen = bpf_map_lookup_elem(&http2_conns, &skb_info->tup);
+    if (last_seen == NULL) {
+        return 0;
+    }
+    *last_seen = bpf_ktime_get_ns();
+
+    // the closing packet is still passed so that userspace releases the decoding state of the connection
+    if (skb_info->tcp_flags & (TCPHDR_FIN|TCPHDR_RST)) {
+        bpf_map_delete_elem(&http2_conns, &skb_info->tup);
+    }
+    return -1;
+}
+
+#endif
@@ -3,6 +3,7 @@
 #include "ip.h"
 #include "ipv6.h"
 #include "http.h"
+#include "http2.h"
 #include "sock.h"
 #include "sockfd.h"
 
@@ -55,7 +56,7 @@ int socket__http_filter(struct __sk_buff* skb) {
     __builtin_memset(buffer, 0, sizeof(buffer));
     read_skb_data(skb, skb_info.data_off, buffer);
     http_process(buffer, &skb_info, src_port);
-    return 0;
+    return http2_filter(skb, &skb_info);
 }
 
 // This kprobe is used to send batch completion notification to userspace
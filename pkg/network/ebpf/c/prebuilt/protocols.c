@@ -0,0 +1,138 @@
+#include "tracer.h"
+#include "bpf_helpers.h"
+#include "ip.h"
+#include "protocols.h"
+#include "protocols-maps.h"
+
+// TODO: Replace those by injected constants based on system configuration
+// once we have port range detection merged into the codebase.
+#define EPHEMERAL_RANGE_BEG 32768
+#define EPHEMERAL_RANGE_END 60999
+
+#ifndef TCPHDR_RST
+#define TCPHDR_RST 0x04
+#endif
+
+static __always_inline int is_ephemeral_port(u16 port) {
+    return port >= EPHEMERAL_RANGE_BEG && port <= EPHEMERAL_RANGE_END;
+}
+
+// read_payload_size returns the size of the TCP payload of a packet. It is computed from the length of the IP
+// packet rather than from the length of the frame, as the frames of the small segments can be padded.
+static __always_inline __u32 read_payload_size(struct __sk_buff *skb, skb_info_t *skb_info) {
+    __u32 ip_end = ETH_HLEN;
+    if (skb_info->tup.metadata & CONN_V6) {
+        ip_end += sizeof(struct ipv6hdr) + load_half(skb, ETH_HLEN + offsetof(struct ipv6hdr, payload_len));
+    } else {
+        ip_end += load_half(skb, ETH_HLEN + offsetof(struct iphdr, tot_len));
+    }
+
+    if (ip_end > skb->len) {
+        ip_end = skb->len;
+    }
+    if (ip_end <= skb_info->data_off) {
+        return 0;
+    }
+    return ip_end - skb_info->data_off;
+}
+
+static __always_inline void read_payload_prefix(struct __sk_buff *skb, __u32 offset, __u32 size, __u8 *buffer) {
+#pragma unroll
+    for (int i = 0; i < CLASSIFICATION_BUFFER_SIZE; i++) {
+        if (i >= size) {
+            break;
+        }
+        buffer[i] = load_byte(skb, offset + i);
+    }
+}
+
+// tls_handshake_done returns whether a payload starts with a ChangeCipherSpec or an application data record, which
+// either side only sends once the ServerHello and the certificate of the server were sent
+static __always_inline bool tls_handshake_done(struct __sk_buff *skb, __u32 offset, __u32 size) {
+    if (size < 3 || load_byte(skb, offset + 1) != 0x03) {
+        return false;
+    }
+
+    __u8 record_type = load_byte(skb, offset);
+    return record_type == TLS_RECORD_CHANGE_CIPHER_SPEC || record_type == TLS_RECORD_APPLICATION_DATA;
+}
+
+// pass_to_userspace returns whether a packet is passed to userspace: all the packets of the connections whose
+// operations are decoded, and the handshake of the TLS connections. The packets of the other connections are
+// dropped, once classified.
+static __always_inline bool pass_to_userspace(struct __sk_buff *skb, skb_info_t *skb_info, protocol_state_t *state, __u32 size) {
+    switch (state->protocol) {
+    case PROTOCOL_POSTGRES:
+    case PROTOCOL_REDIS:
+    case PROTOCOL_KAFKA:
+        return true;
+    case PROTOCOL_TLS:
+        break;
+    default:
+        return false;
+    }
+
+    if (state->flags & PROTOCOL_TLS_HANDSHAKE_DONE) {
+        return false;
+    }
+    if (tls_handshake_done(skb, skb_info->data_off, size)) {
+        state->flags |= PROTOCOL_TLS_HANDSHAKE_DONE;
+        return false;
+    }
+    return true;
+}
+
+// This function is meant to be used as a BPF_PROG_TYPE_SOCKET_FILTER.
+// It classifies the protocol of the TCP connections from the first bytes of their first payloads, and
+// only passes to userspace the packets needed to decode the operations and the TLS handshakes.
+SEC("socket/protocol_filter")
+int socket__protocol_filter(struct __sk_buff* skb) {
+    skb_info_t skb_info;
+    if (!read_conn_tuple_skb(skb, &skb_info) || !(skb_info.tup.metadata & CONN_TYPE_TCP)) {
+        return 0;
+    }
+
+    // we normalize the tuple to always be (client, server),
+    // so if sport is not in ephemeral port range we flip it
+    bool from_client = is_ephemeral_port(skb_info.tup.sport);
+    if (!from_client) {
+        flip_tuple(&skb_info.tup);
+    }
+
+    __u32 size = read_payload_size(skb, &skb_info);
+    protocol_state_t *state = bpf_map_lookup_elem(&connection_protocols, &skb_info.tup);
+    if (state == NULL) {
+        // the connections are tracked from their first payload
+        if (size == 0) {
+            return 0;
+        }
+
+        protocol_state_t new_state = {};
+        bpf_map_update_elem(&connection_protocols, &skb_info.tup, &new_state, BPF_NOEXIST);
+        state = bpf_map_lookup_elem(&connection_protocols, &skb_info.tup);
+        if (state == NULL) {
+            return 0;
+        }
+    }
+    state->last_seen = bpf_ktime_get_ns();
+
+    if (skb_info.tcp_flags & (TCPHDR_FIN|TCPHDR_RST)) {
+        state->flags |= PROTOCOL_CONN_CLOSED;
+    }
+
+    if (size > 0 && state->protocol == PROTOCOL_UNKNOWN && state->attempts < MAX_CLASSIFICATION_ATTEMPTS) {
+        state->attempts++;
+
+        __u8 buffer[CLASSIFICATION_BUFFER_SIZE];
+        __builtin_memset(buffer, 0, sizeof(buffer));
+        read_payload_prefix(skb, skb_info.data_off, size, buffer);
+        state->protocol = classify(buffer, size, from_client);
+    }
+
+    return pass_to_userspace(skb, &skb_info, state, size) ? -1 : 0;
+}
+
+// This number will be interpreted by elf-loader to set the current running kernel version
+__u32 _version SEC("version") = 0xFFFFFFFE; // NOLINT(bugprone-reserved-identifier)
+
+char _license[] SEC("license") = "GPL"; // NOLINT(bugprone-reserved-identifier)
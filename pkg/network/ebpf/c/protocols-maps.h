@@ -0,0 +1,19 @@
+#ifndef __PROTOCOLS_MAPS_H
+#define __PROTOCOLS_MAPS_H
+
+#include "tracer.h"
+#include "bpf_helpers.h"
+#include "protocols-types.h"
+
+/* This map holds the protocol of the TCP connections, classified from their first payloads.
+   The entries of the closed and idle connections are deleted by userspace */
+struct bpf_map_def SEC("maps/connection_protocols") connection_protocols = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(conn_tuple_t),
+    .value_size = sizeof(protocol_state_t),
+    .max_entries = 1, // This will get overridden at runtime using max_tracked_connections
+    .pinning = 0,
+    .namespace = "",
+};
+
+#endif
@@ -0,0 +1,43 @@
+#ifndef __PROTOCOLS_TYPES_H
+#define __PROTOCOLS_TYPES_H
+
+#include "tracer.h"
+
+// The number of payloads inspected before a connection is considered to run an unknown protocol
+#define MAX_CLASSIFICATION_ATTEMPTS 4
+// The number of bytes of a payload the classifiers look at
+#define CLASSIFICATION_BUFFER_SIZE 16
+
+// Must match the values of protocols.Protocol
+typedef enum
+{
+    PROTOCOL_UNKNOWN = 0,
+    PROTOCOL_HTTP,
+    PROTOCOL_HTTP2,
+    PROTOCOL_TLS,
+    PROTOCOL_POSTGRES,
+    PROTOCOL_MYSQL,
+    PROTOCOL_REDIS,
+    PROTOCOL_KAFKA,
+    PROTOCOL_AMQP,
+    PROTOCOL_MONGODB,
+} protocol_t;
+
+typedef enum
+{
+    // A FIN or RST segment of the connection was seen
+    PROTOCOL_CONN_CLOSED = 1 << 0,
+    // The handshake of the TLS connection is over, its following records are encrypted
+    PROTOCOL_TLS_HANDSHAKE_DONE = 1 << 1,
+} protocol_flags_t;
+
+// This struct holds the classification state of a TCP connection, keyed by its (client, server) tuple
+typedef struct {
+    // The last time a packet of the connection was seen, used by userspace to expire the idle connections
+    __u64 last_seen;
+    __u8 protocol;
+    __u8 attempts;
+    __u8 flags;
+} protocol_state_t;
+
+#endif
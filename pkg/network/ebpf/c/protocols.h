@@ -0,0 +1,240 @@
+#ifndef __PROTOCOLS_H
+#define __PROTOCOLS_H
+
+#include "tracer.h"
+#include "protocols-types.h"
+
+#define TLS_RECORD_CHANGE_CIPHER_SPEC 0x14
+#define TLS_RECORD_HANDSHAKE 0x16
+#define TLS_RECORD_APPLICATION_DATA 0x17
+#define TLS_HANDSHAKE_CLIENT_HELLO 0x01
+#define TLS_HANDSHAKE_SERVER_HELLO 0x02
+
+#define POSTGRES_PROTOCOL_VERSION_3 196608 // 3.0
+#define POSTGRES_CANCEL_REQUEST 80877102 // 1234.5678
+#define POSTGRES_SSL_REQUEST 80877103 // 1234.5679
+#define POSTGRES_GSSENC_REQUEST 80877104 // 1234.5680
+#define POSTGRES_MAX_STARTUP_SIZE 10000
+
+#define MONGO_OP_QUERY 2004
+#define MONGO_OP_COMPRESSED 2012
+#define MONGO_OP_MSG 2013
+
+#define MYSQL_PROTOCOL_VERSION_10 0x0a
+
+#define KAFKA_MAX_API_KEY 67
+#define KAFKA_MAX_API_VERSION 17
+#define KAFKA_MAX_FRAME_SIZE (1 << 26)
+#define KAFKA_HEADER_SIZE 14
+
+static __always_inline __u32 read_big_endian_32(const __u8 *buf) {
+    return (__u32)buf[0] << 24 | (__u32)buf[1] << 16 | (__u32)buf[2] << 8 | (__u32)buf[3];
+}
+
+static __always_inline __u16 read_big_endian_16(const __u8 *buf) {
+    return (__u16)buf[0] << 8 | (__u16)buf[1];
+}
+
+static __always_inline __u32 read_little_endian_32(const __u8 *buf) {
+    return (__u32)buf[3] << 24 | (__u32)buf[2] << 16 | (__u32)buf[1] << 8 | (__u32)buf[0];
+}
+
+// has_prefix is meant to be called with a constant prefix, whose size can't exceed CLASSIFICATION_BUFFER_SIZE
+static __always_inline bool has_prefix(const __u8 *buf, __u32 size, const char *prefix, __u32 prefix_size) {
+    if (size < prefix_size) {
+        return false;
+    }
+
+#pragma unroll
+    for (int i = 0; i < CLASSIFICATION_BUFFER_SIZE; i++) {
+        if (i >= prefix_size) {
+            break;
+        }
+        if (buf[i] != (__u8)prefix[i]) {
+            return false;
+        }
+    }
+    return true;
+}
+
+// is_tls matches the record header of a ClientHello, or of a ServerHello when sent by the server.
+// SSL 3.0 to TLS 1.3 are matched, TLS 1.3 using the TLS 1.0 to 1.2 record versions.
+static __always_inline bool is_tls(const __u8 *buf, __u32 size, bool from_client) {
+    if (size < 6) {
+        return false;
+    }
+    if (buf[0] != TLS_RECORD_HANDSHAKE || buf[1] != 0x03 || buf[2] > 0x04) {
+        return false;
+    }
+    return buf[5] == (from_client ? TLS_HANDSHAKE_CLIENT_HELLO : TLS_HANDSHAKE_SERVER_HELLO);
+}
+
+static __always_inline bool is_http_request(const __u8 *buf, __u32 size) {
+    return has_prefix(buf, size, "GET ", 4) || has_prefix(buf, size, "POST ", 5) ||
+        has_prefix(buf, size, "PUT ", 4) || has_prefix(buf, size, "DELETE ", 7) ||
+        has_prefix(buf, size, "HEAD ", 5) || has_prefix(buf, size, "OPTIONS ", 8) ||
+        has_prefix(buf, size, "PATCH ", 6) || has_prefix(buf, size, "CONNECT ", 8);
+}
+
+// is_amqp_header matches the protocol header sent by AMQP clients: "AMQP" followed by 4 version bytes
+static __always_inline bool is_amqp_header(const __u8 *buf, __u32 size) {
+    return size >= 8 && has_prefix(buf, size, "AMQP", 4);
+}
+
+// is_postgres_startup matches the first message sent by PostgreSQL clients, which unlike the
+// following messages has no type: a length, followed by the protocol version or a request code
+static __always_inline bool is_postgres_startup(const __u8 *buf, __u32 size) {
+    if (size < 8) {
+        return false;
+    }
+    __u32 length = read_big_endian_32(buf);
+    if (length < 8 || length > POSTGRES_MAX_STARTUP_SIZE) {
+        return false;
+    }
+
+    switch (read_big_endian_32(buf + 4)) {
+    case POSTGRES_PROTOCOL_VERSION_3:
+        return true;
+    case POSTGRES_SSL_REQUEST:
+    case POSTGRES_GSSENC_REQUEST:
+        return length == 8;
+    case POSTGRES_CANCEL_REQUEST:
+        return length == 16;
+    }
+    return false;
+}
+
+// is_mongo_request matches the header of a MongoDB request: little endian message length,
+// request ID, response-to (0 for requests) and op code
+static __always_inline bool is_mongo_request(const __u8 *buf, __u32 size) {
+    if (size < 16) {
+        return false;
+    }
+    if ((__s32)read_little_endian_32(buf) < 16 || read_little_endian_32(buf + 8) != 0) {
+        return false;
+    }
+
+    switch (read_little_endian_32(buf + 12)) {
+    case MONGO_OP_QUERY:
+    case MONGO_OP_COMPRESSED:
+    case MONGO_OP_MSG:
+        return true;
+    }
+    return false;
+}
+
+// is_redis_command matches a command sent with the RESP protocol: an array of bulk strings,
+// such as "*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n"
+static __always_inline bool is_redis_command(const __u8 *buf, __u32 size) {
+    if (size < 4 || buf[0] != '*') {
+        return false;
+    }
+
+    // the number of elements is followed by "\r\n$", all of which must fit in the buffer
+    __u32 end = 1;
+#pragma unroll
+    for (int i = 1; i < CLASSIFICATION_BUFFER_SIZE - 3; i++) {
+        if (buf[i] < '0' || buf[i] > '9') {
+            break;
+        }
+        end++;
+    }
+    if (end == 1 || end + 3 > size) {
+        return false;
+    }
+    return buf[end] == '\r' && buf[end + 1] == '\n' && buf[end + 2] == '$';
+}
+
+// is_kafka_request matches the header of a Kafka request: big endian size, API key, API version,
+// correlation ID and client ID, whose first characters must be printable
+static __always_inline bool is_kafka_request(const __u8 *buf, __u32 size) {
+    if (size < KAFKA_HEADER_SIZE) {
+        return false;
+    }
+    __s32 frame_size = (__s32)read_big_endian_32(buf);
+    __s16 api_key = (__s16)read_big_endian_16(buf + 4);
+    __s16 api_version = (__s16)read_big_endian_16(buf + 6);
+    __s32 correlation_id = (__s32)read_big_endian_32(buf + 8);
+    __s16 client_id_length = (__s16)read_big_endian_16(buf + 12);
+
+    if (frame_size < 10 || frame_size > KAFKA_MAX_FRAME_SIZE) {
+        return false;
+    }
+    if (api_key < 0 || api_key > KAFKA_MAX_API_KEY || api_version < 0 || api_version > KAFKA_MAX_API_VERSION) {
+        return false;
+    }
+    if (correlation_id < 0 || client_id_length < -1) {
+        return false;
+    }
+    if (client_id_length > 0 && size == KAFKA_HEADER_SIZE) {
+        return false;
+    }
+
+#pragma unroll
+    for (int i = KAFKA_HEADER_SIZE; i < CLASSIFICATION_BUFFER_SIZE; i++) {
+        if (i - KAFKA_HEADER_SIZE >= client_id_length || i >= size) {
+            break;
+        }
+        if (buf[i] < 0x20 || buf[i] > 0x7e) {
+            return false;
+        }
+    }
+    return true;
+}
+
+// is_mysql_greeting matches the initial handshake packet sent by MySQL servers: a 3 bytes little
+// endian length, the sequence ID 0, the protocol version and the server version, which starts with a digit
+static __always_inline bool is_mysql_greeting(const __u8 *buf, __u32 size) {
+    if (size < 6) {
+        return false;
+    }
+    __u32 length = (__u32)buf[0] | (__u32)buf[1] << 8 | (__u32)buf[2] << 16;
+    if (length != size - 4 || buf[3] != 0 || buf[4] != MYSQL_PROTOCOL_VERSION_10) {
+        return false;
+    }
+    return buf[5] >= '0' && buf[5] <= '9';
+}
+
+// classify returns the protocol of a connection from the first bytes of a payload sent by either the client
+// or the server, or PROTOCOL_UNKNOWN if they match none of the supported protocols. Most protocols are
+// identified from the first message of the client, MySQL from the greeting of the server.
+static __always_inline __u8 classify(const __u8 *buf, __u32 size, bool from_client) {
+    if (is_tls(buf, size, from_client)) {
+        return PROTOCOL_TLS;
+    }
+
+    if (!from_client) {
+        if (has_prefix(buf, size, "HTTP/1.", 7)) {
+            return PROTOCOL_HTTP;
+        }
+        if (is_mysql_greeting(buf, size)) {
+            return PROTOCOL_MYSQL;
+        }
+        return PROTOCOL_UNKNOWN;
+    }
+
+    if (has_prefix(buf, size, "PRI * HTTP/2.0", 14)) {
+        return PROTOCOL_HTTP2;
+    }
+    if (is_http_request(buf, size)) {
+        return PROTOCOL_HTTP;
+    }
+    if (is_amqp_header(buf, size)) {
+        return PROTOCOL_AMQP;
+    }
+    if (is_postgres_startup(buf, size)) {
+        return PROTOCOL_POSTGRES;
+    }
+    if (is_mongo_request(buf, size)) {
+        return PROTOCOL_MONGODB;
+    }
+    if (is_redis_command(buf, size)) {
+        return PROTOCOL_REDIS;
+    }
+    if (is_kafka_request(buf, size)) {
+        return PROTOCOL_KAFKA;
+    }
+    return PROTOCOL_UNKNOWN;
+}
+
+#endif
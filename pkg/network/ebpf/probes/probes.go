@@ -83,6 +83,9 @@ const (
 	// SocketDnsFilter is the socket probe for dns
 	SocketDnsFilter ProbeName = "socket/dns_filter"
 
+	// SocketProtocolFilter is the socket probe classifying the protocol of the TCP connections
+	SocketProtocolFilter ProbeName = "socket/protocol_filter"
+
 	// SockMapFdReturn maps a file descriptor to a kernel sock
 	SockMapFdReturn ProbeName = "kretprobe/sockfd_lookup_light"
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/dustin/go-humanize"
 	"go4.org/intern"
@@ -111,6 +112,10 @@ type Connections struct {
 	ConnTelemetry               *ConnectionsTelemetry
 	CompilationTelemetryByAsset map[string]RuntimeCompilationTelemetry
 	HTTP                        map[http.Key]http.RequestStats
+	GRPC                        map[http.Key]http.GRPCStats
+	Protocols                   map[protocols.Key]protocols.RequestStats
+	// DNSResolvers holds the stats of the lookups answered by each resolver since the last request, keyed by IP
+	DNSResolvers map[string]dns.Stats
 }
 
 // ConnectionsTelemetry stores telemetry from the system probe related to connections collection
@@ -183,6 +188,8 @@ type ConnectionStats struct {
 	Family                      ConnectionFamily
 	Direction                   ConnectionDirection
 	SPortIsEphemeral            EphemeralPortType
+	Protocol                    protocols.Protocol
+	TLS                         *protocols.TLSMetadata
 	IPTranslation               *IPTranslation
 	IntraHost                   bool
 	DNSSuccessfulResponses      uint32
@@ -322,6 +329,20 @@ func ConnectionSummary(c *ConnectionStats, names map[util.Address][]string) stri
 		)
 	}
 
+	if c.Protocol != protocols.Unknown {
+		str += fmt.Sprintf(", protocol %s", c.Protocol)
+	}
+
+	if c.TLS != nil {
+		str += fmt.Sprintf(", %s %s", c.TLS.VersionName(), c.TLS.CipherSuiteName())
+		if c.TLS.ServerName != "" {
+			str += fmt.Sprintf(", sni %s", c.TLS.ServerName)
+		}
+		if !c.TLS.CertNotAfter.IsZero() {
+			str += fmt.Sprintf(", certificate expires %s", c.TLS.CertNotAfter.Format(time.RFC3339))
+		}
+	}
+
 	return str
 }
 
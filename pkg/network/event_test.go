@@ -1,11 +1,14 @@
 package network
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 
 	"github.com/stretchr/testify/assert"
@@ -159,3 +162,17 @@ func BenchmarkByteKey(b *testing.B) {
 	}
 	runtime.KeepAlive(buf)
 }
+
+func TestConnectionSummaryTLS(t *testing.T) {
+	conn := testConn
+	conn.Protocol = protocols.TLS
+	conn.TLS = &protocols.TLSMetadata{
+		Version:      tls.VersionTLS10,
+		CipherSuite:  tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+		ServerName:   "example.com",
+		CertNotAfter: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	summary := ConnectionSummary(&conn, nil)
+	assert.Contains(t, summary, ", protocol tls, TLS 1.0 TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA, sni example.com, certificate expires 2030-01-02T03:04:05Z")
+}
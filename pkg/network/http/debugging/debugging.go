@@ -15,6 +15,8 @@ type RequestSummary struct {
 	Path     string
 	Method   string
 	ByStatus map[int]Stats
+	// GRPCByStatus holds the number of gRPC calls by gRPC status code
+	GRPCByStatus map[int]int
 }
 
 // Address represents represents a IP:Port
@@ -30,27 +32,15 @@ type Stats struct {
 	LatencyP50         float64
 }
 
-// HTTP returns a debug-friendly representation of map[http.Key]http.RequestStats
-func HTTP(stats map[http.Key]http.RequestStats, dns map[util.Address][]string) []RequestSummary {
+// HTTP returns a debug-friendly representation of map[http.Key]http.RequestStats,
+// along with the gRPC calls of map[http.Key]http.GRPCStats
+func HTTP(stats map[http.Key]http.RequestStats, grpc map[http.Key]http.GRPCStats, dns map[util.Address][]string) []RequestSummary {
 	all := make([]RequestSummary, 0, len(stats))
+	byKey := make(map[http.Key]int, len(stats))
 	for k, v := range stats {
-		clientAddr := formatIP(k.SrcIPLow, k.SrcIPHigh)
-		serverAddr := formatIP(k.DstIPLow, k.DstIPHigh)
-
-		debug := RequestSummary{
-			Client: Address{
-				IP:   clientAddr.String(),
-				Port: k.SrcPort,
-			},
-			Server: Address{
-				IP:   serverAddr.String(),
-				Port: k.DstPort,
-			},
-			DNS:      getDNS(dns, serverAddr),
-			Path:     k.Path,
-			Method:   k.Method.String(),
-			ByStatus: make(map[int]Stats),
-		}
+		byKey[k] = len(all)
+		all = append(all, newRequestSummary(k, dns))
+		debug := &all[len(all)-1]
 
 		for i, stat := range v {
 			if stat.Count == 0 {
@@ -64,13 +54,47 @@ func HTTP(stats map[http.Key]http.RequestStats, dns map[util.Address][]string) [
 				LatencyP50:         getSketchQuantile(stat.Latencies, 0.5),
 			}
 		}
+	}
 
-		all = append(all, debug)
+	for k, v := range grpc {
+		i, ok := byKey[k]
+		if !ok {
+			i = len(all)
+			all = append(all, newRequestSummary(k, dns))
+		}
+
+		debug := &all[i]
+		debug.GRPCByStatus = make(map[int]int)
+		for code, count := range v {
+			if count > 0 {
+				debug.GRPCByStatus[code] = count
+			}
+		}
 	}
 
 	return all
 }
 
+func newRequestSummary(k http.Key, dns map[util.Address][]string) RequestSummary {
+	clientAddr := formatIP(k.SrcIPLow, k.SrcIPHigh)
+	serverAddr := formatIP(k.DstIPLow, k.DstIPHigh)
+
+	return RequestSummary{
+		Client: Address{
+			IP:   clientAddr.String(),
+			Port: k.SrcPort,
+		},
+		Server: Address{
+			IP:   serverAddr.String(),
+			Port: k.DstPort,
+		},
+		DNS:      getDNS(dns, serverAddr),
+		Path:     k.Path,
+		Method:   k.Method.String(),
+		ByStatus: make(map[int]Stats),
+	}
+}
+
 func formatIP(low, high uint64) util.Address {
 	// TODO: this is  not correct, but we don't have socket family information
 	// for HTTP at the moment, so given this is purely debugging code I think it's fine
@@ -23,6 +23,7 @@ const (
 	httpBatchesMap       = "http_batches"
 	httpBatchStateMap    = "http_batch_state"
 	httpNotificationsMap = "http_notifications"
+	http2ConnsMap        = "http2_conns"
 
 	// ELF section of the BPF_PROG_TYPE_SOCKET_FILTER program used
 	// to inspect plain HTTP traffic
@@ -38,6 +39,7 @@ var mainHTTPMaps = []string{
 	httpInFlightMap,
 	httpBatchesMap,
 	httpBatchStateMap,
+	http2ConnsMap,
 
 	// SSL
 	string(probes.SockByPidFDMap),
@@ -118,7 +120,16 @@ func newEBPFProgram(c *config.Config, offsets []manager.ConstantEditor, sockFD *
 }
 
 func (e *ebpfProgram) Init() error {
+	var constantEditors []manager.ConstantEditor
+	if e.cfg.EnableHTTP2Monitoring {
+		constantEditors = append(constantEditors, manager.ConstantEditor{
+			Name:  "http2_monitoring_enabled",
+			Value: uint64(1),
+		})
+	}
+
 	options := manager.Options{
+		ConstantEditors: constantEditors,
 		RLimit: &unix.Rlimit{
 			Cur: math.MaxUint64,
 			Max: math.MaxUint64,
@@ -134,6 +145,11 @@ func (e *ebpfProgram) Init() error {
 				MaxEntries: uint32(e.cfg.MaxTrackedConnections),
 				EditorFlag: manager.EditMaxEntries,
 			},
+			http2ConnsMap: {
+				Type:       ebpf.Hash,
+				MaxEntries: uint32(e.cfg.MaxTrackedConnections),
+				EditorFlag: manager.EditMaxEntries,
+			},
 		},
 		ActivatedProbes: []manager.ProbesSelector{
 			&manager.ProbeSelector{
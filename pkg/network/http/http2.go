@@ -0,0 +1,414 @@
+// +build linux_bpf
+
+package http
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+const (
+	http2FrameHeaderSize = 9
+
+	// initial size of the HPACK dynamic tables, as defined by RFC 7540
+	http2InitialHeaderTableSize = 4096
+
+	// maxHTTP2Streams bounds the number of concurrent streams tracked for a connection
+	maxHTTP2Streams = 1000
+
+	// maxHTTP2FrameSize bounds the size of the frames we buffer, frames bigger than
+	// the default SETTINGS_MAX_FRAME_SIZE are only expected to be DATA frames
+	maxHTTP2FrameSize = 1 << 14
+
+	// maxHTTP2HeaderBlockSize bounds the size of a header block split in CONTINUATION frames,
+	// a connection sending bigger blocks is considered broken
+	maxHTTP2HeaderBlockSize = 1 << 16
+
+	// noGRPCStatus is used when a response has no grpc-status, meaning it is not a gRPC call
+	noGRPCStatus = -1
+)
+
+var (
+	http2ClientPreface = []byte(http2.ClientPreface)
+
+	errHTTP2ConnBroken = errors.New("http2 connection state lost")
+)
+
+// http2Transaction is a request/response exchange decoded from the frames of an HTTP/2 stream
+type http2Transaction struct {
+	Path       string
+	Method     Method
+	StatusCode int
+	GRPCStatus int
+
+	RequestStarted   uint64
+	ResponseLastSeen uint64
+}
+
+// StatusClass returns an integer representing the status code class
+// Example: a 404 would return 400
+func (tx *http2Transaction) StatusClass() int {
+	return (tx.StatusCode / 100) * 100
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (tx *http2Transaction) RequestLatency() float64 {
+	return nsTimestampToFloat(tx.ResponseLastSeen - tx.RequestStarted)
+}
+
+type http2Stream struct {
+	tx http2Transaction
+}
+
+// http2Direction holds the decoding state of the frames sent by one of the endpoints of a connection
+type http2Direction struct {
+	// each endpoint encodes its headers with its own HPACK dynamic table
+	decoder *hpack.Decoder
+
+	// bytes of an incomplete frame, waiting for the next payload
+	pending []byte
+	// frame bytes left to skip, for frames bigger than maxHTTP2FrameSize
+	skip int
+
+	// header block fragments, for HEADERS frames followed by CONTINUATION frames
+	headerBlock    []byte
+	headerStreamID uint32
+	headerEnd      bool
+	inHeaderBlock  bool
+
+	// TCP sequence number expected for the next payload, used to skip the packets seen twice
+	// (retransmissions, or loopback traffic captured on both its way out and in)
+	nextSeq  uint32
+	seqKnown bool
+}
+
+func newHTTP2Direction() *http2Direction {
+	return &http2Direction{
+		decoder: hpack.NewDecoder(http2InitialHeaderTableSize, nil),
+	}
+}
+
+// http2Conn decodes the frames exchanged on an HTTP/2 connection, including gRPC calls,
+// and returns a transaction for each stream once its response is complete. As HPACK
+// decoding is stateful, a connection can't be decoded if its first frames were missed,
+// in which case it is considered broken.
+type http2Conn struct {
+	client *http2Direction
+	server *http2Direction
+
+	streams map[uint32]*http2Stream
+
+	prefaceChecked bool
+	broken         bool
+
+	// timestamp of the last payload of the connection, in nanoseconds, used to expire idle connections
+	lastSeen uint64
+}
+
+func newHTTP2Conn() *http2Conn {
+	return &http2Conn{
+		client:  newHTTP2Direction(),
+		server:  newHTTP2Direction(),
+		streams: make(map[uint32]*http2Stream),
+	}
+}
+
+// Feed decodes the frames of a payload sent either by the client or the server at the
+// given timestamp, in nanoseconds, and returns the transactions completed by these frames
+func (c *http2Conn) Feed(payload []byte, fromClient bool, ts uint64) ([]http2Transaction, error) {
+	if c.broken {
+		return nil, errHTTP2ConnBroken
+	}
+
+	dir := c.server
+	if fromClient {
+		dir = c.client
+		if !c.prefaceChecked {
+			if len(payload) < len(http2ClientPreface) {
+				c.broken = true
+				return nil, errHTTP2ConnBroken
+			}
+			if !bytes.Equal(payload[:len(http2ClientPreface)], http2ClientPreface) {
+				c.broken = true
+				return nil, fmt.Errorf("missing http2 client preface: %w", errHTTP2ConnBroken)
+			}
+			payload = payload[len(http2ClientPreface):]
+			c.prefaceChecked = true
+		}
+	}
+
+	frames, err := dir.frames(payload)
+	if err != nil {
+		c.broken = true
+		return nil, fmt.Errorf("%s: %w", err, errHTTP2ConnBroken)
+	}
+
+	var transactions []http2Transaction
+	for _, frame := range frames {
+		tx, err := c.handleFrame(dir, frame, fromClient, ts)
+		if err != nil {
+			c.broken = true
+			return transactions, fmt.Errorf("%s: %w", err, errHTTP2ConnBroken)
+		}
+		if tx != nil {
+			transactions = append(transactions, *tx)
+		}
+	}
+	return transactions, nil
+}
+
+// FeedSegment decodes the payload of a TCP segment, skipping the bytes already decoded. A gap in the
+// sequence numbers means that a segment was missed and breaks the connection.
+func (c *http2Conn) FeedSegment(seq uint32, payload []byte, fromClient bool, ts uint64) ([]http2Transaction, error) {
+	if len(payload) == 0 {
+		return nil, nil
+	}
+
+	dir := c.server
+	if fromClient {
+		dir = c.client
+	}
+
+	end := seq + uint32(len(payload))
+	if dir.seqKnown {
+		switch offset := int32(dir.nextSeq - seq); {
+		case offset < 0:
+			c.broken = true
+			return nil, fmt.Errorf("missed %d bytes: %w", -offset, errHTTP2ConnBroken)
+		case int(offset) >= len(payload):
+			return nil, nil
+		default:
+			payload = payload[offset:]
+		}
+	}
+	dir.nextSeq = end
+	dir.seqKnown = true
+
+	return c.Feed(payload, fromClient, ts)
+}
+
+type http2Frame struct {
+	typ      http2.FrameType
+	flags    http2.Flags
+	streamID uint32
+	payload  []byte
+}
+
+// frames splits a payload into complete frames, keeping incomplete ones for the next payload.
+// The payload of DATA frames bigger than maxHTTP2FrameSize is skipped, as it is never decoded.
+func (d *http2Direction) frames(payload []byte) ([]http2Frame, error) {
+	if d.skip > 0 {
+		if len(payload) <= d.skip {
+			d.skip -= len(payload)
+			return nil, nil
+		}
+		payload = payload[d.skip:]
+		d.skip = 0
+	}
+
+	if len(d.pending) > 0 {
+		payload = append(d.pending, payload...)
+		d.pending = nil
+	}
+
+	var frames []http2Frame
+	for len(payload) >= http2FrameHeaderSize {
+		length := int(payload[0])<<16 | int(payload[1])<<8 | int(payload[2])
+		frame := http2Frame{
+			typ:      http2.FrameType(payload[3]),
+			flags:    http2.Flags(payload[4]),
+			streamID: binary.BigEndian.Uint32(payload[5:9]) & (1<<31 - 1),
+		}
+
+		size := http2FrameHeaderSize + length
+		if len(payload) < size {
+			if length <= maxHTTP2FrameSize {
+				break
+			}
+			if frame.typ != http2.FrameData {
+				return nil, fmt.Errorf("%s frame too large: %d bytes", frame.typ, length)
+			}
+			frames = append(frames, frame)
+			d.skip = size - len(payload)
+			return frames, nil
+		}
+
+		frame.payload = payload[http2FrameHeaderSize:size]
+		frames = append(frames, frame)
+		payload = payload[size:]
+	}
+
+	if len(payload) > 0 {
+		d.pending = append([]byte(nil), payload...)
+	}
+	return frames, nil
+}
+
+func (c *http2Conn) handleFrame(dir *http2Direction, frame http2Frame, fromClient bool, ts uint64) (*http2Transaction, error) {
+	switch frame.typ {
+	case http2.FrameSettings:
+		if frame.flags.Has(http2.FlagSettingsAck) {
+			return nil, nil
+		}
+		// the header table size advertised by an endpoint bounds the table of its peer encoder
+		peer := c.server
+		if !fromClient {
+			peer = c.client
+		}
+		for p := frame.payload; len(p) >= 6; p = p[6:] {
+			if http2.SettingID(binary.BigEndian.Uint16(p)) == http2.SettingHeaderTableSize {
+				peer.decoder.SetAllowedMaxDynamicTableSize(binary.BigEndian.Uint32(p[2:]))
+			}
+		}
+		return nil, nil
+
+	case http2.FrameHeaders:
+		block := frame.payload
+		if frame.flags.Has(http2.FlagHeadersPadded) {
+			if len(block) < 1 || int(block[0]) > len(block)-1 {
+				return nil, errors.New("invalid padding")
+			}
+			block = block[1 : len(block)-int(block[0])]
+		}
+		if frame.flags.Has(http2.FlagHeadersPriority) {
+			if len(block) < 5 {
+				return nil, errors.New("invalid priority")
+			}
+			block = block[5:]
+		}
+
+		dir.headerStreamID = frame.streamID
+		dir.headerEnd = frame.flags.Has(http2.FlagHeadersEndStream)
+		dir.headerBlock = append(dir.headerBlock[:0], block...)
+		dir.inHeaderBlock = true
+		if !frame.flags.Has(http2.FlagHeadersEndHeaders) {
+			return nil, nil
+		}
+		return c.handleHeaderBlock(dir, fromClient, ts)
+
+	case http2.FrameContinuation:
+		if !dir.inHeaderBlock || frame.streamID != dir.headerStreamID {
+			return nil, errors.New("unexpected continuation frame")
+		}
+		if len(dir.headerBlock)+len(frame.payload) > maxHTTP2HeaderBlockSize {
+			dir.headerBlock = nil
+			return nil, fmt.Errorf("header block too large: more than %d bytes", maxHTTP2HeaderBlockSize)
+		}
+		dir.headerBlock = append(dir.headerBlock, frame.payload...)
+		if !frame.flags.Has(http2.FlagContinuationEndHeaders) {
+			return nil, nil
+		}
+		return c.handleHeaderBlock(dir, fromClient, ts)
+
+	case http2.FrameData:
+		if !fromClient && frame.flags.Has(http2.FlagDataEndStream) {
+			return c.completeStream(frame.streamID, ts), nil
+		}
+		return nil, nil
+
+	case http2.FrameRSTStream:
+		delete(c.streams, frame.streamID)
+		return nil, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// handleHeaderBlock decodes a complete header block, which holds the request headers when sent
+// by the client, the response headers or the trailers (such as grpc-status) when sent by the server
+func (c *http2Conn) handleHeaderBlock(dir *http2Direction, fromClient bool, ts uint64) (*http2Transaction, error) {
+	fields, err := dir.decoder.DecodeFull(dir.headerBlock)
+	dir.inHeaderBlock = false
+	if err != nil {
+		return nil, err
+	}
+
+	streamID := dir.headerStreamID
+	stream, ok := c.streams[streamID]
+	if !ok {
+		if !fromClient {
+			// response to a request we haven't seen, the HPACK table is kept up to date though
+			return nil, nil
+		}
+		if len(c.streams) >= maxHTTP2Streams {
+			return nil, nil
+		}
+		stream = &http2Stream{
+			tx: http2Transaction{
+				GRPCStatus:     noGRPCStatus,
+				RequestStarted: ts,
+			},
+		}
+		c.streams[streamID] = stream
+	}
+
+	for _, field := range fields {
+		switch field.Name {
+		case ":method":
+			stream.tx.Method = methodFromString(field.Value)
+		case ":path":
+			stream.tx.Path = pathWithoutQuery(field.Value)
+		case ":status":
+			stream.tx.StatusCode, _ = strconv.Atoi(field.Value)
+		case "grpc-status":
+			if code, err := strconv.Atoi(field.Value); err == nil {
+				stream.tx.GRPCStatus = code
+			}
+		}
+	}
+
+	if !fromClient && dir.headerEnd {
+		return c.completeStream(streamID, ts), nil
+	}
+	return nil, nil
+}
+
+func (c *http2Conn) completeStream(streamID uint32, ts uint64) *http2Transaction {
+	stream, ok := c.streams[streamID]
+	if !ok {
+		return nil
+	}
+	delete(c.streams, streamID)
+
+	if stream.tx.StatusCode == 0 {
+		return nil
+	}
+	stream.tx.ResponseLastSeen = ts
+	return &stream.tx
+}
+
+func methodFromString(method string) Method {
+	switch method {
+	case "GET":
+		return MethodGet
+	case "POST":
+		return MethodPost
+	case "PUT":
+		return MethodPut
+	case "DELETE":
+		return MethodDelete
+	case "HEAD":
+		return MethodHead
+	case "OPTIONS":
+		return MethodOptions
+	case "PATCH":
+		return MethodPatch
+	default:
+		return MethodUnknown
+	}
+}
+
+func pathWithoutQuery(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
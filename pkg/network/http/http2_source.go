@@ -0,0 +1,269 @@
+// +build linux_bpf
+
+package http
+
+import (
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	netebpf "github.com/DataDog/datadog-agent/pkg/network/ebpf"
+	filterpkg "github.com/DataDog/datadog-agent/pkg/network/filter"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/ebpf"
+	"github.com/DataDog/ebpf/manager"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// Must match the ephemeral port range used by the socket filter to normalize tuples
+const (
+	ephemeralRangeBegin = 32768
+	ephemeralRangeEnd   = 60999
+)
+
+// http2ExpiryInterval is the interval at which the idle HTTP/2 connections are expired
+const http2ExpiryInterval = 30 * time.Second
+
+var errNotTCPPayload = errors.New("the packet is not a TCP segment")
+
+// packetSource reads raw packet data, it is implemented by filter.AFPacketSource
+type packetSource interface {
+	VisitPackets(exit <-chan struct{}, visit func([]byte, time.Time) error) error
+	PacketType() gopacket.LayerType
+	Close()
+}
+
+// http2Segment is a TCP segment of a plain HTTP/2 connection
+type http2Segment struct {
+	conn       Key
+	seq        uint32
+	payload    []byte
+	fromClient bool
+	closing    bool
+}
+
+// http2PacketParser extracts the TCP segments from the packets passed to userspace by the socket filter
+type http2PacketParser struct {
+	decoder *gopacket.DecodingLayerParser
+	layers  []gopacket.LayerType
+	ipv4    *layers.IPv4
+	ipv6    *layers.IPv6
+	tcp     *layers.TCP
+}
+
+func newHTTP2PacketParser(layerType gopacket.LayerType) *http2PacketParser {
+	ipv4 := &layers.IPv4{}
+	ipv6 := &layers.IPv6{}
+	tcp := &layers.TCP{}
+	decoder := gopacket.NewDecodingLayerParser(layerType, &layers.Ethernet{}, ipv4, ipv6, tcp)
+	// the TCP payload is not decoded any further
+	decoder.IgnoreUnsupported = true
+
+	return &http2PacketParser{
+		decoder: decoder,
+		ipv4:    ipv4,
+		ipv6:    ipv6,
+		tcp:     tcp,
+	}
+}
+
+// ParseInto fills the segment from the packet data. The payload references the packet data.
+func (p *http2PacketParser) ParseInto(data []byte, segment *http2Segment) error {
+	if err := p.decoder.DecodeLayers(data, &p.layers); err != nil {
+		return err
+	}
+
+	var saddr, daddr util.Address
+	var isTCP bool
+	for _, layer := range p.layers {
+		switch layer {
+		case layers.LayerTypeIPv4:
+			saddr, daddr = util.V4AddressFromBytes(p.ipv4.SrcIP), util.V4AddressFromBytes(p.ipv4.DstIP)
+		case layers.LayerTypeIPv6:
+			saddr, daddr = util.V6AddressFromBytes(p.ipv6.SrcIP), util.V6AddressFromBytes(p.ipv6.DstIP)
+		case layers.LayerTypeTCP:
+			isTCP = true
+		}
+	}
+	if !isTCP || saddr == nil {
+		return errNotTCPPayload
+	}
+
+	sport, dport := uint16(p.tcp.SrcPort), uint16(p.tcp.DstPort)
+
+	// like the socket filter, the tuple is normalized to (client, server)
+	segment.fromClient = isEphemeralPort(sport)
+	if segment.fromClient {
+		segment.conn = NewKey(saddr, daddr, sport, dport, "", MethodUnknown)
+	} else {
+		segment.conn = NewKey(daddr, saddr, dport, sport, "", MethodUnknown)
+	}
+	segment.seq = p.tcp.Seq
+	segment.payload = p.tcp.Payload
+	segment.closing = p.tcp.FIN || p.tcp.RST
+	return nil
+}
+
+func isEphemeralPort(port uint16) bool {
+	return port >= ephemeralRangeBegin && port <= ephemeralRangeEnd
+}
+
+// http2Monitor decodes the packets of the plain HTTP/2 connections passed to userspace by the HTTP socket filter
+type http2Monitor struct {
+	source     packetSource
+	parser     *http2PacketParser
+	statkeeper *http2StatKeeper
+	telemetry  *telemetry
+
+	// conns is the eBPF map of the connections whose packets are passed by the socket filter, the connections
+	// are removed from it by the socket filter once closed, or by the monitor once idle for longer than idleTimeout
+	conns       *ebpf.Map
+	idleTimeout time.Duration
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newHTTP2PacketSource creates the raw socket the socket filter is attached to, in the root network namespace
+func newHTTP2PacketSource(procRoot string, filter *manager.Probe) (packetSource, error) {
+	var (
+		source *filterpkg.AFPacketSource
+		err    error
+	)
+
+	err = util.WithRootNS(procRoot, func() error {
+		source, err = filterpkg.NewPacketSource(filter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func newHTTP2Monitor(c *config.Config, source packetSource, conns *ebpf.Map, telemetry *telemetry) *http2Monitor {
+	return &http2Monitor{
+		source:      source,
+		parser:      newHTTP2PacketParser(source.PacketType()),
+		statkeeper:  newHTTP2Statkeeper(c, telemetry),
+		telemetry:   telemetry,
+		conns:       conns,
+		idleTimeout: c.TCPConnTimeout,
+		exit:        make(chan struct{}),
+	}
+}
+
+// Start reads the packets and expires the idle connections until Stop is called
+func (m *http2Monitor) Start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(http2ExpiryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.exit:
+				return
+			case now := <-ticker.C:
+				m.expire(now)
+			}
+		}
+	}()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			if err := m.source.VisitPackets(m.exit, m.processPacket); err != nil {
+				log.Warnf("error reading http2 packet: %s", err)
+			}
+
+			select {
+			case <-m.exit:
+				return
+			default:
+			}
+
+			// Sleep briefly and try again
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+}
+
+// GetAndResetAllStats returns the request stats and the gRPC stats collected since the last call
+func (m *http2Monitor) GetAndResetAllStats() (map[Key]RequestStats, map[Key]GRPCStats) {
+	return m.statkeeper.GetAndResetAllStats()
+}
+
+// expire releases the state of the connections idle for longer than the idle timeout, in userspace and in
+// the eBPF map, so that the connections whose closing packets were missed don't use up their capacity
+func (m *http2Monitor) expire(now time.Time) {
+	removed := m.statkeeper.RemoveExpired(uint64(now.UnixNano()))
+	if removed > 0 {
+		log.Debugf("expired %d idle http2 connections", removed)
+	}
+
+	if m.conns == nil {
+		return
+	}
+
+	// the socket filter timestamps are read from the monotonic clock
+	ts, err := ddebpf.NowNanoseconds()
+	if err != nil {
+		log.Warnf("could not expire the idle http2 connections: %s", err)
+		return
+	}
+	if ts <= m.idleTimeout.Nanoseconds() {
+		return
+	}
+	expiry := uint64(ts - m.idleTimeout.Nanoseconds())
+
+	var (
+		key      netebpf.ConnTuple
+		lastSeen uint64
+		expired  []netebpf.ConnTuple
+	)
+	entries := m.conns.IterateFrom(unsafe.Pointer(&netebpf.ConnTuple{}))
+	for entries.Next(unsafe.Pointer(&key), unsafe.Pointer(&lastSeen)) {
+		if lastSeen < expiry {
+			expired = append(expired, key)
+		}
+	}
+	if err := entries.Err(); err != nil {
+		log.Warnf("unable to iterate the http2 connections map: %s", err)
+	}
+
+	// the entries are deleted once the iteration is done, as deleting them restarts it
+	for i := range expired {
+		_ = m.conns.Delete(unsafe.Pointer(&expired[i]))
+	}
+}
+
+// Stop stops reading the packets and closes the packet source
+func (m *http2Monitor) Stop() {
+	close(m.exit)
+	m.wg.Wait()
+	m.source.Close()
+}
+
+// processPacket feeds the payload of a packet to the decoder of its connection. The packet data can't be
+// referenced after this call, as its memory gets reused by afpacket, which is fine since the decoder copies
+// the bytes of incomplete frames.
+func (m *http2Monitor) processPacket(data []byte, ts time.Time) error {
+	var segment http2Segment
+	if err := m.parser.ParseInto(data, &segment); err != nil {
+		log.Tracef("error decoding http2 packet: %s", err)
+		return nil
+	}
+
+	m.statkeeper.Process(segment.conn, segment.seq, segment.payload, segment.fromClient, uint64(ts.UnixNano()))
+	if segment.closing {
+		m.statkeeper.CloseConn(segment.conn)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// +build linux_bpf
+
+package http
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// http2StatKeeper decodes the HTTP/2 payloads captured on connections and aggregates
+// the resulting transactions in the same RequestStats as HTTP/1.x, along with the
+// gRPC status codes of the calls. Like HTTP/1.x, the paths are normalized before being aggregated.
+// Payloads are processed by the goroutine reading the packets while the stats are collected by the
+// monitor, hence the lock.
+type http2StatKeeper struct {
+	mux        sync.Mutex
+	conns      map[Key]*http2Conn
+	stats      map[Key]RequestStats
+	grpcStats  map[Key]GRPCStats
+	maxEntries int
+	normalizer *pathNormalizer
+	telemetry  *telemetry
+
+	// idleTimeout is the duration, in nanoseconds, after which the decoding state of a connection
+	// without traffic is released, in case its closing packets were missed
+	idleTimeout uint64
+
+	// map containing interned path strings
+	// this is rotated  with the stats map
+	interned map[string]string
+}
+
+func newHTTP2Statkeeper(c *config.Config, telemetry *telemetry) *http2StatKeeper {
+	return &http2StatKeeper{
+		conns:       make(map[Key]*http2Conn),
+		stats:       make(map[Key]RequestStats),
+		grpcStats:   make(map[Key]GRPCStats),
+		maxEntries:  c.MaxHTTPStatsBuffered,
+		normalizer:  newPathNormalizer(c, telemetry),
+		telemetry:   telemetry,
+		idleTimeout: uint64(c.TCPConnTimeout.Nanoseconds()),
+		interned:    make(map[string]string),
+	}
+}
+
+// Process decodes the payload of a TCP segment sent on a connection at the given timestamp, in nanoseconds.
+// The connection is identified by a Key without path nor method, its source being the client.
+func (h *http2StatKeeper) Process(conn Key, seq uint32, payload []byte, fromClient bool, ts uint64) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	c, ok := h.conns[conn]
+	if !ok {
+		if len(h.conns) >= h.maxEntries {
+			atomic.AddInt64(&h.telemetry.dropped, 1)
+			return
+		}
+		c = newHTTP2Conn()
+		h.conns[conn] = c
+	}
+	c.lastSeen = ts
+
+	// broken connections are kept so that their following payloads are ignored
+	wasBroken := c.broken
+	transactions, err := c.FeedSegment(seq, payload, fromClient, ts)
+	if err != nil && !wasBroken {
+		log.Tracef("could not decode http2 connection: %s", err)
+		atomic.AddInt64(&h.telemetry.http2Errors, 1)
+	}
+
+	for _, tx := range transactions {
+		h.add(conn, tx)
+	}
+	atomic.StoreInt64(&h.telemetry.http2Aggregations, int64(len(h.stats)))
+}
+
+// CloseConn releases the decoding state of a closed connection
+func (h *http2StatKeeper) CloseConn(conn Key) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	delete(h.conns, conn)
+}
+
+// RemoveExpired releases the decoding state of the connections without traffic since the idle timeout,
+// given the current timestamp in nanoseconds, and returns the number of connections removed
+func (h *http2StatKeeper) RemoveExpired(now uint64) int {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	var removed int
+	for key, c := range h.conns {
+		if now > c.lastSeen && now-c.lastSeen > h.idleTimeout {
+			delete(h.conns, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// GetAndResetAllStats returns the request stats and the gRPC stats collected since the last call
+func (h *http2StatKeeper) GetAndResetAllStats() (map[Key]RequestStats, map[Key]GRPCStats) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	stats, grpcStats := h.stats, h.grpcStats // No deep copy needed since they get reset
+	h.stats = make(map[Key]RequestStats)
+	h.grpcStats = make(map[Key]GRPCStats)
+	h.interned = make(map[string]string)
+	if h.normalizer != nil {
+		h.normalizer.reset()
+	}
+	return stats, grpcStats
+}
+
+func (h *http2StatKeeper) add(conn Key, tx http2Transaction) {
+	path := tx.Path
+	if h.normalizer != nil {
+		path = string(h.normalizer.Normalize([]byte(path)))
+	}
+
+	key := conn
+	key.Path = h.intern(path)
+	key.Method = tx.Method
+
+	stats, ok := h.stats[key]
+	if !ok && len(h.stats) >= h.maxEntries {
+		atomic.AddInt64(&h.telemetry.dropped, 1)
+		return
+	}
+
+	if i := tx.StatusClass()/100 - 1; i >= 0 && i < len(h.telemetry.hits) {
+		atomic.AddInt64(&h.telemetry.hits[i], 1)
+	}
+
+	stats.AddRequest(tx.StatusClass(), tx.RequestLatency())
+	h.stats[key] = stats
+
+	if tx.GRPCStatus != noGRPCStatus {
+		grpcStats := h.grpcStats[key]
+		grpcStats.AddCall(tx.GRPCStatus)
+		h.grpcStats[key] = grpcStats
+	}
+}
+
+func (h *http2StatKeeper) intern(s string) string {
+	v, ok := h.interned[s]
+	if !ok {
+		h.interned[s] = s
+		v = s
+	}
+	return v
+}
@@ -0,0 +1,398 @@
+// +build linux_bpf
+
+package http
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+)
+
+// The fixtures in testdata/http2 hold the frames sent by each endpoint of a connection with:
+// - stream 1: a gRPC call to /helloworld.Greeter/SayHello returning OK in its trailers
+// - stream 3: the same call, encoded with the dynamic tables and split in a CONTINUATION frame, returning NOT_FOUND
+// - stream 5: a GET to /api/v1/users?id=42 with padded and prioritized headers, returning a 404
+func loadHTTP2Fixtures(t *testing.T) (client []byte, server []byte) {
+	client, err := ioutil.ReadFile("testdata/http2/grpc_client.bin")
+	require.NoError(t, err)
+	server, err = ioutil.ReadFile("testdata/http2/grpc_server.bin")
+	require.NoError(t, err)
+	return client, server
+}
+
+func TestHTTP2Decoding(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	conn := newHTTP2Conn()
+
+	txs, err := conn.Feed(client, true, 1000)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+	assert.Len(t, conn.streams, 3)
+
+	txs, err = conn.Feed(server, false, 5000)
+	require.NoError(t, err)
+	assert.Empty(t, conn.streams)
+
+	expected := []http2Transaction{
+		{Path: "/helloworld.Greeter/SayHello", Method: MethodPost, StatusCode: 200, GRPCStatus: 0, RequestStarted: 1000, ResponseLastSeen: 5000},
+		{Path: "/helloworld.Greeter/SayHello", Method: MethodPost, StatusCode: 200, GRPCStatus: 5, RequestStarted: 1000, ResponseLastSeen: 5000},
+		{Path: "/api/v1/users", Method: MethodGet, StatusCode: 404, GRPCStatus: noGRPCStatus, RequestStarted: 1000, ResponseLastSeen: 5000},
+	}
+	assert.Equal(t, expected, txs)
+	assert.Equal(t, 400, txs[2].StatusClass())
+	assert.Equal(t, nsTimestampToFloat(4000), txs[2].RequestLatency())
+}
+
+func TestHTTP2DecodingSplitPayloads(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	conn := newHTTP2Conn()
+
+	var txs []http2Transaction
+	feed := func(payload []byte, fromClient bool, chunkSize int) {
+		for len(payload) > 0 {
+			n := chunkSize
+			if n > len(payload) {
+				n = len(payload)
+			}
+			res, err := conn.Feed(payload[:n], fromClient, 0)
+			require.NoError(t, err)
+			txs = append(txs, res...)
+			payload = payload[n:]
+		}
+	}
+
+	// the client preface has to be received in a single payload
+	preface := len(http2.ClientPreface)
+	feed(client[:preface], true, preface)
+	feed(client[preface:], true, 1)
+	feed(server, false, 7)
+
+	require.Len(t, txs, 3)
+	assert.Equal(t, "/helloworld.Greeter/SayHello", txs[0].Path)
+	assert.Equal(t, 5, txs[1].GRPCStatus)
+	assert.Equal(t, "/api/v1/users", txs[2].Path)
+}
+
+func TestHTTP2MissingPreface(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	conn := newHTTP2Conn()
+
+	// the connection was established before we started to monitor it
+	_, err := conn.Feed(client[len(http2.ClientPreface):], true, 0)
+	assert.Error(t, err)
+	assert.True(t, conn.broken)
+
+	_, err = conn.Feed(server, false, 0)
+	assert.Equal(t, errHTTP2ConnBroken, err)
+}
+
+func TestHTTP2LargeDataFrame(t *testing.T) {
+	var buf bytes.Buffer
+	framer := http2.NewFramer(&buf, nil)
+	enc := hpack.NewEncoder(&buf)
+
+	buf.WriteString(http2.ClientPreface)
+	writeHeaders(t, &buf, framer, enc, 1, true, ":method", "GET", ":path", "/large")
+	client := append([]byte(nil), buf.Bytes()...)
+
+	buf.Reset()
+	enc = hpack.NewEncoder(&buf)
+	writeHeaders(t, &buf, framer, enc, 1, false, ":status", "200")
+	require.NoError(t, framer.WriteData(1, true, make([]byte, 3*maxHTTP2FrameSize)))
+	server := buf.Bytes()
+
+	conn := newHTTP2Conn()
+	_, err := conn.Feed(client, true, 0)
+	require.NoError(t, err)
+
+	// the DATA frame is received in several payloads, and its content is skipped
+	var txs []http2Transaction
+	for i := 0; i < len(server); i += 1500 {
+		end := i + 1500
+		if end > len(server) {
+			end = len(server)
+		}
+		res, err := conn.Feed(server[i:end], false, 0)
+		require.NoError(t, err)
+		txs = append(txs, res...)
+	}
+
+	require.Len(t, txs, 1)
+	assert.Equal(t, "/large", txs[0].Path)
+	assert.Equal(t, 200, txs[0].StatusCode)
+	assert.Empty(t, conn.server.pending)
+	assert.Equal(t, 0, conn.server.skip)
+}
+
+func TestHTTP2HeaderBlockTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	framer := http2.NewFramer(&buf, nil)
+
+	buf.WriteString(http2.ClientPreface)
+	require.NoError(t, framer.WriteHeaders(http2.HeadersFrameParam{StreamID: 1, BlockFragment: []byte{0x82}}))
+	fragment := make([]byte, maxHTTP2FrameSize)
+	for i := 0; i <= maxHTTP2HeaderBlockSize/maxHTTP2FrameSize; i++ {
+		require.NoError(t, framer.WriteContinuation(1, false, fragment))
+	}
+
+	// the header block is never completed, its fragments are not buffered past the limit
+	conn := newHTTP2Conn()
+	_, err := conn.Feed(buf.Bytes(), true, 0)
+	assert.Error(t, err)
+	assert.True(t, conn.broken)
+	assert.Nil(t, conn.client.headerBlock)
+}
+
+// writeHeaders writes a HEADERS frame, the header block being encoded in buf before the frame is written
+func writeHeaders(t *testing.T, buf *bytes.Buffer, framer *http2.Framer, enc *hpack.Encoder, streamID uint32, endStream bool, fields ...string) {
+	offset := buf.Len()
+	for i := 0; i < len(fields); i += 2 {
+		require.NoError(t, enc.WriteField(hpack.HeaderField{Name: fields[i], Value: fields[i+1]}))
+	}
+	block := append([]byte(nil), buf.Bytes()[offset:]...)
+	buf.Truncate(offset)
+
+	require.NoError(t, framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      streamID,
+		BlockFragment: block,
+		EndStream:     endStream,
+		EndHeaders:    true,
+	}))
+}
+
+func TestHTTP2StatKeeper(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	telemetry := newTelemetry()
+	sk := newHTTP2Statkeeper(testHTTP2Config(1000), telemetry)
+
+	conn := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 50051, "", MethodUnknown)
+	sk.Process(conn, 0, client, true, 1000)
+	sk.Process(conn, 0, server, false, 5000)
+
+	stats, grpcStats := sk.GetAndResetAllStats()
+	assert.Empty(t, sk.stats)
+	assert.Empty(t, sk.grpcStats)
+	require.Len(t, stats, 2)
+
+	grpcKey := conn
+	grpcKey.Path = "/helloworld.Greeter/SayHello"
+	grpcKey.Method = MethodPost
+	assert.Equal(t, 2, stats[grpcKey][1].Count)
+
+	var expectedGRPC GRPCStats
+	expectedGRPC[0] = 1
+	expectedGRPC[5] = 1
+	assert.Equal(t, map[Key]GRPCStats{grpcKey: expectedGRPC}, grpcStats)
+
+	getKey := conn
+	getKey.Path = "/api/v1/users"
+	getKey.Method = MethodGet
+	assert.Equal(t, 1, stats[getKey][3].Count)
+
+	delta := telemetry.reset()
+	assert.Equal(t, int64(2), delta.hits[1])
+	assert.Equal(t, int64(1), delta.hits[3])
+	assert.Equal(t, int64(0), delta.http2Errors)
+	// the HTTP/2 aggregations don't overwrite the HTTP/1.x ones
+	assert.Equal(t, int64(2), delta.http2Aggregations)
+	assert.Equal(t, int64(0), delta.aggregations)
+
+	sk.CloseConn(conn)
+	assert.Empty(t, sk.conns)
+}
+
+func TestHTTP2StatKeeperPathNormalization(t *testing.T) {
+	var buf bytes.Buffer
+	framer := http2.NewFramer(&buf, nil)
+	enc := hpack.NewEncoder(&buf)
+
+	buf.WriteString(http2.ClientPreface)
+	writeHeaders(t, &buf, framer, enc, 1, true, ":method", "GET", ":path", "/users/12345")
+	writeHeaders(t, &buf, framer, enc, 3, true, ":method", "GET", ":path", "/users/678?page=2")
+	client := append([]byte(nil), buf.Bytes()...)
+
+	buf.Reset()
+	enc = hpack.NewEncoder(&buf)
+	writeHeaders(t, &buf, framer, enc, 1, true, ":status", "200")
+	writeHeaders(t, &buf, framer, enc, 3, true, ":status", "200")
+	server := buf.Bytes()
+
+	c := testHTTP2Config(1000)
+	c.EnableHTTPPathNormalization = true
+	telemetry := newTelemetry()
+	sk := newHTTP2Statkeeper(c, telemetry)
+
+	conn := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 8080, "", MethodUnknown)
+	sk.Process(conn, 0, client, true, 1000)
+	sk.Process(conn, 0, server, false, 5000)
+
+	stats, _ := sk.GetAndResetAllStats()
+	key := conn
+	key.Path = "/users/{num}"
+	key.Method = MethodGet
+	require.Len(t, stats, 1)
+	assert.Equal(t, 2, stats[key][1].Count)
+	assert.Equal(t, int64(2), telemetry.reset().pathsCollapsed)
+}
+
+func TestHTTP2StatKeeperBrokenConn(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	telemetry := newTelemetry()
+	sk := newHTTP2Statkeeper(testHTTP2Config(1000), telemetry)
+
+	conn := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 50051, "", MethodUnknown)
+	sk.Process(conn, 0, client[len(http2.ClientPreface):], true, 0)
+	sk.Process(conn, 0, server, false, 0)
+
+	stats, grpcStats := sk.GetAndResetAllStats()
+	assert.Empty(t, stats)
+	assert.Empty(t, grpcStats)
+
+	// the error is only counted once per connection
+	assert.Equal(t, int64(1), telemetry.reset().http2Errors)
+}
+
+func TestHTTP2StatKeeperMaxEntries(t *testing.T) {
+	client, _ := loadHTTP2Fixtures(t)
+	telemetry := newTelemetry()
+	sk := newHTTP2Statkeeper(testHTTP2Config(1), telemetry)
+
+	conn1 := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 50051, "", MethodUnknown)
+	conn2 := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1235, 50051, "", MethodUnknown)
+	sk.Process(conn1, 0, client, true, 0)
+	sk.Process(conn2, 0, client, true, 0)
+
+	assert.Len(t, sk.conns, 1)
+	assert.Equal(t, int64(1), telemetry.reset().dropped)
+}
+
+func TestHTTP2StatKeeperRemoveExpired(t *testing.T) {
+	client, _ := loadHTTP2Fixtures(t)
+	sk := newHTTP2Statkeeper(testHTTP2Config(1), newTelemetry())
+
+	conn1 := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 50051, "", MethodUnknown)
+	conn2 := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1235, 50051, "", MethodUnknown)
+	start := uint64(time.Now().UnixNano())
+	sk.Process(conn1, 0, client, true, start)
+
+	assert.Equal(t, 0, sk.RemoveExpired(start+uint64(30*time.Second)))
+	assert.Equal(t, 1, sk.RemoveExpired(start+uint64(2*time.Minute)))
+	assert.Empty(t, sk.conns)
+
+	// the capacity used by the idle connection is available again
+	sk.Process(conn2, 0, client, true, start+uint64(2*time.Minute))
+	assert.Contains(t, sk.conns, conn2)
+}
+
+func TestHTTP2FeedSegment(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	conn := newHTTP2Conn()
+
+	feed := func(seq uint32, payload []byte, fromClient bool) []http2Transaction {
+		txs, err := conn.FeedSegment(seq, payload, fromClient, 0)
+		require.NoError(t, err)
+		return txs
+	}
+
+	const clientISN, serverISN = 1000, 5000
+	half := len(client) / 2
+	feed(clientISN, client[:half], true)
+	// a retransmission of the first segment, as seen on loopback
+	feed(clientISN, client[:half], true)
+	// a segment overlapping the bytes already decoded
+	feed(clientISN+uint32(half)-10, client[half-10:], true)
+
+	txs := feed(serverISN, server, false)
+	txs = append(txs, feed(serverISN, server, false)...)
+	require.Len(t, txs, 3)
+	assert.False(t, conn.broken)
+
+	// the next server bytes were missed
+	_, err := conn.FeedSegment(serverISN+uint32(len(server))+1, server, false, 0)
+	assert.Error(t, err)
+	assert.True(t, conn.broken)
+}
+
+func TestHTTP2MonitorProcessPacket(t *testing.T) {
+	client, server := loadHTTP2Fixtures(t)
+	telemetry := newTelemetry()
+	monitor := newHTTP2Monitor(testHTTP2Config(1000), &fakePacketSource{}, nil, telemetry)
+
+	clientIP, serverIP := net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")
+	const clientPort, serverPort = 40000, 8080
+
+	var seqs [2]uint32
+	send := func(payload []byte, fromClient bool, fin bool) {
+		tcp := &layers.TCP{SrcPort: clientPort, DstPort: serverPort, Seq: seqs[0], ACK: true, FIN: fin}
+		ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolTCP, SrcIP: clientIP, DstIP: serverIP}
+		if !fromClient {
+			tcp.SrcPort, tcp.DstPort, tcp.Seq = serverPort, clientPort, seqs[1]
+			ip.SrcIP, ip.DstIP = serverIP, clientIP
+		}
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ip))
+
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+		eth := &layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 1},
+			DstMAC:       net.HardwareAddr{0, 0, 0, 0, 0, 2},
+			EthernetType: layers.EthernetTypeIPv4,
+		}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, tcp, gopacket.Payload(payload)))
+		require.NoError(t, monitor.processPacket(buf.Bytes(), time.Now()))
+
+		if fromClient {
+			seqs[0] += uint32(len(payload))
+		} else {
+			seqs[1] += uint32(len(payload))
+		}
+	}
+
+	send(client, true, false)
+	send(server, false, false)
+	send(nil, true, true)
+
+	conn := NewKey(util.AddressFromNetIP(clientIP), util.AddressFromNetIP(serverIP), clientPort, serverPort, "", MethodUnknown)
+	stats, grpcStats := monitor.GetAndResetAllStats()
+	require.Len(t, stats, 2)
+
+	grpcKey := conn
+	grpcKey.Path = "/helloworld.Greeter/SayHello"
+	grpcKey.Method = MethodPost
+	assert.Equal(t, 2, stats[grpcKey][1].Count)
+	assert.Equal(t, 1, grpcStats[grpcKey][0])
+	assert.Equal(t, 1, grpcStats[grpcKey][5])
+
+	// the decoding state is released once the connection is closed
+	assert.Empty(t, monitor.statkeeper.conns)
+}
+
+func testHTTP2Config(maxEntries int) *config.Config {
+	return &config.Config{
+		MaxHTTPStatsBuffered: maxEntries,
+		TCPConnTimeout:       time.Minute,
+	}
+}
+
+type fakePacketSource struct{}
+
+func (*fakePacketSource) VisitPackets(exit <-chan struct{}, visit func([]byte, time.Time) error) error {
+	return nil
+}
+
+func (*fakePacketSource) PacketType() gopacket.LayerType {
+	return layers.LayerTypeEthernet
+}
+
+func (*fakePacketSource) Close() {}
@@ -2,12 +2,17 @@
 
 package http
 
-import "sync/atomic"
+import (
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+)
 
 type httpStatKeeper struct {
 	stats      map[Key]RequestStats
 	incomplete map[Key]httpTX
 	maxEntries int
+	normalizer *pathNormalizer
 	telemetry  *telemetry
 
 	// http path buffer
@@ -18,11 +23,12 @@ type httpStatKeeper struct {
 	interned map[string]string
 }
 
-func newHTTPStatkeeper(maxEntries int, telemetry *telemetry) *httpStatKeeper {
+func newHTTPStatkeeper(c *config.Config, telemetry *telemetry) *httpStatKeeper {
 	return &httpStatKeeper{
 		stats:      make(map[Key]RequestStats),
 		incomplete: make(map[Key]httpTX),
-		maxEntries: maxEntries,
+		maxEntries: c.MaxHTTPStatsBuffered,
+		normalizer: newPathNormalizer(c, telemetry),
 		buffer:     make([]byte, HTTPBufferSize),
 		interned:   make(map[string]string),
 		telemetry:  telemetry,
@@ -47,6 +53,9 @@ func (h *httpStatKeeper) GetAndResetAllStats() map[Key]RequestStats {
 	h.stats = make(map[Key]RequestStats)
 	h.incomplete = make(map[Key]httpTX)
 	h.interned = make(map[string]string)
+	if h.normalizer != nil {
+		h.normalizer.reset()
+	}
 	return ret
 }
 
@@ -98,6 +107,9 @@ func (h *httpStatKeeper) handleIncomplete(tx httpTX) {
 
 func (h *httpStatKeeper) newKey(tx httpTX) Key {
 	path := tx.Path(h.buffer)
+	if h.normalizer != nil {
+		path = h.normalizer.Normalize(path)
+	}
 	pathString := h.intern(path)
 
 	return Key{
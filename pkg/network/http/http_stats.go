@@ -186,3 +186,24 @@ func (r *RequestStats) initSketch(i int) (err error) {
 	}
 	return
 }
+
+// NumGRPCStatusCodes represents the number of gRPC status codes, from OK (0) to UNAUTHENTICATED (16)
+const NumGRPCStatusCodes = 17
+
+// GRPCStats stores the number of gRPC calls to a particular path, by gRPC status code
+type GRPCStats [NumGRPCStatusCodes]int
+
+// AddCall takes the status code of a gRPC call and adds it to the gRPC stats
+func (g *GRPCStats) AddCall(code int) {
+	if code < 0 || code >= len(g) {
+		return
+	}
+	g[code]++
+}
+
+// CombineWith merges the data in 2 GRPCStats objects
+func (g *GRPCStats) CombineWith(newStats GRPCStats) {
+	for i := range g {
+		g[i] += newStats[i]
+	}
+}
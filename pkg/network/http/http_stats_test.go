@@ -67,3 +67,22 @@ func verifyQuantile(t *testing.T, sketch *ddsketch.DDSketch, q float64, expected
 	assert.True(t, val >= expectedValue-acceptableError)
 	assert.True(t, val <= expectedValue+acceptableError)
 }
+
+func TestGRPCStats(t *testing.T) {
+	var stats GRPCStats
+	stats.AddCall(0)
+	stats.AddCall(0)
+	stats.AddCall(14)
+	// out of range status codes are ignored
+	stats.AddCall(-1)
+	stats.AddCall(NumGRPCStatusCodes)
+
+	var other GRPCStats
+	other.AddCall(14)
+	stats.CombineWith(other)
+
+	var expected GRPCStats
+	expected[0] = 2
+	expected[14] = 2
+	assert.Equal(t, expected, stats)
+}
@@ -27,9 +27,12 @@ type Monitor struct {
 	batchManager *batchManager
 	perfHandler  *ddebpf.PerfHandler
 	telemetry    *telemetry
-	pollRequests chan chan map[Key]RequestStats
+	pollRequests chan chan httpStats
 	statkeeper   *httpStatKeeper
 
+	// decodes the plain HTTP/2 connections, nil unless HTTP/2 monitoring is enabled
+	http2Monitor *http2Monitor
+
 	// termination
 	mux           sync.Mutex
 	eventLoopWG   sync.WaitGroup
@@ -53,9 +56,30 @@ func NewMonitor(c *config.Config, offsets []manager.ConstantEditor, sockFD *ebpf
 		return nil, fmt.Errorf("error retrieving socket filter")
 	}
 
-	closeFilterFn, err := filterpkg.HeadlessSocketFilter(c.ProcRoot, filter)
-	if err != nil {
-		return nil, fmt.Errorf("error enabling HTTP traffic inspection: %s", err)
+	telemetry := newTelemetry()
+
+	// The socket filter only passes the packets of plain HTTP/2 connections to userspace
+	// when HTTP/2 monitoring is enabled, otherwise the raw socket isn't polled
+	var closeFilterFn func()
+	var http2Monitor *http2Monitor
+	if c.EnableHTTP2Monitoring {
+		source, err := newHTTP2PacketSource(c.ProcRoot, filter)
+		if err != nil {
+			return nil, fmt.Errorf("error enabling HTTP/2 traffic inspection: %s", err)
+		}
+		http2Conns, _, err := mgr.GetMap(http2ConnsMap)
+		if err != nil {
+			source.Close()
+			return nil, err
+		}
+		// like the tracer does for its connections, the HTTP/2 connections without traffic are expired
+		http2Monitor = newHTTP2Monitor(c, source, http2Conns, telemetry)
+		closeFilterFn = http2Monitor.Stop
+	} else {
+		closeFilterFn, err = filterpkg.HeadlessSocketFilter(c.ProcRoot, filter)
+		if err != nil {
+			return nil, fmt.Errorf("error enabling HTTP traffic inspection: %s", err)
+		}
 	}
 
 	batchMap, _, err := mgr.GetMap(httpBatchesMap)
@@ -71,8 +95,7 @@ func NewMonitor(c *config.Config, offsets []manager.ConstantEditor, sockFD *ebpf
 	notificationMap, _, _ := mgr.GetMap(httpNotificationsMap)
 	numCPUs := int(notificationMap.ABI().MaxEntries)
 
-	telemetry := newTelemetry()
-	statkeeper := newHTTPStatkeeper(c.MaxHTTPStatsBuffered, telemetry)
+	statkeeper := newHTTPStatkeeper(c, telemetry)
 
 	handler := func(transactions []httpTX) {
 		if statkeeper != nil {
@@ -86,9 +109,10 @@ func NewMonitor(c *config.Config, offsets []manager.ConstantEditor, sockFD *ebpf
 		batchManager:  newBatchManager(batchMap, batchStateMap, numCPUs),
 		perfHandler:   mgr.perfHandler,
 		telemetry:     telemetry,
-		pollRequests:  make(chan chan map[Key]RequestStats),
+		pollRequests:  make(chan chan httpStats),
 		closeFilterFn: closeFilterFn,
 		statkeeper:    statkeeper,
+		http2Monitor:  http2Monitor,
 	}, nil
 }
 
@@ -102,6 +126,10 @@ func (m *Monitor) Start() error {
 		return err
 	}
 
+	if m.http2Monitor != nil {
+		m.http2Monitor.Start()
+	}
+
 	m.eventLoopWG.Add(1)
 	go func() {
 		defer m.eventLoopWG.Done()
@@ -135,7 +163,7 @@ func (m *Monitor) Start() error {
 				delta := m.telemetry.reset()
 				delta.report()
 
-				reply <- m.statkeeper.GetAndResetAllStats()
+				reply <- m.getAndResetAllStats()
 			case <-report.C:
 				transactions := m.batchManager.GetPendingTransactions()
 				m.process(transactions, nil)
@@ -148,21 +176,32 @@ func (m *Monitor) Start() error {
 
 // GetHTTPStats returns a map of HTTP stats stored in the following format:
 // [source, dest tuple, request path] -> RequestStats object
-func (m *Monitor) GetHTTPStats() map[Key]RequestStats {
+// along with the number of gRPC calls by status code, for the HTTP/2 requests that are gRPC calls
+func (m *Monitor) GetHTTPStats() (map[Key]RequestStats, map[Key]GRPCStats) {
 	if m == nil {
-		return nil
+		return nil, nil
 	}
 
 	m.mux.Lock()
 	defer m.mux.Unlock()
 	if m.stopped {
-		return nil
+		return nil, nil
 	}
 
-	reply := make(chan map[Key]RequestStats, 1)
+	reply := make(chan httpStats, 1)
 	defer close(reply)
 	m.pollRequests <- reply
-	return <-reply
+	stats := <-reply
+	return stats.requests, stats.grpc
+}
+
+// GetStats returns the telemetry of the HTTP monitoring, accumulated since the stats were last collected
+func (m *Monitor) GetStats() map[string]int64 {
+	if m == nil {
+		return nil
+	}
+
+	return m.telemetry.stats()
 }
 
 // Stop HTTP monitoring
@@ -185,6 +224,29 @@ func (m *Monitor) Stop() {
 	m.stopped = true
 }
 
+// httpStats holds the stats returned by a poll request
+type httpStats struct {
+	requests map[Key]RequestStats
+	grpc     map[Key]GRPCStats
+}
+
+// getAndResetAllStats merges the stats of the HTTP/2 requests with the ones of HTTP/1.x
+func (m *Monitor) getAndResetAllStats() httpStats {
+	stats := httpStats{requests: m.statkeeper.GetAndResetAllStats()}
+	if m.http2Monitor == nil {
+		return stats
+	}
+
+	var http2Stats map[Key]RequestStats
+	http2Stats, stats.grpc = m.http2Monitor.GetAndResetAllStats()
+	for key, s := range http2Stats {
+		requestStats := stats.requests[key]
+		requestStats.CombineWith(s)
+		stats.requests[key] = requestStats
+	}
+	return stats
+}
+
 func (m *Monitor) process(transactions []httpTX, err error) {
 	m.telemetry.aggregate(transactions, err)
 
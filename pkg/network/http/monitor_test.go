@@ -3,8 +3,11 @@
 package http
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"math/rand"
+	"net"
 	nethttp "net/http"
 	"testing"
 	"time"
@@ -13,7 +16,10 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network/http/testutil"
 	netlink "github.com/DataDog/datadog-agent/pkg/network/netlink/testutil"
 	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func TestHTTPMonitorIntegration(t *testing.T) {
@@ -63,7 +69,7 @@ func testHTTPMonitor(t *testing.T, targetAddr, serverAddr string, numReqs int) {
 
 	// Ensure all captured transactions get sent to user-space
 	time.Sleep(10 * time.Millisecond)
-	stats := monitor.GetHTTPStats()
+	stats, _ := monitor.GetHTTPStats()
 
 	// Assert all requests made were correctly captured by the monitor
 	for _, req := range requests {
@@ -71,6 +77,93 @@ func testHTTPMonitor(t *testing.T, targetAddr, serverAddr string, numReqs int) {
 	}
 }
 
+func TestHTTP2MonitorIntegration(t *testing.T) {
+	currKernelVersion, err := kernel.HostVersion()
+	require.NoError(t, err)
+	if currKernelVersion < kernel.VersionCode(4, 1, 0) {
+		t.Skip("HTTP feature not available on pre 4.1.0 kernels")
+	}
+
+	const addr = "localhost:8081"
+	handler := func(w nethttp.ResponseWriter, req *nethttp.Request) {
+		if req.Header.Get("Content-Type") == "application/grpc" {
+			w.Header().Set("Content-Type", "application/grpc")
+			w.WriteHeader(nethttp.StatusOK)
+			w.Header().Set(nethttp.TrailerPrefix+"grpc-status", "5")
+			return
+		}
+		w.WriteHeader(testutil.StatusFromPath(req.URL.Path))
+	}
+	srv := &nethttp.Server{
+		Addr:    addr,
+		Handler: h2c.NewHandler(nethttp.HandlerFunc(handler), &http2.Server{}),
+	}
+	go func() { _ = srv.ListenAndServe() }()
+	defer srv.Shutdown(context.Background())
+
+	cfg := config.New()
+	cfg.EnableHTTP2Monitoring = true
+	monitor, err := NewMonitor(cfg, nil, nil)
+	require.NoError(t, err)
+	err = monitor.Start()
+	require.NoError(t, err)
+	defer monitor.Stop()
+
+	// plain HTTP/2 with prior knowledge, the connection starting with the client preface
+	client := &nethttp.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	do := func(method, path, contentType string) {
+		req, err := nethttp.NewRequest(method, "http://"+addr+path, nil)
+		require.NoError(t, err)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// the server might not be listening yet
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	do(nethttp.MethodGet, "/404/http2-request", "")
+	do(nethttp.MethodPost, "/helloworld.Greeter/SayHello", "application/grpc")
+
+	// Ensure all captured packets get decoded
+	time.Sleep(100 * time.Millisecond)
+	stats, grpcStats := monitor.GetHTTPStats()
+
+	var found bool
+	for key, s := range stats {
+		if key.Path == "/404/http2-request" && key.Method == MethodGet {
+			found = s[3].Count == 1
+		}
+	}
+	assert.True(t, found, "could not find the HTTP/2 request in %v", stats)
+
+	found = false
+	for key, s := range grpcStats {
+		if key.Path == "/helloworld.Greeter/SayHello" && key.Method == MethodPost {
+			found = s[5] == 1
+		}
+	}
+	assert.True(t, found, "could not find the gRPC call in %v", grpcStats)
+}
+
 func requestGenerator(t *testing.T, targetAddr string) func() *nethttp.Request {
 	var (
 		methods     = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
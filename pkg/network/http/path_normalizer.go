@@ -0,0 +1,189 @@
+// +build linux_bpf
+
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	numericPlaceholder = "{num}"
+	uuidPlaceholder    = "{uuid}"
+	hexPlaceholder     = "{hex}"
+
+	// minHexSegmentLength is the minimum length of the hexadecimal segments replaced by a placeholder,
+	// shorter ones being too likely to be words, such as "cafe" or "add"
+	minHexSegmentLength = 8
+	uuidLength          = 36
+)
+
+// pathNormalizer rewrites HTTP paths before their stats are aggregated, so that requests to the same
+// endpoint with different identifiers, such as /users/12345 and /users/678, share the same stats.
+// The rewrite rules of the configuration are applied first, in order, then the numeric, UUID
+// and hexadecimal segments are replaced with placeholders.
+type pathNormalizer struct {
+	builtin bool
+	rules   []*config.ReplaceRule
+
+	// buffers holding the normalized paths
+	buffer  []byte
+	scratch []byte
+
+	// telemetry, reset when the stats are collected. The number of distinct paths collapsed
+	// is reported in the monitor telemetry.
+	ruleMatches  []int
+	collapsed    map[uint64]struct{}
+	maxCollapsed int
+	telemetry    *telemetry
+}
+
+// newPathNormalizer returns a pathNormalizer, or nil if there is no normalization to apply
+func newPathNormalizer(c *config.Config, telemetry *telemetry) *pathNormalizer {
+	if !c.EnableHTTPPathNormalization && len(c.HTTPReplaceRules) == 0 {
+		return nil
+	}
+
+	return &pathNormalizer{
+		builtin:      c.EnableHTTPPathNormalization,
+		rules:        c.HTTPReplaceRules,
+		buffer:       make([]byte, 0, HTTPBufferSize),
+		scratch:      make([]byte, 0, HTTPBufferSize),
+		ruleMatches:  make([]int, len(c.HTTPReplaceRules)),
+		collapsed:    make(map[uint64]struct{}),
+		maxCollapsed: c.MaxHTTPStatsBuffered,
+		telemetry:    telemetry,
+	}
+}
+
+// Normalize returns the normalized version of a path. The returned slice is only valid until the next call.
+func (n *pathNormalizer) Normalize(path []byte) []byte {
+	normalized := path
+
+	if len(n.rules) > 0 {
+		s := string(path)
+		matched := false
+		for i, rule := range n.rules {
+			if rule.Re.MatchString(s) {
+				s = rule.Re.ReplaceAllString(s, rule.Repl)
+				n.ruleMatches[i]++
+				matched = true
+			}
+		}
+		if matched {
+			normalized = append(n.buffer[:0], s...)
+		}
+	}
+
+	if n.builtin {
+		normalized = n.replaceSegments(normalized)
+	}
+
+	if len(n.collapsed) < n.maxCollapsed && !bytes.Equal(normalized, path) {
+		h := fnv.New64a()
+		_, _ = h.Write(path)
+		// the HTTP/1.x and HTTP/2 statkeepers have their own normalizer, both adding to the telemetry
+		if _, ok := n.collapsed[h.Sum64()]; !ok {
+			n.collapsed[h.Sum64()] = struct{}{}
+			atomic.AddInt64(&n.telemetry.pathsCollapsed, 1)
+		}
+	}
+	return normalized
+}
+
+// replaceSegments replaces the numeric, UUID and hexadecimal segments of a path with placeholders
+func (n *pathNormalizer) replaceSegments(path []byte) []byte {
+	out := n.scratch[:0]
+	replaced := false
+
+	for start := 0; start <= len(path); {
+		end := bytes.IndexByte(path[start:], '/')
+		if end < 0 {
+			end = len(path)
+		} else {
+			end += start
+		}
+
+		segment := path[start:end]
+		if placeholder := segmentPlaceholder(segment); placeholder != "" {
+			out = append(out, placeholder...)
+			replaced = true
+		} else {
+			out = append(out, segment...)
+		}
+		if end < len(path) {
+			out = append(out, '/')
+		}
+		start = end + 1
+	}
+
+	if !replaced {
+		return path
+	}
+	// path may be held by buffer, if it was rewritten by the rules
+	n.buffer, n.scratch = out, n.buffer
+	return out
+}
+
+func segmentPlaceholder(segment []byte) string {
+	if len(segment) == 0 {
+		return ""
+	}
+
+	digits, hex := 0, 0
+	for _, c := range segment {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F'):
+			hex++
+		}
+	}
+
+	switch {
+	case digits == len(segment):
+		return numericPlaceholder
+	case len(segment) == uuidLength && isUUID(segment):
+		return uuidPlaceholder
+	case len(segment) >= minHexSegmentLength && digits > 0 && digits+hex == len(segment):
+		return hexPlaceholder
+	}
+	return ""
+}
+
+// isUUID returns whether a segment has the 8-4-4-4-12 format of UUIDs
+func isUUID(segment []byte) bool {
+	for i, c := range segment {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// reset reports and resets the telemetry of the normalizer
+func (n *pathNormalizer) reset() {
+	matches := make([]string, 0, len(n.rules))
+	for i, rule := range n.rules {
+		matches = append(matches, fmt.Sprintf("%q:%d", rule.Pattern, n.ruleMatches[i]))
+		n.ruleMatches[i] = 0
+	}
+
+	if len(matches) > 0 {
+		log.Debugf("http path normalization summary: rules_matched=[%s]", strings.Join(matches, " "))
+	}
+	n.collapsed = make(map[uint64]struct{})
+}
@@ -0,0 +1,112 @@
+// +build linux_bpf
+
+package http
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplaceRule(pattern, repl string) *config.ReplaceRule {
+	return &config.ReplaceRule{
+		Pattern: pattern,
+		Re:      regexp.MustCompile(pattern),
+		Repl:    repl,
+	}
+}
+
+func TestPathNormalization(t *testing.T) {
+	n := newPathNormalizer(&config.Config{EnableHTTPPathNormalization: true, MaxHTTPStatsBuffered: 1000}, newTelemetry())
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/", "/"},
+		{"/users", "/users"},
+		{"/users/12345/orders/678", "/users/{num}/orders/{num}"},
+		{"/users/12345/", "/users/{num}/"},
+		{"/items/3f2b9c4e-1d7a-4e2b-9c5f-0a1b2c3d4e5f", "/items/{uuid}"},
+		{"/items/3F2B9C4E-1D7A-4E2B-9C5F-0A1B2C3D4E5F/details", "/items/{uuid}/details"},
+		{"/commits/5f2b3c4d9e", "/commits/{hex}"},
+		{"/objects/507f1f77bcf86cd799439011", "/objects/{hex}"},
+		// short or digit-less hexadecimal segments are likely words
+		{"/cafe/facade/beef12", "/cafe/facade/beef12"},
+		{"/v2/api", "/v2/api"},
+		{"/items/3f2b9c4e-1d7a-4e2b-9c5f", "/items/3f2b9c4e-1d7a-4e2b-9c5f"},
+		{"12345", "{num}"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, string(n.Normalize([]byte(test.path))), test.path)
+	}
+}
+
+func TestPathNormalizationRules(t *testing.T) {
+	n := newPathNormalizer(&config.Config{
+		EnableHTTPPathNormalization: true,
+		MaxHTTPStatsBuffered:        1000,
+		HTTPReplaceRules: []*config.ReplaceRule{
+			newReplaceRule(`^/users/[^/]+`, "/users/{user}"),
+			newReplaceRule(`/static/.*`, "/static/*"),
+		},
+	}, newTelemetry())
+
+	assert.Equal(t, "/users/{user}/orders/{num}", string(n.Normalize([]byte("/users/john/orders/42"))))
+	assert.Equal(t, "/users/{user}", string(n.Normalize([]byte("/users/12345"))))
+	assert.Equal(t, "/static/*", string(n.Normalize([]byte("/static/js/app.1234abcd.js"))))
+	assert.Equal(t, "/health", string(n.Normalize([]byte("/health"))))
+	assert.Equal(t, []int{2, 1}, n.ruleMatches)
+
+	// the same paths are only counted once
+	assert.Equal(t, "/users/{user}", string(n.Normalize([]byte("/users/12345"))))
+	assert.Len(t, n.collapsed, 3)
+	assert.Equal(t, int64(3), n.telemetry.stats()["paths_collapsed"])
+	assert.Equal(t, int64(3), n.telemetry.reset().pathsCollapsed)
+
+	n.reset()
+	assert.Equal(t, []int{0, 0}, n.ruleMatches)
+	assert.Empty(t, n.collapsed)
+}
+
+func TestPathNormalizationRulesOnly(t *testing.T) {
+	n := newPathNormalizer(&config.Config{
+		MaxHTTPStatsBuffered: 1000,
+		HTTPReplaceRules:     []*config.ReplaceRule{newReplaceRule(`^/api/v[0-9]+`, "/api")},
+	}, newTelemetry())
+
+	assert.Equal(t, "/api/users/12345", string(n.Normalize([]byte("/api/v2/users/12345"))))
+	assert.Nil(t, newPathNormalizer(&config.Config{MaxHTTPStatsBuffered: 1000}, newTelemetry()))
+}
+
+func TestProcessHTTPTransactionsWithNormalization(t *testing.T) {
+	sk := newHTTPStatkeeper(&config.Config{EnableHTTPPathNormalization: true, MaxHTTPStatsBuffered: 1000}, newTelemetry())
+
+	sourceIP := util.AddressFromString("1.1.1.1")
+	destIP := util.AddressFromString("2.2.2.2")
+	txs := []httpTX{
+		generateIPv4HTTPTransaction(sourceIP, destIP, 1234, 8080, "/users/12345/orders/678", 200, time.Millisecond),
+		generateIPv4HTTPTransaction(sourceIP, destIP, 1234, 8080, "/users/42/orders/1", 200, time.Millisecond),
+		generateIPv4HTTPTransaction(sourceIP, destIP, 1234, 8080, "/users", 200, time.Millisecond),
+	}
+	sk.Process(txs)
+
+	stats := sk.GetAndResetAllStats()
+	require.Len(t, stats, 2)
+	for key, s := range stats {
+		switch key.Path {
+		case "/users/{num}/orders/{num}":
+			assert.Equal(t, 2, s[1].Count)
+		case "/users":
+			assert.Equal(t, 1, s[1].Count)
+		default:
+			t.Errorf("unexpected path %s", key.Path)
+		}
+	}
+}
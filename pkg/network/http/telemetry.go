@@ -17,6 +17,11 @@ type telemetry struct {
 	misses       int64 // this happens when we can't cope with the rate of events
 	dropped      int64 // this happens when httpStatKeeper reaches capacity
 	aggregations int64
+	http2Errors  int64 // this happens when the state of an HTTP/2 connection is lost
+
+	http2Aggregations int64 // number of HTTP/2 aggregations, kept apart from the HTTP/1.x ones
+
+	pathsCollapsed int64 // number of distinct paths rewritten by the path normalizer
 }
 
 func newTelemetry() *telemetry {
@@ -42,10 +47,14 @@ func (t *telemetry) reset() telemetry {
 	then := atomic.SwapInt64(&t.then, now.Unix())
 
 	delta := telemetry{
-		misses:       atomic.SwapInt64(&t.misses, 0),
-		dropped:      atomic.SwapInt64(&t.dropped, 0),
-		aggregations: atomic.SwapInt64(&t.aggregations, 0),
-		elapsed:      now.Unix() - then,
+		misses:         atomic.SwapInt64(&t.misses, 0),
+		dropped:        atomic.SwapInt64(&t.dropped, 0),
+		aggregations:   atomic.SwapInt64(&t.aggregations, 0),
+		http2Errors:    atomic.SwapInt64(&t.http2Errors, 0),
+		pathsCollapsed: atomic.SwapInt64(&t.pathsCollapsed, 0),
+		elapsed:        now.Unix() - then,
+
+		http2Aggregations: atomic.SwapInt64(&t.http2Aggregations, 0),
 	}
 
 	for i := range t.hits {
@@ -55,6 +64,24 @@ func (t *telemetry) reset() telemetry {
 	return delta
 }
 
+// stats returns the counters accumulated since the last reset
+func (t *telemetry) stats() map[string]int64 {
+	var totalRequests int64
+	for i := range t.hits {
+		totalRequests += atomic.LoadInt64(&t.hits[i])
+	}
+
+	return map[string]int64{
+		"requests_processed": totalRequests,
+		"requests_missed":    atomic.LoadInt64(&t.misses),
+		"requests_dropped":   atomic.LoadInt64(&t.dropped),
+		"aggregations":       atomic.LoadInt64(&t.aggregations),
+		"http2_errors":       atomic.LoadInt64(&t.http2Errors),
+		"http2_aggregations": atomic.LoadInt64(&t.http2Aggregations),
+		"paths_collapsed":    atomic.LoadInt64(&t.pathsCollapsed),
+	}
+}
+
 func (t *telemetry) report() {
 	var totalRequests int64
 	for _, n := range t.hits {
@@ -62,7 +89,7 @@ func (t *telemetry) report() {
 	}
 
 	log.Debugf(
-		"http stats summary: requests_processed=%d(%.2f/s) requests_missed=%d(%.2f/s) requests_dropped=%d(%.2f/s) aggregations=%d",
+		"http stats summary: requests_processed=%d(%.2f/s) requests_missed=%d(%.2f/s) requests_dropped=%d(%.2f/s) aggregations=%d http2_aggregations=%d http2_errors=%d paths_collapsed=%d",
 		totalRequests,
 		float64(totalRequests)/float64(t.elapsed),
 		t.misses,
@@ -70,5 +97,8 @@ func (t *telemetry) report() {
 		t.dropped,
 		float64(t.dropped)/float64(t.elapsed),
 		t.aggregations,
+		t.http2Aggregations,
+		t.http2Errors,
+		t.pathsCollapsed,
 	)
 }
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 )
 
@@ -29,3 +30,16 @@ func GetNATRemoteAddress(c ConnectionStats) (util.Address, uint16) {
 	}
 	return remoteIP, remotePort
 }
+
+// ProtocolKey returns the key identifying a connection in the protocol stats. As the
+// protocol stats are indexed as (client, server), the key is flipped if necessary
+// using the port range heuristic.
+func ProtocolKey(c ConnectionStats) protocols.Key {
+	laddr, lport := GetNATLocalAddress(c)
+	raddr, rport := GetNATRemoteAddress(c)
+
+	if IsEphemeralPort(int(lport)) {
+		return protocols.NewKey(laddr, raddr, lport, rport, protocols.Unknown, "")
+	}
+	return protocols.NewKey(raddr, laddr, rport, lport, protocols.Unknown, "")
+}
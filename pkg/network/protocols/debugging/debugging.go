@@ -0,0 +1,146 @@
+package debugging
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/sketches-go/ddsketch"
+)
+
+// ConnectionSummary represents a (debug-friendly) view of a classified connection,
+// along with the operations sent on it
+type ConnectionSummary struct {
+	Client     Address
+	Server     Address
+	DNS        string
+	Protocol   string
+	Operations map[string]Stats
+	TLS        *TLS
+}
+
+// Address represents represents a IP:Port
+type Address struct {
+	IP   string
+	Port uint16
+}
+
+// Stats consolidates the count, error count and latency information of an operation
+type Stats struct {
+	Count              int
+	ErrorCount         int
+	FirstLatencySample float64
+	LatencyP50         float64
+}
+
+// TLS represents the parameters negotiated in the handshake of a TLS connection
+type TLS struct {
+	Version      string
+	CipherSuite  string
+	ServerName   string
+	ClientALPN   []string
+	ALPN         string
+	CertNotAfter time.Time
+	// Weak is set when the version is older than TLS 1.2 or the cipher suite is insecure
+	Weak bool
+}
+
+// Protocols returns a debug-friendly representation of the classified connections and of the
+// map[protocols.Key]protocols.RequestStats of the operations sent on them
+func Protocols(conns []network.ConnectionStats, stats map[protocols.Key]protocols.RequestStats, dns map[util.Address][]string) []ConnectionSummary {
+	byConn := make(map[protocols.Key]int)
+	all := make([]ConnectionSummary, 0, len(stats))
+	summary := func(key protocols.Key, protocol protocols.Protocol) *ConnectionSummary {
+		if i, ok := byConn[key]; ok {
+			return &all[i]
+		}
+
+		clientAddr := formatIP(key.SrcIPLow, key.SrcIPHigh)
+		serverAddr := formatIP(key.DstIPLow, key.DstIPHigh)
+		byConn[key] = len(all)
+		all = append(all, ConnectionSummary{
+			Client: Address{
+				IP:   clientAddr.String(),
+				Port: key.SrcPort,
+			},
+			Server: Address{
+				IP:   serverAddr.String(),
+				Port: key.DstPort,
+			},
+			DNS:        getDNS(dns, serverAddr),
+			Protocol:   protocol.String(),
+			Operations: make(map[string]Stats),
+		})
+		return &all[len(all)-1]
+	}
+
+	for _, c := range conns {
+		if c.Protocol == protocols.Unknown {
+			continue
+		}
+
+		debug := summary(network.ProtocolKey(c), c.Protocol)
+		if c.TLS != nil {
+			debug.TLS = formatTLS(c.TLS)
+		}
+	}
+
+	for k, v := range stats {
+		protocol, operation := k.Protocol, k.Operation
+		k.Protocol, k.Operation = protocols.Unknown, ""
+
+		summary(k, protocol).Operations[operation] = Stats{
+			Count:              v.Count,
+			ErrorCount:         v.ErrorCount,
+			FirstLatencySample: v.FirstLatencySample,
+			LatencyP50:         getSketchQuantile(v.Latencies, 0.5),
+		}
+	}
+
+	return all
+}
+
+func formatTLS(metadata *protocols.TLSMetadata) *TLS {
+	debug := &TLS{
+		ServerName:   metadata.ServerName,
+		ClientALPN:   metadata.ClientALPN,
+		ALPN:         metadata.ALPN,
+		CertNotAfter: metadata.CertNotAfter,
+		Weak:         metadata.IsWeak(),
+	}
+	if metadata.Version != 0 {
+		debug.Version = metadata.VersionName()
+	}
+	if metadata.CipherSuite != 0 {
+		debug.CipherSuite = metadata.CipherSuiteName()
+	}
+	return debug
+}
+
+func formatIP(low, high uint64) util.Address {
+	// The keys have no socket family information, so as for the HTTP debugging
+	// code we assume that it's only IPv6 if higher order bits are set.
+	if high > 0 || (low>>32) > 0 {
+		return util.V6Address(low, high)
+	}
+
+	return util.V4Address(uint32(low))
+}
+
+func getDNS(dns map[util.Address][]string, addr util.Address) string {
+	if names := dns[addr]; len(names) > 0 {
+		return names[0]
+	}
+
+	return ""
+}
+
+func getSketchQuantile(sketch *ddsketch.DDSketch, percentile float64) float64 {
+	if sketch == nil {
+		return 0.0
+	}
+
+	val, _ := sketch.GetValueAtQuantile(percentile)
+	return val
+}
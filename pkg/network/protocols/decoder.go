@@ -0,0 +1,73 @@
+package protocols
+
+import (
+	"bytes"
+	"strings"
+)
+
+const (
+	// maxPendingTransactions bounds the number of requests of a connection waiting for their response
+	maxPendingTransactions = 1000
+
+	// maxCommandLength bounds the length of the commands extracted from queries
+	maxCommandLength = 32
+)
+
+// decoder decodes the payloads of a connection once its protocol is classified, and returns
+// the transactions completed by each payload. An error means the state of the connection
+// was lost, and that it can't be decoded anymore.
+type decoder interface {
+	Feed(payload []byte, fromClient bool, ts uint64) ([]transaction, error)
+}
+
+// newDecoder returns the decoder of a protocol, or nil if we only classify it
+func newDecoder(protocol Protocol) decoder {
+	switch protocol {
+	case Postgres:
+		return newPostgresDecoder()
+	case Redis:
+		return newRedisDecoder()
+	case Kafka:
+		return newKafkaDecoder()
+	default:
+		return nil
+	}
+}
+
+// command returns the first keyword of a query in upper case, skipping leading comments,
+// or fallback if there is none
+func command(query []byte, fallback string) string {
+	for {
+		query = bytes.TrimLeft(query, " \t\r\n(")
+		if bytes.HasPrefix(query, []byte("--")) {
+			i := bytes.IndexByte(query, '\n')
+			if i < 0 {
+				return fallback
+			}
+			query = query[i+1:]
+			continue
+		}
+		if bytes.HasPrefix(query, []byte("/*")) {
+			i := bytes.Index(query, []byte("*/"))
+			if i < 0 {
+				return fallback
+			}
+			query = query[i+2:]
+			continue
+		}
+		break
+	}
+
+	end := 0
+	for end < len(query) && end < maxCommandLength && isLetter(query[end]) {
+		end++
+	}
+	if end == 0 {
+		return fallback
+	}
+	return strings.ToUpper(string(query[:end]))
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,16 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommand(t *testing.T) {
+	assert.Equal(t, "SELECT", command([]byte("select * from users"), "UNKNOWN"))
+	assert.Equal(t, "INSERT", command([]byte("  \n\tINSERT INTO users VALUES (1)"), "UNKNOWN"))
+	assert.Equal(t, "SELECT", command([]byte("(SELECT 1) UNION (SELECT 2)"), "UNKNOWN"))
+	assert.Equal(t, "UPDATE", command([]byte("-- comment\n/* another */ UPDATE users SET id = 2"), "UNKNOWN"))
+	assert.Equal(t, "UNKNOWN", command([]byte("/* unterminated"), "UNKNOWN"))
+	assert.Equal(t, "UNKNOWN", command([]byte("42"), "UNKNOWN"))
+}
@@ -0,0 +1,71 @@
+// +build linux_bpf
+
+package protocols
+
+import (
+	"math"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/bytecode"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	netebpf "github.com/DataDog/datadog-agent/pkg/network/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/ebpf/probes"
+	"github.com/DataDog/ebpf"
+	"github.com/DataDog/ebpf/manager"
+	"golang.org/x/sys/unix"
+)
+
+// connectionProtocolsMap is the map of the protocol of the connections classified by the socket filter
+const connectionProtocolsMap = "connection_protocols"
+
+type ebpfProgram struct {
+	*manager.Manager
+	cfg      *config.Config
+	bytecode bytecode.AssetReader
+}
+
+func newEBPFProgram(c *config.Config) (*ebpfProgram, error) {
+	bc, err := netebpf.ReadProtocolsModule(c.BPFDir, c.BPFDebug)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr := &manager.Manager{
+		Maps: []*manager.Map{
+			{Name: connectionProtocolsMap},
+		},
+		Probes: []*manager.Probe{
+			{Section: string(probes.SocketProtocolFilter)},
+		},
+	}
+
+	return &ebpfProgram{
+		Manager:  mgr,
+		bytecode: bc,
+		cfg:      c,
+	}, nil
+}
+
+func (e *ebpfProgram) Init() error {
+	defer e.bytecode.Close()
+
+	return e.InitWithOptions(e.bytecode, manager.Options{
+		RLimit: &unix.Rlimit{
+			Cur: math.MaxUint64,
+			Max: math.MaxUint64,
+		},
+		MapSpecEditors: map[string]manager.MapSpecEditor{
+			connectionProtocolsMap: {
+				Type:       ebpf.Hash,
+				MaxEntries: uint32(e.cfg.MaxTrackedConnections),
+				EditorFlag: manager.EditMaxEntries,
+			},
+		},
+		ActivatedProbes: []manager.ProbesSelector{
+			&manager.ProbeSelector{
+				ProbeIdentificationPair: manager.ProbeIdentificationPair{
+					Section: string(probes.SocketProtocolFilter),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,263 @@
+package protocols
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+const (
+	kafkaProduce     = 0
+	kafkaFetch       = 1
+	kafkaAPIVersions = 18
+
+	// the first versions using the flexible encoding, whose layout we don't decode
+	kafkaProduceFlexibleVersion = 9
+	kafkaFetchFlexibleVersion   = 12
+	// the first version of the Fetch responses with a top level error code
+	kafkaFetchErrorCodeVersion = 7
+
+	// kafkaMaxFrameSize bounds the size of the messages we decode
+	kafkaMaxFrameSize = 1 << 26
+)
+
+var kafkaAPINames = map[int16]string{
+	0:  "Produce",
+	1:  "Fetch",
+	2:  "ListOffsets",
+	3:  "Metadata",
+	8:  "OffsetCommit",
+	9:  "OffsetFetch",
+	10: "FindCoordinator",
+	11: "JoinGroup",
+	12: "Heartbeat",
+	13: "LeaveGroup",
+	14: "SyncGroup",
+	15: "DescribeGroups",
+	16: "ListGroups",
+	17: "SaslHandshake",
+	18: "ApiVersions",
+	19: "CreateTopics",
+	20: "DeleteTopics",
+	22: "InitProducerId",
+	36: "SaslAuthenticate",
+}
+
+type kafkaRequest struct {
+	tx         transaction
+	apiKey     int16
+	apiVersion int16
+}
+
+// kafkaDecoder decodes the Kafka protocol, matching the responses with their request by correlation
+// ID. Errors are reported for the ApiVersions responses, the Fetch responses with a top level error
+// code, and the Produce responses with a partition error, for the non flexible versions of these APIs.
+type kafkaDecoder struct {
+	client messageReader
+	server messageReader
+
+	pending map[int32]kafkaRequest
+}
+
+func newKafkaDecoder() *kafkaDecoder {
+	return &kafkaDecoder{
+		client:  messageReader{split: splitKafkaMessage},
+		server:  messageReader{split: splitKafkaMessage},
+		pending: make(map[int32]kafkaRequest),
+	}
+}
+
+// splitKafkaMessage splits messages prefixed by their big endian size, which excludes itself
+func splitKafkaMessage(buf []byte) (int, error) {
+	if len(buf) < 4 {
+		return 0, nil
+	}
+	size := int32(binary.BigEndian.Uint32(buf))
+	if size < 4 || size > kafkaMaxFrameSize {
+		return 0, errInvalidMessage
+	}
+	return 4 + int(size), nil
+}
+
+func (d *kafkaDecoder) Feed(payload []byte, fromClient bool, ts uint64) ([]transaction, error) {
+	if fromClient {
+		messages, err := d.client.read(payload)
+		for _, msg := range messages {
+			d.handleRequest(msg, ts)
+		}
+		return nil, err
+	}
+
+	messages, err := d.server.read(payload)
+	var transactions []transaction
+	for _, msg := range messages {
+		if len(msg) < 8 {
+			continue
+		}
+		correlationID := int32(binary.BigEndian.Uint32(msg[4:]))
+		req, ok := d.pending[correlationID]
+		if !ok {
+			continue
+		}
+		delete(d.pending, correlationID)
+
+		req.tx.isError = kafkaResponseHasError(req.apiKey, req.apiVersion, msg[8:])
+		req.tx.responseLastSeen = ts
+		transactions = append(transactions, req.tx)
+	}
+	return transactions, err
+}
+
+func (d *kafkaDecoder) handleRequest(msg []byte, ts uint64) {
+	if len(msg) < 14 {
+		return
+	}
+	apiKey := int16(binary.BigEndian.Uint16(msg[4:]))
+	apiVersion := int16(binary.BigEndian.Uint16(msg[6:]))
+	correlationID := int32(binary.BigEndian.Uint32(msg[8:]))
+
+	body, ok := skipKafkaString(msg[12:])
+	if !ok {
+		return
+	}
+	if apiKey == kafkaProduce && apiVersion < kafkaProduceFlexibleVersion && !kafkaProduceExpectsResponse(apiVersion, body) {
+		return
+	}
+
+	if len(d.pending) >= maxPendingTransactions {
+		// the responses of these requests were most likely missed
+		d.pending = make(map[int32]kafkaRequest)
+	}
+	d.pending[correlationID] = kafkaRequest{
+		tx: transaction{
+			operation:      kafkaAPIName(apiKey),
+			requestStarted: ts,
+		},
+		apiKey:     apiKey,
+		apiVersion: apiVersion,
+	}
+}
+
+// kafkaProduceExpectsResponse returns false for the Produce requests sent with acks=0,
+// which are never answered by the broker
+func kafkaProduceExpectsResponse(apiVersion int16, body []byte) bool {
+	if apiVersion >= 3 {
+		var ok bool
+		// transactional ID
+		if body, ok = skipKafkaString(body); !ok {
+			return true
+		}
+	}
+	if len(body) < 2 {
+		return true
+	}
+	return int16(binary.BigEndian.Uint16(body)) != 0
+}
+
+func kafkaResponseHasError(apiKey, apiVersion int16, body []byte) bool {
+	switch {
+	case apiKey == kafkaAPIVersions:
+		return len(body) >= 2 && binary.BigEndian.Uint16(body) != 0
+	case apiKey == kafkaFetch && apiVersion >= kafkaFetchErrorCodeVersion && apiVersion < kafkaFetchFlexibleVersion:
+		// throttle time, followed by the error code
+		return len(body) >= 6 && binary.BigEndian.Uint16(body[4:]) != 0
+	case apiKey == kafkaProduce && apiVersion < kafkaProduceFlexibleVersion:
+		return kafkaProduceHasError(apiVersion, body)
+	}
+	return false
+}
+
+// kafkaProduceHasError returns whether the response to a Produce request holds a partition error,
+// decoding as much of the response as available
+func kafkaProduceHasError(apiVersion int16, body []byte) bool {
+	topics, body, ok := readKafkaArrayLength(body)
+	for i := 0; ok && i < topics; i++ {
+		if body, ok = skipKafkaString(body); !ok {
+			return false
+		}
+
+		var partitions int
+		if partitions, body, ok = readKafkaArrayLength(body); !ok {
+			return false
+		}
+		for j := 0; j < partitions; j++ {
+			// partition index, error code and base offset
+			if len(body) < 14 {
+				return false
+			}
+			if binary.BigEndian.Uint16(body[4:]) != 0 {
+				return true
+			}
+			body = body[14:]
+
+			skip := 0
+			if apiVersion >= 2 {
+				skip += 8 // log append time
+			}
+			if apiVersion >= 5 {
+				skip += 8 // log start offset
+			}
+			if len(body) < skip {
+				return false
+			}
+			body = body[skip:]
+
+			if apiVersion >= 8 {
+				if body, ok = skipKafkaRecordErrors(body); !ok {
+					return false
+				}
+				// error message
+				if body, ok = skipKafkaString(body); !ok {
+					return false
+				}
+			}
+		}
+	}
+	return false
+}
+
+func skipKafkaRecordErrors(body []byte) ([]byte, bool) {
+	n, body, ok := readKafkaArrayLength(body)
+	for i := 0; ok && i < n; i++ {
+		// batch index, followed by the error message
+		if len(body) < 4 {
+			return nil, false
+		}
+		body, ok = skipKafkaString(body[4:])
+	}
+	return body, ok
+}
+
+func readKafkaArrayLength(body []byte) (int, []byte, bool) {
+	if len(body) < 4 {
+		return 0, nil, false
+	}
+	n := int32(binary.BigEndian.Uint32(body))
+	if n < 0 {
+		// null array
+		n = 0
+	}
+	return int(n), body[4:], true
+}
+
+// skipKafkaString skips a nullable string, prefixed by its 16 bits length
+func skipKafkaString(body []byte) ([]byte, bool) {
+	if len(body) < 2 {
+		return nil, false
+	}
+	n := int(int16(binary.BigEndian.Uint16(body)))
+	body = body[2:]
+	if n <= 0 {
+		return body, true
+	}
+	if len(body) < n {
+		return nil, false
+	}
+	return body[n:], true
+}
+
+func kafkaAPIName(apiKey int16) string {
+	if name, ok := kafkaAPINames[apiKey]; ok {
+		return name
+	}
+	return "ApiKey" + strconv.Itoa(int(apiKey))
+}
@@ -0,0 +1,136 @@
+package protocols
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type kafkaWriter []byte
+
+func (w kafkaWriter) int16(v int16) kafkaWriter {
+	return append(w, byte(v>>8), byte(v))
+}
+
+func (w kafkaWriter) int32(v int32) kafkaWriter {
+	return append(w, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (w kafkaWriter) int64(v int64) kafkaWriter {
+	return w.int32(int32(v >> 32)).int32(int32(v))
+}
+
+func (w kafkaWriter) string(s string) kafkaWriter {
+	return append(w.int16(int16(len(s))), s...)
+}
+
+func (w kafkaWriter) message() []byte {
+	msg := make([]byte, 4, 4+len(w))
+	binary.BigEndian.PutUint32(msg, uint32(len(w)))
+	return append(msg, w...)
+}
+
+func kafkaRequestMessage(apiKey, apiVersion int16, correlationID int32, clientID string, body []byte) []byte {
+	w := kafkaWriter{}.int16(apiKey).int16(apiVersion).int32(correlationID)
+	if clientID == "" {
+		w = w.int16(-1)
+	} else {
+		w = w.string(clientID)
+	}
+	return append(w, body...).message()
+}
+
+func kafkaResponseMessage(correlationID int32, body []byte) []byte {
+	return append(kafkaWriter{}.int32(correlationID), body...).message()
+}
+
+// kafkaProduceRequestBody returns the body of a Produce request v3 to v8
+func kafkaProduceRequestBody(acks int16) []byte {
+	return kafkaWriter{}.int16(-1).int16(acks).int32(30000).int32(0)
+}
+
+// kafkaProduceResponseBody returns the body of a Produce response v8, for a topic with 2 partitions
+func kafkaProduceResponseBody(errorCode int16) []byte {
+	w := kafkaWriter{}.int32(1).string("orders").int32(2)
+	for partition, code := range []int16{0, errorCode} {
+		w = w.int32(int32(partition)).int16(code).int64(1234).int64(-1).int64(0)
+		// record errors, with a single one for the failing partition
+		if code != 0 {
+			w = w.int32(1).int32(0).string("invalid record")
+		} else {
+			w = w.int32(0)
+		}
+		w = w.int16(-1)
+	}
+	return w.int32(0)
+}
+
+func TestKafkaDecoder(t *testing.T) {
+	d := newKafkaDecoder()
+
+	// pipelined requests, answered out of order
+	_, err := d.Feed(concat(
+		kafkaRequestMessage(kafkaAPIVersions, 3, 1, "producer-1", nil),
+		kafkaRequestMessage(kafkaProduce, 8, 2, "producer-1", kafkaProduceRequestBody(1)),
+		kafkaRequestMessage(kafkaProduce, 8, 3, "producer-1", kafkaProduceRequestBody(-1)),
+		kafkaRequestMessage(kafkaFetch, 11, 4, "consumer-1", nil),
+		kafkaRequestMessage(3, 9, 5, "consumer-1", nil),
+	), true, 100)
+	require.NoError(t, err)
+	assert.Len(t, d.pending, 5)
+
+	txs, err := d.Feed(concat(
+		kafkaResponseMessage(3, kafkaProduceResponseBody(2)),
+		kafkaResponseMessage(1, kafkaWriter{}.int16(0).int32(0)),
+		kafkaResponseMessage(2, kafkaProduceResponseBody(0)),
+		kafkaResponseMessage(4, kafkaWriter{}.int32(0).int16(58).int32(0)),
+		kafkaResponseMessage(5, kafkaWriter{}.int32(0)),
+		// unknown correlation ID
+		kafkaResponseMessage(42, nil),
+	), false, 300)
+	require.NoError(t, err)
+	assert.Empty(t, d.pending)
+
+	assert.Equal(t, []transaction{
+		{operation: "Produce", isError: true, requestStarted: 100, responseLastSeen: 300},
+		{operation: "ApiVersions", requestStarted: 100, responseLastSeen: 300},
+		{operation: "Produce", requestStarted: 100, responseLastSeen: 300},
+		{operation: "Fetch", isError: true, requestStarted: 100, responseLastSeen: 300},
+		{operation: "Metadata", requestStarted: 100, responseLastSeen: 300},
+	}, txs)
+}
+
+func TestKafkaProduceWithoutAcks(t *testing.T) {
+	d := newKafkaDecoder()
+	_, err := d.Feed(kafkaRequestMessage(kafkaProduce, 7, 1, "producer-1", kafkaProduceRequestBody(0)), true, 0)
+	require.NoError(t, err)
+	assert.Empty(t, d.pending)
+}
+
+func TestKafkaSplitMessages(t *testing.T) {
+	d := newKafkaDecoder()
+	request := kafkaRequestMessage(kafkaAPIVersions, 3, 7, "client", nil)
+	response := kafkaResponseMessage(7, kafkaWriter{}.int16(35).int32(0))
+
+	var txs []transaction
+	for i := range request {
+		_, err := d.Feed(request[i:i+1], true, 10)
+		require.NoError(t, err)
+	}
+	for i := range response {
+		res, err := d.Feed(response[i:i+1], false, 20)
+		require.NoError(t, err)
+		txs = append(txs, res...)
+	}
+	assert.Equal(t, []transaction{{operation: "ApiVersions", isError: true, requestStarted: 10, responseLastSeen: 20}}, txs)
+
+	_, err := d.Feed([]byte{0xff, 0xff, 0xff, 0xff}, false, 0)
+	assert.Error(t, err)
+}
+
+func TestKafkaAPIName(t *testing.T) {
+	assert.Equal(t, "Produce", kafkaAPIName(0))
+	assert.Equal(t, "ApiKey60", kafkaAPIName(60))
+}
@@ -0,0 +1,34 @@
+// +build linux_bpf
+
+package protocols
+
+import (
+	netebpf "github.com/DataDog/datadog-agent/pkg/network/ebpf"
+)
+
+/*
+#include "../ebpf/c/protocols-types.h"
+*/
+import "C"
+
+const connClosed = uint8(C.PROTOCOL_CONN_CLOSED)
+
+// protocolState is the classification state of a connection, as stored by the socket filter
+type protocolState C.protocol_state_t
+
+// connTuple returns the tuple of a connection in the map of the socket filter, from a Key whose source is the client
+func connTuple(conn Key, isIPv6 bool) netebpf.ConnTuple {
+	t := netebpf.ConnTuple{
+		Saddr_h:  conn.SrcIPHigh,
+		Saddr_l:  conn.SrcIPLow,
+		Daddr_h:  conn.DstIPHigh,
+		Daddr_l:  conn.DstIPLow,
+		Sport:    conn.SrcPort,
+		Dport:    conn.DstPort,
+		Metadata: uint32(netebpf.TCP),
+	}
+	if isIPv6 {
+		t.Metadata |= uint32(netebpf.IPv6)
+	}
+	return t
+}
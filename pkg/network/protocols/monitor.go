@@ -0,0 +1,328 @@
+// +build linux_bpf
+
+package protocols
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	ddebpf "github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	netebpf "github.com/DataDog/datadog-agent/pkg/network/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/network/ebpf/probes"
+	filterpkg "github.com/DataDog/datadog-agent/pkg/network/filter"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/ebpf"
+	"github.com/DataDog/ebpf/manager"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// Must match the ephemeral port range used by the socket filter to normalize tuples
+const (
+	ephemeralRangeBegin = 32768
+	ephemeralRangeEnd   = 60999
+)
+
+// expiryInterval is the interval at which the closed and idle connections are expired. The closed connections
+// are kept for an interval at least, so that their protocol can still be looked up when their close event is
+// processed.
+const expiryInterval = 30 * time.Second
+
+var errNotTCPSegment = errors.New("the packet is not a TCP segment")
+
+// packetSource reads raw packet data, it is implemented by filter.AFPacketSource
+type packetSource interface {
+	VisitPackets(exit <-chan struct{}, visit func([]byte, time.Time) error) error
+	PacketType() gopacket.LayerType
+	Close()
+}
+
+// segment is a TCP segment, its connection being identified by a Key whose source is the client
+type segment struct {
+	conn       Key
+	isIPv6     bool
+	seq        uint32
+	payload    []byte
+	fromClient bool
+}
+
+// segmentParser extracts the TCP segments from the packets passed by the socket filter
+type segmentParser struct {
+	decoder *gopacket.DecodingLayerParser
+	layers  []gopacket.LayerType
+	ipv4    *layers.IPv4
+	ipv6    *layers.IPv6
+	tcp     *layers.TCP
+}
+
+func newSegmentParser(layerType gopacket.LayerType) *segmentParser {
+	ipv4 := &layers.IPv4{}
+	ipv6 := &layers.IPv6{}
+	tcp := &layers.TCP{}
+	decoder := gopacket.NewDecodingLayerParser(layerType, &layers.Ethernet{}, ipv4, ipv6, tcp)
+	// the TCP payload is not decoded any further
+	decoder.IgnoreUnsupported = true
+
+	return &segmentParser{
+		decoder: decoder,
+		ipv4:    ipv4,
+		ipv6:    ipv6,
+		tcp:     tcp,
+	}
+}
+
+// ParseInto fills the segment from the packet data. The payload references the packet data.
+func (p *segmentParser) ParseInto(data []byte, s *segment) error {
+	if err := p.decoder.DecodeLayers(data, &p.layers); err != nil {
+		return err
+	}
+
+	var saddr, daddr util.Address
+	var isTCP bool
+	for _, layer := range p.layers {
+		switch layer {
+		case layers.LayerTypeIPv4:
+			saddr, daddr = util.V4AddressFromBytes(p.ipv4.SrcIP), util.V4AddressFromBytes(p.ipv4.DstIP)
+			s.isIPv6 = false
+		case layers.LayerTypeIPv6:
+			saddr, daddr = util.V6AddressFromBytes(p.ipv6.SrcIP), util.V6AddressFromBytes(p.ipv6.DstIP)
+			s.isIPv6 = true
+		case layers.LayerTypeTCP:
+			isTCP = true
+		}
+	}
+	if !isTCP || saddr == nil {
+		return errNotTCPSegment
+	}
+
+	sport, dport := uint16(p.tcp.SrcPort), uint16(p.tcp.DstPort)
+
+	// the tuple is normalized to (client, server)
+	s.fromClient = isEphemeralPort(sport)
+	if s.fromClient {
+		s.conn = NewKey(saddr, daddr, sport, dport, Unknown, "")
+	} else {
+		s.conn = NewKey(daddr, saddr, dport, sport, Unknown, "")
+	}
+	s.seq = p.tcp.Seq
+	s.payload = p.tcp.Payload
+	return nil
+}
+
+func isEphemeralPort(port uint16) bool {
+	return port >= ephemeralRangeBegin && port <= ephemeralRangeEnd
+}
+
+// Monitor classifies the protocol of the TCP connections with a socket filter, which looks at the first bytes
+// of their payloads. The filter passes to userspace the packets of the connections whose operations we decode,
+// and the handshakes of the TLS connections, the packets of the other connections are dropped once classified.
+type Monitor struct {
+	program    *ebpfProgram
+	source     packetSource
+	parser     *segmentParser
+	statkeeper *StatKeeper
+
+	// protocols is the eBPF map of the connections classified by the socket filter, the closed connections
+	// and the connections idle for longer than idleTimeout are removed from it by the monitor
+	protocols   *ebpf.Map
+	idleTimeout time.Duration
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewMonitor returns a new Monitor, its socket filter being attached to a raw socket of the root network namespace
+func NewMonitor(c *config.Config) (*Monitor, error) {
+	program, err := newEBPFProgram(c)
+	if err != nil {
+		return nil, fmt.Errorf("error creating ebpf program: %w", err)
+	}
+
+	if err := program.Init(); err != nil {
+		return nil, fmt.Errorf("error initializing ebpf program: %w", err)
+	}
+
+	protocols, _, err := program.GetMap(connectionProtocolsMap)
+	if err != nil {
+		_ = program.Stop(manager.CleanAll)
+		return nil, fmt.Errorf("error retrieving the map %s: %w", connectionProtocolsMap, err)
+	}
+
+	filter, _ := program.GetProbe(manager.ProbeIdentificationPair{Section: string(probes.SocketProtocolFilter)})
+	if filter == nil {
+		_ = program.Stop(manager.CleanAll)
+		return nil, fmt.Errorf("error retrieving socket filter")
+	}
+
+	// Create the RAW_SOCKET inside the root network namespace
+	var source *filterpkg.AFPacketSource
+	err = util.WithRootNS(c.ProcRoot, func() error {
+		source, err = filterpkg.NewPacketSource(filter)
+		return err
+	})
+	if err != nil {
+		_ = program.Stop(manager.CleanAll)
+		return nil, err
+	}
+
+	return &Monitor{
+		program:     program,
+		source:      source,
+		parser:      newSegmentParser(source.PacketType()),
+		statkeeper:  NewStatKeeper(int(c.MaxTrackedConnections)),
+		protocols:   protocols,
+		idleTimeout: c.TCPConnTimeout,
+		exit:        make(chan struct{}),
+	}, nil
+}
+
+// Start attaches the socket filter, then reads the packets and expires the connections until Stop is called
+func (m *Monitor) Start() error {
+	if m == nil {
+		return nil
+	}
+
+	if err := m.program.Start(); err != nil {
+		return err
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(expiryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.exit:
+				return
+			case <-ticker.C:
+				m.expire()
+			}
+		}
+	}()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		for {
+			if err := m.source.VisitPackets(m.exit, m.processPacket); err != nil {
+				log.Warnf("error reading tcp packet: %s", err)
+			}
+
+			select {
+			case <-m.exit:
+				return
+			default:
+			}
+
+			// Sleep briefly and try again
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	return nil
+}
+
+// GetProtocol returns the protocol of a connection, identified by a Key whose source is the client
+func (m *Monitor) GetProtocol(conn Key, isIPv6 bool) Protocol {
+	if m == nil {
+		return Unknown
+	}
+
+	return m.lookupProtocol(connTuple(conn, isIPv6))
+}
+
+// GetTLSMetadata returns the metadata of the handshake of a TLS connection, identified by a Key whose
+// source is the client, or nil if the connection isn't a TLS one or if its handshake wasn't seen
+func (m *Monitor) GetTLSMetadata(conn Key) *TLSMetadata {
+	if m == nil {
+		return nil
+	}
+
+	return m.statkeeper.GetTLSMetadata(conn)
+}
+
+// GetAndResetAllStats returns the operation stats collected since the last call
+func (m *Monitor) GetAndResetAllStats() map[Key]RequestStats {
+	if m == nil {
+		return nil
+	}
+
+	return m.statkeeper.GetAndResetAllStats()
+}
+
+// Stop stops reading the packets, detaches the socket filter and closes the packet source
+func (m *Monitor) Stop() {
+	if m == nil {
+		return
+	}
+
+	close(m.exit)
+	m.wg.Wait()
+	_ = m.program.Stop(manager.CleanAll)
+	m.source.Close()
+}
+
+// processPacket feeds the payload of a packet to the statkeeper. The packet data can't be referenced
+// after this call, as its memory gets reused by afpacket, which is fine since the decoders copy the
+// bytes they keep.
+func (m *Monitor) processPacket(data []byte, ts time.Time) error {
+	var s segment
+	if err := m.parser.ParseInto(data, &s); err != nil {
+		log.Tracef("error decoding tcp packet: %s", err)
+		return nil
+	}
+
+	protocol := m.lookupProtocol(connTuple(s.conn, s.isIPv6))
+	m.statkeeper.ProcessSegment(s.conn, protocol, s.seq, s.payload, s.fromClient, uint64(ts.UnixNano()))
+	return nil
+}
+
+func (m *Monitor) lookupProtocol(tuple netebpf.ConnTuple) Protocol {
+	var state protocolState
+	if err := m.protocols.Lookup(unsafe.Pointer(&tuple), unsafe.Pointer(&state)); err != nil {
+		return Unknown
+	}
+	return Protocol(state.protocol)
+}
+
+// expire removes the closed connections and the connections idle for longer than the idle timeout from the
+// eBPF map, and releases their state in userspace
+func (m *Monitor) expire() {
+	// the socket filter timestamps are read from the monotonic clock
+	ts, err := ddebpf.NowNanoseconds()
+	if err != nil {
+		log.Warnf("could not expire the classified connections: %s", err)
+		return
+	}
+
+	var (
+		key     netebpf.ConnTuple
+		state   protocolState
+		expired []netebpf.ConnTuple
+	)
+	entries := m.protocols.IterateFrom(unsafe.Pointer(&netebpf.ConnTuple{}))
+	for entries.Next(unsafe.Pointer(&key), unsafe.Pointer(&state)) {
+		idle := time.Duration(ts - int64(state.last_seen))
+		if idle > m.idleTimeout || (uint8(state.flags)&connClosed != 0 && idle > expiryInterval) {
+			expired = append(expired, key)
+		}
+	}
+	if err := entries.Err(); err != nil {
+		log.Warnf("unable to iterate the connection protocols map: %s", err)
+	}
+
+	// the entries are deleted once the iteration is done, as deleting them restarts it
+	for i := range expired {
+		_ = m.protocols.Delete(unsafe.Pointer(&expired[i]))
+		t := expired[i]
+		m.statkeeper.CloseConn(NewKey(t.SourceAddress(), t.DestAddress(), t.Sport, t.Dport, Unknown, ""))
+	}
+	if len(expired) > 0 {
+		log.Debugf("expired %d closed or idle classified connections", len(expired))
+	}
+}
@@ -0,0 +1,125 @@
+// +build linux_bpf
+
+package protocols
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegmentParser(t *testing.T) {
+	parser := newSegmentParser(layers.LayerTypeEthernet)
+	client, server := net.ParseIP("fd00::1"), net.ParseIP("fd00::2")
+	conn := NewKey(util.AddressFromNetIP(client), util.AddressFromNetIP(server), 40000, 6379, Unknown, "")
+
+	var s segment
+	tcp := &layers.TCP{SrcPort: 6379, DstPort: 40000, Seq: 42, ACK: true}
+	require.NoError(t, parser.ParseInto(tcpPacket(t, server, client, tcp, []byte("+PONG\r\n")), &s))
+	assert.Equal(t, conn, s.conn)
+	assert.True(t, s.isIPv6)
+	assert.False(t, s.fromClient)
+	assert.Equal(t, uint32(42), s.seq)
+	assert.Equal(t, []byte("+PONG\r\n"), s.payload)
+
+	assert.Equal(t, errNotTCPSegment, parser.ParseInto(udpPacket(t, net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2"), nil), &s))
+}
+
+func TestMonitorIntegration(t *testing.T) {
+	currKernelVersion, err := kernel.HostVersion()
+	require.NoError(t, err)
+	if currKernelVersion < kernel.VersionCode(4, 1, 0) {
+		t.Skip("protocol classification not available on pre 4.1.0 kernels")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:8379")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			c, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				buf := make([]byte, 64)
+				for {
+					if _, err := c.Read(buf); err != nil {
+						return
+					}
+					_, _ = c.Write([]byte("+PONG\r\n"))
+				}
+			}()
+		}
+	}()
+
+	monitor, err := NewMonitor(config.New())
+	require.NoError(t, err)
+	require.NoError(t, monitor.Start())
+	defer monitor.Stop()
+
+	c, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+
+	buf := make([]byte, 64)
+	for i := 0; i < 10; i++ {
+		_, err := c.Write([]byte("*1\r\n$4\r\nPING\r\n"))
+		require.NoError(t, err)
+		_, err = c.Read(buf)
+		require.NoError(t, err)
+	}
+	// ensure all the packets get read by the monitor
+	time.Sleep(100 * time.Millisecond)
+
+	laddr, raddr := c.LocalAddr().(*net.TCPAddr), c.RemoteAddr().(*net.TCPAddr)
+	conn := NewKey(util.AddressFromNetIP(laddr.IP), util.AddressFromNetIP(raddr.IP), uint16(laddr.Port), uint16(raddr.Port), Unknown, "")
+	assert.Equal(t, Redis, monitor.GetProtocol(conn, false))
+
+	key := conn
+	key.Protocol, key.Operation = Redis, "PING"
+	stats := monitor.GetAndResetAllStats()
+	assert.Equal(t, 10, stats[key].Count)
+}
+
+func tcpPacket(t *testing.T, src, dst net.IP, tcp *layers.TCP, payload []byte) []byte {
+	eth, ip := ipLayers(src, dst, layers.IPProtocolTCP)
+	require.NoError(t, tcp.SetNetworkLayerForChecksum(ip.(gopacket.NetworkLayer)))
+	return serialize(t, eth, ip, tcp, gopacket.Payload(payload))
+}
+
+func udpPacket(t *testing.T, src, dst net.IP, payload []byte) []byte {
+	eth, ip := ipLayers(src, dst, layers.IPProtocolUDP)
+	udp := &layers.UDP{SrcPort: 40000, DstPort: 53}
+	require.NoError(t, udp.SetNetworkLayerForChecksum(ip.(gopacket.NetworkLayer)))
+	return serialize(t, eth, ip, udp, gopacket.Payload(payload))
+}
+
+func ipLayers(src, dst net.IP, protocol layers.IPProtocol) (*layers.Ethernet, gopacket.SerializableLayer) {
+	eth := &layers.Ethernet{
+		SrcMAC: net.HardwareAddr{0, 0, 0, 0, 0, 1},
+		DstMAC: net.HardwareAddr{0, 0, 0, 0, 0, 2},
+	}
+	if src.To4() != nil {
+		eth.EthernetType = layers.EthernetTypeIPv4
+		return eth, &layers.IPv4{Version: 4, TTL: 64, Protocol: protocol, SrcIP: src, DstIP: dst}
+	}
+	eth.EthernetType = layers.EthernetTypeIPv6
+	return eth, &layers.IPv6{Version: 6, HopLimit: 64, NextHeader: protocol, SrcIP: src, DstIP: dst}
+}
+
+func serialize(t *testing.T, serializable ...gopacket.SerializableLayer) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, serializable...))
+	return buf.Bytes()
+}
@@ -0,0 +1,174 @@
+package protocols
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	postgresQuery          = 'Q'
+	postgresParse          = 'P'
+	postgresBind           = 'B'
+	postgresSync           = 'S'
+	postgresErrorResponse  = 'E'
+	postgresReadyForQuery  = 'Z'
+	postgresSSLAccepted    = 'S'
+	postgresSSLRefused     = 'N'
+	postgresUnknownCommand = "UNKNOWN"
+
+	postgresProtocolVersion3 = 196608   // 3.0
+	postgresSSLRequest       = 80877103 // 1234.5679
+	postgresGSSENCRequest    = 80877104 // 1234.5680
+)
+
+// postgresDecoder decodes the messages of the PostgreSQL frontend/backend protocol. Queries sent with
+// the simple query protocol, as well as the pipelines of the extended query protocol ending with Sync,
+// are matched with the ReadyForQuery messages closing their responses, in order.
+type postgresDecoder struct {
+	client messageReader
+	server messageReader
+
+	// the first message of the client, and the message following an SSL request
+	// refused by the server, are startup messages without type
+	startup bool
+	// after an SSL request, the server answers with a single byte
+	sslRequested bool
+
+	// command of the last Parse message of the extended query protocol pipeline
+	// being sent by the client
+	extendedCommand string
+	extended        bool
+
+	pending []transaction
+}
+
+func newPostgresDecoder() *postgresDecoder {
+	d := &postgresDecoder{startup: true}
+	d.client.split = d.splitClient
+	d.server.split = splitPostgresMessage
+	return d
+}
+
+func (d *postgresDecoder) splitClient(buf []byte) (int, error) {
+	if !d.startup {
+		return splitPostgresMessage(buf)
+	}
+	if len(buf) < 4 {
+		return 0, nil
+	}
+	length := int(binary.BigEndian.Uint32(buf))
+	if length < 8 {
+		return 0, errInvalidMessage
+	}
+	return length, nil
+}
+
+// splitPostgresMessage splits typed messages: a type byte followed by the big endian length
+// of the message, which includes itself
+func splitPostgresMessage(buf []byte) (int, error) {
+	if len(buf) < 5 {
+		return 0, nil
+	}
+	length := int(binary.BigEndian.Uint32(buf[1:]))
+	if length < 4 {
+		return 0, errInvalidMessage
+	}
+	return 1 + length, nil
+}
+
+func (d *postgresDecoder) Feed(payload []byte, fromClient bool, ts uint64) ([]transaction, error) {
+	if fromClient {
+		return nil, d.feedClient(payload, ts)
+	}
+
+	if d.sslRequested {
+		d.sslRequested = false
+		if len(payload) == 0 {
+			return nil, nil
+		}
+		if payload[0] != postgresSSLRefused {
+			// the rest of the connection is encrypted
+			return nil, errors.New("postgres connection encrypted with TLS")
+		}
+		d.startup = true
+		payload = payload[1:]
+	}
+
+	messages, err := d.server.read(payload)
+	var transactions []transaction
+	for _, msg := range messages {
+		switch msg[0] {
+		case postgresErrorResponse:
+			if len(d.pending) > 0 {
+				d.pending[0].isError = true
+			}
+		case postgresReadyForQuery:
+			if len(d.pending) == 0 {
+				// end of the startup, or of a pipeline we haven't seen
+				continue
+			}
+			tx := d.pending[0]
+			d.pending = d.pending[1:]
+			tx.responseLastSeen = ts
+			transactions = append(transactions, tx)
+		}
+	}
+	return transactions, err
+}
+
+func (d *postgresDecoder) feedClient(payload []byte, ts uint64) error {
+	messages, err := d.client.read(payload)
+	for _, msg := range messages {
+		if d.startup {
+			d.startup = false
+			if len(msg) >= 8 {
+				switch binary.BigEndian.Uint32(msg[4:]) {
+				case postgresSSLRequest, postgresGSSENCRequest:
+					d.sslRequested = true
+				}
+			}
+			continue
+		}
+
+		switch msg[0] {
+		case postgresQuery:
+			d.addPending(postgresCommand(msg[5:]), ts)
+		case postgresParse:
+			// name of the prepared statement, followed by the query
+			body := msg[5:]
+			if i := bytes.IndexByte(body, 0); i >= 0 {
+				d.extendedCommand = postgresCommand(body[i+1:])
+			}
+			d.extended = true
+		case postgresBind:
+			if !d.extended {
+				// execution of a statement prepared in a previous pipeline
+				d.extendedCommand = postgresUnknownCommand
+				d.extended = true
+			}
+		case postgresSync:
+			if d.extended {
+				d.addPending(d.extendedCommand, ts)
+				d.extended = false
+				d.extendedCommand = ""
+			}
+		}
+	}
+	return err
+}
+
+func (d *postgresDecoder) addPending(command string, ts uint64) {
+	if len(d.pending) >= maxPendingTransactions {
+		return
+	}
+	d.pending = append(d.pending, transaction{
+		operation:      command,
+		requestStarted: ts,
+	})
+}
+
+// postgresCommand returns the command of a query, which is its first keyword, such as SELECT
+func postgresCommand(query []byte) string {
+	return command(query, postgresUnknownCommand)
+}
@@ -0,0 +1,165 @@
+package protocols
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postgresMessage(typ byte, body string) []byte {
+	msg := make([]byte, 5, 5+len(body))
+	msg[0] = typ
+	binary.BigEndian.PutUint32(msg[1:], uint32(4+len(body)))
+	return append(msg, body...)
+}
+
+func postgresStartupMessage() []byte {
+	params := []byte("user\x00postgres\x00database\x00app\x00\x00")
+	msg := make([]byte, 8, 8+len(params))
+	binary.BigEndian.PutUint32(msg, uint32(8+len(params)))
+	binary.BigEndian.PutUint32(msg[4:], postgresProtocolVersion3)
+	return append(msg, params...)
+}
+
+func postgresSSLRequestMessage() []byte {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint32(msg, 8)
+	binary.BigEndian.PutUint32(msg[4:], postgresSSLRequest)
+	return msg
+}
+
+func concat(messages ...[]byte) []byte {
+	var ret []byte
+	for _, msg := range messages {
+		ret = append(ret, msg...)
+	}
+	return ret
+}
+
+func postgresStartupResponse() []byte {
+	return concat(
+		postgresMessage('R', "\x00\x00\x00\x00"),
+		postgresMessage('S', "server_version\x0013.4\x00"),
+		postgresMessage('K', "\x00\x00\x00\x01\x00\x00\x00\x02"),
+		postgresMessage(postgresReadyForQuery, "I"),
+	)
+}
+
+func TestPostgresSimpleQuery(t *testing.T) {
+	d := newPostgresDecoder()
+
+	_, err := d.Feed(postgresStartupMessage(), true, 0)
+	require.NoError(t, err)
+	txs, err := d.Feed(postgresStartupResponse(), false, 10)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+
+	_, err = d.Feed(postgresMessage(postgresQuery, "SELECT * FROM users\x00"), true, 100)
+	require.NoError(t, err)
+	txs, err = d.Feed(concat(
+		postgresMessage('T', "..."),
+		postgresMessage('D', "..."),
+		postgresMessage('C', "SELECT 1\x00"),
+		postgresMessage(postgresReadyForQuery, "I"),
+	), false, 350)
+	require.NoError(t, err)
+	assert.Equal(t, []transaction{{operation: "SELECT", requestStarted: 100, responseLastSeen: 350}}, txs)
+
+	_, err = d.Feed(postgresMessage(postgresQuery, "insert into missing values (1)\x00"), true, 400)
+	require.NoError(t, err)
+	txs, err = d.Feed(concat(
+		postgresMessage(postgresErrorResponse, "SERROR\x00C42P01\x00\x00"),
+		postgresMessage(postgresReadyForQuery, "I"),
+	), false, 500)
+	require.NoError(t, err)
+	assert.Equal(t, []transaction{{operation: "INSERT", isError: true, requestStarted: 400, responseLastSeen: 500}}, txs)
+}
+
+func TestPostgresExtendedQuery(t *testing.T) {
+	d := newPostgresDecoder()
+	_, err := d.Feed(postgresStartupMessage(), true, 0)
+	require.NoError(t, err)
+
+	// two pipelines sent at once, the second one executing the statement prepared by the first one
+	_, err = d.Feed(concat(
+		postgresMessage(postgresParse, "stmt1\x00DELETE FROM users WHERE id = $1\x00\x00\x00"),
+		postgresMessage(postgresBind, "\x00stmt1\x00..."),
+		postgresMessage('E', "\x00\x00\x00\x00\x00"),
+		postgresMessage(postgresSync, ""),
+		postgresMessage(postgresBind, "\x00stmt1\x00..."),
+		postgresMessage('E', "\x00\x00\x00\x00\x00"),
+		postgresMessage(postgresSync, ""),
+	), true, 100)
+	require.NoError(t, err)
+	assert.Len(t, d.pending, 2)
+
+	// the responses are split across payloads
+	response := concat(
+		postgresMessage('1', ""),
+		postgresMessage('2', ""),
+		postgresMessage('C', "DELETE 1\x00"),
+		postgresMessage(postgresReadyForQuery, "I"),
+		postgresMessage('2', ""),
+		postgresMessage('C', "DELETE 0\x00"),
+		postgresMessage(postgresReadyForQuery, "I"),
+	)
+	txs, err := d.Feed(response[:7], false, 200)
+	require.NoError(t, err)
+	assert.Empty(t, txs)
+	txs, err = d.Feed(response[7:], false, 300)
+	require.NoError(t, err)
+	assert.Equal(t, []transaction{
+		{operation: "DELETE", requestStarted: 100, responseLastSeen: 300},
+		{operation: postgresUnknownCommand, requestStarted: 100, responseLastSeen: 300},
+	}, txs)
+}
+
+func TestPostgresSSL(t *testing.T) {
+	// SSL refused, the client sends its startup message in clear text
+	d := newPostgresDecoder()
+	_, err := d.Feed(postgresSSLRequestMessage(), true, 0)
+	require.NoError(t, err)
+	_, err = d.Feed([]byte{postgresSSLRefused}, false, 0)
+	require.NoError(t, err)
+	_, err = d.Feed(postgresStartupMessage(), true, 0)
+	require.NoError(t, err)
+	_, err = d.Feed(postgresStartupResponse(), false, 0)
+	require.NoError(t, err)
+
+	_, err = d.Feed(postgresMessage(postgresQuery, "BEGIN\x00"), true, 10)
+	require.NoError(t, err)
+	txs, err := d.Feed(postgresMessage(postgresReadyForQuery, "T"), false, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []transaction{{operation: "BEGIN", requestStarted: 10, responseLastSeen: 20}}, txs)
+
+	// SSL accepted, the connection can't be decoded
+	d = newPostgresDecoder()
+	_, err = d.Feed(postgresSSLRequestMessage(), true, 0)
+	require.NoError(t, err)
+	_, err = d.Feed([]byte{postgresSSLAccepted}, false, 0)
+	assert.Error(t, err)
+}
+
+func TestPostgresLargeQuery(t *testing.T) {
+	d := newPostgresDecoder()
+	_, err := d.Feed(postgresStartupMessage(), true, 0)
+	require.NoError(t, err)
+
+	query := postgresMessage(postgresQuery, "COPY users FROM STDIN "+string(make([]byte, 2*maxMessageSize))+"\x00")
+	for i := 0; i < len(query); i += 1500 {
+		end := i + 1500
+		if end > len(query) {
+			end = len(query)
+		}
+		_, err = d.Feed(query[i:end], true, 10)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, d.client.pending)
+	assert.Equal(t, 0, d.client.skip)
+
+	txs, err := d.Feed(postgresMessage(postgresReadyForQuery, "I"), false, 20)
+	require.NoError(t, err)
+	assert.Equal(t, []transaction{{operation: "COPY", requestStarted: 10, responseLastSeen: 20}}, txs)
+}
@@ -0,0 +1,52 @@
+package protocols
+
+// Protocol is the application protocol running on a connection
+type Protocol uint8
+
+const (
+	// Unknown is used for connections whose protocol couldn't be classified
+	Unknown Protocol = iota
+	// HTTP represents HTTP/1.x connections
+	HTTP
+	// HTTP2 represents HTTP/2 connections, including gRPC
+	HTTP2
+	// TLS represents connections encrypted with TLS, whatever the protocol they carry
+	TLS
+	// Postgres represents PostgreSQL connections
+	Postgres
+	// MySQL represents MySQL connections
+	MySQL
+	// Redis represents Redis connections
+	Redis
+	// Kafka represents Kafka connections
+	Kafka
+	// AMQP represents AMQP 0-9-1 connections
+	AMQP
+	// MongoDB represents MongoDB connections
+	MongoDB
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case HTTP:
+		return "http"
+	case HTTP2:
+		return "http2"
+	case TLS:
+		return "tls"
+	case Postgres:
+		return "postgres"
+	case MySQL:
+		return "mysql"
+	case Redis:
+		return "redis"
+	case Kafka:
+		return "kafka"
+	case AMQP:
+		return "amqp"
+	case MongoDB:
+		return "mongodb"
+	default:
+		return "unknown"
+	}
+}
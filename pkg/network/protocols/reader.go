@@ -0,0 +1,80 @@
+package protocols
+
+import "errors"
+
+const (
+	// maxMessageSize bounds the size of the messages we buffer, only the beginning of
+	// bigger messages is decoded
+	maxMessageSize = 1 << 16
+
+	// truncatedMessageSize is the number of bytes of a message bigger than maxMessageSize
+	// that are buffered before it is decoded
+	truncatedMessageSize = 512
+)
+
+// errInvalidMessage is returned by message splitting functions when a payload doesn't contain
+// a valid message, which means the state of the connection was lost
+var errInvalidMessage = errors.New("invalid message")
+
+// splitFunc returns the size of the message at the beginning of buf, or 0 if buf does not hold
+// enough bytes to tell
+type splitFunc func(buf []byte) (int, error)
+
+// messageReader splits the payloads sent by one of the endpoints of a connection into messages,
+// buffering incomplete messages until the following payloads are received
+type messageReader struct {
+	split splitFunc
+
+	pending []byte
+	// message bytes left to skip, for messages bigger than maxMessageSize
+	skip int
+}
+
+// read returns the complete messages of a payload. Messages bigger than maxMessageSize are
+// truncated, in which case the returned message is shorter than the size returned by split.
+func (r *messageReader) read(payload []byte) ([][]byte, error) {
+	if r.skip > 0 {
+		if len(payload) <= r.skip {
+			r.skip -= len(payload)
+			return nil, nil
+		}
+		payload = payload[r.skip:]
+		r.skip = 0
+	}
+
+	if len(r.pending) > 0 {
+		payload = append(r.pending, payload...)
+		r.pending = nil
+	}
+
+	var messages [][]byte
+	for len(payload) > 0 {
+		size, err := r.split(payload)
+		if err != nil {
+			return messages, err
+		}
+		if size == 0 {
+			if len(payload) > maxMessageSize {
+				return messages, errInvalidMessage
+			}
+			break
+		}
+
+		if len(payload) < size {
+			if size <= maxMessageSize || len(payload) < truncatedMessageSize {
+				break
+			}
+			messages = append(messages, payload)
+			r.skip = size - len(payload)
+			return messages, nil
+		}
+
+		messages = append(messages, payload[:size])
+		payload = payload[size:]
+	}
+
+	if len(payload) > 0 {
+		r.pending = append([]byte(nil), payload...)
+	}
+	return messages, nil
+}
@@ -0,0 +1,164 @@
+package protocols
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxRESPDepth bounds the nesting of the RESP aggregate types we decode
+	maxRESPDepth = 16
+
+	redisUnknownCommand = "UNKNOWN"
+)
+
+var crlf = []byte("\r\n")
+
+// redisDecoder decodes the RESP2 and RESP3 protocols used by Redis. As commands are answered
+// in order, including when they are pipelined, each reply is matched with the oldest pending
+// command. Out-of-band RESP3 push messages and attributes are ignored.
+type redisDecoder struct {
+	client messageReader
+	server messageReader
+
+	pending []transaction
+}
+
+func newRedisDecoder() *redisDecoder {
+	return &redisDecoder{
+		client: messageReader{split: splitRedisCommand},
+		server: messageReader{split: splitRESPValue},
+	}
+}
+
+func (d *redisDecoder) Feed(payload []byte, fromClient bool, ts uint64) ([]transaction, error) {
+	if fromClient {
+		messages, err := d.client.read(payload)
+		for _, msg := range messages {
+			if len(d.pending) >= maxPendingTransactions {
+				break
+			}
+			d.pending = append(d.pending, transaction{
+				operation:      redisCommand(msg),
+				requestStarted: ts,
+			})
+		}
+		return nil, err
+	}
+
+	messages, err := d.server.read(payload)
+	var transactions []transaction
+	for _, msg := range messages {
+		if msg[0] == '>' || msg[0] == '|' || len(d.pending) == 0 {
+			continue
+		}
+
+		tx := d.pending[0]
+		d.pending = d.pending[1:]
+		tx.isError = msg[0] == '-' || msg[0] == '!'
+		tx.responseLastSeen = ts
+		transactions = append(transactions, tx)
+	}
+	return transactions, err
+}
+
+// splitRedisCommand splits the commands sent by clients, which are either arrays of bulk
+// strings or inline commands terminated by a new line
+func splitRedisCommand(buf []byte) (int, error) {
+	if buf[0] == '*' {
+		return respSize(buf, 0)
+	}
+	i := bytes.IndexByte(buf, '\n')
+	if i < 0 {
+		return 0, nil
+	}
+	return i + 1, nil
+}
+
+func splitRESPValue(buf []byte) (int, error) {
+	return respSize(buf, 0)
+}
+
+// respSize returns the size of the RESP value at the beginning of buf, or 0 if buf does not hold
+// enough bytes to tell. The size of a bulk string is known from its header, so the size of an
+// aggregate value is known as soon as the header of its last element is received.
+func respSize(buf []byte, depth int) (int, error) {
+	if depth > maxRESPDepth {
+		return 0, errInvalidMessage
+	}
+
+	end := bytes.Index(buf, crlf)
+	if end < 0 {
+		return 0, nil
+	}
+	header := end + len(crlf)
+
+	switch buf[0] {
+	case '+', '-', ':', '_', ',', '#', '(':
+		return header, nil
+
+	case '$', '=', '!':
+		n, err := strconv.Atoi(string(buf[1:end]))
+		if err != nil || n < -1 {
+			return 0, errInvalidMessage
+		}
+		if n == -1 {
+			return header, nil
+		}
+		return header + n + len(crlf), nil
+
+	case '*', '~', '>', '%', '|':
+		n, err := strconv.Atoi(string(buf[1:end]))
+		if err != nil || n < -1 {
+			return 0, errInvalidMessage
+		}
+		if buf[0] == '%' || buf[0] == '|' {
+			n *= 2
+		}
+
+		size := header
+		for i := 0; i < n; i++ {
+			if size >= len(buf) {
+				return 0, nil
+			}
+			elem, err := respSize(buf[size:], depth+1)
+			if err != nil || elem == 0 {
+				return 0, err
+			}
+			size += elem
+			if size > len(buf) && i < n-1 {
+				return 0, nil
+			}
+		}
+		return size, nil
+
+	default:
+		return 0, errInvalidMessage
+	}
+}
+
+// redisCommand returns the name of a command in upper case, such as GET
+func redisCommand(msg []byte) string {
+	if msg[0] != '*' {
+		// inline command
+		return command(msg, redisUnknownCommand)
+	}
+
+	// skip the array header, up to the first bulk string
+	i := bytes.Index(msg, crlf)
+	if i < 0 || len(msg) < i+len(crlf)+1 || msg[i+len(crlf)] != '$' {
+		return redisUnknownCommand
+	}
+	bulk := msg[i+len(crlf):]
+
+	end := bytes.Index(bulk, crlf)
+	if end < 0 {
+		return redisUnknownCommand
+	}
+	n, err := strconv.Atoi(string(bulk[1:end]))
+	if err != nil || n <= 0 || n > maxCommandLength || len(bulk) < end+len(crlf)+n {
+		return redisUnknownCommand
+	}
+	return strings.ToUpper(string(bulk[end+len(crlf) : end+len(crlf)+n]))
+}
@@ -0,0 +1,105 @@
+package protocols
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisDecoder(t *testing.T) {
+	d := newRedisDecoder()
+
+	// pipelined commands, including an inline one
+	_, err := d.Feed([]byte(
+		"*3\r\n$3\r\nset\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"+
+			"*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n"+
+			"PING\r\n"+
+			"*2\r\n$5\r\nLPUSH\r\n$3\r\nkey\r\n"+
+			"*3\r\n$5\r\nHMGET\r\n$4\r\nhash\r\n$1\r\na\r\n",
+	), true, 100)
+	require.NoError(t, err)
+	assert.Len(t, d.pending, 5)
+
+	txs, err := d.Feed([]byte(
+		"+OK\r\n"+
+			"$5\r\nvalue\r\n"+
+			// RESP3 push message, not matched with a command
+			">3\r\n$7\r\nmessage\r\n$7\r\nchannel\r\n$5\r\nhello\r\n"+
+			"+PONG\r\n"+
+			"-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"+
+			"*1\r\n$-1\r\n",
+	), false, 300)
+	require.NoError(t, err)
+	assert.Empty(t, d.pending)
+
+	assert.Equal(t, []transaction{
+		{operation: "SET", requestStarted: 100, responseLastSeen: 300},
+		{operation: "GET", requestStarted: 100, responseLastSeen: 300},
+		{operation: "PING", requestStarted: 100, responseLastSeen: 300},
+		{operation: "LPUSH", isError: true, requestStarted: 100, responseLastSeen: 300},
+		{operation: "HMGET", requestStarted: 100, responseLastSeen: 300},
+	}, txs)
+}
+
+func TestRedisLargeReply(t *testing.T) {
+	d := newRedisDecoder()
+	_, err := d.Feed([]byte("*2\r\n$6\r\nLRANGE\r\n$4\r\nlist\r\n"), true, 10)
+	require.NoError(t, err)
+	_, err = d.Feed([]byte("*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n"), true, 10)
+	require.NoError(t, err)
+
+	// an array whose last element is bigger than maxMessageSize, followed by the reply of GET
+	value := strings.Repeat("x", 3*maxMessageSize)
+	reply := []byte("*2\r\n$1\r\na\r\n$" + "196608" + "\r\n" + value + "\r\n" + "$-1\r\n")
+
+	var txs []transaction
+	for i := 0; i < len(reply); i += 1500 {
+		end := i + 1500
+		if end > len(reply) {
+			end = len(reply)
+		}
+		res, err := d.Feed(reply[i:end], false, 20)
+		require.NoError(t, err)
+		txs = append(txs, res...)
+	}
+
+	require.Len(t, txs, 2)
+	assert.Equal(t, "LRANGE", txs[0].operation)
+	assert.Equal(t, "GET", txs[1].operation)
+}
+
+func TestRESPSize(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{"+OK\r\n", 5},
+		{"+OK", 0},
+		{":1000\r\n", 7},
+		{"$5\r\nhello\r\n", 11},
+		{"$5\r\nhel", 11},
+		{"$-1\r\n", 5},
+		{"*-1\r\n", 5},
+		{"*0\r\n", 4},
+		{"*2\r\n$1\r\na\r\n$1\r\nb\r\n", 18},
+		{"*2\r\n$1\r\na\r\n", 0},
+		{"*2\r\n$5\r\nhel", 0},
+		{"%1\r\n+key\r\n:1\r\n", 14},
+		{"*1\r\n*1\r\n#t\r\n", 12},
+	}
+
+	for _, test := range tests {
+		size, err := respSize([]byte(test.value), 0)
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, test.expected, size, test.value)
+	}
+
+	_, err := respSize([]byte("?\r\n"), 0)
+	assert.Error(t, err)
+	_, err = respSize([]byte("$abc\r\n"), 0)
+	assert.Error(t, err)
+	_, err = respSize([]byte(strings.Repeat("*1\r\n", maxRESPDepth+2)), 0)
+	assert.Error(t, err)
+}
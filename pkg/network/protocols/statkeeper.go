@@ -0,0 +1,266 @@
+package protocols
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// connState holds the decoding state of a connection
+type connState struct {
+	protocol Protocol
+	decoder  decoder
+
+	// handshake decoder of TLS connections
+	tls     *tlsDecoder
+	tlsDone bool
+
+	// TCP sequence numbers expected for the next segment of the client and the server, used to skip
+	// the segments seen twice (retransmissions, or loopback traffic captured on both its way out and in)
+	nextSeq  [2]uint32
+	seqKnown [2]bool
+}
+
+// StatKeeper decodes the payloads of the connections classified by the socket filter, and aggregates
+// the latency and errors of the operations of the protocols we decode. The metadata of the handshake
+// of TLS connections is kept until they are closed.
+//
+// The state of the connections and the results are guarded by distinct locks, so that the payloads
+// are decoded without blocking the readers of the stats and of the TLS metadata.
+type StatKeeper struct {
+	connsMux   sync.Mutex
+	conns      map[Key]*connState
+	maxEntries int
+
+	mux   sync.Mutex
+	stats map[Key]RequestStats
+	tls   map[Key]*TLSMetadata
+
+	dropped      int64 // this happens when StatKeeper reaches capacity
+	decodeErrors int64 // this happens when the state of a connection is lost
+
+	// map containing interned operation strings
+	// this is rotated with the stats map
+	interned map[string]string
+}
+
+// NewStatKeeper returns a new StatKeeper tracking at most maxEntries connections and
+// as many operation stats
+func NewStatKeeper(maxEntries int) *StatKeeper {
+	return &StatKeeper{
+		conns:      make(map[Key]*connState),
+		maxEntries: maxEntries,
+		stats:      make(map[Key]RequestStats),
+		tls:        make(map[Key]*TLSMetadata),
+		interned:   make(map[string]string),
+	}
+}
+
+// Process decodes a payload sent on a connection at the given timestamp, in nanoseconds. The connection is
+// identified by a Key without protocol nor operation, its source being the client, and protocol is the one
+// classified by the socket filter.
+func (s *StatKeeper) Process(conn Key, protocol Protocol, payload []byte, fromClient bool, ts uint64) {
+	s.connsMux.Lock()
+	defer s.connsMux.Unlock()
+	s.process(conn.connKey(), protocol, payload, fromClient, ts)
+}
+
+// ProcessSegment processes the payload of a TCP segment, skipping the bytes already processed.
+// A gap in the sequence numbers means the state of the connection decoder was lost.
+func (s *StatKeeper) ProcessSegment(conn Key, protocol Protocol, seq uint32, payload []byte, fromClient bool, ts uint64) {
+	if len(payload) == 0 {
+		return
+	}
+
+	s.connsMux.Lock()
+	defer s.connsMux.Unlock()
+
+	conn = conn.connKey()
+	dir := 0
+	if !fromClient {
+		dir = 1
+	}
+
+	end := seq + uint32(len(payload))
+	if c, ok := s.conns[conn]; ok && c.protocol == protocol && c.seqKnown[dir] {
+		switch offset := int32(c.nextSeq[dir] - seq); {
+		case offset < 0:
+			if c.decoder != nil {
+				log.Tracef("could not decode %s connection: missed %d bytes", c.protocol, -offset)
+				atomic.AddInt64(&s.decodeErrors, 1)
+				c.decoder = nil
+			}
+		case int(offset) >= len(payload):
+			return
+		default:
+			payload = payload[offset:]
+		}
+	}
+
+	s.process(conn, protocol, payload, fromClient, ts)
+
+	if c, ok := s.conns[conn]; ok {
+		c.nextSeq[dir] = end
+		c.seqKnown[dir] = true
+	}
+}
+
+// process decodes a payload, the caller must hold connsMux
+func (s *StatKeeper) process(conn Key, protocol Protocol, payload []byte, fromClient bool, ts uint64) {
+	if len(payload) == 0 || protocol == Unknown {
+		return
+	}
+
+	c := s.getConn(conn, protocol)
+	if c == nil {
+		return
+	}
+
+	if c.tls != nil {
+		s.processTLS(conn, c, payload, fromClient)
+		return
+	}
+
+	if c.decoder == nil {
+		return
+	}
+
+	transactions, err := c.decoder.Feed(payload, fromClient, ts)
+	if len(transactions) > 0 {
+		s.mux.Lock()
+		for _, tx := range transactions {
+			s.add(conn, c.protocol, tx)
+		}
+		s.mux.Unlock()
+	}
+	if err != nil {
+		log.Tracef("could not decode %s connection: %s", c.protocol, err)
+		atomic.AddInt64(&s.decodeErrors, 1)
+		// the following payloads are ignored
+		c.decoder = nil
+	}
+}
+
+// GetTLSMetadata returns the metadata of the handshake of a TLS connection, or nil if
+// the connection isn't a TLS one or if its handshake wasn't seen
+func (s *StatKeeper) GetTLSMetadata(conn Key) *TLSMetadata {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	metadata, ok := s.tls[conn.connKey()]
+	if !ok {
+		return nil
+	}
+	ret := *metadata
+	return &ret
+}
+
+// CloseConn releases the state of a closed connection
+func (s *StatKeeper) CloseConn(conn Key) {
+	conn = conn.connKey()
+	s.connsMux.Lock()
+	delete(s.conns, conn)
+	s.connsMux.Unlock()
+
+	s.mux.Lock()
+	delete(s.tls, conn)
+	s.mux.Unlock()
+}
+
+// GetAndResetAllStats returns the operation stats collected since the last call
+func (s *StatKeeper) GetAndResetAllStats() map[Key]RequestStats {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	ret := s.stats // No deep copy needed since `s.stats` gets reset
+	s.stats = make(map[Key]RequestStats)
+	s.interned = make(map[string]string)
+
+	log.Debugf(
+		"protocol stats summary: aggregations=%d tls_connections=%d dropped=%d decode_errors=%d",
+		len(ret),
+		len(s.tls),
+		atomic.SwapInt64(&s.dropped, 0),
+		atomic.SwapInt64(&s.decodeErrors, 0),
+	)
+	return ret
+}
+
+// getConn returns the state of a connection, creating it on its first payload, or nil if its protocol
+// isn't decoded. The state is reset if the protocol of the connection changed, which happens when its
+// tuple is reused after it was expired.
+func (s *StatKeeper) getConn(conn Key, protocol Protocol) *connState {
+	c, ok := s.conns[conn]
+	if ok && c.protocol == protocol {
+		return c
+	}
+
+	c = &connState{
+		protocol: protocol,
+		decoder:  newDecoder(protocol),
+	}
+	if protocol == TLS {
+		c.tls = newTLSDecoder()
+	}
+	if c.decoder == nil && c.tls == nil {
+		delete(s.conns, conn)
+		return nil
+	}
+
+	if !ok && len(s.conns) >= s.maxEntries {
+		atomic.AddInt64(&s.dropped, 1)
+		return nil
+	}
+	s.conns[conn] = c
+	return c
+}
+
+func (s *StatKeeper) processTLS(conn Key, c *connState, payload []byte, fromClient bool) {
+	if c.tlsDone {
+		return
+	}
+
+	done, err := c.tls.Feed(payload, fromClient)
+	if err != nil {
+		log.Tracef("could not decode tls handshake: %s", err)
+		atomic.AddInt64(&s.decodeErrors, 1)
+		// the metadata decoded so far is kept
+		done = true
+	}
+	c.tlsDone = done
+
+	metadata := c.tls.metadata
+	if metadata.Version == 0 && metadata.ServerName == "" {
+		return
+	}
+
+	s.mux.Lock()
+	s.tls[conn] = &metadata
+	s.mux.Unlock()
+}
+
+// add records a transaction, the caller must hold the lock
+func (s *StatKeeper) add(conn Key, protocol Protocol, tx transaction) {
+	key := conn
+	key.Protocol = protocol
+	key.Operation = s.intern(tx.operation)
+
+	stats, ok := s.stats[key]
+	if !ok && len(s.stats) >= s.maxEntries {
+		atomic.AddInt64(&s.dropped, 1)
+		return
+	}
+
+	stats.AddRequest(tx.latency(), tx.isError)
+	s.stats[key] = stats
+}
+
+func (s *StatKeeper) intern(str string) string {
+	v, ok := s.interned[str]
+	if !ok {
+		s.interned[str] = str
+		v = str
+	}
+	return v
+}
@@ -0,0 +1,133 @@
+package protocols
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatKeeper(t *testing.T) {
+	sk := NewStatKeeper(1000)
+	saddr := util.AddressFromString("1.1.1.1")
+	daddr := util.AddressFromString("2.2.2.2")
+
+	redisConn := NewKey(saddr, daddr, 1234, 6379, Unknown, "")
+	for i := 0; i < 3; i++ {
+		sk.Process(redisConn, Redis, []byte("*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n"), true, uint64(i*1000))
+		sk.Process(redisConn, Redis, []byte("-ERR\r\n"), false, uint64(i*1000+100*(i+1)))
+	}
+
+	// the connections whose protocol we only classify aren't tracked
+	mysqlConn := NewKey(saddr, daddr, 1235, 3306, Unknown, "")
+	sk.Process(mysqlConn, MySQL, []byte("\x05\x00\x00\x00\x0a8.0\x00"), false, 0)
+	unknownConn := NewKey(saddr, daddr, 1236, 22, Unknown, "")
+	sk.Process(unknownConn, Unknown, []byte("SSH-2.0-OpenSSH_8.2p1\r\n"), true, 0)
+	assert.Len(t, sk.conns, 1)
+
+	stats := sk.GetAndResetAllStats()
+	require.Len(t, stats, 1)
+	assert.Empty(t, sk.stats)
+
+	getStats := stats[NewKey(saddr, daddr, 1234, 6379, Redis, "GET")]
+	assert.Equal(t, 3, getStats.Count)
+	assert.Equal(t, 3, getStats.ErrorCount)
+	p50, err := getStats.Latencies.GetValueAtQuantile(0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 200, p50, 200*RelativeAccuracy)
+
+	sk.CloseConn(redisConn)
+	assert.NotContains(t, sk.conns, redisConn)
+}
+
+func TestStatKeeperDecodeError(t *testing.T) {
+	sk := NewStatKeeper(1000)
+	conn := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 6379, Unknown, "")
+
+	sk.Process(conn, Redis, []byte("*1\r\n$4\r\nPING\r\n"), true, 0)
+	sk.Process(conn, Redis, []byte("?garbage\r\n"), false, 0)
+	assert.Equal(t, int64(1), sk.decodeErrors)
+
+	// the connection isn't decoded anymore
+	sk.Process(conn, Redis, []byte("*1\r\n$4\r\nPING\r\n"), true, 0)
+	sk.Process(conn, Redis, []byte("+PONG\r\n"), false, 0)
+	assert.Empty(t, sk.GetAndResetAllStats())
+}
+
+func TestStatKeeperMaxEntries(t *testing.T) {
+	sk := NewStatKeeper(1)
+	saddr := util.AddressFromString("1.1.1.1")
+	daddr := util.AddressFromString("2.2.2.2")
+
+	sk.Process(NewKey(saddr, daddr, 1234, 6379, Unknown, ""), Redis, []byte("*1\r\n$4\r\nPING\r\n"), true, 0)
+	sk.Process(NewKey(saddr, daddr, 1235, 6379, Unknown, ""), Redis, []byte("*1\r\n$4\r\nPING\r\n"), true, 0)
+	assert.Len(t, sk.conns, 1)
+	assert.Equal(t, int64(1), sk.dropped)
+}
+
+func TestRequestStatsCombineWith(t *testing.T) {
+	var stats RequestStats
+	stats.AddRequest(10, false)
+
+	var other RequestStats
+	other.AddRequest(20, true)
+	other.AddRequest(30, false)
+
+	stats.CombineWith(other)
+	assert.Equal(t, 3, stats.Count)
+	assert.Equal(t, 1, stats.ErrorCount)
+	assert.Equal(t, 3.0, stats.Latencies.GetCount())
+
+	var single RequestStats
+	single.AddRequest(40, true)
+	stats.CombineWith(single)
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, 2, stats.ErrorCount)
+}
+
+func TestStatKeeperProcessSegment(t *testing.T) {
+	sk := NewStatKeeper(1000)
+	saddr := util.AddressFromString("1.1.1.1")
+	daddr := util.AddressFromString("2.2.2.2")
+	conn := NewKey(saddr, daddr, 1234, 6379, Unknown, "")
+
+	get := []byte("*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n")
+	sk.ProcessSegment(conn, Redis, 100, get, true, 0)
+	// a retransmission, as seen on loopback
+	sk.ProcessSegment(conn, Redis, 100, get, true, 0)
+	sk.ProcessSegment(conn, Redis, 500, []byte("$1\r\na\r\n"), false, 100)
+	// a segment overlapping the bytes already processed
+	next := uint32(100 + len(get))
+	sk.ProcessSegment(conn, Redis, next-4, append([]byte("key\r\n")[1:], get...), true, 200)
+	sk.ProcessSegment(conn, Redis, 507, []byte("$1\r\na\r\n"), false, 300)
+
+	stats := sk.GetAndResetAllStats()
+	getStats := stats[NewKey(saddr, daddr, 1234, 6379, Redis, "GET")]
+	assert.Equal(t, 2, getStats.Count)
+	assert.Equal(t, 0, getStats.ErrorCount)
+
+	// the next client bytes were missed, the connection can't be decoded anymore
+	sk.ProcessSegment(conn, Redis, next+uint32(len(get))+10, get, true, 400)
+	sk.ProcessSegment(conn, Redis, 514, []byte("$1\r\na\r\n"), false, 500)
+	assert.Empty(t, sk.GetAndResetAllStats())
+}
+
+func TestStatKeeperProtocolChange(t *testing.T) {
+	sk := NewStatKeeper(1000)
+	saddr := util.AddressFromString("1.1.1.1")
+	daddr := util.AddressFromString("2.2.2.2")
+	conn := NewKey(saddr, daddr, 1234, 6379, Unknown, "")
+
+	// the decoder of the previous connection using the same tuple is discarded
+	sk.Process(conn, Redis, []byte("*1\r\n$4\r\nPI"), true, 0)
+	sk.Process(conn, Postgres, postgresStartupMessage(), true, 0)
+	sk.Process(conn, Postgres, postgresStartupResponse(), false, 0)
+	sk.Process(conn, Postgres, postgresMessage(postgresQuery, "SELECT 1\x00"), true, 100)
+	sk.Process(conn, Postgres, postgresMessage(postgresReadyForQuery, "I"), false, 200)
+
+	stats := sk.GetAndResetAllStats()
+	require.Len(t, stats, 1)
+	assert.Equal(t, 1, stats[NewKey(saddr, daddr, 1234, 6379, Postgres, "SELECT")].Count)
+	assert.Equal(t, int64(0), sk.decodeErrors)
+}
@@ -0,0 +1,147 @@
+package protocols
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/sketches-go/ddsketch"
+)
+
+// RelativeAccuracy defines the acceptable error in quantile values calculated by DDSketch.
+// For example, if the actual value at p50 is 100, with a relative accuracy of 0.01 the value calculated
+// will be between 99 and 101
+const RelativeAccuracy = 0.01
+
+// Key is an identifier for a group of operations of the same type sent on a connection,
+// such as the SELECT queries of a PostgreSQL connection or the GET commands of a Redis one
+type Key struct {
+	SrcIPHigh uint64
+	SrcIPLow  uint64
+	SrcPort   uint16
+
+	DstIPHigh uint64
+	DstIPLow  uint64
+	DstPort   uint16
+
+	Protocol  Protocol
+	Operation string
+}
+
+// NewKey generates a new Key
+func NewKey(saddr, daddr util.Address, sport, dport uint16, protocol Protocol, operation string) Key {
+	saddrl, saddrh := util.ToLowHigh(saddr)
+	daddrl, daddrh := util.ToLowHigh(daddr)
+	return Key{
+		SrcIPHigh: saddrh,
+		SrcIPLow:  saddrl,
+		SrcPort:   sport,
+		DstIPHigh: daddrh,
+		DstIPLow:  daddrl,
+		DstPort:   dport,
+		Protocol:  protocol,
+		Operation: operation,
+	}
+}
+
+// connKey returns the key of the connection an operation was sent on
+func (k Key) connKey() Key {
+	k.Protocol = Unknown
+	k.Operation = ""
+	return k
+}
+
+// transaction is an operation decoded from a connection, along with its response
+type transaction struct {
+	operation string
+	isError   bool
+
+	requestStarted   uint64
+	responseLastSeen uint64
+}
+
+// latency returns the latency of the operation in nanoseconds
+func (tx *transaction) latency() float64 {
+	return float64(tx.responseLastSeen - tx.requestStarted)
+}
+
+// RequestStats stores the stats of the operations of a given type sent on a connection
+type RequestStats struct {
+	// Note: every time we add a latency value to the DDSketch below, it's possible for the sketch to discard that value
+	// (ie if it is outside the range that is tracked by the sketch). For that reason, in order to keep an accurate count
+	// the number of operations processed, we have our own count field (rather than relying on DDSketch.GetCount())
+	Count      int
+	ErrorCount int
+	Latencies  *ddsketch.DDSketch
+
+	// This field holds the value (in nanoseconds) of the first operation, to avoid
+	// creating sketches with a single value
+	FirstLatencySample float64
+}
+
+// AddRequest takes the latency of an operation and whether it failed and adds it to the stats
+func (r *RequestStats) AddRequest(latency float64, isError bool) {
+	r.Count++
+	if isError {
+		r.ErrorCount++
+	}
+
+	if r.Count == 1 {
+		// We postpone the creation of histograms when we have only one latency sample
+		r.FirstLatencySample = latency
+		return
+	}
+
+	if r.Latencies == nil {
+		if err := r.initSketch(); err != nil {
+			return
+		}
+
+		// Add the defered latency sample
+		if err := r.Latencies.Add(r.FirstLatencySample); err != nil {
+			log.Debugf("could not add operation latency to ddsketch: %v", err)
+		}
+	}
+
+	if err := r.Latencies.Add(latency); err != nil {
+		log.Debugf("could not add operation latency to ddsketch: %v", err)
+	}
+}
+
+// CombineWith merges the data in 2 RequestStats objects
+// newStats is kept as it is, while the method receiver gets mutated
+func (r *RequestStats) CombineWith(newStats RequestStats) {
+	if newStats.Count == 0 {
+		return
+	}
+
+	if newStats.Count == 1 {
+		// The other stats have a single latency sample, so we "manually" add it
+		r.AddRequest(newStats.FirstLatencySample, newStats.ErrorCount > 0)
+		return
+	}
+
+	if r.Latencies == nil {
+		if err := r.initSketch(); err != nil {
+			return
+		}
+
+		if r.Count == 1 {
+			if err := r.Latencies.Add(r.FirstLatencySample); err != nil {
+				log.Debugf("could not add operation latency to ddsketch: %v", err)
+			}
+		}
+	}
+
+	r.Count += newStats.Count
+	r.ErrorCount += newStats.ErrorCount
+	if err := r.Latencies.MergeWith(newStats.Latencies); err != nil {
+		log.Debugf("error merging operation latencies: %v", err)
+	}
+}
+
+func (r *RequestStats) initSketch() (err error) {
+	r.Latencies, err = ddsketch.NewDefaultDDSketch(RelativeAccuracy)
+	if err != nil {
+		log.Debugf("error recording operation latency: could not create new ddsketch: %v", err)
+	}
+	return
+}
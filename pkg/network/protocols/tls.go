@@ -0,0 +1,366 @@
+package protocols
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	tlsRecordHeaderSize    = 5
+	tlsHandshakeHeaderSize = 4
+
+	tlsRecordChangeCipherSpec = 0x14
+	tlsRecordHandshake        = 0x16
+	tlsRecordApplicationData  = 0x17
+
+	tlsClientHello     = 1
+	tlsServerHello     = 2
+	tlsCertificate     = 11
+	tlsServerHelloDone = 14
+
+	tlsExtensionServerName        = 0
+	tlsExtensionALPN              = 16
+	tlsExtensionSupportedVersions = 43
+
+	tlsServerNameTypeHostName = 0
+
+	// maxTLSHandshakeSize bounds the size of the handshake messages we buffer, which is
+	// mostly driven by the size of the certificate chain of the server
+	maxTLSHandshakeSize = maxMessageSize
+)
+
+// tlsHelloRetryRequestRandom is the random of the ServerHello messages which are in fact HelloRetryRequest messages
+var tlsHelloRetryRequestRandom = []byte{
+	0xcf, 0x21, 0xad, 0x74, 0xe5, 0x9a, 0x61, 0x11, 0xbe, 0x1d, 0x8c, 0x02, 0x1e, 0x65, 0xb8, 0x91,
+	0xc2, 0xa2, 0x11, 0x16, 0x7a, 0xbb, 0x8c, 0x5e, 0x07, 0x9e, 0x09, 0xe2, 0xc8, 0xa8, 0x33, 0x9c,
+}
+
+var errTLSHandshakeTooLarge = errors.New("tls handshake message too large")
+
+// TLSMetadata holds the parameters of a TLS connection, as negotiated in its handshake
+type TLSMetadata struct {
+	// Version is the negotiated version, as defined by crypto/tls, such as tls.VersionTLS12
+	Version     uint16
+	CipherSuite uint16
+	// ServerName is the name sent by the client in the SNI extension
+	ServerName string
+	// ClientALPN is the list of application protocols offered by the client
+	ClientALPN []string
+	// ALPN is the application protocol selected by the server. It is only visible up to
+	// TLS 1.2, as TLS 1.3 sends it encrypted.
+	ALPN string
+	// CertNotAfter is the expiry of the certificate of the server, or the zero time if it wasn't
+	// seen. As with the ALPN, the certificate is only visible up to TLS 1.2.
+	CertNotAfter time.Time
+}
+
+// VersionName returns the name of the negotiated version, such as "TLS 1.2"
+func (m *TLSMetadata) VersionName() string {
+	switch m.Version {
+	case 0:
+		return "unknown"
+	case tls.VersionSSL30: //nolint:staticcheck
+		return "SSL 3.0"
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04X", m.Version)
+	}
+}
+
+// CipherSuiteName returns the standard name of the negotiated cipher suite
+func (m *TLSMetadata) CipherSuiteName() string {
+	return tls.CipherSuiteName(m.CipherSuite)
+}
+
+// IsWeak returns whether the connection negotiated a version older than TLS 1.2, or
+// a cipher suite with known security issues
+func (m *TLSMetadata) IsWeak() bool {
+	if m.Version != 0 && m.Version < tls.VersionTLS12 {
+		return true
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.ID == m.CipherSuite {
+			return true
+		}
+	}
+	return false
+}
+
+// tlsHandshake holds the state of one of the endpoints of a TLS handshake
+type tlsHandshake struct {
+	records messageReader
+	// bytes of an incomplete handshake message, which can be split in several records
+	pending []byte
+	// the following records of the endpoint are encrypted
+	done bool
+}
+
+func newTLSHandshake() *tlsHandshake {
+	return &tlsHandshake{
+		records: messageReader{split: splitTLSRecord},
+	}
+}
+
+// tlsDecoder extracts the metadata of a TLS connection from the unencrypted messages of its
+// handshake: the ClientHello, the ServerHello and, up to TLS 1.2, the Certificate of the server
+type tlsDecoder struct {
+	client   *tlsHandshake
+	server   *tlsHandshake
+	metadata TLSMetadata
+}
+
+func newTLSDecoder() *tlsDecoder {
+	return &tlsDecoder{
+		client: newTLSHandshake(),
+		server: newTLSHandshake(),
+	}
+}
+
+// Feed decodes a payload sent either by the client or the server, and returns whether the
+// handshake messages we decode were all received. An error means the state of the handshake
+// was lost, the metadata decoded so far being kept.
+func (d *tlsDecoder) Feed(payload []byte, fromClient bool) (bool, error) {
+	h := d.server
+	if fromClient {
+		h = d.client
+	}
+	if h.done {
+		return d.server.done, nil
+	}
+
+	records, err := h.records.read(payload)
+	if err != nil {
+		return false, err
+	}
+
+	for _, record := range records {
+		switch record[0] {
+		case tlsRecordHandshake:
+			if err := d.handleHandshakeRecord(h, record[tlsRecordHeaderSize:], fromClient); err != nil {
+				return false, err
+			}
+		case tlsRecordChangeCipherSpec, tlsRecordApplicationData:
+			// the following records are encrypted. A client may still send a second ClientHello
+			// after a HelloRetryRequest, but with the same extensions as the first one.
+			h.done = true
+		}
+		if h.done {
+			break
+		}
+	}
+	return d.server.done, nil
+}
+
+func (d *tlsDecoder) handleHandshakeRecord(h *tlsHandshake, fragment []byte, fromClient bool) error {
+	data := fragment
+	if len(h.pending) > 0 {
+		data = append(h.pending, fragment...)
+		h.pending = nil
+	}
+
+	for len(data) >= tlsHandshakeHeaderSize {
+		length := int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+		if length > maxTLSHandshakeSize {
+			return errTLSHandshakeTooLarge
+		}
+		size := tlsHandshakeHeaderSize + length
+		if len(data) < size {
+			break
+		}
+
+		typ, body := data[0], data[tlsHandshakeHeaderSize:size]
+		switch {
+		case fromClient && typ == tlsClientHello:
+			if err := d.parseClientHello(body); err != nil {
+				return err
+			}
+		case !fromClient && typ == tlsServerHello:
+			if err := d.parseServerHello(body); err != nil {
+				return err
+			}
+			// all the following messages are encrypted with TLS 1.3
+			if d.metadata.Version == tls.VersionTLS13 {
+				h.done = true
+				return nil
+			}
+		case !fromClient && typ == tlsCertificate:
+			d.parseCertificate(body)
+		case !fromClient && typ == tlsServerHelloDone:
+			h.done = true
+			return nil
+		}
+		data = data[size:]
+	}
+
+	if len(data) > 0 {
+		h.pending = append([]byte(nil), data...)
+	}
+	return nil
+}
+
+// tlsReader reads the fields of a handshake message, keeping the first error
+type tlsReader struct {
+	data []byte
+	err  error
+}
+
+func (r *tlsReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errInvalidMessage
+		return nil
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *tlsReader) uint8() int {
+	if b := r.bytes(1); b != nil {
+		return int(b[0])
+	}
+	return 0
+}
+
+func (r *tlsReader) uint16() int {
+	if b := r.bytes(2); b != nil {
+		return int(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *tlsReader) uint24() int {
+	if b := r.bytes(3); b != nil {
+		return int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+	}
+	return 0
+}
+
+// extensions returns the extensions of a hello message by type, if any
+func (r *tlsReader) extensions() map[int][]byte {
+	if r.err != nil || len(r.data) == 0 {
+		return nil
+	}
+	ext := &tlsReader{data: r.bytes(r.uint16())}
+	extensions := make(map[int][]byte)
+	for ext.err == nil && len(ext.data) > 0 {
+		typ := ext.uint16()
+		extensions[typ] = ext.bytes(ext.uint16())
+	}
+	if ext.err != nil {
+		r.err = ext.err
+	}
+	return extensions
+}
+
+func (d *tlsDecoder) parseClientHello(body []byte) error {
+	r := &tlsReader{data: body}
+	r.bytes(2)          // legacy version
+	r.bytes(32)         // random
+	r.bytes(r.uint8())  // session ID
+	r.bytes(r.uint16()) // cipher suites
+	r.bytes(r.uint8())  // compression methods
+	extensions := r.extensions()
+	if r.err != nil {
+		return fmt.Errorf("invalid client hello: %w", r.err)
+	}
+
+	if data, ok := extensions[tlsExtensionServerName]; ok {
+		names := &tlsReader{data: data}
+		list := &tlsReader{data: names.bytes(names.uint16())}
+		for list.err == nil && len(list.data) > 0 {
+			typ := list.uint8()
+			name := list.bytes(list.uint16())
+			if list.err == nil && typ == tlsServerNameTypeHostName {
+				d.metadata.ServerName = string(name)
+				break
+			}
+		}
+	}
+	if data, ok := extensions[tlsExtensionALPN]; ok {
+		d.metadata.ClientALPN = parseALPN(data)
+	}
+	return nil
+}
+
+func (d *tlsDecoder) parseServerHello(body []byte) error {
+	r := &tlsReader{data: body}
+	version := r.uint16()
+	random := r.bytes(32)
+	r.bytes(r.uint8()) // session ID
+	cipherSuite := r.uint16()
+	r.uint8() // compression method
+	extensions := r.extensions()
+	if r.err != nil {
+		return fmt.Errorf("invalid server hello: %w", r.err)
+	}
+	if bytes.Equal(random, tlsHelloRetryRequestRandom) {
+		// the client is going to send a new ClientHello, followed by the actual ServerHello
+		return nil
+	}
+
+	// TLS 1.3 is negotiated in an extension, the version of the message being TLS 1.2
+	if data, ok := extensions[tlsExtensionSupportedVersions]; ok && len(data) == 2 {
+		version = int(binary.BigEndian.Uint16(data))
+	}
+	d.metadata.Version = uint16(version)
+	d.metadata.CipherSuite = uint16(cipherSuite)
+	if data, ok := extensions[tlsExtensionALPN]; ok {
+		if protocols := parseALPN(data); len(protocols) > 0 {
+			d.metadata.ALPN = protocols[0]
+		}
+	}
+	return nil
+}
+
+// parseCertificate extracts the expiry of the leaf certificate of a Certificate message
+func (d *tlsDecoder) parseCertificate(body []byte) {
+	r := &tlsReader{data: body}
+	list := &tlsReader{data: r.bytes(r.uint24())}
+	der := list.bytes(list.uint24())
+	if list.err != nil {
+		return
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return
+	}
+	d.metadata.CertNotAfter = cert.NotAfter
+}
+
+func parseALPN(data []byte) []string {
+	r := &tlsReader{data: data}
+	list := &tlsReader{data: r.bytes(r.uint16())}
+	var protocols []string
+	for list.err == nil && len(list.data) > 0 {
+		if protocol := list.bytes(list.uint8()); list.err == nil {
+			protocols = append(protocols, string(protocol))
+		}
+	}
+	return protocols
+}
+
+// splitTLSRecord splits the payloads of a TLS connection into records
+func splitTLSRecord(buf []byte) (int, error) {
+	if len(buf) < tlsRecordHeaderSize {
+		return 0, nil
+	}
+	if buf[0] < tlsRecordChangeCipherSpec || buf[0] > tlsRecordApplicationData || buf[1] != 0x03 {
+		return 0, errInvalidMessage
+	}
+	return tlsRecordHeaderSize + int(binary.BigEndian.Uint16(buf[3:])), nil
+}
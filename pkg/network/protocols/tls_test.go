@@ -0,0 +1,204 @@
+package protocols
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCertNotAfter = time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// tlsPayload is a payload sent during a recorded TLS handshake
+type tlsPayload struct {
+	data       []byte
+	fromClient bool
+}
+
+// recordingConn records the payloads written to a connection
+type recordingConn struct {
+	net.Conn
+	fromClient bool
+
+	mu       *sync.Mutex
+	payloads *[]tlsPayload
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	*c.payloads = append(*c.payloads, tlsPayload{data: append([]byte(nil), b...), fromClient: c.fromClient})
+	c.mu.Unlock()
+	return c.Conn.Write(b)
+}
+
+func newTestCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     testCertNotAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// recordTLSHandshake runs a handshake between a client and a server, and returns the
+// payloads they exchanged, in order
+func recordTLSHandshake(t *testing.T, clientConfig, serverConfig *tls.Config) []tlsPayload {
+	var mu sync.Mutex
+	var payloads []tlsPayload
+
+	clientConn, serverConn := net.Pipe()
+	client := tls.Client(&recordingConn{Conn: clientConn, fromClient: true, mu: &mu, payloads: &payloads}, clientConfig)
+	server := tls.Server(&recordingConn{Conn: serverConn, mu: &mu, payloads: &payloads}, serverConfig)
+	// closing the TLS connections would wait for their close_notify alerts to be read
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Handshake()
+	}()
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-errs)
+	return payloads
+}
+
+func newTestTLSConfigs(t *testing.T, version uint16) (*tls.Config, *tls.Config) {
+	clientConfig := &tls.Config{
+		ServerName:         "example.com",
+		NextProtos:         []string{"h2", "http/1.1"},
+		InsecureSkipVerify: true,
+		MinVersion:         version,
+		MaxVersion:         version,
+	}
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{newTestCertificate(t)},
+		NextProtos:   []string{"http/1.1"},
+		MinVersion:   version,
+		MaxVersion:   version,
+	}
+	return clientConfig, serverConfig
+}
+
+func TestTLSDecoderTLS12(t *testing.T) {
+	clientConfig, serverConfig := newTestTLSConfigs(t, tls.VersionTLS12)
+	serverConfig.CipherSuites = []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}
+
+	d := newTLSDecoder()
+	done := false
+	for _, p := range recordTLSHandshake(t, clientConfig, serverConfig) {
+		var err error
+		done, err = d.Feed(p.data, p.fromClient)
+		require.NoError(t, err)
+	}
+	assert.True(t, done)
+
+	assert.Equal(t, TLSMetadata{
+		Version:      tls.VersionTLS12,
+		CipherSuite:  tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		ServerName:   "example.com",
+		ClientALPN:   []string{"h2", "http/1.1"},
+		ALPN:         "http/1.1",
+		CertNotAfter: testCertNotAfter,
+	}, d.metadata)
+	assert.Equal(t, "TLS 1.2", d.metadata.VersionName())
+	assert.Equal(t, "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", d.metadata.CipherSuiteName())
+	assert.False(t, d.metadata.IsWeak())
+}
+
+func TestTLSDecoderTLS13(t *testing.T) {
+	clientConfig, serverConfig := newTestTLSConfigs(t, tls.VersionTLS13)
+
+	d := newTLSDecoder()
+	done := false
+	for _, p := range recordTLSHandshake(t, clientConfig, serverConfig) {
+		var err error
+		done, err = d.Feed(p.data, p.fromClient)
+		require.NoError(t, err)
+	}
+	assert.True(t, done)
+
+	// the negotiated ALPN and the certificate are encrypted
+	assert.Equal(t, uint16(tls.VersionTLS13), d.metadata.Version)
+	assert.Equal(t, "TLS 1.3", d.metadata.VersionName())
+	assert.NotZero(t, d.metadata.CipherSuite)
+	assert.Equal(t, "example.com", d.metadata.ServerName)
+	assert.Equal(t, []string{"h2", "http/1.1"}, d.metadata.ClientALPN)
+	assert.Empty(t, d.metadata.ALPN)
+	assert.True(t, d.metadata.CertNotAfter.IsZero())
+}
+
+func TestTLSDecoderSplitPayloads(t *testing.T) {
+	clientConfig, serverConfig := newTestTLSConfigs(t, tls.VersionTLS12)
+
+	d := newTLSDecoder()
+	for _, p := range recordTLSHandshake(t, clientConfig, serverConfig) {
+		for i := range p.data {
+			_, err := d.Feed(p.data[i:i+1], p.fromClient)
+			require.NoError(t, err)
+		}
+	}
+	assert.Equal(t, "example.com", d.metadata.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS12), d.metadata.Version)
+	assert.Equal(t, testCertNotAfter, d.metadata.CertNotAfter)
+}
+
+func TestTLSDecoderInvalidRecord(t *testing.T) {
+	d := newTLSDecoder()
+	_, err := d.Feed([]byte("GET / HTTP/1.1\r\n\r\n"), true)
+	assert.Error(t, err)
+}
+
+func TestTLSMetadataIsWeak(t *testing.T) {
+	for _, tc := range []struct {
+		metadata TLSMetadata
+		weak     bool
+	}{
+		{TLSMetadata{Version: tls.VersionTLS10, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA}, true},
+		{TLSMetadata{Version: tls.VersionTLS12, CipherSuite: tls.TLS_RSA_WITH_RC4_128_SHA}, true},
+		{TLSMetadata{Version: tls.VersionTLS12, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, false},
+		{TLSMetadata{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256}, false},
+	} {
+		assert.Equal(t, tc.weak, tc.metadata.IsWeak(), "%s %s", tc.metadata.VersionName(), tc.metadata.CipherSuiteName())
+	}
+}
+
+func TestStatKeeperTLS(t *testing.T) {
+	clientConfig, serverConfig := newTestTLSConfigs(t, tls.VersionTLS12)
+	sk := NewStatKeeper(1000)
+	conn := NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 1234, 443, Unknown, "")
+
+	assert.Nil(t, sk.GetTLSMetadata(conn))
+	for _, p := range recordTLSHandshake(t, clientConfig, serverConfig) {
+		sk.Process(conn, TLS, p.data, p.fromClient, 0)
+	}
+	// encrypted application data is ignored
+	sk.Process(conn, TLS, []byte{tlsRecordApplicationData, 3, 3, 0, 1, 0}, true, 0)
+
+	metadata := sk.GetTLSMetadata(conn)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "example.com", metadata.ServerName)
+	assert.Equal(t, "http/1.1", metadata.ALPN)
+	assert.Equal(t, int64(0), sk.decodeErrors)
+	assert.Empty(t, sk.GetAndResetAllStats())
+
+	sk.CloseConn(conn)
+	assert.Nil(t, sk.GetTLSMetadata(conn))
+}
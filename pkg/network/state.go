@@ -7,6 +7,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"go4.org/intern"
@@ -40,6 +41,9 @@ type State interface {
 		latestConns []ConnectionStats,
 		dns dns.StatsByKeyByNameByType,
 		http map[http.Key]http.RequestStats,
+		grpc map[http.Key]http.GRPCStats,
+		protocolStats map[protocols.Key]protocols.RequestStats,
+		resolverStats map[string]dns.Stats,
 	) Delta
 
 	// StoreClosedConnection stores a new closed connection
@@ -65,16 +69,21 @@ type State interface {
 type Delta struct {
 	Connections []ConnectionStats
 	HTTP        map[http.Key]http.RequestStats
+	GRPC        map[http.Key]http.GRPCStats
+	Protocols   map[protocols.Key]protocols.RequestStats
+	// DNSResolvers holds the stats of the lookups answered by each resolver since the last call, keyed by IP
+	DNSResolvers map[string]dns.Stats
 }
 
 type telemetry struct {
-	closedConnDropped  int64
-	connDropped        int64
-	statsResets        int64
-	timeSyncCollisions int64
-	dnsStatsDropped    int64
-	httpStatsDropped   int64
-	dnsPidCollisions   int64
+	closedConnDropped    int64
+	connDropped          int64
+	statsResets          int64
+	timeSyncCollisions   int64
+	dnsStatsDropped      int64
+	httpStatsDropped     int64
+	protocolStatsDropped int64
+	dnsPidCollisions     int64
 }
 
 type stats struct {
@@ -95,6 +104,10 @@ type client struct {
 	// maps by dns key the domain (string) to stats structure
 	dnsStats       dns.StatsByKeyByNameByType
 	httpStatsDelta map[http.Key]http.RequestStats
+	grpcStatsDelta map[http.Key]http.GRPCStats
+	protocolStats  map[protocols.Key]protocols.RequestStats
+	// resolverStats holds the cumulative stats of each resolver at the last call, the deltas being computed from them
+	resolverStats map[string]dns.Stats
 }
 
 type networkState struct {
@@ -152,6 +165,9 @@ func (ns *networkState) GetDelta(
 	latestConns []ConnectionStats,
 	dnsStats dns.StatsByKeyByNameByType,
 	httpStats map[http.Key]http.RequestStats,
+	grpcStats map[http.Key]http.GRPCStats,
+	protocolStats map[protocols.Key]protocols.RequestStats,
+	resolverStats map[string]dns.Stats,
 ) Delta {
 	ns.Lock()
 	defer ns.Unlock()
@@ -184,13 +200,22 @@ func (ns *networkState) GetDelta(
 		if len(httpStats) > 0 {
 			ns.storeHTTPStats(httpStats)
 		}
+		if len(grpcStats) > 0 {
+			ns.storeGRPCStats(grpcStats)
+		}
+		if len(protocolStats) > 0 {
+			ns.storeProtocolStats(protocolStats)
+		}
 
 		// copy to ensure return value doesn't get clobbered
 		conns := make([]ConnectionStats, len(latestConns))
 		copy(conns, latestConns)
 		return Delta{
-			Connections: conns,
-			HTTP:        ns.getHTTPDelta(id),
+			Connections:  conns,
+			HTTP:         ns.getHTTPDelta(id),
+			GRPC:         ns.getGRPCDelta(id),
+			Protocols:    ns.getProtocolDelta(id),
+			DNSResolvers: ns.getResolverDelta(id, resolverStats),
 		}
 	}
 
@@ -218,10 +243,19 @@ func (ns *networkState) GetDelta(
 	if len(httpStats) > 0 {
 		ns.storeHTTPStats(httpStats)
 	}
+	if len(grpcStats) > 0 {
+		ns.storeGRPCStats(grpcStats)
+	}
+	if len(protocolStats) > 0 {
+		ns.storeProtocolStats(protocolStats)
+	}
 
 	return Delta{
-		Connections: conns,
-		HTTP:        ns.getHTTPDelta(id),
+		Connections:  conns,
+		HTTP:         ns.getHTTPDelta(id),
+		GRPC:         ns.getGRPCDelta(id),
+		Protocols:    ns.getProtocolDelta(id),
+		DNSResolvers: ns.getResolverDelta(id, resolverStats),
 	}
 }
 
@@ -270,6 +304,7 @@ func (ns *networkState) addDNSStats(id string, conns []ConnectionStats) {
 						ds.Timeouts = dnsStats.Timeouts
 						ds.SuccessLatencySum = dnsStats.SuccessLatencySum
 						ds.FailureLatencySum = dnsStats.FailureLatencySum
+						ds.MergeTTLs(dnsStats)
 						ds.CountByRcode = make(map[uint32]uint32)
 						for rcode, count := range dnsStats.CountByRcode {
 							ds.CountByRcode[rcode] = count
@@ -382,6 +417,7 @@ func (ns *networkState) storeDNSStats(stats dns.StatsByKeyByNameByType) {
 						prev.Timeouts += dnsStats.Timeouts
 						prev.SuccessLatencySum += dnsStats.SuccessLatencySum
 						prev.FailureLatencySum += dnsStats.FailureLatencySum
+						prev.MergeTTLs(dnsStats)
 						for rcode, count := range dnsStats.CountByRcode {
 							prev.CountByRcode[rcode] += count
 						}
@@ -422,6 +458,86 @@ func (ns *networkState) getHTTPDelta(clientID string) map[http.Key]http.RequestS
 	return delta
 }
 
+// storeGRPCStats stores latest gRPC stats for all clients
+func (ns *networkState) storeGRPCStats(allStats map[http.Key]http.GRPCStats) {
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.grpcStatsDelta[key]
+			if !ok && len(client.grpcStatsDelta) >= ns.maxHTTPStats {
+				ns.telemetry.httpStatsDropped++
+				continue
+			}
+
+			prevStats.CombineWith(stats)
+			client.grpcStatsDelta[key] = prevStats
+		}
+	}
+}
+
+func (ns *networkState) getGRPCDelta(clientID string) map[http.Key]http.GRPCStats {
+	delta := ns.clients[clientID].grpcStatsDelta
+	ns.clients[clientID].grpcStatsDelta = make(map[http.Key]http.GRPCStats)
+	return delta
+}
+
+// storeProtocolStats stores latest protocol operation stats for all clients
+func (ns *networkState) storeProtocolStats(allStats map[protocols.Key]protocols.RequestStats) {
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.protocolStats[key]
+			if !ok && len(client.protocolStats) >= ns.maxHTTPStats {
+				ns.telemetry.protocolStatsDropped++
+				continue
+			}
+
+			prevStats.CombineWith(stats)
+			client.protocolStats[key] = prevStats
+		}
+	}
+}
+
+func (ns *networkState) getProtocolDelta(clientID string) map[protocols.Key]protocols.RequestStats {
+	delta := ns.clients[clientID].protocolStats
+	ns.clients[clientID].protocolStats = make(map[protocols.Key]protocols.RequestStats)
+	return delta
+}
+
+// getResolverDelta returns the difference between the given cumulative resolver stats and the ones
+// of the last call for the client, omitting the resolvers which didn't answer any lookup since then
+func (ns *networkState) getResolverDelta(clientID string, resolverStats map[string]dns.Stats) map[string]dns.Stats {
+	client := ns.clients[clientID]
+	last := client.resolverStats
+	client.resolverStats = resolverStats
+	if len(resolverStats) == 0 {
+		return nil
+	}
+
+	delta := make(map[string]dns.Stats)
+	for resolver, stats := range resolverStats {
+		prev, ok := last[resolver]
+		if !ok {
+			delta[resolver] = stats
+			continue
+		}
+
+		d := dns.Stats{
+			Timeouts:          stats.Timeouts - prev.Timeouts,
+			SuccessLatencySum: stats.SuccessLatencySum - prev.SuccessLatencySum,
+			FailureLatencySum: stats.FailureLatencySum - prev.FailureLatencySum,
+			CountByRcode:      make(map[uint32]uint32),
+		}
+		for rcode, count := range stats.CountByRcode {
+			if count > prev.CountByRcode[rcode] {
+				d.CountByRcode[rcode] = count - prev.CountByRcode[rcode]
+			}
+		}
+		if d.Timeouts > 0 || len(d.CountByRcode) > 0 {
+			delta[resolver] = d
+		}
+	}
+	return delta
+}
+
 // newClient creates a new client and returns true if the given client already exists
 func (ns *networkState) newClient(clientID string) (*client, bool) {
 	if c, ok := ns.clients[clientID]; ok {
@@ -434,6 +550,8 @@ func (ns *networkState) newClient(clientID string) (*client, bool) {
 		closedConnections: map[string]ConnectionStats{},
 		dnsStats:          dns.StatsByKeyByNameByType{},
 		httpStatsDelta:    map[http.Key]http.RequestStats{},
+		grpcStatsDelta:    map[http.Key]http.GRPCStats{},
+		protocolStats:     map[protocols.Key]protocols.RequestStats{},
 	}
 	ns.clients[clientID] = c
 	return c, false
@@ -632,6 +750,7 @@ func (ns *networkState) RemoveConnections(keys []string) {
 		s += " [%d closed connections dropped]"
 		s += " [%d dns stats dropped]"
 		s += " [%d HTTP stats dropped]"
+		s += " [%d protocol stats dropped]"
 		s += " [%d DNS pid collisions]"
 		s += " [%d time sync collisions]"
 		log.Warnf(s,
@@ -640,6 +759,7 @@ func (ns *networkState) RemoveConnections(keys []string) {
 			ns.telemetry.closedConnDropped,
 			ns.telemetry.dnsStatsDropped,
 			ns.telemetry.httpStatsDropped,
+			ns.telemetry.protocolStatsDropped,
 			ns.telemetry.dnsPidCollisions,
 			ns.telemetry.timeSyncCollisions)
 	}
@@ -664,13 +784,14 @@ func (ns *networkState) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"clients": clientInfo,
 		"telemetry": map[string]int64{
-			"stats_resets":         ns.telemetry.statsResets,
-			"closed_conn_dropped":  ns.telemetry.closedConnDropped,
-			"conn_dropped":         ns.telemetry.connDropped,
-			"time_sync_collisions": ns.telemetry.timeSyncCollisions,
-			"dns_stats_dropped":    ns.telemetry.dnsStatsDropped,
-			"http_stats_dropped":   ns.telemetry.httpStatsDropped,
-			"dns_pid_collisions":   ns.telemetry.dnsPidCollisions,
+			"stats_resets":           ns.telemetry.statsResets,
+			"closed_conn_dropped":    ns.telemetry.closedConnDropped,
+			"conn_dropped":           ns.telemetry.connDropped,
+			"time_sync_collisions":   ns.telemetry.timeSyncCollisions,
+			"dns_stats_dropped":      ns.telemetry.dnsStatsDropped,
+			"http_stats_dropped":     ns.telemetry.httpStatsDropped,
+			"protocol_stats_dropped": ns.telemetry.protocolStatsDropped,
+			"dns_pid_collisions":     ns.telemetry.dnsPidCollisions,
 		},
 		"current_time":       time.Now().Unix(),
 		"latest_bpf_time_ns": ns.latestTimeEpoch,
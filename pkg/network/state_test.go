@@ -12,6 +12,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"go4.org/intern"
 
@@ -39,7 +40,7 @@ func BenchmarkStoreClosedConnection(b *testing.B) {
 	} {
 		b.Run(fmt.Sprintf("StoreClosedConnection-%d", bench.connCount), func(b *testing.B) {
 			ns := newDefaultState()
-			ns.GetDelta(DEBUGCLIENT, latestEpochTime(), nil, nil, nil) // Initial fetch to set up client
+			ns.GetDelta(DEBUGCLIENT, latestEpochTime(), nil, nil, nil, nil, nil, nil) // Initial fetch to set up client
 
 			b.ResetTimer()
 			b.ReportAllocs()
@@ -114,7 +115,7 @@ func BenchmarkConnectionsGet(b *testing.B) {
 			ns := newDefaultState()
 
 			// Initial fetch to set up client
-			ns.GetDelta(DEBUGCLIENT, latestTime, nil, nil, nil)
+			ns.GetDelta(DEBUGCLIENT, latestTime, nil, nil, nil, nil, nil, nil)
 
 			for _, c := range closed[:bench.closedCount] {
 				ns.StoreClosedConnection(&c)
@@ -124,7 +125,7 @@ func BenchmarkConnectionsGet(b *testing.B) {
 			b.ReportAllocs()
 
 			for n := 0; n < b.N; n++ {
-				ns.GetDelta(DEBUGCLIENT, latestTime, conns[:bench.connCount], nil, nil)
+				ns.GetDelta(DEBUGCLIENT, latestTime, conns[:bench.connCount], nil, nil, nil, nil, nil)
 			}
 		})
 	}
@@ -154,10 +155,10 @@ func TestRemoveConnections(t *testing.T) {
 
 	clientID := "1"
 	state := newDefaultState().(*networkState)
-	conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+	conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 0, len(conns))
 
-	conns = state.GetDelta(clientID, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+	conns = state.GetDelta(clientID, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, conn, conns[0])
 
@@ -191,7 +192,7 @@ func TestRetrieveClosedConnection(t *testing.T) {
 	t.Run("without prior registration", func(t *testing.T) {
 		state := newDefaultState()
 		state.StoreClosedConnection(&conn)
-		conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 
 		assert.Equal(t, 0, len(conns))
 	})
@@ -199,21 +200,21 @@ func TestRetrieveClosedConnection(t *testing.T) {
 	t.Run("with registration", func(t *testing.T) {
 		state := newDefaultState()
 
-		conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		state.StoreClosedConnection(&conn)
 
-		conns = state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, conn, conns[0])
 
 		// An other client that is not registered should not have the closed connection
-		conns = state.GetDelta("2", latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta("2", latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// It should no more have connections stored
-		conns = state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 	})
 }
@@ -225,7 +226,7 @@ func TestCleanupClient(t *testing.T) {
 	clients := state.(*networkState).getClients()
 	assert.Equal(t, 0, len(clients))
 
-	conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+	conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 0, len(conns))
 
 	// Should be a no op
@@ -275,15 +276,15 @@ func TestLastStats(t *testing.T) {
 	conn3.MonotonicRetransmits += dRetransmits
 
 	// First get, we should not have any connections stored
-	conns := state.GetDelta(client1, latestEpochTime(), nil, nil, nil).Connections
+	conns := state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 0, len(conns))
 
 	// Same for an other client
-	conns = state.GetDelta(client2, latestEpochTime(), nil, nil, nil).Connections
+	conns = state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 0, len(conns))
 
 	// We should have only one connection but with last stats equal to monotonic
-	conns = state.GetDelta(client1, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+	conns = state.GetDelta(client1, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, conn.MonotonicSentBytes, conns[0].LastSentBytes)
 	assert.Equal(t, conn.MonotonicRecvBytes, conns[0].LastRecvBytes)
@@ -293,7 +294,7 @@ func TestLastStats(t *testing.T) {
 	assert.Equal(t, conn.MonotonicRetransmits, conns[0].MonotonicRetransmits)
 
 	// This client didn't collect the first connection so last stats = monotonic
-	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{conn2}, nil, nil).Connections
+	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{conn2}, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, conn2.MonotonicSentBytes, conns[0].LastSentBytes)
 	assert.Equal(t, conn2.MonotonicRecvBytes, conns[0].LastRecvBytes)
@@ -303,7 +304,7 @@ func TestLastStats(t *testing.T) {
 	assert.Equal(t, conn2.MonotonicRetransmits, conns[0].MonotonicRetransmits)
 
 	// client 1 should have conn3 - conn1 since it did not collected conn2
-	conns = state.GetDelta(client1, latestEpochTime(), []ConnectionStats{conn3}, nil, nil).Connections
+	conns = state.GetDelta(client1, latestEpochTime(), []ConnectionStats{conn3}, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, 2*dSent, conns[0].LastSentBytes)
 	assert.Equal(t, 2*dRecv, conns[0].LastRecvBytes)
@@ -313,7 +314,7 @@ func TestLastStats(t *testing.T) {
 	assert.Equal(t, conn3.MonotonicRetransmits, conns[0].MonotonicRetransmits)
 
 	// client 2 should have conn3 - conn2
-	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{conn3}, nil, nil).Connections
+	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{conn3}, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, dSent, conns[0].LastSentBytes)
 	assert.Equal(t, dRecv, conns[0].LastRecvBytes)
@@ -350,11 +351,11 @@ func TestLastStatsForClosedConnection(t *testing.T) {
 	conn2.MonotonicRetransmits += dRetransmits
 
 	// First get, we should not have any connections stored
-	conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+	conns := state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 0, len(conns))
 
 	// We should have one connection with last stats equal to monotonic stats
-	conns = state.GetDelta(clientID, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+	conns = state.GetDelta(clientID, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, conn.MonotonicSentBytes, conns[0].LastSentBytes)
 	assert.Equal(t, conn.MonotonicRecvBytes, conns[0].LastRecvBytes)
@@ -366,7 +367,7 @@ func TestLastStatsForClosedConnection(t *testing.T) {
 	state.StoreClosedConnection(&conn2)
 
 	// We should have one connection with last stats
-	conns = state.GetDelta(clientID, latestEpochTime(), nil, nil, nil).Connections
+	conns = state.GetDelta(clientID, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 
 	assert.Equal(t, 1, len(conns))
 	assert.Equal(t, dSent, conns[0].LastSentBytes)
@@ -417,7 +418,7 @@ func TestRaceConditions(t *testing.T) {
 				case <-timer.C:
 					return
 				default:
-					state.GetDelta(c, latestEpochTime(), genConns(nConns), nil, nil)
+					state.GetDelta(c, latestEpochTime(), genConns(nConns), nil, nil, nil, nil, nil)
 				}
 			}
 		}(fmt.Sprintf("%d", i))
@@ -456,14 +457,14 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Store the connection as closed
 		state.StoreClosedConnection(&conn)
 
 		// Second get, we should have monotonic and last stats = 3
-		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
@@ -485,7 +486,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Store the connection as closed
@@ -498,7 +499,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state.StoreClosedConnection(&conn2)
 
 		// Second get, we should have monotonic and last stats = 8
-		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 8, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 8, int(conns[0].LastSentBytes))
@@ -522,7 +523,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get for client c, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Len(t, conns, 0)
 
 		conn := ConnectionStats{
@@ -537,7 +538,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		}
 
 		// Simulate this connection starting
-		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 		require.Len(t, conns, 1)
 		assert.EqualValues(t, 1, conns[0].LastSentBytes)
 		assert.EqualValues(t, 1, conns[0].MonotonicSentBytes)
@@ -550,7 +551,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		conn.MonotonicSentBytes = 1
 		conn.LastUpdateEpoch = latestEpochTime()
 		// Retrieve the connections
-		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 		require.Len(t, conns, 1)
 		assert.EqualValues(t, 2, conns[0].LastSentBytes)
 		assert.EqualValues(t, 3, conns[0].MonotonicSentBytes)
@@ -560,7 +561,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		// Store the connection as closed
 		state.StoreClosedConnection(&conn)
 
-		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		require.Len(t, conns, 1)
 		assert.EqualValues(t, 1, conns[0].LastSentBytes)
 		assert.EqualValues(t, 2, conns[0].MonotonicSentBytes)
@@ -584,7 +585,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Store the connection as closed
@@ -597,7 +598,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs := []ConnectionStats{conn2}
 
 		// Second get, we should have monotonic and last stats = 5
-		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		require.Equal(t, 1, len(conns))
 		assert.Equal(t, 5, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 5, int(conns[0].LastSentBytes))
@@ -614,7 +615,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs = []ConnectionStats{conn3}
 
 		// Third get, we should have monotonic = 6 and last stats = 4
-		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 6, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 4, int(conns[0].LastSentBytes))
@@ -624,7 +625,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state.StoreClosedConnection(&conn3)
 
 		// 4th get, we should have monotonic = 3 and last stats = 2
-		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 2, int(conns[0].LastSentBytes))
@@ -646,14 +647,14 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// this is to register we should not have anything
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Store the connection as opened
 		cs := []ConnectionStats{conn}
 
 		// First get, we should have monotonic = 3 and last seen = 3
-		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
@@ -664,7 +665,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state.StoreClosedConnection(&conn2)
 
 		// Second get, we should have monotonic = 8 and last stats = 5
-		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 8, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 5, int(conns[0].LastSentBytes))
@@ -704,18 +705,18 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get for client c, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// First get for client d, we should have nothing
-		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Store the connection as closed
 		state.StoreClosedConnection(&conn)
 
 		// Second get for client d we should have monotonic and last stats = 3
-		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
@@ -727,7 +728,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs := []ConnectionStats{conn2}
 
 		// Second get, for client c we should have monotonic and last stats = 5
-		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 5, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 5, int(conns[0].LastSentBytes))
@@ -738,7 +739,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs = []ConnectionStats{conn2}
 
 		// Third get, for client d we should have monotonic = 3 and last stats = 3
-		conns = state.GetDelta(clientD, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
@@ -755,7 +756,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs = []ConnectionStats{conn3}
 
 		// Third get, for client c, we should have monotonic = 6 and last stats = 4
-		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 6, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 4, int(conns[0].LastSentBytes))
@@ -766,7 +767,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs = []ConnectionStats{conn3}
 
 		// 4th get, for client d, we should have monotonic = 7 and last stats = 4
-		conns = state.GetDelta(clientD, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 7, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 4, int(conns[0].LastSentBytes))
@@ -777,13 +778,13 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state.StoreClosedConnection(&conn3)
 
 		// 4th get, for client c we should have monotonic = 3 and last stats = 2
-		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 2, int(conns[0].LastSentBytes))
 
 		// 5th get, for client d we should have monotonic = 3 and last stats = 1
-		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 1, int(conns[0].LastSentBytes))
@@ -831,15 +832,15 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get for client c, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// First get for client d, we should have nothing
-		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// First get for client e, we should have nothing
-		conns = state.GetDelta(clientE, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientE, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Store the connection
@@ -848,7 +849,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs := []ConnectionStats{conn}
 
 		// Second get for client e we should have monotonic and last stats = 2
-		conns = state.GetDelta(clientE, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(clientE, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 2, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 2, int(conns[0].LastSentBytes))
@@ -859,13 +860,13 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state.StoreClosedConnection(&conn)
 
 		// Second get for client d we should have monotonic and last stats = 3
-		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
 
 		// Third get for client e we should have monotonic = 3and last stats = 1
-		conns = state.GetDelta(clientE, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientE, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 1, int(conns[0].LastSentBytes))
@@ -877,7 +878,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs = []ConnectionStats{conn2}
 
 		// Second get, for client c we should have monotonic and last stats = 5
-		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 5, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 5, int(conns[0].LastSentBytes))
@@ -888,7 +889,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		cs = []ConnectionStats{conn2}
 
 		// Third get, for client d we should have monotonic = 3 and last stats = 3
-		conns = state.GetDelta(clientD, latestEpochTime(), cs, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), cs, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
@@ -899,7 +900,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state.StoreClosedConnection(&conn2)
 
 		// 4th get, for client e we should have monotonic = 5 and last stats = 5
-		conns = state.GetDelta(clientE, latestEpochTime(), nil, nil, nil).Connections
+		conns = state.GetDelta(clientE, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 1, len(conns))
 		assert.Equal(t, 5, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 5, int(conns[0].LastSentBytes))
@@ -935,11 +936,11 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		state := newDefaultState()
 
 		// First get for client c, we should have nothing
-		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+		conns := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 		assert.Equal(t, 0, len(conns))
 
 		// Second get for client c we should have monotonic and last stats = 3
-		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 		assert.Len(t, conns, 1)
 		assert.Equal(t, 3, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 3, int(conns[0].LastSentBytes))
@@ -949,7 +950,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		conn2.LastUpdateEpoch++
 
 		// First get for client d we should have monotonic = 4 and last bytes = 4
-		conns = state.GetDelta(clientD, latestEpochTime(), []ConnectionStats{conn2}, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), []ConnectionStats{conn2}, nil, nil, nil, nil, nil).Connections
 		assert.Len(t, conns, 1)
 		assert.Equal(t, 4, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 0, int(conns[0].LastSentBytes))
@@ -959,7 +960,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		conn3.LastUpdateEpoch++
 
 		// Third get for client c we should have monotonic = 7 and last bytes = 4
-		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn3}, nil, nil).Connections
+		conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn3}, nil, nil, nil, nil, nil).Connections
 		assert.Len(t, conns, 1)
 		assert.Equal(t, 7, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 4, int(conns[0].LastSentBytes))
@@ -969,7 +970,7 @@ func TestSameKeyEdgeCases(t *testing.T) {
 		conn4.LastUpdateEpoch++
 
 		// Second get for client d we should have monotonic = 9 and last bytes = 5
-		conns = state.GetDelta(clientD, latestEpochTime(), []ConnectionStats{conn4}, nil, nil).Connections
+		conns = state.GetDelta(clientD, latestEpochTime(), []ConnectionStats{conn4}, nil, nil, nil, nil, nil).Connections
 		assert.Len(t, conns, 1)
 		assert.Equal(t, 9, int(conns[0].MonotonicSentBytes))
 		assert.Equal(t, 5, int(conns[0].LastSentBytes))
@@ -992,10 +993,10 @@ func TestStatsResetOnUnderflow(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the client
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	// Get the connections once to register stats
-	conns := state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+	conns := state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 
 	// Expect LastStats to be 3
@@ -1005,7 +1006,7 @@ func TestStatsResetOnUnderflow(t *testing.T) {
 	// Get the connections again but by simulating an underflow
 	conn.MonotonicSentBytes--
 
-	conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+	conns = state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	expected := conn
 	expected.LastSentBytes = 2
@@ -1035,8 +1036,8 @@ func TestDoubleCloseOnTwoClients(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the clients
-	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil).Connections, 0)
-	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	// Store the closed connection twice
 	state.StoreClosedConnection(&conn)
@@ -1045,12 +1046,12 @@ func TestDoubleCloseOnTwoClients(t *testing.T) {
 
 	expectedConn.LastUpdateEpoch = conn.LastUpdateEpoch
 	// Get the connections for client1 we should have only one with stats = 2*conn
-	conns := state.GetDelta(client1, latestEpochTime(), nil, nil, nil).Connections
+	conns := state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	assert.Equal(t, expectedConn, conns[0])
 
 	// Same for client2
-	conns = state.GetDelta(client2, latestEpochTime(), nil, nil, nil).Connections
+	conns = state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	assert.Equal(t, expectedConn, conns[0])
 }
@@ -1069,7 +1070,7 @@ func TestUnorderedCloseEvent(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the client
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	// Simulate storing a closed connection while we were reading from the eBPF map
 	// in this case the closed conn will have an earlier epoch
@@ -1081,22 +1082,22 @@ func TestUnorderedCloseEvent(t *testing.T) {
 	conn.LastUpdateEpoch--
 	conn.MonotonicSentBytes--
 	conn.MonotonicRecvBytes = 0
-	conns := state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil).Connections
+	conns := state.GetDelta(client, latestEpochTime(), []ConnectionStats{conn}, nil, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	assert.EqualValues(t, 4, conns[0].LastSentBytes)
 	assert.EqualValues(t, 1, conns[0].LastRecvBytes)
 
 	// Simulate some other gets
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	// Simulate having the connection getting active again
 	conn.LastUpdateEpoch = latestEpochTime()
 	conn.MonotonicSentBytes--
 	state.StoreClosedConnection(&conn)
 
-	conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections
+	conns = state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	assert.EqualValues(t, 2, conns[0].LastSentBytes)
 	assert.EqualValues(t, 0, conns[0].LastRecvBytes)
@@ -1104,7 +1105,7 @@ func TestUnorderedCloseEvent(t *testing.T) {
 	// Ensure we don't have underflows / unordered conns
 	assert.Zero(t, state.(*networkState).telemetry.statsResets)
 
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 }
 
 func TestAggregateClosedConnectionsTimestamp(t *testing.T) {
@@ -1121,7 +1122,7 @@ func TestAggregateClosedConnectionsTimestamp(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the client
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	conn.LastUpdateEpoch = latestEpochTime()
 	state.StoreClosedConnection(&conn)
@@ -1133,7 +1134,7 @@ func TestAggregateClosedConnectionsTimestamp(t *testing.T) {
 	state.StoreClosedConnection(&conn)
 
 	// Make sure the connections we get has the latest timestamp
-	delta := state.GetDelta(client, latestEpochTime(), nil, nil, nil)
+	delta := state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil)
 	assert.Equal(t, conn.LastUpdateEpoch, delta.Connections[0].LastUpdateEpoch)
 }
 
@@ -1168,23 +1169,23 @@ func TestDNSStatsWithMultipleClients(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the first two clients
-	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil).Connections, 0)
-	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	c.LastUpdateEpoch = latestEpochTime()
 	state.StoreClosedConnection(&c)
 
-	conns := state.GetDelta(client1, latestEpochTime(), nil, getStats(), nil).Connections
+	conns := state.GetDelta(client1, latestEpochTime(), nil, getStats(), nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	assert.EqualValues(t, 1, conns[0].DNSSuccessfulResponses)
 
 	// Register the third client but also pass in dns stats
-	conns = state.GetDelta(client3, latestEpochTime(), []ConnectionStats{c}, getStats(), nil).Connections
+	conns = state.GetDelta(client3, latestEpochTime(), []ConnectionStats{c}, getStats(), nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	// DNS stats should be available for the new client
 	assert.EqualValues(t, 1, conns[0].DNSSuccessfulResponses)
 
-	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{c}, getStats(), nil).Connections
+	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{c}, getStats(), nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	// 2nd client should get accumulated stats
 	assert.EqualValues(t, 3, conns[0].DNSSuccessfulResponses)
@@ -1220,27 +1221,27 @@ func TestDNSStatsWithMultipleClientsWithDomainCollectionEnabled(t *testing.T) {
 	state := NewState(2*time.Minute, 50000, 75000, 75000, 7500, true)
 
 	// Register the first two clients
-	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil).Connections, 0)
-	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	c.LastUpdateEpoch = latestEpochTime()
 	state.StoreClosedConnection(&c)
 
-	conns := state.GetDelta(client1, latestEpochTime(), nil, getStats(), nil).Connections
+	conns := state.GetDelta(client1, latestEpochTime(), nil, getStats(), nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	assert.EqualValues(t, 1, conns[0].DNSStatsByDomainByQueryType[d][dns.TypeA].CountByRcode[DNSResponseCodeNoError])
 	// domain agnostic stats should be 0
 	assert.EqualValues(t, 0, conns[0].DNSSuccessfulResponses)
 
 	// Register the third client but also pass in dns stats
-	conns = state.GetDelta(client3, latestEpochTime(), []ConnectionStats{c}, getStats(), nil).Connections
+	conns = state.GetDelta(client3, latestEpochTime(), []ConnectionStats{c}, getStats(), nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	// DNS stats should be available for the new client
 	assert.EqualValues(t, 1, conns[0].DNSStatsByDomainByQueryType[d][dns.TypeA].CountByRcode[DNSResponseCodeNoError])
 	// domain agnostic stats should be 0
 	assert.EqualValues(t, 0, conns[0].DNSSuccessfulResponses)
 
-	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{c}, getStats(), nil).Connections
+	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{c}, getStats(), nil, nil, nil, nil).Connections
 	require.Len(t, conns, 1)
 	// 2nd client should get accumulated stats
 	assert.EqualValues(t, 3, conns[0].DNSStatsByDomainByQueryType[d][dns.TypeA].CountByRcode[DNSResponseCodeNoError])
@@ -1248,6 +1249,51 @@ func TestDNSStatsWithMultipleClientsWithDomainCollectionEnabled(t *testing.T) {
 	assert.EqualValues(t, 0, conns[0].DNSSuccessfulResponses)
 }
 
+func TestDNSStatsTTLs(t *testing.T) {
+	c := ConnectionStats{
+		Pid:    123,
+		Type:   UDP,
+		Family: AFINET,
+		Source: util.AddressFromString("127.0.0.1"),
+		Dest:   util.AddressFromString("127.0.0.1"),
+		SPort:  1000,
+		DPort:  53,
+	}
+
+	dKey := dns.Key{ClientIP: c.Source, ClientPort: c.SPort, ServerIP: c.Dest, Protocol: getIPProtocol(c.Type)}
+	var d = intern.GetByString("foo.com")
+	getStats := func(ttl uint32) dns.StatsByKeyByNameByType {
+		stats := dns.Stats{CountByRcode: map[uint32]uint32{uint32(DNSResponseCodeNoError): 1}}
+		stats.AddTTL(ttl)
+		return dns.StatsByKeyByNameByType{
+			dKey: {d: {dns.TypeA: stats}},
+		}
+	}
+
+	client1 := "client1"
+	client2 := "client2"
+	state := NewState(2*time.Minute, 50000, 75000, 75000, 7500, true)
+
+	// Register the clients
+	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
+
+	conns := state.GetDelta(client1, latestEpochTime(), []ConnectionStats{c}, getStats(300), nil, nil, nil, nil).Connections
+	require.Len(t, conns, 1)
+	stats := conns[0].DNSStatsByDomainByQueryType[d][dns.TypeA]
+	assert.EqualValues(t, 1, stats.TTLCount)
+	assert.EqualValues(t, 300, stats.MinTTL)
+	assert.EqualValues(t, 300, stats.MaxTTL)
+
+	conns = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{c}, getStats(30), nil, nil, nil, nil).Connections
+	require.Len(t, conns, 1)
+	// 2nd client should get the TTLs of both responses
+	stats = conns[0].DNSStatsByDomainByQueryType[d][dns.TypeA]
+	assert.EqualValues(t, 2, stats.TTLCount)
+	assert.EqualValues(t, 30, stats.MinTTL)
+	assert.EqualValues(t, 300, stats.MaxTTL)
+}
+
 func TestDNSStatsPIDCollisions(t *testing.T) {
 	c := ConnectionStats{
 		Pid:    123,
@@ -1273,7 +1319,7 @@ func TestDNSStatsPIDCollisions(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the client
-	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil).Connections, 0)
+	assert.Len(t, state.GetDelta(client, latestEpochTime(), nil, nil, nil, nil, nil, nil).Connections, 0)
 
 	c.LastUpdateEpoch = latestEpochTime()
 	state.StoreClosedConnection(&c)
@@ -1282,7 +1328,7 @@ func TestDNSStatsPIDCollisions(t *testing.T) {
 	c.Pid++
 	state.StoreClosedConnection(&c)
 
-	conns := state.GetDelta(client, latestEpochTime(), nil, statsByDomain, nil).Connections
+	conns := state.GetDelta(client, latestEpochTime(), nil, statsByDomain, nil, nil, nil, nil).Connections
 	require.Len(t, conns, 2)
 	successes := 0
 	for _, conn := range conns {
@@ -1308,16 +1354,104 @@ func TestHTTPStats(t *testing.T) {
 
 	// Register client & pass in HTTP stats
 	state := newDefaultState()
-	delta := state.GetDelta("client", latestEpochTime(), []ConnectionStats{c}, nil, httpStats)
+	delta := state.GetDelta("client", latestEpochTime(), []ConnectionStats{c}, nil, httpStats, nil, nil, nil)
 
 	// Verify connection has HTTP data embedded in it
 	assert.Len(t, delta.HTTP, 1)
 
 	// Verify HTTP data has been flushed
-	delta = state.GetDelta("client", latestEpochTime(), []ConnectionStats{c}, nil, nil)
+	delta = state.GetDelta("client", latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, nil, nil)
 	assert.Len(t, delta.HTTP, 0)
 }
 
+func TestGRPCStats(t *testing.T) {
+	c := ConnectionStats{
+		Source: util.AddressFromString("1.1.1.1"),
+		Dest:   util.AddressFromString("0.0.0.0"),
+		SPort:  1000,
+		DPort:  50051,
+	}
+
+	key := http.NewKey(c.Source, c.Dest, c.SPort, c.DPort, "/helloworld.Greeter/SayHello", http.MethodPost)
+
+	var gs http.GRPCStats
+	gs.AddCall(0)
+	grpcStats := map[http.Key]http.GRPCStats{key: gs}
+
+	// Register the clients
+	state := newDefaultState()
+	state.GetDelta("client-a", latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, nil, nil)
+	state.GetDelta("client-b", latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, nil, nil)
+
+	delta := state.GetDelta("client-a", latestEpochTime(), []ConnectionStats{c}, nil, nil, grpcStats, nil, nil)
+	assert.Equal(t, map[http.Key]http.GRPCStats{key: gs}, delta.GRPC)
+
+	// The stats are flushed for the client that got them
+	delta = state.GetDelta("client-a", latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, nil, nil)
+	assert.Empty(t, delta.GRPC)
+
+	// and kept for the other client, combined with the new stats
+	delta = state.GetDelta("client-b", latestEpochTime(), []ConnectionStats{c}, nil, nil, grpcStats, nil, nil)
+	var expected http.GRPCStats
+	expected[0] = 2
+	assert.Equal(t, map[http.Key]http.GRPCStats{key: expected}, delta.GRPC)
+}
+
+func TestProtocolStats(t *testing.T) {
+	c := ConnectionStats{
+		Source: util.AddressFromString("1.1.1.1"),
+		Dest:   util.AddressFromString("0.0.0.0"),
+		SPort:  1000,
+		DPort:  6379,
+	}
+
+	key := protocols.NewKey(c.Source, c.Dest, c.SPort, c.DPort, protocols.Redis, "GET")
+
+	var rs protocols.RequestStats
+	rs.AddRequest(100, false)
+	protocolStats := map[protocols.Key]protocols.RequestStats{key: rs}
+
+	// Register client & pass in protocol stats
+	state := newDefaultState()
+	delta := state.GetDelta("client", latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, protocolStats, nil)
+	require.Len(t, delta.Protocols, 1)
+	assert.Equal(t, 1, delta.Protocols[key].Count)
+
+	// Verify protocol stats have been flushed
+	delta = state.GetDelta("client", latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, nil, nil)
+	assert.Empty(t, delta.Protocols)
+}
+
+func TestDNSResolverStatsDelta(t *testing.T) {
+	resolverStats := func(timeouts, noErrors, nxDomains uint32, latency uint64) map[string]dns.Stats {
+		return map[string]dns.Stats{
+			"8.8.8.8": {
+				Timeouts:          timeouts,
+				SuccessLatencySum: latency,
+				CountByRcode:      map[uint32]uint32{0: noErrors, 3: nxDomains},
+			},
+		}
+	}
+
+	state := newDefaultState()
+	delta := state.GetDelta("client1", latestEpochTime(), nil, nil, nil, nil, nil, resolverStats(1, 2, 0, 100))
+	assert.Equal(t, resolverStats(1, 2, 0, 100), delta.DNSResolvers)
+
+	// the stats are reported as deltas
+	delta = state.GetDelta("client1", latestEpochTime(), nil, nil, nil, nil, nil, resolverStats(1, 5, 1, 400))
+	assert.Equal(t, map[string]dns.Stats{
+		"8.8.8.8": {SuccessLatencySum: 300, CountByRcode: map[uint32]uint32{0: 3, 3: 1}},
+	}, delta.DNSResolvers)
+
+	// the resolvers which didn't answer any lookup are omitted
+	delta = state.GetDelta("client1", latestEpochTime(), nil, nil, nil, nil, nil, resolverStats(1, 5, 1, 400))
+	assert.Empty(t, delta.DNSResolvers)
+
+	// the deltas are computed by client
+	delta = state.GetDelta("client2", latestEpochTime(), nil, nil, nil, nil, nil, resolverStats(1, 5, 1, 400))
+	assert.Equal(t, resolverStats(1, 5, 1, 400), delta.DNSResolvers)
+}
+
 func TestHTTPStatsWithMultipleClients(t *testing.T) {
 	c := ConnectionStats{
 		Source: util.AddressFromString("1.1.1.1"),
@@ -1340,37 +1474,37 @@ func TestHTTPStatsWithMultipleClients(t *testing.T) {
 	state := newDefaultState()
 
 	// Register the first two clients
-	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil).HTTP, 0)
-	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil).HTTP, 0)
+	assert.Len(t, state.GetDelta(client1, latestEpochTime(), nil, nil, nil, nil, nil, nil).HTTP, 0)
+	assert.Len(t, state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil).HTTP, 0)
 
 	// Store the connection to both clients & pass HTTP stats to the first client
 	c.LastUpdateEpoch = latestEpochTime()
 	state.StoreClosedConnection(&c)
 
-	delta := state.GetDelta(client1, latestEpochTime(), nil, nil, getStats("/testpath"))
+	delta := state.GetDelta(client1, latestEpochTime(), nil, nil, getStats("/testpath"), nil, nil, nil)
 	assert.Len(t, delta.HTTP, 1)
 
 	// Verify that the HTTP stats were also stored in the second client
-	delta = state.GetDelta(client2, latestEpochTime(), nil, nil, nil)
+	delta = state.GetDelta(client2, latestEpochTime(), nil, nil, nil, nil, nil, nil)
 	assert.Len(t, delta.HTTP, 1)
 
 	// Register a third client & verify that it does not have the HTTP stats
-	delta = state.GetDelta(client3, latestEpochTime(), []ConnectionStats{c}, nil, nil)
+	delta = state.GetDelta(client3, latestEpochTime(), []ConnectionStats{c}, nil, nil, nil, nil, nil)
 	assert.Len(t, delta.HTTP, 0)
 
 	c.LastUpdateEpoch = latestEpochTime()
 	state.StoreClosedConnection(&c)
 
 	// Pass in new HTTP stats to the first client
-	delta = state.GetDelta(client1, latestEpochTime(), nil, nil, getStats("/testpath2"))
+	delta = state.GetDelta(client1, latestEpochTime(), nil, nil, getStats("/testpath2"), nil, nil, nil)
 	assert.Len(t, delta.HTTP, 1)
 
 	// And the second client
-	delta = state.GetDelta(client2, latestEpochTime(), nil, nil, getStats("/testpath3"))
+	delta = state.GetDelta(client2, latestEpochTime(), nil, nil, getStats("/testpath3"), nil, nil, nil)
 	assert.Len(t, delta.HTTP, 2)
 
 	// Verify that the third client also accumulated both new HTTP stats
-	delta = state.GetDelta(client3, latestEpochTime(), nil, nil, nil)
+	delta = state.GetDelta(client3, latestEpochTime(), nil, nil, nil, nil, nil, nil)
 	assert.Len(t, delta.HTTP, 2)
 }
 
@@ -22,6 +22,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network/ebpf/probes"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
 	"github.com/DataDog/datadog-agent/pkg/network/netlink"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/connection"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/connection/kprobe"
 	"github.com/DataDog/datadog-agent/pkg/process/procutil"
@@ -43,6 +44,8 @@ type Tracer struct {
 	httpMonitor *http.Monitor
 	ebpfTracer  connection.Tracer
 
+	protocolMonitor *protocols.Monitor
+
 	closedConnsCh <-chan network.ConnectionStats
 
 	// Telemetry
@@ -155,6 +158,7 @@ func NewTracer(config *config.Config) (*Tracer, error) {
 		state:                      state,
 		reverseDNS:                 newReverseDNS(!pre410Kernel, config),
 		httpMonitor:                newHTTPMonitor(!pre410Kernel, config, ebpfTracer, constantEditors),
+		protocolMonitor:            newProtocolMonitor(config),
 		buffer:                     make([]network.ConnectionStats, 0, 512),
 		conntracker:                conntracker,
 		sourceExcludes:             network.ParseConnectionFilters(config.ExcludedSourceConnections),
@@ -282,6 +286,7 @@ func (t *Tracer) storeClosedConn(cs *network.ConnectionStats) {
 	atomic.AddInt64(&t.closedConns, 1)
 	cs.IPTranslation = t.conntracker.GetTranslationForConn(*cs)
 	t.connVia(cs)
+	t.connProtocol(cs)
 	t.state.StoreClosedConnection(cs)
 	if cs.IPTranslation != nil {
 		t.conntracker.DeleteTranslation(*cs)
@@ -292,6 +297,7 @@ func (t *Tracer) Stop() {
 	t.reverseDNS.Close()
 	t.ebpfTracer.Stop()
 	t.httpMonitor.Stop()
+	t.protocolMonitor.Stop()
 	t.conntracker.Close()
 }
 
@@ -320,7 +326,9 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 		t.storeClosedConn(&c)
 	}
 
-	delta := t.state.GetDelta(clientID, latestTime, latestConns, t.reverseDNS.GetDNSStats(), t.httpMonitor.GetHTTPStats())
+	httpStats, grpcStats := t.httpMonitor.GetHTTPStats()
+	protocolStats := t.protocolMonitor.GetAndResetAllStats()
+	delta := t.state.GetDelta(clientID, latestTime, latestConns, t.reverseDNS.GetDNSStats(), httpStats, grpcStats, protocolStats, t.reverseDNS.GetResolverStats())
 	ips := make([]util.Address, 0, len(delta.Connections)*2)
 	for _, conn := range delta.Connections {
 		ips = append(ips, conn.Source, conn.Dest)
@@ -333,6 +341,9 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 		Conns:                       delta.Connections,
 		DNS:                         names,
 		HTTP:                        delta.HTTP,
+		GRPC:                        delta.GRPC,
+		Protocols:                   delta.Protocols,
+		DNSResolvers:                delta.DNSResolvers,
 		ConnTelemetry:               ctm,
 		CompilationTelemetryByAsset: rctm,
 	}, nil
@@ -439,6 +450,7 @@ func (t *Tracer) getConnections(buffer []network.ConnectionStats) ([]network.Con
 
 	for i, conn := range active {
 		active[i].IPTranslation = t.conntracker.GetTranslationForConn(conn)
+		t.connProtocol(&active[i])
 	}
 
 	entryCount := uint(len(active))
@@ -554,11 +566,19 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 		"ebpf":      t.ebpfTracer.GetTelemetry(),
 		"kprobes":   ddebpf.GetProbeStats(),
 		"dns":       t.reverseDNS.GetStats(),
+		"http":      t.httpMonitor.GetStats(),
+		// cumulative stats of each resolver, keyed by IP
+		"dns_resolvers": t.reverseDNS.GetResolverStats(),
 	}
 
 	return ret, nil
 }
 
+// DNSFailedLookups returns the most recent failed DNS lookups, or nil if their recording is disabled
+func (t *Tracer) DNSFailedLookups() []dns.FailedLookup {
+	return t.reverseDNS.FailedLookups()
+}
+
 // DebugNetworkState returns a map with the current tracer's internal state, for debugging
 func (t *Tracer) DebugNetworkState(clientID string) (map[string]interface{}, error) {
 	if t.state == nil {
@@ -616,6 +636,39 @@ func (t *Tracer) connVia(cs *network.ConnectionStats) {
 	cs.Via = t.gwLookup.Lookup(cs)
 }
 
+// connProtocol sets the application protocol of a TCP connection, if it was classified
+func (t *Tracer) connProtocol(cs *network.ConnectionStats) {
+	if t.protocolMonitor == nil || cs.Type != network.TCP {
+		return
+	}
+
+	key := network.ProtocolKey(*cs)
+	cs.Protocol = t.protocolMonitor.GetProtocol(key, cs.Family == network.AFINET6)
+	if cs.Protocol == protocols.TLS {
+		cs.TLS = t.protocolMonitor.GetTLSMetadata(key)
+	}
+}
+
+func newProtocolMonitor(c *config.Config) *protocols.Monitor {
+	if !c.EnableProtocolClassification {
+		return nil
+	}
+
+	monitor, err := protocols.NewMonitor(c)
+	if err != nil {
+		log.Errorf("could not enable protocol classification: %s", err)
+		return nil
+	}
+
+	if err := monitor.Start(); err != nil {
+		log.Errorf("could not start protocol classification: %s", err)
+		monitor.Stop()
+		return nil
+	}
+	log.Info("protocol classification enabled")
+	return monitor
+}
+
 func newHTTPMonitor(supported bool, c *config.Config, tracer connection.Tracer, offsets []manager.ConstantEditor) *http.Monitor {
 	if !c.EnableHTTPMonitoring {
 		return nil
@@ -6,6 +6,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
 // Tracer is not implemented
@@ -29,6 +30,11 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
 }
 
+// DNSFailedLookups is not implemented on this OS for Tracer
+func (t *Tracer) DNSFailedLookups() []dns.FailedLookup {
+	return nil
+}
+
 // DebugNetworkState is not implemented on this OS for Tracer
 func (t *Tracer) DebugNetworkState(clientID string) (map[string]interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
@@ -124,14 +124,14 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	// check for expired clients in the state
 	t.state.RemoveExpiredClients(time.Now())
 
-	delta := t.state.GetDelta(clientID, uint64(time.Now().Nanosecond()), activeConnStats, t.reverseDNS.GetDNSStats(), nil)
+	delta := t.state.GetDelta(clientID, uint64(time.Now().Nanosecond()), activeConnStats, t.reverseDNS.GetDNSStats(), nil, nil, nil, t.reverseDNS.GetResolverStats())
 	conns := delta.Connections
 	var ips []util.Address
 	for _, conn := range delta.Connections {
 		ips = append(ips, conn.Source, conn.Dest)
 	}
 	names := t.reverseDNS.Resolve(ips)
-	return &network.Connections{Conns: conns, DNS: names}, nil
+	return &network.Connections{Conns: conns, DNS: names, DNSResolvers: delta.DNSResolvers}, nil
 }
 
 // GetStats returns a map of statistics about the current tracer's internal state
@@ -144,6 +144,8 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 	stats := map[string]interface{}{
 		"state": t.state.GetStats(),
 		"dns":   t.reverseDNS.GetStats(),
+		// cumulative stats of each resolver, keyed by IP
+		"dns_resolvers": t.reverseDNS.GetResolverStats(),
 	}
 	for _, name := range network.DriverExpvarNames {
 		stats[string(name)] = driverStats[name]
@@ -151,6 +153,11 @@ func (t *Tracer) GetStats() (map[string]interface{}, error) {
 	return stats, nil
 }
 
+// DNSFailedLookups returns the most recent failed DNS lookups, or nil if their recording is disabled
+func (t *Tracer) DNSFailedLookups() []dns.FailedLookup {
+	return t.reverseDNS.FailedLookups()
+}
+
 // DebugNetworkState returns a map with the current tracer's internal state, for debugging
 func (t *Tracer) DebugNetworkState(_ string) (map[string]interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
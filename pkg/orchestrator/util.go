@@ -39,6 +39,28 @@ const (
 	K8sPersistentVolume
 	// K8sPersistentVolumeClaim represents a Kubernetes PersistentVolumeClaim
 	K8sPersistentVolumeClaim
+	// K8sRole represents a Kubernetes Role
+	K8sRole
+	// K8sRoleBinding represents a Kubernetes RoleBinding
+	K8sRoleBinding
+	// K8sClusterRole represents a Kubernetes ClusterRole
+	K8sClusterRole
+	// K8sClusterRoleBinding represents a Kubernetes ClusterRoleBinding
+	K8sClusterRoleBinding
+	// K8sServiceAccount represents a Kubernetes ServiceAccount
+	K8sServiceAccount
+	// K8sIngress represents a Kubernetes Ingress
+	K8sIngress
+)
+
+// The following node types are not contiguous, their values must match the type IDs of the
+// manifest intake: 18 and 19 are used by custom resource definitions and custom resources,
+// 21 by vertical pod autoscalers.
+const (
+	// K8sNamespace represents a Kubernetes Namespace
+	K8sNamespace NodeType = 20
+	// K8sHorizontalPodAutoscaler represents a Kubernetes HorizontalPodAutoscaler
+	K8sHorizontalPodAutoscaler NodeType = 22
 )
 
 // NodeTypes returns the current existing NodesTypes as a slice to iterate over.
@@ -56,6 +78,14 @@ func NodeTypes() []NodeType {
 		K8sStatefulSet,
 		K8sPersistentVolumeClaim,
 		K8sPersistentVolume,
+		K8sRole,
+		K8sRoleBinding,
+		K8sClusterRole,
+		K8sClusterRoleBinding,
+		K8sServiceAccount,
+		K8sIngress,
+		K8sNamespace,
+		K8sHorizontalPodAutoscaler,
 	}
 }
 
@@ -85,6 +115,22 @@ func (n NodeType) String() string {
 		return "PersistentVolume"
 	case K8sPersistentVolumeClaim:
 		return "PersistentVolumeClaim"
+	case K8sIngress:
+		return "Ingress"
+	case K8sNamespace:
+		return "Namespace"
+	case K8sRole:
+		return "Role"
+	case K8sClusterRole:
+		return "ClusterRole"
+	case K8sRoleBinding:
+		return "RoleBinding"
+	case K8sClusterRoleBinding:
+		return "ClusterRoleBinding"
+	case K8sServiceAccount:
+		return "ServiceAccount"
+	case K8sHorizontalPodAutoscaler:
+		return "HorizontalPodAutoscaler"
 	default:
 		log.Errorf("Trying to convert unknown NodeType iota: %d", n)
 		return "Unknown"
@@ -95,7 +141,9 @@ func (n NodeType) String() string {
 func (n NodeType) Orchestrator() string {
 	switch n {
 	case K8sCluster, K8sCronJob, K8sDeployment, K8sDaemonSet, K8sJob,
-		K8sNode, K8sPod, K8sReplicaSet, K8sService, K8sStatefulSet, K8sPersistentVolume, K8sPersistentVolumeClaim:
+		K8sNode, K8sPod, K8sReplicaSet, K8sService, K8sStatefulSet, K8sPersistentVolume, K8sPersistentVolumeClaim,
+		K8sIngress, K8sNamespace, K8sRole, K8sClusterRole, K8sRoleBinding, K8sClusterRoleBinding, K8sServiceAccount,
+		K8sHorizontalPodAutoscaler:
 		return "k8s"
 	default:
 		log.Errorf("Unknown NodeType %v", n)
@@ -87,3 +87,20 @@ func TestGroupSize(t *testing.T) {
 		})
 	}
 }
+
+// TestNodeTypeValues checks that the node types keep the type IDs of the manifest intake
+func TestNodeTypeValues(t *testing.T) {
+	for nodeType, expected := range map[NodeType]int{
+		K8sPersistentVolumeClaim:   11,
+		K8sRole:                    12,
+		K8sRoleBinding:             13,
+		K8sClusterRole:             14,
+		K8sClusterRoleBinding:      15,
+		K8sServiceAccount:          16,
+		K8sIngress:                 17,
+		K8sNamespace:               20,
+		K8sHorizontalPodAutoscaler: 22,
+	} {
+		assert.Equal(t, expected, int(nodeType), nodeType.String())
+	}
+}
@@ -141,6 +141,21 @@ func (ds *DataScrubber) ScrubProcessCommand(p *procutil.Process) []string {
 	return p.Cmdline
 }
 
+// ScrubCmdline scrubs a cmdline the way ScrubProcessCommand does, without caching the result. Unlike
+// ScrubProcessCommand, it doesn't modify the DataScrubber and may be called concurrently.
+func (ds *DataScrubber) ScrubCmdline(cmdline []string) []string {
+	if ds.StripAllArguments {
+		return ds.stripArguments(cmdline)
+	}
+
+	if !ds.Enabled {
+		return cmdline
+	}
+
+	scrubbed, _ := ds.ScrubCommand(cmdline)
+	return scrubbed
+}
+
 // IncrementCacheAge increments one cycle of cache memory age. If it reaches
 // cacheMaxCycles, the cache is restarted
 func (ds *DataScrubber) IncrementCacheAge() {
@@ -301,6 +301,23 @@ func TestBlacklistedArgs(t *testing.T) {
 	}
 }
 
+func TestScrubCmdline(t *testing.T) {
+	cases := setupSensitiveCmdlines()
+	scrubber := setupDataScrubber(t)
+
+	for i := range cases {
+		assert.Equal(t, cases[i].parsedCmdline, scrubber.ScrubCmdline(cases[i].cmdline))
+	}
+	// the cmdlines aren't cached
+	assert.Empty(t, scrubber.seenProcess)
+
+	scrubber.Enabled = false
+	assert.Equal(t, []string{"agent", "-password", "1234"}, scrubber.ScrubCmdline([]string{"agent", "-password", "1234"}))
+
+	scrubber.StripAllArguments = true
+	assert.Equal(t, []string{"agent"}, scrubber.ScrubCmdline([]string{"agent", "-password", "1234"}))
+}
+
 func TestBlacklistedArgsWhenDisabled(t *testing.T) {
 	cases := []struct {
 		cmdline       []string
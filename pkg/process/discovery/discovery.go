@@ -0,0 +1,194 @@
+package discovery
+
+import (
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+// Language is the language, or runtime, a process is running
+type Language string
+
+// Languages we detect
+const (
+	LanguageUnknown Language = ""
+	LanguageJava    Language = "java"
+	LanguagePython  Language = "python"
+	LanguageNode    Language = "node"
+	LanguageDotnet  Language = ".net"
+	LanguageGo      Language = "go"
+)
+
+// Port is a port a process is listening on
+type Port struct {
+	// Protocol is either "tcp" or "udp"
+	Protocol string `json:"protocol"`
+	Port     uint16 `json:"port"`
+}
+
+// Service is a process which was identified as running a service, because its language,
+// its server software or its service name was detected, or because it listens on a port
+type Service struct {
+	PID  int32  `json:"pid"`
+	Name string `json:"name"`
+
+	Language Language `json:"language,omitempty"`
+	// LanguageVersion is only known for some languages, such as Go
+	LanguageVersion string `json:"language_version,omitempty"`
+
+	// Server is the well-known server software the process is running, such as nginx
+	Server string `json:"server,omitempty"`
+
+	// ServiceName is the name of the service set by the process configuration, and ServiceNameSource the
+	// flag or environment variable it was extracted from
+	ServiceName       string `json:"service_name,omitempty"`
+	ServiceNameSource string `json:"service_name_source,omitempty"`
+
+	Ports []Port `json:"ports,omitempty"`
+}
+
+// Inventory is the list of the services running on a host
+type Inventory struct {
+	Hostname  string    `json:"hostname"`
+	Timestamp int64     `json:"timestamp"`
+	Services  []Service `json:"services"`
+}
+
+// Discoverer identifies the services run by processes
+type Discoverer struct {
+	procRoot string
+}
+
+// NewDiscoverer returns a Discoverer reading the details of the processes, such as their environment
+// variables or their sockets, from procRoot
+func NewDiscoverer(procRoot string) *Discoverer {
+	return &Discoverer{procRoot: procRoot}
+}
+
+// Inventory returns the inventory of the services run by the given processes
+func (d *Discoverer) Inventory(hostname string, procs map[int32]*procutil.Process) *Inventory {
+	return &Inventory{
+		Hostname:  hostname,
+		Timestamp: time.Now().Unix(),
+		Services:  d.Discover(procs),
+	}
+}
+
+// Discover returns the services run by the given processes, sorted by PID. The processes a service
+// forks, such as the workers of nginx, are folded into the process which started them.
+func (d *Discoverer) Discover(procs map[int32]*procutil.Process) []Service {
+	portsByPID := d.listeningPorts(procs)
+
+	services := make(map[int32]*Service)
+	for pid, proc := range procs {
+		// skip kernel threads
+		if len(proc.Cmdline) == 0 {
+			continue
+		}
+		if s := d.discover(proc, portsByPID[pid]); s != nil {
+			services[pid] = s
+		}
+	}
+
+	// find the first process of each service, in case it forked several times. As PIDs get reused,
+	// the PPIDs of a snapshot of the processes may form a cycle, whose root is its lowest PID.
+	roots := make(map[int32]*Service, len(services))
+	for pid, s := range services {
+		root := s
+		path := []*Service{s}
+		visited := map[int32]int{pid: 0}
+		for {
+			parent := services[procs[root.PID].Ppid]
+			if parent == nil || !sameService(root, parent) {
+				break
+			}
+			if i, ok := visited[parent.PID]; ok {
+				root = lowestPID(path[i:])
+				break
+			}
+			visited[parent.PID] = len(path)
+			path = append(path, parent)
+			root = parent
+		}
+		roots[pid] = root
+	}
+
+	for pid, s := range services {
+		if root := roots[pid]; root != s {
+			root.Ports = append(root.Ports, s.Ports...)
+		}
+	}
+
+	ret := make([]Service, 0, len(services))
+	for pid, s := range services {
+		if roots[pid] == s {
+			s.Ports = sortPorts(s.Ports)
+			ret = append(ret, *s)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].PID < ret[j].PID
+	})
+	return ret
+}
+
+// discover returns the service run by a process, or nil if nothing identifies it as a service
+func (d *Discoverer) discover(proc *procutil.Process, ports []Port) *Service {
+	name := executableName(proc)
+	s := &Service{
+		PID:      proc.Pid,
+		Name:     proc.Name,
+		Language: detectLanguage(name),
+		Server:   detectServer(name, proc.Cmdline),
+		Ports:    ports,
+	}
+	if s.Language == LanguageUnknown {
+		s.Language, s.LanguageVersion = d.detectCompiledLanguage(proc.Pid)
+	}
+	s.ServiceName, s.ServiceNameSource = serviceNameFromCmdline(proc.Cmdline)
+	if s.ServiceName == "" {
+		s.ServiceName, s.ServiceNameSource = serviceNameFromEnv(d.readEnv(proc.Pid, serviceNameEnvVars))
+	}
+
+	if s.Language == LanguageUnknown && s.Server == "" && s.ServiceName == "" && len(s.Ports) == 0 {
+		return nil
+	}
+	return s
+}
+
+// lowestPID returns the service with the lowest PID
+func lowestPID(services []*Service) *Service {
+	lowest := services[0]
+	for _, s := range services[1:] {
+		if s.PID < lowest.PID {
+			lowest = s
+		}
+	}
+	return lowest
+}
+
+// sameService returns whether two processes run the same service
+func sameService(a, b *Service) bool {
+	return a.Name == b.Name && a.Language == b.Language && a.Server == b.Server && a.ServiceName == b.ServiceName
+}
+
+// sortPorts sorts ports, removing the duplicates
+func sortPorts(ports []Port) []Port {
+	if len(ports) == 0 {
+		return nil
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return ports[i].Port < ports[j].Port
+	})
+	unique := ports[:1]
+	for _, p := range ports[1:] {
+		if p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,213 @@
+// +build linux
+
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+const (
+	// states of the sockets in /proc/net, in hexadecimal
+	tcpListen      = "0A"
+	udpUnconnected = "07"
+
+	// goBuildInfoSize is the size of the header of the build info of Go executables
+	goBuildInfoSize = 32
+	// goBuildInfoInlineVersion is the flag of the executables built by Go 1.18 and later, which inline the
+	// version in their build info instead of referencing it with a pointer
+	goBuildInfoInlineVersion = 0x2
+	// maxGoVersionSize bounds the size of the version of Go we read
+	maxGoVersionSize = 64
+)
+
+var goBuildInfoMagic = []byte("\xff Go buildinf:")
+
+// procNetFiles are the files of /proc/<pid>/net listing the sockets of a network namespace
+var procNetFiles = []struct {
+	name     string
+	protocol string
+	state    string
+}{
+	{"tcp", "tcp", tcpListen},
+	{"tcp6", "tcp", tcpListen},
+	{"udp", "udp", udpUnconnected},
+	{"udp6", "udp", udpUnconnected},
+}
+
+func (d *Discoverer) pidPath(pid int32, elems ...string) string {
+	return filepath.Join(append([]string{d.procRoot, strconv.Itoa(int(pid))}, elems...)...)
+}
+
+// readEnv returns the given environment variables of a process, if they are set. The other variables
+// are not kept, as they may hold secrets.
+func (d *Discoverer) readEnv(pid int32, names []string) map[string]string {
+	data, err := ioutil.ReadFile(d.pidPath(pid, "environ"))
+	if err != nil {
+		return nil
+	}
+
+	env := make(map[string]string)
+	for _, variable := range bytes.Split(data, []byte{0}) {
+		for _, name := range names {
+			if len(variable) > len(name) && variable[len(name)] == '=' && string(variable[:len(name)]) == name {
+				env[name] = string(variable[len(name)+1:])
+			}
+		}
+	}
+	return env
+}
+
+// detectCompiledLanguage returns the language of an executable which isn't an interpreter, and its version if known
+func (d *Discoverer) detectCompiledLanguage(pid int32) (Language, string) {
+	if version, ok := goVersion(d.pidPath(pid, "exe")); ok {
+		return LanguageGo, version
+	}
+	// self-contained .NET applications embed the runtime instead of being run by dotnet
+	if mapsLibrary(d.pidPath(pid, "maps"), "libcoreclr.so") {
+		return LanguageDotnet, ""
+	}
+	return LanguageUnknown, ""
+}
+
+// goVersion returns the version of Go an ELF executable was built with, and whether it was built with Go.
+// The version is only known for the executables built by Go 1.18 and later.
+func goVersion(path string) (string, bool) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	section := f.Section(".go.buildinfo")
+	if section == nil {
+		return "", false
+	}
+	data := make([]byte, goBuildInfoSize+maxGoVersionSize)
+	n, _ := section.ReadAt(data, 0)
+	if n < goBuildInfoSize || !bytes.HasPrefix(data, goBuildInfoMagic) {
+		return "", false
+	}
+	if data[len(goBuildInfoMagic)+1]&goBuildInfoInlineVersion == 0 {
+		return "", true
+	}
+
+	data = data[goBuildInfoSize:n]
+	size, read := binary.Uvarint(data)
+	if read <= 0 || size > uint64(len(data)-read) {
+		return "", true
+	}
+	return string(data[read : read+int(size)]), true
+}
+
+// mapsLibrary returns whether a process maps the given library
+func mapsLibrary(mapsPath string, library string) bool {
+	f, err := os.Open(mapsPath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.HasSuffix(scanner.Text(), "/"+library) {
+			return true
+		}
+	}
+	return false
+}
+
+// listeningPorts returns the ports the processes listen on, by PID
+func (d *Discoverer) listeningPorts(procs map[int32]*procutil.Process) map[int32][]Port {
+	// the sockets are listed by network namespace, which we read once
+	portsByInode := make(map[uint64]Port)
+	namespaces := make(map[string]struct{})
+	for pid := range procs {
+		ns, err := os.Readlink(d.pidPath(pid, "ns", "net"))
+		if err != nil {
+			continue
+		}
+		if _, ok := namespaces[ns]; ok {
+			continue
+		}
+		namespaces[ns] = struct{}{}
+
+		for _, f := range procNetFiles {
+			readListeningSockets(d.pidPath(pid, "net", f.name), f.protocol, f.state, portsByInode)
+		}
+	}
+	if len(portsByInode) == 0 {
+		return nil
+	}
+
+	portsByPID := make(map[int32][]Port)
+	for pid := range procs {
+		fdPath := d.pidPath(pid, "fd")
+		dir, err := os.Open(fdPath)
+		if err != nil {
+			continue
+		}
+		fds, err := dir.Readdirnames(-1)
+		dir.Close()
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdPath, fd))
+			if err != nil || !strings.HasPrefix(link, "socket:[") || !strings.HasSuffix(link, "]") {
+				continue
+			}
+			inode, err := strconv.ParseUint(link[len("socket:["):len(link)-1], 10, 64)
+			if err != nil {
+				continue
+			}
+			if port, ok := portsByInode[inode]; ok {
+				portsByPID[pid] = append(portsByPID[pid], port)
+			}
+		}
+	}
+	return portsByPID
+}
+
+// readListeningSockets reads the sockets in the given state of a /proc/net file, and adds their port by inode
+func readListeningSockets(path string, protocol string, state string, portsByInode map[uint64]Port) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// skip the header line
+	scanner.Scan()
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != state {
+			continue
+		}
+		i := strings.LastIndexByte(fields[1], ':')
+		if i < 0 {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil || inode == 0 {
+			continue
+		}
+		portsByInode[inode] = Port{Protocol: protocol, Port: uint16(port)}
+	}
+}
@@ -0,0 +1,117 @@
+// +build linux
+
+package discovery
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+const testProcNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1
+`
+
+const testProcNetUDP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  0: 00000000000000000000000000000000:0035 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 2001 2 0000000000000000 0
+`
+
+// newTestProcRoot creates a procfs with a process listening on a TCP and a UDP port
+func newTestProcRoot(t *testing.T) string {
+	procRoot, err := ioutil.TempDir("", "discovery")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(procRoot) })
+
+	pidPath := filepath.Join(procRoot, "100")
+	for _, dir := range []string{"net", "ns", "fd"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(pidPath, dir), 0755))
+	}
+	require.NoError(t, os.Symlink("net:[4026531992]", filepath.Join(pidPath, "ns", "net")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pidPath, "net", "tcp"), []byte(testProcNetTCP), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pidPath, "net", "udp6"), []byte(testProcNetUDP6), 0644))
+	for fd, target := range map[string]string{"0": "/dev/null", "3": "socket:[1001]", "4": "socket:[1002]", "5": "socket:[2001]"} {
+		require.NoError(t, os.Symlink(target, filepath.Join(pidPath, "fd", fd)))
+	}
+	environ := "PATH=/usr/bin\x00DD_SERVICE=web\x00DD_API_KEY=secret\x00"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pidPath, "environ"), []byte(environ), 0644))
+	return procRoot
+}
+
+func TestDiscoverProcRoot(t *testing.T) {
+	d := NewDiscoverer(newTestProcRoot(t))
+	assert.Equal(t, map[string]string{"DD_SERVICE": "web"}, d.readEnv(100, serviceNameEnvVars))
+
+	procs := map[int32]*procutil.Process{
+		100: {Pid: 100, Name: "server", Exe: "/usr/local/bin/server", Cmdline: []string{"server"}},
+	}
+	assert.Equal(t, []Service{{
+		PID:               100,
+		Name:              "server",
+		ServiceName:       "web",
+		ServiceNameSource: "DD_SERVICE",
+		Ports:             []Port{{"tcp", 8080}, {"udp", 53}},
+	}}, d.Discover(procs))
+}
+
+func TestGoVersion(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	version, ok := goVersion(exe)
+	assert.True(t, ok)
+	assert.Equal(t, runtime.Version(), version)
+
+	_, ok = goVersion("/nonexistent")
+	assert.False(t, ok)
+}
+
+func TestDiscoverSelf(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer tcp.Close()
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer udp.Close()
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	pid := int32(os.Getpid())
+	procs := map[int32]*procutil.Process{
+		pid: {Pid: pid, Name: filepath.Base(exe), Exe: exe, Cmdline: os.Args},
+	}
+
+	services := NewDiscoverer("/proc").Discover(procs)
+	require.Len(t, services, 1)
+	assert.Equal(t, LanguageGo, services[0].Language)
+	assert.Equal(t, runtime.Version(), services[0].LanguageVersion)
+	assert.Contains(t, services[0].Ports, Port{"tcp", uint16(tcp.Addr().(*net.TCPAddr).Port)})
+	assert.Contains(t, services[0].Ports, Port{"udp", uint16(udp.LocalAddr().(*net.UDPAddr).Port)})
+}
+
+func TestDiscoverServiceNameFromEnv(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	cmd.Env = append(os.Environ(), "DD_SERVICE=sleeper")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	pid := int32(cmd.Process.Pid)
+	procs := map[int32]*procutil.Process{
+		pid: {Pid: pid, Name: "sleep", Cmdline: []string{"sleep", "30"}},
+	}
+	services := NewDiscoverer("/proc").Discover(procs)
+	require.Len(t, services, 1)
+	assert.Equal(t, "sleeper", services[0].ServiceName)
+	assert.Equal(t, "DD_SERVICE", services[0].ServiceNameSource)
+}
@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+func TestExecutableName(t *testing.T) {
+	for _, tc := range []struct {
+		proc     procutil.Process
+		expected string
+	}{
+		{procutil.Process{Exe: "/usr/lib/jvm/java-11/bin/java", Cmdline: []string{"java"}}, "java"},
+		{procutil.Process{Cmdline: []string{"/usr/bin/python3.9", "app.py"}}, "python3.9"},
+		{procutil.Process{Exe: `C:\Program Files\dotnet\dotnet.exe`}, "dotnet"},
+		{procutil.Process{Name: "Nginx"}, "nginx"},
+	} {
+		assert.Equal(t, tc.expected, executableName(&tc.proc))
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	for name, language := range map[string]Language{
+		"java":      LanguageJava,
+		"python":    LanguagePython,
+		"python3":   LanguagePython,
+		"python3.9": LanguagePython,
+		"node":      LanguageNode,
+		"dotnet":    LanguageDotnet,
+		"pythonic":  LanguageUnknown,
+		"bash":      LanguageUnknown,
+	} {
+		assert.Equal(t, language, detectLanguage(name), name)
+	}
+}
+
+func TestDetectServer(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		cmdline  []string
+		expected string
+	}{
+		{"nginx", []string{"nginx: master process /usr/sbin/nginx -g daemon off;"}, "nginx"},
+		{"postgres", []string{"/usr/lib/postgresql/13/bin/postgres", "-D", "/var/lib/postgresql/13/main"}, "postgres"},
+		{"redis-server", []string{"/usr/bin/redis-server 127.0.0.1:6379"}, "redis"},
+		{"java", []string{"java", "-Xmx1G", "-cp", "/opt/kafka/libs/*", "kafka.Kafka", "config/server.properties"}, "kafka"},
+		{"java", []string{"java", "-jar", "app.jar", "kafka.Kafka"}, ""},
+		{"java", []string{"java", "-cp", "kafka.Kafka", "com.example.App"}, ""},
+		{"bash", []string{"bash"}, ""},
+	} {
+		assert.Equal(t, tc.expected, detectServer(tc.name, tc.cmdline), "%v", tc.cmdline)
+	}
+}
+
+func TestServiceName(t *testing.T) {
+	name, source := serviceNameFromCmdline([]string{"java", "-Dservice.name=billing", "-Ddd.service=payments", "-jar", "app.jar"})
+	assert.Equal(t, "payments", name)
+	assert.Equal(t, "-Ddd.service", source)
+
+	name, source = serviceNameFromCmdline([]string{"java -Dservice.name=billing -jar app.jar"})
+	assert.Equal(t, "billing", name)
+	assert.Equal(t, "-Dservice.name", source)
+
+	name, source = serviceNameFromCmdline([]string{"java", "-Dservice.name=", "-jar", "app.jar"})
+	assert.Empty(t, name)
+	assert.Empty(t, source)
+
+	name, source = serviceNameFromEnv(map[string]string{"OTEL_SERVICE_NAME": "otel", "DD_SERVICE": "dd"})
+	assert.Equal(t, "dd", name)
+	assert.Equal(t, "DD_SERVICE", source)
+
+	name, source = serviceNameFromEnv(map[string]string{"OTEL_SERVICE_NAME": "otel"})
+	assert.Equal(t, "otel", name)
+	assert.Equal(t, "OTEL_SERVICE_NAME", source)
+}
+
+func TestDiscover(t *testing.T) {
+	procs := map[int32]*procutil.Process{
+		// kernel thread
+		2:  {Pid: 2, Ppid: 0, Name: "kthreadd"},
+		10: {Pid: 10, Ppid: 1, Name: "bash", Exe: "/bin/bash", Cmdline: []string{"bash"}},
+		20: {Pid: 20, Ppid: 1, Name: "java", Exe: "/usr/bin/java", Cmdline: []string{"java", "-Dservice.name=broker", "-cp", "libs/*", "kafka.Kafka"}},
+		30: {Pid: 30, Ppid: 1, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: master process /usr/sbin/nginx"}},
+		31: {Pid: 31, Ppid: 30, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+		32: {Pid: 32, Ppid: 30, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+		40: {Pid: 40, Ppid: 10, Name: "python3", Exe: "/usr/bin/python3.9", Cmdline: []string{"python3", "app.py"}},
+		// a child process running another service is not folded
+		41: {Pid: 41, Ppid: 40, Name: "node", Exe: "/usr/bin/node", Cmdline: []string{"node", "server.js"}},
+	}
+
+	services := NewDiscoverer("/nonexistent").Discover(procs)
+	assert.Equal(t, []Service{
+		{PID: 20, Name: "java", Language: LanguageJava, Server: "kafka", ServiceName: "broker", ServiceNameSource: "-Dservice.name"},
+		{PID: 30, Name: "nginx", Server: "nginx"},
+		{PID: 40, Name: "python3", Language: LanguagePython},
+		{PID: 41, Name: "node", Language: LanguageNode},
+	}, services)
+}
+
+func TestDiscoverPPIDCycle(t *testing.T) {
+	// the PIDs were reused while the processes were listed
+	procs := map[int32]*procutil.Process{
+		50: {Pid: 50, Ppid: 52, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+		51: {Pid: 51, Ppid: 50, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+		52: {Pid: 52, Ppid: 51, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+		53: {Pid: 53, Ppid: 53, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: master process"}},
+		60: {Pid: 60, Ppid: 51, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+	}
+
+	services := NewDiscoverer("/nonexistent").Discover(procs)
+	assert.Equal(t, []Service{
+		{PID: 50, Name: "nginx", Server: "nginx"},
+		{PID: 53, Name: "nginx", Server: "nginx"},
+	}, services)
+}
+
+func TestSortPorts(t *testing.T) {
+	assert.Nil(t, sortPorts(nil))
+	assert.Equal(t, []Port{{"tcp", 80}, {"tcp", 443}, {"udp", 53}}, sortPorts([]Port{{"udp", 53}, {"tcp", 443}, {"tcp", 80}, {"tcp", 443}}))
+}
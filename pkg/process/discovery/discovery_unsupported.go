@@ -0,0 +1,20 @@
+// +build !linux
+
+package discovery
+
+import "github.com/DataDog/datadog-agent/pkg/process/procutil"
+
+// readEnv is not implemented on this OS
+func (d *Discoverer) readEnv(_ int32, _ []string) map[string]string {
+	return nil
+}
+
+// detectCompiledLanguage is not implemented on this OS
+func (d *Discoverer) detectCompiledLanguage(_ int32) (Language, string) {
+	return LanguageUnknown, ""
+}
+
+// listeningPorts is not implemented on this OS
+func (d *Discoverer) listeningPorts(_ map[int32]*procutil.Process) map[int32][]Port {
+	return nil
+}
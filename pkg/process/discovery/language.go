@@ -0,0 +1,60 @@
+package discovery
+
+import (
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+// languagesByExecutable maps the executables of interpreters and virtual machines to their language
+var languagesByExecutable = map[string]Language{
+	"java":   LanguageJava,
+	"javaw":  LanguageJava,
+	"node":   LanguageNode,
+	"nodejs": LanguageNode,
+	"dotnet": LanguageDotnet,
+}
+
+// executableName returns the lowercased name of the executable of a process, without its
+// directory nor its .exe extension
+func executableName(proc *procutil.Process) string {
+	exe := proc.Exe
+	if exe == "" && len(proc.Cmdline) > 0 {
+		exe = proc.Cmdline[0]
+	}
+	if exe == "" {
+		exe = proc.Name
+	}
+	// paths are split by hand, as filepath only knows the separator of the current platform
+	if i := strings.LastIndexAny(exe, `/\`); i >= 0 {
+		exe = exe[i+1:]
+	}
+	return strings.TrimSuffix(strings.ToLower(exe), ".exe")
+}
+
+// detectLanguage returns the language run by an executable, if it is an interpreter or a virtual machine
+func detectLanguage(name string) Language {
+	if language, ok := languagesByExecutable[name]; ok {
+		return language
+	}
+	if isPythonExecutable(name) {
+		return LanguagePython
+	}
+	return LanguageUnknown
+}
+
+// isPythonExecutable returns whether an executable is CPython, which is named after its version,
+// such as python3 or python3.9
+func isPythonExecutable(name string) bool {
+	version := strings.TrimPrefix(name, "python")
+	return version != name && strings.Trim(version, "0123456789.") == ""
+}
+
+// splitCmdline returns the arguments of a command line. Processes which rewrite their command line,
+// such as nginx, end up with their arguments joined by spaces.
+func splitCmdline(cmdline []string) []string {
+	if len(cmdline) == 1 {
+		return strings.Fields(cmdline[0])
+	}
+	return cmdline
+}
@@ -0,0 +1,133 @@
+package discovery
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	processutil "github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const defaultReportInterval = 600 // in seconds
+
+// IsEnabled returns whether process discovery is enabled in the Agent configuration
+func IsEnabled() bool {
+	return config.Datadog.GetBool("process_config.process_discovery.enabled")
+}
+
+// ReportInterval returns the interval at which the inventory of the services is sent
+func ReportInterval() time.Duration {
+	interval := config.Datadog.GetInt("process_config.process_discovery.interval")
+	if interval <= 0 {
+		interval = defaultReportInterval
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// Reporter periodically sends the inventory of the services running on the host to the event platform
+type Reporter struct {
+	// Telemetry is at the beginning of the struct to keep all fields 64-bit aligned.
+	// see https://staticcheck.io/docs/checks#SA1027
+	sent   int64
+	errors int64
+
+	discoverer    *Discoverer
+	listProcesses func() (map[int32]*procutil.Process, error)
+	sender        epforwarder.EventPlatformForwarder
+	hostname      string
+	interval      time.Duration
+
+	probe    *procutil.Probe
+	stopChan chan struct{}
+	stopped  sync.WaitGroup
+}
+
+// NewReporter returns a Reporter sending the inventory of the services of the host through sender at every interval
+func NewReporter(interval time.Duration, sender epforwarder.EventPlatformForwarder, hostname string) *Reporter {
+	probe := procutil.NewProcessProbe()
+	r := newReporter(interval, sender, hostname, NewDiscoverer(processutil.HostProc()), func() (map[int32]*procutil.Process, error) {
+		return probe.ProcessesByPID(time.Now())
+	})
+	r.probe = probe
+	return r
+}
+
+func newReporter(interval time.Duration, sender epforwarder.EventPlatformForwarder, hostname string, discoverer *Discoverer, listProcesses func() (map[int32]*procutil.Process, error)) *Reporter {
+	return &Reporter{
+		discoverer:    discoverer,
+		listProcesses: listProcesses,
+		sender:        sender,
+		hostname:      hostname,
+		interval:      interval,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start sends the inventory, and then sends it again at every interval until Stop is called
+func (r *Reporter) Start() {
+	r.stopped.Add(1)
+	go func() {
+		defer r.stopped.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		r.report()
+		for {
+			select {
+			case <-ticker.C:
+				r.report()
+			case <-r.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops sending the inventory
+func (r *Reporter) Stop() {
+	close(r.stopChan)
+	r.stopped.Wait()
+	if r.probe != nil {
+		r.probe.Close()
+	}
+}
+
+// Stats returns a map of counters, meant to be reported as telemetry
+func (r *Reporter) Stats() map[string]int64 {
+	return map[string]int64{
+		"inventories_sent":   atomic.LoadInt64(&r.sent),
+		"inventories_errors": atomic.LoadInt64(&r.errors),
+	}
+}
+
+// report builds the inventory of the services and sends it
+func (r *Reporter) report() {
+	procs, err := r.listProcesses()
+	if err != nil {
+		log.Errorf("Unable to list processes for discovery: %s", err)
+		atomic.AddInt64(&r.errors, 1)
+		return
+	}
+
+	inventory := r.discoverer.Inventory(r.hostname, procs)
+	payload, err := json.Marshal(inventory)
+	if err != nil {
+		log.Errorf("Error marshalling the inventory of the services: %s", err)
+		atomic.AddInt64(&r.errors, 1)
+		return
+	}
+	if err := r.sender.SendEventPlatformEvent(&message.Message{Content: payload}, epforwarder.EventTypeProcessDiscovery); err != nil {
+		log.Warnf("Error sending the inventory of the services: %s", err)
+		atomic.AddInt64(&r.errors, 1)
+		return
+	}
+	atomic.AddInt64(&r.sent, 1)
+	log.Debugf("Sent the inventory of %d services", len(inventory.Services))
+}
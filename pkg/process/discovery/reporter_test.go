@@ -0,0 +1,59 @@
+package discovery
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+func TestReporter(t *testing.T) {
+	procs := map[int32]*procutil.Process{
+		30: {Pid: 30, Ppid: 1, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: master process /usr/sbin/nginx"}},
+		31: {Pid: 31, Ppid: 30, Name: "nginx", Exe: "/usr/sbin/nginx", Cmdline: []string{"nginx: worker process"}},
+	}
+	var listErr error
+	listProcesses := func() (map[int32]*procutil.Process, error) {
+		return procs, listErr
+	}
+
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	r := newReporter(time.Hour, sender, "my-host", NewDiscoverer("/nonexistent"), listProcesses)
+	r.report()
+	listErr = errors.New("unreadable")
+	r.report()
+
+	messages := sender.Purge()[epforwarder.EventTypeProcessDiscovery]
+	require.Len(t, messages, 1)
+	var inventory Inventory
+	require.NoError(t, json.Unmarshal(messages[0].Content, &inventory))
+	assert.Equal(t, "my-host", inventory.Hostname)
+	assert.NotZero(t, inventory.Timestamp)
+	assert.Equal(t, []Service{{PID: 30, Name: "nginx", Server: "nginx"}}, inventory.Services)
+
+	assert.Equal(t, map[string]int64{
+		"inventories_sent":   1,
+		"inventories_errors": 1,
+	}, r.Stats())
+}
+
+func TestReporterStart(t *testing.T) {
+	listProcesses := func() (map[int32]*procutil.Process, error) {
+		return nil, nil
+	}
+
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	r := newReporter(time.Hour, sender, "my-host", NewDiscoverer("/nonexistent"), listProcesses)
+	r.Start()
+	// the inventory is sent once started, without waiting for the interval
+	require.Eventually(t, func() bool {
+		return r.Stats()["inventories_sent"] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	r.Stop()
+}
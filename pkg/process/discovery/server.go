@@ -0,0 +1,72 @@
+package discovery
+
+import "strings"
+
+// serversByExecutable maps the executables of well-known server software to their name
+var serversByExecutable = map[string]string{
+	"nginx":        "nginx",
+	"httpd":        "apache",
+	"apache2":      "apache",
+	"haproxy":      "haproxy",
+	"postgres":     "postgres",
+	"postmaster":   "postgres",
+	"mysqld":       "mysql",
+	"mariadbd":     "mysql",
+	"mongod":       "mongodb",
+	"redis-server": "redis",
+	"memcached":    "memcached",
+}
+
+// serversByJavaMainClass maps the main classes of well-known server software running on the JVM to their name
+var serversByJavaMainClass = map[string]string{
+	"kafka.Kafka": "kafka",
+	"org.apache.zookeeper.server.quorum.QuorumPeerMain": "zookeeper",
+	"org.elasticsearch.bootstrap.Elasticsearch":         "elasticsearch",
+	"org.apache.cassandra.service.CassandraDaemon":      "cassandra",
+}
+
+// javaOptionsWithValue are the options of the java command whose value is the following argument
+var javaOptionsWithValue = map[string]struct{}{
+	"-cp":            {},
+	"-classpath":     {},
+	"--class-path":   {},
+	"-p":             {},
+	"--module-path":  {},
+	"--add-modules":  {},
+	"--add-opens":    {},
+	"--add-exports":  {},
+	"--add-reads":    {},
+	"--patch-module": {},
+}
+
+// detectServer returns the name of the well-known server software run by a process, if any
+func detectServer(name string, cmdline []string) string {
+	if server, ok := serversByExecutable[name]; ok {
+		return server
+	}
+	if detectLanguage(name) == LanguageJava {
+		return serversByJavaMainClass[javaMainClass(cmdline)]
+	}
+	return ""
+}
+
+// javaMainClass returns the main class run by the java command, which is its first argument
+// which is not an option
+func javaMainClass(cmdline []string) string {
+	args := splitCmdline(cmdline)
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "-jar" {
+			// the main class is set by the manifest of the jar
+			return ""
+		}
+		if _, ok := javaOptionsWithValue[arg]; ok {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+	}
+	return ""
+}
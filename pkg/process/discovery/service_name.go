@@ -0,0 +1,32 @@
+package discovery
+
+import "strings"
+
+// serviceNameFlags are the flags the service name is read from, by order of priority
+var serviceNameFlags = []string{"-Ddd.service=", "-Dservice.name="}
+
+// serviceNameEnvVars are the environment variables the service name is read from, by order of priority
+var serviceNameEnvVars = []string{"DD_SERVICE", "OTEL_SERVICE_NAME"}
+
+// serviceNameFromCmdline returns the service name set by a flag of a command line, and the flag
+func serviceNameFromCmdline(cmdline []string) (string, string) {
+	args := splitCmdline(cmdline)
+	for _, flag := range serviceNameFlags {
+		for _, arg := range args {
+			if strings.HasPrefix(arg, flag) && len(arg) > len(flag) {
+				return arg[len(flag):], strings.TrimSuffix(flag, "=")
+			}
+		}
+	}
+	return "", ""
+}
+
+// serviceNameFromEnv returns the service name set by an environment variable, and the variable
+func serviceNameFromEnv(env map[string]string) (string, string) {
+	for _, name := range serviceNameEnvVars {
+		if value := env[name]; value != "" {
+			return value, name
+		}
+	}
+	return "", ""
+}
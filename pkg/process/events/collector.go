@@ -0,0 +1,169 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// eventsChanSize is the number of events buffered until they are batched
+	eventsChanSize = 1000
+
+	sendMaxRetries    = 5
+	sendRetryInterval = 100 * time.Millisecond
+)
+
+// source reports the lifecycle events of the processes of the host
+type source interface {
+	start(emit func(*Event)) error
+	stop()
+}
+
+// Collector collects process lifecycle events, and sends them in batches to the event platform
+type Collector struct {
+	// Telemetry is at the beginning of the struct to keep all fields 64-bit aligned.
+	// see https://staticcheck.io/docs/checks#SA1027
+	sent    int64
+	dropped int64
+
+	config   *Config
+	sender   epforwarder.EventPlatformForwarder
+	scrubber *config.DataScrubber
+	hostname string
+
+	source   source
+	eventsIn chan *Event
+	batch    []*Event
+	stopChan chan struct{}
+	stopped  sync.WaitGroup
+}
+
+// NewCollector returns a Collector sending the events through sender, after scrubbing their cmdline
+func NewCollector(cfg *Config, sender epforwarder.EventPlatformForwarder, scrubber *config.DataScrubber, hostname string) *Collector {
+	return &Collector{
+		config:   cfg,
+		sender:   sender,
+		scrubber: scrubber,
+		hostname: hostname,
+		eventsIn: make(chan *Event, eventsChanSize),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start starts listening to the lifecycle events of the processes, from the netlink process connector
+// if possible, or by polling /proc otherwise
+func (c *Collector) Start() error {
+	src, err := newSource(c.config)
+	if err != nil {
+		return err
+	}
+	return c.start(src)
+}
+
+func (c *Collector) start(src source) error {
+	c.source = src
+	c.stopped.Add(1)
+	go c.run()
+
+	if err := src.start(c.emit); err != nil {
+		close(c.stopChan)
+		c.stopped.Wait()
+		return err
+	}
+	return nil
+}
+
+// Stop stops the collection, and sends the events received so far
+func (c *Collector) Stop() {
+	c.source.stop()
+	close(c.stopChan)
+	c.stopped.Wait()
+}
+
+// Stats returns a map of counters, meant to be reported as telemetry
+func (c *Collector) Stats() map[string]int64 {
+	return map[string]int64{
+		"events_sent":    atomic.LoadInt64(&c.sent),
+		"events_dropped": atomic.LoadInt64(&c.dropped),
+	}
+}
+
+// emit queues an event, it is dropped instead of blocking the source when the batches can't be sent fast enough
+func (c *Collector) emit(e *Event) {
+	select {
+	case c.eventsIn <- e:
+	default:
+		atomic.AddInt64(&c.dropped, 1)
+	}
+}
+
+func (c *Collector) run() {
+	defer c.stopped.Done()
+
+	ticker := time.NewTicker(c.config.flushInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-c.eventsIn:
+			c.add(e)
+		case <-ticker.C:
+			c.flush()
+		case <-c.stopChan:
+			for len(c.eventsIn) > 0 {
+				c.add(<-c.eventsIn)
+			}
+			c.flush()
+			return
+		}
+	}
+}
+
+func (c *Collector) add(e *Event) {
+	e.Cmdline = c.scrubber.ScrubCmdline(e.Cmdline)
+	c.batch = append(c.batch, e)
+	if len(c.batch) >= c.config.MaxBatchSize {
+		c.flush()
+	}
+}
+
+// flush sends the batched events
+func (c *Collector) flush() {
+	if len(c.batch) == 0 {
+		return
+	}
+	batch := c.batch
+	c.batch = nil
+
+	payloadBytes, err := json.Marshal(Payload{Hostname: c.hostname, Events: batch})
+	if err != nil {
+		log.Errorf("Error marshalling process events: %s", err)
+		return
+	}
+	if err := c.send(&message.Message{Content: payloadBytes}); err != nil {
+		log.Warnf("Error sending process events, dropping %d events: %s", len(batch), err)
+		atomic.AddInt64(&c.dropped, int64(len(batch)))
+		return
+	}
+	atomic.AddInt64(&c.sent, int64(len(batch)))
+	log.Debugf("Flushed %d process events", len(batch))
+}
+
+// send sends a payload to the event platform forwarder, waiting for its pipeline to make room for it
+func (c *Collector) send(m *message.Message) error {
+	var err error
+	for i := 0; i < sendMaxRetries; i++ {
+		if err = c.sender.SendEventPlatformEvent(m, epforwarder.EventTypeProcessEvents); err == nil {
+			return nil
+		}
+		time.Sleep(sendRetryInterval)
+	}
+	return err
+}
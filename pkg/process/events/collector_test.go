@@ -0,0 +1,80 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+)
+
+// testSource emits the events it is given once started
+type testSource struct {
+	events []*Event
+}
+
+func (s *testSource) start(emit func(*Event)) error {
+	for _, e := range s.events {
+		emit(e)
+	}
+	return nil
+}
+
+func (s *testSource) stop() {}
+
+func purgePayloads(t *testing.T, sender epforwarder.EventPlatformForwarder) []Payload {
+	var payloads []Payload
+	for _, m := range sender.Purge()[epforwarder.EventTypeProcessEvents] {
+		var payload Payload
+		require.NoError(t, json.Unmarshal(m.Content, &payload))
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+func TestCollector(t *testing.T) {
+	exitCode := int32(1)
+	src := &testSource{events: []*Event{
+		{Type: EventTypeExec, Timestamp: 1000, Pid: 10, Ppid: 1, Cmdline: []string{"mysql", "--password", "secret"}},
+		{Type: EventTypeExec, Timestamp: 1001, Pid: 11, Ppid: 10, Cmdline: []string{"sh"}, ContainerID: "abc"},
+		{Type: EventTypeExit, Timestamp: 1500, Pid: 11, Ppid: 10, Cmdline: []string{"sh"}, ContainerID: "abc", ExitCode: &exitCode, Duration: 499},
+	}}
+
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	c := NewCollector(&Config{FlushInterval: 3600, MaxBatchSize: 2}, sender, config.NewDefaultDataScrubber(), "my-host")
+	require.NoError(t, c.start(src))
+	c.Stop()
+
+	payloads := purgePayloads(t, sender)
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "my-host", payloads[0].Hostname)
+	require.Len(t, payloads[0].Events, 2)
+	require.Len(t, payloads[1].Events, 1)
+
+	assert.Equal(t, []string{"mysql", "--password", "********"}, payloads[0].Events[0].Cmdline)
+	assert.Equal(t, &Event{
+		Type:        EventTypeExit,
+		Timestamp:   1500,
+		Pid:         11,
+		Ppid:        10,
+		Cmdline:     []string{"sh"},
+		ContainerID: "abc",
+		ExitCode:    &exitCode,
+		Duration:    499,
+	}, payloads[1].Events[0])
+
+	assert.Equal(t, map[string]int64{"events_sent": 3, "events_dropped": 0}, c.Stats())
+}
+
+func TestCollectorDropsEvents(t *testing.T) {
+	sender := epforwarder.NewNoopEventPlatformForwarder()
+	c := NewCollector(&Config{FlushInterval: 3600, MaxBatchSize: 10}, sender, config.NewDefaultDataScrubber(), "my-host")
+	// the events aren't read until the collector runs
+	for i := 0; i < eventsChanSize+5; i++ {
+		c.emit(&Event{Type: EventTypeExec, Pid: int32(i)})
+	}
+	assert.Equal(t, int64(5), c.Stats()["events_dropped"])
+}
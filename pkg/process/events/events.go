@@ -0,0 +1,93 @@
+package events
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// EventType is the type of a process lifecycle event
+type EventType string
+
+// Types of the process lifecycle events
+const (
+	EventTypeExec EventType = "exec"
+	EventTypeExit EventType = "exit"
+)
+
+const (
+	defaultPollInterval  = 5  // in seconds
+	defaultFlushInterval = 10 // in seconds
+	defaultMaxBatchSize  = 500
+)
+
+// Event is a process lifecycle event
+type Event struct {
+	Type EventType `json:"type"`
+	// Timestamp is the time of the event, in milliseconds since the epoch
+	Timestamp   int64    `json:"timestamp"`
+	Pid         int32    `json:"pid"`
+	Ppid        int32    `json:"ppid"`
+	Cmdline     []string `json:"cmdline,omitempty"`
+	ContainerID string   `json:"container_id,omitempty"`
+
+	// ExitCode is the exit code of a process which exited normally, and Signal the signal which
+	// killed a process. Both are only known when the events are read from the netlink process connector.
+	ExitCode *int32 `json:"exit_code,omitempty"`
+	Signal   int32  `json:"signal,omitempty"`
+	// Duration is the lifetime of an exited process, in milliseconds
+	Duration int64 `json:"duration_ms,omitempty"`
+}
+
+// Payload is a batch of events sent to the event platform
+type Payload struct {
+	Hostname string   `json:"hostname"`
+	Events   []*Event `json:"events"`
+}
+
+// IsEnabled returns whether the collection of process lifecycle events is enabled in the Agent configuration
+func IsEnabled() bool {
+	return config.Datadog.GetBool("process_config.event_collection.enabled")
+}
+
+// Config contains the configuration of the collection of process lifecycle events
+type Config struct {
+	// PollInterval is the interval, in seconds, at which /proc is polled for new and exited processes
+	// when the netlink process connector can't be used
+	PollInterval int `mapstructure:"poll_interval"`
+	// FlushInterval is the maximum time, in seconds, events are batched before being sent
+	FlushInterval int `mapstructure:"flush_interval"`
+	// MaxBatchSize is the maximum number of events sent in a payload
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+}
+
+// ReadConfig builds and returns the configuration from the Agent configuration
+func ReadConfig() (*Config, error) {
+	var c Config
+	if err := config.Datadog.UnmarshalKey("process_config.event_collection", &c); err != nil {
+		return nil, err
+	}
+
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = defaultFlushInterval
+	}
+	if c.MaxBatchSize <= 0 {
+		c.MaxBatchSize = defaultMaxBatchSize
+	}
+	return &c, nil
+}
+
+func (c *Config) pollInterval() time.Duration {
+	return time.Duration(c.PollInterval) * time.Second
+}
+
+func (c *Config) flushInterval() time.Duration {
+	return time.Duration(c.FlushInterval) * time.Second
+}
+
+func timestamp(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
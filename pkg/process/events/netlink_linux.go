@@ -0,0 +1,196 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Constants of the process events connector, see include/uapi/linux/cn_proc.h and connector.h
+const (
+	cnIdxProc = 0x1
+	cnValProc = 0x1
+
+	procCnMcastListen = 1
+	procCnMcastIgnore = 2
+
+	procEventFork = 0x00000001
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	// cnMsgSize is the size of struct cn_msg, which precedes its data
+	cnMsgSize = 20
+	// procEventHeaderSize is the size of the what, cpu and timestamp_ns fields of struct proc_event,
+	// which precede the event data
+	procEventHeaderSize = 16
+
+	netlinkReceiveBufferSize = 4096
+	netlinkReadTimeout       = time.Second
+)
+
+// netlinkSource reports the lifecycle events of the processes as they are sent by the kernel
+// on the netlink process connector, which requires CAP_NET_ADMIN
+type netlinkSource struct {
+	tracker *tracker
+	probe   *procutil.Probe
+	fd      int
+
+	stopChan chan struct{}
+	stopped  sync.WaitGroup
+}
+
+func newNetlinkSource(t *tracker, probe *procutil.Probe) (*netlinkSource, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_CONNECTOR)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create netlink connector socket: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("unable to bind to the process connector: %w", err)
+	}
+	tv := unix.NsecToTimeval(netlinkReadTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("unable to set the read timeout of the netlink connector socket: %w", err)
+	}
+
+	return &netlinkSource{
+		tracker:  t,
+		probe:    probe,
+		fd:       fd,
+		stopChan: make(chan struct{}),
+	}, nil
+}
+
+func (n *netlinkSource) start(emit func(*Event)) error {
+	// subscribe before seeding the processes, to miss none of them
+	if err := n.setListen(procCnMcastListen); err != nil {
+		unix.Close(n.fd)
+		return fmt.Errorf("unable to subscribe to the process connector: %w", err)
+	}
+	procs, err := n.probe.ProcessesByPID(time.Now())
+	if err != nil {
+		log.Warnf("Unable to list the running processes, their exit won't be fully reported: %s", err)
+	}
+	n.tracker.seed(procs)
+
+	n.stopped.Add(1)
+	go n.run(emit)
+	return nil
+}
+
+func (n *netlinkSource) stop() {
+	close(n.stopChan)
+	n.stopped.Wait()
+	_ = n.setListen(procCnMcastIgnore)
+	unix.Close(n.fd)
+	n.probe.Close()
+}
+
+// setListen sends a PROC_CN_MCAST_LISTEN or PROC_CN_MCAST_IGNORE operation to the process connector
+func (n *netlinkSource) setListen(op uint32) error {
+	native := nl.NativeEndian()
+	msg := make([]byte, unix.NLMSG_HDRLEN+cnMsgSize+4)
+
+	// struct nlmsghdr
+	native.PutUint32(msg[0:], uint32(len(msg)))
+	native.PutUint16(msg[4:], unix.NLMSG_DONE)
+	// struct cn_msg
+	cn := msg[unix.NLMSG_HDRLEN:]
+	native.PutUint32(cn[0:], cnIdxProc)
+	native.PutUint32(cn[4:], cnValProc)
+	native.PutUint16(cn[16:], 4)
+	// enum proc_cn_mcast_op
+	native.PutUint32(cn[cnMsgSize:], op)
+
+	return unix.Sendto(n.fd, msg, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+}
+
+func (n *netlinkSource) run(emit func(*Event)) {
+	defer n.stopped.Done()
+
+	buf := make([]byte, netlinkReceiveBufferSize)
+	for {
+		select {
+		case <-n.stopChan:
+			return
+		default:
+		}
+
+		size, from, err := unix.Recvfrom(n.fd, buf, 0)
+		if err != nil {
+			switch {
+			case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+			case errors.Is(err, unix.ENOBUFS):
+				log.Debugf("The process connector socket buffer overflowed, process events were lost")
+			default:
+				log.Warnf("Error reading from the process connector: %s", err)
+				time.Sleep(netlinkReadTimeout)
+			}
+			continue
+		}
+		// ignore the messages which weren't sent by the kernel
+		if sa, ok := from.(*unix.SockaddrNetlink); !ok || sa.Pid != 0 {
+			continue
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:size])
+		if err != nil {
+			continue
+		}
+		now := time.Now()
+		for _, m := range msgs {
+			if e := n.handleMessage(m.Data, now); e != nil {
+				emit(e)
+			}
+		}
+	}
+}
+
+// handleMessage decodes a struct cn_msg holding a struct proc_event, and returns the event it
+// reports, if any
+func (n *netlinkSource) handleMessage(data []byte, now time.Time) *Event {
+	native := nl.NativeEndian()
+	if len(data) < cnMsgSize+procEventHeaderSize+16 {
+		return nil
+	}
+	if native.Uint32(data[0:]) != cnIdxProc || native.Uint32(data[4:]) != cnValProc {
+		return nil
+	}
+	ev := data[cnMsgSize:]
+	what := native.Uint32(ev[0:])
+	fields := ev[procEventHeaderSize:]
+
+	switch what {
+	case procEventFork:
+		parentTgid := int32(native.Uint32(fields[4:]))
+		childPid := int32(native.Uint32(fields[8:]))
+		childTgid := int32(native.Uint32(fields[12:]))
+		// ignore the creation of threads
+		if childPid != childTgid {
+			return nil
+		}
+		n.tracker.fork(childTgid, parentTgid, now)
+	case procEventExec:
+		tgid := int32(native.Uint32(fields[4:]))
+		return n.tracker.exec(tgid, now)
+	case procEventExit:
+		pid := int32(native.Uint32(fields[0:]))
+		tgid := int32(native.Uint32(fields[4:]))
+		// ignore the exit of threads
+		if pid != tgid {
+			return nil
+		}
+		status := syscall.WaitStatus(native.Uint32(fields[8:]))
+		return n.tracker.exit(tgid, now, &status)
+	}
+	return nil
+}
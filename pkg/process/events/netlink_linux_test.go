@@ -0,0 +1,107 @@
+package events
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink/nl"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+// procEventMessage builds a struct cn_msg holding a struct proc_event with the given fields
+func procEventMessage(what uint32, fields ...uint32) []byte {
+	native := nl.NativeEndian()
+	data := make([]byte, cnMsgSize+procEventHeaderSize+24)
+	native.PutUint32(data[0:], cnIdxProc)
+	native.PutUint32(data[4:], cnValProc)
+	native.PutUint32(data[cnMsgSize:], what)
+	for i, f := range fields {
+		native.PutUint32(data[cnMsgSize+procEventHeaderSize+4*i:], f)
+	}
+	return data
+}
+
+func TestNetlinkHandleMessage(t *testing.T) {
+	tr := newTestTracker(t)
+	n := &netlinkSource{tracker: tr}
+	now := time.Unix(1000, 0)
+
+	// the creation of a thread is ignored
+	assert.Nil(t, n.handleMessage(procEventMessage(procEventFork, 10, 10, 21, 20), now))
+	assert.NotContains(t, tr.procs, int32(21))
+
+	assert.Nil(t, n.handleMessage(procEventMessage(procEventFork, 10, 10, 20, 20), now))
+	assert.Contains(t, tr.procs, int32(20))
+
+	e := n.handleMessage(procEventMessage(procEventExec, 20, 20), now)
+	require.NotNil(t, e)
+	assert.Equal(t, EventTypeExec, e.Type)
+	assert.Equal(t, int32(10), e.Ppid)
+	assert.Equal(t, []string{"python3", "-m", "http.server"}, e.Cmdline)
+
+	// the exit of a thread is ignored
+	assert.Nil(t, n.handleMessage(procEventMessage(procEventExit, 22, 20, 0, 0), now))
+
+	e = n.handleMessage(procEventMessage(procEventExit, 20, 20, 2<<8, 0), now.Add(time.Second))
+	require.NotNil(t, e)
+	assert.Equal(t, EventTypeExit, e.Type)
+	require.NotNil(t, e.ExitCode)
+	assert.Equal(t, int32(2), *e.ExitCode)
+	assert.Equal(t, int64(1000), e.Duration)
+
+	// messages of other connectors, or truncated ones, are ignored
+	other := procEventMessage(procEventExec, 20, 20)
+	other[0] = 2
+	assert.Nil(t, n.handleMessage(other, now))
+	assert.Nil(t, n.handleMessage(procEventMessage(procEventExec, 20, 20)[:cnMsgSize+4], now))
+}
+
+func TestNetlinkSource(t *testing.T) {
+	tr := newTracker("/proc", nil)
+	probe := procutil.NewProcessProbe()
+	src, err := newNetlinkSource(tr, probe)
+	if err != nil {
+		probe.Close()
+		t.Skipf("the process connector can't be used: %s", err)
+	}
+
+	events := make(chan *Event, 1000)
+	require.NoError(t, src.start(func(e *Event) {
+		events <- e
+	}))
+	defer src.stop()
+
+	cmd := exec.Command("/bin/sh", "-c", "sleep 0.2; exit 3")
+	err = cmd.Run()
+	require.Error(t, err)
+	pid := int32(cmd.Process.Pid)
+
+	var started, exited *Event
+	timeout := time.After(5 * time.Second)
+	for exited == nil {
+		select {
+		case e := <-events:
+			if e.Pid != pid {
+				continue
+			}
+			if e.Type == EventTypeExec {
+				started = e
+			} else {
+				exited = e
+			}
+		case <-timeout:
+			require.FailNow(t, "the exit of the process wasn't reported")
+		}
+	}
+
+	require.NotNil(t, started)
+	assert.Equal(t, []string{"/bin/sh", "-c", "sleep 0.2; exit 3"}, started.Cmdline)
+	require.NotNil(t, exited.ExitCode)
+	assert.Equal(t, int32(3), *exited.ExitCode)
+	assert.Equal(t, []string{"/bin/sh", "-c", "sleep 0.2; exit 3"}, exited.Cmdline)
+	assert.NotZero(t, exited.Duration)
+}
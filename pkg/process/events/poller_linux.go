@@ -0,0 +1,85 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// pollSource reports the lifecycle events of the processes by diffing the processes found in /proc
+// at each poll. Processes living less than the poll interval are missed, and the exit codes are unknown.
+type pollSource struct {
+	tracker  *tracker
+	probe    *procutil.Probe
+	interval time.Duration
+
+	stopChan chan struct{}
+	stopped  sync.WaitGroup
+}
+
+func newPollSource(t *tracker, probe *procutil.Probe, interval time.Duration) *pollSource {
+	return &pollSource{
+		tracker:  t,
+		probe:    probe,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+func (p *pollSource) start(emit func(*Event)) error {
+	procs, err := p.probe.ProcessesByPID(time.Now())
+	if err != nil {
+		return err
+	}
+	p.tracker.seed(procs)
+
+	p.stopped.Add(1)
+	go func() {
+		defer p.stopped.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				procs, err := p.probe.ProcessesByPID(now)
+				if err != nil {
+					log.Debugf("Unable to list the processes: %s", err)
+					continue
+				}
+				p.diff(procs, now, emit)
+			case <-p.stopChan:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (p *pollSource) stop() {
+	close(p.stopChan)
+	p.stopped.Wait()
+	p.probe.Close()
+}
+
+// diff reports the processes which exited since the previous poll, then the ones which started
+func (p *pollSource) diff(procs map[int32]*procutil.Process, now time.Time, emit func(*Event)) {
+	for pid, info := range p.tracker.procs {
+		proc, ok := procs[pid]
+		// the pid was reused by a new process
+		reused := ok && proc.Stats != nil && !info.start.IsZero() && proc.Stats.CreateTime != timestamp(info.start)
+		if !ok || reused {
+			emit(p.tracker.exit(pid, now, nil))
+		}
+	}
+	for pid, proc := range procs {
+		if _, ok := p.tracker.procs[pid]; ok {
+			continue
+		}
+		if e := p.tracker.started(pid, proc, now); e != nil {
+			emit(e)
+		}
+	}
+}
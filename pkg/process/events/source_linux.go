@@ -0,0 +1,23 @@
+package events
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/providers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// newSource returns a source reading the events from the netlink process connector, or polling
+// /proc when the connector can't be used
+func newSource(cfg *Config) (source, error) {
+	t := newTracker(util.HostProc(), providers.ContainerImpl().ContainerIDForPID)
+	probe := procutil.NewProcessProbe()
+
+	src, err := newNetlinkSource(t, probe)
+	if err == nil {
+		log.Info("Collecting process events from the netlink process connector")
+		return src, nil
+	}
+	log.Infof("Collecting process events by polling the processes every %s: %s", cfg.pollInterval(), err)
+	return newPollSource(t, probe, cfg.pollInterval()), nil
+}
@@ -0,0 +1,10 @@
+// +build !linux
+
+package events
+
+import "errors"
+
+// newSource returns an error, as the events of the processes are only collected on Linux
+func newSource(cfg *Config) (source, error) {
+	return nil, errors.New("process event collection is only supported on Linux")
+}
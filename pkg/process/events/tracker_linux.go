@@ -0,0 +1,196 @@
+package events
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+// maxTrackedProcesses bounds the number of processes whose details are kept until they exit
+const maxTrackedProcesses = 100000
+
+var errInvalidStat = errors.New("invalid stat file")
+
+// procInfo holds the details of a running process, reported again when it exits
+type procInfo struct {
+	ppid        int32
+	cmdline     []string
+	containerID string
+	start       time.Time
+}
+
+// tracker keeps the details of the running processes, and builds their lifecycle events.
+// It isn't safe for concurrent use.
+type tracker struct {
+	procRoot          string
+	containerIDForPID func(pid int) (string, error)
+	procs             map[int32]*procInfo
+}
+
+func newTracker(procRoot string, containerIDForPID func(pid int) (string, error)) *tracker {
+	return &tracker{
+		procRoot:          procRoot,
+		containerIDForPID: containerIDForPID,
+		procs:             make(map[int32]*procInfo),
+	}
+}
+
+// seed tracks the processes running before the collection started, without reporting them
+func (t *tracker) seed(procs map[int32]*procutil.Process) {
+	for pid, proc := range procs {
+		t.track(pid, t.newProcInfo(pid, proc))
+	}
+}
+
+func (t *tracker) newProcInfo(pid int32, proc *procutil.Process) *procInfo {
+	info := &procInfo{
+		ppid:        proc.Ppid,
+		cmdline:     proc.Cmdline,
+		containerID: t.containerID(pid),
+	}
+	if proc.Stats != nil {
+		info.start = time.Unix(0, proc.Stats.CreateTime*int64(time.Millisecond))
+	}
+	return info
+}
+
+// track keeps the details of a process, it returns false if too many processes are tracked already
+func (t *tracker) track(pid int32, info *procInfo) bool {
+	if _, ok := t.procs[pid]; !ok && len(t.procs) >= maxTrackedProcesses {
+		return false
+	}
+	t.procs[pid] = info
+	return true
+}
+
+// started returns the exec event of a process found running, or nil if it can't be tracked
+func (t *tracker) started(pid int32, proc *procutil.Process, now time.Time) *Event {
+	info := t.newProcInfo(pid, proc)
+	if !t.track(pid, info) {
+		return nil
+	}
+	if info.start.IsZero() {
+		info.start = now
+	}
+	return execEvent(pid, info, info.start)
+}
+
+// fork tracks a process forked by ppid, which runs the command of its parent until it execs
+func (t *tracker) fork(pid, ppid int32, now time.Time) {
+	info := &procInfo{ppid: ppid, start: now}
+	if parent, ok := t.procs[ppid]; ok {
+		info.cmdline = parent.cmdline
+		info.containerID = parent.containerID
+	}
+	t.track(pid, info)
+}
+
+// exec returns the event of a process executing a new command
+func (t *tracker) exec(pid int32, now time.Time) *Event {
+	info, ok := t.procs[pid]
+	if !ok {
+		// the fork of the process wasn't seen
+		info = &procInfo{start: now}
+		info.ppid, _ = t.readPpid(pid)
+		info.containerID = t.containerID(pid)
+		t.track(pid, info)
+	}
+	// the process may already have exited, in which case we keep the command of its parent
+	if cmdline, err := t.readCmdline(pid); err == nil && len(cmdline) > 0 {
+		info.cmdline = cmdline
+	}
+	return execEvent(pid, info, now)
+}
+
+func execEvent(pid int32, info *procInfo, ts time.Time) *Event {
+	return &Event{
+		Type:        EventTypeExec,
+		Timestamp:   timestamp(ts),
+		Pid:         pid,
+		Ppid:        info.ppid,
+		Cmdline:     info.cmdline,
+		ContainerID: info.containerID,
+	}
+}
+
+// exit returns the event of a process exiting, with its wait status if it's known
+func (t *tracker) exit(pid int32, now time.Time, status *syscall.WaitStatus) *Event {
+	e := &Event{
+		Type:      EventTypeExit,
+		Timestamp: timestamp(now),
+		Pid:       pid,
+	}
+	if info, ok := t.procs[pid]; ok {
+		delete(t.procs, pid)
+		e.Ppid = info.ppid
+		e.Cmdline = info.cmdline
+		e.ContainerID = info.containerID
+		if !info.start.IsZero() && now.After(info.start) {
+			e.Duration = int64(now.Sub(info.start) / time.Millisecond)
+		}
+	}
+	if status != nil {
+		if status.Signaled() {
+			e.Signal = int32(status.Signal())
+		} else {
+			exitCode := int32(status.ExitStatus())
+			e.ExitCode = &exitCode
+		}
+	}
+	return e
+}
+
+func (t *tracker) containerID(pid int32) string {
+	if t.containerIDForPID == nil {
+		return ""
+	}
+	id, err := t.containerIDForPID(int(pid))
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+func (t *tracker) readCmdline(pid int32) ([]string, error) {
+	content, err := ioutil.ReadFile(filepath.Join(t.procRoot, strconv.Itoa(int(pid)), "cmdline"))
+	if err != nil {
+		return nil, err
+	}
+	content = bytes.TrimRight(content, "\x00")
+	if len(content) == 0 {
+		return nil, nil
+	}
+	var cmdline []string
+	for _, arg := range bytes.Split(content, []byte{0}) {
+		cmdline = append(cmdline, string(arg))
+	}
+	return cmdline, nil
+}
+
+// readPpid reads the parent of a process from /proc/<pid>/stat, the command in the second field
+// being enclosed in parentheses, and possibly containing spaces
+func (t *tracker) readPpid(pid int32) (int32, error) {
+	content, err := ioutil.ReadFile(filepath.Join(t.procRoot, strconv.Itoa(int(pid)), "stat"))
+	if err != nil {
+		return 0, err
+	}
+	i := bytes.LastIndexByte(content, ')')
+	if i < 0 {
+		return 0, errInvalidStat
+	}
+	fields := bytes.Fields(content[i+1:])
+	if len(fields) < 2 {
+		return 0, errInvalidStat
+	}
+	ppid, err := strconv.ParseInt(string(fields[1]), 10, 32)
+	if err != nil {
+		return 0, errInvalidStat
+	}
+	return int32(ppid), nil
+}
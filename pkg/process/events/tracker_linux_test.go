@@ -0,0 +1,139 @@
+package events
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+func writeProcFile(t *testing.T, procRoot string, pid, name, content string) {
+	dir := filepath.Join(procRoot, pid)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func newTestTracker(t *testing.T) *tracker {
+	procRoot := t.TempDir()
+	writeProcFile(t, procRoot, "20", "cmdline", "python3\x00-m\x00http.server\x00")
+	writeProcFile(t, procRoot, "30", "cmdline", "curl\x00example.com\x00")
+	writeProcFile(t, procRoot, "30", "stat", "30 (my (weird) cmd) S 12 30 30 0 -1 4194304 100 0 0 0")
+
+	containers := map[int]string{10: "ctr1", 30: "ctr2"}
+	return newTracker(procRoot, func(pid int) (string, error) {
+		return containers[pid], nil
+	})
+}
+
+func TestTrackerForkExecExit(t *testing.T) {
+	tr := newTestTracker(t)
+	start := time.Unix(1000, 0)
+	tr.seed(map[int32]*procutil.Process{
+		10: {Pid: 10, Ppid: 1, Cmdline: []string{"bash"}, Stats: &procutil.Stats{CreateTime: 500000}},
+	})
+
+	tr.fork(20, 10, start)
+	e := tr.exec(20, start.Add(time.Millisecond))
+	assert.Equal(t, &Event{
+		Type:        EventTypeExec,
+		Timestamp:   1000001,
+		Pid:         20,
+		Ppid:        10,
+		Cmdline:     []string{"python3", "-m", "http.server"},
+		ContainerID: "ctr1",
+	}, e)
+
+	status := syscall.WaitStatus(3 << 8)
+	e = tr.exit(20, start.Add(2*time.Second), &status)
+	exitCode := int32(3)
+	assert.Equal(t, &Event{
+		Type:        EventTypeExit,
+		Timestamp:   1002000,
+		Pid:         20,
+		Ppid:        10,
+		Cmdline:     []string{"python3", "-m", "http.server"},
+		ContainerID: "ctr1",
+		ExitCode:    &exitCode,
+		Duration:    2000,
+	}, e)
+	assert.NotContains(t, tr.procs, int32(20))
+
+	// killed by a signal, the seeded process lived since its creation time
+	status = syscall.WaitStatus(syscall.SIGKILL)
+	e = tr.exit(10, start, &status)
+	assert.Nil(t, e.ExitCode)
+	assert.Equal(t, int32(syscall.SIGKILL), e.Signal)
+	assert.Equal(t, int64(500000), e.Duration)
+	assert.Equal(t, []string{"bash"}, e.Cmdline)
+}
+
+func TestTrackerForkWithoutExec(t *testing.T) {
+	tr := newTestTracker(t)
+	tr.seed(map[int32]*procutil.Process{
+		10: {Pid: 10, Ppid: 1, Cmdline: []string{"bash"}},
+	})
+
+	// a forked process runs the command of its parent
+	tr.fork(40, 10, time.Unix(1000, 0))
+	e := tr.exit(40, time.Unix(1001, 0), nil)
+	assert.Equal(t, []string{"bash"}, e.Cmdline)
+	assert.Equal(t, int32(10), e.Ppid)
+	assert.Equal(t, "ctr1", e.ContainerID)
+	assert.Nil(t, e.ExitCode)
+}
+
+func TestTrackerExecWithoutFork(t *testing.T) {
+	tr := newTestTracker(t)
+
+	e := tr.exec(30, time.Unix(1000, 0))
+	assert.Equal(t, int32(12), e.Ppid)
+	assert.Equal(t, []string{"curl", "example.com"}, e.Cmdline)
+	assert.Equal(t, "ctr2", e.ContainerID)
+
+	// the exit of an unknown process is still reported
+	e = tr.exit(50, time.Unix(1000, 0), nil)
+	assert.Equal(t, &Event{Type: EventTypeExit, Timestamp: 1000000, Pid: 50}, e)
+}
+
+func TestPollSourceDiff(t *testing.T) {
+	tr := newTestTracker(t)
+	tr.seed(map[int32]*procutil.Process{
+		10: {Pid: 10, Ppid: 1, Cmdline: []string{"bash"}, Stats: &procutil.Stats{CreateTime: 500000}},
+		11: {Pid: 11, Ppid: 1, Cmdline: []string{"sleep", "10"}, Stats: &procutil.Stats{CreateTime: 600000}},
+		12: {Pid: 12, Ppid: 1, Cmdline: []string{"old"}, Stats: &procutil.Stats{CreateTime: 600000}},
+	})
+	p := newPollSource(tr, nil, time.Second)
+
+	var events []*Event
+	p.diff(map[int32]*procutil.Process{
+		10: {Pid: 10, Ppid: 1, Cmdline: []string{"bash"}, Stats: &procutil.Stats{CreateTime: 500000}},
+		// the pid was reused
+		12: {Pid: 12, Ppid: 10, Cmdline: []string{"new"}, Stats: &procutil.Stats{CreateTime: 900000}},
+		13: {Pid: 13, Ppid: 10, Cmdline: []string{"ls"}, Stats: &procutil.Stats{CreateTime: 950000}},
+	}, time.Unix(1000, 0), func(e *Event) {
+		events = append(events, e)
+	})
+
+	require.Len(t, events, 4)
+	exits, execs := events[:2], events[2:]
+	if exits[0].Pid != 11 {
+		exits[0], exits[1] = exits[1], exits[0]
+	}
+	if execs[0].Pid != 12 {
+		execs[0], execs[1] = execs[1], execs[0]
+	}
+
+	assert.Equal(t, &Event{Type: EventTypeExit, Timestamp: 1000000, Pid: 11, Ppid: 1, Cmdline: []string{"sleep", "10"}, Duration: 400000}, exits[0])
+	assert.Equal(t, int32(12), exits[1].Pid)
+	assert.Equal(t, []string{"old"}, exits[1].Cmdline)
+	assert.Equal(t, &Event{Type: EventTypeExec, Timestamp: 900000, Pid: 12, Ppid: 10, Cmdline: []string{"new"}}, execs[0])
+	assert.Equal(t, &Event{Type: EventTypeExec, Timestamp: 950000, Pid: 13, Ppid: 10, Cmdline: []string{"ls"}}, execs[1])
+	assert.Len(t, tr.procs, 3)
+}
@@ -7,6 +7,7 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/DataDog/datadog-agent/cmd/system-probe/config"
@@ -86,6 +87,30 @@ type Config struct {
 	LogPatterns []string
 	// SelfTestEnabled defines if the self tester should be enabled (useful for tests for example)
 	SelfTestEnabled bool
+	// ActiveResponseEnabled defines whether the actions declared by the rules are executed
+	ActiveResponseEnabled bool
+	// ActiveResponseDryRun defines whether the actions are only reported without being executed
+	ActiveResponseDryRun bool
+	// ActiveResponseRateLimit defines the maximum number of actions executed per rule and per minute
+	ActiveResponseRateLimit int
+	// ActiveResponseProtectedPIDs defines the PIDs that can never be targeted by an action
+	ActiveResponseProtectedPIDs []uint32
+	// ActiveResponseProtectedBinaries defines the binaries whose processes can never be targeted by an action
+	ActiveResponseProtectedBinaries []string
+	// RecordEventsFile defines the file where the events are recorded, using the serialized event format, to be replayed offline
+	RecordEventsFile string
+	// ActivityProfilesEnabled defines whether activity profiles are learned per container image to detect anomalies
+	ActivityProfilesEnabled bool
+	// ActivityProfilesDir defines the folder in which the activity profiles are persisted
+	ActivityProfilesDir string
+	// ActivityProfilesLearningPeriod defines how long a profile learns the activity of an image before being stable
+	ActivityProfilesLearningPeriod time.Duration
+	// ActivityProfilesPathDepth defines the number of path components of the opened files kept in a profile
+	ActivityProfilesPathDepth int
+	// ActivityProfilesMaxEntries defines the maximum number of entries of each kind learned per profile
+	ActivityProfilesMaxEntries int
+	// ActivityProfilesMaxProfiles defines the maximum number of profiles
+	ActivityProfilesMaxProfiles int
 }
 
 // IsEnabled returns true if any feature is enabled. Has to be applied in config package too
@@ -125,6 +150,17 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		RemoteTaggerEnabled:                aconfig.Datadog.GetBool("runtime_security_config.remote_tagger"),
 		LogPatterns:                        aconfig.Datadog.GetStringSlice("runtime_security_config.log_patterns"),
 		SelfTestEnabled:                    aconfig.Datadog.GetBool("runtime_security_config.self_test.enabled"),
+		ActiveResponseEnabled:              aconfig.Datadog.GetBool("runtime_security_config.active_response.enabled"),
+		ActiveResponseDryRun:               aconfig.Datadog.GetBool("runtime_security_config.active_response.dry_run"),
+		ActiveResponseRateLimit:            aconfig.Datadog.GetInt("runtime_security_config.active_response.rate_limit"),
+		ActiveResponseProtectedBinaries:    aconfig.Datadog.GetStringSlice("runtime_security_config.active_response.protected_binaries"),
+		RecordEventsFile:                   aconfig.Datadog.GetString("runtime_security_config.record_events.file"),
+		ActivityProfilesEnabled:            aconfig.Datadog.GetBool("runtime_security_config.activity_profiles.enabled"),
+		ActivityProfilesDir:                aconfig.Datadog.GetString("runtime_security_config.activity_profiles.dir"),
+		ActivityProfilesLearningPeriod:     time.Duration(aconfig.Datadog.GetInt("runtime_security_config.activity_profiles.learning_period")) * time.Second,
+		ActivityProfilesPathDepth:          aconfig.Datadog.GetInt("runtime_security_config.activity_profiles.path_depth"),
+		ActivityProfilesMaxEntries:         aconfig.Datadog.GetInt("runtime_security_config.activity_profiles.max_entries"),
+		ActivityProfilesMaxProfiles:        aconfig.Datadog.GetInt("runtime_security_config.activity_profiles.max_profiles"),
 	}
 
 	// if runtime is enabled then we force fim
@@ -152,6 +188,14 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		c.MapDentryResolutionEnabled = true
 	}
 
+	for _, value := range aconfig.Datadog.GetStringSlice("runtime_security_config.active_response.protected_pids") {
+		pid, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protected pid `%s`: %w", value, err)
+		}
+		c.ActiveResponseProtectedPIDs = append(c.ActiveResponseProtectedPIDs, uint32(pid))
+	}
+
 	serviceName := utils.GetTagValue("service", aconfig.GetConfiguredTags(true))
 	if len(serviceName) > 0 {
 		c.HostServiceName = fmt.Sprintf("service:%s", serviceName)
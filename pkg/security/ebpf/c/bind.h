@@ -0,0 +1,73 @@
+#ifndef _BIND_H_
+#define _BIND_H_
+
+#include "syscalls.h"
+#include "network.h"
+
+struct bind_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct addr_t addr;
+};
+
+SYSCALL_KPROBE0(bind) {
+    struct syscall_cache_t syscall = {
+        .type = EVENT_BIND,
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_bind")
+int kprobe_security_socket_bind(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_BIND);
+    if (!syscall)
+        return 0;
+
+    struct sockaddr *address = (struct sockaddr *)PT_REGS_PARM2(ctx);
+    fill_addr(&syscall->bind.addr, address);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_bind_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_BIND);
+    if (!syscall)
+        return 0;
+
+    if (retval < 0)
+        return 0;
+
+    if (!is_supported_family(syscall->bind.addr.family))
+        return 0;
+
+    struct bind_event_t event = {
+        .syscall.retval = retval,
+        .addr = syscall->bind.addr,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+
+    send_event(ctx, EVENT_BIND, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(bind) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_bind_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_bind")
+int tracepoint_syscalls_sys_exit_bind(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_bind_ret(args, args->ret);
+}
+
+SEC("tracepoint/handle_sys_bind_exit")
+int tracepoint_handle_sys_bind_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_bind_ret(args, args->ret);
+}
+
+#endif
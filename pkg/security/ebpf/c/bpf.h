@@ -0,0 +1,77 @@
+#ifndef _BPF_H_
+#define _BPF_H_
+
+#include "syscalls.h"
+
+struct bpf_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    u32 cmd;
+    u32 prog_type;
+    char prog_name[BPF_OBJ_NAME_LEN];
+};
+
+SYSCALL_KPROBE3(bpf, int, cmd, union bpf_attr *, uattr, unsigned int, size) {
+    // only program loads are reported
+    if (cmd != BPF_PROG_LOAD)
+        return 0;
+
+    struct policy_t policy = fetch_policy(EVENT_BPF);
+    if (is_discarded_by_process(policy.mode, EVENT_BPF)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_BPF,
+        .policy = policy,
+        .bpf = {
+            .cmd = cmd,
+        },
+    };
+    bpf_probe_read(&syscall.bpf.prog_type, sizeof(syscall.bpf.prog_type), &uattr->prog_type);
+    bpf_probe_read(&syscall.bpf.prog_name, sizeof(syscall.bpf.prog_name), &uattr->prog_name);
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_bpf_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_BPF);
+    if (!syscall)
+        return 0;
+
+    if (IS_UNHANDLED_ERROR(retval))
+        return 0;
+
+    struct bpf_event_t event = {
+        .syscall.retval = retval,
+        .cmd = syscall->bpf.cmd,
+        .prog_type = syscall->bpf.prog_type,
+    };
+    bpf_probe_read(&event.prog_name, sizeof(event.prog_name), &syscall->bpf.prog_name);
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+
+    send_event(ctx, EVENT_BPF, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(bpf) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_bpf_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_bpf")
+int tracepoint_syscalls_sys_exit_bpf(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_bpf_ret(args, args->ret);
+}
+
+SEC("tracepoint/handle_sys_bpf_exit")
+int tracepoint_handle_sys_bpf_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_bpf_ret(args, args->ret);
+}
+
+#endif
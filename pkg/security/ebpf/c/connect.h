@@ -0,0 +1,74 @@
+#ifndef _CONNECT_H_
+#define _CONNECT_H_
+
+#include "syscalls.h"
+#include "network.h"
+
+struct connect_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct addr_t addr;
+};
+
+SYSCALL_KPROBE0(connect) {
+    struct syscall_cache_t syscall = {
+        .type = EVENT_CONNECT,
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_connect")
+int kprobe_security_socket_connect(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_CONNECT);
+    if (!syscall)
+        return 0;
+
+    struct sockaddr *address = (struct sockaddr *)PT_REGS_PARM2(ctx);
+    fill_addr(&syscall->connect.addr, address);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_connect_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_CONNECT);
+    if (!syscall)
+        return 0;
+
+    // non-blocking sockets return EINPROGRESS while the connection is being established
+    if (retval < 0 && retval != -EINPROGRESS)
+        return 0;
+
+    if (!is_supported_family(syscall->connect.addr.family))
+        return 0;
+
+    struct connect_event_t event = {
+        .syscall.retval = retval,
+        .addr = syscall->connect.addr,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+
+    send_event(ctx, EVENT_CONNECT, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(connect) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_connect_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_connect")
+int tracepoint_syscalls_sys_exit_connect(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_connect_ret(args, args->ret);
+}
+
+SEC("tracepoint/handle_sys_connect_exit")
+int tracepoint_handle_sys_connect_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_connect_ret(args, args->ret);
+}
+
+#endif
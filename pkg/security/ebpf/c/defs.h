@@ -212,6 +212,14 @@ enum event_type
     EVENT_ARGS_ENVS,
     EVENT_MOUNT_RELEASED,
     EVENT_SELINUX,
+    EVENT_CONNECT,
+    EVENT_BIND,
+    EVENT_DNS,
+    EVENT_LOAD_MODULE,
+    EVENT_PTRACE,
+    EVENT_MMAP,
+    EVENT_MPROTECT,
+    EVENT_BPF,
     EVENT_MAX, // has to be the last one
 };
 
@@ -375,6 +383,27 @@ struct bpf_map_def SEC("maps/events_stats") events_stats = {
         }                                                                                                              \
     }                                                                                                                  \
 
+// send_event_ptr is the same as send_event for events allocated in a map
+#define send_event_ptr(ctx, event_type, kernel_event)                                                                  \
+    kernel_event->event.type = event_type;                                                                             \
+    kernel_event->event.cpu = bpf_get_smp_processor_id();                                                              \
+    kernel_event->event.timestamp = bpf_ktime_get_ns();                                                                \
+                                                                                                                       \
+    u64 size = sizeof(*kernel_event);                                                                                  \
+    int perf_ret = bpf_perf_event_output(ctx, &events, kernel_event->event.cpu, kernel_event, size);                   \
+                                                                                                                       \
+    if (kernel_event->event.type < EVENT_MAX) {                                                                        \
+        struct perf_map_stats_t *stats = bpf_map_lookup_elem(&events_stats, &kernel_event->event.type);                \
+        if (stats != NULL) {                                                                                           \
+            if (!perf_ret) {                                                                                           \
+                __sync_fetch_and_add(&stats->bytes, size + 4);                                                         \
+                __sync_fetch_and_add(&stats->count, 1);                                                                \
+            } else {                                                                                                   \
+                __sync_fetch_and_add(&stats->lost, 1);                                                                 \
+            }                                                                                                          \
+        }                                                                                                              \
+    }                                                                                                                  \
+
 
 // implemented in the discarder.h file
 int __attribute__((always_inline)) bump_discarder_revision(u32 mount_id);
@@ -507,7 +536,7 @@ static __attribute__((always_inline)) u64 get_enabled_events(void) {
 }
 
 static __attribute__((always_inline)) int mask_has_event(u64 mask, enum event_type event) {
-    return mask & (1 << (event-EVENT_FIRST_DISCARDER));
+    return mask & (1ULL << (event-EVENT_FIRST_DISCARDER));
 }
 
 static __attribute__((always_inline)) int is_event_enabled(enum event_type event) {
@@ -515,7 +544,7 @@ static __attribute__((always_inline)) int is_event_enabled(enum event_type event
 }
 
 static __attribute__((always_inline)) void add_event_to_mask(u64 *mask, enum event_type event) {
-    *mask |= 1 << (event - EVENT_FIRST_DISCARDER);
+    *mask |= 1ULL << (event - EVENT_FIRST_DISCARDER);
 }
 
 #endif
@@ -0,0 +1,119 @@
+#ifndef _DNS_H_
+#define _DNS_H_
+
+#include "syscalls.h"
+#include "network.h"
+
+#define DNS_PORT 53
+#define DNS_HEADER_LENGTH 12
+#define DNS_MAX_LENGTH 256
+
+struct dns_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    u16 size;
+    u16 padding[3];
+    char payload[DNS_MAX_LENGTH];
+};
+
+// the DNS payload doesn't fit on the stack
+struct bpf_map_def SEC("maps/dns_event") dns_event = {
+    .type = BPF_MAP_TYPE_PERCPU_ARRAY,
+    .key_size = sizeof(u32),
+    .value_size = sizeof(struct dns_event_t),
+    .max_entries = 1,
+    .pinning = 0,
+    .namespace = "",
+};
+
+u16 __attribute__((always_inline)) get_sendmsg_dport(struct socket *sock, struct msghdr *msg) {
+    u16 port = 0;
+
+    struct sockaddr *address = NULL;
+    bpf_probe_read(&address, sizeof(address), &msg->msg_name);
+    if (address) {
+        struct addr_t addr = {};
+        fill_addr(&addr, address);
+        return addr.port;
+    }
+
+    // connected socket
+    struct sock *sk = NULL;
+    bpf_probe_read(&sk, sizeof(sk), &sock->sk);
+    if (sk) {
+        bpf_probe_read(&port, sizeof(port), &sk->__sk_common.skc_dport);
+    }
+    return port;
+}
+
+// get_sendmsg_buffer returns the first buffer of the message, it holds the whole
+// request for the usual resolvers
+void __attribute__((always_inline)) *get_sendmsg_buffer(struct msghdr *msg) {
+    // the iovec pointer shares its union with the user buffer of ITER_UBUF iterators,
+    // used by the kernels >= 6.0 for single buffer writes such as send and sendto
+    void *iov = NULL;
+    bpf_probe_read(&iov, sizeof(iov), &msg->msg_iter.iov);
+    if (!iov)
+        return NULL;
+
+    u64 iter_type_ubuf;
+    LOAD_CONSTANT("iter_type_ubuf", iter_type_ubuf);
+
+    // the iterator type is the first byte of the iterator, on all the kernel versions
+    u8 iter_type = 0;
+    bpf_probe_read(&iter_type, sizeof(iter_type), &msg->msg_iter);
+    if (iter_type == iter_type_ubuf)
+        return iov;
+
+    void *base = NULL;
+    bpf_probe_read(&base, sizeof(base), &((const struct iovec *)iov)->iov_base);
+    return base;
+}
+
+SEC("kprobe/security_socket_sendmsg")
+int kprobe_security_socket_sendmsg(struct pt_regs *ctx) {
+    if (!is_event_enabled(EVENT_DNS))
+        return 0;
+
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct msghdr *msg = (struct msghdr *)PT_REGS_PARM2(ctx);
+    int size = (int)PT_REGS_PARM3(ctx);
+
+    if (size < DNS_HEADER_LENGTH)
+        return 0;
+
+    short type = 0;
+    bpf_probe_read(&type, sizeof(type), &sock->type);
+    if (type != SOCK_DGRAM)
+        return 0;
+
+    if (get_sendmsg_dport(sock, msg) != bpf_htons(DNS_PORT))
+        return 0;
+
+    void *base = get_sendmsg_buffer(msg);
+    if (!base)
+        return 0;
+
+    u32 key = 0;
+    struct dns_event_t *event = bpf_map_lookup_elem(&dns_event, &key);
+    if (!event)
+        return 0;
+
+    event->size = size;
+
+    u32 len = size;
+    if (len > DNS_MAX_LENGTH)
+        len = DNS_MAX_LENGTH;
+
+    if (bpf_probe_read(&event->payload, len, base) < 0)
+        return 0;
+
+    struct proc_cache_t *entry = fill_process_context(&event->process);
+    fill_container_context(entry, &event->container);
+
+    send_event_ptr(ctx, EVENT_DNS, event);
+    return 0;
+}
+
+#endif
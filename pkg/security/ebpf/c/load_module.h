@@ -0,0 +1,141 @@
+#ifndef _LOAD_MODULE_H_
+#define _LOAD_MODULE_H_
+
+#include "syscalls.h"
+
+struct init_module_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct file_t file;
+    char name[MODULE_NAME_LEN];
+    u32 loaded_from_memory;
+    u32 padding;
+};
+
+int __attribute__((always_inline)) trace_init_module(u32 loaded_from_memory) {
+    struct policy_t policy = fetch_policy(EVENT_LOAD_MODULE);
+    if (is_discarded_by_process(policy.mode, EVENT_LOAD_MODULE)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_LOAD_MODULE,
+        .policy = policy,
+        .init_module = {
+            .loaded_from_memory = loaded_from_memory,
+        },
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SYSCALL_KPROBE0(init_module) {
+    return trace_init_module(1);
+}
+
+SYSCALL_KPROBE0(finit_module) {
+    return trace_init_module(0);
+}
+
+// security_kernel_read_file is called by finit_module with the file holding the module
+SEC("kprobe/security_kernel_read_file")
+int kprobe_security_kernel_read_file(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_LOAD_MODULE);
+    if (!syscall)
+        return 0;
+
+    struct file *file = (struct file *)PT_REGS_PARM1(ctx);
+    if (!file || syscall->init_module.file.path_key.ino)
+        return 0;
+
+    syscall->init_module.dentry = get_file_dentry(file);
+    syscall->init_module.file.path_key.mount_id = get_file_mount_id(file);
+    set_file_inode(syscall->init_module.dentry, &syscall->init_module.file, 0);
+    fill_file_metadata(syscall->init_module.dentry, &syscall->init_module.file.metadata);
+
+    syscall->resolver.key = syscall->init_module.file.path_key;
+    syscall->resolver.dentry = syscall->init_module.dentry;
+    syscall->resolver.discarder_type = syscall->policy.mode != NO_FILTER ? EVENT_LOAD_MODULE : 0;
+    syscall->resolver.callback = DR_LOAD_MODULE_CALLBACK_KPROBE_KEY;
+    syscall->resolver.iteration = 0;
+    syscall->resolver.ret = 0;
+
+    resolve_dentry(ctx, DR_KPROBE);
+    return 0;
+}
+
+SEC("kprobe/dr_load_module_callback")
+int __attribute__((always_inline)) kprobe_dr_load_module_callback(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_LOAD_MODULE);
+    if (!syscall)
+        return 0;
+
+    if (syscall->resolver.ret == DENTRY_DISCARDED) {
+        return discard_syscall(syscall);
+    }
+
+    return 0;
+}
+
+SEC("kprobe/do_init_module")
+int kprobe_do_init_module(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_LOAD_MODULE);
+    if (!syscall)
+        return 0;
+
+    struct module *mod = (struct module *)PT_REGS_PARM1(ctx);
+    bpf_probe_read_str(&syscall->init_module.name, sizeof(syscall->init_module.name), &mod->name);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_init_module_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_LOAD_MODULE);
+    if (!syscall)
+        return 0;
+
+    if (IS_UNHANDLED_ERROR(retval))
+        return 0;
+
+    struct init_module_event_t event = {
+        .syscall.retval = retval,
+        .file = syscall->init_module.file,
+        .loaded_from_memory = syscall->init_module.loaded_from_memory,
+    };
+    bpf_probe_read_str(&event.name, sizeof(event.name), &syscall->init_module.name);
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+
+    send_event(ctx, EVENT_LOAD_MODULE, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(init_module) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_init_module_ret(ctx, retval);
+}
+
+SYSCALL_KRETPROBE(finit_module) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_init_module_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_init_module")
+int tracepoint_syscalls_sys_exit_init_module(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_init_module_ret(args, args->ret);
+}
+
+SEC("tracepoint/syscalls/sys_exit_finit_module")
+int tracepoint_syscalls_sys_exit_finit_module(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_init_module_ret(args, args->ret);
+}
+
+SEC("tracepoint/handle_sys_init_module_exit")
+int tracepoint_handle_sys_init_module_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_init_module_ret(args, args->ret);
+}
+
+#endif
@@ -0,0 +1,128 @@
+#ifndef _MMAP_H_
+#define _MMAP_H_
+
+#include <linux/mman.h>
+
+#include "syscalls.h"
+
+struct mmap_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct file_t file;
+    u64 addr;
+    u64 offset;
+    u32 len;
+    int protection;
+    int flags;
+    u32 padding;
+};
+
+SYSCALL_KPROBE6(mmap, void *, addr, size_t, len, int, protection, int, flags, int, fd, off_t, offset) {
+    struct policy_t policy = fetch_policy(EVENT_MMAP);
+    if (is_discarded_by_process(policy.mode, EVENT_MMAP)) {
+        return 0;
+    }
+
+    // only executable mappings are reported
+    if (!(protection & PROT_EXEC)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_MMAP,
+        .policy = policy,
+        .mmap = {
+            .offset = offset,
+            .len = len,
+            .protection = protection,
+            .flags = flags,
+        },
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+// security_mmap_file is called with the file of file backed mappings
+SEC("kprobe/security_mmap_file")
+int kprobe_security_mmap_file(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_MMAP);
+    if (!syscall)
+        return 0;
+
+    struct file *file = (struct file *)PT_REGS_PARM1(ctx);
+    if (!file)
+        return 0;
+
+    syscall->mmap.dentry = get_file_dentry(file);
+    syscall->mmap.file.path_key.mount_id = get_file_mount_id(file);
+    set_file_inode(syscall->mmap.dentry, &syscall->mmap.file, 0);
+    fill_file_metadata(syscall->mmap.dentry, &syscall->mmap.file.metadata);
+
+    syscall->resolver.key = syscall->mmap.file.path_key;
+    syscall->resolver.dentry = syscall->mmap.dentry;
+    syscall->resolver.discarder_type = syscall->policy.mode != NO_FILTER ? EVENT_MMAP : 0;
+    syscall->resolver.callback = DR_MMAP_CALLBACK_KPROBE_KEY;
+    syscall->resolver.iteration = 0;
+    syscall->resolver.ret = 0;
+
+    resolve_dentry(ctx, DR_KPROBE);
+    return 0;
+}
+
+SEC("kprobe/dr_mmap_callback")
+int __attribute__((always_inline)) kprobe_dr_mmap_callback(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_MMAP);
+    if (!syscall)
+        return 0;
+
+    if (syscall->resolver.ret == DENTRY_DISCARDED) {
+        return discard_syscall(syscall);
+    }
+
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_mmap_ret(void *ctx, long retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_MMAP);
+    if (!syscall)
+        return 0;
+
+    if (IS_UNHANDLED_ERROR(retval))
+        return 0;
+
+    struct mmap_event_t event = {
+        .syscall.retval = retval,
+        .file = syscall->mmap.file,
+        .addr = retval < 0 ? 0 : (u64)retval,
+        .offset = syscall->mmap.offset,
+        .len = syscall->mmap.len,
+        .protection = syscall->mmap.protection,
+        .flags = syscall->mmap.flags,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+
+    send_event(ctx, EVENT_MMAP, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(mmap) {
+    long retval = PT_REGS_RC(ctx);
+    return sys_mmap_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_mmap")
+int tracepoint_syscalls_sys_exit_mmap(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_mmap_ret(args, args->ret);
+}
+
+SEC("tracepoint/handle_sys_mmap_exit")
+int tracepoint_handle_sys_mmap_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_mmap_ret(args, args->ret);
+}
+
+#endif
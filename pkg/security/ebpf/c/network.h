@@ -0,0 +1,45 @@
+#ifndef _NETWORK_H_
+#define _NETWORK_H_
+
+#include <linux/errno.h>
+#include <linux/in.h>
+#include <linux/in6.h>
+#include <linux/net.h>
+#include <linux/socket.h>
+#include <net/sock.h>
+
+#include "bpf_endian.h"
+
+struct addr_t {
+    u64 addr[2];
+    u16 family;
+    u16 port;
+    u32 padding;
+};
+
+int __attribute__((always_inline)) is_supported_family(u16 family) {
+    return family == AF_INET || family == AF_INET6;
+}
+
+// fill_addr copies the address and port of an IPv4 or IPv6 socket address, the
+// port is kept in network byte order
+void __attribute__((always_inline)) fill_addr(struct addr_t *addr, struct sockaddr *address) {
+    bpf_probe_read(&addr->family, sizeof(addr->family), &address->sa_family);
+
+    switch (addr->family) {
+    case AF_INET: {
+        struct sockaddr_in *sin = (struct sockaddr_in *)address;
+        bpf_probe_read(&addr->port, sizeof(addr->port), &sin->sin_port);
+        bpf_probe_read(&addr->addr, sizeof(sin->sin_addr), &sin->sin_addr);
+        break;
+    }
+    case AF_INET6: {
+        struct sockaddr_in6 *sin6 = (struct sockaddr_in6 *)address;
+        bpf_probe_read(&addr->port, sizeof(addr->port), &sin6->sin6_port);
+        bpf_probe_read(&addr->addr, sizeof(sin6->sin6_addr), &sin6->sin6_addr);
+        break;
+    }
+    }
+}
+
+#endif
@@ -38,6 +38,14 @@
 #include "erpc.h"
 #include "ioctl.h"
 #include "selinux.h"
+#include "connect.h"
+#include "bind.h"
+#include "dns.h"
+#include "load_module.h"
+#include "ptrace.h"
+#include "mmap.h"
+#include "mprotect.h"
+#include "bpf.h"
 #include "raw_syscalls.h"
 
 struct invalidate_dentry_event_t {
@@ -0,0 +1,93 @@
+#ifndef _PTRACE_H_
+#define _PTRACE_H_
+
+#include <linux/ptrace.h>
+
+#include "syscalls.h"
+
+struct ptrace_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    u32 request;
+    u32 pid;
+    u64 addr;
+};
+
+// is_ptrace_request_monitored returns whether a ptrace request can be used to
+// take control of, or to inject code into, the tracee
+int __attribute__((always_inline)) is_ptrace_request_monitored(u32 request) {
+    switch (request) {
+    case PTRACE_ATTACH:
+    case PTRACE_SEIZE:
+    case PTRACE_TRACEME:
+    case PTRACE_POKETEXT:
+    case PTRACE_POKEDATA:
+    case PTRACE_POKEUSR:
+        return 1;
+    }
+    return 0;
+}
+
+SYSCALL_KPROBE3(ptrace, u32, request, pid_t, pid, void *, addr) {
+    if (!is_ptrace_request_monitored(request))
+        return 0;
+
+    struct policy_t policy = fetch_policy(EVENT_PTRACE);
+    if (is_discarded_by_process(policy.mode, EVENT_PTRACE)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_PTRACE,
+        .policy = policy,
+        .ptrace = {
+            .request = request,
+            .pid = pid,
+            .addr = (u64)addr,
+        },
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_ptrace_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_PTRACE);
+    if (!syscall)
+        return 0;
+
+    if (IS_UNHANDLED_ERROR(retval))
+        return 0;
+
+    struct ptrace_event_t event = {
+        .syscall.retval = retval,
+        .request = syscall->ptrace.request,
+        .pid = syscall->ptrace.pid,
+        .addr = syscall->ptrace.addr,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+
+    send_event(ctx, EVENT_PTRACE, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(ptrace) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_ptrace_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_ptrace")
+int tracepoint_syscalls_sys_exit_ptrace(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_ptrace_ret(args, args->ret);
+}
+
+SEC("tracepoint/handle_sys_ptrace_exit")
+int tracepoint_handle_sys_ptrace_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_ptrace_ret(args, args->ret);
+}
+
+#endif
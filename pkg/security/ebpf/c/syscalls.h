@@ -1,8 +1,12 @@
 #ifndef _SYSCALLS_H_
 #define _SYSCALLS_H_
 
+#include <linux/bpf.h>
+#include <linux/module.h>
+
 #include "filters.h"
 #include "process.h"
+#include "network.h"
 
 #define FSTYPE_LEN 16
 
@@ -135,6 +139,50 @@ struct syscall_cache_t {
             u32 event_kind;
             union selinux_write_payload_t payload;
         } selinux;
+
+        struct {
+            struct addr_t addr;
+        } connect;
+
+        struct {
+            struct addr_t addr;
+        } bind;
+
+        struct {
+            struct dentry *dentry;
+            struct file_t file;
+            u32 loaded_from_memory;
+            char name[MODULE_NAME_LEN];
+        } init_module;
+
+        struct {
+            u32 request;
+            u32 pid;
+            u64 addr;
+        } ptrace;
+
+        struct {
+            struct dentry *dentry;
+            struct file_t file;
+            u64 addr;
+            u64 offset;
+            u32 len;
+            int protection;
+            int flags;
+        } mmap;
+
+        struct {
+            u64 vm_start;
+            u64 vm_end;
+            u32 vm_protection;
+            u32 req_protection;
+        } mprotect;
+
+        struct {
+            u32 cmd;
+            u32 prog_type;
+            char prog_name[BPF_OBJ_NAME_LEN];
+        } bpf;
     };
 };
 
@@ -35,6 +35,12 @@ var (
 	Kernel5_3 = kernel.VersionCode(5, 3, 0) //nolint:deadcode,unused
 	// Kernel5_4 is the KernelVersion representation of kernel version 5.4
 	Kernel5_4 = kernel.VersionCode(5, 4, 0) //nolint:deadcode,unused
+	// Kernel6_0 is the KernelVersion representation of kernel version 6.0
+	Kernel6_0 = kernel.VersionCode(6, 0, 0) //nolint:deadcode,unused
+	// Kernel6_5 is the KernelVersion representation of kernel version 6.5
+	Kernel6_5 = kernel.VersionCode(6, 5, 0) //nolint:deadcode,unused
+	// Kernel6_7 is the KernelVersion representation of kernel version 6.7
+	Kernel6_7 = kernel.VersionCode(6, 7, 0) //nolint:deadcode,unused
 )
 
 // Version defines a kernel version helper
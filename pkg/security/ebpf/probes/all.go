@@ -35,6 +35,12 @@ func AllProbes() []*manager.Probe {
 	allProbes = append(allProbes, getXattrProbes()...)
 	allProbes = append(allProbes, getIoctlProbes()...)
 	allProbes = append(allProbes, getSELinuxProbes()...)
+	allProbes = append(allProbes, getNetworkProbes()...)
+	allProbes = append(allProbes, getModuleProbes()...)
+	allProbes = append(allProbes, getPTraceProbes()...)
+	allProbes = append(allProbes, getMMapProbes()...)
+	allProbes = append(allProbes, getMProtectProbes()...)
+	allProbes = append(allProbes, getBPFProbes()...)
 
 	allProbes = append(allProbes,
 		// Syscall monitor
@@ -82,6 +88,8 @@ func AllMaps() []*manager.Map {
 		// SELinux tables
 		{Name: "selinux_write_buffer"},
 		{Name: "selinux_enforce_status"},
+		// DNS tables
+		{Name: "dns_event"},
 		// Syscall monitor tables
 		{Name: "buffer_selector"},
 		{Name: "noisy_processes_fb"},
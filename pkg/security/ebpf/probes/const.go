@@ -47,6 +47,10 @@ const (
 	DentryResolverRenameCallbackKprobeKey
 	// DentryResolverSELinuxCallbackKprobeKey is the key to the callback program to execute after resolving the destination dentry of a selinux event
 	DentryResolverSELinuxCallbackKprobeKey
+	// DentryResolverMMapCallbackKprobeKey is the key to the callback program to execute after resolving the dentry of an mmap event
+	DentryResolverMMapCallbackKprobeKey
+	// DentryResolverLoadModuleCallbackKprobeKey is the key to the callback program to execute after resolving the dentry of a load_module event
+	DentryResolverLoadModuleCallbackKprobeKey
 )
 
 const (
@@ -113,6 +113,20 @@ func getDentryResolverTailCallRoutes() []manager.TailCallRoute {
 				Section: "kprobe/dr_selinux_callback",
 			},
 		},
+		{
+			ProgArrayName: "dentry_resolver_kprobe_callbacks",
+			Key:           DentryResolverMMapCallbackKprobeKey,
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "kprobe/dr_mmap_callback",
+			},
+		},
+		{
+			ProgArrayName: "dentry_resolver_kprobe_callbacks",
+			Key:           DentryResolverLoadModuleCallbackKprobeKey,
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "kprobe/dr_load_module_callback",
+			},
+		},
 
 		// dentry resolver tracepoint callbacks
 		{
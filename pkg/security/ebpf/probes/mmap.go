@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probes
+
+import (
+	"github.com/DataDog/ebpf/manager"
+)
+
+// mmapProbes holds the list of probes used to track mmap events
+var mmapProbes = []*manager.Probe{
+	{
+		UID:     SecurityAgentUID,
+		Section: "kprobe/security_mmap_file",
+	},
+}
+
+func getMMapProbes() []*manager.Probe {
+	mmapProbes = append(mmapProbes, ExpandSyscallProbes(&manager.Probe{
+		UID:             SecurityAgentUID,
+		SyscallFuncName: "mmap",
+	}, EntryAndExit)...)
+	return mmapProbes
+}
+
+// mprotectProbes holds the list of probes used to track mprotect events
+var mprotectProbes = []*manager.Probe{
+	{
+		UID:     SecurityAgentUID,
+		Section: "kprobe/security_file_mprotect",
+	},
+}
+
+func getMProtectProbes() []*manager.Probe {
+	mprotectProbes = append(mprotectProbes, ExpandSyscallProbes(&manager.Probe{
+		UID:             SecurityAgentUID,
+		SyscallFuncName: "mprotect",
+	}, EntryAndExit)...)
+	return mprotectProbes
+}
@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probes
+
+import (
+	"github.com/DataDog/ebpf/manager"
+)
+
+// moduleProbes holds the list of probes used to track kernel module load events
+var moduleProbes = []*manager.Probe{
+	{
+		UID:     SecurityAgentUID,
+		Section: "kprobe/security_kernel_read_file",
+	},
+	{
+		UID:     SecurityAgentUID,
+		Section: "kprobe/do_init_module",
+	},
+}
+
+func getModuleProbes() []*manager.Probe {
+	moduleProbes = append(moduleProbes, ExpandSyscallProbes(&manager.Probe{
+		UID:             SecurityAgentUID,
+		SyscallFuncName: "init_module",
+	}, EntryAndExit)...)
+	moduleProbes = append(moduleProbes, ExpandSyscallProbes(&manager.Probe{
+		UID:             SecurityAgentUID,
+		SyscallFuncName: "finit_module",
+	}, EntryAndExit)...)
+	return moduleProbes
+}
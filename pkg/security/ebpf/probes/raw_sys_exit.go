@@ -126,5 +126,54 @@ func getSysExitTailCallRoutes() []manager.TailCallRoute {
 				Section: "tracepoint/handle_sys_commit_creds_exit",
 			},
 		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.ConnectEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_connect_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.BindEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_bind_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.LoadModuleEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_init_module_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.PTraceEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_ptrace_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.MMapEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_mmap_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.MProtectEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_mprotect_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.BPFEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				Section: "tracepoint/handle_sys_bpf_exit",
+			},
+		},
 	}
 }
@@ -98,6 +98,11 @@ var (
 	// Tags: -
 	MetricPerfBufferSortingAvgOp = newRuntimeMetric(".perf_buffer.sorting_avg_op")
 
+	// MetricEventRecorderDropped is the name of the metric used to count the events that couldn't be recorded
+	// because the event recorder buffer was full
+	// Tags: -
+	MetricEventRecorderDropped = newRuntimeMetric(".event_recorder.dropped")
+
 	// Process Resolver metrics
 
 	// MetricProcessResolverCacheSize is the name of the metric used to report the size of the user space
@@ -126,6 +131,9 @@ var (
 	// MetricRuleSetLoaded is the name of the metric used to report that a new ruleset was loaded
 	// Tags: -
 	MetricRuleSetLoaded = newRuntimeMetric(".ruleset_loaded")
+	// MetricActiveResponse is the name of the metric used to count the actions executed in response to a rule match
+	// Tags: rule_id, action, status
+	MetricActiveResponse = newRuntimeMetric(".active_response")
 
 	// Security Agent metrics
 
@@ -30,20 +30,36 @@ func (m *Model) GetIterator(field eval.Field) (eval.Iterator, error) {
 func (m *Model) GetEventTypes() []eval.EventType {
 	return []eval.EventType{
 
+		eval.EventType("bind"),
+
+		eval.EventType("bpf"),
+
 		eval.EventType("capset"),
 
 		eval.EventType("chmod"),
 
 		eval.EventType("chown"),
 
+		eval.EventType("connect"),
+
+		eval.EventType("dns"),
+
 		eval.EventType("exec"),
 
 		eval.EventType("link"),
 
+		eval.EventType("load_module"),
+
 		eval.EventType("mkdir"),
 
+		eval.EventType("mmap"),
+
+		eval.EventType("mprotect"),
+
 		eval.EventType("open"),
 
+		eval.EventType("ptrace"),
+
 		eval.EventType("removexattr"),
 
 		eval.EventType("rename"),
@@ -67,6 +83,86 @@ func (m *Model) GetEventTypes() []eval.EventType {
 func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Evaluator, error) {
 	switch field {
 
+	case "bind.addr.family":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Bind.Addr.Family)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bind.addr.ip":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Bind.Addr.IP
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bind.addr.port":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Bind.Addr.Port)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bind.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Bind.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bpf.cmd":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).BPF.Cmd)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bpf.prog.name":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).BPF.ProgName
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bpf.prog.type":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).BPF.ProgType)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bpf.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).BPF.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "capset.cap_effective":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
@@ -447,6 +543,46 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: eval.FunctionWeight,
 		}, nil
 
+	case "connect.addr.family":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Connect.Addr.Family)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "connect.addr.ip":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Connect.Addr.IP
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "connect.addr.port":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Connect.Addr.Port)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "connect.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Connect.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "container.id":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
@@ -468,6 +604,66 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: 9999,
 		}, nil
 
+	case "dns.id":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).DNS.ID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "dns.question.class":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).DNS.Class)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "dns.question.count":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).DNS.Count)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "dns.question.length":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).DNS.Size)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "dns.question.name":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).DNS.Name
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "dns.question.type":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).DNS.Type)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "exec.args":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
@@ -1192,241 +1388,611 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.change_time":
-		return &eval.IntEvaluator{
-			EvalFnc: func(ctx *eval.Context) int {
-
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.CTime)
-			},
-			Field:  field,
-			Weight: eval.FunctionWeight,
-		}, nil
-
-	case "mkdir.file.destination.mode":
-		return &eval.IntEvaluator{
-			EvalFnc: func(ctx *eval.Context) int {
-
-				return int((*Event)(ctx.Object).Mkdir.Mode)
-			},
-			Field:  field,
-			Weight: eval.FunctionWeight,
-		}, nil
-
-	case "mkdir.file.destination.rights":
+	case "load_module.file.change_time":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.Mode)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.CTime)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.filesystem":
+	case "load_module.file.filesystem":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Mkdir.File.Filesytem
+				return (*Event)(ctx.Object).LoadModule.File.Filesytem
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.file.gid":
+	case "load_module.file.gid":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.GID)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.GID)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.group":
+	case "load_module.file.group":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Mkdir.File.FileFields.Group
+				return (*Event)(ctx.Object).LoadModule.File.FileFields.Group
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.file.in_upper_layer":
+	case "load_module.file.in_upper_layer":
 		return &eval.BoolEvaluator{
 			EvalFnc: func(ctx *eval.Context) bool {
 
-				return (*Event)(ctx.Object).Mkdir.File.FileFields.InUpperLayer
+				return (*Event)(ctx.Object).LoadModule.File.FileFields.InUpperLayer
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.file.inode":
+	case "load_module.file.inode":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.Inode)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.Inode)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.mode":
+	case "load_module.file.mode":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.Mode)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.Mode)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.modification_time":
+	case "load_module.file.modification_time":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.MTime)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.MTime)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.mount_id":
+	case "load_module.file.mount_id":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.MountID)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.MountID)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.name":
+	case "load_module.file.name":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Mkdir.File.BasenameStr
+				return (*Event)(ctx.Object).LoadModule.File.BasenameStr
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.file.path":
+	case "load_module.file.path":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Mkdir.File.PathnameStr
+				return (*Event)(ctx.Object).LoadModule.File.PathnameStr
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.file.rights":
+	case "load_module.file.rights":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.Mode)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.Mode)
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.file.uid":
+	case "load_module.file.uid":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.File.FileFields.UID)
+				return int((*Event)(ctx.Object).LoadModule.File.FileFields.UID)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "mkdir.file.user":
+	case "load_module.file.user":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Mkdir.File.FileFields.User
+				return (*Event)(ctx.Object).LoadModule.File.FileFields.User
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "mkdir.retval":
+	case "load_module.loaded_from_memory":
+		return &eval.BoolEvaluator{
+			EvalFnc: func(ctx *eval.Context) bool {
+
+				return (*Event)(ctx.Object).LoadModule.LoadedFromMemory
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "load_module.name":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).LoadModule.Name
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "load_module.retval":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Mkdir.SyscallEvent.Retval)
+				return int((*Event)(ctx.Object).LoadModule.SyscallEvent.Retval)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "open.file.change_time":
+	case "mkdir.file.change_time":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Open.File.FileFields.CTime)
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.CTime)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "open.file.destination.mode":
+	case "mkdir.file.destination.mode":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Open.Mode)
+				return int((*Event)(ctx.Object).Mkdir.Mode)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "open.file.filesystem":
+	case "mkdir.file.destination.rights":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.Mode)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mkdir.file.filesystem":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Open.File.Filesytem
+				return (*Event)(ctx.Object).Mkdir.File.Filesytem
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "open.file.gid":
+	case "mkdir.file.gid":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Open.File.FileFields.GID)
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.GID)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
 
-	case "open.file.group":
+	case "mkdir.file.group":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
 
-				return (*Event)(ctx.Object).Open.File.FileFields.Group
+				return (*Event)(ctx.Object).Mkdir.File.FileFields.Group
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "open.file.in_upper_layer":
+	case "mkdir.file.in_upper_layer":
 		return &eval.BoolEvaluator{
 			EvalFnc: func(ctx *eval.Context) bool {
 
-				return (*Event)(ctx.Object).Open.File.FileFields.InUpperLayer
+				return (*Event)(ctx.Object).Mkdir.File.FileFields.InUpperLayer
 			},
 			Field:  field,
 			Weight: eval.HandlerWeight,
 		}, nil
 
-	case "open.file.inode":
+	case "mkdir.file.inode":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
 
-				return int((*Event)(ctx.Object).Open.File.FileFields.Inode)
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.Inode)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mkdir.file.mode":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.Mode)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mkdir.file.modification_time":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.MTime)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mkdir.file.mount_id":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.MountID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mkdir.file.name":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Mkdir.File.BasenameStr
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mkdir.file.path":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Mkdir.File.PathnameStr
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mkdir.file.rights":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.Mode)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mkdir.file.uid":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.File.FileFields.UID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mkdir.file.user":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Mkdir.File.FileFields.User
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mkdir.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mkdir.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.change_time":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.CTime)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.filesystem":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).MMap.File.Filesytem
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.file.gid":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.GID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.group":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).MMap.File.FileFields.Group
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.file.in_upper_layer":
+		return &eval.BoolEvaluator{
+			EvalFnc: func(ctx *eval.Context) bool {
+
+				return (*Event)(ctx.Object).MMap.File.FileFields.InUpperLayer
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.file.inode":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.Inode)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.mode":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.Mode)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.modification_time":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.MTime)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.mount_id":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.MountID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.name":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).MMap.File.BasenameStr
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.file.path":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).MMap.File.PathnameStr
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.file.rights":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.Mode)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.file.uid":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.File.FileFields.UID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.file.user":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).MMap.File.FileFields.User
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mmap.flags":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return (*Event)(ctx.Object).MMap.Flags
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.protection":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return (*Event)(ctx.Object).MMap.Protection
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mmap.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MMap.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mprotect.req_protection":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return (*Event)(ctx.Object).MProtect.ReqProtection
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mprotect.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).MProtect.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mprotect.vm_protection":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return (*Event)(ctx.Object).MProtect.VMProtection
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "open.file.change_time":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Open.File.FileFields.CTime)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "open.file.destination.mode":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Open.Mode)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "open.file.filesystem":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Open.File.Filesytem
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "open.file.gid":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Open.File.FileFields.GID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "open.file.group":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Open.File.FileFields.Group
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "open.file.in_upper_layer":
+		return &eval.BoolEvaluator{
+			EvalFnc: func(ctx *eval.Context) bool {
+
+				return (*Event)(ctx.Object).Open.File.FileFields.InUpperLayer
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "open.file.inode":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Open.File.FileFields.Inode)
 			},
 			Field:  field,
 			Weight: eval.FunctionWeight,
@@ -2792,6 +3358,36 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: eval.FunctionWeight,
 		}, nil
 
+	case "ptrace.request":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).PTrace.Request)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "ptrace.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).PTrace.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "ptrace.tracee.pid":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).PTrace.PID)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "removexattr.file.change_time":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
@@ -4040,6 +4636,22 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 func (e *Event) GetFields() []eval.Field {
 	return []eval.Field{
 
+		"bind.addr.family",
+
+		"bind.addr.ip",
+
+		"bind.addr.port",
+
+		"bind.retval",
+
+		"bpf.cmd",
+
+		"bpf.prog.name",
+
+		"bpf.prog.type",
+
+		"bpf.retval",
+
 		"capset.cap_effective",
 
 		"capset.cap_permitted",
@@ -4116,10 +4728,30 @@ func (e *Event) GetFields() []eval.Field {
 
 		"chown.retval",
 
+		"connect.addr.family",
+
+		"connect.addr.ip",
+
+		"connect.addr.port",
+
+		"connect.retval",
+
 		"container.id",
 
 		"container.tags",
 
+		"dns.id",
+
+		"dns.question.class",
+
+		"dns.question.count",
+
+		"dns.question.length",
+
+		"dns.question.name",
+
+		"dns.question.type",
+
 		"exec.args",
 
 		"exec.args_flags",
@@ -4264,6 +4896,40 @@ func (e *Event) GetFields() []eval.Field {
 
 		"link.retval",
 
+		"load_module.file.change_time",
+
+		"load_module.file.filesystem",
+
+		"load_module.file.gid",
+
+		"load_module.file.group",
+
+		"load_module.file.in_upper_layer",
+
+		"load_module.file.inode",
+
+		"load_module.file.mode",
+
+		"load_module.file.modification_time",
+
+		"load_module.file.mount_id",
+
+		"load_module.file.name",
+
+		"load_module.file.path",
+
+		"load_module.file.rights",
+
+		"load_module.file.uid",
+
+		"load_module.file.user",
+
+		"load_module.loaded_from_memory",
+
+		"load_module.name",
+
+		"load_module.retval",
+
 		"mkdir.file.change_time",
 
 		"mkdir.file.destination.mode",
@@ -4298,6 +4964,46 @@ func (e *Event) GetFields() []eval.Field {
 
 		"mkdir.retval",
 
+		"mmap.file.change_time",
+
+		"mmap.file.filesystem",
+
+		"mmap.file.gid",
+
+		"mmap.file.group",
+
+		"mmap.file.in_upper_layer",
+
+		"mmap.file.inode",
+
+		"mmap.file.mode",
+
+		"mmap.file.modification_time",
+
+		"mmap.file.mount_id",
+
+		"mmap.file.name",
+
+		"mmap.file.path",
+
+		"mmap.file.rights",
+
+		"mmap.file.uid",
+
+		"mmap.file.user",
+
+		"mmap.flags",
+
+		"mmap.protection",
+
+		"mmap.retval",
+
+		"mprotect.req_protection",
+
+		"mprotect.retval",
+
+		"mprotect.vm_protection",
+
 		"open.file.change_time",
 
 		"open.file.destination.mode",
@@ -4476,6 +5182,12 @@ func (e *Event) GetFields() []eval.Field {
 
 		"process.user",
 
+		"ptrace.request",
+
+		"ptrace.retval",
+
+		"ptrace.tracee.pid",
+
 		"removexattr.file.change_time",
 
 		"removexattr.file.destination.name",
@@ -4729,6 +5441,38 @@ func (e *Event) GetFields() []eval.Field {
 func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 	switch field {
 
+	case "bind.addr.family":
+
+		return int(e.Bind.Addr.Family), nil
+
+	case "bind.addr.ip":
+
+		return e.Bind.Addr.IP, nil
+
+	case "bind.addr.port":
+
+		return int(e.Bind.Addr.Port), nil
+
+	case "bind.retval":
+
+		return int(e.Bind.SyscallEvent.Retval), nil
+
+	case "bpf.cmd":
+
+		return int(e.BPF.Cmd), nil
+
+	case "bpf.prog.name":
+
+		return e.BPF.ProgName, nil
+
+	case "bpf.prog.type":
+
+		return int(e.BPF.ProgType), nil
+
+	case "bpf.retval":
+
+		return int(e.BPF.SyscallEvent.Retval), nil
+
 	case "capset.cap_effective":
 
 		return int(e.Capset.CapEffective), nil
@@ -4881,6 +5625,22 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Chown.SyscallEvent.Retval), nil
 
+	case "connect.addr.family":
+
+		return int(e.Connect.Addr.Family), nil
+
+	case "connect.addr.ip":
+
+		return e.Connect.Addr.IP, nil
+
+	case "connect.addr.port":
+
+		return int(e.Connect.Addr.Port), nil
+
+	case "connect.retval":
+
+		return int(e.Connect.SyscallEvent.Retval), nil
+
 	case "container.id":
 
 		return e.ContainerContext.ID, nil
@@ -4889,6 +5649,30 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return e.ContainerContext.Tags, nil
 
+	case "dns.id":
+
+		return int(e.DNS.ID), nil
+
+	case "dns.question.class":
+
+		return int(e.DNS.Class), nil
+
+	case "dns.question.count":
+
+		return int(e.DNS.Count), nil
+
+	case "dns.question.length":
+
+		return int(e.DNS.Size), nil
+
+	case "dns.question.name":
+
+		return e.DNS.Name, nil
+
+	case "dns.question.type":
+
+		return int(e.DNS.Type), nil
+
 	case "exec.args":
 
 		return e.Exec.Args, nil
@@ -5177,6 +5961,74 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Link.SyscallEvent.Retval), nil
 
+	case "load_module.file.change_time":
+
+		return int(e.LoadModule.File.FileFields.CTime), nil
+
+	case "load_module.file.filesystem":
+
+		return e.LoadModule.File.Filesytem, nil
+
+	case "load_module.file.gid":
+
+		return int(e.LoadModule.File.FileFields.GID), nil
+
+	case "load_module.file.group":
+
+		return e.LoadModule.File.FileFields.Group, nil
+
+	case "load_module.file.in_upper_layer":
+
+		return e.LoadModule.File.FileFields.InUpperLayer, nil
+
+	case "load_module.file.inode":
+
+		return int(e.LoadModule.File.FileFields.Inode), nil
+
+	case "load_module.file.mode":
+
+		return int(e.LoadModule.File.FileFields.Mode), nil
+
+	case "load_module.file.modification_time":
+
+		return int(e.LoadModule.File.FileFields.MTime), nil
+
+	case "load_module.file.mount_id":
+
+		return int(e.LoadModule.File.FileFields.MountID), nil
+
+	case "load_module.file.name":
+
+		return e.LoadModule.File.BasenameStr, nil
+
+	case "load_module.file.path":
+
+		return e.LoadModule.File.PathnameStr, nil
+
+	case "load_module.file.rights":
+
+		return int(e.LoadModule.File.FileFields.Mode), nil
+
+	case "load_module.file.uid":
+
+		return int(e.LoadModule.File.FileFields.UID), nil
+
+	case "load_module.file.user":
+
+		return e.LoadModule.File.FileFields.User, nil
+
+	case "load_module.loaded_from_memory":
+
+		return e.LoadModule.LoadedFromMemory, nil
+
+	case "load_module.name":
+
+		return e.LoadModule.Name, nil
+
+	case "load_module.retval":
+
+		return int(e.LoadModule.SyscallEvent.Retval), nil
+
 	case "mkdir.file.change_time":
 
 		return int(e.Mkdir.File.FileFields.CTime), nil
@@ -5245,6 +6097,86 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Mkdir.SyscallEvent.Retval), nil
 
+	case "mmap.file.change_time":
+
+		return int(e.MMap.File.FileFields.CTime), nil
+
+	case "mmap.file.filesystem":
+
+		return e.MMap.File.Filesytem, nil
+
+	case "mmap.file.gid":
+
+		return int(e.MMap.File.FileFields.GID), nil
+
+	case "mmap.file.group":
+
+		return e.MMap.File.FileFields.Group, nil
+
+	case "mmap.file.in_upper_layer":
+
+		return e.MMap.File.FileFields.InUpperLayer, nil
+
+	case "mmap.file.inode":
+
+		return int(e.MMap.File.FileFields.Inode), nil
+
+	case "mmap.file.mode":
+
+		return int(e.MMap.File.FileFields.Mode), nil
+
+	case "mmap.file.modification_time":
+
+		return int(e.MMap.File.FileFields.MTime), nil
+
+	case "mmap.file.mount_id":
+
+		return int(e.MMap.File.FileFields.MountID), nil
+
+	case "mmap.file.name":
+
+		return e.MMap.File.BasenameStr, nil
+
+	case "mmap.file.path":
+
+		return e.MMap.File.PathnameStr, nil
+
+	case "mmap.file.rights":
+
+		return int(e.MMap.File.FileFields.Mode), nil
+
+	case "mmap.file.uid":
+
+		return int(e.MMap.File.FileFields.UID), nil
+
+	case "mmap.file.user":
+
+		return e.MMap.File.FileFields.User, nil
+
+	case "mmap.flags":
+
+		return e.MMap.Flags, nil
+
+	case "mmap.protection":
+
+		return e.MMap.Protection, nil
+
+	case "mmap.retval":
+
+		return int(e.MMap.SyscallEvent.Retval), nil
+
+	case "mprotect.req_protection":
+
+		return e.MProtect.ReqProtection, nil
+
+	case "mprotect.retval":
+
+		return int(e.MProtect.SyscallEvent.Retval), nil
+
+	case "mprotect.vm_protection":
+
+		return e.MProtect.VMProtection, nil
+
 	case "open.file.change_time":
 
 		return int(e.Open.File.FileFields.CTime), nil
@@ -6249,6 +7181,18 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return e.ProcessContext.Process.Credentials.User, nil
 
+	case "ptrace.request":
+
+		return int(e.PTrace.Request), nil
+
+	case "ptrace.retval":
+
+		return int(e.PTrace.SyscallEvent.Retval), nil
+
+	case "ptrace.tracee.pid":
+
+		return int(e.PTrace.PID), nil
+
 	case "removexattr.file.change_time":
 
 		return int(e.RemoveXAttr.File.FileFields.CTime), nil
@@ -6753,6 +7697,30 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	switch field {
 
+	case "bind.addr.family":
+		return "bind", nil
+
+	case "bind.addr.ip":
+		return "bind", nil
+
+	case "bind.addr.port":
+		return "bind", nil
+
+	case "bind.retval":
+		return "bind", nil
+
+	case "bpf.cmd":
+		return "bpf", nil
+
+	case "bpf.prog.name":
+		return "bpf", nil
+
+	case "bpf.prog.type":
+		return "bpf", nil
+
+	case "bpf.retval":
+		return "bpf", nil
+
 	case "capset.cap_effective":
 		return "capset", nil
 
@@ -6867,12 +7835,42 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "chown.retval":
 		return "chown", nil
 
+	case "connect.addr.family":
+		return "connect", nil
+
+	case "connect.addr.ip":
+		return "connect", nil
+
+	case "connect.addr.port":
+		return "connect", nil
+
+	case "connect.retval":
+		return "connect", nil
+
 	case "container.id":
 		return "*", nil
 
 	case "container.tags":
 		return "*", nil
 
+	case "dns.id":
+		return "dns", nil
+
+	case "dns.question.class":
+		return "dns", nil
+
+	case "dns.question.count":
+		return "dns", nil
+
+	case "dns.question.length":
+		return "dns", nil
+
+	case "dns.question.name":
+		return "dns", nil
+
+	case "dns.question.type":
+		return "dns", nil
+
 	case "exec.args":
 		return "exec", nil
 
@@ -7071,23 +8069,74 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "link.file.mount_id":
 		return "link", nil
 
-	case "link.file.name":
-		return "link", nil
+	case "link.file.name":
+		return "link", nil
+
+	case "link.file.path":
+		return "link", nil
+
+	case "link.file.rights":
+		return "link", nil
+
+	case "link.file.uid":
+		return "link", nil
+
+	case "link.file.user":
+		return "link", nil
+
+	case "link.retval":
+		return "link", nil
+
+	case "load_module.file.change_time":
+		return "load_module", nil
+
+	case "load_module.file.filesystem":
+		return "load_module", nil
+
+	case "load_module.file.gid":
+		return "load_module", nil
+
+	case "load_module.file.group":
+		return "load_module", nil
+
+	case "load_module.file.in_upper_layer":
+		return "load_module", nil
+
+	case "load_module.file.inode":
+		return "load_module", nil
+
+	case "load_module.file.mode":
+		return "load_module", nil
+
+	case "load_module.file.modification_time":
+		return "load_module", nil
+
+	case "load_module.file.mount_id":
+		return "load_module", nil
+
+	case "load_module.file.name":
+		return "load_module", nil
+
+	case "load_module.file.path":
+		return "load_module", nil
+
+	case "load_module.file.rights":
+		return "load_module", nil
 
-	case "link.file.path":
-		return "link", nil
+	case "load_module.file.uid":
+		return "load_module", nil
 
-	case "link.file.rights":
-		return "link", nil
+	case "load_module.file.user":
+		return "load_module", nil
 
-	case "link.file.uid":
-		return "link", nil
+	case "load_module.loaded_from_memory":
+		return "load_module", nil
 
-	case "link.file.user":
-		return "link", nil
+	case "load_module.name":
+		return "load_module", nil
 
-	case "link.retval":
-		return "link", nil
+	case "load_module.retval":
+		return "load_module", nil
 
 	case "mkdir.file.change_time":
 		return "mkdir", nil
@@ -7140,6 +8189,66 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "mkdir.retval":
 		return "mkdir", nil
 
+	case "mmap.file.change_time":
+		return "mmap", nil
+
+	case "mmap.file.filesystem":
+		return "mmap", nil
+
+	case "mmap.file.gid":
+		return "mmap", nil
+
+	case "mmap.file.group":
+		return "mmap", nil
+
+	case "mmap.file.in_upper_layer":
+		return "mmap", nil
+
+	case "mmap.file.inode":
+		return "mmap", nil
+
+	case "mmap.file.mode":
+		return "mmap", nil
+
+	case "mmap.file.modification_time":
+		return "mmap", nil
+
+	case "mmap.file.mount_id":
+		return "mmap", nil
+
+	case "mmap.file.name":
+		return "mmap", nil
+
+	case "mmap.file.path":
+		return "mmap", nil
+
+	case "mmap.file.rights":
+		return "mmap", nil
+
+	case "mmap.file.uid":
+		return "mmap", nil
+
+	case "mmap.file.user":
+		return "mmap", nil
+
+	case "mmap.flags":
+		return "mmap", nil
+
+	case "mmap.protection":
+		return "mmap", nil
+
+	case "mmap.retval":
+		return "mmap", nil
+
+	case "mprotect.req_protection":
+		return "mprotect", nil
+
+	case "mprotect.retval":
+		return "mprotect", nil
+
+	case "mprotect.vm_protection":
+		return "mprotect", nil
+
 	case "open.file.change_time":
 		return "open", nil
 
@@ -7407,6 +8516,15 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "process.user":
 		return "*", nil
 
+	case "ptrace.request":
+		return "ptrace", nil
+
+	case "ptrace.retval":
+		return "ptrace", nil
+
+	case "ptrace.tracee.pid":
+		return "ptrace", nil
+
 	case "removexattr.file.change_time":
 		return "removexattr", nil
 
@@ -7787,6 +8905,38 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 	switch field {
 
+	case "bind.addr.family":
+
+		return reflect.Int, nil
+
+	case "bind.addr.ip":
+
+		return reflect.String, nil
+
+	case "bind.addr.port":
+
+		return reflect.Int, nil
+
+	case "bind.retval":
+
+		return reflect.Int, nil
+
+	case "bpf.cmd":
+
+		return reflect.Int, nil
+
+	case "bpf.prog.name":
+
+		return reflect.String, nil
+
+	case "bpf.prog.type":
+
+		return reflect.Int, nil
+
+	case "bpf.retval":
+
+		return reflect.Int, nil
+
 	case "capset.cap_effective":
 
 		return reflect.Int, nil
@@ -7939,6 +9089,22 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "connect.addr.family":
+
+		return reflect.Int, nil
+
+	case "connect.addr.ip":
+
+		return reflect.String, nil
+
+	case "connect.addr.port":
+
+		return reflect.Int, nil
+
+	case "connect.retval":
+
+		return reflect.Int, nil
+
 	case "container.id":
 
 		return reflect.String, nil
@@ -7947,6 +9113,30 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.String, nil
 
+	case "dns.id":
+
+		return reflect.Int, nil
+
+	case "dns.question.class":
+
+		return reflect.Int, nil
+
+	case "dns.question.count":
+
+		return reflect.Int, nil
+
+	case "dns.question.length":
+
+		return reflect.Int, nil
+
+	case "dns.question.name":
+
+		return reflect.String, nil
+
+	case "dns.question.type":
+
+		return reflect.Int, nil
+
 	case "exec.args":
 
 		return reflect.String, nil
@@ -8235,6 +9425,74 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "load_module.file.change_time":
+
+		return reflect.Int, nil
+
+	case "load_module.file.filesystem":
+
+		return reflect.String, nil
+
+	case "load_module.file.gid":
+
+		return reflect.Int, nil
+
+	case "load_module.file.group":
+
+		return reflect.String, nil
+
+	case "load_module.file.in_upper_layer":
+
+		return reflect.Bool, nil
+
+	case "load_module.file.inode":
+
+		return reflect.Int, nil
+
+	case "load_module.file.mode":
+
+		return reflect.Int, nil
+
+	case "load_module.file.modification_time":
+
+		return reflect.Int, nil
+
+	case "load_module.file.mount_id":
+
+		return reflect.Int, nil
+
+	case "load_module.file.name":
+
+		return reflect.String, nil
+
+	case "load_module.file.path":
+
+		return reflect.String, nil
+
+	case "load_module.file.rights":
+
+		return reflect.Int, nil
+
+	case "load_module.file.uid":
+
+		return reflect.Int, nil
+
+	case "load_module.file.user":
+
+		return reflect.String, nil
+
+	case "load_module.loaded_from_memory":
+
+		return reflect.Bool, nil
+
+	case "load_module.name":
+
+		return reflect.String, nil
+
+	case "load_module.retval":
+
+		return reflect.Int, nil
+
 	case "mkdir.file.change_time":
 
 		return reflect.Int, nil
@@ -8303,6 +9561,86 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "mmap.file.change_time":
+
+		return reflect.Int, nil
+
+	case "mmap.file.filesystem":
+
+		return reflect.String, nil
+
+	case "mmap.file.gid":
+
+		return reflect.Int, nil
+
+	case "mmap.file.group":
+
+		return reflect.String, nil
+
+	case "mmap.file.in_upper_layer":
+
+		return reflect.Bool, nil
+
+	case "mmap.file.inode":
+
+		return reflect.Int, nil
+
+	case "mmap.file.mode":
+
+		return reflect.Int, nil
+
+	case "mmap.file.modification_time":
+
+		return reflect.Int, nil
+
+	case "mmap.file.mount_id":
+
+		return reflect.Int, nil
+
+	case "mmap.file.name":
+
+		return reflect.String, nil
+
+	case "mmap.file.path":
+
+		return reflect.String, nil
+
+	case "mmap.file.rights":
+
+		return reflect.Int, nil
+
+	case "mmap.file.uid":
+
+		return reflect.Int, nil
+
+	case "mmap.file.user":
+
+		return reflect.String, nil
+
+	case "mmap.flags":
+
+		return reflect.Int, nil
+
+	case "mmap.protection":
+
+		return reflect.Int, nil
+
+	case "mmap.retval":
+
+		return reflect.Int, nil
+
+	case "mprotect.req_protection":
+
+		return reflect.Int, nil
+
+	case "mprotect.retval":
+
+		return reflect.Int, nil
+
+	case "mprotect.vm_protection":
+
+		return reflect.Int, nil
+
 	case "open.file.change_time":
 
 		return reflect.Int, nil
@@ -8659,6 +9997,18 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.String, nil
 
+	case "ptrace.request":
+
+		return reflect.Int, nil
+
+	case "ptrace.retval":
+
+		return reflect.Int, nil
+
+	case "ptrace.tracee.pid":
+
+		return reflect.Int, nil
+
 	case "removexattr.file.change_time":
 
 		return reflect.Int, nil
@@ -9147,21 +10497,103 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
-	case "utimes.file.user":
+	case "utimes.file.user":
+
+		return reflect.String, nil
+
+	case "utimes.retval":
+
+		return reflect.Int, nil
+
+	}
+
+	return reflect.Invalid, &eval.ErrFieldNotFound{Field: field}
+}
+
+func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
+	switch field {
+
+	case "bind.addr.family":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Addr.Family"}
+		}
+		e.Bind.Addr.Family = uint16(v)
+		return nil
+
+	case "bind.addr.ip":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Addr.IP"}
+		}
+		e.Bind.Addr.IP = str
+
+		return nil
+
+	case "bind.addr.port":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Addr.Port"}
+		}
+		e.Bind.Addr.Port = uint16(v)
+		return nil
+
+	case "bind.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.SyscallEvent.Retval"}
+		}
+		e.Bind.SyscallEvent.Retval = int64(v)
+		return nil
+
+	case "bpf.cmd":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "BPF.Cmd"}
+		}
+		e.BPF.Cmd = uint32(v)
+		return nil
+
+	case "bpf.prog.name":
 
-		return reflect.String, nil
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "BPF.ProgName"}
+		}
+		e.BPF.ProgName = str
 
-	case "utimes.retval":
+		return nil
 
-		return reflect.Int, nil
+	case "bpf.prog.type":
 
-	}
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "BPF.ProgType"}
+		}
+		e.BPF.ProgType = uint32(v)
+		return nil
 
-	return reflect.Invalid, &eval.ErrFieldNotFound{Field: field}
-}
+	case "bpf.retval":
 
-func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
-	switch field {
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "BPF.SyscallEvent.Retval"}
+		}
+		e.BPF.SyscallEvent.Retval = int64(v)
+		return nil
 
 	case "capset.cap_effective":
 
@@ -9551,6 +10983,47 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Chown.SyscallEvent.Retval = int64(v)
 		return nil
 
+	case "connect.addr.family":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.Family"}
+		}
+		e.Connect.Addr.Family = uint16(v)
+		return nil
+
+	case "connect.addr.ip":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.IP"}
+		}
+		e.Connect.Addr.IP = str
+
+		return nil
+
+	case "connect.addr.port":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.Port"}
+		}
+		e.Connect.Addr.Port = uint16(v)
+		return nil
+
+	case "connect.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.SyscallEvent.Retval"}
+		}
+		e.Connect.SyscallEvent.Retval = int64(v)
+		return nil
+
 	case "container.id":
 
 		var ok bool
@@ -9573,6 +11046,67 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 
 		return nil
 
+	case "dns.id":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "DNS.ID"}
+		}
+		e.DNS.ID = uint16(v)
+		return nil
+
+	case "dns.question.class":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "DNS.Class"}
+		}
+		e.DNS.Class = uint16(v)
+		return nil
+
+	case "dns.question.count":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "DNS.Count"}
+		}
+		e.DNS.Count = uint16(v)
+		return nil
+
+	case "dns.question.length":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "DNS.Size"}
+		}
+		e.DNS.Size = uint16(v)
+		return nil
+
+	case "dns.question.name":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "DNS.Name"}
+		}
+		e.DNS.Name = str
+
+		return nil
+
+	case "dns.question.type":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "DNS.Type"}
+		}
+		e.DNS.Type = uint16(v)
+		return nil
+
 	case "exec.args":
 
 		var ok bool
@@ -10119,197 +11653,369 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 	case "link.file.destination.name":
 
 		var ok bool
-		str, ok := value.(string)
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Target.BasenameStr"}
+		}
+		e.Link.Target.BasenameStr = str
+
+		return nil
+
+	case "link.file.destination.path":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Target.PathnameStr"}
+		}
+		e.Link.Target.PathnameStr = str
+
+		return nil
+
+	case "link.file.destination.rights":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Target.FileFields.Mode"}
+		}
+		e.Link.Target.FileFields.Mode = uint16(v)
+		return nil
+
+	case "link.file.destination.uid":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Target.FileFields.UID"}
+		}
+		e.Link.Target.FileFields.UID = uint32(v)
+		return nil
+
+	case "link.file.destination.user":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Target.FileFields.User"}
+		}
+		e.Link.Target.FileFields.User = str
+
+		return nil
+
+	case "link.file.filesystem":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.Filesytem"}
+		}
+		e.Link.Source.Filesytem = str
+
+		return nil
+
+	case "link.file.gid":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.GID"}
+		}
+		e.Link.Source.FileFields.GID = uint32(v)
+		return nil
+
+	case "link.file.group":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Group"}
+		}
+		e.Link.Source.FileFields.Group = str
+
+		return nil
+
+	case "link.file.in_upper_layer":
+
+		var ok bool
+		if e.Link.Source.FileFields.InUpperLayer, ok = value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.InUpperLayer"}
+		}
+		return nil
+
+	case "link.file.inode":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Inode"}
+		}
+		e.Link.Source.FileFields.Inode = uint64(v)
+		return nil
+
+	case "link.file.mode":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Mode"}
+		}
+		e.Link.Source.FileFields.Mode = uint16(v)
+		return nil
+
+	case "link.file.modification_time":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.MTime"}
+		}
+		e.Link.Source.FileFields.MTime = uint64(v)
+		return nil
+
+	case "link.file.mount_id":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.MountID"}
+		}
+		e.Link.Source.FileFields.MountID = uint32(v)
+		return nil
+
+	case "link.file.name":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.BasenameStr"}
+		}
+		e.Link.Source.BasenameStr = str
+
+		return nil
+
+	case "link.file.path":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.PathnameStr"}
+		}
+		e.Link.Source.PathnameStr = str
+
+		return nil
+
+	case "link.file.rights":
+
+		var ok bool
+		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Target.BasenameStr"}
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Mode"}
 		}
-		e.Link.Target.BasenameStr = str
-
+		e.Link.Source.FileFields.Mode = uint16(v)
 		return nil
 
-	case "link.file.destination.path":
+	case "link.file.uid":
 
 		var ok bool
-		str, ok := value.(string)
+		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Target.PathnameStr"}
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.UID"}
 		}
-		e.Link.Target.PathnameStr = str
-
+		e.Link.Source.FileFields.UID = uint32(v)
 		return nil
 
-	case "link.file.destination.rights":
+	case "link.file.user":
 
 		var ok bool
-		v, ok := value.(int)
+		str, ok := value.(string)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Target.FileFields.Mode"}
+			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.User"}
 		}
-		e.Link.Target.FileFields.Mode = uint16(v)
+		e.Link.Source.FileFields.User = str
+
 		return nil
 
-	case "link.file.destination.uid":
+	case "link.retval":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Target.FileFields.UID"}
+			return &eval.ErrValueTypeMismatch{Field: "Link.SyscallEvent.Retval"}
 		}
-		e.Link.Target.FileFields.UID = uint32(v)
+		e.Link.SyscallEvent.Retval = int64(v)
 		return nil
 
-	case "link.file.destination.user":
+	case "load_module.file.change_time":
 
 		var ok bool
-		str, ok := value.(string)
+		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Target.FileFields.User"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.CTime"}
 		}
-		e.Link.Target.FileFields.User = str
-
+		e.LoadModule.File.FileFields.CTime = uint64(v)
 		return nil
 
-	case "link.file.filesystem":
+	case "load_module.file.filesystem":
 
 		var ok bool
 		str, ok := value.(string)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.Filesytem"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.Filesytem"}
 		}
-		e.Link.Source.Filesytem = str
+		e.LoadModule.File.Filesytem = str
 
 		return nil
 
-	case "link.file.gid":
+	case "load_module.file.gid":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.GID"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.GID"}
 		}
-		e.Link.Source.FileFields.GID = uint32(v)
+		e.LoadModule.File.FileFields.GID = uint32(v)
 		return nil
 
-	case "link.file.group":
+	case "load_module.file.group":
 
 		var ok bool
 		str, ok := value.(string)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Group"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.Group"}
 		}
-		e.Link.Source.FileFields.Group = str
+		e.LoadModule.File.FileFields.Group = str
 
 		return nil
 
-	case "link.file.in_upper_layer":
+	case "load_module.file.in_upper_layer":
 
 		var ok bool
-		if e.Link.Source.FileFields.InUpperLayer, ok = value.(bool); !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.InUpperLayer"}
+		if e.LoadModule.File.FileFields.InUpperLayer, ok = value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.InUpperLayer"}
 		}
 		return nil
 
-	case "link.file.inode":
+	case "load_module.file.inode":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Inode"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.Inode"}
 		}
-		e.Link.Source.FileFields.Inode = uint64(v)
+		e.LoadModule.File.FileFields.Inode = uint64(v)
 		return nil
 
-	case "link.file.mode":
+	case "load_module.file.mode":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Mode"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.Mode"}
 		}
-		e.Link.Source.FileFields.Mode = uint16(v)
+		e.LoadModule.File.FileFields.Mode = uint16(v)
 		return nil
 
-	case "link.file.modification_time":
+	case "load_module.file.modification_time":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.MTime"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.MTime"}
 		}
-		e.Link.Source.FileFields.MTime = uint64(v)
+		e.LoadModule.File.FileFields.MTime = uint64(v)
 		return nil
 
-	case "link.file.mount_id":
+	case "load_module.file.mount_id":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.MountID"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.MountID"}
 		}
-		e.Link.Source.FileFields.MountID = uint32(v)
+		e.LoadModule.File.FileFields.MountID = uint32(v)
 		return nil
 
-	case "link.file.name":
+	case "load_module.file.name":
 
 		var ok bool
 		str, ok := value.(string)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.BasenameStr"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.BasenameStr"}
 		}
-		e.Link.Source.BasenameStr = str
+		e.LoadModule.File.BasenameStr = str
 
 		return nil
 
-	case "link.file.path":
+	case "load_module.file.path":
 
 		var ok bool
 		str, ok := value.(string)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.PathnameStr"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.PathnameStr"}
 		}
-		e.Link.Source.PathnameStr = str
+		e.LoadModule.File.PathnameStr = str
 
 		return nil
 
-	case "link.file.rights":
+	case "load_module.file.rights":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.Mode"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.Mode"}
 		}
-		e.Link.Source.FileFields.Mode = uint16(v)
+		e.LoadModule.File.FileFields.Mode = uint16(v)
 		return nil
 
-	case "link.file.uid":
+	case "load_module.file.uid":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.UID"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.UID"}
 		}
-		e.Link.Source.FileFields.UID = uint32(v)
+		e.LoadModule.File.FileFields.UID = uint32(v)
 		return nil
 
-	case "link.file.user":
+	case "load_module.file.user":
 
 		var ok bool
 		str, ok := value.(string)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.Source.FileFields.User"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.File.FileFields.User"}
 		}
-		e.Link.Source.FileFields.User = str
+		e.LoadModule.File.FileFields.User = str
 
 		return nil
 
-	case "link.retval":
+	case "load_module.loaded_from_memory":
+
+		var ok bool
+		if e.LoadModule.LoadedFromMemory, ok = value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.LoadedFromMemory"}
+		}
+		return nil
+
+	case "load_module.name":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.Name"}
+		}
+		e.LoadModule.Name = str
+
+		return nil
+
+	case "load_module.retval":
 
 		var ok bool
 		v, ok := value.(int)
 		if !ok {
-			return &eval.ErrValueTypeMismatch{Field: "Link.SyscallEvent.Retval"}
+			return &eval.ErrValueTypeMismatch{Field: "LoadModule.SyscallEvent.Retval"}
 		}
-		e.Link.SyscallEvent.Retval = int64(v)
+		e.LoadModule.SyscallEvent.Retval = int64(v)
 		return nil
 
 	case "mkdir.file.change_time":
@@ -10485,6 +12191,209 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Mkdir.SyscallEvent.Retval = int64(v)
 		return nil
 
+	case "mmap.file.change_time":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.CTime"}
+		}
+		e.MMap.File.FileFields.CTime = uint64(v)
+		return nil
+
+	case "mmap.file.filesystem":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.Filesytem"}
+		}
+		e.MMap.File.Filesytem = str
+
+		return nil
+
+	case "mmap.file.gid":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.GID"}
+		}
+		e.MMap.File.FileFields.GID = uint32(v)
+		return nil
+
+	case "mmap.file.group":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.Group"}
+		}
+		e.MMap.File.FileFields.Group = str
+
+		return nil
+
+	case "mmap.file.in_upper_layer":
+
+		var ok bool
+		if e.MMap.File.FileFields.InUpperLayer, ok = value.(bool); !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.InUpperLayer"}
+		}
+		return nil
+
+	case "mmap.file.inode":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.Inode"}
+		}
+		e.MMap.File.FileFields.Inode = uint64(v)
+		return nil
+
+	case "mmap.file.mode":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.Mode"}
+		}
+		e.MMap.File.FileFields.Mode = uint16(v)
+		return nil
+
+	case "mmap.file.modification_time":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.MTime"}
+		}
+		e.MMap.File.FileFields.MTime = uint64(v)
+		return nil
+
+	case "mmap.file.mount_id":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.MountID"}
+		}
+		e.MMap.File.FileFields.MountID = uint32(v)
+		return nil
+
+	case "mmap.file.name":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.BasenameStr"}
+		}
+		e.MMap.File.BasenameStr = str
+
+		return nil
+
+	case "mmap.file.path":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.PathnameStr"}
+		}
+		e.MMap.File.PathnameStr = str
+
+		return nil
+
+	case "mmap.file.rights":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.Mode"}
+		}
+		e.MMap.File.FileFields.Mode = uint16(v)
+		return nil
+
+	case "mmap.file.uid":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.UID"}
+		}
+		e.MMap.File.FileFields.UID = uint32(v)
+		return nil
+
+	case "mmap.file.user":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.File.FileFields.User"}
+		}
+		e.MMap.File.FileFields.User = str
+
+		return nil
+
+	case "mmap.flags":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.Flags"}
+		}
+		e.MMap.Flags = int(v)
+		return nil
+
+	case "mmap.protection":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.Protection"}
+		}
+		e.MMap.Protection = int(v)
+		return nil
+
+	case "mmap.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MMap.SyscallEvent.Retval"}
+		}
+		e.MMap.SyscallEvent.Retval = int64(v)
+		return nil
+
+	case "mprotect.req_protection":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MProtect.ReqProtection"}
+		}
+		e.MProtect.ReqProtection = int(v)
+		return nil
+
+	case "mprotect.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MProtect.SyscallEvent.Retval"}
+		}
+		e.MProtect.SyscallEvent.Retval = int64(v)
+		return nil
+
+	case "mprotect.vm_protection":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "MProtect.VMProtection"}
+		}
+		e.MProtect.VMProtection = int(v)
+		return nil
+
 	case "open.file.change_time":
 
 		var ok bool
@@ -11546,6 +13455,36 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 
 		return nil
 
+	case "ptrace.request":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "PTrace.Request"}
+		}
+		e.PTrace.Request = uint32(v)
+		return nil
+
+	case "ptrace.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "PTrace.SyscallEvent.Retval"}
+		}
+		e.PTrace.SyscallEvent.Retval = int64(v)
+		return nil
+
+	case "ptrace.tracee.pid":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "PTrace.PID"}
+		}
+		e.PTrace.PID = uint32(v)
+		return nil
+
 	case "removexattr.file.change_time":
 
 		var ok bool
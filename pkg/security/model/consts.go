@@ -267,12 +267,123 @@ var (
 		"AT_REMOVEDIR": unix.AT_REMOVEDIR,
 	}
 
+	addressFamilyConstants = map[string]int{
+		"AF_INET":  unix.AF_INET,
+		"AF_INET6": unix.AF_INET6,
+	}
+
+	// DNSQTypeConstants see https://www.iana.org/assignments/dns-parameters/dns-parameters.xhtml#dns-parameters-4
+	DNSQTypeConstants = map[string]int{
+		"A":     1,
+		"NS":    2,
+		"CNAME": 5,
+		"SOA":   6,
+		"PTR":   12,
+		"MX":    15,
+		"TXT":   16,
+		"AAAA":  28,
+		"SRV":   33,
+		"NAPTR": 35,
+		"DS":    43,
+		"SVCB":  64,
+		"HTTPS": 65,
+		"ANY":   255,
+	}
+
+	// DNSQClassConstants see https://www.iana.org/assignments/dns-parameters/dns-parameters.xhtml#dns-parameters-2
+	DNSQClassConstants = map[string]int{
+		"CLASS_INET":   1,
+		"CLASS_CSNET":  2,
+		"CLASS_CHAOS":  3,
+		"CLASS_HESIOD": 4,
+		"CLASS_NONE":   254,
+		"CLASS_ANY":    255,
+	}
+
+	// ptraceConstants are the ptrace requests reported by the ptrace event
+	ptraceConstants = map[string]int{
+		"PTRACE_TRACEME":  unix.PTRACE_TRACEME,
+		"PTRACE_ATTACH":   unix.PTRACE_ATTACH,
+		"PTRACE_SEIZE":    unix.PTRACE_SEIZE,
+		"PTRACE_POKETEXT": unix.PTRACE_POKETEXT,
+		"PTRACE_POKEDATA": unix.PTRACE_POKEDATA,
+		"PTRACE_POKEUSR":  unix.PTRACE_POKEUSR,
+	}
+
+	protConstants = map[string]int{
+		"PROT_NONE":  unix.PROT_NONE,
+		"PROT_READ":  unix.PROT_READ,
+		"PROT_WRITE": unix.PROT_WRITE,
+		"PROT_EXEC":  unix.PROT_EXEC,
+	}
+
+	mmapFlagConstants = map[string]int{
+		"MAP_SHARED":    unix.MAP_SHARED,
+		"MAP_PRIVATE":   unix.MAP_PRIVATE,
+		"MAP_FIXED":     unix.MAP_FIXED,
+		"MAP_ANONYMOUS": unix.MAP_ANONYMOUS,
+		"MAP_GROWSDOWN": unix.MAP_GROWSDOWN,
+		"MAP_DENYWRITE": unix.MAP_DENYWRITE,
+		"MAP_LOCKED":    unix.MAP_LOCKED,
+		"MAP_NORESERVE": unix.MAP_NORESERVE,
+		"MAP_POPULATE":  unix.MAP_POPULATE,
+		"MAP_NONBLOCK":  unix.MAP_NONBLOCK,
+		"MAP_STACK":     unix.MAP_STACK,
+		"MAP_HUGETLB":   unix.MAP_HUGETLB,
+	}
+
+	bpfCmdConstants = map[string]int{
+		"BPF_PROG_LOAD": unix.BPF_PROG_LOAD,
+	}
+
+	bpfProgTypeConstants = map[string]int{
+		"BPF_PROG_TYPE_UNSPEC":                  unix.BPF_PROG_TYPE_UNSPEC,
+		"BPF_PROG_TYPE_SOCKET_FILTER":           unix.BPF_PROG_TYPE_SOCKET_FILTER,
+		"BPF_PROG_TYPE_KPROBE":                  unix.BPF_PROG_TYPE_KPROBE,
+		"BPF_PROG_TYPE_SCHED_CLS":               unix.BPF_PROG_TYPE_SCHED_CLS,
+		"BPF_PROG_TYPE_SCHED_ACT":               unix.BPF_PROG_TYPE_SCHED_ACT,
+		"BPF_PROG_TYPE_TRACEPOINT":              unix.BPF_PROG_TYPE_TRACEPOINT,
+		"BPF_PROG_TYPE_XDP":                     unix.BPF_PROG_TYPE_XDP,
+		"BPF_PROG_TYPE_PERF_EVENT":              unix.BPF_PROG_TYPE_PERF_EVENT,
+		"BPF_PROG_TYPE_CGROUP_SKB":              unix.BPF_PROG_TYPE_CGROUP_SKB,
+		"BPF_PROG_TYPE_CGROUP_SOCK":             unix.BPF_PROG_TYPE_CGROUP_SOCK,
+		"BPF_PROG_TYPE_LWT_IN":                  unix.BPF_PROG_TYPE_LWT_IN,
+		"BPF_PROG_TYPE_LWT_OUT":                 unix.BPF_PROG_TYPE_LWT_OUT,
+		"BPF_PROG_TYPE_LWT_XMIT":                unix.BPF_PROG_TYPE_LWT_XMIT,
+		"BPF_PROG_TYPE_SOCK_OPS":                unix.BPF_PROG_TYPE_SOCK_OPS,
+		"BPF_PROG_TYPE_SK_SKB":                  unix.BPF_PROG_TYPE_SK_SKB,
+		"BPF_PROG_TYPE_CGROUP_DEVICE":           unix.BPF_PROG_TYPE_CGROUP_DEVICE,
+		"BPF_PROG_TYPE_SK_MSG":                  unix.BPF_PROG_TYPE_SK_MSG,
+		"BPF_PROG_TYPE_RAW_TRACEPOINT":          unix.BPF_PROG_TYPE_RAW_TRACEPOINT,
+		"BPF_PROG_TYPE_CGROUP_SOCK_ADDR":        unix.BPF_PROG_TYPE_CGROUP_SOCK_ADDR,
+		"BPF_PROG_TYPE_LWT_SEG6LOCAL":           unix.BPF_PROG_TYPE_LWT_SEG6LOCAL,
+		"BPF_PROG_TYPE_LIRC_MODE2":              unix.BPF_PROG_TYPE_LIRC_MODE2,
+		"BPF_PROG_TYPE_SK_REUSEPORT":            unix.BPF_PROG_TYPE_SK_REUSEPORT,
+		"BPF_PROG_TYPE_FLOW_DISSECTOR":          unix.BPF_PROG_TYPE_FLOW_DISSECTOR,
+		"BPF_PROG_TYPE_CGROUP_SYSCTL":           unix.BPF_PROG_TYPE_CGROUP_SYSCTL,
+		"BPF_PROG_TYPE_RAW_TRACEPOINT_WRITABLE": unix.BPF_PROG_TYPE_RAW_TRACEPOINT_WRITABLE,
+		"BPF_PROG_TYPE_CGROUP_SOCKOPT":          unix.BPF_PROG_TYPE_CGROUP_SOCKOPT,
+		"BPF_PROG_TYPE_TRACING":                 unix.BPF_PROG_TYPE_TRACING,
+		"BPF_PROG_TYPE_STRUCT_OPS":              unix.BPF_PROG_TYPE_STRUCT_OPS,
+		"BPF_PROG_TYPE_EXT":                     unix.BPF_PROG_TYPE_EXT,
+		"BPF_PROG_TYPE_LSM":                     unix.BPF_PROG_TYPE_LSM,
+	}
+
 	// SECLConstants are constants available in runtime security agent rules
 	SECLConstants = map[string]interface{}{
 		// boolean
 		"true":  &eval.BoolEvaluator{Value: true},
 		"false": &eval.BoolEvaluator{Value: false},
 	}
+
+	// SECLVariableScopes are the scopes of the variables available in runtime security agent rules
+	SECLVariableScopes = map[string]eval.VariableScope{
+		// the variables of the process scope are keyed by pid
+		"process": func(ctx *eval.Context) (interface{}, bool) {
+			pid := (*Event)(ctx.Object).ProcessContext.Pid
+			return pid, pid != 0
+		},
+	}
 )
 
 var (
@@ -280,6 +391,14 @@ var (
 	chmodModeStrings          = map[int]string{}
 	unlinkFlagsStrings        = map[int]string{}
 	kernelCapabilitiesStrings = map[uint64]string{}
+	addressFamilyStrings      = map[int]string{}
+	dnsQTypeStrings           = map[int]string{}
+	dnsQClassStrings          = map[int]string{}
+	ptraceStrings             = map[int]string{}
+	protStrings               = map[int]string{}
+	mmapFlagStrings           = map[int]string{}
+	bpfCmdStrings             = map[int]string{}
+	bpfProgTypeStrings        = map[int]string{}
 )
 
 // File flags
@@ -327,12 +446,70 @@ func initKernelCapabilityConstants() {
 	}
 }
 
+func initAddressFamilyConstants() {
+	for k, v := range addressFamilyConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		addressFamilyStrings[v] = k
+	}
+}
+
+func initDNSQTypeConstants() {
+	for k, v := range DNSQTypeConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		dnsQTypeStrings[v] = k
+	}
+}
+
+func initDNSQClassConstants() {
+	for k, v := range DNSQClassConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		dnsQClassStrings[v] = k
+	}
+}
+
+func initPTraceConstants() {
+	for k, v := range ptraceConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		ptraceStrings[v] = k
+	}
+}
+
+func initMMapConstants() {
+	for k, v := range protConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		protStrings[v] = k
+	}
+
+	for k, v := range mmapFlagConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		mmapFlagStrings[v] = k
+	}
+}
+
+func initBPFConstants() {
+	for k, v := range bpfCmdConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		bpfCmdStrings[v] = k
+	}
+
+	for k, v := range bpfProgTypeConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+		bpfProgTypeStrings[v] = k
+	}
+}
+
 func initConstants() {
 	initErrorConstants()
 	initOpenConstants()
 	initChmodConstants()
 	initUnlinkConstanst()
 	initKernelCapabilityConstants()
+	initAddressFamilyConstants()
+	initDNSQTypeConstants()
+	initDNSQClassConstants()
+	initPTraceConstants()
+	initMMapConstants()
+	initBPFConstants()
 }
 
 func bitmaskToStringArray(bitmask int, intToStrMap map[int]string) []string {
@@ -426,6 +603,96 @@ func (f UnlinkFlags) StringArray() []string {
 	return bitmaskToStringArray(int(f), unlinkFlagsStrings)
 }
 
+// AddressFamily represents a socket address family
+type AddressFamily int
+
+func (f AddressFamily) String() string {
+	if s, found := addressFamilyStrings[int(f)]; found {
+		return s
+	}
+	return fmt.Sprintf("%d", f)
+}
+
+// QType represents a DNS question type
+type QType int
+
+func (t QType) String() string {
+	if s, found := dnsQTypeStrings[int(t)]; found {
+		return s
+	}
+	return fmt.Sprintf("%d", t)
+}
+
+// QClass represents a DNS question class
+type QClass int
+
+func (c QClass) String() string {
+	if s, found := dnsQClassStrings[int(c)]; found {
+		return s
+	}
+	return fmt.Sprintf("%d", c)
+}
+
+// PTraceRequest represents a ptrace request value
+type PTraceRequest int
+
+func (r PTraceRequest) String() string {
+	if s, found := ptraceStrings[int(r)]; found {
+		return s
+	}
+	return fmt.Sprintf("%d", r)
+}
+
+// Protection represents a memory protection bitmask value
+type Protection int
+
+func (p Protection) String() string {
+	if int(p) == unix.PROT_NONE {
+		return protStrings[unix.PROT_NONE]
+	}
+	return bitmaskToString(int(p), protStrings)
+}
+
+// StringArray returns the memory protection as an array of strings
+func (p Protection) StringArray() []string {
+	if int(p) == unix.PROT_NONE {
+		return []string{protStrings[unix.PROT_NONE]}
+	}
+	return bitmaskToStringArray(int(p), protStrings)
+}
+
+// MMapFlag represents an mmap flags bitmask value
+type MMapFlag int
+
+func (f MMapFlag) String() string {
+	return bitmaskToString(int(f), mmapFlagStrings)
+}
+
+// StringArray returns the mmap flags as an array of strings
+func (f MMapFlag) StringArray() []string {
+	return bitmaskToStringArray(int(f), mmapFlagStrings)
+}
+
+// BPFCmd represents a bpf command value
+type BPFCmd int
+
+func (c BPFCmd) String() string {
+	if s, found := bpfCmdStrings[int(c)]; found {
+		return s
+	}
+	return fmt.Sprintf("%d", c)
+}
+
+// BPFProgType represents an eBPF program type value
+type BPFProgType int
+
+func (t BPFProgType) String() string {
+	if s, found := bpfProgTypeStrings[int(t)]; found {
+		return s
+	}
+	return fmt.Sprintf("%d", t)
+}
+
 // RetValError represents a syscall return error value
 type RetValError int
 
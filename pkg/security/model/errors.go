@@ -18,4 +18,10 @@ var (
 
 	// ErrNonPrintable returned when a string contains non printable char
 	ErrNonPrintable = errors.New("non printable")
+
+	// ErrDNSNameMalformed returned when a DNS question name can't be decoded
+	ErrDNSNameMalformed = errors.New("malformed DNS name")
+
+	// ErrDNSNamePointerNotSupported returned when a DNS question name uses message compression
+	ErrDNSNamePointerNotSupported = errors.New("DNS name compression pointers are not supported")
 )
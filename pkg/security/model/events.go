@@ -59,6 +59,22 @@ const (
 	MountReleasedEventType
 	// SELinuxEventType selinux event
 	SELinuxEventType
+	// ConnectEventType connect event
+	ConnectEventType
+	// BindEventType bind event
+	BindEventType
+	// DNSEventType DNS request event
+	DNSEventType
+	// LoadModuleEventType kernel module load event
+	LoadModuleEventType
+	// PTraceEventType ptrace event
+	PTraceEventType
+	// MMapEventType mmap event
+	MMapEventType
+	// MProtectEventType mprotect event
+	MProtectEventType
+	// BPFEventType bpf event
+	BPFEventType
 	// MaxEventType is used internally to get the maximum number of kernel events.
 	MaxEventType
 
@@ -80,6 +96,10 @@ const (
 	CustomForkBombEventType
 	// CustomTruncatedParentsEventType is the custom event used to report that the parents of a path were truncated
 	CustomTruncatedParentsEventType
+	// CustomActiveResponseEventType is the custom event used to report an action executed in response to a rule match
+	CustomActiveResponseEventType
+	// CustomActivityAnomalyEventType is the custom event used to report an activity outside of the profile of a container image
+	CustomActivityAnomalyEventType
 )
 
 func (t EventType) String() string {
@@ -130,6 +150,22 @@ func (t EventType) String() string {
 		return "mount_released"
 	case SELinuxEventType:
 		return "selinux"
+	case ConnectEventType:
+		return "connect"
+	case BindEventType:
+		return "bind"
+	case DNSEventType:
+		return "dns"
+	case LoadModuleEventType:
+		return "load_module"
+	case PTraceEventType:
+		return "ptrace"
+	case MMapEventType:
+		return "mmap"
+	case MProtectEventType:
+		return "mprotect"
+	case BPFEventType:
+		return "bpf"
 
 	case CustomLostReadEventType:
 		return "lost_events_read"
@@ -143,6 +179,10 @@ func (t EventType) String() string {
 		return "fork_bomb"
 	case CustomTruncatedParentsEventType:
 		return "truncated_parents"
+	case CustomActiveResponseEventType:
+		return "active_response"
+	case CustomActivityAnomalyEventType:
+		return "activity_anomaly"
 	default:
 		return "unknown"
 	}
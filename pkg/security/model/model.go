@@ -135,6 +135,16 @@ type Event struct {
 
 	SELinux SELinuxEvent `field:"selinux" event:"selinux"`
 
+	Connect ConnectEvent `field:"connect" event:"connect"`
+	Bind    BindEvent    `field:"bind" event:"bind"`
+	DNS     DNSEvent     `field:"dns" event:"dns"`
+
+	LoadModule LoadModuleEvent `field:"load_module" event:"load_module"`
+	PTrace     PTraceEvent     `field:"ptrace" event:"ptrace"`
+	MMap       MMapEvent       `field:"mmap" event:"mmap"`
+	MProtect   MProtectEvent   `field:"mprotect" event:"mprotect"`
+	BPF        BPFEvent        `field:"bpf" event:"bpf"`
+
 	Mount            MountEvent            `field:"-"`
 	Umount           UmountEvent           `field:"-"`
 	InvalidateDentry InvalidateDentryEvent `field:"-"`
@@ -430,6 +440,79 @@ type SELinuxEvent struct {
 	EnforceStatus   string           `field:"enforce.status"`
 }
 
+// IPPortContext holds a socket address
+type IPPortContext struct {
+	Family uint16 `field:"family"`
+	IP     string `field:"ip"`
+	Port   uint16 `field:"port"`
+}
+
+// ConnectEvent represents a connect event
+type ConnectEvent struct {
+	SyscallEvent
+	Addr IPPortContext `field:"addr"`
+}
+
+// BindEvent represents a bind event
+type BindEvent struct {
+	SyscallEvent
+	Addr IPPortContext `field:"addr"`
+}
+
+// DNSEvent represents an outgoing DNS request event
+type DNSEvent struct {
+	ID    uint16 `field:"id"`
+	Name  string `field:"question.name"`
+	Type  uint16 `field:"question.type"`
+	Class uint16 `field:"question.class"`
+	Size  uint16 `field:"question.length"`
+	Count uint16 `field:"question.count"`
+}
+
+// LoadModuleEvent represents a kernel module load event
+type LoadModuleEvent struct {
+	SyscallEvent
+	File             FileEvent `field:"file"`
+	Name             string    `field:"name"`
+	LoadedFromMemory bool      `field:"loaded_from_memory"`
+}
+
+// PTraceEvent represents a ptrace event
+type PTraceEvent struct {
+	SyscallEvent
+	Request uint32 `field:"request"`
+	PID     uint32 `field:"tracee.pid"`
+	Address uint64 `field:"-"`
+}
+
+// MMapEvent represents an executable memory mapping event
+type MMapEvent struct {
+	SyscallEvent
+	File       FileEvent `field:"file"`
+	Addr       uint64    `field:"-"`
+	Offset     uint64    `field:"-"`
+	Len        uint32    `field:"-"`
+	Protection int       `field:"protection"`
+	Flags      int       `field:"flags"`
+}
+
+// MProtectEvent represents an mprotect event adding the exec permission to a memory region
+type MProtectEvent struct {
+	SyscallEvent
+	VMStart       uint64 `field:"-"`
+	VMEnd         uint64 `field:"-"`
+	VMProtection  int    `field:"vm_protection"`
+	ReqProtection int    `field:"req_protection"`
+}
+
+// BPFEvent represents an eBPF program load event
+type BPFEvent struct {
+	SyscallEvent
+	Cmd      uint32 `field:"cmd"`
+	ProgType uint32 `field:"prog.type"`
+	ProgName string `field:"prog.name"`
+}
+
 var zeroProcessContext ProcessContext
 
 // ProcessCacheEntry this struct holds process context kept in the process tree
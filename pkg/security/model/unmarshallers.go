@@ -9,6 +9,9 @@ package model
 
 import (
 	"bytes"
+	"encoding/binary"
+	"net"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -485,3 +488,227 @@ func (e *MountReleasedEvent) UnmarshalBinary(data []byte) (int, error) {
 
 	return 8, nil
 }
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *IPPortContext) UnmarshalBinary(data []byte) (int, error) {
+	if len(data) < 24 {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Family = ByteOrder.Uint16(data[16:18])
+	// the port is kept in network byte order by the kernel
+	e.Port = binary.BigEndian.Uint16(data[18:20])
+
+	switch e.Family {
+	case 2: // AF_INET
+		e.IP = net.IP(data[0:4]).String()
+	case 10: // AF_INET6
+		e.IP = net.IP(data[0:16]).String()
+	default:
+		e.IP = ""
+	}
+
+	return 24, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ConnectEvent) UnmarshalBinary(data []byte) (int, error) {
+	return UnmarshalBinary(data, &e.SyscallEvent, &e.Addr)
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *BindEvent) UnmarshalBinary(data []byte) (int, error) {
+	return UnmarshalBinary(data, &e.SyscallEvent, &e.Addr)
+}
+
+const (
+	// moduleNameLength is the length of the name of a kernel module, MODULE_NAME_LEN in the kernel
+	moduleNameLength = 56
+	// bpfObjNameLength is the length of the name of an eBPF object, BPF_OBJ_NAME_LEN in the kernel
+	bpfObjNameLength = 16
+)
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *LoadModuleEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := UnmarshalBinary(data, &e.SyscallEvent, &e.File)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < moduleNameLength+8 {
+		return n, ErrNotEnoughData
+	}
+
+	e.Name, err = UnmarshalString(data, moduleNameLength)
+	if err != nil {
+		return n, err
+	}
+	e.LoadedFromMemory = ByteOrder.Uint32(data[moduleNameLength:moduleNameLength+4]) == 1
+
+	// +4 for padding
+
+	return n + moduleNameLength + 8, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *PTraceEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := UnmarshalBinary(data, &e.SyscallEvent)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < 16 {
+		return n, ErrNotEnoughData
+	}
+
+	e.Request = ByteOrder.Uint32(data[0:4])
+	e.PID = ByteOrder.Uint32(data[4:8])
+	e.Address = ByteOrder.Uint64(data[8:16])
+	return n + 16, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *MMapEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := UnmarshalBinary(data, &e.SyscallEvent, &e.File)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < 32 {
+		return n, ErrNotEnoughData
+	}
+
+	e.Addr = ByteOrder.Uint64(data[0:8])
+	e.Offset = ByteOrder.Uint64(data[8:16])
+	e.Len = ByteOrder.Uint32(data[16:20])
+	e.Protection = int(ByteOrder.Uint32(data[20:24]))
+	e.Flags = int(ByteOrder.Uint32(data[24:28]))
+
+	// +4 for padding
+
+	return n + 32, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *MProtectEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := UnmarshalBinary(data, &e.SyscallEvent)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < 24 {
+		return n, ErrNotEnoughData
+	}
+
+	e.VMStart = ByteOrder.Uint64(data[0:8])
+	e.VMEnd = ByteOrder.Uint64(data[8:16])
+	e.VMProtection = int(ByteOrder.Uint32(data[16:20]))
+	e.ReqProtection = int(ByteOrder.Uint32(data[20:24]))
+	return n + 24, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *BPFEvent) UnmarshalBinary(data []byte) (int, error) {
+	n, err := UnmarshalBinary(data, &e.SyscallEvent)
+	if err != nil {
+		return n, err
+	}
+
+	data = data[n:]
+	if len(data) < 8+bpfObjNameLength {
+		return n, ErrNotEnoughData
+	}
+
+	e.Cmd = ByteOrder.Uint32(data[0:4])
+	e.ProgType = ByteOrder.Uint32(data[4:8])
+	e.ProgName, err = UnmarshalString(data[8:], bpfObjNameLength)
+	if err != nil {
+		return n, err
+	}
+	return n + 8 + bpfObjNameLength, nil
+}
+
+const (
+	dnsHeaderLength    = 12
+	dnsMaxPayloadSize  = 256
+	dnsMaxNameLength   = 253
+	dnsLabelPointerBit = 0xC0
+)
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *DNSEvent) UnmarshalBinary(data []byte) (int, error) {
+	if len(data) < 8+dnsMaxPayloadSize {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Size = ByteOrder.Uint16(data[0:2])
+	if e.Size < dnsHeaderLength {
+		return 0, ErrDNSNameMalformed
+	}
+	read := 8 + dnsMaxPayloadSize
+
+	// only the beginning of large requests is captured
+	captured := int(e.Size)
+	if captured > dnsMaxPayloadSize {
+		captured = dnsMaxPayloadSize
+	}
+
+	// the DNS payload is in network byte order
+	payload := data[8 : 8+captured]
+	e.ID = binary.BigEndian.Uint16(payload[0:2])
+	e.Count = binary.BigEndian.Uint16(payload[4:6])
+
+	name, n, err := decodeDNSName(payload[dnsHeaderLength:])
+	if err != nil {
+		return read, err
+	}
+	e.Name = name
+
+	question := payload[dnsHeaderLength+n:]
+	if len(question) < 4 {
+		return read, ErrNotEnoughData
+	}
+	e.Type = binary.BigEndian.Uint16(question[0:2])
+	e.Class = binary.BigEndian.Uint16(question[2:4])
+
+	return read, nil
+}
+
+// decodeDNSName decodes the labels of a DNS question name and returns the
+// number of bytes it uses in the payload
+func decodeDNSName(raw []byte) (string, int, error) {
+	var labels []string
+	var length, i int
+
+	for {
+		if i >= len(raw) {
+			return "", i, ErrNotEnoughData
+		}
+
+		labelLength := int(raw[i])
+		i++
+		if labelLength == 0 {
+			break
+		}
+		if labelLength&dnsLabelPointerBit != 0 {
+			return "", i, ErrDNSNamePointerNotSupported
+		}
+		if i+labelLength > len(raw) {
+			return "", i, ErrNotEnoughData
+		}
+
+		length += labelLength + 1
+		if length > dnsMaxNameLength+1 {
+			return "", i, ErrDNSNameMalformed
+		}
+
+		labels = append(labels, string(raw[i:i+labelLength]))
+		i += labelLength
+	}
+
+	return strings.Join(labels, "."), i, nil
+}
@@ -0,0 +1,153 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package model
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDNSEventData(size int, payload []byte) []byte {
+	data := make([]byte, 8+dnsMaxPayloadSize)
+	ByteOrder.PutUint16(data[0:2], uint16(size))
+	copy(data[8:], payload)
+	return data
+}
+
+func newDNSPayload(id uint16, labels []string, qtype, qclass uint16) []byte {
+	payload := make([]byte, dnsHeaderLength)
+	binary.BigEndian.PutUint16(payload[0:2], id)
+	binary.BigEndian.PutUint16(payload[4:6], 1)
+	for _, label := range labels {
+		payload = append(payload, byte(len(label)))
+		payload = append(payload, label...)
+	}
+	payload = append(payload, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint16(payload[len(payload)-4:], qtype)
+	binary.BigEndian.PutUint16(payload[len(payload)-2:], qclass)
+	return payload
+}
+
+func TestDNSEventUnmarshal(t *testing.T) {
+	payload := newDNSPayload(0x1234, []string{"www", "datadoghq", "com"}, 28, 1)
+
+	var e DNSEvent
+	n, err := e.UnmarshalBinary(newDNSEventData(len(payload), payload))
+	assert.NoError(t, err)
+	assert.Equal(t, 8+dnsMaxPayloadSize, n)
+	assert.Equal(t, uint16(0x1234), e.ID)
+	assert.Equal(t, "www.datadoghq.com", e.Name)
+	assert.Equal(t, uint16(28), e.Type)
+	assert.Equal(t, uint16(1), e.Class)
+	assert.Equal(t, uint16(len(payload)), e.Size)
+	assert.Equal(t, uint16(1), e.Count)
+
+	t.Run("truncated", func(t *testing.T) {
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(20, payload))
+		assert.Equal(t, ErrNotEnoughData, err)
+	})
+
+	t.Run("compression-pointer", func(t *testing.T) {
+		payload := make([]byte, dnsHeaderLength+6)
+		payload[dnsHeaderLength] = 0xC0
+
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(len(payload), payload))
+		assert.Equal(t, ErrDNSNamePointerNotSupported, err)
+	})
+
+	t.Run("large-request", func(t *testing.T) {
+		var e DNSEvent
+		_, err := e.UnmarshalBinary(newDNSEventData(1024, payload))
+		assert.NoError(t, err)
+		assert.Equal(t, "www.datadoghq.com", e.Name)
+		assert.Equal(t, uint16(1024), e.Size)
+	})
+}
+
+func TestConnectEventUnmarshal(t *testing.T) {
+	data := make([]byte, 32)
+	ByteOrder.PutUint64(data[0:8], 0)
+	copy(data[8:12], []byte{127, 0, 0, 1})
+	ByteOrder.PutUint16(data[24:26], 2)
+	binary.BigEndian.PutUint16(data[26:28], 8080)
+
+	var e ConnectEvent
+	n, err := e.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 32, n)
+	assert.Equal(t, "127.0.0.1", e.Addr.IP)
+	assert.Equal(t, uint16(8080), e.Addr.Port)
+	assert.Equal(t, "AF_INET", AddressFamily(e.Addr.Family).String())
+
+	copy(data[8:24], []byte{0xfe, 0x80, 15: 1})
+	ByteOrder.PutUint16(data[24:26], 10)
+
+	var b BindEvent
+	_, err = b.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "fe80::1", b.Addr.IP)
+	assert.Equal(t, "AF_INET6", AddressFamily(b.Addr.Family).String())
+}
+
+func TestLoadModuleEventUnmarshal(t *testing.T) {
+	data := make([]byte, 8+72+moduleNameLength+8)
+	ByteOrder.PutUint64(data[8:16], 42)
+	copy(data[80:], "nf_tables")
+	ByteOrder.PutUint32(data[80+moduleNameLength:], 1)
+
+	var e LoadModuleEvent
+	n, err := e.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, uint64(42), e.File.Inode)
+	assert.Equal(t, "nf_tables", e.Name)
+	assert.True(t, e.LoadedFromMemory)
+
+	_, err = e.UnmarshalBinary(data[:len(data)-1])
+	assert.Equal(t, ErrNotEnoughData, err)
+}
+
+func TestMMapEventUnmarshal(t *testing.T) {
+	data := make([]byte, 8+72+32)
+	ByteOrder.PutUint64(data[0:8], 0x7f0000001000)
+	ByteOrder.PutUint64(data[80:88], 0x7f0000001000)
+	ByteOrder.PutUint64(data[88:96], 4096)
+	ByteOrder.PutUint32(data[96:100], 8192)
+	ByteOrder.PutUint32(data[100:104], 0x5)
+	ByteOrder.PutUint32(data[104:108], 0x22)
+
+	var e MMapEvent
+	n, err := e.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, uint64(0x7f0000001000), e.Addr)
+	assert.Equal(t, uint64(4096), e.Offset)
+	assert.Equal(t, uint32(8192), e.Len)
+	assert.Equal(t, "PROT_EXEC | PROT_READ", Protection(e.Protection).String())
+	assert.Equal(t, "MAP_ANONYMOUS | MAP_PRIVATE", MMapFlag(e.Flags).String())
+	assert.Equal(t, "PROT_NONE", Protection(0).String())
+}
+
+func TestBPFEventUnmarshal(t *testing.T) {
+	data := make([]byte, 8+8+bpfObjNameLength)
+	ByteOrder.PutUint32(data[8:12], 5)
+	ByteOrder.PutUint32(data[12:16], 2)
+	copy(data[16:], "kprobe_open")
+
+	var e BPFEvent
+	n, err := e.UnmarshalBinary(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, "BPF_PROG_LOAD", BPFCmd(e.Cmd).String())
+	assert.Equal(t, "BPF_PROG_TYPE_KPROBE", BPFProgType(e.ProgType).String())
+	assert.Equal(t, "kprobe_open", e.ProgName)
+}
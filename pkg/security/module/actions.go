@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+	"golang.org/x/time/rate"
+
+	sconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+	"github.com/DataDog/datadog-agent/pkg/security/model"
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+)
+
+const (
+	// ActionStatusPerformed reports that the action was executed
+	ActionStatusPerformed = "performed"
+	// ActionStatusDryRun reports that the action would have been executed without the dry run mode
+	ActionStatusDryRun = "dry_run"
+	// ActionStatusRateLimited reports that the action was dropped by the per rule rate limiter
+	ActionStatusRateLimited = "rate_limited"
+	// ActionStatusProtected reports that the action targeted a protected process
+	ActionStatusProtected = "protected"
+	// ActionStatusError reports that the action failed
+	ActionStatusError = "error"
+)
+
+// ActionReport describes the outcome of an action
+type ActionReport struct {
+	Kill   *rules.KillDefinition
+	PID    uint32
+	Status string
+	Err    error
+}
+
+// ActionExecutor executes the actions declared by the rules, with the safeguards defined in the configuration
+type ActionExecutor struct {
+	sync.Mutex
+	config            *sconfig.Config
+	statsdClient      *statsd.Client
+	limiters          map[rules.RuleID]*rate.Limiter
+	protectedPIDs     map[uint32]bool
+	protectedBinaries map[string]bool
+	killFnc           func(pid int, sig syscall.Signal) error
+}
+
+// NewActionExecutor returns a new ActionExecutor
+func NewActionExecutor(cfg *sconfig.Config, statsdClient *statsd.Client) *ActionExecutor {
+	ae := &ActionExecutor{
+		config:       cfg,
+		statsdClient: statsdClient,
+		limiters:     make(map[rules.RuleID]*rate.Limiter),
+		protectedPIDs: map[uint32]bool{
+			1:                   true,
+			uint32(os.Getpid()): true,
+		},
+		protectedBinaries: make(map[string]bool),
+		killFnc:           syscall.Kill,
+	}
+
+	for _, pid := range cfg.ActiveResponseProtectedPIDs {
+		ae.protectedPIDs[pid] = true
+	}
+
+	for _, path := range cfg.ActiveResponseProtectedBinaries {
+		ae.protectedBinaries[path] = true
+	}
+
+	if path, err := os.Executable(); err == nil {
+		ae.protectedBinaries[path] = true
+	}
+
+	return ae
+}
+
+// Apply resets the rate limiters according to the new set of rules
+func (ae *ActionExecutor) Apply(ruleIDs []rules.RuleID) {
+	ae.Lock()
+	defer ae.Unlock()
+
+	limiters := make(map[rules.RuleID]*rate.Limiter)
+	for _, id := range ruleIDs {
+		if limiter, exists := ae.limiters[id]; exists {
+			limiters[id] = limiter
+		}
+	}
+	ae.limiters = limiters
+}
+
+func (ae *ActionExecutor) allow(ruleID rules.RuleID) bool {
+	ae.Lock()
+	defer ae.Unlock()
+
+	limiter, exists := ae.limiters[ruleID]
+	if !exists {
+		limit := ae.config.ActiveResponseRateLimit
+		if limit < 1 {
+			limit = 1
+		}
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(limit)), limit)
+		ae.limiters[ruleID] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+func (ae *ActionExecutor) isProtected(entry *model.ProcessCacheEntry) bool {
+	return ae.protectedPIDs[entry.Pid] || ae.protectedBinaries[entry.PathnameStr]
+}
+
+// resolveTarget returns the process targeted by a kill action of the given scope
+func resolveTarget(entry *model.ProcessCacheEntry, scope string) (*model.ProcessCacheEntry, error) {
+	if entry == nil || entry.Pid == 0 {
+		return nil, errors.New("process not resolved")
+	}
+
+	switch scope {
+	case rules.KillScopeProcess:
+		return entry, nil
+	case rules.KillScopeContainer:
+		if entry.ContainerID == "" {
+			return nil, errors.New("process not running in a container")
+		}
+
+		// the oldest ancestor sharing the container ID is the init process of the container
+		target := entry
+		for ancestor := entry.Ancestor; ancestor != nil && ancestor.ContainerID == entry.ContainerID; ancestor = ancestor.Ancestor {
+			target = ancestor
+		}
+		return target, nil
+	default:
+		return nil, fmt.Errorf("unsupported scope `%s`", scope)
+	}
+}
+
+func (ae *ActionExecutor) kill(ruleID rules.RuleID, kill *rules.KillDefinition, entry *model.ProcessCacheEntry) *ActionReport {
+	report := &ActionReport{Kill: kill}
+
+	target, err := resolveTarget(entry, kill.GetScope())
+	if err != nil {
+		report.Status, report.Err = ActionStatusError, err
+		return report
+	}
+	report.PID = target.Pid
+
+	if ae.isProtected(target) {
+		report.Status = ActionStatusProtected
+		return report
+	}
+
+	if !ae.allow(ruleID) {
+		report.Status = ActionStatusRateLimited
+		return report
+	}
+
+	if ae.config.ActiveResponseDryRun {
+		report.Status = ActionStatusDryRun
+		return report
+	}
+
+	sig := unix.SignalNum(kill.GetSignal())
+	if sig == 0 {
+		report.Status, report.Err = ActionStatusError, fmt.Errorf("unsupported signal `%s`", kill.GetSignal())
+		return report
+	}
+
+	if err := ae.killFnc(int(target.Pid), sig); err != nil {
+		report.Status, report.Err = ActionStatusError, err
+		return report
+	}
+	report.Status = ActionStatusPerformed
+
+	return report
+}
+
+func (ae *ActionExecutor) execute(rule *rules.Rule, entry *model.ProcessCacheEntry) []*ActionReport {
+	var reports []*ActionReport
+
+	for _, action := range rule.Definition.Actions {
+		if action.Kill == nil {
+			continue
+		}
+
+		report := ae.kill(rule.ID, action.Kill, entry)
+		if report.Err != nil {
+			seclog.Errorf("failed to execute action of rule `%s` on pid %d: %s", rule.ID, report.PID, report.Err)
+		} else {
+			seclog.Debugf("action of rule `%s` on pid %d: %s", rule.ID, report.PID, report.Status)
+		}
+
+		if ae.statsdClient != nil {
+			tags := []string{"rule_id:" + rule.ID, "action:kill", "status:" + report.Status}
+			_ = ae.statsdClient.Count(metrics.MetricActiveResponse, 1, tags, 1.0)
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// Execute runs the actions of the rule against the process that triggered the event and returns their reports
+func (ae *ActionExecutor) Execute(rule *rules.Rule, event *sprobe.Event) []*ActionReport {
+	if len(rule.Definition.Actions) == 0 {
+		return nil
+	}
+
+	return ae.execute(rule, event.ResolveProcessCacheEntry())
+}
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/model"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+type killCall struct {
+	pid int
+	sig syscall.Signal
+}
+
+func newTestActionExecutor(cfg *sconfig.Config) (*ActionExecutor, *[]killCall) {
+	var calls []killCall
+
+	ae := NewActionExecutor(cfg, nil)
+	ae.killFnc = func(pid int, sig syscall.Signal) error {
+		calls = append(calls, killCall{pid: pid, sig: sig})
+		return nil
+	}
+
+	return ae, &calls
+}
+
+func newTestActionRule(id string, kill *rules.KillDefinition) *rules.Rule {
+	return &rules.Rule{
+		Rule: &eval.Rule{ID: id},
+		Definition: &rules.RuleDefinition{
+			ID:      id,
+			Actions: []rules.ActionDefinition{{Kill: kill}},
+		},
+	}
+}
+
+func newTestProcessEntry(pid uint32, path string, containerID string, ancestor *model.ProcessCacheEntry) *model.ProcessCacheEntry {
+	entry := &model.ProcessCacheEntry{}
+	entry.Pid = pid
+	entry.PathnameStr = path
+	entry.ContainerID = containerID
+	entry.Ancestor = ancestor
+	return entry
+}
+
+func TestActionKill(t *testing.T) {
+	ae, calls := newTestActionExecutor(&sconfig.Config{ActiveResponseRateLimit: 5})
+
+	rule := newTestActionRule("reverse_shell", &rules.KillDefinition{Signal: "SIGTERM"})
+	reports := ae.execute(rule, newTestProcessEntry(4242, "/usr/bin/nc", "", nil))
+
+	assert.Len(t, reports, 1)
+	assert.Equal(t, ActionStatusPerformed, reports[0].Status)
+	assert.Equal(t, uint32(4242), reports[0].PID)
+	assert.Equal(t, []killCall{{pid: 4242, sig: syscall.SIGTERM}}, *calls)
+}
+
+func TestActionKillContainerScope(t *testing.T) {
+	ae, calls := newTestActionExecutor(&sconfig.Config{ActiveResponseRateLimit: 5})
+
+	host := newTestProcessEntry(1000, "/usr/bin/containerd-shim", "", nil)
+	containerInit := newTestProcessEntry(1001, "/bin/sh", "abc", host)
+	shell := newTestProcessEntry(1002, "/bin/bash", "abc", containerInit)
+
+	rule := newTestActionRule("reverse_shell", &rules.KillDefinition{Scope: rules.KillScopeContainer})
+	reports := ae.execute(rule, newTestProcessEntry(1003, "/usr/bin/nc", "abc", shell))
+
+	assert.Len(t, reports, 1)
+	assert.Equal(t, ActionStatusPerformed, reports[0].Status)
+	assert.Equal(t, []killCall{{pid: 1001, sig: syscall.SIGKILL}}, *calls)
+
+	reports = ae.execute(rule, newTestProcessEntry(1004, "/usr/bin/nc", "", nil))
+	assert.Equal(t, ActionStatusError, reports[0].Status)
+	assert.Error(t, reports[0].Err)
+	assert.Len(t, *calls, 1)
+}
+
+func TestActionKillProtected(t *testing.T) {
+	ae, calls := newTestActionExecutor(&sconfig.Config{
+		ActiveResponseRateLimit:         5,
+		ActiveResponseProtectedPIDs:     []uint32{33},
+		ActiveResponseProtectedBinaries: []string{"/usr/sbin/sshd"},
+	})
+
+	rule := newTestActionRule("reverse_shell", &rules.KillDefinition{})
+
+	for _, entry := range []*model.ProcessCacheEntry{
+		newTestProcessEntry(1, "/sbin/init", "", nil),
+		newTestProcessEntry(33, "/usr/bin/nc", "", nil),
+		newTestProcessEntry(4242, "/usr/sbin/sshd", "", nil),
+	} {
+		reports := ae.execute(rule, entry)
+		assert.Equal(t, ActionStatusProtected, reports[0].Status)
+	}
+	assert.Empty(t, *calls)
+}
+
+func TestActionKillRateLimit(t *testing.T) {
+	ae, calls := newTestActionExecutor(&sconfig.Config{ActiveResponseRateLimit: 2})
+
+	rule := newTestActionRule("reverse_shell", &rules.KillDefinition{})
+	other := newTestActionRule("crypto_miner", &rules.KillDefinition{})
+
+	var statuses []string
+	for i := 0; i != 3; i++ {
+		reports := ae.execute(rule, newTestProcessEntry(uint32(4242+i), "/usr/bin/nc", "", nil))
+		statuses = append(statuses, reports[0].Status)
+	}
+	assert.Equal(t, []string{ActionStatusPerformed, ActionStatusPerformed, ActionStatusRateLimited}, statuses)
+
+	// the limit applies per rule
+	reports := ae.execute(other, newTestProcessEntry(5000, "/tmp/xmrig", "", nil))
+	assert.Equal(t, ActionStatusPerformed, reports[0].Status)
+	assert.Len(t, *calls, 3)
+}
+
+func TestActionKillDryRun(t *testing.T) {
+	ae, calls := newTestActionExecutor(&sconfig.Config{ActiveResponseRateLimit: 5, ActiveResponseDryRun: true})
+
+	rule := newTestActionRule("reverse_shell", &rules.KillDefinition{})
+	reports := ae.execute(rule, newTestProcessEntry(4242, "/usr/bin/nc", "", nil))
+
+	assert.Equal(t, ActionStatusDryRun, reports[0].Status)
+	assert.Equal(t, uint32(4242), reports[0].PID)
+	assert.Empty(t, *calls)
+}
@@ -0,0 +1,361 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	sconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/model"
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+)
+
+const (
+	// ProfileExecKind is the kind of the binaries executed by the processes of an image
+	ProfileExecKind = "exec"
+	// ProfileOpenKind is the kind of the path prefixes of the files opened by the processes of an image
+	ProfileOpenKind = "open"
+	// ProfileConnectKind is the kind of the network destinations the processes of an image connect to
+	ProfileConnectKind = "connect"
+	// ProfileDNSKind is the kind of the domains resolved by the processes of an image
+	ProfileDNSKind = "dns"
+
+	activityProfilesSavePeriod = time.Minute
+)
+
+var profileFilenameRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// ActivityProfile holds the activity learned for a container image. The profile learns during the learning
+// period following its creation, it is stable afterwards.
+type ActivityProfile struct {
+	Image         string
+	LearningStart time.Time
+	LearningEnd   time.Time
+	Entries       map[string]map[string]bool
+
+	dirty    bool
+	reported map[string]bool
+}
+
+type activityProfileFile struct {
+	Image         string              `json:"image"`
+	LearningStart time.Time           `json:"learning_start"`
+	LearningEnd   time.Time           `json:"learning_end"`
+	Entries       map[string][]string `json:"entries"`
+}
+
+// MarshalJSON returns the JSON representation of the profile, its entries being sorted
+func (p *ActivityProfile) MarshalJSON() ([]byte, error) {
+	file := activityProfileFile{
+		Image:         p.Image,
+		LearningStart: p.LearningStart,
+		LearningEnd:   p.LearningEnd,
+		Entries:       make(map[string][]string),
+	}
+
+	for kind, values := range p.Entries {
+		for value := range values {
+			file.Entries[kind] = append(file.Entries[kind], value)
+		}
+		sort.Strings(file.Entries[kind])
+	}
+
+	return json.Marshal(file)
+}
+
+// UnmarshalJSON reads a profile from its JSON representation
+func (p *ActivityProfile) UnmarshalJSON(data []byte) error {
+	var file activityProfileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	p.Image = file.Image
+	p.LearningStart = file.LearningStart
+	p.LearningEnd = file.LearningEnd
+	p.Entries = make(map[string]map[string]bool)
+	for kind, values := range file.Entries {
+		p.Entries[kind] = make(map[string]bool)
+		for _, value := range values {
+			p.Entries[kind][value] = true
+		}
+	}
+
+	return nil
+}
+
+// IsStable returns whether the learning period of the profile is over
+func (p *ActivityProfile) IsStable(now time.Time) bool {
+	return !now.Before(p.LearningEnd)
+}
+
+// Contains returns whether the profile contains the given entry
+func (p *ActivityProfile) Contains(kind string, value string) bool {
+	return p.Entries[kind][value]
+}
+
+// ActivityAnomaly describes an activity outside of the stable profile of an image
+type ActivityAnomaly struct {
+	Image string
+	Kind  string
+	Value string
+}
+
+// ActivityProfiler learns the activity of the processes of each container image and reports, once the profile
+// of an image is stable, the activity deviating from it. Profiles are persisted so that learning survives restarts.
+type ActivityProfiler struct {
+	sync.Mutex
+
+	config   *sconfig.Config
+	profiles map[string]*ActivityProfile
+}
+
+// NewActivityProfiler returns a new ActivityProfiler, loading the profiles previously persisted
+func NewActivityProfiler(cfg *sconfig.Config) (*ActivityProfiler, error) {
+	ap := &ActivityProfiler{
+		config:   cfg,
+		profiles: make(map[string]*ActivityProfile),
+	}
+
+	if err := os.MkdirAll(cfg.ActivityProfilesDir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create activity profiles directory")
+	}
+
+	files, err := ioutil.ReadDir(cfg.ActivityProfilesDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read activity profiles directory")
+	}
+
+	for _, file := range files {
+		if filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		// a profile that can't be loaded, e.g. truncated by a crash, is learned again
+		data, err := ioutil.ReadFile(filepath.Join(cfg.ActivityProfilesDir, file.Name()))
+		if err != nil {
+			seclog.Warnf("skipping activity profile `%s`, failed to read it: %s", file.Name(), err)
+			continue
+		}
+
+		profile := &ActivityProfile{}
+		if err := json.Unmarshal(data, profile); err != nil {
+			seclog.Warnf("skipping activity profile `%s`, failed to parse it: %s", file.Name(), err)
+			continue
+		}
+		if profile.Image == "" {
+			seclog.Warnf("skipping activity profile `%s`, no image", file.Name())
+			continue
+		}
+		profile.reported = make(map[string]bool)
+
+		ap.profiles[profile.Image] = profile
+	}
+
+	return ap, nil
+}
+
+// GetProfile returns the profile of the given image
+func (ap *ActivityProfiler) GetProfile(image string) *ActivityProfile {
+	ap.Lock()
+	defer ap.Unlock()
+
+	return ap.profiles[image]
+}
+
+func (ap *ActivityProfiler) pathPrefix(path string) string {
+	if ap.config.ActivityProfilesPathDepth <= 0 {
+		return path
+	}
+
+	components := strings.SplitN(strings.TrimPrefix(path, "/"), "/", ap.config.ActivityProfilesPathDepth+1)
+	if len(components) > ap.config.ActivityProfilesPathDepth {
+		components = components[:ap.config.ActivityProfilesPathDepth]
+	}
+
+	return "/" + strings.Join(components, "/")
+}
+
+func getImage(tags []string) string {
+	image := utils.GetTagValue("image_name", tags)
+	if image == "" {
+		return ""
+	}
+
+	if tag := utils.GetTagValue("image_tag", tags); tag != "" {
+		return image + ":" + tag
+	}
+	return image
+}
+
+// ProcessEvent learns or checks the activity of the event against the profile of the image of its container
+func (ap *ActivityProfiler) ProcessEvent(event *sprobe.Event) *ActivityAnomaly {
+	var kind, value string
+
+	switch event.GetEventType() {
+	case model.ExecEventType:
+		kind, value = ProfileExecKind, event.Exec.PathnameStr
+	case model.FileOpenEventType:
+		if event.Open.Retval < 0 {
+			return nil
+		}
+		kind, value = ProfileOpenKind, ap.pathPrefix(event.ResolveFilePath(&event.Open.File))
+	case model.ConnectEventType:
+		if event.Connect.Retval < 0 {
+			return nil
+		}
+		kind, value = ProfileConnectKind, net.JoinHostPort(event.Connect.Addr.IP, strconv.Itoa(int(event.Connect.Addr.Port)))
+	case model.DNSEventType:
+		kind, value = ProfileDNSKind, event.DNS.Name
+	default:
+		return nil
+	}
+
+	if value == "" {
+		return nil
+	}
+
+	if event.ResolveContainerID(&event.ContainerContext) == "" {
+		return nil
+	}
+
+	// the tags of a container may not be available yet right after its creation
+	image := getImage(event.ResolveContainerTags(&event.ContainerContext))
+	if image == "" {
+		return nil
+	}
+
+	return ap.processActivity(image, kind, value, event.ResolveEventTimestamp())
+}
+
+func (ap *ActivityProfiler) processActivity(image string, kind string, value string, now time.Time) *ActivityAnomaly {
+	ap.Lock()
+	defer ap.Unlock()
+
+	profile, exists := ap.profiles[image]
+	if !exists {
+		if len(ap.profiles) >= ap.config.ActivityProfilesMaxProfiles {
+			return nil
+		}
+
+		profile = &ActivityProfile{
+			Image:         image,
+			LearningStart: now,
+			LearningEnd:   now.Add(ap.config.ActivityProfilesLearningPeriod),
+			Entries:       make(map[string]map[string]bool),
+			reported:      make(map[string]bool),
+		}
+		ap.profiles[image] = profile
+		seclog.Debugf("start learning the activity profile of `%s`", image)
+	}
+
+	if profile.Contains(kind, value) {
+		return nil
+	}
+
+	if !profile.IsStable(now) {
+		entries := profile.Entries[kind]
+		if entries == nil {
+			entries = make(map[string]bool)
+			profile.Entries[kind] = entries
+		}
+
+		if len(entries) < ap.config.ActivityProfilesMaxEntries {
+			entries[value] = true
+			profile.dirty = true
+		}
+		return nil
+	}
+
+	// report each anomaly only once
+	key := kind + ":" + value
+	if profile.reported[key] || len(profile.reported) >= ap.config.ActivityProfilesMaxEntries {
+		return nil
+	}
+	profile.reported[key] = true
+
+	return &ActivityAnomaly{
+		Image: image,
+		Kind:  kind,
+		Value: value,
+	}
+}
+
+// Save persists the profiles modified since the last save
+func (ap *ActivityProfiler) Save() error {
+	ap.Lock()
+	defer ap.Unlock()
+
+	for _, profile := range ap.profiles {
+		if !profile.dirty {
+			continue
+		}
+
+		data, err := json.Marshal(profile)
+		if err != nil {
+			return err
+		}
+
+		filename := filepath.Join(ap.config.ActivityProfilesDir, profileFilename(profile.Image))
+		tmpFilename := filename + ".tmp"
+		if err := ioutil.WriteFile(tmpFilename, data, 0600); err != nil {
+			return errors.Wrapf(err, "failed to write activity profile of `%s`", profile.Image)
+		}
+		if err := os.Rename(tmpFilename, filename); err != nil {
+			return errors.Wrapf(err, "failed to write activity profile of `%s`", profile.Image)
+		}
+
+		profile.dirty = false
+	}
+
+	return nil
+}
+
+// profileFilename returns the name of the file of the profile of an image. The image reference is sanitized
+// to be readable, a hash of it keeps the names of the references sanitized the same way apart.
+func profileFilename(image string) string {
+	hash := sha256.Sum256([]byte(image))
+	return profileFilenameRegexp.ReplaceAllString(image, "_") + "-" + hex.EncodeToString(hash[:8]) + ".json"
+}
+
+// Run periodically persists the profiles until the context is done
+func (ap *ActivityProfiler) Run(ctx context.Context) {
+	ticker := time.NewTicker(activityProfilesSavePeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := ap.Save(); err != nil {
+				seclog.Errorf("failed to save activity profiles: %s", err)
+			}
+		case <-ctx.Done():
+			if err := ap.Save(); err != nil {
+				seclog.Errorf("failed to save activity profiles: %s", err)
+			}
+			return
+		}
+	}
+}
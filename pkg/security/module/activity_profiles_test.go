@@ -0,0 +1,176 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	sconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+)
+
+func newTestActivityProfiler(t *testing.T) (*ActivityProfiler, func()) {
+	dir, err := ioutil.TempDir("", "activity-profiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &sconfig.Config{
+		ActivityProfilesEnabled:        true,
+		ActivityProfilesDir:            dir,
+		ActivityProfilesLearningPeriod: time.Hour,
+		ActivityProfilesPathDepth:      2,
+		ActivityProfilesMaxEntries:     3,
+		ActivityProfilesMaxProfiles:    2,
+	}
+
+	ap, err := NewActivityProfiler(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return ap, func() { os.RemoveAll(dir) }
+}
+
+func TestActivityProfilerLearning(t *testing.T) {
+	ap, cleanup := newTestActivityProfiler(t)
+	defer cleanup()
+
+	now := time.Now()
+
+	assert.Nil(t, ap.processActivity("nginx:1.19", ProfileExecKind, "/usr/sbin/nginx", now))
+	assert.Nil(t, ap.processActivity("nginx:1.19", ProfileDNSKind, "example.com", now.Add(time.Minute)))
+
+	profile := ap.GetProfile("nginx:1.19")
+	if assert.NotNil(t, profile) {
+		assert.False(t, profile.IsStable(now.Add(time.Minute)))
+		assert.True(t, profile.Contains(ProfileExecKind, "/usr/sbin/nginx"))
+		assert.True(t, profile.Contains(ProfileDNSKind, "example.com"))
+	}
+
+	stable := now.Add(2 * time.Hour)
+
+	// learned activity
+	assert.Nil(t, ap.processActivity("nginx:1.19", ProfileExecKind, "/usr/sbin/nginx", stable))
+
+	// activity outside of the profile
+	anomaly := ap.processActivity("nginx:1.19", ProfileExecKind, "/bin/sh", stable)
+	assert.Equal(t, &ActivityAnomaly{Image: "nginx:1.19", Kind: ProfileExecKind, Value: "/bin/sh"}, anomaly)
+	assert.False(t, profile.Contains(ProfileExecKind, "/bin/sh"))
+
+	// reported only once
+	assert.Nil(t, ap.processActivity("nginx:1.19", ProfileExecKind, "/bin/sh", stable))
+}
+
+func TestActivityProfilerLimits(t *testing.T) {
+	ap, cleanup := newTestActivityProfiler(t)
+	defer cleanup()
+
+	now := time.Now()
+
+	for _, value := range []string{"a.com", "b.com", "c.com", "d.com"} {
+		ap.processActivity("redis:6", ProfileDNSKind, value, now)
+	}
+	assert.Len(t, ap.GetProfile("redis:6").Entries[ProfileDNSKind], 3)
+	assert.False(t, ap.GetProfile("redis:6").Contains(ProfileDNSKind, "d.com"))
+
+	ap.processActivity("nginx:1.19", ProfileDNSKind, "a.com", now)
+	ap.processActivity("mysql:8", ProfileDNSKind, "a.com", now)
+	assert.NotNil(t, ap.GetProfile("nginx:1.19"))
+	assert.Nil(t, ap.GetProfile("mysql:8"))
+}
+
+func TestActivityProfilerPathPrefix(t *testing.T) {
+	ap, cleanup := newTestActivityProfiler(t)
+	defer cleanup()
+
+	assert.Equal(t, "/etc/nginx", ap.pathPrefix("/etc/nginx/nginx.conf"))
+	assert.Equal(t, "/etc/passwd", ap.pathPrefix("/etc/passwd"))
+	assert.Equal(t, "/etc", ap.pathPrefix("/etc"))
+
+	ap.config.ActivityProfilesPathDepth = 0
+	assert.Equal(t, "/etc/nginx/nginx.conf", ap.pathPrefix("/etc/nginx/nginx.conf"))
+}
+
+func TestActivityProfilerPersistence(t *testing.T) {
+	ap, cleanup := newTestActivityProfiler(t)
+	defer cleanup()
+
+	now := time.Now()
+	ap.processActivity("docker.io/library/nginx:1.19", ProfileOpenKind, "/etc/nginx", now)
+	ap.processActivity("docker.io/library/nginx:1.19", ProfileConnectKind, "10.0.0.1:5432", now)
+
+	if err := ap.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(ap.config.ActivityProfilesDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, profileFilename("docker.io/library/nginx:1.19"), files[0].Name())
+	}
+
+	loaded, err := NewActivityProfiler(ap.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := loaded.GetProfile("docker.io/library/nginx:1.19")
+	if assert.NotNil(t, profile) {
+		assert.True(t, profile.Contains(ProfileOpenKind, "/etc/nginx"))
+		assert.True(t, profile.Contains(ProfileConnectKind, "10.0.0.1:5432"))
+		assert.True(t, profile.LearningEnd.Equal(now.Add(time.Hour)))
+	}
+
+	// the learning period is kept across restarts
+	anomaly := loaded.processActivity("docker.io/library/nginx:1.19", ProfileOpenKind, "/root", now.Add(2*time.Hour))
+	assert.NotNil(t, anomaly)
+}
+
+func TestActivityProfilerFilenames(t *testing.T) {
+	assert.Regexp(t, `^docker.io_library_nginx_1.19-[0-9a-f]{16}\.json$`, profileFilename("docker.io/library/nginx:1.19"))
+
+	// references sanitized to the same string don't share a file
+	assert.NotEqual(t, profileFilename("registry/app:1"), profileFilename("registry_app:1"))
+}
+
+func TestActivityProfilerSkipsInvalidProfiles(t *testing.T) {
+	ap, cleanup := newTestActivityProfiler(t)
+	defer cleanup()
+
+	ap.processActivity("docker.io/library/nginx:1.19", ProfileOpenKind, "/etc/nginx", time.Now())
+	if err := ap.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range map[string]string{
+		"truncated.json": `{"Image":"docker.io/library/redis:6","Entr`,
+		"empty.json":     ``,
+		"no_image.json":  `{"Entries":{}}`,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(ap.config.ActivityProfilesDir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := NewActivityProfiler(ap.config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotNil(t, loaded.GetProfile("docker.io/library/nginx:1.19"))
+	assert.Nil(t, loaded.GetProfile("docker.io/library/redis:6"))
+	assert.Len(t, loaded.profiles, 1)
+}
@@ -57,7 +57,9 @@ type Module struct {
 	rulesLoaded      func(rs *rules.RuleSet)
 	policiesVersions []string
 
-	selfTester *SelfTester
+	selfTester       *SelfTester
+	actionExecutor   *ActionExecutor
+	activityProfiler *ActivityProfiler
 }
 
 // Register the runtime security agent module
@@ -127,6 +129,14 @@ func (m *Module) Start() error {
 	m.wg.Add(1)
 	go m.metricsSender()
 
+	if m.activityProfiler != nil {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.activityProfiler.Run(m.ctx)
+		}()
+	}
+
 	signal.Notify(m.sigupChan, syscall.SIGHUP)
 
 	m.wg.Add(1)
@@ -220,13 +230,15 @@ func (m *Module) Reload() error {
 	rsa := sprobe.NewRuleSetApplier(m.config, m.probe)
 
 	newRuleSetOpts := func() *rules.Opts {
-		return rules.NewOptsWithParams(
+		opts := rules.NewOptsWithParams(
 			model.SECLConstants,
 			sprobe.SupportedDiscarders,
 			m.getEventTypeEnabled(),
 			sprobe.AllCustomRuleIDs(),
 			model.SECLLegacyAttributes,
 			&seclog.PatternLogger{})
+		opts.VariableScopes = model.SECLVariableScopes
+		return opts
 	}
 
 	ruleSet := m.probe.NewRuleSet(newRuleSetOpts())
@@ -282,6 +294,9 @@ func (m *Module) Reload() error {
 
 	m.apiServer.Apply(ruleIDs)
 	m.rateLimiter.Apply(ruleIDs)
+	if m.actionExecutor != nil {
+		m.actionExecutor.Apply(ruleSet.ListRuleIDs())
+	}
 
 	m.displayReport(report)
 
@@ -329,9 +344,31 @@ func (m *Module) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field
 func (m *Module) HandleEvent(event *sprobe.Event) {
 	if ruleSet := m.GetRuleSet(); ruleSet != nil {
 		ruleSet.Evaluate(event)
+
+		// the process scoped variables of an exiting process won't be read anymore
+		if event.GetEventType() == model.ExitEventType {
+			ruleSet.ReleaseVariableScope("process", event)
+		}
+	}
+
+	if m.activityProfiler != nil {
+		if anomaly := m.activityProfiler.ProcessEvent(event); anomaly != nil {
+			m.sendActivityAnomaly(event, anomaly)
+		}
 	}
 }
 
+func (m *Module) sendActivityAnomaly(event *sprobe.Event, anomaly *ActivityAnomaly) {
+	service := event.GetProcessServiceTag()
+	if service == "" {
+		service = m.probe.GetResolvers().TagsResolver.GetValue(event.ContainerContext.ID, "service")
+	}
+
+	tags := event.ContainerContext.Tags
+	rule, customEvent := sprobe.NewActivityAnomalyEvent(event, anomaly.Image, anomaly.Kind, anomaly.Value)
+	m.SendEvent(rule, customEvent, func() []string { return tags }, service)
+}
+
 // HandleCustomEvent is called by the probe when an event should be sent to Datadog but doesn't need evaluation
 func (m *Module) HandleCustomEvent(rule *rules.Rule, event *sprobe.CustomEvent) {
 	m.SendEvent(rule, event, func() []string { return nil }, "")
@@ -367,6 +404,12 @@ func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
 		m.selfTester.SendEventIfExpecting(rule, event)
 	}
 	m.SendEvent(rule, event, extTagsCb, service)
+
+	if m.actionExecutor != nil {
+		for _, report := range m.actionExecutor.Execute(rule, event.(*sprobe.Event)) {
+			m.HandleCustomEvent(sprobe.NewActiveResponseEvent(event.(*sprobe.Event), rule.ID, report.Kill, report.PID, report.Status, report.Err))
+		}
+	}
 }
 
 // SendEvent sends an event to the backend after checking that the rate limiter allows it for the provided rule
@@ -469,6 +512,13 @@ func NewModule(cfg *sconfig.Config) (module.Module, error) {
 		return nil, err
 	}
 
+	var activityProfiler *ActivityProfiler
+	if cfg.ActivityProfilesEnabled {
+		if activityProfiler, err = NewActivityProfiler(cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	ctx, cancelFnc := context.WithCancel(context.Background())
 
 	// custom limiters
@@ -479,18 +529,25 @@ func NewModule(cfg *sconfig.Config) (module.Module, error) {
 		selfTester = NewSelfTester()
 	}
 
+	var actionExecutor *ActionExecutor
+	if cfg.ActiveResponseEnabled {
+		actionExecutor = NewActionExecutor(cfg, statsdClient)
+	}
+
 	m := &Module{
-		config:         cfg,
-		probe:          probe,
-		statsdClient:   statsdClient,
-		apiServer:      NewAPIServer(cfg, probe, statsdClient),
-		grpcServer:     grpc.NewServer(),
-		rateLimiter:    NewRateLimiter(statsdClient, LimiterOpts{Limits: limits}),
-		sigupChan:      make(chan os.Signal, 1),
-		currentRuleSet: 1,
-		ctx:            ctx,
-		cancelFnc:      cancelFnc,
-		selfTester:     selfTester,
+		config:           cfg,
+		probe:            probe,
+		statsdClient:     statsdClient,
+		apiServer:        NewAPIServer(cfg, probe, statsdClient),
+		grpcServer:       grpc.NewServer(),
+		rateLimiter:      NewRateLimiter(statsdClient, LimiterOpts{Limits: limits}),
+		sigupChan:        make(chan os.Signal, 1),
+		currentRuleSet:   1,
+		ctx:              ctx,
+		cancelFnc:        cancelFnc,
+		selfTester:       selfTester,
+		actionExecutor:   actionExecutor,
+		activityProfiler: activityProfiler,
 	}
 	m.apiServer.module = m
 
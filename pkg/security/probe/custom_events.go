@@ -28,6 +28,10 @@ const (
 	NoisyProcessRuleID = "noisy_process"
 	// AbnormalPathRuleID is the rule ID for the abnormal_path events
 	AbnormalPathRuleID = "abnormal_path"
+	// ActiveResponseRuleID is the rule ID for the active_response events
+	ActiveResponseRuleID = "active_response"
+	// ActivityAnomalyRuleID is the rule ID for the activity_anomaly events
+	ActivityAnomalyRuleID = "activity_anomaly"
 )
 
 // AllCustomRuleIDs returns the list of custom rule IDs
@@ -37,6 +41,8 @@ func AllCustomRuleIDs() []string {
 		RulesetLoadedRuleID,
 		NoisyProcessRuleID,
 		AbnormalPathRuleID,
+		ActiveResponseRuleID,
+		ActivityAnomalyRuleID,
 	}
 }
 
@@ -320,3 +326,61 @@ func NewAbnormalPathEvent(event *Event, pathResolutionError error) (*rules.Rule,
 			PathResolutionError: pathResolutionError.Error(),
 		}.MarshalJSON)
 }
+
+// ActiveResponseEvent is used to report an action executed in response to a rule match
+// easyjson:json
+type ActiveResponseEvent struct {
+	Timestamp time.Time        `json:"date"`
+	RuleID    string           `json:"rule_id"`
+	Action    string           `json:"action"`
+	Signal    string           `json:"signal"`
+	Scope     string           `json:"scope"`
+	PID       uint32           `json:"pid,omitempty"`
+	Status    string           `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	Event     *EventSerializer `json:"triggering_event"`
+}
+
+// NewActiveResponseEvent returns the rule and a populated custom event for an active_response event
+func NewActiveResponseEvent(event *Event, ruleID string, kill *rules.KillDefinition, pid uint32, status string, err error) (*rules.Rule, *CustomEvent) {
+	activeResponseEvent := ActiveResponseEvent{
+		Timestamp: time.Now(),
+		RuleID:    ruleID,
+		Action:    "kill",
+		Signal:    kill.GetSignal(),
+		Scope:     kill.GetScope(),
+		PID:       pid,
+		Status:    status,
+		Event:     NewEventSerializer(event),
+	}
+	if err != nil {
+		activeResponseEvent.Error = err.Error()
+	}
+
+	return newRule(&rules.RuleDefinition{
+		ID: ActiveResponseRuleID,
+	}), newCustomEvent(model.CustomActiveResponseEventType, activeResponseEvent.MarshalJSON)
+}
+
+// ActivityAnomalyEvent is used to report an activity outside of the stable profile of a container image
+// easyjson:json
+type ActivityAnomalyEvent struct {
+	Timestamp time.Time        `json:"date"`
+	Image     string           `json:"image"`
+	Kind      string           `json:"kind"`
+	Value     string           `json:"value"`
+	Event     *EventSerializer `json:"triggering_event"`
+}
+
+// NewActivityAnomalyEvent returns the rule and a populated custom event for an activity_anomaly event
+func NewActivityAnomalyEvent(event *Event, image string, kind string, value string) (*rules.Rule, *CustomEvent) {
+	return newRule(&rules.RuleDefinition{
+			ID: ActivityAnomalyRuleID,
+		}), newCustomEvent(model.CustomActivityAnomalyEventType, ActivityAnomalyEvent{
+			Timestamp: event.ResolveEventTimestamp(),
+			Image:     image,
+			Kind:      kind,
+			Value:     value,
+			Event:     NewEventSerializer(event),
+		}.MarshalJSON)
+}
@@ -105,6 +105,8 @@ var InvalidDiscarders = map[eval.Field][]interface{}{
 	"process.file.path":            dentryInvalidDiscarder,
 	"setxattr.file.path":           dentryInvalidDiscarder,
 	"removexattr.file.path":        dentryInvalidDiscarder,
+	"load_module.file.path":        dentryInvalidDiscarder,
+	"mmap.file.path":               dentryInvalidDiscarder,
 }
 
 func marshalDiscardHeader(req *ERPCRequest, eventType model.EventType, timeout uint64) int {
@@ -509,4 +511,24 @@ func init() {
 				return "removexattr.file.path", event.RemoveXAttr.File.MountID, event.RemoveXAttr.File.Inode, event.RemoveXAttr.File.PathID, false
 			}))
 	SupportedDiscarders["removexattr.file.path"] = true
+
+	allDiscarderHandlers["load_module"] = processDiscarderWrapper(model.LoadModuleEventType,
+		filenameDiscarderWrapper(model.LoadModuleEventType, nil,
+			func(event *Event) (eval.Field, uint32, uint64, uint32, bool) {
+				return "load_module.file.path", event.LoadModule.File.MountID, event.LoadModule.File.Inode, event.LoadModule.File.PathID, false
+			}))
+	SupportedDiscarders["load_module.file.path"] = true
+
+	allDiscarderHandlers["mmap"] = processDiscarderWrapper(model.MMapEventType,
+		filenameDiscarderWrapper(model.MMapEventType, nil,
+			func(event *Event) (eval.Field, uint32, uint64, uint32, bool) {
+				return "mmap.file.path", event.MMap.File.MountID, event.MMap.File.Inode, event.MMap.File.PathID, false
+			}))
+	SupportedDiscarders["mmap.file.path"] = true
+
+	allDiscarderHandlers["ptrace"] = processDiscarderWrapper(model.PTraceEventType, nil)
+
+	allDiscarderHandlers["mprotect"] = processDiscarderWrapper(model.MProtectEventType, nil)
+
+	allDiscarderHandlers["bpf"] = processDiscarderWrapper(model.BPFEventType, nil)
 }
@@ -0,0 +1,563 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// EventDecoder reads a stream of serialized events, as generated by the EventSerializer, and returns
+// the corresponding events so that they can be evaluated without a running probe
+type EventDecoder struct {
+	decoder *json.Decoder
+}
+
+// NewEventDecoder returns a new EventDecoder reading serialized events from the provided reader
+func NewEventDecoder(r io.Reader) *EventDecoder {
+	return &EventDecoder{
+		decoder: json.NewDecoder(r),
+	}
+}
+
+// Decode returns the next event of the stream, or io.EOF when the stream is exhausted
+func (d *EventDecoder) Decode() (*model.Event, error) {
+	var s EventSerializer
+	if err := d.decoder.Decode(&s); err != nil {
+		return nil, err
+	}
+	return NewEventFromSerializer(&s)
+}
+
+func parseConstant(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	if constant, exists := model.SECLConstants[value]; exists {
+		if evaluator, ok := constant.(*eval.IntEvaluator); ok {
+			return evaluator.Value, nil
+		}
+	}
+
+	v, err := strconv.ParseInt(value, 0, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unknown constant `%s`", value)
+	}
+	return int(v), nil
+}
+
+func parseBitmask(values []string) (int, error) {
+	var bitmask int
+	for _, value := range values {
+		v, err := parseConstant(strings.TrimSpace(value))
+		if err != nil {
+			return 0, err
+		}
+		bitmask |= v
+	}
+	return bitmask, nil
+}
+
+func parseBitmaskString(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return parseBitmask(strings.Split(value, "|"))
+}
+
+func parseCapabilities(values []string) (uint64, error) {
+	var caps uint64
+	for _, value := range values {
+		if v, exists := model.KernelCapabilityConstants[value]; exists {
+			caps |= v
+			continue
+		}
+
+		v, err := strconv.ParseUint(value, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unknown capability `%s`", value)
+		}
+		caps |= v
+	}
+	return caps, nil
+}
+
+func parseAddress(value string) (uint64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 0, 64)
+}
+
+// parseOutcome returns a retval matching the serialized outcome. The exact error isn't serialized,
+// EACCES is used for refused syscalls and EINVAL for the other errors.
+func parseOutcome(outcome string) int64 {
+	switch outcome {
+	case "Refused":
+		return -int64(syscall.EACCES)
+	case "Error":
+		return -int64(syscall.EINVAL)
+	default:
+		return 0
+	}
+}
+
+func newFileEventFromSerializer(s *FileSerializer) model.FileEvent {
+	if s == nil {
+		return model.FileEvent{}
+	}
+
+	fe := model.FileEvent{
+		FileFields: model.FileFields{
+			UID:   s.UID,
+			User:  s.User,
+			GID:   s.GID,
+			Group: s.Group,
+		},
+		PathnameStr: s.Path,
+		BasenameStr: s.Name,
+		Filesytem:   s.Filesystem,
+	}
+
+	if s.Inode != nil {
+		fe.Inode = *s.Inode
+	}
+	if s.MountID != nil {
+		fe.MountID = *s.MountID
+	}
+	if s.Mode != nil {
+		fe.Mode = uint16(*s.Mode)
+	}
+	if s.InUpperLayer != nil {
+		fe.InUpperLayer = *s.InUpperLayer
+		if *s.InUpperLayer {
+			fe.Flags |= model.UpperLayer
+		} else {
+			fe.Flags |= model.LowerLayer
+		}
+	}
+	if s.Mtime != nil {
+		fe.MTime = uint64(s.Mtime.UnixNano())
+	}
+	if s.Ctime != nil {
+		fe.CTime = uint64(s.Ctime.UnixNano())
+	}
+	if s.PathResolutionError != "" {
+		fe.PathResolutionError = errors.New(s.PathResolutionError)
+	}
+
+	return fe
+}
+
+func newProcessCacheEntryFromSerializer(s *ProcessCacheEntrySerializer) (*model.ProcessCacheEntry, error) {
+	entry := &model.ProcessCacheEntry{}
+	if s == nil {
+		return entry, nil
+	}
+
+	process := &entry.Process
+	process.Pid = s.Pid
+	process.PPid = s.PPid
+	process.Tid = s.Tid
+	process.Comm = s.Comm
+	process.TTYName = s.TTY
+	process.ArgsTruncated = s.ArgsTruncated
+	process.EnvsTruncated = s.EnvsTruncated
+
+	if s.ForkTime != nil {
+		process.ForkTime = *s.ForkTime
+	}
+	if s.ExecTime != nil {
+		process.ExecTime = *s.ExecTime
+	}
+	if s.ExitTime != nil {
+		process.ExitTime = *s.ExitTime
+	}
+
+	if s.Executable != nil {
+		file := newFileEventFromSerializer(s.Executable)
+		process.FileFields = file.FileFields
+		process.PathnameStr = file.PathnameStr
+		process.BasenameStr = file.BasenameStr
+		process.Filesystem = file.Filesytem
+		process.PathResolutionError = file.PathResolutionError
+	}
+
+	if s.Container != nil {
+		process.ContainerID = s.Container.ID
+	}
+
+	// legacy fields, overridden by the credentials when available
+	process.UID = uint32(s.UID)
+	process.User = s.User
+	process.GID = uint32(s.GID)
+	process.Group = s.Group
+
+	if s.Credentials != nil && s.Credentials.CredentialsSerializer != nil {
+		creds := s.Credentials.CredentialsSerializer
+
+		capEffective, err := parseCapabilities(creds.CapEffective)
+		if err != nil {
+			return nil, err
+		}
+
+		capPermitted, err := parseCapabilities(creds.CapPermitted)
+		if err != nil {
+			return nil, err
+		}
+
+		process.Credentials = model.Credentials{
+			UID:          uint32(creds.UID),
+			User:         creds.User,
+			GID:          uint32(creds.GID),
+			Group:        creds.Group,
+			EUID:         uint32(creds.EUID),
+			EUser:        creds.EUser,
+			EGID:         uint32(creds.EGID),
+			EGroup:       creds.EGroup,
+			FSUID:        uint32(creds.FSUID),
+			FSUser:       creds.FSUser,
+			FSGID:        uint32(creds.FSGID),
+			FSGroup:      creds.FSGroup,
+			CapEffective: capEffective,
+			CapPermitted: capPermitted,
+		}
+	}
+
+	return entry, nil
+}
+
+func newProcessContextFromSerializer(s *ProcessContextSerializer) (*model.ProcessCacheEntry, error) {
+	if s == nil {
+		return &model.ProcessCacheEntry{}, nil
+	}
+
+	entry, err := newProcessCacheEntryFromSerializer(s.ProcessCacheEntrySerializer)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := s.Ancestors
+	if len(ancestors) == 0 && s.Parent != nil {
+		ancestors = []*ProcessCacheEntrySerializer{s.Parent}
+	}
+
+	child := entry
+	for _, as := range ancestors {
+		ancestor, err := newProcessCacheEntryFromSerializer(as)
+		if err != nil {
+			return nil, err
+		}
+		child.Ancestor = ancestor
+		child = ancestor
+	}
+
+	return entry, nil
+}
+
+// decodeDestination decodes the destination of the process credentials, which is specific to the event type
+func decodeDestination(s *ProcessContextSerializer, dest interface{}) error {
+	if s == nil || s.ProcessCacheEntrySerializer == nil || s.Credentials == nil || s.Credentials.Destination == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(s.Credentials.Destination)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// NewEventFromSerializer returns the event described by a serialized event
+func NewEventFromSerializer(s *EventSerializer) (*model.Event, error) {
+	if s.EventContextSerializer == nil {
+		return nil, errors.New("event context missing")
+	}
+
+	eventType := model.ParseEvalEventType(s.EventContextSerializer.Name)
+	if eventType == model.UnknownEventType {
+		return nil, fmt.Errorf("unknown event type `%s`", s.EventContextSerializer.Name)
+	}
+
+	event := &model.Event{
+		Type:      uint64(eventType),
+		Timestamp: s.Date,
+	}
+
+	entry, err := newProcessContextFromSerializer(s.ProcessContextSerializer)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid process context")
+	}
+	event.ProcessContext = entry.ProcessContext
+
+	if s.ContainerContextSerializer != nil {
+		event.ContainerContext.ID = s.ContainerContextSerializer.ID
+	} else {
+		event.ContainerContext.ID = entry.ContainerID
+	}
+
+	var file, destination model.FileEvent
+	var flags []string
+	var destinationMode *uint32
+	if s.FileEventSerializer != nil {
+		file = newFileEventFromSerializer(&s.FileEventSerializer.FileSerializer)
+		destination = newFileEventFromSerializer(s.FileEventSerializer.Destination)
+		flags = s.FileEventSerializer.Flags
+		if s.FileEventSerializer.Destination != nil {
+			destinationMode = s.FileEventSerializer.Destination.Mode
+		}
+	}
+
+	retval := parseOutcome(s.EventContextSerializer.Outcome)
+
+	switch eventType {
+	case model.FileChmodEventType:
+		event.Chmod = model.ChmodEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+		if destinationMode != nil {
+			event.Chmod.Mode = *destinationMode
+		}
+	case model.FileChownEventType:
+		event.Chown = model.ChownEvent{
+			SyscallEvent: model.SyscallEvent{Retval: retval},
+			File:         file,
+			UID:          destination.UID,
+			User:         destination.User,
+			GID:          destination.GID,
+			Group:        destination.Group,
+		}
+	case model.FileLinkEventType:
+		event.Link = model.LinkEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, Source: file, Target: destination}
+	case model.FileOpenEventType:
+		openFlags, err := parseBitmask(flags)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid open flags")
+		}
+		event.Open = model.OpenEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file, Flags: uint32(openFlags)}
+		if destinationMode != nil {
+			event.Open.Mode = *destinationMode
+		}
+	case model.FileMkdirEventType:
+		event.Mkdir = model.MkdirEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+		if destinationMode != nil {
+			event.Mkdir.Mode = *destinationMode
+		}
+	case model.FileRmdirEventType:
+		event.Rmdir = model.RmdirEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+	case model.FileUnlinkEventType:
+		unlinkFlags, err := parseBitmask(flags)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid unlink flags")
+		}
+		event.Unlink = model.UnlinkEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file, Flags: uint32(unlinkFlags)}
+	case model.FileRenameEventType:
+		event.Rename = model.RenameEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, Old: file, New: destination}
+	case model.FileSetXAttrEventType, model.FileRemoveXAttrEventType:
+		xattr := model.SetXAttrEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+		if s.FileEventSerializer != nil && s.FileEventSerializer.Destination != nil {
+			xattr.Name = s.FileEventSerializer.Destination.XAttrName
+			xattr.Namespace = s.FileEventSerializer.Destination.XAttrNamespace
+		}
+		if eventType == model.FileSetXAttrEventType {
+			event.SetXAttr = xattr
+		} else {
+			event.RemoveXAttr = xattr
+		}
+	case model.FileUtimesEventType:
+		event.Utimes = model.UtimesEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+		if dest := s.FileEventSerializer; dest != nil && dest.Destination != nil {
+			if dest.Destination.Atime != nil {
+				event.Utimes.Atime = *dest.Destination.Atime
+			}
+			if dest.Destination.Mtime != nil {
+				event.Utimes.Mtime = *dest.Destination.Mtime
+			}
+		}
+	case model.SetuidEventType:
+		var setuid SetuidSerializer
+		if err := decodeDestination(s.ProcessContextSerializer, &setuid); err != nil {
+			return nil, errors.Wrap(err, "invalid setuid destination")
+		}
+		event.SetUID = model.SetuidEvent{
+			UID:    uint32(setuid.UID),
+			User:   setuid.User,
+			EUID:   uint32(setuid.EUID),
+			EUser:  setuid.EUser,
+			FSUID:  uint32(setuid.FSUID),
+			FSUser: setuid.FSUser,
+		}
+	case model.SetgidEventType:
+		var setgid SetgidSerializer
+		if err := decodeDestination(s.ProcessContextSerializer, &setgid); err != nil {
+			return nil, errors.Wrap(err, "invalid setgid destination")
+		}
+		event.SetGID = model.SetgidEvent{
+			GID:     uint32(setgid.GID),
+			Group:   setgid.Group,
+			EGID:    uint32(setgid.EGID),
+			EGroup:  setgid.EGroup,
+			FSGID:   uint32(setgid.FSGID),
+			FSGroup: setgid.FSGroup,
+		}
+	case model.CapsetEventType:
+		var capset CapsetSerializer
+		if err := decodeDestination(s.ProcessContextSerializer, &capset); err != nil {
+			return nil, errors.Wrap(err, "invalid capset destination")
+		}
+		if event.Capset.CapEffective, err = parseCapabilities(capset.CapEffective); err != nil {
+			return nil, err
+		}
+		if event.Capset.CapPermitted, err = parseCapabilities(capset.CapPermitted); err != nil {
+			return nil, err
+		}
+	case model.ExecEventType:
+		event.Exec.Process = entry.Process
+		if s.ProcessContextSerializer != nil && s.ProcessContextSerializer.ProcessCacheEntrySerializer != nil {
+			event.Exec.Argv = s.ProcessContextSerializer.Args
+			event.Exec.Args = strings.Join(s.ProcessContextSerializer.Args, " ")
+			event.Exec.ArgsTruncated = s.ProcessContextSerializer.ArgsTruncated
+			event.Exec.Envs = s.ProcessContextSerializer.Envs
+			event.Exec.EnvsTruncated = s.ProcessContextSerializer.EnvsTruncated
+		}
+	case model.SELinuxEventType:
+		event.SELinux.File = file
+		if selinux := s.SELinuxEventSerializer; selinux != nil {
+			switch {
+			case selinux.BoolChange != nil:
+				event.SELinux.EventKind = model.SELinuxBoolChangeEventKind
+				event.SELinux.BoolName = selinux.BoolChange.Name
+				event.SELinux.BoolChangeValue = selinux.BoolChange.State
+			case selinux.EnforceStatus != nil:
+				event.SELinux.EventKind = model.SELinuxStatusChangeEventKind
+				event.SELinux.EnforceStatus = selinux.EnforceStatus.Status
+			case selinux.BoolCommit != nil:
+				event.SELinux.EventKind = model.SELinuxBoolCommitEventKind
+				event.SELinux.BoolCommitValue = selinux.BoolCommit.State
+			}
+		}
+	case model.ConnectEventType, model.BindEventType:
+		var addr *IPPortSerializer
+		if s.ConnectEventSerializer != nil {
+			addr = &s.ConnectEventSerializer.Addr
+		} else if s.BindEventSerializer != nil {
+			addr = &s.BindEventSerializer.Addr
+		}
+
+		var ctx model.IPPortContext
+		if addr != nil {
+			family, err := parseConstant(addr.Family)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid address family")
+			}
+			ctx = model.IPPortContext{Family: uint16(family), IP: addr.IP, Port: addr.Port}
+		}
+
+		if eventType == model.ConnectEventType {
+			event.Connect = model.ConnectEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, Addr: ctx}
+		} else {
+			event.Bind = model.BindEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, Addr: ctx}
+		}
+	case model.DNSEventType:
+		if dns := s.DNSEventSerializer; dns != nil {
+			qtype, err := parseConstant(dns.Question.Type)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid DNS question type")
+			}
+			qclass, err := parseConstant(dns.Question.Class)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid DNS question class")
+			}
+			event.DNS = model.DNSEvent{
+				ID:    dns.ID,
+				Name:  dns.Question.Name,
+				Type:  uint16(qtype),
+				Class: uint16(qclass),
+				Size:  dns.Question.Length,
+				Count: dns.Question.Count,
+			}
+		}
+	case model.LoadModuleEventType:
+		event.LoadModule = model.LoadModuleEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+		if module := s.ModuleEventSerializer; module != nil {
+			event.LoadModule.Name = module.Name
+			event.LoadModule.LoadedFromMemory = module.LoadedFromMemory
+		}
+	case model.PTraceEventType:
+		event.PTrace = model.PTraceEvent{SyscallEvent: model.SyscallEvent{Retval: retval}}
+		if ptrace := s.PTraceEventSerializer; ptrace != nil {
+			request, err := parseConstant(ptrace.Request)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid ptrace request")
+			}
+			address, err := parseAddress(ptrace.Address)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid ptrace address")
+			}
+			event.PTrace.Request = uint32(request)
+			event.PTrace.PID = ptrace.TraceePID
+			event.PTrace.Address = address
+		}
+	case model.MMapEventType:
+		event.MMap = model.MMapEvent{SyscallEvent: model.SyscallEvent{Retval: retval}, File: file}
+		if mmap := s.MMapEventSerializer; mmap != nil {
+			if event.MMap.Addr, err = parseAddress(mmap.Address); err != nil {
+				return nil, errors.Wrap(err, "invalid mmap address")
+			}
+			if event.MMap.Protection, err = parseBitmaskString(mmap.Protection); err != nil {
+				return nil, errors.Wrap(err, "invalid mmap protection")
+			}
+			if event.MMap.Flags, err = parseBitmaskString(mmap.Flags); err != nil {
+				return nil, errors.Wrap(err, "invalid mmap flags")
+			}
+			event.MMap.Offset = mmap.Offset
+			event.MMap.Len = mmap.Len
+		}
+	case model.MProtectEventType:
+		event.MProtect = model.MProtectEvent{SyscallEvent: model.SyscallEvent{Retval: retval}}
+		if mprotect := s.MProtectEventSerializer; mprotect != nil {
+			if event.MProtect.VMStart, err = parseAddress(mprotect.VMStart); err != nil {
+				return nil, errors.Wrap(err, "invalid mprotect vm_start")
+			}
+			if event.MProtect.VMEnd, err = parseAddress(mprotect.VMEnd); err != nil {
+				return nil, errors.Wrap(err, "invalid mprotect vm_end")
+			}
+			if event.MProtect.VMProtection, err = parseBitmaskString(mprotect.VMProtection); err != nil {
+				return nil, errors.Wrap(err, "invalid mprotect vm_protection")
+			}
+			if event.MProtect.ReqProtection, err = parseBitmaskString(mprotect.ReqProtection); err != nil {
+				return nil, errors.Wrap(err, "invalid mprotect req_protection")
+			}
+		}
+	case model.BPFEventType:
+		event.BPF = model.BPFEvent{SyscallEvent: model.SyscallEvent{Retval: retval}}
+		if bpf := s.BPFEventSerializer; bpf != nil {
+			cmd, err := parseConstant(bpf.Cmd)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid bpf cmd")
+			}
+			progType, err := parseConstant(bpf.Program.Type)
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid bpf program type")
+			}
+			event.BPF.Cmd = uint32(cmd)
+			event.BPF.ProgType = uint32(progType)
+			event.BPF.ProgName = bpf.Program.Name
+		}
+	}
+
+	return event, nil
+}
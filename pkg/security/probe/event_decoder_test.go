@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/model"
+)
+
+const testSerializedEvents = `
+{"evt":{"name":"open","category":"File Activity","outcome":"Refused"},"file":{"path":"/etc/shadow","name":"shadow","inode":42,"mount_id":7,"uid":0,"gid":0,"flags":["O_CREAT","O_WRONLY"],"destination":{"mode":420,"uid":0,"gid":0}},"process":{"pid":4242,"ppid":1,"uid":1000,"gid":1000,"comm":"bash","executable":{"path":"/usr/bin/bash","name":"bash","uid":0,"gid":0},"container":{"id":"abc"},"credentials":{"uid":1000,"gid":1000,"euid":0,"egid":0,"fsuid":0,"fsgid":0,"cap_effective":["CAP_SYS_ADMIN"],"cap_permitted":[]},"ancestors":[{"pid":1,"uid":0,"gid":0,"comm":"systemd","executable":{"path":"/lib/systemd/systemd","name":"systemd","uid":0,"gid":0}}]},"container":{"id":"abc"},"date":"2021-06-01T10:00:00Z"}
+{"evt":{"name":"mmap","outcome":"Success"},"mmap":{"address":"0x7f0000000000","offset":0,"length":4096,"protection":"PROT_EXEC | PROT_READ","flags":"MAP_ANONYMOUS | MAP_PRIVATE"},"process":{"pid":12,"uid":0,"gid":0},"date":"2021-06-01T10:00:01Z"}
+{"evt":{"name":"connect","outcome":"Error"},"connect":{"addr":{"family":"AF_INET","ip":"10.0.0.1","port":4444}},"process":{"pid":12,"uid":0,"gid":0},"date":"2021-06-01T10:00:02Z"}
+`
+
+func TestEventDecoder(t *testing.T) {
+	decoder := NewEventDecoder(strings.NewReader(testSerializedEvents))
+
+	open, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, model.FileOpenEventType, open.GetEventType())
+	assert.Equal(t, "/etc/shadow", open.Open.File.PathnameStr)
+	assert.Equal(t, uint64(42), open.Open.File.Inode)
+	assert.Equal(t, uint32(syscall.O_CREAT|syscall.O_WRONLY), open.Open.Flags)
+	assert.Equal(t, uint32(420), open.Open.Mode)
+	assert.Equal(t, -int64(syscall.EACCES), open.Open.Retval)
+	assert.Equal(t, "abc", open.ContainerContext.ID)
+	assert.Equal(t, uint32(4242), open.ProcessContext.Pid)
+	assert.Equal(t, "/usr/bin/bash", open.ProcessContext.PathnameStr)
+	assert.Equal(t, uint32(0), open.ProcessContext.EUID)
+	assert.Equal(t, model.KernelCapabilityConstants["CAP_SYS_ADMIN"], open.ProcessContext.CapEffective)
+	if assert.NotNil(t, open.ProcessContext.Ancestor) {
+		assert.Equal(t, "/lib/systemd/systemd", open.ProcessContext.Ancestor.PathnameStr)
+	}
+
+	value, err := open.GetFieldValue("process.ancestors.file.path")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/lib/systemd/systemd"}, value)
+
+	mmap, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, model.MMapEventType, mmap.GetEventType())
+	assert.Equal(t, uint64(0x7f0000000000), mmap.MMap.Addr)
+	assert.Equal(t, syscall.PROT_EXEC|syscall.PROT_READ, mmap.MMap.Protection)
+	assert.Equal(t, syscall.MAP_ANONYMOUS|syscall.MAP_PRIVATE, mmap.MMap.Flags)
+
+	connect, err := decoder.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, model.ConnectEventType, connect.GetEventType())
+	assert.Equal(t, uint16(syscall.AF_INET), connect.Connect.Addr.Family)
+	assert.Equal(t, "10.0.0.1", connect.Connect.Addr.IP)
+	assert.Equal(t, uint16(4444), connect.Connect.Addr.Port)
+	assert.Equal(t, -int64(syscall.EINVAL), connect.Connect.Retval)
+
+	_, err = decoder.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestEventDecoderErrors(t *testing.T) {
+	for _, data := range []string{
+		`{"process":{"pid":1}}`,
+		`{"evt":{"name":"unknown_event"}}`,
+		`{"evt":{"name":"open"},"file":{"path":"/etc/passwd","flags":["O_UNKNOWN"]}}`,
+	} {
+		_, err := NewEventDecoder(strings.NewReader(data)).Decode()
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-go/statsd"
+	"github.com/pkg/errors"
+
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+)
+
+const eventRecorderBufferSize = 1000
+
+// EventRecorder writes the events dispatched by the probe to a file, one serialized event per line,
+// so that they can be replayed later on against a set of rules. The events are written by a dedicated
+// goroutine so that the file writes don't slow down the event dispatching, the events that don't fit in
+// the buffer are dropped.
+type EventRecorder struct {
+	// dropped is used with atomic operations, keep it first for 64-bit alignment on 32-bit platforms
+	dropped int64
+
+	sync.RWMutex
+	file   *os.File
+	events chan []byte
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewEventRecorder returns a new EventRecorder appending events to the provided file
+func NewEventRecorder(filename string) (*EventRecorder, error) {
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open event record file")
+	}
+
+	r := &EventRecorder{
+		file:   file,
+		events: make(chan []byte, eventRecorderBufferSize),
+	}
+
+	r.wg.Add(1)
+	go r.write()
+
+	return r, nil
+}
+
+func (r *EventRecorder) write() {
+	defer r.wg.Done()
+
+	for data := range r.events {
+		if _, err := r.file.Write(data); err != nil {
+			seclog.Debugf("failed to record event: %s", err)
+		}
+	}
+}
+
+// Record queues the event to be appended to the record file. The event is serialized right away as it
+// is reused by the probe once dispatched.
+func (r *EventRecorder) Record(event *Event) error {
+	data, err := NewEventSerializer(event).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return r.record(append(data, '\n'))
+}
+
+func (r *EventRecorder) record(data []byte) error {
+	r.RLock()
+	defer r.RUnlock()
+
+	if r.closed {
+		return errors.New("event recorder closed")
+	}
+
+	select {
+	case r.events <- data:
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+	}
+	return nil
+}
+
+// SendStats sends the number of events dropped because the buffer was full
+func (r *EventRecorder) SendStats(client *statsd.Client) error {
+	if dropped := atomic.SwapInt64(&r.dropped, 0); dropped > 0 {
+		return client.Count(metrics.MetricEventRecorderDropped, dropped, []string{}, 1.0)
+	}
+	return nil
+}
+
+// Close writes the queued events and closes the record file
+func (r *EventRecorder) Close() error {
+	r.Lock()
+	if r.closed {
+		r.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.events)
+	r.Unlock()
+
+	r.wg.Wait()
+	return r.file.Close()
+}
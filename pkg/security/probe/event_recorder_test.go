@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventRecorder(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "events.json")
+
+	r, err := NewEventRecorder(filename)
+	require.NoError(t, err)
+
+	assert.NoError(t, r.record([]byte("{\"id\":1}\n")))
+	assert.NoError(t, r.record([]byte("{\"id\":2}\n")))
+	require.NoError(t, r.Close())
+
+	content, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", string(content))
+
+	assert.Error(t, r.record([]byte("{\"id\":3}\n")))
+	assert.NoError(t, r.Close())
+}
+
+func TestEventRecorderDropsWhenFull(t *testing.T) {
+	// no writer goroutine, the buffer is never drained
+	r := &EventRecorder{events: make(chan []byte, 2)}
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, r.record([]byte("{}\n")))
+	}
+	assert.Len(t, r.events, 2)
+	assert.EqualValues(t, 3, r.dropped)
+}
@@ -69,7 +69,8 @@ func (ev *Event) GetPathResolutionError() error {
 
 // ResolveFilePath resolves the inode to a full path
 func (ev *Event) ResolveFilePath(f *model.FileEvent) string {
-	if len(f.PathnameStr) == 0 {
+	// events not backed by a file, such as anonymous memory mappings, have no inode
+	if len(f.PathnameStr) == 0 && f.Inode != 0 {
 		path, err := ev.resolvers.resolveFileFieldsPath(&f.FileFields)
 		if err != nil {
 			switch err.(type) {
@@ -87,7 +88,7 @@ func (ev *Event) ResolveFilePath(f *model.FileEvent) string {
 
 // ResolveFileBasename resolves the inode to a full path
 func (ev *Event) ResolveFileBasename(f *model.FileEvent) string {
-	if len(f.BasenameStr) == 0 {
+	if len(f.BasenameStr) == 0 && f.Inode != 0 {
 		if f.PathnameStr != "" {
 			f.BasenameStr = path.Base(f.PathnameStr)
 		} else {
@@ -65,6 +65,7 @@ type Probe struct {
 	perfMap   *manager.PerfMap
 	reOrderer *ReOrderer
 	scrubber  *pconfig.DataScrubber
+	recorder  *EventRecorder
 
 	// Approvers / discarders section
 	erpc               *ERPC
@@ -234,6 +235,12 @@ func (p *Probe) DispatchEvent(event *Event, size uint64, CPU int, perfMap *manag
 		p.handler.HandleEvent(event)
 	}
 
+	if p.recorder != nil {
+		if err := p.recorder.Record(event); err != nil {
+			seclog.Debugf("failed to record event: %s", err)
+		}
+	}
+
 	// Process after evaluation because some monitors need the DentryResolver to have been called first.
 	p.monitor.ProcessEvent(event, size, CPU, perfMap)
 }
@@ -510,6 +517,47 @@ func (p *Probe) handleEvent(CPU uint64, data []byte) {
 			log.Errorf("failed to decode selinux event: %s (offset %d, len %d)", err, offset, len(data))
 			return
 		}
+	case model.ConnectEventType:
+		if _, err = event.Connect.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode connect event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.BindEventType:
+		if _, err = event.Bind.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode bind event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.LoadModuleEventType:
+		if _, err = event.LoadModule.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode load_module event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.PTraceEventType:
+		if _, err = event.PTrace.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode ptrace event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.MMapEventType:
+		if _, err = event.MMap.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode mmap event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.MProtectEventType:
+		if _, err = event.MProtect.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode mprotect event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.BPFEventType:
+		if _, err = event.BPF.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode bpf event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.DNSEventType:
+		if _, err = event.DNS.UnmarshalBinary(data[offset:]); err != nil {
+			// requests using features we don't decode are expected, don't flood the logs
+			seclog.Tracef("failed to decode DNS event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
 	default:
 		log.Errorf("unsupported event type %d", eventType)
 		return
@@ -791,6 +839,12 @@ func (p *Probe) Close() error {
 		return err
 	}
 
+	if p.recorder != nil {
+		if err := p.recorder.Close(); err != nil {
+			seclog.Errorf("failed to close the event record file: %s", err)
+		}
+	}
+
 	// when we reach this point, we do not generate nor consume events anymore, we can close the resolvers
 	return p.resolvers.Close()
 }
@@ -883,6 +937,10 @@ func NewProbe(config *config.Config, client *statsd.Client) (*Probe, error) {
 			Name:  "getattr2",
 			Value: getAttr2(p),
 		},
+		manager.ConstantEditor{
+			Name:  "iter_type_ubuf",
+			Value: getIterTypeUbuf(p),
+		},
 	)
 	p.managerOptions.ConstantEditors = append(p.managerOptions.ConstantEditors, TTYConstants(p)...)
 	p.managerOptions.ConstantEditors = append(p.managerOptions.ConstantEditors, erpc.GetConstants()...)
@@ -933,8 +991,34 @@ func NewProbe(config *config.Config, client *statsd.Client) (*Probe, error) {
 
 	p.event = NewEvent(p.resolvers, p.scrubber)
 
+	if config.RecordEventsFile != "" {
+		if p.recorder, err = NewEventRecorder(config.RecordEventsFile); err != nil {
+			return nil, err
+		}
+	}
+
 	eventZero.resolvers = p.resolvers
 	eventZero.scrubber = p.scrubber
 
 	return p, nil
 }
+
+// iterTypeUnsupported is an iov_iter type that no kernel uses, it disables the ITER_UBUF handling
+const iterTypeUnsupported = uint64(0xff)
+
+// getIterTypeUbuf returns the value of ITER_UBUF in the iter_type enum, which was added in 6.0 and reordered since
+func getIterTypeUbuf(probe *Probe) uint64 {
+	switch code := probe.kernelVersion.Code; {
+	case code == 0 || code < kernel.Kernel6_0:
+		return iterTypeUnsupported
+	case code < kernel.Kernel6_5:
+		// ITER_IOVEC, ITER_KVEC, ITER_BVEC, ITER_PIPE, ITER_XARRAY, ITER_DISCARD, ITER_UBUF
+		return 6
+	case code < kernel.Kernel6_7:
+		// ITER_PIPE was removed
+		return 5
+	default:
+		// ITER_UBUF was moved first
+		return 0
+	}
+}
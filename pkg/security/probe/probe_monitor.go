@@ -107,6 +107,12 @@ func (m *Monitor) SendStats() error {
 		return errors.Wrap(err, "failed to send load controller stats")
 	}
 
+	if m.probe.recorder != nil {
+		if err := m.probe.recorder.SendStats(m.client); err != nil {
+			return errors.Wrap(err, "failed to send event recorder stats")
+		}
+	}
+
 	return nil
 }
 
@@ -11,6 +11,7 @@ package probe
 
 import (
 	"encoding/json"
+	"fmt"
 	"syscall"
 	"time"
 
@@ -23,6 +24,7 @@ const (
 	FIMCategory     = "File Activity"
 	ProcessActivity = "Process Activity"
 	KernelActivity  = "Kernel Activity"
+	NetworkActivity = "Network Activity"
 )
 
 // FileSerializer serializes a file to JSON
@@ -205,18 +207,132 @@ type SELinuxEventSerializer struct {
 	BoolCommit    *selinuxBoolCommitSerializer    `json:"bool_commit,omitempty"`
 }
 
+// IPPortSerializer serializes a socket address to JSON
+// easyjson:json
+type IPPortSerializer struct {
+	Family string `json:"family"`
+	IP     string `json:"ip,omitempty"`
+	Port   uint16 `json:"port"`
+}
+
+// ConnectEventSerializer serializes a connect event to JSON
+// easyjson:json
+type ConnectEventSerializer struct {
+	Addr IPPortSerializer `json:"addr"`
+}
+
+// BindEventSerializer serializes a bind event to JSON
+// easyjson:json
+type BindEventSerializer struct {
+	Addr IPPortSerializer `json:"addr"`
+}
+
+// DNSQuestionSerializer serializes a DNS question to JSON
+// easyjson:json
+type DNSQuestionSerializer struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Class  string `json:"class"`
+	Length uint16 `json:"length"`
+	Count  uint16 `json:"count"`
+}
+
+// DNSEventSerializer serializes a DNS request event to JSON
+// easyjson:json
+type DNSEventSerializer struct {
+	ID       uint16                `json:"id"`
+	Question DNSQuestionSerializer `json:"question"`
+}
+
+// ModuleEventSerializer serializes a kernel module load event to JSON
+// easyjson:json
+type ModuleEventSerializer struct {
+	Name             string `json:"name"`
+	LoadedFromMemory bool   `json:"loaded_from_memory"`
+}
+
+// PTraceEventSerializer serializes a ptrace event to JSON
+// easyjson:json
+type PTraceEventSerializer struct {
+	Request   string `json:"request"`
+	TraceePID uint32 `json:"tracee_pid"`
+	Address   string `json:"address"`
+}
+
+// MMapEventSerializer serializes an mmap event to JSON
+// easyjson:json
+type MMapEventSerializer struct {
+	Address    string `json:"address"`
+	Offset     uint64 `json:"offset"`
+	Len        uint32 `json:"length"`
+	Protection string `json:"protection"`
+	Flags      string `json:"flags"`
+}
+
+// MProtectEventSerializer serializes an mprotect event to JSON
+// easyjson:json
+type MProtectEventSerializer struct {
+	VMStart       string `json:"vm_start"`
+	VMEnd         string `json:"vm_end"`
+	VMProtection  string `json:"vm_protection"`
+	ReqProtection string `json:"req_protection"`
+}
+
+// BPFProgramSerializer serializes an eBPF program to JSON
+// easyjson:json
+type BPFProgramSerializer struct {
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// BPFEventSerializer serializes a bpf event to JSON
+// easyjson:json
+type BPFEventSerializer struct {
+	Cmd     string               `json:"cmd"`
+	Program BPFProgramSerializer `json:"program"`
+}
+
 // EventSerializer serializes an event to JSON
 // easyjson:json
 type EventSerializer struct {
 	*EventContextSerializer    `json:"evt,omitempty"`
 	*FileEventSerializer       `json:"file,omitempty"`
 	*SELinuxEventSerializer    `json:"selinux,omitempty"`
+	ConnectEventSerializer     *ConnectEventSerializer     `json:"connect,omitempty"`
+	BindEventSerializer        *BindEventSerializer        `json:"bind,omitempty"`
+	DNSEventSerializer         *DNSEventSerializer         `json:"dns,omitempty"`
+	ModuleEventSerializer      *ModuleEventSerializer      `json:"module,omitempty"`
+	PTraceEventSerializer      *PTraceEventSerializer      `json:"ptrace,omitempty"`
+	MMapEventSerializer        *MMapEventSerializer        `json:"mmap,omitempty"`
+	MProtectEventSerializer    *MProtectEventSerializer    `json:"mprotect,omitempty"`
+	BPFEventSerializer         *BPFEventSerializer         `json:"bpf,omitempty"`
 	UserContextSerializer      UserContextSerializer       `json:"usr,omitempty"`
 	ProcessContextSerializer   *ProcessContextSerializer   `json:"process,omitempty"`
 	ContainerContextSerializer *ContainerContextSerializer `json:"container,omitempty"`
 	Date                       time.Time                   `json:"date,omitempty"`
 }
 
+func newIPPortSerializer(c *model.IPPortContext) IPPortSerializer {
+	return IPPortSerializer{
+		Family: model.AddressFamily(c.Family).String(),
+		IP:     c.IP,
+		Port:   c.Port,
+	}
+}
+
+func newDNSEventSerializer(e *model.DNSEvent) *DNSEventSerializer {
+	return &DNSEventSerializer{
+		ID: e.ID,
+		Question: DNSQuestionSerializer{
+			Name:   e.Name,
+			Type:   model.QType(e.Type).String(),
+			Class:  model.QClass(e.Class).String(),
+			Length: e.Size,
+			Count:  e.Count,
+		},
+	}
+}
+
 func getInUpperLayer(r *Resolvers, f *model.FileFields) *bool {
 	lowerLayer := f.GetInLowerLayer()
 	upperLayer := f.GetInUpperLayer()
@@ -644,6 +760,77 @@ func NewEventSerializer(event *Event) *EventSerializer {
 		}
 		s.SELinuxEventSerializer = newSELinuxSerializer(event)
 		s.Category = KernelActivity
+	case model.ConnectEventType:
+		s.ConnectEventSerializer = &ConnectEventSerializer{
+			Addr: newIPPortSerializer(&event.Connect.Addr),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Connect.Retval)
+		s.Category = NetworkActivity
+	case model.BindEventType:
+		s.BindEventSerializer = &BindEventSerializer{
+			Addr: newIPPortSerializer(&event.Bind.Addr),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Bind.Retval)
+		s.Category = NetworkActivity
+	case model.DNSEventType:
+		s.DNSEventSerializer = newDNSEventSerializer(&event.DNS)
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(0)
+		s.Category = NetworkActivity
+	case model.LoadModuleEventType:
+		if !event.LoadModule.LoadedFromMemory {
+			s.FileEventSerializer = &FileEventSerializer{
+				FileSerializer: *newFileSerializer(&event.LoadModule.File, event),
+			}
+		}
+		s.ModuleEventSerializer = &ModuleEventSerializer{
+			Name:             event.LoadModule.Name,
+			LoadedFromMemory: event.LoadModule.LoadedFromMemory,
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.LoadModule.Retval)
+		s.Category = KernelActivity
+	case model.PTraceEventType:
+		s.PTraceEventSerializer = &PTraceEventSerializer{
+			Request:   model.PTraceRequest(event.PTrace.Request).String(),
+			TraceePID: event.PTrace.PID,
+			Address:   fmt.Sprintf("0x%x", event.PTrace.Address),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.PTrace.Retval)
+		s.Category = ProcessActivity
+	case model.MMapEventType:
+		// anonymous mappings aren't backed by a file
+		if event.MMap.File.Inode != 0 {
+			s.FileEventSerializer = &FileEventSerializer{
+				FileSerializer: *newFileSerializer(&event.MMap.File, event),
+			}
+		}
+		s.MMapEventSerializer = &MMapEventSerializer{
+			Address:    fmt.Sprintf("0x%x", event.MMap.Addr),
+			Offset:     event.MMap.Offset,
+			Len:        event.MMap.Len,
+			Protection: model.Protection(event.MMap.Protection).String(),
+			Flags:      model.MMapFlag(event.MMap.Flags).String(),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.MMap.Retval)
+		s.Category = KernelActivity
+	case model.MProtectEventType:
+		s.MProtectEventSerializer = &MProtectEventSerializer{
+			VMStart:       fmt.Sprintf("0x%x", event.MProtect.VMStart),
+			VMEnd:         fmt.Sprintf("0x%x", event.MProtect.VMEnd),
+			VMProtection:  model.Protection(event.MProtect.VMProtection).String(),
+			ReqProtection: model.Protection(event.MProtect.ReqProtection).String(),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.MProtect.Retval)
+		s.Category = KernelActivity
+	case model.BPFEventType:
+		s.BPFEventSerializer = &BPFEventSerializer{
+			Cmd: model.BPFCmd(event.BPF.Cmd).String(),
+			Program: BPFProgramSerializer{
+				Name: event.BPF.ProgName,
+				Type: model.BPFProgType(event.BPF.ProgType).String(),
+			},
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.BPF.Retval)
+		s.Category = KernelActivity
 	}
 
 	return s
@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// KillScopeProcess kills the process that triggered the rule
+	KillScopeProcess = "process"
+	// KillScopeContainer kills the init process of the container of the process that triggered the rule
+	KillScopeContainer = "container"
+
+	// DefaultKillSignal is the signal sent by the kill action when none is specified
+	DefaultKillSignal = "SIGKILL"
+)
+
+// SupportedKillSignals lists the signals that can be sent by the kill action
+var SupportedKillSignals = map[string]bool{
+	"SIGKILL": true,
+	"SIGTERM": true,
+	"SIGINT":  true,
+	"SIGQUIT": true,
+	"SIGHUP":  true,
+	"SIGSTOP": true,
+	"SIGUSR1": true,
+	"SIGUSR2": true,
+}
+
+// KillDefinition describes the kill action of a rule
+type KillDefinition struct {
+	Signal string `yaml:"signal"`
+	Scope  string `yaml:"scope"`
+}
+
+// GetSignal returns the signal to send, the default one if none was specified
+func (k *KillDefinition) GetSignal() string {
+	if k.Signal == "" {
+		return DefaultKillSignal
+	}
+	return k.Signal
+}
+
+// GetScope returns the scope of the kill action, the process by default
+func (k *KillDefinition) GetScope() string {
+	if k.Scope == "" {
+		return KillScopeProcess
+	}
+	return k.Scope
+}
+
+// SetDefinition describes the action setting a variable. Without scope, the variable is shared by all the rules
+// of the rule set. The value expires after the TTL, if any.
+type SetDefinition struct {
+	Name  string        `yaml:"name"`
+	Value interface{}   `yaml:"value"`
+	Scope string        `yaml:"scope"`
+	TTL   time.Duration `yaml:"ttl"`
+}
+
+// GetValue returns the value to set, true if none was specified
+func (s *SetDefinition) GetValue() interface{} {
+	if s.Value == nil {
+		return true
+	}
+	return s.Value
+}
+
+// CountDefinition describes the action incrementing a counter. Without scope, the counter is shared by all the
+// rules of the rule set. When a window is defined, only the increments of the last window are counted.
+type CountDefinition struct {
+	Name   string        `yaml:"name"`
+	Scope  string        `yaml:"scope"`
+	Window time.Duration `yaml:"window"`
+}
+
+// ActionDefinition describes an action executed when a rule matches. Variable actions are executed by the rule set
+// itself, before the listeners are notified of the match.
+type ActionDefinition struct {
+	Kill  *KillDefinition  `yaml:"kill"`
+	Set   *SetDefinition   `yaml:"set"`
+	Count *CountDefinition `yaml:"count"`
+}
+
+// Check returns an error if the action is invalid
+func (a *ActionDefinition) Check() error {
+	var defined int
+	for _, action := range []bool{a.Kill != nil, a.Set != nil, a.Count != nil} {
+		if action {
+			defined++
+		}
+	}
+
+	switch defined {
+	case 0:
+		return errors.New("no action defined")
+	case 1:
+	default:
+		return errors.New("only one action can be defined per entry")
+	}
+
+	switch {
+	case a.Kill != nil:
+		if !SupportedKillSignals[a.Kill.GetSignal()] {
+			return fmt.Errorf("unsupported kill signal `%s`", a.Kill.Signal)
+		}
+
+		switch a.Kill.GetScope() {
+		case KillScopeProcess, KillScopeContainer:
+		default:
+			return fmt.Errorf("unsupported kill scope `%s`", a.Kill.Scope)
+		}
+	case a.Set != nil:
+		if a.Set.Name == "" {
+			return errors.New("variable name missing")
+		}
+	case a.Count != nil:
+		if a.Count.Name == "" {
+			return errors.New("counter name missing")
+		}
+	}
+
+	return nil
+}
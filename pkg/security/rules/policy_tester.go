@@ -0,0 +1,132 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+// DiscarderTestReport describes a discarder found while evaluating an event
+type DiscarderTestReport struct {
+	Field eval.Field  `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// EventTestReport describes the evaluation of an event against a rule set
+type EventTestReport struct {
+	Index          int                    `json:"index"`
+	Type           eval.EventType         `json:"type"`
+	MatchedRules   []RuleID               `json:"matched_rules,omitempty"`
+	Discarders     []*DiscarderTestReport `json:"discarders,omitempty"`
+	EvaluationTime time.Duration          `json:"evaluation_time"`
+}
+
+// RuleTestReport describes the events matched by a rule
+type RuleTestReport struct {
+	Matches int   `json:"matches"`
+	Events  []int `json:"events,omitempty"`
+}
+
+// PolicyTestReport describes the evaluation of a stream of events against a rule set
+type PolicyTestReport struct {
+	Events                []*EventTestReport         `json:"events"`
+	Rules                 map[RuleID]*RuleTestReport `json:"rules"`
+	TotalEvaluationTime   time.Duration              `json:"total_evaluation_time"`
+	AverageEvaluationTime time.Duration              `json:"average_evaluation_time"`
+	MaxEvaluationTime     time.Duration              `json:"max_evaluation_time"`
+}
+
+// PolicyTester evaluates events against a rule set and reports the rules matched and the discarders found,
+// without requiring a running probe
+type PolicyTester struct {
+	ruleSet *RuleSet
+	report  *PolicyTestReport
+	current *EventTestReport
+}
+
+// NewPolicyTester returns a new PolicyTester for the given rule set
+func NewPolicyTester(rs *RuleSet) *PolicyTester {
+	pt := &PolicyTester{
+		ruleSet: rs,
+		report: &PolicyTestReport{
+			Rules: make(map[RuleID]*RuleTestReport),
+		},
+	}
+
+	for _, id := range rs.ListRuleIDs() {
+		pt.report.Rules[id] = &RuleTestReport{}
+	}
+
+	rs.AddListener(pt)
+
+	return pt
+}
+
+// RuleMatch is called by the rule set when a rule matches
+func (pt *PolicyTester) RuleMatch(rule *Rule, event eval.Event) {
+	if pt.current == nil {
+		return
+	}
+
+	pt.current.MatchedRules = append(pt.current.MatchedRules, rule.ID)
+
+	report, exists := pt.report.Rules[rule.ID]
+	if !exists {
+		report = &RuleTestReport{}
+		pt.report.Rules[rule.ID] = report
+	}
+	report.Matches++
+	report.Events = append(report.Events, pt.current.Index)
+}
+
+// EventDiscarderFound is called by the rule set when a discarder is found
+func (pt *PolicyTester) EventDiscarderFound(rs *RuleSet, event eval.Event, field eval.Field, eventType eval.EventType) {
+	if pt.current == nil {
+		return
+	}
+
+	value, err := event.GetFieldValue(field)
+	if err != nil {
+		return
+	}
+
+	pt.current.Discarders = append(pt.current.Discarders, &DiscarderTestReport{
+		Field: field,
+		Value: value,
+	})
+}
+
+// Test evaluates the event against the rule set and returns the report of this evaluation
+func (pt *PolicyTester) Test(event eval.Event) *EventTestReport {
+	pt.current = &EventTestReport{
+		Index: len(pt.report.Events),
+		Type:  event.GetType(),
+	}
+	defer func() {
+		pt.current = nil
+	}()
+
+	start := time.Now()
+	pt.ruleSet.Evaluate(event)
+	pt.current.EvaluationTime = time.Since(start)
+
+	report := pt.report
+	report.Events = append(report.Events, pt.current)
+	report.TotalEvaluationTime += pt.current.EvaluationTime
+	report.AverageEvaluationTime = report.TotalEvaluationTime / time.Duration(len(report.Events))
+	if pt.current.EvaluationTime > report.MaxEvaluationTime {
+		report.MaxEvaluationTime = pt.current.EvaluationTime
+	}
+
+	return pt.current
+}
+
+// GetReport returns the report of all the events evaluated so far
+func (pt *PolicyTester) GetReport() *PolicyTestReport {
+	return pt.report
+}
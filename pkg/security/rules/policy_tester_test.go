@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"reflect"
+	"syscall"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
+)
+
+func TestPolicyTester(t *testing.T) {
+	enabled := map[eval.EventType]bool{"*": true}
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(testConstants, testSupportedDiscarders, enabled, nil, nil))
+
+	addRuleExpr(t, rs,
+		`open.filename == "/etc/passwd" && process.uid != 0`,
+		`open.filename =~ "/etc/*" && open.flags & O_CREAT > 0`,
+	)
+
+	tester := NewPolicyTester(rs)
+
+	events := []*testEvent{
+		{
+			kind:    "open",
+			process: testProcess{uid: 1000},
+			open:    testOpen{filename: "/etc/passwd", flags: syscall.O_CREAT},
+		},
+		{
+			kind:    "open",
+			process: testProcess{uid: 1000},
+			open:    testOpen{filename: "/tmp/test", flags: syscall.O_RDONLY},
+		},
+		{
+			kind:    "open",
+			process: testProcess{uid: 0},
+			open:    testOpen{filename: "/etc/passwd", flags: syscall.O_RDONLY},
+		},
+	}
+
+	for _, event := range events {
+		tester.Test(event)
+	}
+
+	report := tester.GetReport()
+	if len(report.Events) != 3 {
+		t.Fatalf("expected 3 event reports, got %d", len(report.Events))
+	}
+
+	if matched := report.Events[0].MatchedRules; !reflect.DeepEqual(matched, []RuleID{"ID0", "ID1"}) {
+		t.Errorf("unexpected matched rules for the first event: %v", matched)
+	}
+
+	if len(report.Events[1].MatchedRules) != 0 {
+		t.Errorf("unexpected matched rules for the second event: %v", report.Events[1].MatchedRules)
+	}
+
+	discarders := report.Events[1].Discarders
+	if len(discarders) != 1 || discarders[0].Field != "open.filename" || discarders[0].Value != "/tmp/test" {
+		t.Errorf("unexpected discarders for the second event: %+v", discarders)
+	}
+
+	for _, discarder := range report.Events[2].Discarders {
+		if discarder.Field == "open.filename" {
+			t.Errorf("open.filename shouldn't be a discarder for the third event")
+		}
+	}
+
+	if rule := report.Rules["ID0"]; rule.Matches != 1 || !reflect.DeepEqual(rule.Events, []int{0}) {
+		t.Errorf("unexpected report for rule ID0: %+v", rule)
+	}
+
+	if rule := report.Rules["ID1"]; rule.Matches != 1 {
+		t.Errorf("unexpected report for rule ID1: %+v", rule)
+	}
+
+	if report.TotalEvaluationTime < report.MaxEvaluationTime || report.MaxEvaluationTime < report.AverageEvaluationTime {
+		t.Errorf("inconsistent evaluation timings: %+v", report)
+	}
+}
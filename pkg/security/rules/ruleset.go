@@ -35,11 +35,12 @@ type RuleID = string
 
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID          RuleID            `yaml:"id"`
-	Version     string            `yaml:"version"`
-	Expression  string            `yaml:"expression"`
-	Description string            `yaml:"description"`
-	Tags        map[string]string `yaml:"tags"`
+	ID          RuleID             `yaml:"id"`
+	Version     string             `yaml:"version"`
+	Expression  string             `yaml:"expression"`
+	Description string             `yaml:"description"`
+	Tags        map[string]string  `yaml:"tags"`
+	Actions     []ActionDefinition `yaml:"actions"`
 	Policy      *Policy
 }
 
@@ -70,10 +71,12 @@ type RuleSetListener interface {
 // Opts defines rules set options
 type Opts struct {
 	eval.Opts
-	SupportedDiscarders map[eval.Field]bool
-	ReservedRuleIDs     []RuleID
-	EventTypeEnabled    map[eval.EventType]bool
-	Logger              Logger
+	SupportedDiscarders  map[eval.Field]bool
+	ReservedRuleIDs      []RuleID
+	EventTypeEnabled     map[eval.EventType]bool
+	Logger               Logger
+	VariableScopes       map[string]eval.VariableScope
+	MaxVariableInstances int
 }
 
 // NewOptsWithParams initializes a new Opts instance with Debug and Constants parameters
@@ -179,8 +182,18 @@ func (rs *RuleSet) AddMacro(macroDef *MacroDefinition) (*eval.Macro, error) {
 func (rs *RuleSet) AddRules(rules []*RuleDefinition) *multierror.Error {
 	var result *multierror.Error
 
+	// declare the variables of all the rules first, once, so that a rule can read a variable set by another one
+	loaded := make([]*RuleDefinition, 0, len(rules))
 	for _, ruleDef := range rules {
-		if _, err := rs.AddRule(ruleDef); err != nil {
+		if err := rs.loadActions(ruleDef); err != nil {
+			result = multierror.Append(result, err)
+			continue
+		}
+		loaded = append(loaded, ruleDef)
+	}
+
+	for _, ruleDef := range loaded {
+		if _, err := rs.addRule(ruleDef); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
@@ -194,6 +207,29 @@ func (rs *RuleSet) AddRules(rules []*RuleDefinition) *multierror.Error {
 
 // AddRule creates the rule evaluator and adds it to the bucket of its events
 func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
+	if err := rs.loadActions(ruleDef); err != nil {
+		return nil, err
+	}
+	return rs.addRule(ruleDef)
+}
+
+// loadActions checks the actions of a rule and declares the variables they use
+func (rs *RuleSet) loadActions(ruleDef *RuleDefinition) error {
+	for _, action := range ruleDef.Actions {
+		if err := action.Check(); err != nil {
+			return &ErrRuleLoad{Definition: ruleDef, Err: errors.Wrap(err, "invalid action")}
+		}
+	}
+
+	if err := rs.declareVariables(ruleDef); err != nil {
+		return &ErrRuleLoad{Definition: ruleDef, Err: errors.Wrap(err, "invalid action")}
+	}
+
+	return nil
+}
+
+// addRule adds a rule whose actions were loaded
+func (rs *RuleSet) addRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 	for _, id := range rs.opts.ReservedRuleIDs {
 		if id == ruleDef.ID {
 			return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrInternalIDConflict}
@@ -265,6 +301,59 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 	return rule.Rule, nil
 }
 
+func (rs *RuleSet) declareVariables(ruleDef *RuleDefinition) error {
+	for _, action := range ruleDef.Actions {
+		switch {
+		case action.Set != nil:
+			if _, err := rs.opts.Variables.Declare(action.Set.Name, action.Set.Scope, eval.ValueVariable, action.Set.GetValue(), action.Set.TTL); err != nil {
+				return err
+			}
+		case action.Count != nil:
+			if _, err := rs.opts.Variables.Declare(action.Count.Name, action.Count.Scope, eval.CounterVariable, nil, action.Count.Window); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyVariableActions executes the variable actions of a rule that matched
+func (rs *RuleSet) applyVariableActions(rule *Rule, ctx *eval.Context) {
+	for _, action := range rule.Definition.Actions {
+		var err error
+
+		switch {
+		case action.Set != nil:
+			if variable := rs.opts.Variables.Get(eval.GetVariableName(action.Set.Name, action.Set.Scope)); variable != nil {
+				err = variable.Set(ctx, action.Set.GetValue())
+			}
+		case action.Count != nil:
+			if variable := rs.opts.Variables.Get(eval.GetVariableName(action.Count.Name, action.Count.Scope)); variable != nil {
+				err = variable.Increment(ctx)
+			}
+		}
+
+		if err != nil {
+			rs.logger.Errorf("failed to apply an action of rule `%s`: %s", rule.ID, err)
+		}
+	}
+}
+
+// GetVariables returns the variables declared by the rules of the rule set
+func (rs *RuleSet) GetVariables() *eval.VariableStore {
+	return rs.opts.Variables
+}
+
+// ReleaseVariableScope removes the instances of the variables of the given scope matching the event,
+// for example when a process exits
+func (rs *RuleSet) ReleaseVariableScope(scope string, event eval.Event) {
+	ctx := rs.pool.Get(event.GetPointer())
+	defer rs.pool.Put(ctx)
+
+	rs.opts.Variables.ReleaseScope(scope, ctx)
+}
+
 // NotifyRuleMatch notifies all the ruleset listeners that an event matched a rule
 func (rs *RuleSet) NotifyRuleMatch(rule *Rule, event eval.Event) {
 	for _, listener := range rs.listeners {
@@ -386,6 +475,7 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 		if rule.GetEvaluator().Eval(ctx) {
 			rs.logger.Tracef("Rule `%s` matches with event `%s`\n", rule.ID, event)
 
+			rs.applyVariableActions(rule, ctx)
 			rs.NotifyRuleMatch(rule, event)
 			result = true
 		}
@@ -462,6 +552,10 @@ func (rs *RuleSet) AddPolicyVersion(filename string, version string) {
 
 // NewRuleSet returns a new ruleset for the specified data model
 func NewRuleSet(model eval.Model, eventCtor func() eval.Event, opts *Opts) *RuleSet {
+	if opts.Variables == nil {
+		opts.Variables = eval.NewVariableStore(opts.VariableScopes, opts.MaxVariableInstances)
+	}
+
 	return &RuleSet{
 		model:            model,
 		eventCtor:        eventCtor,
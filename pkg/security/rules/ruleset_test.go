@@ -8,8 +8,10 @@ package rules
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/eval"
 )
@@ -379,3 +381,194 @@ func TestRuleSetFilters6(t *testing.T) {
 		t.Fatal("shouldn't get any approver")
 	}
 }
+
+func TestRuleSetActions(t *testing.T) {
+	enabled := map[eval.EventType]bool{"*": true}
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(testConstants, testSupportedDiscarders, enabled, nil, nil))
+
+	policy, err := LoadPolicy(strings.NewReader(`
+rules:
+  - id: kill_process
+    expression: open.filename == "/etc/shadow"
+    actions:
+      - kill:
+          signal: SIGTERM
+  - id: kill_container
+    expression: open.filename == "/etc/passwd"
+    actions:
+      - kill:
+          scope: container
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rs.AddRules(policy.Rules); err != nil {
+		t.Fatal(err)
+	}
+
+	kill := rs.GetRules()["kill_process"].Definition.Actions[0].Kill
+	if kill.GetSignal() != "SIGTERM" || kill.GetScope() != KillScopeProcess {
+		t.Errorf("unexpected kill action: %+v", kill)
+	}
+
+	kill = rs.GetRules()["kill_container"].Definition.Actions[0].Kill
+	if kill.GetSignal() != DefaultKillSignal || kill.GetScope() != KillScopeContainer {
+		t.Errorf("unexpected kill action: %+v", kill)
+	}
+
+	invalid := []ActionDefinition{
+		{},
+		{Kill: &KillDefinition{Signal: "SIGSEGV"}},
+		{Kill: &KillDefinition{Scope: "host"}},
+	}
+	for i, action := range invalid {
+		_, err := rs.AddRule(&RuleDefinition{
+			ID:         fmt.Sprintf("invalid_%d", i),
+			Expression: `open.filename == "/etc/group"`,
+			Actions:    []ActionDefinition{action},
+		})
+		if err == nil {
+			t.Errorf("expected an error for action %+v", action)
+		}
+	}
+}
+
+func TestRuleSetVariables(t *testing.T) {
+	enabled := map[eval.EventType]bool{"*": true}
+	opts := NewOptsWithParams(testConstants, testSupportedDiscarders, enabled, nil, nil)
+	opts.VariableScopes = map[string]eval.VariableScope{
+		"process": func(ctx *eval.Context) (interface{}, bool) {
+			name := (*testEvent)(ctx.Object).process.name
+			return name, name != ""
+		},
+	}
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, opts)
+
+	policy, err := LoadPolicy(strings.NewReader(`
+rules:
+  - id: brute_force
+    expression: open.filename == "/etc/shadow" && ${process.shadow_opens} in the last 1m >= 2
+    actions:
+      - set:
+          name: brute_forcer
+          scope: process
+  - id: count_shadow
+    expression: open.filename == "/etc/shadow" && process.uid != 0
+    actions:
+      - count:
+          name: shadow_opens
+          scope: process
+          window: 10s
+      - set:
+          name: last_uid
+          value: 1000
+  - id: brute_forcer_write
+    expression: open.filename == "/tmp/test" && ${process.brute_forcer} && ${last_uid} == process.uid
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rs.AddRules(policy.Rules); err != nil {
+		t.Fatal(err)
+	}
+
+	tester := NewPolicyTester(rs)
+
+	shadow := &testEvent{kind: "open", process: testProcess{name: "john", uid: 1000}, open: testOpen{filename: "/etc/shadow"}}
+	write := &testEvent{kind: "open", process: testProcess{name: "john", uid: 1000}, open: testOpen{filename: "/tmp/test"}}
+	other := &testEvent{kind: "open", process: testProcess{name: "vi", uid: 1000}, open: testOpen{filename: "/tmp/test"}}
+
+	for _, event := range []*testEvent{write, shadow, shadow, write, shadow, write, other} {
+		tester.Test(event)
+	}
+
+	report := tester.GetReport()
+	if rule := report.Rules["count_shadow"]; rule.Matches != 3 {
+		t.Errorf("unexpected report for rule count_shadow: %+v", rule)
+	}
+	if rule := report.Rules["brute_force"]; !reflect.DeepEqual(rule.Events, []int{4}) {
+		t.Errorf("unexpected report for rule brute_force: %+v", rule)
+	}
+	if rule := report.Rules["brute_forcer_write"]; !reflect.DeepEqual(rule.Events, []int{5}) {
+		t.Errorf("unexpected report for rule brute_forcer_write: %+v", rule)
+	}
+
+	counter := rs.GetVariables().Get("process.shadow_opens")
+	if counter == nil || counter.TTL != 10*time.Second || counter.Len() != 1 {
+		t.Fatalf("unexpected counter: %+v", counter)
+	}
+
+	rs.ReleaseVariableScope("process", shadow)
+	if counter.Len() != 0 {
+		t.Errorf("expected the counter instance to be released")
+	}
+
+	invalid := []ActionDefinition{
+		{Set: &SetDefinition{}},
+		{Count: &CountDefinition{}},
+		{Set: &SetDefinition{Name: "last_uid", Value: "1000"}},
+		{Count: &CountDefinition{Name: "shadow_opens", Scope: "container"}},
+		{Set: &SetDefinition{Name: "flag"}, Count: &CountDefinition{Name: "counter"}},
+	}
+	for i, action := range invalid {
+		_, err := rs.AddRule(&RuleDefinition{
+			ID:         fmt.Sprintf("invalid_%d", i),
+			Expression: `open.filename == "/etc/group"`,
+			Actions:    []ActionDefinition{action},
+		})
+		if err == nil {
+			t.Errorf("expected an error for action %+v", action)
+		}
+	}
+
+	if _, err := rs.AddRule(&RuleDefinition{ID: "unknown_variable", Expression: `open.filename == "/etc/group" && ${unknown}`}); err == nil {
+		t.Error("expected an unknown variable error")
+	}
+}
+
+func TestRuleSetVariablesConflict(t *testing.T) {
+	enabled := map[eval.EventType]bool{"*": true}
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(testConstants, testSupportedDiscarders, enabled, nil, nil))
+
+	policy, err := LoadPolicy(strings.NewReader(`
+rules:
+  - id: set_flag
+    expression: open.filename == "/etc/shadow"
+    actions:
+      - set:
+          name: flag
+  - id: set_flag_as_string
+    expression: open.filename == "/etc/passwd"
+    actions:
+      - set:
+          name: flag
+          value: "true"
+  - id: read_flag
+    expression: open.filename == "/tmp/test" && ${flag}
+`), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merr := rs.AddRules(policy.Rules)
+	if merr == nil || len(merr.Errors) != 1 {
+		t.Fatalf("expected a single error, got %v", merr)
+	}
+	if rerr, ok := merr.Errors[0].(*ErrRuleLoad); !ok || rerr.Definition.ID != "set_flag_as_string" {
+		t.Fatalf("expected a load error for set_flag_as_string, got %v", merr.Errors[0])
+	}
+
+	if !rs.HasRulesForEventType("open") {
+		t.Fatal("expected the other rules to be loaded")
+	}
+	for _, id := range []string{"set_flag", "read_flag"} {
+		if _, found := rs.rules[id]; !found {
+			t.Errorf("expected rule %s to be loaded", id)
+		}
+	}
+	if _, found := rs.rules["set_flag_as_string"]; found {
+		t.Error("expected rule set_flag_as_string not to be loaded")
+	}
+}
@@ -18,9 +18,10 @@ import (
 var (
 	seclLexer = lexer.Must(ebnf.New(`
 Comment = ("#" | "//") { "\u0000"…"\uffff"-"\n" } .
-Duration = digit { digit } ("ms" | "s" | "m" | "h" | "d") .
+Duration = digit { digit } ("m" ["s"] | "s" | "h" | "d") .
 Regexp = "r\"" { "\u0000"…"\uffff"-"\""-"\\" | "\\" any } "\"" .
 Ident = (alpha | "_") { "_" | alpha | digit | "." | "[" | "]" } .
+Variable = "${" (alpha | "_") { "_" | alpha | digit | "." } "}" .
 String = "\"" { "\u0000"…"\uffff"-"\""-"\\" | "\\" any } "\"" .
 Pattern = "~\"" { "\u0000"…"\uffff"-"\""-"\\" | "\\" any } "\"" .
 Int = [ "-" | "+" ] digit { digit } .
@@ -42,6 +43,12 @@ func unquotePattern(t lexer.Token) (lexer.Token, error) {
 	return t, nil
 }
 
+func unquoteVariable(t lexer.Token) (lexer.Token, error) {
+	t.Value = t.Value[2 : len(t.Value)-1]
+
+	return t, nil
+}
+
 func parseDuration(t lexer.Token) (lexer.Token, error) {
 	duration, err := time.ParseDuration(t.Value)
 	if err != nil {
@@ -60,6 +67,8 @@ func buildParser(obj interface{}) (*participle.Parser, error) {
 		participle.Unquote("String"),
 		participle.Map(parseDuration, "Duration"),
 		participle.Map(unquotePattern, "Pattern", "Regexp"),
+		participle.Map(unquoteVariable, "Variable"),
+		participle.UseLookahead(3),
 	)
 }
 
@@ -180,6 +189,7 @@ type Primary struct {
 	Pos lexer.Position
 
 	Ident         *string     `parser:"@Ident"`
+	Variable      *Variable   `parser:"| @@"`
 	Number        *int        `parser:"| @Int"`
 	String        *string     `parser:"| @String"`
 	Pattern       *string     `parser:"| @Pattern"`
@@ -188,6 +198,15 @@ type Primary struct {
 	SubExpression *Expression `parser:"| \"(\" @@ \")\""`
 }
 
+// Variable describes a reference to a variable, optionally restricted to the updates that happened in the
+// given time window
+type Variable struct {
+	Pos lexer.Position
+
+	Name   *string `parser:"@Variable"`
+	Window *int    `parser:"[ \"in\" \"the\" \"last\" @Duration ]"`
+}
+
 // StringMember describes a String based array member
 type StringMember struct {
 	Pos lexer.Position
@@ -8,6 +8,7 @@ package ast
 import (
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func print(t *testing.T, i interface{}) {
@@ -213,3 +214,23 @@ func TestDuration(t *testing.T) {
 
 	print(t, rule)
 }
+
+func TestVariable(t *testing.T) {
+	rule, err := ParseRule(`${process.failed_opens} > 5 && ${suspicious} in the last 5m && ${process.name} in ["a", "b"]`)
+	if err != nil {
+		t.Error(err)
+	}
+
+	print(t, rule)
+
+	comparison := rule.BooleanExpression.Expression.Comparison
+	variable := comparison.BitOperation.Unary.Primary.Variable
+	if variable == nil || *variable.Name != "process.failed_opens" || variable.Window != nil {
+		t.Errorf("unexpected variable: %+v", variable)
+	}
+
+	variable = rule.BooleanExpression.Expression.Next.Expression.Comparison.BitOperation.Unary.Primary.Variable
+	if variable == nil || *variable.Name != "suspicious" || variable.Window == nil || *variable.Window != int(5*time.Minute) {
+		t.Errorf("unexpected variable: %+v", variable)
+	}
+}
@@ -56,6 +56,7 @@ type Opts struct {
 	LegacyAttributes map[Field]Field
 	Constants        map[string]interface{}
 	Macros           map[MacroID]*Macro
+	Variables        *VariableStore
 }
 
 // Evaluator is the interface of an evaluator
@@ -977,6 +978,8 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, le
 		switch {
 		case obj.Ident != nil:
 			return identToEvaluator(&ident{Pos: obj.Pos, Ident: obj.Ident}, opts, state)
+		case obj.Variable != nil:
+			return variableToEvaluator(obj.Variable, opts, state)
 		case obj.Number != nil:
 			return &IntEvaluator{
 				Value: *obj.Number,
@@ -0,0 +1,442 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package eval
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/participle/lexer"
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/ast"
+)
+
+const (
+	// DefaultMaxVariableInstances is the default maximum number of instances kept for a scoped variable
+	DefaultMaxVariableInstances = 4096
+	// MaxCounterEvents is the maximum number of increments kept per counter instance to evaluate time windows.
+	// A count evaluated over a time window saturates at this value.
+	MaxCounterEvents = 256
+)
+
+var variableNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// VariableKind describes the kind of a variable
+type VariableKind int
+
+const (
+	// ValueVariable holds the last value set by a rule action
+	ValueVariable VariableKind = iota
+	// CounterVariable holds the number of times a rule action incremented it
+	CounterVariable
+)
+
+func (k VariableKind) String() string {
+	if k == CounterVariable {
+		return "counter"
+	}
+	return "value"
+}
+
+// VariableScope returns the key of the variable instance to use for the evaluated object, the pid
+// of the process of an event for example. It returns false if the object doesn't have such a key.
+type VariableScope func(ctx *Context) (interface{}, bool)
+
+type variableInstance struct {
+	key       interface{}
+	value     interface{}
+	updatedAt int64
+
+	// counter only, the timestamps of the last increments are kept in a ring
+	total  int
+	events []int64
+	next   int
+}
+
+func (i *variableInstance) countSince(since int64) int {
+	var count int
+	for _, timestamp := range i.events {
+		if timestamp > since {
+			count++
+		}
+	}
+	return count
+}
+
+// Variable describes a variable that can be set by rule actions and read by rule expressions. A variable is
+// either global to a rule set or scoped, in which case one instance of the variable is kept per scope key.
+// The number of instances is bounded, the least recently updated instances being evicted first. When a TTL
+// is defined, a value expires TTL after its last update, and a counter only counts the increments that
+// happened during the last TTL.
+type Variable struct {
+	sync.Mutex
+
+	Name  string
+	Scope string
+	Kind  VariableKind
+	TTL   time.Duration
+
+	scopeFnc     VariableScope
+	defaultValue interface{}
+	maxInstances int
+	instances    map[interface{}]*list.Element
+	lru          *list.List
+}
+
+func (v *Variable) getKey(ctx *Context) (interface{}, bool) {
+	if v.scopeFnc == nil {
+		return "", true
+	}
+	return v.scopeFnc(ctx)
+}
+
+func (v *Variable) isExpired(instance *variableInstance, now int64) bool {
+	return v.TTL > 0 && now-instance.updatedAt > int64(v.TTL)
+}
+
+func (v *Variable) removeElement(element *list.Element) {
+	delete(v.instances, element.Value.(*variableInstance).key)
+	v.lru.Remove(element)
+}
+
+// purge removes the expired and the exceeding instances, starting by the least recently updated ones
+func (v *Variable) purge(now int64) {
+	for element := v.lru.Back(); element != nil; element = v.lru.Back() {
+		if v.lru.Len() <= v.maxInstances && !v.isExpired(element.Value.(*variableInstance), now) {
+			return
+		}
+		v.removeElement(element)
+	}
+}
+
+func (v *Variable) getInstance(key interface{}, now int64) *variableInstance {
+	element, exists := v.instances[key]
+	if !exists {
+		return nil
+	}
+
+	instance := element.Value.(*variableInstance)
+	if v.isExpired(instance, now) {
+		v.removeElement(element)
+		return nil
+	}
+
+	return instance
+}
+
+func (v *Variable) update(ctx *Context, updateFnc func(instance *variableInstance, now int64)) {
+	key, ok := v.getKey(ctx)
+	if !ok {
+		return
+	}
+	now := ctx.Now().UnixNano()
+
+	v.Lock()
+	defer v.Unlock()
+
+	var instance *variableInstance
+	if element, exists := v.instances[key]; exists {
+		instance = element.Value.(*variableInstance)
+		if v.isExpired(instance, now) {
+			*instance = variableInstance{key: key}
+		}
+		v.lru.MoveToFront(element)
+	} else {
+		instance = &variableInstance{key: key}
+		v.instances[key] = v.lru.PushFront(instance)
+	}
+
+	updateFnc(instance, now)
+	instance.updatedAt = now
+
+	v.purge(now)
+}
+
+// Set sets the value of the instance of the variable matching the context
+func (v *Variable) Set(ctx *Context, value interface{}) error {
+	if v.Kind != ValueVariable {
+		return fmt.Errorf("variable `%s` is a %s", v.Name, v.Kind)
+	}
+
+	if reflect.TypeOf(value) != reflect.TypeOf(v.defaultValue) {
+		return fmt.Errorf("invalid value type for variable `%s`: %T expected, got %T", v.Name, v.defaultValue, value)
+	}
+
+	v.update(ctx, func(instance *variableInstance, now int64) {
+		instance.value = value
+	})
+
+	return nil
+}
+
+// Increment increments the instance of the counter matching the context
+func (v *Variable) Increment(ctx *Context) error {
+	if v.Kind != CounterVariable {
+		return fmt.Errorf("variable `%s` is a %s", v.Name, v.Kind)
+	}
+
+	v.update(ctx, func(instance *variableInstance, now int64) {
+		instance.total++
+		if len(instance.events) < MaxCounterEvents {
+			instance.events = append(instance.events, now)
+		} else {
+			instance.events[instance.next] = now
+			instance.next = (instance.next + 1) % MaxCounterEvents
+		}
+	})
+
+	return nil
+}
+
+// Get returns the value of the instance of the variable matching the context, the zero value of the variable
+// if there is none. When window is not zero, only the updates that happened during the last window are
+// taken into account.
+func (v *Variable) Get(ctx *Context, window time.Duration) interface{} {
+	key, ok := v.getKey(ctx)
+	if !ok {
+		return v.defaultValue
+	}
+	now := ctx.Now().UnixNano()
+
+	v.Lock()
+	defer v.Unlock()
+
+	instance := v.getInstance(key, now)
+	if instance == nil {
+		return v.defaultValue
+	}
+
+	if v.Kind == CounterVariable {
+		if window == 0 || (v.TTL != 0 && v.TTL < window) {
+			window = v.TTL
+		}
+		if window == 0 {
+			return instance.total
+		}
+		return instance.countSince(now - int64(window))
+	}
+
+	if window != 0 && now-instance.updatedAt > int64(window) {
+		return v.defaultValue
+	}
+	return instance.value
+}
+
+// Release removes the instance of the variable matching the context
+func (v *Variable) Release(ctx *Context) {
+	key, ok := v.getKey(ctx)
+	if !ok {
+		return
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if element, exists := v.instances[key]; exists {
+		v.removeElement(element)
+	}
+}
+
+// Len returns the number of instances of the variable
+func (v *Variable) Len() int {
+	v.Lock()
+	defer v.Unlock()
+
+	return v.lru.Len()
+}
+
+// GetEvaluator returns an evaluator reading the variable, see Get for the window semantics. As the value of a
+// variable doesn't depend on the evaluated field, the evaluator is always partial.
+func (v *Variable) GetEvaluator(window time.Duration) interface{} {
+	switch v.defaultValue.(type) {
+	case bool:
+		return &BoolEvaluator{
+			EvalFnc: func(ctx *Context) bool {
+				return v.Get(ctx, window).(bool)
+			},
+			Weight:    FunctionWeight,
+			isPartial: true,
+		}
+	case int:
+		return &IntEvaluator{
+			EvalFnc: func(ctx *Context) int {
+				return v.Get(ctx, window).(int)
+			},
+			Weight:    FunctionWeight,
+			isPartial: true,
+		}
+	default:
+		return &StringEvaluator{
+			EvalFnc: func(ctx *Context) string {
+				return v.Get(ctx, window).(string)
+			},
+			Weight:    FunctionWeight,
+			isPartial: true,
+			valueType: ScalarValueType,
+		}
+	}
+}
+
+// VariableStore holds the variables declared by a rule set
+type VariableStore struct {
+	sync.RWMutex
+
+	scopes       map[string]VariableScope
+	maxInstances int
+	variables    map[string]*Variable
+}
+
+// GetVariableName returns the name used to refer to a variable in rule expressions
+func GetVariableName(name string, scope string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+// Declare declares a variable. The type of a value variable is the type of the given value, which can either
+// be a bool, an int or a string. Declaring an already declared variable returns it if the definitions
+// are identical.
+func (s *VariableStore) Declare(name string, scope string, kind VariableKind, value interface{}, ttl time.Duration) (*Variable, error) {
+	if !variableNameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("invalid variable name `%s`", name)
+	}
+
+	var scopeFnc VariableScope
+	if scope != "" {
+		var exists bool
+		if scopeFnc, exists = s.scopes[scope]; !exists {
+			return nil, fmt.Errorf("unknown variable scope `%s`", scope)
+		}
+	}
+
+	if ttl < 0 {
+		return nil, fmt.Errorf("invalid ttl for variable `%s`", name)
+	}
+
+	var defaultValue interface{}
+	switch kind {
+	case CounterVariable:
+		defaultValue = 0
+	case ValueVariable:
+		switch value.(type) {
+		case bool:
+			defaultValue = false
+		case int:
+			defaultValue = 0
+		case string:
+			defaultValue = ""
+		default:
+			return nil, fmt.Errorf("unsupported value type for variable `%s`: %T", name, value)
+		}
+	default:
+		return nil, fmt.Errorf("unknown variable kind for variable `%s`", name)
+	}
+
+	fullName := GetVariableName(name, scope)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if variable, exists := s.variables[fullName]; exists {
+		if variable.Kind != kind || variable.TTL != ttl || reflect.TypeOf(variable.defaultValue) != reflect.TypeOf(defaultValue) {
+			return nil, fmt.Errorf("conflicting definitions for variable `%s`", fullName)
+		}
+		return variable, nil
+	}
+
+	variable := &Variable{
+		Name:         fullName,
+		Scope:        scope,
+		Kind:         kind,
+		TTL:          ttl,
+		scopeFnc:     scopeFnc,
+		defaultValue: defaultValue,
+		maxInstances: s.maxInstances,
+		instances:    make(map[interface{}]*list.Element),
+		lru:          list.New(),
+	}
+	if scope == "" {
+		variable.maxInstances = 1
+	}
+	s.variables[fullName] = variable
+
+	return variable, nil
+}
+
+// Get returns the variable referred to by the given name, nil if it wasn't declared
+func (s *VariableStore) Get(name string) *Variable {
+	s.RLock()
+	defer s.RUnlock()
+
+	return s.variables[name]
+}
+
+// ReleaseScope removes the instances matching the context of all the variables of the given scope, for
+// example when a process exits
+func (s *VariableStore) ReleaseScope(scope string, ctx *Context) {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, variable := range s.variables {
+		if variable.Scope == scope {
+			variable.Release(ctx)
+		}
+	}
+}
+
+// NewVariableStore returns a new variable store supporting the given scopes. maxInstances bounds the number of
+// instances kept per scoped variable, DefaultMaxVariableInstances is used if zero.
+func NewVariableStore(scopes map[string]VariableScope, maxInstances int) *VariableStore {
+	if maxInstances <= 0 {
+		maxInstances = DefaultMaxVariableInstances
+	}
+
+	return &VariableStore{
+		scopes:       scopes,
+		maxInstances: maxInstances,
+		variables:    make(map[string]*Variable),
+	}
+}
+
+func variableToEvaluator(obj *ast.Variable, opts *Opts, state *state) (interface{}, lexer.Position, error) {
+	var variable *Variable
+	if opts.Variables != nil {
+		variable = opts.Variables.Get(*obj.Name)
+	}
+	if variable == nil {
+		return nil, obj.Pos, NewError(obj.Pos, fmt.Sprintf("unknown variable `%s`", *obj.Name))
+	}
+
+	var window time.Duration
+	if obj.Window != nil {
+		if window = time.Duration(*obj.Window); window <= 0 {
+			return nil, obj.Pos, NewOpError(obj.Pos, "in the last", errors.New("invalid time window"))
+		}
+	}
+
+	evaluator := variable.GetEvaluator(window)
+
+	// while generating a partial, a variable is handled like another field so that any comparison
+	// involving it is considered as partial, its value being unknown
+	if state.field != "" {
+		switch evaluator := evaluator.(type) {
+		case *BoolEvaluator:
+			evaluator.Field = "${" + variable.Name + "}"
+		case *IntEvaluator:
+			evaluator.Field = "${" + variable.Name + "}"
+		case *StringEvaluator:
+			evaluator.Field = "${" + variable.Name + "}"
+		}
+	}
+
+	return evaluator, obj.Pos, nil
+}
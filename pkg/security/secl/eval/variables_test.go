@@ -0,0 +1,318 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package eval
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+var testVariableScopes = map[string]VariableScope{
+	"process": func(ctx *Context) (interface{}, bool) {
+		name := (*testEvent)(ctx.Object).process.name
+		return name, name != ""
+	},
+}
+
+func newTestVariableStore(t *testing.T, maxInstances int) *VariableStore {
+	store := NewVariableStore(testVariableScopes, maxInstances)
+
+	for _, def := range []struct {
+		name  string
+		scope string
+		kind  VariableKind
+		value interface{}
+		ttl   time.Duration
+	}{
+		{name: "failed_opens", scope: "process", kind: CounterVariable, ttl: 10 * time.Second},
+		{name: "opens", scope: "process", kind: CounterVariable},
+		{name: "suspicious", scope: "process", kind: ValueVariable, value: true, ttl: time.Minute},
+		{name: "last_file", kind: ValueVariable, value: ""},
+		{name: "last_uid", kind: ValueVariable, value: 0},
+	} {
+		if _, err := store.Declare(def.name, def.scope, def.kind, def.value, def.ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return store
+}
+
+func newTestVariableContext(event *testEvent, now time.Time) *Context {
+	ctx := NewContext(unsafe.Pointer(event))
+	ctx.now = now
+	return ctx
+}
+
+func evalWithVariables(t *testing.T, store *VariableStore, ctx *Context, expr string) bool {
+	rule, err := parseRule(expr, &testModel{}, &Opts{Constants: testConstants, Variables: store})
+	if err != nil {
+		t.Fatalf("error while evaluating `%s`: %s", expr, err)
+	}
+
+	return rule.Eval(ctx)
+}
+
+func TestVariables(t *testing.T) {
+	store := newTestVariableStore(t, 0)
+
+	now := time.Now()
+	event := &testEvent{
+		process: testProcess{name: "cat", uid: 1000},
+		open:    testOpen{filename: "/etc/shadow"},
+	}
+	ctx := newTestVariableContext(event, now)
+
+	for i := 0; i != 6; i++ {
+		if err := store.Get("process.failed_opens").Increment(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Get("process.suspicious").Set(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Get("last_file").Set(ctx, "/etc/shadow"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Get("last_uid").Set(ctx, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	other := newTestVariableContext(&testEvent{process: testProcess{name: "ls"}}, now)
+
+	tests := []struct {
+		Expr     string
+		Ctx      *Context
+		Expected bool
+	}{
+		{Expr: `${process.failed_opens} > 5 && process.uid == 1000`, Ctx: ctx, Expected: true},
+		{Expr: `${process.failed_opens} > 5`, Ctx: other, Expected: false},
+		{Expr: `${process.failed_opens} == 0`, Ctx: other, Expected: true},
+		{Expr: `${process.opens} == 0`, Ctx: ctx, Expected: true},
+		{Expr: `${process.suspicious}`, Ctx: ctx, Expected: true},
+		{Expr: `!${process.suspicious}`, Ctx: other, Expected: true},
+		{Expr: `${process.suspicious} && open.filename == "/etc/shadow"`, Ctx: ctx, Expected: true},
+		{Expr: `${last_file} == open.filename`, Ctx: ctx, Expected: true},
+		{Expr: `${last_file} in ["/etc/passwd", "/etc/shadow"]`, Ctx: other, Expected: true},
+		{Expr: `${last_file} =~ "/etc/*"`, Ctx: other, Expected: true},
+		{Expr: `${last_uid} == process.uid`, Ctx: ctx, Expected: true},
+		{Expr: `${last_uid} != process.uid`, Ctx: other, Expected: true},
+	}
+
+	for _, test := range tests {
+		if result := evalWithVariables(t, store, test.Ctx, test.Expr); result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+
+	if err := store.Get("last_uid").Set(ctx, "1000"); err == nil {
+		t.Error("expected a type error")
+	}
+	if err := store.Get("process.opens").Set(ctx, 1); err == nil {
+		t.Error("expected a kind error")
+	}
+	if err := store.Get("last_uid").Increment(ctx); err == nil {
+		t.Error("expected a kind error")
+	}
+}
+
+func TestVariablesTimeWindow(t *testing.T) {
+	store := newTestVariableStore(t, 0)
+
+	start := time.Now()
+	event := &testEvent{process: testProcess{name: "cat"}}
+
+	// one increment per second
+	for i := 0; i != 12; i++ {
+		ctx := newTestVariableContext(event, start.Add(time.Duration(i)*time.Second))
+		if err := store.Get("process.failed_opens").Increment(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Get("process.opens").Increment(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := newTestVariableContext(event, start.Add(11*time.Second))
+	if err := store.Get("process.suspicious").Set(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Expr     string
+		Now      time.Duration
+		Expected bool
+	}{
+		// the ttl of the counter restricts the count to the last 10s
+		{Expr: `${process.failed_opens} == 10`, Now: 11 * time.Second, Expected: true},
+		{Expr: `${process.failed_opens} in the last 5s == 5`, Now: 11 * time.Second, Expected: true},
+		{Expr: `${process.failed_opens} in the last 1m == 10`, Now: 11 * time.Second, Expected: true},
+		{Expr: `${process.opens} == 12`, Now: 11 * time.Second, Expected: true},
+		{Expr: `${process.opens} in the last 3s == 3`, Now: 11 * time.Second, Expected: true},
+		{Expr: `${process.opens} in the last 3s == 0`, Now: time.Minute, Expected: true},
+		{Expr: `${process.failed_opens} == 0`, Now: time.Minute, Expected: true},
+		{Expr: `${process.suspicious} in the last 5s`, Now: 12 * time.Second, Expected: true},
+		{Expr: `${process.suspicious} in the last 5s`, Now: 20 * time.Second, Expected: false},
+		{Expr: `${process.suspicious}`, Now: 20 * time.Second, Expected: true},
+		// the ttl of the value is 1m
+		{Expr: `${process.suspicious}`, Now: 2 * time.Minute, Expected: false},
+	}
+
+	for _, test := range tests {
+		ctx := newTestVariableContext(event, start.Add(test.Now))
+		if result := evalWithVariables(t, store, ctx, test.Expr); result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t` at %s\n%s", test.Expected, result, test.Now, test.Expr)
+		}
+	}
+
+	// increments beyond the capacity of the ring saturate the windowed count
+	for i := 0; i != MaxCounterEvents+10; i++ {
+		if err := store.Get("process.opens").Increment(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if count := store.Get("process.opens").Get(ctx, time.Second); count != MaxCounterEvents {
+		t.Errorf("expected a saturated count of %d, got %v", MaxCounterEvents, count)
+	}
+	if count := store.Get("process.opens").Get(ctx, 0); count != MaxCounterEvents+22 {
+		t.Errorf("expected a total count of %d, got %v", MaxCounterEvents+22, count)
+	}
+
+	if _, err := parseRule(`${process.opens} in the last 0s > 1`, &testModel{}, &Opts{Variables: store}); err == nil {
+		t.Error("expected an invalid time window error")
+	}
+}
+
+func TestVariablesBoundedInstances(t *testing.T) {
+	store := newTestVariableStore(t, 2)
+	variable := store.Get("process.opens")
+
+	now := time.Now()
+	for _, name := range []string{"a", "b", "c"} {
+		ctx := newTestVariableContext(&testEvent{process: testProcess{name: name}}, now)
+		if err := variable.Increment(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if variable.Len() != 2 {
+		t.Fatalf("expected 2 instances, got %d", variable.Len())
+	}
+
+	// the least recently updated instance was evicted
+	if count := variable.Get(newTestVariableContext(&testEvent{process: testProcess{name: "a"}}, now), 0); count != 0 {
+		t.Errorf("expected the instance `a` to be evicted, got %v", count)
+	}
+
+	ctx := newTestVariableContext(&testEvent{process: testProcess{name: "c"}}, now)
+	store.ReleaseScope("process", ctx)
+	if variable.Len() != 1 {
+		t.Errorf("expected 1 instance after release, got %d", variable.Len())
+	}
+
+	// events without scope key are ignored
+	if err := variable.Increment(newTestVariableContext(&testEvent{}, now)); err != nil {
+		t.Fatal(err)
+	}
+	if variable.Len() != 1 {
+		t.Errorf("expected 1 instance, got %d", variable.Len())
+	}
+
+	// expired instances are purged on update
+	failedOpens := store.Get("process.failed_opens")
+	for _, name := range []string{"a", "b"} {
+		ctx := newTestVariableContext(&testEvent{process: testProcess{name: name}}, now)
+		if err := failedOpens.Increment(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := failedOpens.Increment(newTestVariableContext(&testEvent{process: testProcess{name: "b"}}, now.Add(time.Minute))); err != nil {
+		t.Fatal(err)
+	}
+	if failedOpens.Len() != 1 {
+		t.Errorf("expected the expired instance to be purged, got %d instances", failedOpens.Len())
+	}
+}
+
+func TestVariablesDeclare(t *testing.T) {
+	store := newTestVariableStore(t, 0)
+
+	if _, err := store.Declare("opens", "process", CounterVariable, nil, 0); err != nil {
+		t.Errorf("identical definitions shouldn't conflict: %s", err)
+	}
+
+	for _, def := range []struct {
+		name  string
+		scope string
+		kind  VariableKind
+		value interface{}
+		ttl   time.Duration
+	}{
+		{name: "opens", scope: "process", kind: CounterVariable, ttl: time.Second},
+		{name: "opens", scope: "process", kind: ValueVariable, value: 1},
+		{name: "last_file", kind: ValueVariable, value: true},
+		{name: "opens", scope: "container", kind: CounterVariable},
+		{name: "process.opens", kind: CounterVariable},
+		{name: "ratio", kind: ValueVariable, value: 1.5},
+		{name: "negative", kind: CounterVariable, ttl: -time.Second},
+	} {
+		if _, err := store.Declare(def.name, def.scope, def.kind, def.value, def.ttl); err == nil {
+			t.Errorf("expected an error for %+v", def)
+		}
+	}
+
+	if _, err := parseRule(`${process.unknown} > 1`, &testModel{}, &Opts{Variables: store}); err == nil {
+		t.Error("expected an unknown variable error")
+	}
+	if _, err := parseRule(`${last_file} > 1`, &testModel{}, &Opts{Variables: store}); err == nil {
+		t.Error("expected a type error")
+	}
+}
+
+func TestVariablesPartial(t *testing.T) {
+	store := newTestVariableStore(t, 0)
+
+	event := testEvent{
+		process: testProcess{name: "abc", uid: 123},
+		open:    testOpen{filename: "xyz"},
+	}
+
+	tests := []struct {
+		Expr        string
+		Field       Field
+		IsDiscarder bool
+	}{
+		{Expr: `open.filename == "test1" && ${process.failed_opens} > 5`, Field: "open.filename", IsDiscarder: true},
+		{Expr: `open.filename == "xyz" && ${process.failed_opens} > 5`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `open.filename == "test1" && !${process.suspicious}`, Field: "open.filename", IsDiscarder: true},
+		{Expr: `open.filename == "test1" || ${process.suspicious}`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `open.filename == "test1" || ${process.failed_opens} in the last 5s > 5`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `process.uid == 0 && ${last_file} == open.filename`, Field: "open.filename", IsDiscarder: false},
+		{Expr: `process.uid == 0 && ${last_file} == open.filename`, Field: "process.uid", IsDiscarder: true},
+	}
+
+	ctx := NewContext(unsafe.Pointer(&event))
+
+	for _, test := range tests {
+		rule, err := parseRule(test.Expr, &testModel{}, &Opts{Constants: testConstants, Variables: store})
+		if err != nil {
+			t.Fatalf("error while evaluating `%s`: %s", test.Expr, err)
+		}
+		if err := rule.GenPartials(); err != nil {
+			t.Fatalf("error while evaluating `%s`: %s", test.Expr, err)
+		}
+
+		result, err := rule.PartialEval(ctx, test.Field)
+		if err != nil {
+			t.Fatalf("error while partial evaluating `%s` for `%s`: %s", test.Expr, test.Field, err)
+		}
+
+		if !result != test.IsDiscarder {
+			t.Errorf("expected result `%t` for `%s`, got `%t`\n%s", test.IsDiscarder, test.Field, result, test.Expr)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/DataDog/ebpf"
+	"github.com/DataDog/ebpf/asm"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"gotest.tools/assert"
+)
+
+func TestBPFProgLoad(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_bpf_prog_load",
+			Expression: `bpf.cmd == BPF_PROG_LOAD && bpf.prog.type == BPF_PROG_TYPE_SOCKET_FILTER && bpf.prog.name == "test_prog"`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	err = test.GetSignal(t, func() error {
+		prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+			Name: "test_prog",
+			Type: ebpf.SocketFilter,
+			Instructions: asm.Instructions{
+				asm.LoadImm(asm.R0, 0, asm.DWord),
+				asm.Return(),
+			},
+			License: "Apache-2.0",
+		})
+		if err != nil {
+			return err
+		}
+		return prog.Close()
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_bpf_prog_load")
+		assert.Equal(t, "bpf", event.GetType(), "wrong event type")
+
+		if !validateBPFSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"gotest.tools/assert"
+)
+
+func TestMMap(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_mmap",
+			Expression: `mmap.protection & PROT_EXEC > 0 && mmap.flags & MAP_ANONYMOUS > 0 && process.file.name == "testsuite"`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	err = test.GetSignal(t, func() error {
+		data, err := unix.Mmap(-1, 0, 4096, unix.PROT_READ|unix.PROT_EXEC, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+		if err != nil {
+			return err
+		}
+		return unix.Munmap(data)
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_mmap")
+		assert.Equal(t, "mmap", event.GetType(), "wrong event type")
+		assert.Equal(t, uint32(4096), event.MMap.Len, "wrong length")
+		assert.Equal(t, uint64(0), event.MMap.File.Inode, "anonymous mapping shouldn't have a file")
+
+		if !validateMMapSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMProtect(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_mprotect",
+			Expression: `mprotect.req_protection & PROT_EXEC > 0 && mprotect.vm_protection & PROT_WRITE > 0 && process.file.name == "testsuite"`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	data, err := unix.Mmap(-1, 0, 4096, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Munmap(data)
+
+	err = test.GetSignal(t, func() error {
+		return unix.Mprotect(data, unix.PROT_READ|unix.PROT_EXEC)
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_mprotect")
+		assert.Equal(t, "mprotect", event.GetType(), "wrong event type")
+		assert.Equal(t, int64(0), event.MProtect.Retval, "wrong retval")
+
+		if !validateMMapSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"gotest.tools/assert"
+)
+
+func TestConnect(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_connect",
+			Expression: `connect.addr.ip == "127.0.0.1" && connect.addr.port == 4242 && connect.addr.family == AF_INET`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:4242")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	err = test.GetSignal(t, func() error {
+		conn, err := net.Dial("tcp", "127.0.0.1:4242")
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_connect")
+		assert.Equal(t, "connect", event.GetType(), "wrong event type")
+		assert.Equal(t, int64(0), event.Connect.Retval, "wrong retval")
+
+		if !validateNetworkSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBind(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_bind",
+			Expression: `bind.addr.family == AF_INET6 && bind.addr.port == 4243`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	err = test.GetSignal(t, func() error {
+		listener, err := net.Listen("tcp6", "[::1]:4243")
+		if err != nil {
+			return err
+		}
+		return listener.Close()
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_bind")
+		assert.Equal(t, "bind", event.GetType(), "wrong event type")
+		assertFieldEqual(t, event, "bind.addr.ip", "::1", "wrong address")
+
+		if !validateNetworkSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDNS(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_dns",
+			Expression: `dns.question.name == "www.datadoghq.com" && dns.question.type == A`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	// A record question for www.datadoghq.com
+	request := make([]byte, 12)
+	binary.BigEndian.PutUint16(request[0:2], 0xcafe)
+	binary.BigEndian.PutUint16(request[4:6], 1)
+	for _, label := range []string{"www", "datadoghq", "com"} {
+		request = append(request, byte(len(label)))
+		request = append(request, label...)
+	}
+	request = append(request, 0, 0, 1, 0, 1)
+
+	err = test.GetSignal(t, func() error {
+		conn, err := net.Dial("udp", "127.0.0.1:53")
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		// no resolver needs to be listening, the request is caught when sent
+		_, err = conn.Write(request)
+		return err
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_dns")
+		assert.Equal(t, "dns", event.GetType(), "wrong event type")
+		assertFieldEqual(t, event, "dns.id", 0xcafe, "wrong request id")
+		assertFieldEqual(t, event, "dns.question.class", 1, "wrong question class")
+
+		if !validateDNSSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/rules"
+	"gotest.tools/assert"
+)
+
+func TestPTrace(t *testing.T) {
+	ruleset := []*rules.RuleDefinition{
+		{
+			ID:         "test_ptrace",
+			Expression: `ptrace.request == PTRACE_ATTACH && process.file.name == "testsuite"`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleset, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Process.Kill()
+
+	// ptrace requests have to be issued by the tracer thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	err = test.GetSignal(t, func() error {
+		if err := syscall.PtraceAttach(cmd.Process.Pid); err != nil {
+			return err
+		}
+		var status syscall.WaitStatus
+		if _, err := syscall.Wait4(cmd.Process.Pid, &status, 0, nil); err != nil {
+			return err
+		}
+		return syscall.PtraceDetach(cmd.Process.Pid)
+	}, func(event *probe.Event, rule *rules.Rule) {
+		assertTriggeredRule(t, rule, "test_ptrace")
+		assert.Equal(t, "ptrace", event.GetType(), "wrong event type")
+		assert.Equal(t, uint32(cmd.Process.Pid), event.PTrace.PID, "wrong tracee pid")
+		assert.Equal(t, int64(0), event.PTrace.Retval, "wrong retval")
+
+		if !validatePTraceSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -100,3 +100,23 @@ func validateChownSchema(t *testing.T, event *sprobe.Event) bool {
 func validateSELinuxSchema(t *testing.T, event *sprobe.Event) bool {
 	return validateSchema(t, event, "file:///selinux.schema.json")
 }
+
+func validateNetworkSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///network.schema.json")
+}
+
+func validateDNSSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///dns.schema.json")
+}
+
+func validatePTraceSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///ptrace.schema.json")
+}
+
+func validateMMapSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///mmap.schema.json")
+}
+
+func validateBPFSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///bpf.schema.json")
+}
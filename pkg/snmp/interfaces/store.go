@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package interfaces
+
+import "sync"
+
+// DefaultStore is the store filled by the SNMP check with the interfaces of the devices it monitors
+var DefaultStore = NewStore()
+
+// Store holds the names of the interfaces of network devices, by device IP address and interface index.
+// It is safe for concurrent use.
+type Store struct {
+	mu    sync.RWMutex
+	names map[string]map[int32]string
+}
+
+// NewStore returns a new empty Store
+func NewStore() *Store {
+	return &Store{
+		names: make(map[string]map[int32]string),
+	}
+}
+
+// SetDeviceInterfaces replaces the interface names of a device, by interface index
+func (s *Store) SetDeviceInterfaces(deviceIP string, names map[int32]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names[deviceIP] = names
+}
+
+// GetInterfaceName returns the name of an interface of a device, if known
+func (s *Store) GetInterfaceName(deviceIP string, index int32) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	name, ok := s.names[deviceIP][index]
+	return name, ok
+}
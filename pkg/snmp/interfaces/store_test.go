@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package interfaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+	store.SetDeviceInterfaces("10.0.0.1", map[int32]string{1: "eth0", 2: "eth1"})
+
+	name, ok := store.GetInterfaceName("10.0.0.1", 2)
+	assert.True(t, ok)
+	assert.Equal(t, "eth1", name)
+
+	_, ok = store.GetInterfaceName("10.0.0.1", 3)
+	assert.False(t, ok)
+	_, ok = store.GetInterfaceName("10.0.0.2", 1)
+	assert.False(t, ok)
+
+	// interfaces are replaced, not merged
+	store.SetDeviceInterfaces("10.0.0.1", map[int32]string{3: "eth2"})
+	_, ok = store.GetInterfaceName("10.0.0.1", 1)
+	assert.False(t, ok)
+	name, _ = store.GetInterfaceName("10.0.0.1", 3)
+	assert.Equal(t, "eth2", name)
+}
@@ -135,6 +135,33 @@ type ContainerIOStats struct {
 	OpenFiles uint64
 }
 
+// PressureLine stores one line of pressure stall information (PSI), that is the share
+// of time some or all the tasks were stalled on a resource
+type PressureLine struct {
+	// Percentage of time stalled over the last 10, 60 and 300 seconds
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+
+	// Total stall time in microseconds
+	Total uint64
+}
+
+// PressureStats stores the pressure stall information of a resource.
+// Full is nil when not reported, as for the cpu resource before Linux 5.13.
+type PressureStats struct {
+	Some *PressureLine
+	Full *PressureLine
+}
+
+// ContainerPressureStats stores the pressure stall information of a cgroup,
+// which is only available with cgroup v2
+type ContainerPressureStats struct {
+	CPU    *PressureStats
+	Memory *PressureStats
+	IO     *PressureStats
+}
+
 // ContainerMetrics wraps all container metrics
 type ContainerMetrics struct {
 	CPU    *ContainerCPUStats
@@ -33,10 +33,46 @@ var (
 	dindCgroupRe = regexp.MustCompile("^\\/docker\\/[0-9a-f]{64}(\\/docker\\/[0-9a-f]{64})")
 )
 
+// unifiedHierarchy is the target used in mounts and paths for the cgroup v2 hierarchy, which has
+// no controller name in /proc/$pid/cgroup (0::/path) and is mounted with the cgroup2 filesystem type
+const unifiedHierarchy = "unified"
+
+// cgroupMode describes how the cgroup hierarchies are set up on the host
+type cgroupMode int
+
+const (
+	// legacyMode means only cgroup v1 hierarchies are mounted
+	legacyMode cgroupMode = iota
+	// hybridMode means cgroup v1 hierarchies hold the controllers, and the unified hierarchy
+	// is mounted alongside them (usually on /sys/fs/cgroup/unified) without controllers
+	hybridMode
+	// unifiedMode means the unified hierarchy is the only one mounted and holds all the controllers
+	unifiedMode
+)
+
+// detectCgroupMode returns the cgroup mode given the mount points of the hierarchies. In hybrid
+// mode, the controllers we read are bound to the v1 hierarchies so metrics are read from them.
+func detectCgroupMode(mountPoints map[string]string) cgroupMode {
+	_, hasUnified := mountPoints[unifiedHierarchy]
+	_, hasMemory := mountPoints["memory"]
+	switch {
+	case hasUnified && hasMemory:
+		return hybridMode
+	case hasUnified:
+		return unifiedMode
+	default:
+		return legacyMode
+	}
+}
+
 // ContainerStartTime gets the stat for cgroup directory and use the mtime for that dir to determine the start time for the container
 // this should work because the cgroup dir for the container would be created only when it's started
 func (c ContainerCgroup) ContainerStartTime() (int64, error) {
-	cgroupDir := c.cgroupFilePath("cpuacct", "")
+	target := "cpuacct"
+	if c.isUnified() {
+		target = unifiedHierarchy
+	}
+	cgroupDir := c.cgroupFilePath(target, "")
 	if !pathExists(cgroupDir) {
 		return 0, fmt.Errorf("could not get cgroup dir, directory doesn't exist")
 	}
@@ -96,6 +132,9 @@ func (c ContainerCgroup) cgroupParentFilePath(target, file string) string {
 //	 cgroup /sys/fs/cgroup/perf_event cgroup rw,relatime,perf_event 0 0
 //	 cgroup /sys/fs/cgroup/hugetlb cgroup rw,relatime,hugetlb 0 0
 //
+// The unified hierarchy, if mounted, is returned as the unified target:
+//	 cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime,nsdelegate 0 0
+//
 // Returns a map for every target (cpuset, cpu, cpuacct) => path
 func cgroupMountPoints() (map[string]string, error) {
 	mountsFile := "/proc/mounts"
@@ -117,6 +156,15 @@ func parseCgroupMountPoints(r io.Reader) map[string]string {
 	for scanner.Scan() {
 		mount := scanner.Text()
 		tokens := strings.Split(mount, " ")
+		// The unified hierarchy can be mounted on the cgroup root itself
+		if len(tokens) >= 3 && tokens[2] == "cgroup2" {
+			cgroupPath := tokens[1]
+			if !strings.HasPrefix(cgroupPath+"/", cgroupRoot) {
+				continue
+			}
+			mountPoints[unifiedHierarchy] = cgroupPath
+			continue
+		}
 		// Check if the filesystem type is 'cgroup'
 		if len(tokens) >= 3 && tokens[2] == "cgroup" {
 			cgroupPath := tokens[1]
@@ -162,6 +210,7 @@ func scrapeAllCgroups() (map[string]*ContainerCgroup, error) {
 	}
 
 	prefix := config.Datadog.GetString("container_cgroup_prefix")
+	unified := detectCgroupMode(mountPoints) == unifiedMode
 
 	for _, dirName := range dirNames {
 		pid, err := strconv.ParseInt(dirName, 10, 32)
@@ -180,11 +229,21 @@ func scrapeAllCgroups() (map[string]*ContainerCgroup, error) {
 			continue
 		}
 
-		mP, mFound := paths["memory"]
-		fP, fFound := paths["freezer"]
-		if !fFound || !mFound || mP != fP {
-			log.Tracef("skipping cgroup from pid: %d - does not appear to be a container: memory path: %s, freezer path: %s", pid, mP, fP)
-			continue
+		if unified {
+			// There is no freezer cgroup to rely on, but conmon gets its own scope
+			// named after the container, i.e. crio-conmon-$containerID.scope
+			uP, uFound := paths[unifiedHierarchy]
+			if !uFound || strings.Contains(path.Base(uP), "conmon") {
+				log.Tracef("skipping cgroup from pid: %d - does not appear to be a container: unified path: %s", pid, uP)
+				continue
+			}
+		} else {
+			mP, mFound := paths["memory"]
+			fP, fFound := paths["freezer"]
+			if !fFound || !mFound || mP != fP {
+				log.Tracef("skipping cgroup from pid: %d - does not appear to be a container: memory path: %s, freezer path: %s", pid, mP, fP)
+				continue
+			}
 		}
 
 		if err != nil {
@@ -250,6 +309,10 @@ func parseCgroupPaths(r io.Reader, prefix string) (string, map[string]string, er
 		}
 		// Target can be comma-separate values like cpu,cpuacct
 		tsp := strings.Split(sp[1], ",")
+		// The unified hierarchy has no controller list: 0::/system.slice/docker-$containerID.scope
+		if sp[0] == "0" && sp[1] == "" {
+			tsp = []string{unifiedHierarchy}
+		}
 		for _, target := range tsp {
 			if len(sp[2]) > 1 && sp[2] != "/docker" { // if the path is only one character it's the root cgroup
 				paths[target] = sp[2]
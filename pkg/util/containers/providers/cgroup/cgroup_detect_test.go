@@ -112,6 +112,33 @@ func TestParseCgroupMountPoints(t *testing.T) {
 				"systemd":    "/sys/fs/cgroup/systemd",
 			},
 		},
+		{
+			// unified hierarchy only
+			contents: []string{
+				"sysfs /sys sysfs rw,nosuid,nodev,noexec,relatime 0 0",
+				"cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime,nsdelegate 0 0",
+			},
+			expected: map[string]string{
+				"unified": "/sys/fs/cgroup",
+			},
+		},
+		{
+			// hybrid mode
+			contents: []string{
+				"tmpfs /sys/fs/cgroup tmpfs ro,nosuid,nodev,noexec,mode=755 0 0",
+				"cgroup2 /sys/fs/cgroup/unified cgroup2 rw,nosuid,nodev,noexec,relatime,nsdelegate 0 0",
+				"cgroup /sys/fs/cgroup/systemd cgroup rw,nosuid,nodev,noexec,relatime,xattr,name=systemd 0 0",
+				"cgroup /sys/fs/cgroup/memory cgroup rw,nosuid,nodev,noexec,relatime,memory 0 0",
+				"cgroup /sys/fs/cgroup/cpu,cpuacct cgroup rw,nosuid,nodev,noexec,relatime,cpu,cpuacct 0 0",
+			},
+			expected: map[string]string{
+				"unified": "/sys/fs/cgroup/unified",
+				"systemd": "/sys/fs/cgroup/systemd",
+				"memory":  "/sys/fs/cgroup/memory",
+				"cpu":     "/sys/fs/cgroup/cpu,cpuacct",
+				"cpuacct": "/sys/fs/cgroup/cpu,cpuacct",
+			},
+		},
 		{
 			contents: []string{
 				"",
@@ -306,6 +333,30 @@ func TestParseCgroupPaths(t *testing.T) {
 				"name=systemd": "/system.slice/ecs-agent.service/1236529c30c0bf2faf2c5c63c0af2afd134118b91348f321c996734e15b7a8f9",
 			},
 		},
+		{
+			// unified hierarchy
+			contents: []string{
+				"0::/system.slice/docker-a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419.scope",
+			},
+			expectedContainer: "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+			expectedPaths: map[string]string{
+				"unified": "/system.slice/docker-a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419.scope",
+			},
+		},
+		{
+			// hybrid mode
+			contents: []string{
+				"4:memory:/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+				"1:name=systemd:/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+				"0::/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+			},
+			expectedContainer: "a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+			expectedPaths: map[string]string{
+				"memory":       "/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+				"name=systemd": "/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+				"unified":      "/docker/a27f1331f6ddf72629811aac65207949fc858ea90100c438768b531a4c540419",
+			},
+		},
 	} {
 		contents := strings.NewReader(strings.Join(tc.contents, "\n"))
 		c, p, err := parseCgroupPaths(contents, "")
@@ -315,6 +366,22 @@ func TestParseCgroupPaths(t *testing.T) {
 	}
 }
 
+func TestDetectCgroupMode(t *testing.T) {
+	assert.Equal(t, legacyMode, detectCgroupMode(map[string]string{
+		"memory":  "/sys/fs/cgroup/memory",
+		"systemd": "/sys/fs/cgroup/systemd",
+	}))
+	assert.Equal(t, hybridMode, detectCgroupMode(map[string]string{
+		"memory":  "/sys/fs/cgroup/memory",
+		"systemd": "/sys/fs/cgroup/systemd",
+		"unified": "/sys/fs/cgroup/unified",
+	}))
+	assert.Equal(t, unifiedMode, detectCgroupMode(map[string]string{
+		"unified": "/sys/fs/cgroup",
+	}))
+	assert.Equal(t, legacyMode, detectCgroupMode(map[string]string{}))
+}
+
 func TestContainerIDFromCgroup(t *testing.T) {
 	for _, tc := range []struct {
 		path       string
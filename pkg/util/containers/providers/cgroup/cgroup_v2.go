@@ -0,0 +1,448 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package cgroup
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/system"
+)
+
+// MicroToUserHZDivisor holds the divisor to convert the usec values of cgroup v2
+// to the same unit as cgroup v1 cpuacct.stat (USER_HZ = 1/100)
+const MicroToUserHZDivisor float64 = 1e6 / 100
+
+// isUnified returns whether the cgroup controllers are read from the unified hierarchy (cgroup v2)
+func (c ContainerCgroup) isUnified() bool {
+	return detectCgroupMode(c.Mounts) == unifiedMode
+}
+
+// unifiedMetrics returns the CPU, IO and Memory metrics of a cgroup v2
+func (c ContainerCgroup) unifiedMetrics() (*metrics.ContainerMetrics, error) {
+	var metrics metrics.ContainerMetrics
+	var err error
+
+	metrics.Memory, err = c.memV2()
+	if err != nil {
+		return nil, fmt.Errorf("memory: %s", err)
+	}
+	metrics.CPU, err = c.cpuV2()
+	if err != nil {
+		return nil, fmt.Errorf("cpu: %s", err)
+	}
+	metrics.IO, err = c.ioV2()
+	if err != nil {
+		return nil, fmt.Errorf("i/o: %s", err)
+	}
+
+	return &metrics, nil
+}
+
+// unifiedLimits returns the CPU, Thread and Memory limits of a cgroup v2
+func (c ContainerCgroup) unifiedLimits() (*metrics.ContainerLimits, error) {
+	var limits metrics.ContainerLimits
+	var err error
+
+	limits.CPULimit, err = c.cpuLimitV2()
+	if err != nil {
+		return nil, fmt.Errorf("cpu limit: %s", err)
+	}
+	limits.MemLimit, err = c.parseMaxStatV2("memory.max")
+	if err != nil {
+		return nil, fmt.Errorf("mem limit: %s", err)
+	}
+	limits.ThreadLimit, err = c.parseMaxStatV2("pids.max")
+	if err != nil {
+		return nil, fmt.Errorf("thread limit: %s", err)
+	}
+
+	return &limits, nil
+}
+
+// memV2 returns the memory statistics of a cgroup v2, read from memory.current, memory.stat,
+// memory.events, memory.swap.current, memory.max and memory.low. Contrary to cgroup v1,
+// memory.stat values are always hierarchical, they are reported as totals as well.
+func (c ContainerCgroup) memV2() (*metrics.ContainerMemStats, error) {
+	ret := &metrics.ContainerMemStats{}
+
+	var kernelStack, slab uint64
+	var hasKernel bool
+	err := c.scanStatFile(unifiedHierarchy, "memory.stat", func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil
+		}
+		switch fields[0] {
+		case "anon":
+			ret.RSS = v
+			ret.TotalRSS = v
+		case "file":
+			ret.Cache = v
+			ret.TotalCache = v
+		case "anon_thp":
+			ret.RSSHuge = v
+			ret.TotalRSSHuge = v
+		case "file_mapped":
+			ret.MappedFile = v
+			ret.TotalMappedFile = v
+		case "pgfault":
+			ret.Pgfault = v
+			ret.TotalPgFault = v
+		case "pgmajfault":
+			ret.Pgmajfault = v
+			ret.TotalPgMajFault = v
+		case "inactive_anon":
+			ret.InactiveAnon = v
+			ret.TotalInactiveAnon = v
+		case "active_anon":
+			ret.ActiveAnon = v
+			ret.TotalActiveAnon = v
+		case "inactive_file":
+			ret.InactiveFile = v
+			ret.TotalInactiveFile = v
+		case "active_file":
+			ret.ActiveFile = v
+			ret.TotalActiveFile = v
+		case "unevictable":
+			ret.Unevictable = v
+			ret.TotalUnevictable = v
+		case "kernel":
+			// only available on kernels 5.18+
+			ret.KernMemUsage = v
+			hasKernel = true
+		case "kernel_stack":
+			kernelStack = v
+		case "slab":
+			slab = v
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hasKernel {
+		ret.KernMemUsage = kernelStack + slab
+	}
+
+	// the max event counts the times the cgroup usage was about to go over memory.max
+	err = c.scanStatFile(unifiedHierarchy, "memory.events", func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "max" {
+			ret.MemFailCnt, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret.MemUsageInBytes, err = c.parseStatV2("memory.current")
+	if err != nil {
+		return nil, err
+	}
+
+	swap, err := c.ParseSingleStat(unifiedHierarchy, "memory.swap.current")
+	if err == nil {
+		ret.Swap = swap
+		ret.SwapPresent = true
+	} else if os.IsNotExist(err) {
+		log.Debugf("Missing cgroup file: %s", c.cgroupFilePath(unifiedHierarchy, "memory.swap.current"))
+	} else {
+		return nil, err
+	}
+
+	ret.HierarchicalMemoryLimit, err = c.parseMaxStatV2("memory.max")
+	if err != nil {
+		return nil, err
+	}
+
+	// docker --memory-reservation sets memory.low on cgroup v2
+	ret.SoftMemLimit, err = c.parseMaxStatV2("memory.low")
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// cpuV2 returns the CPU statistics of a cgroup v2, read from cpu.stat and cpu.weight.
+// Format of cpu.stat:
+//
+// usage_usec 1520000
+// user_usec 1190000
+// system_usec 330000
+// nr_periods 40
+// nr_throttled 3
+// throttled_usec 254000
+//
+func (c ContainerCgroup) cpuV2() (*metrics.ContainerCPUStats, error) {
+	ret := &metrics.ContainerCPUStats{
+		User:       -1,
+		System:     -1,
+		UsageTotal: -1,
+		Shares:     -1,
+	}
+
+	err := c.scanStatFile(unifiedHierarchy, "cpu.stat", func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil
+		}
+		switch fields[0] {
+		case "usage_usec":
+			ret.UsageTotal = float64(v) / MicroToUserHZDivisor
+		case "user_usec":
+			ret.User = float64(v) / MicroToUserHZDivisor
+		case "system_usec":
+			ret.System = float64(v) / MicroToUserHZDivisor
+		case "nr_throttled":
+			ret.NrThrottled = v
+		case "throttled_usec":
+			ret.ThrottledTime = float64(v) / MicroToUserHZDivisor
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret.Timestsamp = time.Now()
+
+	weight, err := c.ParseSingleStat(unifiedHierarchy, "cpu.weight")
+	if err == nil {
+		ret.Shares = cpuWeightToShares(weight)
+	} else {
+		log.Debugf("Missing cpu weight stat for %s: %s", c.ContainerID, err.Error())
+	}
+
+	ret.ThreadCount, err = c.parseStatV2("pids.current")
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// cpuWeightToShares converts a cpu.weight value, in [1, 10000], to cpu.shares, in [2, 262144],
+// which is the reverse of the conversion done by container runtimes on cgroup v2
+func cpuWeightToShares(weight uint64) float64 {
+	if weight == 0 {
+		return 0
+	}
+	return float64(2 + ((weight-1)*262142)/9999)
+}
+
+// cpuLimitV2 returns the CPU limit of a cgroup v2, as a percentage of one CPU. It uses
+// cpu.max, which holds the quota and the period, and cpuset.cpus, taking the minimum of both
+// like for cgroup v1. If the cgroup has no quota, the quota of the parent cgroup is used.
+//
+// Format of cpu.max:
+//
+// max 100000
+// 50000 100000
+//
+func (c ContainerCgroup) cpuLimitV2() (float64, error) {
+	defaultLimit := float64(system.HostCPUCount()) * 100.0
+	limitFromCPUSet := float64(-1)
+	limitFromQuota := float64(-1)
+
+	cpusetFile := c.cgroupFilePath(unifiedHierarchy, "cpuset.cpus")
+	cpuLines, err := readLines(cpusetFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return 0, err
+		}
+		log.Debugf("Missing cgroup file: %s", cpusetFile)
+	} else if len(cpuLines) > 0 && cpuLines[0] != "" {
+		// cpuset.cpus is empty when no cpuset is set
+		numCPUs := parseCPUSetFile(cpuLines)
+		if numCPUs > 0 {
+			limitFromCPUSet = float64(numCPUs) * 100.0
+		}
+	}
+
+	quota, period, err := parseCPUMax(c.cgroupFilePath(unifiedHierarchy, "cpu.max"))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return 0, err
+		}
+		log.Debugf("Missing cgroup file: %s", c.cgroupFilePath(unifiedHierarchy, "cpu.max"))
+	}
+
+	// We ignore failures as we already have current cgroup values
+	if quota == -1 {
+		if parentQuota, parentPeriod, err := parseCPUMax(c.cgroupParentFilePath(unifiedHierarchy, "cpu.max")); err == nil {
+			quota, period = parentQuota, parentPeriod
+		}
+	}
+
+	if (period > 0) && (quota > 0) {
+		limitFromQuota = quota / period * 100.0
+	}
+
+	if limitFromCPUSet == -1 && limitFromQuota == -1 {
+		return defaultLimit, nil
+	}
+	if limitFromCPUSet == -1 {
+		return limitFromQuota, nil
+	}
+	if limitFromQuota == -1 {
+		return limitFromCPUSet, nil
+	}
+	return math.Min(limitFromQuota, limitFromCPUSet), nil
+}
+
+// parseCPUMax returns the quota and period from a cpu.max file, quota being -1 if there is no limit
+func parseCPUMax(filename string) (quota float64, period float64, err error) {
+	lines, err := readLines(filename)
+	if err != nil {
+		return -1, 0, err
+	}
+	if len(lines) != 1 {
+		return -1, 0, fmt.Errorf("wrong file format: %s", filename)
+	}
+	fields := strings.Fields(lines[0])
+	if len(fields) != 2 {
+		return -1, 0, fmt.Errorf("wrong file format: %s", filename)
+	}
+	period, err = strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return -1, 0, err
+	}
+	if fields[0] == "max" {
+		return -1, period, nil
+	}
+	quota, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return -1, 0, err
+	}
+	return quota, period, nil
+}
+
+// ioV2 returns the disk read and write stats of a cgroup v2, read from io.stat.
+// Format:
+//
+// 8:0 rbytes=49225728 wbytes=9850880 rios=1204 wios=532 dbytes=0 dios=0
+// 252:0 rbytes=49094656 wbytes=9850880 rios=1190 wios=532 dbytes=0 dios=0
+//
+func (c ContainerCgroup) ioV2() (*metrics.ContainerIOStats, error) {
+	ret := &metrics.ContainerIOStats{
+		DeviceReadBytes:       make(map[string]uint64),
+		DeviceWriteBytes:      make(map[string]uint64),
+		DeviceReadOperations:  make(map[string]uint64),
+		DeviceWriteOperations: make(map[string]uint64),
+	}
+
+	// Get device id->name mapping
+	var devices map[string]string
+	mapping, err := getDiskDeviceMapping()
+	if err != nil {
+		log.Debugf("Cannot get per-device stats: %s", err)
+		// devices will stay nil, lookups are safe in nil maps
+	} else {
+		devices = mapping.idToName
+	}
+
+	err = c.scanStatFile(unifiedHierarchy, "io.stat", func(line string) error {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil
+		}
+		deviceName := devices[fields[0]]
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			var total *uint64
+			var device map[string]uint64
+			switch kv[0] {
+			case "rbytes":
+				total, device = &ret.ReadBytes, ret.DeviceReadBytes
+			case "wbytes":
+				total, device = &ret.WriteBytes, ret.DeviceWriteBytes
+			case "rios":
+				total, device = &ret.ReadOperations, ret.DeviceReadOperations
+			case "wios":
+				total, device = &ret.WriteOperations, ret.DeviceWriteOperations
+			default:
+				continue
+			}
+			*total += v
+			if deviceName != "" {
+				device[deviceName] = v
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var fileDescCount uint64
+	for _, pid := range c.Pids {
+		fdCount, err := GetFileDescriptorLen(int(pid))
+		if err != nil {
+			log.Debugf("Failed to get file desc length for pid %d, container %s: %s", pid, c.ContainerID[:12], err)
+			continue
+		}
+		fileDescCount += uint64(fdCount)
+	}
+	ret.OpenFiles = fileDescCount
+
+	return ret, nil
+}
+
+// parseStatV2 reads a single-value stat file of the unified hierarchy, defaulting to 0
+// if the file does not exist
+func (c ContainerCgroup) parseStatV2(file string) (uint64, error) {
+	v, err := c.ParseSingleStat(unifiedHierarchy, file)
+	if os.IsNotExist(err) {
+		log.Debugf("Missing cgroup file: %s", c.cgroupFilePath(unifiedHierarchy, file))
+		return 0, nil
+	}
+	return v, err
+}
+
+// parseMaxStatV2 reads a single-value stat file of the unified hierarchy that can be set to `max`,
+// like memory.max or pids.max. If `max` is found or the file does not exist, it returns 0 as-in "no limit"
+func (c ContainerCgroup) parseMaxStatV2(file string) (uint64, error) {
+	statFile := c.cgroupFilePath(unifiedHierarchy, file)
+	lines, err := readLines(statFile)
+	if os.IsNotExist(err) {
+		log.Debugf("Missing cgroup file: %s", statFile)
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(lines) != 1 {
+		return 0, fmt.Errorf("wrong file format: %s", statFile)
+	}
+	if lines[0] == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(lines[0], 10, 64)
+}
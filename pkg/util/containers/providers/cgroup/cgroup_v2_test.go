@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package cgroup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/cache"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/system"
+)
+
+const testUnifiedContainerID = "3e1a2b1f6f0e05b1c2a5d8a0f6b62f3e7e9a7c8d9f0a1b2c3d4e5f60718293a4"
+
+// newUnifiedContainerCgroup returns a cgroup of the fake unified hierarchy in testdata/cgroupv2
+func newUnifiedContainerCgroup(cgroupPath string) *ContainerCgroup {
+	return &ContainerCgroup{
+		ContainerID: testUnifiedContainerID,
+		Mounts:      map[string]string{unifiedHierarchy: "./testdata/cgroupv2/sys/fs/cgroup"},
+		Paths:       map[string]string{unifiedHierarchy: cgroupPath},
+	}
+}
+
+func TestUnifiedMetrics(t *testing.T) {
+	cache.Cache.Set(diskMappingCacheKey, &diskDeviceMapping{
+		idToName: map[string]string{"8:0": "sda"},
+	}, time.Minute)
+	defer cache.Cache.Delete(diskMappingCacheKey)
+
+	cgroup := newUnifiedContainerCgroup("/system.slice/docker-" + testUnifiedContainerID + ".scope")
+	require.True(t, cgroup.isUnified())
+
+	containerMetrics, err := cgroup.unifiedMetrics()
+	require.NoError(t, err)
+
+	mem := containerMetrics.Memory
+	assert.Equal(t, uint64(8716288), mem.RSS)
+	assert.Equal(t, uint64(8716288), mem.TotalRSS)
+	assert.Equal(t, uint64(10813440), mem.Cache)
+	assert.Equal(t, uint64(2097152), mem.RSSHuge)
+	assert.Equal(t, uint64(5271552), mem.MappedFile)
+	assert.Equal(t, uint64(9834), mem.Pgfault)
+	assert.Equal(t, uint64(66), mem.Pgmajfault)
+	assert.Equal(t, uint64(98304+890880), mem.KernMemUsage)
+	assert.Equal(t, uint64(12), mem.MemFailCnt)
+	assert.Equal(t, uint64(20971520), mem.MemUsageInBytes)
+	assert.True(t, mem.SwapPresent)
+	assert.Equal(t, uint64(0), mem.Swap)
+	assert.Equal(t, uint64(536870912), mem.HierarchicalMemoryLimit)
+	assert.Equal(t, uint64(268435456), mem.SoftMemLimit)
+
+	cpu := containerMetrics.CPU
+	assert.Equal(t, float64(152), cpu.UsageTotal)
+	assert.Equal(t, float64(119), cpu.User)
+	assert.Equal(t, float64(33), cpu.System)
+	assert.Equal(t, uint64(3), cpu.NrThrottled)
+	assert.Equal(t, 25.4, cpu.ThrottledTime)
+	assert.Equal(t, float64(2597), cpu.Shares)
+	assert.Equal(t, uint64(12), cpu.ThreadCount)
+
+	assert.Equal(t, &metrics.ContainerIOStats{
+		ReadBytes:             49225728 + 1048576,
+		WriteBytes:            9850880,
+		ReadOperations:        1204 + 16,
+		WriteOperations:       532,
+		DeviceReadBytes:       map[string]uint64{"sda": 49225728},
+		DeviceWriteBytes:      map[string]uint64{"sda": 9850880},
+		DeviceReadOperations:  map[string]uint64{"sda": 1204},
+		DeviceWriteOperations: map[string]uint64{"sda": 532},
+	}, containerMetrics.IO)
+}
+
+func TestUnifiedLimits(t *testing.T) {
+	cgroup := newUnifiedContainerCgroup("/system.slice/docker-" + testUnifiedContainerID + ".scope")
+
+	limits, err := cgroup.unifiedLimits()
+	require.NoError(t, err)
+
+	// no quota in the container cgroup, the quota of the parent is 200%, the cpuset 400%
+	assert.Equal(t, float64(200), limits.CPULimit)
+	assert.Equal(t, uint64(536870912), limits.MemLimit)
+	assert.Equal(t, uint64(1024), limits.ThreadLimit)
+}
+
+func TestUnifiedMissingFiles(t *testing.T) {
+	cgroup := newUnifiedContainerCgroup("/missing.slice/missing.scope")
+
+	containerMetrics, err := cgroup.unifiedMetrics()
+	require.NoError(t, err)
+	assert.Equal(t, &metrics.ContainerMemStats{}, containerMetrics.Memory)
+	assert.Equal(t, float64(-1), containerMetrics.CPU.UsageTotal)
+	assert.Equal(t, float64(-1), containerMetrics.CPU.Shares)
+
+	limits, err := cgroup.unifiedLimits()
+	require.NoError(t, err)
+	assert.Equal(t, float64(system.HostCPUCount())*100.0, limits.CPULimit)
+	assert.Equal(t, uint64(0), limits.MemLimit)
+	assert.Equal(t, uint64(0), limits.ThreadLimit)
+}
+
+func TestParseCPUMax(t *testing.T) {
+	tempFolder, err := newTempFolder("cpu-max")
+	assert.Nil(t, err)
+	defer tempFolder.removeAll()
+
+	tempFolder.add("limited/cpu.max", "50000 100000")
+	quota, period, err := parseCPUMax(tempFolder.RootPath + "/limited/cpu.max")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(50000), quota)
+	assert.Equal(t, float64(100000), period)
+
+	tempFolder.add("unlimited/cpu.max", "max 100000")
+	quota, period, err = parseCPUMax(tempFolder.RootPath + "/unlimited/cpu.max")
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-1), quota)
+	assert.Equal(t, float64(100000), period)
+
+	tempFolder.add("invalid/cpu.max", "max")
+	_, _, err = parseCPUMax(tempFolder.RootPath + "/invalid/cpu.max")
+	assert.Error(t, err)
+}
+
+func TestCPUWeightToShares(t *testing.T) {
+	assert.Equal(t, float64(2), cpuWeightToShares(1))
+	// 1024 shares are converted to a weight of 39 by runtimes, the conversion is lossy
+	assert.Equal(t, float64(998), cpuWeightToShares(39))
+	assert.Equal(t, float64(262144), cpuWeightToShares(10000))
+}
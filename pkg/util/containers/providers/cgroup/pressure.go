@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ErrPressureNotAvailable is returned when the pressure stall information of
+// containers is requested on hosts not running the cgroup v2 unified hierarchy
+var ErrPressureNotAvailable = errors.New("pressure stall information is only available with cgroup v2")
+
+// ParsePressureFile parses a pressure stall information file, as found in /proc/pressure
+// or in the cgroup v2 hierarchy. It returns nil if the file does not exist, which is
+// the case if the kernel is not built with, or started without, PSI support.
+// Format:
+//
+// some avg10=0.12 avg60=0.05 avg300=0.01 total=1043752
+// full avg10=0.00 avg60=0.00 avg300=0.00 total=251037
+//
+func ParsePressureFile(filename string) (*metrics.PressureStats, error) {
+	lines, err := readLines(filename)
+	if os.IsNotExist(err) {
+		log.Debugf("Missing pressure file: %s", filename)
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	stats := &metrics.PressureStats{}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pressureLine, err := parsePressureLine(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("wrong file format: %s: %s", filename, err)
+		}
+
+		switch fields[0] {
+		case "some":
+			stats.Some = pressureLine
+		case "full":
+			stats.Full = pressureLine
+		}
+	}
+	return stats, nil
+}
+
+func parsePressureLine(fields []string) (*metrics.PressureLine, error) {
+	line := &metrics.PressureLine{}
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid field `%s`", field)
+		}
+
+		var err error
+		switch kv[0] {
+		case "avg10":
+			line.Avg10, err = strconv.ParseFloat(kv[1], 64)
+		case "avg60":
+			line.Avg60, err = strconv.ParseFloat(kv[1], 64)
+		case "avg300":
+			line.Avg300, err = strconv.ParseFloat(kv[1], 64)
+		case "total":
+			line.Total, err = strconv.ParseUint(kv[1], 10, 64)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return line, nil
+}
+
+// Pressure returns the pressure stall information of the cgroup, read from
+// cpu.pressure, memory.pressure and io.pressure of the unified hierarchy
+func (c ContainerCgroup) Pressure() (*metrics.ContainerPressureStats, error) {
+	if !c.isUnified() {
+		return nil, ErrPressureNotAvailable
+	}
+
+	var ret metrics.ContainerPressureStats
+	var err error
+
+	if ret.CPU, err = ParsePressureFile(c.cgroupFilePath(unifiedHierarchy, "cpu.pressure")); err != nil {
+		return nil, err
+	}
+	if ret.Memory, err = ParsePressureFile(c.cgroupFilePath(unifiedHierarchy, "memory.pressure")); err != nil {
+		return nil, err
+	}
+	if ret.IO, err = ParsePressureFile(c.cgroupFilePath(unifiedHierarchy, "io.pressure")); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+// GetContainersPressure returns the pressure stall information of all running
+// containers, by container ID
+func GetContainersPressure() (map[string]*metrics.ContainerPressureStats, error) {
+	cgroups, err := scrapeAllCgroups()
+	if err != nil {
+		return nil, err
+	}
+
+	pressures := make(map[string]*metrics.ContainerPressureStats, len(cgroups))
+	for containerID, cg := range cgroups {
+		pressure, err := cg.Pressure()
+		if err == ErrPressureNotAvailable {
+			return nil, err
+		} else if err != nil {
+			log.Debugf("Could not get pressure stall information of container %s: %s", containerID[:12], err)
+			continue
+		}
+		pressures[containerID] = pressure
+	}
+
+	return pressures, nil
+}
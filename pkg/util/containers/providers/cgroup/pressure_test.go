@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package cgroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
+)
+
+func TestParsePressureFile(t *testing.T) {
+	tempFolder, err := newTempFolder("pressure")
+	assert.Nil(t, err)
+	defer tempFolder.removeAll()
+
+	// kernels older than 5.13 only report the some line for cpu
+	tempFolder.add("cpu", "some avg10=0.12 avg60=0.05 avg300=0.01 total=1043752\n")
+	stats, err := ParsePressureFile(tempFolder.RootPath + "/cpu")
+	assert.NoError(t, err)
+	assert.Equal(t, &metrics.PressureStats{
+		Some: &metrics.PressureLine{Avg10: 0.12, Avg60: 0.05, Avg300: 0.01, Total: 1043752},
+	}, stats)
+
+	tempFolder.add("invalid", "some avg10=0.12 avg60\n")
+	_, err = ParsePressureFile(tempFolder.RootPath + "/invalid")
+	assert.Error(t, err)
+
+	stats, err = ParsePressureFile(tempFolder.RootPath + "/missing")
+	assert.NoError(t, err)
+	assert.Nil(t, stats)
+}
+
+func TestContainerPressure(t *testing.T) {
+	cgroup := newUnifiedContainerCgroup("/system.slice/docker-" + testUnifiedContainerID + ".scope")
+
+	pressure, err := cgroup.Pressure()
+	require.NoError(t, err)
+	assert.Equal(t, &metrics.PressureStats{
+		Some: &metrics.PressureLine{Avg10: 1.5, Avg60: 0.75, Avg300: 0.2, Total: 3527491},
+		Full: &metrics.PressureLine{},
+	}, pressure.CPU)
+	assert.Equal(t, 0.12, pressure.Memory.Some.Avg10)
+	assert.Equal(t, uint64(16450012), pressure.IO.Full.Total)
+
+	_, err = newDummyContainerCgroup("/sys/fs/cgroup", "memory").Pressure()
+	assert.Equal(t, ErrPressureNotAvailable, err)
+}
@@ -69,6 +69,10 @@ func (mp *provider) GetContainerMetrics(containerID string) (*metrics.ContainerM
 		return nil, err
 	}
 
+	if cg.isUnified() {
+		return cg.unifiedMetrics()
+	}
+
 	var metrics metrics.ContainerMetrics
 	metrics.Memory, err = cg.Mem()
 	if err != nil {
@@ -113,6 +117,10 @@ func (mp *provider) GetContainerLimits(containerID string) (*metrics.ContainerLi
 		return nil, err
 	}
 
+	if cg.isUnified() {
+		return cg.unifiedLimits()
+	}
+
 	var limits metrics.ContainerLimits
 	limits.CPULimit, err = cg.CPULimit()
 	if err != nil {
@@ -498,6 +498,7 @@ def build_network_ebpf_files(ctx, build_dir):
         "offset-guess",
         "http",
         "dns",
+        "protocols",
     ]
 
     network_flags = get_ebpf_build_flags()